@@ -1,11 +1,14 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 
 	"cscan/api/internal/config"
 	"cscan/api/internal/handler"
+	"cscan/api/internal/logic"
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/scheduler"
 
@@ -25,6 +28,13 @@ func main() {
 	// 创建服务上下文
 	svcCtx := svc.NewServiceContext(c)
 
+	// 旧版本用户（未设置角色，原先均为管理员）显式升级为超级管理员
+	if n, err := svcCtx.UserModel.MigrateLegacyRoles(context.Background()); err != nil {
+		fmt.Printf("Migrate legacy user roles failed: %v\n", err)
+	} else if n > 0 {
+		fmt.Printf("Migrated %d legacy admin users to superadmin\n", n)
+	}
+
 	// 启动时自动导入自定义POC和指纹（包括主动指纹路径）
 	go svcCtx.ImportCustomPocAndFingerprints()
 
@@ -40,8 +50,22 @@ func main() {
 		Password: c.Redis.Pass,
 	})
 	schedulerSvc := scheduler.NewSchedulerService(rdb, svcCtx.SyncMethods)
+	schedulerSvc.GetScheduler().SetDeadLetterHandler(svcCtx.HandleTaskDeadLetter)
+	// 定时任务在工作空间或组织的维护窗口关闭时推迟执行
+	schedulerSvc.GetCronManager().SetWindowLoader(func(ctx context.Context, workspaceId, orgId string) (scheduler.Windows, error) {
+		return common.LoadWindows(ctx, svcCtx, workspaceId, orgId)
+	})
 	go schedulerSvc.Start()
 
+	// 启动Worker离线检测
+	svcCtx.StartWorkerWatcher(context.Background())
+
+	// 启动维护窗口检查，窗口关闭时暂停执行中的任务，重新打开时自动继续
+	logic.StartMaintenanceWindowWatcher(context.Background(), svcCtx)
+
+	// 启动Webhook投递和失败重试
+	svcCtx.Webhook.Start(context.Background())
+
 	fmt.Printf("Starting API server at %s:%d...\n", c.Host, c.Port)
 	server.Start()
 }
@@ -1,6 +1,8 @@
 package config
 
 import (
+	"cscan/pkg/blob"
+
 	"github.com/zeromicro/go-zero/core/stores/redis"
 	"github.com/zeromicro/go-zero/rest"
 	"github.com/zeromicro/go-zero/zrpc"
@@ -19,4 +21,7 @@ type Config struct {
 	Redis   redis.RedisConf
 	TaskRpc zrpc.RpcClientConf
 	Console ConsoleConfig `json:",optional"`
+	GeoIP   GeoIPConfig   `json:",optional"`
+	// 截图存储：local（默认，data/blob）、s3（S3/MinIO）、gridfs
+	Screenshot blob.Config `json:",optional"`
 }
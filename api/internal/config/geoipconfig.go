@@ -0,0 +1,15 @@
+package config
+
+// GeoIPConfig 离线IP库配置
+type GeoIPConfig struct {
+	// 数据库文件目录，存放 ip2region.xdb、GeoLite2-City.mmdb、GeoLite2-ASN.mmdb
+	Dir string `json:",default=data/geoip"`
+}
+
+// GetDir 获取数据库目录
+func (c *GeoIPConfig) GetDir() string {
+	if c.Dir == "" {
+		return "data/geoip"
+	}
+	return c.Dir
+}
@@ -20,6 +20,7 @@ type DirScanResultListReq struct {
 	Authority  string `json:"authority"`
 	Path       string `json:"path"`
 	StatusCode int    `json:"statusCode"`
+	Source     string `json:"source"` // urlfinder/crawler
 	Page       int    `json:"page"`
 	PageSize   int    `json:"pageSize"`
 }
@@ -70,6 +71,14 @@ func DirScanResultListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		if req.StatusCode > 0 {
 			filter["status_code"] = req.StatusCode
 		}
+		switch req.Source {
+		case "":
+		case "urlfinder":
+			// 旧数据没有来源字段，均为目录扫描结果
+			filter["source"] = bson.M{"$in": bson.A{"urlfinder", "", nil}}
+		default:
+			filter["source"] = req.Source
+		}
 
 		// 先统计总数（不带分页）
 		total, _ := resultModel.CountByFilter(ctx, filter)
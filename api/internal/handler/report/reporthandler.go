@@ -36,19 +36,72 @@ func ReportExportHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 
 		workspaceId := middleware.GetWorkspaceId(r.Context())
 		l := logic.NewReportExportLogic(r.Context(), svcCtx)
-		data, filename, err := l.ReportExport(&req, workspaceId)
+		data, filename, contentType, err := l.ReportExport(&req, workspaceId)
 		if err != nil {
 			httpResult(w, &types.BaseResp{Code: 500, Msg: err.Error()})
 			return
 		}
 
 		// 设置响应头
-		w.Header().Set("Content-Type", "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet")
+		w.Header().Set("Content-Type", contentType)
 		w.Header().Set("Content-Disposition", "attachment; filename*=UTF-8''"+url.PathEscape(filename))
 		w.Write(data)
 	}
 }
 
+func ReportTemplateListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewReportTemplateLogic(r.Context(), svcCtx)
+		resp, _ := l.TemplateList(workspaceId)
+		httpResult(w, resp)
+	}
+}
+
+func ReportTemplateSaveHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ReportTemplateSaveReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpResult(w, &types.BaseResp{Code: 400, Msg: "参数错误"})
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewReportTemplateLogic(r.Context(), svcCtx)
+		resp, _ := l.TemplateSave(&req, workspaceId)
+		httpResult(w, resp)
+	}
+}
+
+func ReportTemplateDeleteHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ReportTemplateIdReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpResult(w, &types.BaseResp{Code: 400, Msg: "参数错误"})
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewReportTemplateLogic(r.Context(), svcCtx)
+		resp, _ := l.TemplateDelete(&req, workspaceId)
+		httpResult(w, resp)
+	}
+}
+
+func ReportTemplateDefaultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ReportTemplateDefaultReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpResult(w, &types.BaseResp{Code: 400, Msg: "参数错误"})
+			return
+		}
+
+		l := logic.NewReportTemplateLogic(r.Context(), svcCtx)
+		resp, _ := l.TemplateDefault(&req)
+		httpResult(w, resp)
+	}
+}
+
 func httpResult(w http.ResponseWriter, resp interface{}) {
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(resp)
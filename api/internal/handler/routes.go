@@ -5,12 +5,18 @@ import (
 
 	"cscan/api/internal/handler/ai"
 	"cscan/api/internal/handler/asset"
+	"cscan/api/internal/handler/brute"
 	"cscan/api/internal/handler/dirscan"
+	"cscan/api/internal/handler/dnsrecord"
 	"cscan/api/internal/handler/fingerprint"
+	"cscan/api/internal/handler/geoip"
+	"cscan/api/internal/handler/interactsh"
+	"cscan/api/internal/handler/notify"
 	"cscan/api/internal/handler/onlineapi"
 	"cscan/api/internal/handler/organization"
 	"cscan/api/internal/handler/poc"
 	"cscan/api/internal/handler/report"
+	"cscan/api/internal/handler/screenshot"
 	"cscan/api/internal/handler/subfinder"
 	"cscan/api/internal/handler/task"
 	"cscan/api/internal/handler/user"
@@ -56,8 +62,10 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/result", Handler: worker.WorkerTaskResultHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/vul", Handler: worker.WorkerVulResultHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/dirscan", Handler: worker.WorkerDirScanResultHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/task/dnsrecord", Handler: worker.WorkerDNSRecordResultHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/subtask/done", Handler: worker.WorkerSubTaskDoneHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/task/control", Handler: worker.WorkerTaskControlHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/task/handoff", Handler: worker.WorkerTaskHandoffHandler(svcCtx)},
 		// 心跳
 		{Method: http.MethodPost, Path: "/api/v1/worker/heartbeat", Handler: worker.WorkerHeartbeatHandler(svcCtx)},
 		// Worker离线通知
@@ -70,6 +78,13 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/worker/config/activefingerprints", Handler: worker.WorkerConfigActiveFingerprintsHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/config/poc", Handler: worker.WorkerConfigPocHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/config/dirscandict", Handler: worker.WorkerConfigDirScanDictHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/brutedict", Handler: worker.WorkerConfigBruteDictHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/scope", Handler: worker.WorkerConfigScopeHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/ratelimit", Handler: worker.WorkerConfigRateLimitHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/origin", Handler: worker.WorkerConfigOriginHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/worker/config/interactsh", Handler: worker.WorkerConfigInteractshHandler(svcCtx)},
+		// 分布式限速令牌
+		{Method: http.MethodPost, Path: "/api/v1/worker/ratelimit/acquire", Handler: worker.WorkerRateLimitAcquireHandler(svcCtx)},
 	}
 
 	// 为Worker路由包装认证中间件
@@ -83,7 +98,8 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 	server.AddRoutes(workerRoutes)
 
 	// 需要认证的路由
-	authMiddleware := middleware.NewAuthMiddleware(svcCtx.Config.Auth.AccessSecret)
+	authMiddleware := middleware.NewAuthMiddleware(svcCtx.Config.Auth.AccessSecret, svcCtx.UserModel.FindById).
+		WithApiTokens(svcCtx.ApiTokenModel, svcCtx.AuditLogModel)
 	authRoutes := []rest.Route{
 		// 用户管理
 		{Method: http.MethodPost, Path: "/api/v1/user/list", Handler: user.UserListHandler(svcCtx)},
@@ -94,6 +110,11 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/user/scanConfig/save", Handler: user.SaveScanConfigHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/user/scanConfig/get", Handler: user.GetScanConfigHandler(svcCtx)},
 
+		// API Token
+		{Method: http.MethodPost, Path: "/api/v1/token/list", Handler: user.ApiTokenListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/token/create", Handler: user.ApiTokenCreateHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/token/revoke", Handler: user.ApiTokenRevokeHandler(svcCtx)},
+
 		// Worker日志（需要认证）
 		{Method: http.MethodGet, Path: "/api/v1/worker/logs/stream", Handler: worker.WorkerLogsHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/worker/logs/history", Handler: worker.WorkerLogsHistoryHandler(svcCtx)},
@@ -117,6 +138,7 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/asset/batchDelete", Handler: asset.AssetBatchDeleteHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/asset/clear", Handler: asset.AssetClearHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/asset/history", Handler: asset.AssetHistoryHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/asset/diff", Handler: asset.AssetDiffHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/asset/import", Handler: asset.AssetImportHandler(svcCtx)},
 
 		// 站点管理
@@ -154,6 +176,9 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/task/profile/delete", Handler: task.TaskProfileDeleteHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/task/logs", Handler: task.GetTaskLogsHandler(svcCtx)},
 		{Method: http.MethodGet, Path: "/api/v1/task/logs/stream", Handler: task.TaskLogsStreamHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/task/deadletter/list", Handler: task.DeadLetterListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/task/deadletter/replay", Handler: task.DeadLetterReplayHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/task/deadletter/delete", Handler: task.DeadLetterDeleteHandler(svcCtx)},
 
 		// 漏洞管理
 		{Method: http.MethodPost, Path: "/api/v1/vul/list", Handler: vul.VulListHandler(svcCtx)},
@@ -162,6 +187,12 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/vul/delete", Handler: vul.VulDeleteHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/vul/batchDelete", Handler: vul.VulBatchDeleteHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/vul/clear", Handler: vul.VulClearHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/vul/status", Handler: vul.VulStatusHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/vul/assign", Handler: vul.VulAssignHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/vul/comment", Handler: vul.VulCommentHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/vul/suppress/list", Handler: vul.VulSuppressListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/vul/suppress/save", Handler: vul.VulSuppressSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/vul/suppress/delete", Handler: vul.VulSuppressDeleteHandler(svcCtx)},
 
 		// Worker管理
 		{Method: http.MethodPost, Path: "/api/v1/worker/list", Handler: worker.WorkerListHandler(svcCtx)},
@@ -239,12 +270,30 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		// 报告管理
 		{Method: http.MethodPost, Path: "/api/v1/report/detail", Handler: report.ReportDetailHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/report/export", Handler: report.ReportExportHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/report/template/list", Handler: report.ReportTemplateListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/report/template/save", Handler: report.ReportTemplateSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/report/template/delete", Handler: report.ReportTemplateDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/report/template/default", Handler: report.ReportTemplateDefaultHandler(svcCtx)},
 
 		// Subfinder数据源配置
 		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/list", Handler: subfinder.SubfinderProviderListHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/save", Handler: subfinder.SubfinderProviderSaveHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/subfinder/provider/info", Handler: subfinder.SubfinderProviderInfoHandler(svcCtx)},
 
+		// 消息通知
+		{Method: http.MethodPost, Path: "/api/v1/notify/channel/list", Handler: notify.NotifyChannelListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/notify/channel/save", Handler: notify.NotifyChannelSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/notify/channel/delete", Handler: notify.NotifyChannelDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/notify/channel/test", Handler: notify.NotifyChannelTestHandler(svcCtx)},
+
+		// Webhook 事件订阅
+		{Method: http.MethodPost, Path: "/api/v1/webhook/list", Handler: notify.WebhookListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/webhook/save", Handler: notify.WebhookSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/webhook/delete", Handler: notify.WebhookDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/webhook/test", Handler: notify.WebhookTestHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/webhook/delivery/list", Handler: notify.WebhookDeliveryListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/webhook/delivery/replay", Handler: notify.WebhookDeliveryReplayHandler(svcCtx)},
+
 		// AI辅助
 		{Method: http.MethodPost, Path: "/api/v1/ai/generatePoc", Handler: ai.GeneratePocHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/ai/config/get", Handler: ai.AIConfigGetHandler(svcCtx)},
@@ -257,12 +306,41 @@ func RegisterHandlers(server *rest.Server, svcCtx *svc.ServiceContext) {
 		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/clear", Handler: dirscan.DirScanDictClearHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/dirscan/dict/enabled", Handler: dirscan.DirScanDictEnabledListHandler(svcCtx)},
 
+		// 弱口令爆破字典
+		{Method: http.MethodPost, Path: "/api/v1/brute/dict/list", Handler: brute.BruteDictListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/brute/dict/save", Handler: brute.BruteDictSaveHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/brute/dict/delete", Handler: brute.BruteDictDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/brute/dict/enabled", Handler: brute.BruteDictEnabledListHandler(svcCtx)},
+
 		// 目录扫描结果
 		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/list", Handler: dirscan.DirScanResultListHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/stat", Handler: dirscan.DirScanResultStatHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/delete", Handler: dirscan.DirScanResultDeleteHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/batchDelete", Handler: dirscan.DirScanResultBatchDeleteHandler(svcCtx)},
 		{Method: http.MethodPost, Path: "/api/v1/dirscan/result/clear", Handler: dirscan.DirScanResultClearHandler(svcCtx)},
+
+		// DNS记录
+		{Method: http.MethodPost, Path: "/api/v1/dnsrecord/list", Handler: dnsrecord.DNSRecordListHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/dnsrecord/stat", Handler: dnsrecord.DNSRecordStatHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/dnsrecord/delete", Handler: dnsrecord.DNSRecordDeleteHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/dnsrecord/clear", Handler: dnsrecord.DNSRecordClearHandler(svcCtx)},
+
+		// 离线IP库
+		{Method: http.MethodPost, Path: "/api/v1/geoip/info", Handler: geoip.GeoIPInfoHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/geoip/upload", Handler: geoip.GeoIPUploadHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/geoip/update", Handler: geoip.GeoIPUpdateHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/geoip/lookup", Handler: geoip.GeoIPLookupHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/geoip/enrich", Handler: geoip.GeoIPEnrichHandler(svcCtx)},
+
+		// OOB回连
+		{Method: http.MethodPost, Path: "/api/v1/interactsh/config/get", Handler: interactsh.InteractshConfigGetHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/interactsh/config/save", Handler: interactsh.InteractshConfigSaveHandler(svcCtx)},
+
+		// 截图
+		{Method: http.MethodGet, Path: "/api/v1/screenshot/get", Handler: screenshot.ScreenshotGetHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/screenshot/gallery", Handler: screenshot.ScreenshotGalleryHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/screenshot/similar", Handler: screenshot.ScreenshotSimilarHandler(svcCtx)},
+		{Method: http.MethodPost, Path: "/api/v1/screenshot/migrate", Handler: screenshot.ScreenshotMigrateHandler(svcCtx)},
 	}
 
 	// 为每个路由包装认证中间件
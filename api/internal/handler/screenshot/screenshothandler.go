@@ -0,0 +1,98 @@
+package screenshot
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"cscan/api/internal/logic"
+	"cscan/api/internal/middleware"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/pkg/blob"
+	"cscan/pkg/response"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// ScreenshotGetHandler 读取截图图片
+// GET /api/v1/screenshot/get?key=<key>
+func ScreenshotGetHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ScreenshotGetReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		l := logic.NewScreenshotLogic(r.Context(), svcCtx)
+		data, contentType, err := l.Get(req.Key)
+		if err != nil {
+			if !errors.Is(err, blob.ErrNotFound) {
+				logx.Errorf("[Screenshot] get %s failed: %v", req.Key, err)
+			}
+			http.NotFound(w, r)
+			return
+		}
+		// key 由内容哈希生成，内容不会变化
+		w.Header().Set("Content-Type", contentType)
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("Cache-Control", "private, max-age=31536000, immutable")
+		w.Write(data)
+	}
+}
+
+// ScreenshotGalleryHandler 截图相似度聚类
+func ScreenshotGalleryHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ScreenshotGalleryReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewScreenshotLogic(r.Context(), svcCtx)
+		resp, err := l.Gallery(&req, workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// ScreenshotSimilarHandler 查询相似截图
+func ScreenshotSimilarHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.ScreenshotSimilarReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewScreenshotLogic(r.Context(), svcCtx)
+		resp, err := l.Similar(&req, workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// ScreenshotMigrateHandler 将已有资产的内联截图迁移到对象存储
+func ScreenshotMigrateHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewScreenshotLogic(r.Context(), svcCtx)
+		resp, err := l.Migrate(workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
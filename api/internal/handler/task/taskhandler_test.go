@@ -0,0 +1,52 @@
+package task
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"cscan/api/internal/middleware"
+	"cscan/api/internal/svc"
+)
+
+// TestMainTaskCrossWorkspace 测试受限用户不能通过请求体中的 workspaceId 操作其他工作空间的任务
+func TestMainTaskCrossWorkspace(t *testing.T) {
+	svcCtx := &svc.ServiceContext{}
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		body    string
+		header  string
+		want    int
+	}{
+		{"create", MainTaskCreateHandler(svcCtx), `{"name":"t","target":"10.0.0.1","workspaceId":"ws-b"}`, "ws-a", 403},
+		{"start", MainTaskStartHandler(svcCtx), `{"id":"1","workspaceId":"ws-b"}`, "ws-a", 403},
+		{"pause", MainTaskPauseHandler(svcCtx), `{"id":"1","workspaceId":"ws-b"}`, "ws-a", 403},
+		{"resume", MainTaskResumeHandler(svcCtx), `{"id":"1","workspaceId":"ws-b"}`, "ws-a", 403},
+		{"stop", MainTaskStopHandler(svcCtx), `{"id":"1","workspaceId":"ws-b"}`, "ws-a", 403},
+		{"all is not a workspace", MainTaskStopHandler(svcCtx), `{"id":"1","workspaceId":"all"}`, "ws-a", 403},
+		{"empty workspace", MainTaskStopHandler(svcCtx), `{"id":"1"}`, "", 400},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/task/main", strings.NewReader(tt.body))
+		req.Header.Set("Content-Type", "application/json")
+		ctx := context.WithValue(req.Context(), middleware.AllowedWorkspacesKey, []string{"ws-a"})
+		ctx = context.WithValue(ctx, middleware.WorkspaceIdKey, tt.header)
+		rec := httptest.NewRecorder()
+		tt.handler(rec, req.WithContext(ctx))
+
+		var resp struct {
+			Code int `json:"code"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Errorf("%s: invalid response %q: %v", tt.name, rec.Body.String(), err)
+			continue
+		}
+		if resp.Code != tt.want {
+			t.Errorf("%s: code = %d, want %d", tt.name, resp.Code, tt.want)
+		}
+	}
+}
@@ -122,9 +122,133 @@ func VulClearHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 // VulStatHandler 漏洞统计
 func VulStatHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulStatReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
 		workspaceId := middleware.GetWorkspaceId(r.Context())
 		l := logic.NewVulStatLogic(r.Context(), svcCtx)
-		resp, err := l.VulStat(workspaceId)
+		resp, err := l.VulStat(&req, workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulStatusHandler 批量变更漏洞处置状态
+func VulStatusHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulStatusUpdateReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulLogic(r.Context(), svcCtx)
+		resp, err := l.VulStatusUpdate(&req, workspaceId, middleware.GetUsername(r.Context()))
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulAssignHandler 批量指派漏洞处理人
+func VulAssignHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulAssignReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulLogic(r.Context(), svcCtx)
+		resp, err := l.VulAssign(&req, workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulCommentHandler 添加漏洞评论
+func VulCommentHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulCommentReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulLogic(r.Context(), svcCtx)
+		resp, err := l.VulComment(&req, workspaceId, middleware.GetUsername(r.Context()))
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulSuppressListHandler 误报抑制规则列表
+func VulSuppressListHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulSuppressLogic(r.Context(), svcCtx)
+		resp, err := l.RuleList(workspaceId)
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulSuppressSaveHandler 保存误报抑制规则
+func VulSuppressSaveHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulSuppressRuleSaveReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulSuppressLogic(r.Context(), svcCtx)
+		resp, err := l.RuleSave(&req, workspaceId, middleware.GetUsername(r.Context()))
+		if err != nil {
+			response.Error(w, err)
+			return
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// VulSuppressDeleteHandler 删除误报抑制规则
+func VulSuppressDeleteHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req types.VulSuppressRuleDeleteReq
+		if err := httpx.Parse(r, &req); err != nil {
+			response.ParamError(w, err.Error())
+			return
+		}
+		if req.Id == "" {
+			response.Error(w, xerr.NewParamError("ID不能为空"))
+			return
+		}
+
+		workspaceId := middleware.GetWorkspaceId(r.Context())
+		l := logic.NewVulSuppressLogic(r.Context(), svcCtx)
+		resp, err := l.RuleDelete(&req, workspaceId)
 		if err != nil {
 			response.Error(w, err)
 			return
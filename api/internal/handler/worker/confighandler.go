@@ -2,16 +2,24 @@ package worker
 
 import (
 	"encoding/json"
+	"net"
 	"net/http"
 	"strings"
 
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/model"
+	"cscan/pkg/ratelimit"
 	"cscan/pkg/response"
 	"cscan/rpc/task/pb"
+	"cscan/scanner"
+	"cscan/scheduler"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/rest/httpx"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
 )
 
 // ==================== Templates Config Types ====================
@@ -593,3 +601,330 @@ func parseDictPaths(content string) []string {
 	}
 	return paths
 }
+
+// ==================== Brute Dict Config Types ====================
+
+// WorkerBruteDictReq 爆破字典获取请求
+type WorkerBruteDictReq struct {
+	WorkspaceId string   `json:"workspaceId"` // 字典所属工作空间
+	DictIds     []string `json:"dictIds"`     // 字典ID列表
+}
+
+// WorkerBruteDictItem 爆破字典项
+type WorkerBruteDictItem struct {
+	Id       string   `json:"id"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`     // username/password
+	Services []string `json:"services"` // 适用的服务，为空表示全部
+	Words    []string `json:"words"`    // 解析后的条目
+}
+
+// WorkerBruteDictResp 爆破字典获取响应
+type WorkerBruteDictResp struct {
+	Code  int                   `json:"code"`
+	Msg   string                `json:"msg"`
+	Dicts []WorkerBruteDictItem `json:"dicts"`
+	Count int                   `json:"count"`
+}
+
+// ==================== Brute Dict Handler ====================
+
+// WorkerConfigBruteDictHandler 爆破字典配置获取接口
+// POST /api/v1/worker/config/brutedict
+func WorkerConfigBruteDictHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerBruteDictReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerBruteDictResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+
+		if len(req.DictIds) == 0 {
+			httpx.OkJson(w, &WorkerBruteDictResp{Code: 400, Msg: "dictIds不能为空"})
+			return
+		}
+
+		// 只返回任务所属工作空间的字典
+		dicts, err := model.NewBruteDictModel(svcCtx.MongoDB).FindByIds(r.Context(), req.WorkspaceId, req.DictIds)
+		if err != nil {
+			logx.Errorf("[WorkerConfigBruteDict] FindByIds error: %v", err)
+			httpx.OkJson(w, &WorkerBruteDictResp{Code: 500, Msg: "获取字典失败"})
+			return
+		}
+
+		items := make([]WorkerBruteDictItem, 0, len(dicts))
+		for _, d := range dicts {
+			items = append(items, WorkerBruteDictItem{
+				Id:       d.Id.Hex(),
+				Name:     d.Name,
+				Type:     d.Type,
+				Services: d.Services,
+				Words:    model.ParseBruteWords(d.Content),
+			})
+		}
+
+		httpx.OkJson(w, &WorkerBruteDictResp{
+			Code:  0,
+			Msg:   "success",
+			Dicts: items,
+			Count: len(items),
+		})
+	}
+}
+
+// ==================== Scope Config Types ====================
+
+// WorkerScopeReq 扫描范围获取请求
+type WorkerScopeReq struct {
+	WorkspaceId string `json:"workspaceId"`
+}
+
+// WorkerScopeResp 扫描范围获取响应，Scope 为空表示不限制
+type WorkerScopeResp struct {
+	Code  int                   `json:"code"`
+	Msg   string                `json:"msg"`
+	Scope *scheduler.ScopeRules `json:"scope,omitempty"`
+}
+
+// ==================== Scope Handler ====================
+
+// WorkerConfigScopeHandler 工作空间扫描范围获取接口
+// POST /api/v1/worker/config/scope
+func WorkerConfigScopeHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerScopeReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerScopeResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+
+		// 默认工作空间没有工作空间记录，不限制
+		if !primitive.IsValidObjectID(req.WorkspaceId) {
+			httpx.OkJson(w, &WorkerScopeResp{Code: 0, Msg: "success"})
+			return
+		}
+		ws, err := svcCtx.WorkspaceModel.FindById(r.Context(), req.WorkspaceId)
+		if err == mongo.ErrNoDocuments {
+			httpx.OkJson(w, &WorkerScopeResp{Code: 0, Msg: "success"})
+			return
+		}
+		if err != nil {
+			logx.Errorf("[WorkerConfigScope] find workspace %s error: %v", req.WorkspaceId, err)
+			httpx.OkJson(w, &WorkerScopeResp{Code: 500, Msg: "查询工作空间失败"})
+			return
+		}
+
+		resp := &WorkerScopeResp{Code: 0, Msg: "success"}
+		if ws.Scope != nil {
+			resp.Scope = &scheduler.ScopeRules{
+				AllowedCidrs:   ws.Scope.AllowedCidrs,
+				AllowedDomains: ws.Scope.AllowedDomains,
+				ExcludedHosts:  ws.Scope.ExcludedHosts,
+				ExcludedPorts:  ws.Scope.ExcludedPorts,
+			}
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// ==================== Rate Limit Types ====================
+
+// WorkerRateLimitConfigResp 限速预算获取响应，Budget 为空表示不限速
+type WorkerRateLimitConfigResp struct {
+	Code   int               `json:"code"`
+	Msg    string            `json:"msg"`
+	Budget *ratelimit.Budget `json:"budget,omitempty"`
+}
+
+// WorkerRateLimitAcquireReq 令牌获取请求
+type WorkerRateLimitAcquireReq struct {
+	Buckets []ratelimit.Bucket `json:"buckets"`
+	Tokens  int                `json:"tokens"`
+}
+
+// WorkerRateLimitAcquireResp 令牌获取响应，Granted 为 0 时需等待 WaitMs 后重试
+type WorkerRateLimitAcquireResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Granted int    `json:"granted"`
+	WaitMs  int64  `json:"waitMs"`
+}
+
+// ==================== Rate Limit Handler ====================
+
+// WorkerConfigRateLimitHandler 工作空间限速预算获取接口
+// POST /api/v1/worker/config/ratelimit
+func WorkerConfigRateLimitHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerScopeReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerRateLimitConfigResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+
+		if !primitive.IsValidObjectID(req.WorkspaceId) {
+			httpx.OkJson(w, &WorkerRateLimitConfigResp{Code: 0, Msg: "success"})
+			return
+		}
+		ws, err := svcCtx.WorkspaceModel.FindById(r.Context(), req.WorkspaceId)
+		if err == mongo.ErrNoDocuments {
+			httpx.OkJson(w, &WorkerRateLimitConfigResp{Code: 0, Msg: "success"})
+			return
+		}
+		if err != nil {
+			logx.Errorf("[WorkerConfigRateLimit] find workspace %s error: %v", req.WorkspaceId, err)
+			httpx.OkJson(w, &WorkerRateLimitConfigResp{Code: 500, Msg: "查询工作空间失败"})
+			return
+		}
+
+		resp := &WorkerRateLimitConfigResp{Code: 0, Msg: "success"}
+		if budget := common.RateLimitBudget(ws.RateLimit); budget.Enabled() {
+			resp.Budget = budget
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// WorkerRateLimitAcquireHandler 从分布式令牌桶获取令牌
+// POST /api/v1/worker/ratelimit/acquire
+func WorkerRateLimitAcquireHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerRateLimitAcquireReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerRateLimitAcquireResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if len(req.Buckets) > 8 {
+			httpx.OkJson(w, &WorkerRateLimitAcquireResp{Code: 400, Msg: "令牌桶数量过多"})
+			return
+		}
+		for _, b := range req.Buckets {
+			if !ratelimit.ValidKey(b.Key) || b.Rate <= 0 {
+				httpx.OkJson(w, &WorkerRateLimitAcquireResp{Code: 400, Msg: "无效的令牌桶: " + b.Key})
+				return
+			}
+		}
+
+		granted, wait, err := ratelimit.NewLimiter(svcCtx.RedisClient).Take(r.Context(), req.Buckets, req.Tokens)
+		if err != nil {
+			logx.Errorf("[WorkerRateLimit] take tokens error: %v", err)
+			httpx.OkJson(w, &WorkerRateLimitAcquireResp{Code: 500, Msg: "获取令牌失败"})
+			return
+		}
+		httpx.OkJson(w, &WorkerRateLimitAcquireResp{Code: 0, Msg: "success", Granted: granted, WaitMs: wait.Milliseconds()})
+	}
+}
+
+// ==================== CDN Origin Types ====================
+
+// WorkerOriginReq 候选源站查询请求
+type WorkerOriginReq struct {
+	WorkspaceId string   `json:"workspaceId"`
+	Domains     []string `json:"domains"`
+}
+
+// WorkerOriginResp 候选源站查询响应，key 为域名
+type WorkerOriginResp struct {
+	Code       int                                  `json:"code"`
+	Msg        string                               `json:"msg"`
+	Candidates map[string][]scanner.OriginCandidate `json:"candidates"`
+}
+
+// ==================== CDN Origin Handler ====================
+
+// WorkerConfigOriginHandler 从历史DNS记录和证书中查找CDN域名的候选源站IP
+func WorkerConfigOriginHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerOriginReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerOriginResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		resp := &WorkerOriginResp{Code: 0, Msg: "success", Candidates: make(map[string][]scanner.OriginCandidate)}
+		if len(req.Domains) == 0 {
+			httpx.OkJson(w, resp)
+			return
+		}
+
+		// 历史解析记录，当前解析已指向CDN，旧记录可能是源站
+		records, err := svcCtx.GetDNSRecordModel(req.WorkspaceId).Find(r.Context(), bson.M{
+			"domain": bson.M{"$in": req.Domains},
+			"type":   bson.M{"$in": []string{"A", "AAAA"}},
+		}, 0, 0)
+		if err != nil {
+			logx.Errorf("[WorkerConfigOrigin] find dns records error: %v", err)
+		}
+		for _, record := range records {
+			resp.Candidates[record.Domain] = append(resp.Candidates[record.Domain], scanner.OriginCandidate{IP: record.Value, Source: "dns-history"})
+		}
+
+		// 证书SAN包含该域名的IP资产
+		names := make(map[string][]string)
+		var nameList []string
+		for _, domain := range req.Domains {
+			names[domain] = append(names[domain], domain)
+			if i := strings.Index(domain, "."); i > 0 {
+				wildcard := "*" + domain[i:]
+				names[wildcard] = append(names[wildcard], domain)
+			}
+		}
+		for name := range names {
+			nameList = append(nameList, name)
+		}
+		assets, err := svcCtx.GetAssetModel(req.WorkspaceId).FindBrief(r.Context(), bson.M{"tls.chain.sans": bson.M{"$in": nameList}})
+		if err != nil {
+			logx.Errorf("[WorkerConfigOrigin] find cert assets error: %v", err)
+		}
+		for _, asset := range assets {
+			if net.ParseIP(asset.Host) == nil || asset.TLS == nil || len(asset.TLS.Chain) == 0 {
+				continue
+			}
+			for _, san := range asset.TLS.Chain[0].SANs {
+				for _, domain := range names[strings.ToLower(san)] {
+					resp.Candidates[domain] = append(resp.Candidates[domain], scanner.OriginCandidate{IP: asset.Host, Source: "cert"})
+				}
+			}
+		}
+		httpx.OkJson(w, resp)
+	}
+}
+
+// ==================== Interactsh Config Types ====================
+
+// WorkerInteractshResp OOB回连配置获取响应，Config 为空表示使用 nuclei 默认公共服务
+type WorkerInteractshResp struct {
+	Code   int                        `json:"code"`
+	Msg    string                     `json:"msg"`
+	Config *scanner.InteractshOptions `json:"config,omitempty"`
+}
+
+// ==================== Interactsh Handler ====================
+
+// WorkerConfigInteractshHandler OOB回连配置获取接口
+// POST /api/v1/worker/config/interactsh
+func WorkerConfigInteractshHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		doc, err := model.NewInteractshConfigModel(svcCtx.MongoDB).Get(r.Context())
+		if err == mongo.ErrNoDocuments {
+			httpx.OkJson(w, &WorkerInteractshResp{Code: 0, Msg: "success"})
+			return
+		}
+		if err != nil {
+			logx.Errorf("[WorkerConfigInteractsh] find config error: %v", err)
+			httpx.OkJson(w, &WorkerInteractshResp{Code: 500, Msg: "查询配置失败"})
+			return
+		}
+		httpx.OkJson(w, &WorkerInteractshResp{
+			Code: 0,
+			Msg:  "success",
+			Config: &scanner.InteractshOptions{
+				Mode:           doc.Mode,
+				ServerURL:      doc.ServerURL,
+				Token:          doc.Token,
+				PollInterval:   doc.PollInterval,
+				CooldownPeriod: doc.CooldownPeriod,
+				Eviction:       doc.Eviction,
+			},
+		})
+	}
+}
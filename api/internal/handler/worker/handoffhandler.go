@@ -0,0 +1,246 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/svc"
+	"cscan/model"
+	"cscan/scanner"
+	"cscan/scheduler"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"github.com/zeromicro/go-zero/rest/httpx"
+)
+
+// ==================== Handoff Types ====================
+
+// WorkerTaskHandoffReq 阶段交接请求
+type WorkerTaskHandoffReq struct {
+	TaskId          string           `json:"taskId"`
+	MainTaskId      string           `json:"mainTaskId"`
+	WorkspaceId     string           `json:"workspaceId"`
+	Config          string           `json:"config"`          // 当前子任务配置
+	PhaseGroup      int              `json:"phaseGroup"`      // 交接的阶段组下标
+	CompletedPhases []string         `json:"completedPhases"` // 已完成的阶段
+	Assets          []*scanner.Asset `json:"assets"`
+}
+
+// WorkerTaskHandoffResp 阶段交接响应
+type WorkerTaskHandoffResp struct {
+	Code    int      `json:"code"`
+	Msg     string   `json:"msg"`
+	TaskIds []string `json:"taskIds"` // 新建的子任务ID
+}
+
+// ==================== Handoff Handler ====================
+
+// WorkerTaskHandoffHandler 阶段交接接口
+// 按阶段分布式执行时，Worker 完成一组阶段后将资产按主机重新拆分，推送为需要下一组工具标签的子任务
+// POST /api/v1/worker/task/handoff
+func WorkerTaskHandoffHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerTaskHandoffReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if req.TaskId == "" || req.MainTaskId == "" || req.WorkspaceId == "" {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 400, Msg: "taskId、mainTaskId和workspaceId不能为空"})
+			return
+		}
+		if len(req.Assets) == 0 {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 400, Msg: "没有需要交接的资产"})
+			return
+		}
+
+		var taskConfig map[string]interface{}
+		if err := json.Unmarshal([]byte(req.Config), &taskConfig); err != nil {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 400, Msg: "任务配置解析失败"})
+			return
+		}
+		config, _ := scheduler.ParseTaskConfig(req.Config)
+		if config == nil || !config.PhaseSplit {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 400, Msg: "任务未启用按阶段分布式执行"})
+			return
+		}
+		groups := config.PhaseGroups()
+		if req.PhaseGroup < 1 || req.PhaseGroup >= len(groups) {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 400, Msg: "阶段组不存在"})
+			return
+		}
+
+		ctx := r.Context()
+		taskModel := svcCtx.GetMainTaskModel(req.WorkspaceId)
+		mainTask, err := taskModel.FindById(ctx, req.MainTaskId)
+		if err != nil {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 404, Msg: "主任务不存在"})
+			return
+		}
+		if mainTask.Status == model.TaskStatusStopped || mainTask.Status == model.TaskStatusRevoked {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 400, Msg: "主任务已停止"})
+			return
+		}
+
+		// 与启动任务时的目标拆分一致，batchSize 为每批主机数，0 表示不拆分
+		batchSize := 50
+		if bs, ok := taskConfig["batchSize"].(float64); ok {
+			if bs == 0 {
+				batchSize = 1000000
+			} else if bs > 0 {
+				batchSize = int(bs)
+			}
+		}
+		batches := splitAssetsByHost(req.Assets, batchSize)
+
+		// 当前子任务原本要完成剩余阶段，拆分为多个子任务后每个都要完成，需要增加子任务总数
+		remaining := 0
+		for _, g := range groups[req.PhaseGroup:] {
+			remaining += g.Modules()
+		}
+		extra := (len(batches) - 1) * remaining
+
+		// 子任务ID沿用 {主任务ID}-{序号} 格式，序号从目标批次数之后递增，Worker 据此将日志和控制信号关联到主任务
+		subTaskTotal := 1
+		if total, ok := taskConfig["subTaskTotal"].(float64); ok && total > 0 {
+			subTaskTotal = int(total)
+		}
+		seqKey := "cscan:task:handoff:seq:" + mainTask.TaskId
+		seq, err := svcCtx.RedisClient.IncrBy(ctx, seqKey, int64(len(batches))).Result()
+		if err != nil {
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 500, Msg: "生成子任务ID失败"})
+			return
+		}
+		svcCtx.RedisClient.Expire(ctx, seqKey, 24*time.Hour)
+		firstIndex := subTaskTotal + int(seq) - len(batches)
+
+		var workers []string
+		if ws, ok := taskConfig["workers"].([]interface{}); ok {
+			for _, v := range ws {
+				if s, ok := v.(string); ok {
+					workers = append(workers, s)
+				}
+			}
+		}
+
+		// 子任务信息沿用当前子任务，用于状态更新时定位主任务
+		taskInfoData, err := svcCtx.RedisClient.Get(ctx, "cscan:task:info:"+req.TaskId).Result()
+		if err != nil {
+			data, _ := json.Marshal(map[string]interface{}{
+				"workspaceId":  req.WorkspaceId,
+				"mainTaskId":   req.MainTaskId,
+				"subTaskCount": mainTask.SubTaskCount,
+			})
+			taskInfoData = string(data)
+		}
+
+		group := groups[req.PhaseGroup]
+		requires := config.Requires(req.PhaseGroup)
+		var schedTasks []*scheduler.TaskInfo
+		var taskIds, infoKeys []string
+		for i, batch := range batches {
+			assetsJson, _ := json.Marshal(batch)
+			resumeState, _ := json.Marshal(map[string]interface{}{
+				"completedPhases": req.CompletedPhases,
+				"assets":          string(assetsJson),
+			})
+			subConfig := make(map[string]interface{}, len(taskConfig))
+			for k, v := range taskConfig {
+				subConfig[k] = v
+			}
+			subConfig["target"] = batchHosts(batch)
+			subConfig["phaseGroup"] = req.PhaseGroup
+			subConfig["resumeState"] = string(resumeState)
+			subConfigBytes, _ := json.Marshal(subConfig)
+
+			subTaskId := mainTask.TaskId + "-" + strconv.Itoa(firstIndex+i)
+			schedTasks = append(schedTasks, &scheduler.TaskInfo{
+				TaskId:      subTaskId,
+				MainTaskId:  req.MainTaskId,
+				WorkspaceId: req.WorkspaceId,
+				TaskName:    mainTask.Name,
+				Config:      string(subConfigBytes),
+				Priority:    1,
+				Workers:     workers,
+				Requires:    requires,
+			})
+			taskIds = append(taskIds, subTaskId)
+			infoKeys = append(infoKeys, "cscan:task:info:"+subTaskId)
+			svcCtx.RedisClient.Set(ctx, "cscan:task:info:"+subTaskId, taskInfoData, 24*time.Hour)
+		}
+
+		// 先增加子任务总数再入队，避免子任务在总数更新前完成导致主任务提前结束；入队失败时回滚
+		if extra > 0 {
+			if err := taskModel.IncrSubTaskCount(ctx, req.MainTaskId, extra); err != nil {
+				logx.Errorf("[WorkerTaskHandoff] incr sub task count error: %v", err)
+				svcCtx.RedisClient.Del(ctx, infoKeys...)
+				httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 500, Msg: "更新子任务数失败"})
+				return
+			}
+		}
+		if err := svcCtx.Scheduler.PushTaskBatch(ctx, schedTasks); err != nil {
+			logx.Errorf("[WorkerTaskHandoff] push sub-tasks error: %v", err)
+			if extra > 0 {
+				if err := taskModel.IncrSubTaskCount(ctx, req.MainTaskId, -extra); err != nil {
+					logx.Errorf("[WorkerTaskHandoff] rollback sub task count error: %v", err)
+				}
+			}
+			svcCtx.RedisClient.Del(ctx, infoKeys...)
+			httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 500, Msg: "子任务入队失败"})
+			return
+		}
+
+		selector := "any"
+		if len(requires) > 0 {
+			selector = strings.Join(requires, ",")
+		}
+		common.WriteTaskLog(ctx, svcCtx, mainTask.TaskId, "INFO", "[Handoff] %s: %d assets → %d sub-tasks (%s), requires: %s",
+			req.TaskId, len(req.Assets), len(batches), strings.Join(group.Phases, ", "), selector)
+
+		httpx.OkJson(w, &WorkerTaskHandoffResp{Code: 0, Msg: fmt.Sprintf("已拆分为%d个子任务", len(batches)), TaskIds: taskIds})
+	}
+}
+
+// splitAssetsByHost 按主机拆分资产，同一主机的端口在同一批次
+func splitAssetsByHost(assets []*scanner.Asset, hostsPerBatch int) [][]*scanner.Asset {
+	var hosts []string
+	byHost := make(map[string][]*scanner.Asset)
+	for _, asset := range assets {
+		if _, ok := byHost[asset.Host]; !ok {
+			hosts = append(hosts, asset.Host)
+		}
+		byHost[asset.Host] = append(byHost[asset.Host], asset)
+	}
+
+	var batches [][]*scanner.Asset
+	for start := 0; start < len(hosts); start += hostsPerBatch {
+		end := start + hostsPerBatch
+		if end > len(hosts) {
+			end = len(hosts)
+		}
+		var batch []*scanner.Asset
+		for _, host := range hosts[start:end] {
+			batch = append(batch, byHost[host]...)
+		}
+		batches = append(batches, batch)
+	}
+	return batches
+}
+
+// batchHosts 批次内的主机列表，作为子任务目标
+func batchHosts(batch []*scanner.Asset) string {
+	seen := make(map[string]bool)
+	var hosts []string
+	for _, asset := range batch {
+		if !seen[asset.Host] {
+			seen[asset.Host] = true
+			hosts = append(hosts, asset.Host)
+		}
+	}
+	return strings.Join(hosts, "\n")
+}
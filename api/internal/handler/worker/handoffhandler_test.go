@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"testing"
+
+	"cscan/scanner"
+)
+
+// TestSplitAssetsByHost 测试阶段交接时按主机拆分资产，同一主机的端口不拆开
+func TestSplitAssetsByHost(t *testing.T) {
+	assets := []*scanner.Asset{
+		{Host: "10.0.0.1", Port: 80},
+		{Host: "10.0.0.2", Port: 22},
+		{Host: "10.0.0.1", Port: 443},
+		{Host: "10.0.0.3", Port: 8080},
+	}
+	batches := splitAssetsByHost(assets, 2)
+	if len(batches) != 2 {
+		t.Fatalf("len(batches) = %d, want 2", len(batches))
+	}
+	if len(batches[0]) != 3 || len(batches[1]) != 1 {
+		t.Errorf("batch sizes = %d,%d, want 3,1", len(batches[0]), len(batches[1]))
+	}
+	if got := batchHosts(batches[0]); got != "10.0.0.1\n10.0.0.2" {
+		t.Errorf("batchHosts() = %q", got)
+	}
+	if got := splitAssetsByHost(assets, 1000000); len(got) != 1 {
+		t.Errorf("no split: len(batches) = %d, want 1", len(got))
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"time"
 
 	"cscan/api/internal/svc"
+	"cscan/pkg/notify"
 	"cscan/pkg/response"
+	"cscan/pkg/webhook"
 	"cscan/rpc/task/pb"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -18,14 +20,16 @@ import (
 
 // WorkerHeartbeatReq 心跳请求
 type WorkerHeartbeatReq struct {
-	WorkerName         string  `json:"workerName"`
-	IP                 string  `json:"ip"`
-	CpuLoad            float64 `json:"cpuLoad"`
-	MemUsed            float64 `json:"memUsed"`
-	TaskStartedNumber  int32   `json:"taskStartedNumber"`
-	TaskExecutedNumber int32   `json:"taskExecutedNumber"`
-	Concurrency        int     `json:"concurrency"`
-	IsDaemon           bool    `json:"isDaemon"`
+	WorkerName         string   `json:"workerName"`
+	IP                 string   `json:"ip"`
+	CpuLoad            float64  `json:"cpuLoad"`
+	MemUsed            float64  `json:"memUsed"`
+	TaskStartedNumber  int32    `json:"taskStartedNumber"`
+	TaskExecutedNumber int32    `json:"taskExecutedNumber"`
+	Concurrency        int      `json:"concurrency"`
+	IsDaemon           bool     `json:"isDaemon"`
+	TaskIds            []string `json:"taskIds,omitempty"` // 正在执行的任务ID，用于续约
+	Labels             []string `json:"labels,omitempty"`  // Worker 标签
 }
 
 // WorkerHeartbeatResp 心跳响应
@@ -74,21 +78,33 @@ func WorkerHeartbeatHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			return
 		}
 
-		// 额外更新 concurrency 到 Redis（因为 proto 中没有这个字段）
-		if req.Concurrency > 0 {
+		// 额外更新 concurrency 和 labels 到 Redis（因为 proto 中没有这些字段）
+		if req.Concurrency > 0 || len(req.Labels) > 0 {
 			workerKey := "cscan:worker:" + req.WorkerName
-			// 获取现有数据并更新 concurrency
+			// 获取现有数据并更新 concurrency 和 labels
 			existingData, err := svcCtx.RedisClient.Get(r.Context(), workerKey).Result()
 			if err == nil {
 				var workerData map[string]interface{}
 				if json.Unmarshal([]byte(existingData), &workerData) == nil {
-					workerData["concurrency"] = req.Concurrency
+					if req.Concurrency > 0 {
+						workerData["concurrency"] = req.Concurrency
+					}
+					if len(req.Labels) > 0 {
+						workerData["labels"] = req.Labels
+					}
 					updatedJson, _ := json.Marshal(workerData)
 					svcCtx.RedisClient.Set(r.Context(), workerKey, updatedJson, 60*time.Second)
 				}
 			}
 		}
 
+		// 续约正在执行的任务，避免被调度器判定为崩溃而回收
+		if len(req.TaskIds) > 0 && svcCtx.Scheduler != nil {
+			if _, err := svcCtx.Scheduler.RenewLeases(r.Context(), req.TaskIds); err != nil {
+				logx.Errorf("[WorkerHeartbeat] renew leases error: %v", err)
+			}
+		}
+
 		httpx.OkJson(w, &WorkerHeartbeatResp{
 			Code:              0,
 			Msg:               "success",
@@ -139,8 +155,11 @@ func WorkerOfflineHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		workerKey := fmt.Sprintf("cscan:worker:%s", req.WorkerName)
 		rdb.Del(r.Context(), workerKey)
 
-		// 从Worker集合中移除
-		rdb.SRem(r.Context(), "cscan:workers", req.WorkerName)
+		// 从Worker集合中移除，移除成功说明此前在线，发送离线通知
+		if removed, _ := rdb.SRem(r.Context(), "cscan:workers", req.WorkerName).Result(); removed > 0 {
+			svcCtx.Notifier.Dispatch("", notify.WorkerOfflineMessage(req.WorkerName, "Worker主动下线"))
+			svcCtx.Webhook.Emit(webhook.WorkerOfflineEvent(req.WorkerName, "Worker主动下线"))
+		}
 
 		// 删除控制命令（如果有）
 		controlKey := fmt.Sprintf("cscan:worker:control:%s", req.WorkerName)
@@ -1,13 +1,18 @@
 package worker
 
 import (
+	"encoding/binary"
 	"encoding/json"
+	"net"
 	"net/http"
 
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/model"
+	"cscan/pkg/geoip"
 	"cscan/pkg/response"
 	"cscan/rpc/task/pb"
+	"cscan/scanner"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/rest/httpx"
@@ -30,29 +35,34 @@ type WorkerIPV6 struct {
 
 // WorkerAssetDocument 资产文档
 type WorkerAssetDocument struct {
-	Authority  string       `json:"authority"`
-	Host       string       `json:"host"`
-	Port       int32        `json:"port"`
-	Category   string       `json:"category"`
-	Service    string       `json:"service"`
-	Server     string       `json:"server"`
-	Banner     string       `json:"banner"`
-	Title      string       `json:"title"`
-	App        []string     `json:"app"`
-	HttpStatus string       `json:"httpStatus"`
-	HttpHeader string       `json:"httpHeader"`
-	HttpBody   string       `json:"httpBody"`
-	Cert       string       `json:"cert"`
-	IconHash   string       `json:"iconHash"`
-	IsCdn      bool         `json:"isCdn"`
-	Cname      string       `json:"cname"`
-	IsCloud    bool         `json:"isCloud"`
-	Ipv4       []WorkerIPV4 `json:"ipv4"`
-	Ipv6       []WorkerIPV6 `json:"ipv6"`
-	Screenshot string       `json:"screenshot"`
-	IsHttp     bool         `json:"isHttp"`
-	Source     string       `json:"source"`
-	IconData   []byte       `json:"iconData"`
+	Authority  string          `json:"authority"`
+	Host       string          `json:"host"`
+	Port       int32           `json:"port"`
+	Category   string          `json:"category"`
+	Service    string          `json:"service"`
+	Server     string          `json:"server"`
+	Banner     string          `json:"banner"`
+	Title      string          `json:"title"`
+	App        []string        `json:"app"`
+	HttpStatus string          `json:"httpStatus"`
+	HttpHeader string          `json:"httpHeader"`
+	HttpBody   string          `json:"httpBody"`
+	Cert       string          `json:"cert"`
+	IconHash   string          `json:"iconHash"`
+	IsCdn      bool            `json:"isCdn"`
+	Cname      string          `json:"cname"`
+	IsCloud    bool            `json:"isCloud"`
+	CdnName    string          `json:"cdnName,omitempty"`
+	Waf        string          `json:"waf,omitempty"`
+	CloudName  string          `json:"cloudName,omitempty"`
+	Ipv4       []WorkerIPV4    `json:"ipv4"`
+	Ipv6       []WorkerIPV6    `json:"ipv6"`
+	Screenshot string          `json:"screenshot"`
+	IsHttp     bool            `json:"isHttp"`
+	Source     string          `json:"source"`
+	IconData   []byte          `json:"iconData"`
+	Transport  string          `json:"transport,omitempty"`
+	TLS        json.RawMessage `json:"tls,omitempty"`
 }
 
 // WorkerTaskResultReq 资产结果上报请求
@@ -96,6 +106,8 @@ type WorkerVulDocument struct {
 	Request           *string  `json:"request,omitempty"`
 	Response          *string  `json:"response,omitempty"`
 	ResponseTruncated *bool    `json:"responseTruncated,omitempty"`
+
+	Interaction *scanner.VulInteraction `json:"interaction,omitempty"` // OOB 回连证据
 }
 
 // WorkerVulResultReq 漏洞结果上报请求
@@ -151,10 +163,15 @@ func WorkerTaskResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 				IsCdn:      asset.IsCdn,
 				Cname:      asset.Cname,
 				IsCloud:    asset.IsCloud,
+				CdnName:    asset.CdnName,
+				Waf:        asset.Waf,
+				CloudName:  asset.CloudName,
 				Screenshot: asset.Screenshot,
 				IsHttp:     asset.IsHttp,
 				Source:     asset.Source,
 				IconData:   asset.IconData,
+				Transport:  asset.Transport,
+				TlsInfo:    asset.TLS,
 			}
 
 			// 转换IPv4
@@ -174,6 +191,19 @@ func WorkerTaskResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 				})
 			}
 
+			// 离线库补全IP归属
+			enrichAssetGeo(svcCtx.GeoIP, pbAsset)
+
+			// 截图转存到对象存储，资产只保存引用，失败时保留原始数据
+			if pbAsset.Screenshot != "" && svcCtx.Screenshots != nil {
+				key, hash, err := common.StoreScreenshot(r.Context(), svcCtx.Screenshots, pbAsset.Screenshot)
+				if err != nil {
+					logx.Errorf("[WorkerTaskResult] store screenshot for %s failed: %v", pbAsset.Authority, err)
+				} else {
+					pbAsset.Screenshot, pbAsset.ScreenshotHash = key, hash
+				}
+			}
+
 			pbAssets = append(pbAssets, pbAsset)
 		}
 
@@ -203,6 +233,49 @@ func WorkerTaskResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 	}
 }
 
+// enrichAssetGeo 使用离线库补全资产IP的地理位置和ASN
+// Host 为IP但未上报IP信息的资产（如端口扫描结果）补充一条IP记录
+func enrichAssetGeo(resolver *geoip.Resolver, asset *pb.AssetDocument) {
+	if resolver == nil || !resolver.Available() {
+		return
+	}
+	if len(asset.Ipv4) == 0 && len(asset.Ipv6) == 0 {
+		if ip := net.ParseIP(asset.Host); ip != nil {
+			if ip.To4() != nil {
+				asset.Ipv4 = append(asset.Ipv4, &pb.IPV4{Ip: asset.Host})
+			} else {
+				asset.Ipv6 = append(asset.Ipv6, &pb.IPV6{Ip: asset.Host})
+			}
+		}
+	}
+
+	for _, ip := range asset.Ipv4 {
+		if v4 := net.ParseIP(ip.Ip).To4(); v4 != nil && ip.IpInt == 0 {
+			ip.IpInt = binary.BigEndian.Uint32(v4)
+		}
+		info := resolver.Lookup(ip.Ip)
+		if info.IsEmpty() {
+			continue
+		}
+		ip.Country, ip.Region, ip.City = info.Country, info.Region, info.City
+		ip.Asn, ip.Org, ip.Isp = uint32(info.ASN), info.Org, info.ISP
+		if loc := info.Location(); loc != "" {
+			ip.Location = loc
+		}
+	}
+	for _, ip := range asset.Ipv6 {
+		info := resolver.Lookup(ip.Ip)
+		if info.IsEmpty() {
+			continue
+		}
+		ip.Country, ip.Region, ip.City = info.Country, info.Region, info.City
+		ip.Asn, ip.Org, ip.Isp = uint32(info.ASN), info.Org, info.ISP
+		if loc := info.Location(); loc != "" {
+			ip.Location = loc
+		}
+	}
+}
+
 // ==================== Vul Result Handler ====================
 
 // WorkerVulResultHandler 漏洞结果上报接口
@@ -266,6 +339,19 @@ func WorkerVulResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 			if vul.ResponseTruncated != nil {
 				pbVul.ResponseTruncated = vul.ResponseTruncated
 			}
+			if i := vul.Interaction; i != nil {
+				pbVul.Interaction = &pb.VulInteraction{
+					Protocol:      i.Protocol,
+					UniqueId:      i.UniqueId,
+					FullId:        i.FullId,
+					QType:         i.QType,
+					RawRequest:    i.RawRequest,
+					RawResponse:   i.RawResponse,
+					RemoteAddress: i.RemoteAddress,
+					SmtpFrom:      i.SMTPFrom,
+					Timestamp:     i.Timestamp.UnixMilli(),
+				}
+			}
 
 			pbVuls = append(pbVuls, pbVul)
 		}
@@ -307,6 +393,7 @@ type WorkerDirScanResultDocument struct {
 	ContentType   string `json:"contentType"`
 	Title         string `json:"title"`
 	RedirectURL   string `json:"redirectUrl"`
+	Source        string `json:"source"`
 }
 
 // WorkerDirScanResultReq 目录扫描结果上报请求
@@ -365,6 +452,7 @@ func WorkerDirScanResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 				ContentType:   result.ContentType,
 				Title:         result.Title,
 				RedirectURL:   result.RedirectURL,
+				Source:        result.Source,
 			}
 
 			// 使用 Upsert 避免重复
@@ -385,3 +473,71 @@ func WorkerDirScanResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		})
 	}
 }
+
+// ==================== DNS Record Result ====================
+
+// WorkerDNSRecordDocument DNS记录文档
+type WorkerDNSRecordDocument struct {
+	Domain string `json:"domain"`
+	Root   string `json:"root"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	TTL    uint32 `json:"ttl"`
+	Source string `json:"source"`
+}
+
+// WorkerDNSRecordReq DNS记录上报请求
+type WorkerDNSRecordReq struct {
+	WorkspaceId string                    `json:"workspaceId"`
+	MainTaskId  string                    `json:"mainTaskId"`
+	Records     []WorkerDNSRecordDocument `json:"records"`
+}
+
+// WorkerDNSRecordResp DNS记录上报响应
+type WorkerDNSRecordResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Success bool   `json:"success"`
+	Total   int64  `json:"total"`
+}
+
+// WorkerDNSRecordResultHandler DNS记录上报接口
+// POST /api/v1/worker/task/dnsrecord
+func WorkerDNSRecordResultHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req WorkerDNSRecordReq
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			httpx.OkJson(w, &WorkerDNSRecordResp{Code: 400, Msg: "参数解析失败"})
+			return
+		}
+		if req.WorkspaceId == "" || req.MainTaskId == "" {
+			httpx.OkJson(w, &WorkerDNSRecordResp{Code: 400, Msg: "workspaceId和mainTaskId不能为空"})
+			return
+		}
+
+		docs := make([]*model.DNSRecord, 0, len(req.Records))
+		for _, rec := range req.Records {
+			if rec.Domain == "" || rec.Type == "" {
+				continue
+			}
+			docs = append(docs, &model.DNSRecord{
+				Domain:     rec.Domain,
+				Root:       rec.Root,
+				Type:       rec.Type,
+				Value:      rec.Value,
+				TTL:        rec.TTL,
+				Source:     rec.Source,
+				MainTaskId: req.MainTaskId,
+			})
+		}
+
+		saved, err := svcCtx.GetDNSRecordModel(req.WorkspaceId).BulkUpsert(r.Context(), docs)
+		if err != nil {
+			logx.Errorf("[WorkerDNSRecord] BulkUpsert error: %v", err)
+			httpx.OkJson(w, &WorkerDNSRecordResp{Code: 500, Msg: "保存失败"})
+			return
+		}
+		logx.Infof("[WorkerDNSRecord] Saved %d dns records for task %s", saved, req.MainTaskId)
+		httpx.OkJson(w, &WorkerDNSRecordResp{Code: 0, Msg: "success", Success: true, Total: saved})
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"cscan/api/internal/config"
 	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
+	"cscan/model"
 
 	"github.com/redis/go-redis/v9"
 )
@@ -30,8 +31,8 @@ func TestConsoleAuthMiddleware_AdminOnly(t *testing.T) {
 		expectedStatus int
 	}{
 		{
-			name:           "admin role should be allowed",
-			role:           "admin",
+			name:           "superadmin role should be allowed",
+			role:           model.RoleSuperAdmin,
 			expectedStatus: http.StatusOK,
 		},
 		{
@@ -347,7 +348,7 @@ func TestAuditLogRecording(t *testing.T) {
 
 	// Create request with admin role
 	req := httptest.NewRequest(http.MethodGet, "/api/v1/worker/console/info?name=test-worker", nil)
-	ctx := context.WithValue(req.Context(), middleware.RoleKey, "admin")
+	ctx := context.WithValue(req.Context(), middleware.RoleKey, model.RoleSuperAdmin)
 	ctx = context.WithValue(ctx, middleware.UserIdKey, "user-123")
 	ctx = context.WithValue(ctx, middleware.UsernameKey, "testuser")
 	req = req.WithContext(ctx)
@@ -16,7 +16,9 @@ import (
 
 // WorkerTaskCheckReq 任务拉取请求
 type WorkerTaskCheckReq struct {
-	WorkerName string `json:"workerName"`
+	WorkerName string   `json:"workerName"`
+	Labels     []string `json:"labels"`    // Worker 标签（key=value）
+	LabelOnly  bool     `json:"labelOnly"` // 只获取标签选择器匹配的任务
 }
 
 // WorkerTaskCheckResp 任务拉取响应
@@ -70,6 +72,8 @@ func WorkerTaskCheckHandler(svcCtx *svc.ServiceContext) http.HandlerFunc {
 		rpcReq := &pb.CheckTaskReq{
 			TaskId:     req.WorkerName,
 			MainTaskId: "",
+			Labels:     req.Labels,
+			LabelOnly:  req.LabelOnly,
 		}
 
 		rpcResp, err := svcCtx.TaskRpcClient.CheckTask(r.Context(), rpcReq)
@@ -680,9 +680,14 @@ func WorkerTerminalWSHandlerWithAuth(svcCtx *svc.ServiceContext, wsHandler *Work
 			return
 		}
 
-		// 检查是否是管理员
-		role, _ := claims["role"].(string)
-		if role != "admin" {
+		// 检查是否是超级管理员（以数据库中的角色为准）
+		userId, _ := claims["userId"].(string)
+		user, err := svcCtx.UserModel.FindById(r.Context(), userId)
+		if err != nil || user == nil || user.Status != model.StatusEnable {
+			http.Error(w, "invalid user", http.StatusUnauthorized)
+			return
+		}
+		if role := user.GetRole(); !model.RoleAtLeast(role, model.RoleSuperAdmin) {
 			logx.Errorf("[TerminalWS] Access denied for non-admin user, role: %s", role)
 			http.Error(w, "admin access required", http.StatusForbidden)
 			return
@@ -0,0 +1,158 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"cscan/api/internal/middleware"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// ApiTokenLogic API Token管理
+type ApiTokenLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewApiTokenLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ApiTokenLogic {
+	return &ApiTokenLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *ApiTokenLogic) isSuperAdmin() bool {
+	return model.RoleAtLeast(middleware.GetRole(l.ctx), model.RoleSuperAdmin)
+}
+
+// List Token列表，普通用户只能查看自己的Token
+func (l *ApiTokenLogic) List(req *types.ApiTokenListReq) (*types.ApiTokenListResp, error) {
+	userId := middleware.GetUserId(l.ctx)
+	if req.All && l.isSuperAdmin() {
+		userId = ""
+	}
+	tokens, err := l.svcCtx.ApiTokenModel.FindByUser(l.ctx, userId)
+	if err != nil {
+		l.Logger.Errorf("ApiToken List: %v", err)
+		return &types.ApiTokenListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.ApiTokenInfo, 0, len(tokens))
+	for i := range tokens {
+		list = append(list, toApiTokenInfo(&tokens[i]))
+	}
+	return &types.ApiTokenListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+// Create 创建Token，明文只在响应中返回一次
+func (l *ApiTokenLogic) Create(req *types.ApiTokenCreateReq) (*types.ApiTokenCreateResp, error) {
+	if req.Name == "" {
+		return &types.ApiTokenCreateResp{Code: 400, Msg: "名称不能为空"}, nil
+	}
+	if len(req.Scopes) == 0 {
+		return &types.ApiTokenCreateResp{Code: 400, Msg: "请选择权限范围"}, nil
+	}
+	for _, scope := range req.Scopes {
+		if !model.IsValidApiTokenScope(scope) {
+			return &types.ApiTokenCreateResp{Code: 400, Msg: "无效的权限范围: " + scope}, nil
+		}
+	}
+	if req.ExpireDays < 0 {
+		return &types.ApiTokenCreateResp{Code: 400, Msg: "有效天数不能为负数"}, nil
+	}
+
+	doc := &model.ApiToken{
+		Name:     req.Name,
+		Type:     req.Type,
+		Scopes:   req.Scopes,
+		UserId:   middleware.GetUserId(l.ctx),
+		Username: middleware.GetUsername(l.ctx),
+	}
+	switch req.Type {
+	case model.ApiTokenTypePersonal:
+		// 只读用户不能创建写权限的Token
+		role := middleware.GetRole(l.ctx)
+		for _, scope := range req.Scopes {
+			if scope != model.ApiTokenScopeRead && !model.RoleAtLeast(role, model.RoleOperator) {
+				return &types.ApiTokenCreateResp{Code: 403, Msg: "当前角色只能创建只读Token"}, nil
+			}
+		}
+	case model.ApiTokenTypeService:
+		if !l.isSuperAdmin() {
+			return &types.ApiTokenCreateResp{Code: 403, Msg: "只有超级管理员可以创建服务Token"}, nil
+		}
+		if len(req.WorkspaceIds) == 0 {
+			return &types.ApiTokenCreateResp{Code: 400, Msg: "服务Token需要指定工作空间"}, nil
+		}
+		doc.WorkspaceIds = req.WorkspaceIds
+	default:
+		return &types.ApiTokenCreateResp{Code: 400, Msg: "无效的Token类型"}, nil
+	}
+	if req.ExpireDays > 0 {
+		expire := time.Now().AddDate(0, 0, req.ExpireDays)
+		doc.ExpireTime = &expire
+	}
+
+	plain, err := model.GenerateApiToken()
+	if err != nil {
+		l.Logger.Errorf("ApiToken Create: generate token: %v", err)
+		return &types.ApiTokenCreateResp{Code: 500, Msg: "生成Token失败"}, nil
+	}
+	doc.TokenHash = model.HashApiToken(plain)
+	doc.TokenPrefix = plain[:len(model.ApiTokenPrefix)+6]
+
+	if err := l.svcCtx.ApiTokenModel.Create(l.ctx, doc); err != nil {
+		l.Logger.Errorf("ApiToken Create: %v", err)
+		return &types.ApiTokenCreateResp{Code: 500, Msg: "创建失败"}, nil
+	}
+	return &types.ApiTokenCreateResp{
+		Code:  0,
+		Msg:   "创建成功，请妥善保存Token，关闭后将无法再次查看",
+		Token: plain,
+		Info:  toApiTokenInfo(doc),
+	}, nil
+}
+
+// Revoke 吊销Token，超级管理员可吊销任意Token
+func (l *ApiTokenLogic) Revoke(req *types.ApiTokenRevokeReq) (*types.BaseResp, error) {
+	token, err := l.svcCtx.ApiTokenModel.FindById(l.ctx, req.Id)
+	if err != nil {
+		return &types.BaseResp{Code: 404, Msg: "Token不存在"}, nil
+	}
+	if token.UserId != middleware.GetUserId(l.ctx) && !l.isSuperAdmin() {
+		return &types.BaseResp{Code: 403, Msg: "无权吊销该Token"}, nil
+	}
+	if err := l.svcCtx.ApiTokenModel.DeleteById(l.ctx, req.Id); err != nil {
+		l.Logger.Errorf("ApiToken Revoke: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "吊销失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "吊销成功"}, nil
+}
+
+func toApiTokenInfo(t *model.ApiToken) types.ApiTokenInfo {
+	info := types.ApiTokenInfo{
+		Id:           t.Id.Hex(),
+		Name:         t.Name,
+		Type:         t.Type,
+		TokenPrefix:  t.TokenPrefix,
+		Scopes:       t.Scopes,
+		Username:     t.Username,
+		WorkspaceIds: t.WorkspaceIds,
+		LastUsedIP:   t.LastUsedIP,
+		Expired:      t.Expired(),
+		CreateTime:   t.CreateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+	if t.ExpireTime != nil {
+		info.ExpireTime = t.ExpireTime.Local().Format("2006-01-02 15:04:05")
+	}
+	if t.LastUsedTime != nil {
+		info.LastUsedTime = t.LastUsedTime.Local().Format("2006-01-02 15:04:05")
+	}
+	return info
+}
@@ -0,0 +1,178 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/assetdiff"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// AssetDiffLogic 资产对比
+type AssetDiffLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewAssetDiffLogic(ctx context.Context, svcCtx *svc.ServiceContext) *AssetDiffLogic {
+	return &AssetDiffLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// AssetDiff 对比两个任务或两个时间点之间的资产变化
+func (l *AssetDiffLogic) AssetDiff(req *types.AssetDiffReq, workspaceId string) (*types.AssetDiffResp, error) {
+	builder := assetdiff.NewBuilder(
+		l.svcCtx.GetAssetModel(workspaceId),
+		l.svcCtx.GetAssetHistoryModel(workspaceId),
+		l.svcCtx.GetVulModel(workspaceId),
+	)
+
+	var base, target *assetdiff.Snapshot
+	var errResp *types.AssetDiffResp
+	switch {
+	case req.BaseTaskId != "" || req.TargetTaskId != "":
+		base, target, errResp = l.diffByTask(builder, req, workspaceId)
+	case req.StartTime != "":
+		base, target, errResp = l.diffByTime(builder, req)
+	default:
+		errResp = &types.AssetDiffResp{Code: 400, Msg: "请指定对比的任务或时间范围"}
+	}
+	if errResp != nil {
+		return errResp, nil
+	}
+
+	result := assetdiff.Compare(base, target)
+	resp := &types.AssetDiffResp{
+		Code:         0,
+		Msg:          "success",
+		Summary:      *toDiffSummary(result.Summary()),
+		NewHosts:     result.NewHosts,
+		NewPorts:     toDiffAssets(result.NewPorts),
+		ClosedPorts:  toDiffAssets(result.ClosedPorts),
+		Changes:      make([]types.AssetDiffChange, 0, len(result.Changes)),
+		NewVuls:      toDiffVuls(result.NewVuls),
+		ResolvedVuls: toDiffVuls(result.ResolvedVuls),
+	}
+	if resp.NewHosts == nil {
+		resp.NewHosts = []string{}
+	}
+	for _, c := range result.Changes {
+		fields := make([]types.AssetDiffField, 0, len(c.Fields))
+		for _, f := range c.Fields {
+			fields = append(fields, types.AssetDiffField{Field: f.Field, Old: f.Old, New: f.New})
+		}
+		resp.Changes = append(resp.Changes, types.AssetDiffChange{
+			Authority: c.Authority,
+			Host:      c.Host,
+			Port:      c.Port,
+			Fields:    fields,
+		})
+	}
+	return resp, nil
+}
+
+// diffByTask 构建两个任务的快照
+func (l *AssetDiffLogic) diffByTask(builder *assetdiff.Builder, req *types.AssetDiffReq, workspaceId string) (base, target *assetdiff.Snapshot, errResp *types.AssetDiffResp) {
+	if req.BaseTaskId == "" || req.TargetTaskId == "" {
+		return nil, nil, &types.AssetDiffResp{Code: 400, Msg: "请同时指定基准任务和对比任务"}
+	}
+	if req.BaseTaskId == req.TargetTaskId {
+		return nil, nil, &types.AssetDiffResp{Code: 400, Msg: "基准任务和对比任务不能相同"}
+	}
+	taskModel := l.svcCtx.GetMainTaskModel(workspaceId)
+	baseTask, err := taskModel.FindById(l.ctx, req.BaseTaskId)
+	if err != nil {
+		return nil, nil, &types.AssetDiffResp{Code: 400, Msg: "基准任务不存在"}
+	}
+	targetTask, err := taskModel.FindById(l.ctx, req.TargetTaskId)
+	if err != nil {
+		return nil, nil, &types.AssetDiffResp{Code: 400, Msg: "对比任务不存在"}
+	}
+
+	if base, err = builder.ByTask(l.ctx, baseTask); err == nil {
+		target, err = builder.ByTask(l.ctx, targetTask)
+	}
+	if err != nil {
+		l.Logger.Errorf("AssetDiff: build task snapshot failed: %v", err)
+		return nil, nil, &types.AssetDiffResp{Code: 500, Msg: "查询失败"}
+	}
+	return base, target, nil
+}
+
+// diffByTime 构建开始时间的资产状态，以及开始到结束时间内扫描到的资产
+func (l *AssetDiffLogic) diffByTime(builder *assetdiff.Builder, req *types.AssetDiffReq) (base, target *assetdiff.Snapshot, errResp *types.AssetDiffResp) {
+	startTime, err := time.ParseInLocation("2006-01-02 15:04:05", req.StartTime, time.Local)
+	if err != nil {
+		return nil, nil, &types.AssetDiffResp{Code: 400, Msg: "开始时间格式错误"}
+	}
+	endTime := time.Now()
+	if req.EndTime != "" {
+		if endTime, err = time.ParseInLocation("2006-01-02 15:04:05", req.EndTime, time.Local); err != nil {
+			return nil, nil, &types.AssetDiffResp{Code: 400, Msg: "结束时间格式错误"}
+		}
+	}
+	if !endTime.After(startTime) {
+		return nil, nil, &types.AssetDiffResp{Code: 400, Msg: "结束时间必须晚于开始时间"}
+	}
+
+	if base, err = builder.At(l.ctx, startTime); err == nil {
+		target, err = builder.Between(l.ctx, startTime, endTime)
+	}
+	if err != nil {
+		l.Logger.Errorf("AssetDiff: build time snapshot failed: %v", err)
+		return nil, nil, &types.AssetDiffResp{Code: 500, Msg: "查询失败"}
+	}
+	return base, target, nil
+}
+
+func toDiffSummary(s *model.AssetDiffSummary) *types.AssetDiffSummary {
+	if s == nil {
+		return nil
+	}
+	return &types.AssetDiffSummary{
+		NewHosts:      s.NewHosts,
+		NewPorts:      s.NewPorts,
+		ClosedPorts:   s.ClosedPorts,
+		ChangedAssets: s.ChangedAssets,
+		NewVuls:       s.NewVuls,
+		ResolvedVuls:  s.ResolvedVuls,
+		BaseTime:      s.BaseTime.Local().Format("2006-01-02 15:04:05"),
+		TargetTime:    s.TargetTime.Local().Format("2006-01-02 15:04:05"),
+	}
+}
+
+func toDiffAssets(assets []*assetdiff.AssetState) []types.AssetDiffAsset {
+	list := make([]types.AssetDiffAsset, 0, len(assets))
+	for _, a := range assets {
+		list = append(list, types.AssetDiffAsset{
+			Authority: a.Authority,
+			Host:      a.Host,
+			Port:      a.Port,
+			Service:   a.Service,
+			Title:     a.Title,
+			App:       a.App,
+		})
+	}
+	return list
+}
+
+func toDiffVuls(vuls []*assetdiff.VulState) []types.AssetDiffVul {
+	list := make([]types.AssetDiffVul, 0, len(vuls))
+	for _, v := range vuls {
+		list = append(list, types.AssetDiffVul{
+			Authority: v.Authority,
+			Url:       v.Url,
+			PocFile:   v.PocFile,
+			Severity:  v.Severity,
+		})
+	}
+	return list
+}
@@ -10,9 +10,11 @@ import (
 	"strconv"
 	"strings"
 
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
+	"cscan/pkg/query"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
@@ -36,6 +38,29 @@ func sortAssetsByTime(assets []model.Asset, byUpdateTime bool) {
 }
 
 // sortMapToStatItems 将 map 转换为排序后的 StatItem 列表
+// convertAssetTLS 提取叶子证书和握手信息摘要
+func convertAssetTLS(t *model.TLSInfo) *types.AssetTLS {
+	if t == nil {
+		return nil
+	}
+	info := &types.AssetTLS{
+		Versions: t.Versions,
+		Findings: t.Findings,
+		JA3S:     t.JA3S,
+		JARM:     t.JARM,
+	}
+	if len(t.Chain) > 0 {
+		leaf := t.Chain[0]
+		info.Subject = leaf.Subject
+		info.Issuer = leaf.Issuer
+		info.NotAfter = leaf.NotAfter.Local().Format("2006-01-02 15:04:05")
+		info.SANs = leaf.SANs
+		info.KeyType = leaf.KeyType
+		info.KeyBits = leaf.KeyBits
+	}
+	return info
+}
+
 func sortMapToStatItems(m map[string]int, limit int) []types.StatItem {
 	type kv struct {
 		Key   string
@@ -103,56 +128,31 @@ func sortIconHashMap(m map[string]*types.IconHashStatItem, limit int) []types.Ic
 	return result
 }
 
-// parseQuerySyntax 解析查询语法
-// 支持格式: port=80 && service=http || title="test"
-func parseQuerySyntax(query string, filter bson.M) {
-	query = strings.TrimSpace(query)
-	if query == "" {
-		return
-	}
-
-	// 简单解析：支持 field=value 格式，多个条件用 && 连接
-	// 例如: port=80 && service=http && title=test
-	conditions := strings.Split(query, "&&")
-	for _, cond := range conditions {
-		cond = strings.TrimSpace(cond)
-		if cond == "" {
-			continue
-		}
+// buildAssetQuery 解析资产查询语法
+// 支持格式: (port=80 || port>=8000) && !app=nginx && org="研发中心"
+func buildAssetQuery(ctx context.Context, svcCtx *svc.ServiceContext, q string) (bson.M, error) {
+	schema := query.AssetSchema()
+	schema.Fields["app"].Transform = cleanAppName
+	schema.Set(&query.Field{Build: func(op, value string) (bson.M, error) {
+		return orgQuery(ctx, svcCtx, op, value), nil
+	}}, "org")
+	return query.Build(q, schema)
+}
 
-		// 解析 field=value 或 field="value"
-		parts := strings.SplitN(cond, "=", 2)
-		if len(parts) != 2 {
-			continue
+// orgQuery 按组织名称（或组织ID）匹配，转换为 org_id 条件
+func orgQuery(ctx context.Context, svcCtx *svc.ServiceContext, op, value string) bson.M {
+	ids := []string{}
+	lower := strings.ToLower(value)
+	for id, name := range common.LoadOrgMap(ctx, svcCtx) {
+		matched := id == value || name == value
+		if !matched && op == "=" {
+			matched = strings.Contains(strings.ToLower(name), lower)
 		}
-
-		field := strings.TrimSpace(parts[0])
-		value := strings.TrimSpace(parts[1])
-		// 去除引号
-		value = strings.Trim(value, "\"'")
-
-		// 映射字段名
-		switch strings.ToLower(field) {
-		case "port":
-			if port, err := strconv.Atoi(value); err == nil {
-				filter["port"] = port
-			}
-		case "host", "ip":
-			filter["host"] = bson.M{"$regex": value, "$options": "i"}
-		case "service", "protocol":
-			filter["service"] = bson.M{"$regex": value, "$options": "i"}
-		case "title":
-			filter["title"] = bson.M{"$regex": value, "$options": "i"}
-		case "app", "finger", "fingerprint":
-			filter["app"] = bson.M{"$regex": cleanAppName(value), "$options": "i"}
-		case "status", "httpstatus":
-			filter["status"] = value
-		case "domain":
-			filter["domain"] = bson.M{"$regex": value, "$options": "i"}
-		case "banner":
-			filter["banner"] = bson.M{"$regex": value, "$options": "i"}
+		if matched {
+			ids = append(ids, id)
 		}
 	}
+	return bson.M{"org_id": bson.M{"$in": ids}}
 }
 
 type AssetListLogic struct {
@@ -175,7 +175,14 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 
 	// 如果有语法查询，解析语法
 	if req.Query != "" {
-		parseQuerySyntax(req.Query, filter)
+		q, err := buildAssetQuery(l.ctx, l.svcCtx, req.Query)
+		if err != nil {
+			return &types.AssetListResp{Code: 400, Msg: err.Error()}, nil
+		}
+		// 放入 $and，避免与下方的筛选条件字段冲突
+		if len(q) > 0 {
+			filter["$and"] = []bson.M{q}
+		}
 	} else {
 		// 快捷查询
 		if req.Host != "" {
@@ -226,12 +233,12 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 
 	// 如果 workspaceId 为空，查询所有工作空间
 	if workspaceId == "" {
-		workspaces, _ := l.svcCtx.WorkspaceModel.Find(l.ctx, bson.M{}, 1, 100)
+		workspaceIds := common.GetWorkspaceIds(l.ctx, l.svcCtx, "")
 		
 		// 收集所有工作空间的数据
 		var allAssets []model.Asset
-		for _, ws := range workspaces {
-			assetModel := l.svcCtx.GetAssetModel(ws.Id.Hex())
+		for _, wsId := range workspaceIds {
+			assetModel := l.svcCtx.GetAssetModel(wsId)
 			wsTotal, _ := assetModel.Count(l.ctx, filter)
 			total += wsTotal
 			
@@ -335,6 +342,7 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 			Port:       a.Port,
 			Category:   a.Category,
 			Service:    a.Service,
+			Transport:  a.Transport,
 			Title:      a.Title,
 			App:        a.App,
 			HttpStatus: a.HttpStatus,
@@ -348,6 +356,9 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 			IP:         ipInfo,
 			IsCDN:      a.IsCDN,
 			IsCloud:    a.IsCloud,
+			CDNName:    a.CDNName,
+			WAF:        a.WAF,
+			CloudName:  a.CloudName,
 			IsNew:      a.IsNewAsset,
 			IsUpdated:  a.IsUpdated,
 			CreateTime: a.CreateTime.Local().Format("2006-01-02 15:04:05"),
@@ -358,6 +369,7 @@ func (l *AssetListLogic) AssetList(req *types.AssetListReq, workspaceId string)
 			// 新增字段 - 风险评分 
 			RiskScore: a.RiskScore,
 			RiskLevel: a.RiskLevel,
+			TLS:       convertAssetTLS(a.TLS),
 		})
 	}
 
@@ -391,7 +403,7 @@ func (l *AssetStatLogic) AssetStat(workspaceId string) (resp *types.AssetStatRes
 
 	// 如果 workspaceId 为空，统计所有工作空间
 	if workspaceId == "" {
-		workspaces, _ := l.svcCtx.WorkspaceModel.Find(l.ctx, bson.M{}, 1, 100)
+		workspaceIds := common.GetWorkspaceIds(l.ctx, l.svcCtx, "")
 		
 		portMap := make(map[int]int)
 		serviceMap := make(map[string]int)
@@ -400,8 +412,8 @@ func (l *AssetStatLogic) AssetStat(workspaceId string) (resp *types.AssetStatRes
 		iconHashMap := make(map[string]*types.IconHashStatItem)
 		riskMap := make(map[string]int)
 		
-		for _, ws := range workspaces {
-			assetModel := l.svcCtx.GetAssetModel(ws.Id.Hex())
+		for _, wsId := range workspaceIds {
+			assetModel := l.svcCtx.GetAssetModel(wsId)
 			
 			wsTotal, _ := assetModel.Count(l.ctx, bson.M{})
 			totalAsset += wsTotal
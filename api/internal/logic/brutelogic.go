@@ -0,0 +1,161 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// BruteDictLogic 弱口令爆破字典逻辑
+type BruteDictLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewBruteDictLogic(ctx context.Context, svcCtx *svc.ServiceContext) *BruteDictLogic {
+	return &BruteDictLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *BruteDictLogic) List(req *types.BruteDictListReq, workspaceId string) (*types.BruteDictListResp, error) {
+	dictModel := model.NewBruteDictModel(l.svcCtx.MongoDB)
+
+	dicts, err := dictModel.Find(l.ctx, workspaceId, req.Type, req.Page, req.PageSize)
+	if err != nil {
+		return nil, err
+	}
+	total, err := dictModel.Count(l.ctx, workspaceId, req.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]types.BruteDict, 0, len(dicts))
+	for _, d := range dicts {
+		list = append(list, types.BruteDict{
+			Id:          d.Id.Hex(),
+			Name:        d.Name,
+			Description: d.Description,
+			Type:        d.Type,
+			Services:    d.Services,
+			Content:     d.Content,
+			WordCount:   d.WordCount,
+			Enabled:     d.Enabled,
+			CreateTime:  d.CreateTime.Format("2006-01-02 15:04:05"),
+			UpdateTime:  d.UpdateTime.Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	return &types.BruteDictListResp{
+		Code:  0,
+		Msg:   "success",
+		Total: int(total),
+		List:  list,
+	}, nil
+}
+
+func (l *BruteDictLogic) Save(req *types.BruteDictSaveReq, workspaceId string) (*types.BaseRespWithId, error) {
+	if strings.TrimSpace(req.Name) == "" {
+		return &types.BaseRespWithId{Code: 400, Msg: "字典名称不能为空"}, nil
+	}
+	if req.Type != model.BruteDictUsername && req.Type != model.BruteDictPassword {
+		return &types.BaseRespWithId{Code: 400, Msg: "字典类型必须为username或password"}, nil
+	}
+	services, bad := normalizeBruteServices(req.Services)
+	if bad != "" {
+		return &types.BaseRespWithId{Code: 400, Msg: "不支持的服务: " + bad}, nil
+	}
+
+	dictModel := model.NewBruteDictModel(l.svcCtx.MongoDB)
+	dict := &model.BruteDict{
+		WorkspaceId: workspaceId,
+		Name:        req.Name,
+		Description: req.Description,
+		Type:        req.Type,
+		Services:    services,
+		Content:     req.Content,
+		WordCount:   countBruteWords(req.Content),
+		Enabled:     req.Enabled,
+	}
+
+	if req.Id != "" {
+		if err := dictModel.Update(l.ctx, workspaceId, req.Id, dict); err != nil {
+			if errors.Is(err, mongo.ErrNoDocuments) {
+				return &types.BaseRespWithId{Code: 404, Msg: "字典不存在"}, nil
+			}
+			return nil, err
+		}
+		return &types.BaseRespWithId{Code: 0, Msg: "success", Id: req.Id}, nil
+	}
+
+	if err := dictModel.Insert(l.ctx, dict); err != nil {
+		return nil, err
+	}
+	return &types.BaseRespWithId{Code: 0, Msg: "success", Id: dict.Id.Hex()}, nil
+}
+
+func (l *BruteDictLogic) Delete(req *types.BruteDictDeleteReq, workspaceId string) (*types.BaseResp, error) {
+	dictModel := model.NewBruteDictModel(l.svcCtx.MongoDB)
+	if err := dictModel.Delete(l.ctx, workspaceId, req.Id); err != nil {
+		return nil, err
+	}
+	return &types.BaseResp{Code: 0, Msg: "success"}, nil
+}
+
+func (l *BruteDictLogic) EnabledList(workspaceId string) (*types.BruteDictEnabledListResp, error) {
+	dictModel := model.NewBruteDictModel(l.svcCtx.MongoDB)
+	dicts, err := dictModel.FindEnabled(l.ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]types.BruteDictSimple, 0, len(dicts))
+	for _, d := range dicts {
+		list = append(list, types.BruteDictSimple{
+			Id:        d.Id.Hex(),
+			Name:      d.Name,
+			Type:      d.Type,
+			Services:  d.Services,
+			WordCount: d.WordCount,
+		})
+	}
+	return &types.BruteDictEnabledListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+// normalizeBruteServices 规范化服务列表，返回第一个不支持的服务
+func normalizeBruteServices(services []string) ([]string, string) {
+	supported := make(map[string]bool, len(scanner.BruteServices))
+	for _, s := range scanner.BruteServices {
+		supported[s] = true
+	}
+	result := make([]string, 0, len(services))
+	seen := make(map[string]bool)
+	for _, s := range services {
+		s = strings.ToLower(strings.TrimSpace(s))
+		if s == "" || seen[s] {
+			continue
+		}
+		if !supported[s] {
+			return nil, s
+		}
+		seen[s] = true
+		result = append(result, s)
+	}
+	return result, ""
+}
+
+// countBruteWords 计算字典条目数量，空行和注释不计入
+func countBruteWords(content string) int {
+	return len(model.ParseBruteWords(content))
+}
@@ -0,0 +1,47 @@
+package common
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// ValidateTaskConfig 校验任务配置中的声明式流水线和 Worker 标签选择器
+func ValidateTaskConfig(config string) error {
+	if config == "" {
+		return nil
+	}
+	// 只解析需要校验的字段，其余配置沿用原有的宽松处理
+	var taskConfig struct {
+		Pipeline       *scheduler.Pipeline `json:"pipeline"`
+		WorkerSelector string              `json:"workerSelector"`
+	}
+	if err := json.Unmarshal([]byte(config), &taskConfig); err != nil {
+		return fmt.Errorf("任务配置格式错误: %v", err)
+	}
+	if _, err := scheduler.ParseSelector(taskConfig.WorkerSelector); err != nil {
+		return fmt.Errorf("Worker标签选择器错误: %v", err)
+	}
+	return validatePipeline(taskConfig.Pipeline)
+}
+
+// validatePipeline 校验声明式流水线，未配置流水线时返回 nil
+func validatePipeline(p *scheduler.Pipeline) error {
+	if !p.Enabled() {
+		return nil
+	}
+	if _, err := p.Levels(); err != nil {
+		return fmt.Errorf("流水线配置错误: %v", err)
+	}
+	for _, stage := range p.Stages {
+		if _, ok := scanner.Lookup(stage.Scanner); !ok {
+			return fmt.Errorf("流水线配置错误: 阶段 %s 的扫描器 %s 不存在，可用扫描器: %v", stage.Name, stage.Scanner, scanner.Registered())
+		}
+		if _, err := scanner.DecodeOptions(stage.Scanner, stage.Options); err != nil {
+			return fmt.Errorf("流水线配置错误: 阶段 %s: %v", stage.Name, err)
+		}
+	}
+	return nil
+}
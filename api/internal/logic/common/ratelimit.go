@@ -0,0 +1,19 @@
+package common
+
+import (
+	"cscan/model"
+	"cscan/pkg/ratelimit"
+)
+
+// RateLimitBudget 转换工作空间限速配置为限速预算
+func RateLimitBudget(r *model.RateLimit) *ratelimit.Budget {
+	if r == nil {
+		return nil
+	}
+	return &ratelimit.Budget{
+		HostRate:      r.HostRate,
+		CIDRRate:      r.CIDRRate,
+		CIDRPrefix:    r.CIDRPrefix,
+		WorkspaceRate: r.WorkspaceRate,
+	}
+}
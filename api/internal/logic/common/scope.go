@@ -0,0 +1,107 @@
+package common
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"cscan/api/internal/svc"
+	"cscan/model"
+	"cscan/scheduler"
+
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ScopeRules 转换工作空间扫描范围为调度器规则
+func ScopeRules(s *model.ScanScope) *scheduler.ScopeRules {
+	if s == nil {
+		return nil
+	}
+	return &scheduler.ScopeRules{
+		AllowedCidrs:   s.AllowedCidrs,
+		AllowedDomains: s.AllowedDomains,
+		ExcludedHosts:  s.ExcludedHosts,
+		ExcludedPorts:  s.ExcludedPorts,
+	}
+}
+
+// LoadScope 加载工作空间扫描范围，未配置或默认工作空间返回 nil
+func LoadScope(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId string) (*scheduler.Scope, error) {
+	if !primitive.IsValidObjectID(workspaceId) {
+		return nil, nil
+	}
+	ws, err := svcCtx.WorkspaceModel.FindById(ctx, workspaceId)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scheduler.NewScope(ScopeRules(ws.Scope))
+}
+
+// FormatScopeDrops 格式化超出扫描范围的目标，最多列出10个
+func FormatScopeDrops(drops []scheduler.ScopeDrop) string {
+	var lines []string
+	for i, d := range drops {
+		if i >= 10 {
+			lines = append(lines, fmt.Sprintf("... 共 %d 个目标", len(drops)))
+			break
+		}
+		lines = append(lines, d.String())
+	}
+	return "以下目标不在工作空间扫描范围内:\n" + strings.Join(lines, "\n")
+}
+
+// WriteTaskLog 写入任务日志，格式与 Worker 上报的日志一致
+func WriteTaskLog(ctx context.Context, svcCtx *svc.ServiceContext, taskId, level, format string, args ...interface{}) {
+	logJSON, err := json.Marshal(map[string]interface{}{
+		"level":      level,
+		"message":    fmt.Sprintf(format, args...),
+		"timestamp":  time.Now().Local().Format("2006-01-02 15:04:05"),
+		"workerName": "server",
+		"taskId":     taskId,
+	})
+	if err != nil {
+		return
+	}
+	svcCtx.RedisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: "cscan:task:logs:" + taskId,
+		MaxLen: 5000,
+		Approx: true,
+		Values: map[string]interface{}{"data": string(logJSON)},
+	})
+	svcCtx.RedisClient.Publish(ctx, "cscan:task:logs:realtime:"+taskId, string(logJSON))
+}
+
+// SplitTargetsInScope 按工作空间扫描范围过滤并拆分目标，被过滤的目标写入任务日志
+func SplitTargetsInScope(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId, taskId, target string, batchSize int) ([]string, error) {
+	scope, err := LoadScope(ctx, svcCtx, workspaceId)
+	if err != nil {
+		return nil, fmt.Errorf("加载扫描范围失败: %w", err)
+	}
+	splitter := scheduler.NewTargetSplitter(batchSize).SetScope(scope, func(d scheduler.ScopeDrop) {
+		WriteTaskLog(ctx, svcCtx, taskId, "WARN", "[Scope] 跳过超出扫描范围的目标 %s", d)
+	})
+	batches := splitter.SplitTargets(target)
+	if len(batches) == 0 {
+		return nil, fmt.Errorf("所有目标均不在工作空间扫描范围内")
+	}
+	return batches, nil
+}
+
+// CheckTargetScope 校验目标是否都在工作空间扫描范围内，返回错误信息
+func CheckTargetScope(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId, target string) string {
+	scope, err := LoadScope(ctx, svcCtx, workspaceId)
+	if err != nil {
+		return "加载扫描范围失败: " + err.Error()
+	}
+	if _, drops := scope.FilterTargets(target); len(drops) > 0 {
+		return FormatScopeDrops(drops)
+	}
+	return ""
+}
@@ -0,0 +1,58 @@
+package common
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+
+	"cscan/pkg/blob"
+	"cscan/pkg/imagehash"
+)
+
+// StoreScreenshot 将 base64 截图写入对象存储，返回存储 key 和感知哈希
+// 已经是存储 key 的直接返回；哈希计算失败时 hash 为空，不影响保存
+func StoreScreenshot(ctx context.Context, store blob.Store, screenshot string) (key, hash string, err error) {
+	if screenshot == "" || blob.IsKey(screenshot) {
+		return screenshot, "", nil
+	}
+	data, err := DecodeScreenshot(screenshot)
+	if err != nil {
+		return "", "", err
+	}
+
+	key, contentType := blob.Key(data)
+	exists, err := store.Exists(ctx, key)
+	if err != nil {
+		return "", "", err
+	}
+	if !exists {
+		if err := store.Put(ctx, key, data, contentType); err != nil {
+			return "", "", err
+		}
+	}
+	hash, _ = imagehash.PHash(data)
+	return key, hash, nil
+}
+
+// DecodeScreenshot 解码 base64 截图，兼容 data URI 格式
+func DecodeScreenshot(s string) ([]byte, error) {
+	if strings.HasPrefix(s, "data:") {
+		idx := strings.Index(s, ",")
+		if idx < 0 {
+			return nil, errors.New("invalid data uri")
+		}
+		s = s[idx+1:]
+	}
+	data, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		data, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(s, "="))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty screenshot")
+	}
+	return data, nil
+}
@@ -0,0 +1,56 @@
+package common
+
+import (
+	"context"
+
+	"cscan/api/internal/svc"
+	"cscan/model"
+	"cscan/scheduler"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// WindowRules 转换维护窗口为调度器规则
+func WindowRules(w *model.MaintenanceWindow) *scheduler.WindowRules {
+	if w == nil {
+		return nil
+	}
+	return &scheduler.WindowRules{
+		Timezone:      w.Timezone,
+		AllowedHours:  w.AllowedHours,
+		BlackoutDates: w.BlackoutDates,
+	}
+}
+
+// LoadWindows 加载工作空间和组织的维护窗口，未配置的不返回
+func LoadWindows(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId, orgId string) (scheduler.Windows, error) {
+	var windows scheduler.Windows
+	if primitive.IsValidObjectID(workspaceId) {
+		ws, err := svcCtx.WorkspaceModel.FindById(ctx, workspaceId)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		if ws != nil && ws.Window != nil {
+			w, err := scheduler.NewWindow(WindowRules(ws.Window))
+			if err != nil {
+				return nil, err
+			}
+			windows = append(windows, w)
+		}
+	}
+	if primitive.IsValidObjectID(orgId) {
+		org, err := svcCtx.OrganizationModel.FindById(ctx, orgId)
+		if err != nil && err != mongo.ErrNoDocuments {
+			return nil, err
+		}
+		if org != nil && org.Window != nil {
+			w, err := scheduler.NewWindow(WindowRules(org.Window))
+			if err != nil {
+				return nil, err
+			}
+			windows = append(windows, w)
+		}
+	}
+	return windows, nil
+}
@@ -2,20 +2,27 @@ package common
 
 import (
 	"context"
+	"slices"
 
+	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
 
 // GetWorkspaceIds 获取工作空间ID列表
-// 当 workspaceId 为空或 "all" 时，返回所有工作空间ID
+// 当 workspaceId 为空或 "all" 时，返回当前用户可访问的所有工作空间ID
 func GetWorkspaceIds(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId string) []string {
 	// 处理 "all" 值 - 前端传递 "all" 表示查询所有工作空间
 	if workspaceId != "" && workspaceId != "all" {
 		return []string{workspaceId}
 	}
 
+	// 非超级管理员只能查询所属工作空间
+	if allowed := middleware.GetAllowedWorkspaces(ctx); allowed != nil {
+		return allowed
+	}
+
 	// 查询所有工作空间
 	workspaces, err := svcCtx.WorkspaceModel.Find(ctx, bson.M{}, 1, 100)
 	if err != nil {
@@ -29,6 +36,19 @@ func GetWorkspaceIds(ctx context.Context, svcCtx *svc.ServiceContext, workspaceI
 	return ids
 }
 
+// ResolveWorkspaceId 解析请求实际操作的工作空间
+// 请求体中的 workspaceId 优先于请求头，但受限用户只能指定其可访问的工作空间，返回 false 表示无权访问
+func ResolveWorkspaceId(ctx context.Context, reqWorkspaceId, workspaceId string) (string, bool) {
+	if reqWorkspaceId == "" {
+		return workspaceId, true
+	}
+	// 请求头中的工作空间已由认证中间件校验，请求体中的需要在此校验
+	if allowed := middleware.GetAllowedWorkspaces(ctx); allowed != nil && !slices.Contains(allowed, reqWorkspaceId) {
+		return "", false
+	}
+	return reqWorkspaceId, true
+}
+
 // LoadOrgMap 加载组织ID到名称的映射
 func LoadOrgMap(ctx context.Context, svcCtx *svc.ServiceContext) map[string]string {
 	orgMap := make(map[string]string)
@@ -0,0 +1,99 @@
+package logic
+
+import (
+	"context"
+	"regexp"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DNSRecordLogic DNS记录逻辑
+type DNSRecordLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDNSRecordLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DNSRecordLogic {
+	return &DNSRecordLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DNSRecordLogic) List(req *types.DNSRecordListReq, workspaceId string) (*types.DNSRecordListResp, error) {
+	recordModel := l.svcCtx.GetDNSRecordModel(workspaceId)
+
+	filter := bson.M{}
+	if req.Domain != "" {
+		filter["domain"] = bson.M{"$regex": regexp.QuoteMeta(strings.ToLower(req.Domain))}
+	}
+	if req.Root != "" {
+		filter["root"] = strings.ToLower(req.Root)
+	}
+	if req.Type != "" {
+		filter["type"] = strings.ToUpper(req.Type)
+	}
+	if req.Value != "" {
+		filter["value"] = bson.M{"$regex": regexp.QuoteMeta(req.Value), "$options": "i"}
+	}
+	if req.Source != "" {
+		filter["source"] = req.Source
+	}
+
+	total, err := recordModel.Count(l.ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	records, err := recordModel.FindWithSort(l.ctx, filter, req.Page, req.PageSize, "domain", 1)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]types.DNSRecord, 0, len(records))
+	for _, r := range records {
+		list = append(list, types.DNSRecord{
+			Id:         r.Id.Hex(),
+			Domain:     r.Domain,
+			Root:       r.Root,
+			Type:       r.Type,
+			Value:      r.Value,
+			TTL:        r.TTL,
+			Source:     r.Source,
+			CreateTime: r.CreateTime.Local().Format("2006-01-02 15:04:05"),
+			UpdateTime: r.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	return &types.DNSRecordListResp{Code: 0, Msg: "success", Total: int(total), List: list}, nil
+}
+
+func (l *DNSRecordLogic) Stat(workspaceId string) (*types.DNSRecordStatResp, error) {
+	stat, err := l.svcCtx.GetDNSRecordModel(workspaceId).StatByType(l.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &types.DNSRecordStatResp{Code: 0, Msg: "success", Stat: stat}, nil
+}
+
+func (l *DNSRecordLogic) Delete(req *types.DNSRecordDeleteReq, workspaceId string) (*types.BaseResp, error) {
+	if len(req.Ids) == 0 {
+		return &types.BaseResp{Code: 400, Msg: "ID列表不能为空"}, nil
+	}
+	if _, err := l.svcCtx.GetDNSRecordModel(workspaceId).BatchDeleteByIds(l.ctx, req.Ids); err != nil {
+		return nil, err
+	}
+	return &types.BaseResp{Code: 0, Msg: "success"}, nil
+}
+
+func (l *DNSRecordLogic) Clear(workspaceId string) (*types.BaseResp, error) {
+	if _, err := l.svcCtx.GetDNSRecordModel(workspaceId).DeleteMany(l.ctx, bson.M{}); err != nil {
+		return nil, err
+	}
+	return &types.BaseResp{Code: 0, Msg: "success"}, nil
+}
@@ -33,6 +33,16 @@ func (l *DomainLogic) DomainList(req *types.DomainListReq, workspaceId string) (
 		return resp, nil
 	}
 
+	// 语法查询
+	var queryFilter bson.M
+	if req.Query != "" {
+		q, err := buildAssetQuery(l.ctx, l.svcCtx, req.Query)
+		if err != nil {
+			return &types.DomainListResp{Code: 400, Msg: err.Error()}, nil
+		}
+		queryFilter = q
+	}
+
 	orgMap := common.LoadOrgMap(l.ctx, l.svcCtx)
 
 	// 用于去重和聚合域名
@@ -85,6 +95,9 @@ func (l *DomainLogic) DomainList(req *types.DomainListReq, workspaceId string) (
 		if req.OrgId != "" {
 			filter["org_id"] = req.OrgId
 		}
+		if len(queryFilter) > 0 {
+			filter = bson.M{"$and": []bson.M{filter, queryFilter}}
+		}
 
 		// 查询所有匹配的资产
 		assets, err := assetModel.Find(l.ctx, filter, 0, 0)
@@ -145,6 +158,9 @@ func (l *DomainLogic) DomainList(req *types.DomainListReq, workspaceId string) (
 					RootDomain: rootDomain,
 					IPs:        ips,
 					CName:      asset.CName,
+					CDNName:    asset.CDNName,
+					WAF:        asset.WAF,
+					CloudName:  asset.CloudName,
 					Source:     source,
 					OrgId:      asset.OrgId,
 					OrgName:    orgMap[asset.OrgId],
@@ -0,0 +1,177 @@
+package logic
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/geoip"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GeoIPLogic 离线IP库管理
+type GeoIPLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewGeoIPLogic(ctx context.Context, svcCtx *svc.ServiceContext) *GeoIPLogic {
+	return &GeoIPLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Info 数据库状态
+func (l *GeoIPLogic) Info() (*types.GeoIPInfoResp, error) {
+	resp := &types.GeoIPInfoResp{Code: 0, Msg: "success", List: []types.GeoIPDBStatus{}}
+	for _, st := range l.svcCtx.GeoIP.Status() {
+		item := types.GeoIPDBStatus{
+			Type:         st.Type,
+			File:         st.File,
+			Loaded:       st.Loaded,
+			Size:         st.Size,
+			DatabaseType: st.DatabaseType,
+			Error:        st.Error,
+		}
+		if !st.BuildTime.IsZero() {
+			item.BuildTime = st.BuildTime.Local().Format("2006-01-02 15:04:05")
+		}
+		if !st.UpdateTime.IsZero() {
+			item.UpdateTime = st.UpdateTime.Local().Format("2006-01-02 15:04:05")
+		}
+		resp.List = append(resp.List, item)
+	}
+	return resp, nil
+}
+
+// Upload 上传数据库文件
+func (l *GeoIPLogic) Upload(req *types.GeoIPUploadReq) (*types.BaseResp, error) {
+	if len(req.Data) == 0 {
+		return &types.BaseResp{Code: 400, Msg: "文件内容不能为空"}, nil
+	}
+	if err := l.svcCtx.GeoIP.Install(req.Type, req.Data); err != nil {
+		return &types.BaseResp{Code: 400, Msg: "数据库安装失败: " + err.Error()}, nil
+	}
+	l.Logger.Infof("[GeoIP] %s database uploaded, size=%d", req.Type, len(req.Data))
+	return &types.BaseResp{Code: 0, Msg: "数据库已更新"}, nil
+}
+
+// Update 从URL下载更新数据库
+func (l *GeoIPLogic) Update(req *types.GeoIPUpdateReq) (*types.BaseResp, error) {
+	if !strings.HasPrefix(req.Url, "http://") && !strings.HasPrefix(req.Url, "https://") {
+		return &types.BaseResp{Code: 400, Msg: "下载地址必须为http(s)链接"}, nil
+	}
+	if err := l.svcCtx.GeoIP.Download(l.ctx, req.Type, req.Url); err != nil {
+		return &types.BaseResp{Code: 400, Msg: "数据库更新失败: " + err.Error()}, nil
+	}
+	l.Logger.Infof("[GeoIP] %s database downloaded", req.Type)
+	return &types.BaseResp{Code: 0, Msg: "数据库已更新"}, nil
+}
+
+// Lookup 查询单个IP
+func (l *GeoIPLogic) Lookup(req *types.GeoIPLookupReq) (*types.GeoIPLookupResp, error) {
+	if net.ParseIP(strings.TrimSpace(req.IP)) == nil {
+		return &types.GeoIPLookupResp{Code: 400, Msg: "无效的IP地址"}, nil
+	}
+	info := l.svcCtx.GeoIP.Lookup(req.IP)
+	return &types.GeoIPLookupResp{
+		Code:     0,
+		Msg:      "success",
+		IP:       strings.TrimSpace(req.IP),
+		Location: info.Location(),
+		Country:  info.Country,
+		Region:   info.Region,
+		City:     info.City,
+		ASN:      info.ASN,
+		Org:      info.Org,
+		ISP:      info.ISP,
+	}, nil
+}
+
+// Enrich 为已有资产补全IP归属信息
+func (l *GeoIPLogic) Enrich(req *types.GeoIPEnrichReq, workspaceId string) (*types.GeoIPEnrichResp, error) {
+	if !l.svcCtx.GeoIP.Available() {
+		return &types.GeoIPEnrichResp{Code: 400, Msg: "未加载任何IP数据库，请先上传"}, nil
+	}
+
+	updated := 0
+	for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+		assetModel := model.NewAssetModel(l.svcCtx.MongoDB, wsId)
+		assets, err := assetModel.Find(l.ctx, bson.M{}, 0, 0)
+		if err != nil {
+			continue
+		}
+		for i := range assets {
+			if !enrichModelAssetIP(l.svcCtx.GeoIP, &assets[i], req.Force) {
+				continue
+			}
+			if err := assetModel.Update(l.ctx, assets[i].Id.Hex(), bson.M{"ip": assets[i].Ip}); err != nil {
+				l.Logger.Errorf("[GeoIP] update asset %s failed: %v", assets[i].Id.Hex(), err)
+				continue
+			}
+			updated++
+		}
+	}
+	return &types.GeoIPEnrichResp{Code: 0, Msg: fmt.Sprintf("已补全 %d 条资产", updated), Updated: updated}, nil
+}
+
+// enrichModelAssetIP 补全资产的IP归属，返回是否有变更
+// Host 为IP但没有IP记录的资产补充一条；force 为 false 时跳过已有国家或ASN信息的记录
+func enrichModelAssetIP(resolver *geoip.Resolver, asset *model.Asset, force bool) bool {
+	changed := false
+	if len(asset.Ip.IpV4) == 0 && len(asset.Ip.IpV6) == 0 {
+		if ip := net.ParseIP(asset.Host); ip != nil {
+			if v4 := ip.To4(); v4 != nil {
+				asset.Ip.IpV4 = append(asset.Ip.IpV4, model.IPV4{IPName: asset.Host, IPInt: binary.BigEndian.Uint32(v4)})
+			} else {
+				asset.Ip.IpV6 = append(asset.Ip.IpV6, model.IPV6{IPName: asset.Host})
+			}
+			changed = true
+		}
+	}
+
+	for i := range asset.Ip.IpV4 {
+		ip := &asset.Ip.IpV4[i]
+		if !force && (ip.Country != "" || ip.ASN != 0) {
+			continue
+		}
+		info := resolver.Lookup(ip.IPName)
+		if info.IsEmpty() {
+			continue
+		}
+		ip.Country, ip.Region, ip.City = info.Country, info.Region, info.City
+		ip.ASN, ip.Org, ip.ISP = info.ASN, info.Org, info.ISP
+		if loc := info.Location(); loc != "" {
+			ip.Location = loc
+		}
+		changed = true
+	}
+	for i := range asset.Ip.IpV6 {
+		ip := &asset.Ip.IpV6[i]
+		if !force && (ip.Country != "" || ip.ASN != 0) {
+			continue
+		}
+		info := resolver.Lookup(ip.IPName)
+		if info.IsEmpty() {
+			continue
+		}
+		ip.Country, ip.Region, ip.City = info.Country, info.Region, info.City
+		ip.ASN, ip.Org, ip.ISP = info.ASN, info.Org, info.ISP
+		if loc := info.Location(); loc != "" {
+			ip.Location = loc
+		}
+		changed = true
+	}
+	return changed
+}
@@ -0,0 +1,84 @@
+package logic
+
+import (
+	"context"
+	"net/url"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scanner"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// InteractshLogic OOB回连配置
+type InteractshLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewInteractshLogic(ctx context.Context, svcCtx *svc.ServiceContext) *InteractshLogic {
+	return &InteractshLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Get 获取配置，未配置时使用 nuclei 默认的公共服务
+func (l *InteractshLogic) Get() (*types.InteractshConfigGetResp, error) {
+	doc, err := model.NewInteractshConfigModel(l.svcCtx.MongoDB).Get(l.ctx)
+	if err == mongo.ErrNoDocuments {
+		return &types.InteractshConfigGetResp{Code: 0, Msg: "success", Data: &types.InteractshConfig{Mode: scanner.InteractshModePublic}}, nil
+	}
+	if err != nil {
+		return &types.InteractshConfigGetResp{Code: 500, Msg: "查询配置失败"}, nil
+	}
+	return &types.InteractshConfigGetResp{
+		Code: 0,
+		Msg:  "success",
+		Data: &types.InteractshConfig{
+			Mode:           doc.Mode,
+			ServerURL:      doc.ServerURL,
+			Token:          doc.Token,
+			PollInterval:   doc.PollInterval,
+			CooldownPeriod: doc.CooldownPeriod,
+			Eviction:       doc.Eviction,
+			UpdateTime:     doc.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+		},
+	}, nil
+}
+
+// Save 保存配置
+func (l *InteractshLogic) Save(req *types.InteractshConfig) (*types.BaseResp, error) {
+	switch req.Mode {
+	case scanner.InteractshModePublic, scanner.InteractshModeDisable:
+	case scanner.InteractshModeSelf:
+		u, err := url.Parse(req.ServerURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return &types.BaseResp{Code: 400, Msg: "自建服务地址格式错误，如 https://oast.example.com"}, nil
+		}
+	default:
+		return &types.BaseResp{Code: 400, Msg: "不支持的模式: " + req.Mode}, nil
+	}
+	if req.PollInterval < 0 || req.CooldownPeriod < 0 || req.Eviction < 0 {
+		return &types.BaseResp{Code: 400, Msg: "时间参数不能为负数"}, nil
+	}
+
+	err := model.NewInteractshConfigModel(l.svcCtx.MongoDB).Save(l.ctx, &model.InteractshConfig{
+		Mode:           req.Mode,
+		ServerURL:      req.ServerURL,
+		Token:          req.Token,
+		PollInterval:   req.PollInterval,
+		CooldownPeriod: req.CooldownPeriod,
+		Eviction:       req.Eviction,
+	})
+	if err != nil {
+		l.Logger.Errorf("[Interactsh] save config failed: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "保存配置失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功，新任务生效"}, nil
+}
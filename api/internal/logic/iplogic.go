@@ -9,6 +9,7 @@ import (
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
+	"cscan/pkg/geoip"
 
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -35,10 +36,22 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 		return resp, nil
 	}
 
+	// 语法查询
+	var queryFilter bson.M
+	if req.Query != "" {
+		q, err := buildAssetQuery(l.ctx, l.svcCtx, req.Query)
+		if err != nil {
+			return &types.IPListResp{Code: 400, Msg: err.Error()}, nil
+		}
+		queryFilter = q
+	}
+
 	orgMap := common.LoadOrgMap(l.ctx, l.svcCtx)
 
 	// 用于聚合IP信息
 	ipMap := make(map[string]*types.IPAsset)
+	// IP归属信息（ASN/ISP）
+	geoMap := make(map[string]model.IPV4)
 
 	for _, wsId := range workspaceIds {
 		assetModel := model.NewAssetModel(l.svcCtx.MongoDB, wsId)
@@ -78,6 +91,9 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 		if req.OrgId != "" {
 			conditions = append(conditions, bson.M{"org_id": req.OrgId})
 		}
+		if len(queryFilter) > 0 {
+			conditions = append(conditions, queryFilter)
+		}
 
 		if len(conditions) > 0 {
 			filter["$and"] = conditions
@@ -102,6 +118,9 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 					if location == "" && ipv4.Location != "" {
 						location = ipv4.Location
 					}
+					if ipv4.ASN != 0 || ipv4.ISP != "" {
+						geoMap[ipv4.IPName] = ipv4
+					}
 				}
 			}
 
@@ -207,6 +226,13 @@ func (l *IPLogic) IPList(req *types.IPListReq, workspaceId string) (*types.IPLis
 	// 转换为列表并排序
 	allIPs := make([]types.IPAsset, 0, len(ipMap))
 	for _, ip := range ipMap {
+		if geo, ok := geoMap[ip.IP]; ok {
+			ip.ASN = geoip.Info{ASN: geo.ASN, Org: geo.Org}.ASNName()
+			ip.ISP = geo.ISP
+			if geo.Location != "" {
+				ip.Location = geo.Location
+			}
+		}
 		allIPs = append(allIPs, *ip)
 	}
 
@@ -246,6 +272,10 @@ func (l *IPLogic) IPStat(workspaceId string) (*types.IPStatResp, error) {
 	portSet := make(map[int]bool)
 	serviceSet := make(map[string]bool)
 	newIPs := make(map[string]bool)
+	// 按IP去重统计ASN和国家
+	geoSeen := make(map[string]bool)
+	asnCount := make(map[string]int)
+	countryCount := make(map[string]int)
 
 	for _, wsId := range workspaceIds {
 		assetModel := model.NewAssetModel(l.svcCtx.MongoDB, wsId)
@@ -262,6 +292,15 @@ func (l *IPLogic) IPStat(workspaceId string) (*types.IPStatResp, error) {
 			for _, ipv4 := range asset.Ip.IpV4 {
 				if ipv4.IPName != "" {
 					ips = append(ips, ipv4.IPName)
+					if !geoSeen[ipv4.IPName] && (ipv4.ASN != 0 || ipv4.Country != "") {
+						geoSeen[ipv4.IPName] = true
+						if name := (geoip.Info{ASN: ipv4.ASN, Org: ipv4.Org}).ASNName(); name != "" {
+							asnCount[name]++
+						}
+						if ipv4.Country != "" {
+							countryCount[ipv4.Country]++
+						}
+					}
 				}
 			}
 			if common.IsIPAddress(asset.Host) && asset.Host != "" {
@@ -300,6 +339,8 @@ func (l *IPLogic) IPStat(workspaceId string) (*types.IPStatResp, error) {
 	resp.PortCount = len(portSet)
 	resp.ServiceCount = len(serviceSet)
 	resp.NewCount = len(newIPs)
+	resp.TopASN = sortMapToStatItems(asnCount, 10)
+	resp.TopCountry = sortMapToStatItems(countryCount, 10)
 
 	return resp, nil
 }
@@ -0,0 +1,233 @@
+package logic
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/notify"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// NotifyLogic 消息通知渠道管理
+type NotifyLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewNotifyLogic(ctx context.Context, svcCtx *svc.ServiceContext) *NotifyLogic {
+	return &NotifyLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// ChannelList 通知渠道列表（密钥脱敏）
+func (l *NotifyLogic) ChannelList() (*types.NotifyChannelListResp, error) {
+	docs, err := l.svcCtx.NotifyChannelModel.FindAll(l.ctx)
+	if err != nil {
+		return &types.NotifyChannelListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.NotifyChannel, 0, len(docs))
+	for _, doc := range docs {
+		item := types.NotifyChannel{
+			Id:         doc.Id.Hex(),
+			Name:       doc.Name,
+			Type:       doc.Type,
+			Events:     doc.Events,
+			Status:     doc.Status,
+			WebhookUrl: maskWebhookUrl(doc.WebhookUrl),
+			Headers:    maskHeaders(doc.Headers),
+			SmtpHost:   doc.SmtpHost,
+			SmtpPort:   doc.SmtpPort,
+			SmtpUser:   doc.SmtpUser,
+			SmtpFrom:   doc.SmtpFrom,
+			SmtpTo:     doc.SmtpTo,
+			SmtpSsl:    doc.SmtpSSL,
+			CreateTime: doc.CreateTime.Local().Format("2006-01-02 15:04:05"),
+			UpdateTime: doc.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+		}
+		if doc.Secret != "" {
+			item.Secret = maskKey(doc.Secret)
+		}
+		if doc.SmtpPass != "" {
+			item.SmtpPass = maskKey(doc.SmtpPass)
+		}
+		list = append(list, item)
+	}
+
+	return &types.NotifyChannelListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+// ChannelSave 新增或更新通知渠道
+func (l *NotifyLogic) ChannelSave(req *types.NotifyChannelSaveReq) (*types.BaseResp, error) {
+	if req.Name == "" {
+		return &types.BaseResp{Code: 400, Msg: "渠道名称不能为空"}, nil
+	}
+	for _, event := range req.Events {
+		if !isNotifyEvent(event) {
+			return &types.BaseResp{Code: 400, Msg: "不支持的事件: " + event}, nil
+		}
+	}
+
+	doc := &model.NotifyChannel{
+		Name:       req.Name,
+		Type:       req.Type,
+		Events:     req.Events,
+		Status:     req.Status,
+		WebhookUrl: strings.TrimSpace(req.WebhookUrl),
+		Secret:     req.Secret,
+		Headers:    req.Headers,
+		SmtpHost:   strings.TrimSpace(req.SmtpHost),
+		SmtpPort:   req.SmtpPort,
+		SmtpUser:   req.SmtpUser,
+		SmtpPass:   req.SmtpPass,
+		SmtpFrom:   req.SmtpFrom,
+		SmtpTo:     req.SmtpTo,
+		SmtpSSL:    req.SmtpSsl,
+	}
+	if doc.Events == nil {
+		doc.Events = []string{}
+	}
+
+	// 更新时，脱敏后的密钥表示未修改
+	if req.Id != "" {
+		existing, err := l.svcCtx.NotifyChannelModel.FindById(l.ctx, req.Id)
+		if err != nil {
+			return &types.BaseResp{Code: 400, Msg: "渠道不存在"}, nil
+		}
+		if strings.Contains(doc.Secret, "****") {
+			doc.Secret = existing.Secret
+		}
+		if strings.Contains(doc.SmtpPass, "****") {
+			doc.SmtpPass = existing.SmtpPass
+		}
+		if strings.Contains(doc.WebhookUrl, "****") {
+			doc.WebhookUrl = existing.WebhookUrl
+		}
+		for k, v := range doc.Headers {
+			if strings.Contains(v, "****") {
+				doc.Headers[k] = existing.Headers[k]
+			}
+		}
+	}
+
+	if err := notify.Validate(doc); err != nil {
+		return &types.BaseResp{Code: 400, Msg: "配置无效: " + err.Error()}, nil
+	}
+
+	if req.Id == "" {
+		if err := l.svcCtx.NotifyChannelModel.Insert(l.ctx, doc); err != nil {
+			return &types.BaseResp{Code: 500, Msg: "保存失败: " + err.Error()}, nil
+		}
+		return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+	}
+
+	update := bson.M{
+		"name":        doc.Name,
+		"type":        doc.Type,
+		"events":      doc.Events,
+		"webhook_url": doc.WebhookUrl,
+		"secret":      doc.Secret,
+		"headers":     doc.Headers,
+		"smtp_host":   doc.SmtpHost,
+		"smtp_port":   doc.SmtpPort,
+		"smtp_user":   doc.SmtpUser,
+		"smtp_pass":   doc.SmtpPass,
+		"smtp_from":   doc.SmtpFrom,
+		"smtp_to":     doc.SmtpTo,
+		"smtp_ssl":    doc.SmtpSSL,
+	}
+	if doc.Status != "" {
+		update["status"] = doc.Status
+	}
+	if err := l.svcCtx.NotifyChannelModel.Update(l.ctx, req.Id, update); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "保存失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+// ChannelDelete 删除通知渠道
+func (l *NotifyLogic) ChannelDelete(req *types.NotifyChannelIdReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.NotifyChannelModel.Delete(l.ctx, req.Id); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// ChannelTest 向渠道发送测试消息（同步发送，返回实际发送结果）
+func (l *NotifyLogic) ChannelTest(req *types.NotifyChannelTestReq) (*types.BaseResp, error) {
+	ch, err := l.svcCtx.NotifyChannelModel.FindById(l.ctx, req.Id)
+	if err != nil {
+		return &types.BaseResp{Code: 400, Msg: "渠道不存在"}, nil
+	}
+
+	if err := notify.Send(l.ctx, ch, notify.TestMessage(ch)); err != nil {
+		l.Logger.Errorf("ChannelTest: channel=%s, error=%v", ch.Name, err)
+		return &types.BaseResp{Code: 500, Msg: "发送失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "发送成功"}, nil
+}
+
+// isNotifyEvent 是否为支持的通知事件
+func isNotifyEvent(event string) bool {
+	switch event {
+	case model.NotifyEventTaskComplete, model.NotifyEventTaskFailed,
+		model.NotifyEventVulFound, model.NotifyEventWorkerOffline:
+		return true
+	}
+	return false
+}
+
+// maskWebhookUrl 对 Webhook 地址中的令牌脱敏
+// 钉钉、企业微信的令牌在查询参数中，飞书、Slack 的令牌在路径中
+func maskWebhookUrl(raw string) string {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		if raw == "" {
+			return ""
+		}
+		return maskKey(raw)
+	}
+	if _, ok := u.User.Password(); ok {
+		u.User = url.UserPassword(u.User.Username(), "****")
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, seg := range segments {
+		if len(seg) >= 16 {
+			segments[i] = maskKey(seg)
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+	query := u.Query()
+	for k, values := range query {
+		for i, v := range values {
+			values[i] = maskKey(v)
+		}
+		query[k] = values
+	}
+	u.RawQuery = query.Encode()
+	// 保持 **** 可读，便于保存时识别未修改的地址
+	return strings.ReplaceAll(u.String(), "%2A", "*")
+}
+
+// maskHeaders 对自定义请求头的值脱敏，常用于携带鉴权令牌
+func maskHeaders(headers map[string]string) map[string]string {
+	if headers == nil {
+		return nil
+	}
+	masked := make(map[string]string, len(headers))
+	for k, v := range headers {
+		masked[k] = maskKey(v)
+	}
+	return masked
+}
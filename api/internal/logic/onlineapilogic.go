@@ -4,12 +4,15 @@ import (
 	"fmt"
 	"context"
 	"strings"
+	"cscan/api/internal/logic/common"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
 	"cscan/onlineapi"
+	"cscan/scheduler"
 	"time"
 
+	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
@@ -125,9 +128,17 @@ func (l *OnlineAPILogic) Search(req *types.OnlineSearchReq, workspaceId string)
 
 func (l *OnlineAPILogic) Import(req *types.OnlineImportReq, workspaceId string) (*types.BaseResp, error) {
 	assetModel := l.svc.GetAssetModel(workspaceId)
+	scope, err := common.LoadScope(l.ctx, l.svc, workspaceId)
+	if err != nil {
+		return &types.BaseResp{Code: 500, Msg: "加载扫描范围失败"}, nil
+	}
 
-	count := 0
+	count, skipped := 0, 0
 	for _, a := range req.Assets {
+		if !onlineAssetInScope(scope, workspaceId, a) {
+			skipped++
+			continue
+		}
 		apps := parseApps(a.Product)
 		asset := &model.Asset{
 			Authority: a.Host,
@@ -143,7 +154,11 @@ func (l *OnlineAPILogic) Import(req *types.OnlineImportReq, workspaceId string)
 		}
 	}
 
-	return &types.BaseResp{Code: 0, Msg: fmt.Sprintf("成功导入%d条资产", count)}, nil
+	msg := fmt.Sprintf("成功导入%d条资产", count)
+	if skipped > 0 {
+		msg += fmt.Sprintf("，%d条不在扫描范围内已跳过", skipped)
+	}
+	return &types.BaseResp{Code: 0, Msg: msg}, nil
 }
 
 // ImportAll 导入全部资产（自动遍历所有页面）
@@ -156,6 +171,10 @@ func (l *OnlineAPILogic) ImportAll(req *types.OnlineImportAllReq, workspaceId st
 	}
 
 	assetModel := l.svc.GetAssetModel(workspaceId)
+	scope, err := common.LoadScope(l.ctx, l.svc, workspaceId)
+	if err != nil {
+		return &types.OnlineImportAllResp{Code: 500, Msg: "加载扫描范围失败"}, nil
+	}
 	pageSize := req.PageSize
 	if pageSize <= 0 {
 		pageSize = 100
@@ -174,6 +193,7 @@ func (l *OnlineAPILogic) ImportAll(req *types.OnlineImportAllReq, workspaceId st
 
 	totalFetched := 0
 	totalImport := 0
+	totalSkipped := 0
 	currentPage := 1
 
 PageLoop:
@@ -263,6 +283,10 @@ PageLoop:
 
 		// 导入当前页的资产
 		for _, a := range results {
+			if !onlineAssetInScope(scope, workspaceId, a) {
+				totalSkipped++
+				continue
+			}
 			apps := parseApps(a.Product)
 			asset := &model.Asset{
 				Authority: a.Host,
@@ -288,15 +312,31 @@ PageLoop:
 	}
 
 	totalPages := currentPage
+	msg := fmt.Sprintf("成功导入%d条资产（共获取%d条，%d页）", totalImport, totalFetched, totalPages)
+	if totalSkipped > 0 {
+		msg += fmt.Sprintf("，%d条不在扫描范围内已跳过", totalSkipped)
+	}
 	return &types.OnlineImportAllResp{
 		Code:         0,
-		Msg:          fmt.Sprintf("成功导入%d条资产（共获取%d条，%d页）", totalImport, totalFetched, totalPages),
+		Msg:          msg,
 		TotalFetched: totalFetched,
 		TotalImport:  totalImport,
 		TotalPages:   totalPages,
 	}, nil
 }
 
+// onlineAssetInScope 在线API资产的IP、端口和域名是否都在扫描范围内，超出范围的记录日志
+func onlineAssetInScope(scope *scheduler.Scope, workspaceId string, a types.OnlineSearchResult) bool {
+	ok, reason := scope.AllowAsset(a.IP, a.Port)
+	if ok && a.Domain != "" {
+		ok, reason = scope.AllowHost(a.Domain)
+	}
+	if !ok {
+		logx.Infof("[OnlineAPI] workspace %s skip out-of-scope asset %s %s:%d: %s", workspaceId, a.Domain, a.IP, a.Port, reason)
+	}
+	return ok
+}
+
 func (l *OnlineAPILogic) ConfigList(workspaceId string) (*types.APIConfigListResp, error) {
 	configModel := model.NewAPIConfigModel(l.svc.MongoDB, workspaceId)
 	docs, err := configModel.FindAll(l.ctx)
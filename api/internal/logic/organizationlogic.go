@@ -46,6 +46,7 @@ func (l *OrganizationListLogic) OrganizationList(req *types.PageReq) (resp *type
 			Name:        o.Name,
 			Description: o.Description,
 			Status:      o.Status,
+			Window:      toTypesWindow(o.Window),
 			CreateTime:  o.CreateTime.Local().Format("2006-01-02 15:04:05"),
 		})
 	}
@@ -74,6 +75,13 @@ func NewOrganizationSaveLogic(ctx context.Context, svcCtx *svc.ServiceContext) *
 }
 
 func (l *OrganizationSaveLogic) OrganizationSave(req *types.OrganizationSaveReq) (resp *types.BaseResp, err error) {
+	var window *model.MaintenanceWindow
+	if req.Window != nil {
+		if window, err = toModelWindow(req.Window); err != nil {
+			return &types.BaseResp{Code: 400, Msg: "维护窗口配置错误: " + err.Error()}, nil
+		}
+	}
+
 	if req.Id != "" {
 		// 更新
 		update := bson.M{
@@ -83,6 +91,9 @@ func (l *OrganizationSaveLogic) OrganizationSave(req *types.OrganizationSaveReq)
 		if req.Status != "" {
 			update["status"] = req.Status
 		}
+		if req.Window != nil {
+			update["window"] = window
+		}
 		err = l.svcCtx.OrganizationModel.Update(l.ctx, req.Id, update)
 		if err != nil {
 			return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
@@ -94,6 +105,7 @@ func (l *OrganizationSaveLogic) OrganizationSave(req *types.OrganizationSaveReq)
 	org := &model.Organization{
 		Name:        req.Name,
 		Description: req.Description,
+		Window:      window,
 	}
 	if err = l.svcCtx.OrganizationModel.Insert(l.ctx, org); err != nil {
 		return &types.BaseResp{Code: 500, Msg: "创建失败"}, nil
@@ -1,7 +1,6 @@
 package logic
 
 import (
-	"bytes"
 	"context"
 	"fmt"
 	"strconv"
@@ -10,8 +9,9 @@ import (
 
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/report"
 
-	"github.com/xuri/excelize/v2"
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -188,12 +188,13 @@ func NewReportExportLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Repo
 	}
 }
 
-func (l *ReportExportLogic) ReportExport(req *types.ReportExportReq, workspaceId string) ([]byte, string, error) {
+// ReportExport 按格式导出报告，返回文件内容、文件名和 Content-Type
+func (l *ReportExportLogic) ReportExport(req *types.ReportExportReq, workspaceId string) ([]byte, string, string, error) {
 	// 获取任务信息
 	taskModel := l.svcCtx.GetMainTaskModel(workspaceId)
 	task, err := taskModel.FindById(l.ctx, req.TaskId)
 	if err != nil {
-		return nil, "", fmt.Errorf("任务不存在")
+		return nil, "", "", fmt.Errorf("任务不存在")
 	}
 
 	// 资产保存时使用的是 task.Id.Hex() (ObjectID) 作为 taskId
@@ -223,88 +224,206 @@ func (l *ReportExportLogic) ReportExport(req *types.ReportExportReq, workspaceId
 	}
 	vuls, _ := vulModel.Find(l.ctx, vulFilter, 0, 0)
 
-	// 创建Excel文件
-	f := excelize.NewFile()
-	defer f.Close()
-
-	// 概览Sheet
-	f.SetSheetName("Sheet1", "概览")
-	f.SetCellValue("概览", "A1", "扫描报告")
-	f.SetCellValue("概览", "A3", "任务名称")
-	f.SetCellValue("概览", "B3", task.Name)
-	f.SetCellValue("概览", "A4", "扫描目标")
-	f.SetCellValue("概览", "B4", task.Target)
-	f.SetCellValue("概览", "A5", "任务状态")
-	f.SetCellValue("概览", "B5", task.Status)
-	f.SetCellValue("概览", "A6", "创建时间")
-	f.SetCellValue("概览", "B6", task.CreateTime.Local().Format("2006-01-02 15:04:05"))
-	f.SetCellValue("概览", "A7", "资产数量")
-	f.SetCellValue("概览", "B7", len(assets))
-	f.SetCellValue("概览", "A8", "漏洞数量")
-	f.SetCellValue("概览", "B8", len(vuls))
-
-	// 设置概览样式
-	titleStyle, _ := f.NewStyle(&excelize.Style{
-		Font:      &excelize.Font{Bold: true, Size: 16},
-		Alignment: &excelize.Alignment{Horizontal: "center"},
+	tpl, err := l.loadTemplate(req, workspaceId)
+	if err != nil {
+		return nil, "", "", err
+	}
+	renderer, err := report.NewRenderer(req.Format, tpl)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	reportAssets := make([]*report.Asset, 0, len(assets))
+	for _, a := range assets {
+		reportAssets = append(reportAssets, &report.Asset{
+			Authority:  a.Authority,
+			Host:       a.Host,
+			Port:       a.Port,
+			Service:    a.Service,
+			Title:      a.Title,
+			App:        a.App,
+			HttpStatus: a.HttpStatus,
+			Server:     a.Server,
+			IconHash:   a.IconHash,
+			CreateTime: a.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	findings := make([]*report.Finding, 0, len(vuls))
+	for _, v := range vuls {
+		findings = append(findings, &report.Finding{
+			Authority:        v.Authority,
+			Host:             v.Host,
+			Port:             v.Port,
+			Url:              v.Url,
+			PocFile:          v.PocFile,
+			Source:           v.Source,
+			Severity:         v.Severity,
+			Result:           v.Result,
+			CveId:            v.CveId,
+			CweId:            v.CweId,
+			CvssScore:        v.CvssScore,
+			Remediation:      v.Remediation,
+			References:       v.References,
+			MatcherName:      v.MatcherName,
+			ExtractedResults: v.ExtractedResults,
+			Request:          v.Request,
+			Response:         v.Response,
+			CurlCommand:      v.CurlCommand,
+			CreateTime:       v.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+
+	r := report.New(report.Meta{
+		TaskId:     req.TaskId,
+		TaskName:   task.Name,
+		Target:     task.Target,
+		Status:     task.Status,
+		CreateTime: task.CreateTime.Local().Format("2006-01-02 15:04:05"),
+	}, reportAssets, findings)
+
+	data, err := renderer.Render(r)
+	if err != nil {
+		return nil, "", "", err
+	}
+
+	filename := fmt.Sprintf("report_%s_%s.%s", task.Name, time.Now().Format("20060102150405"), renderer.Extension())
+	return data, filename, renderer.ContentType(), nil
+}
+
+// loadTemplate 获取导出使用的模板内容，返回空字符串表示使用内置模板
+func (l *ReportExportLogic) loadTemplate(req *types.ReportExportReq, workspaceId string) (string, error) {
+	if !report.IsTemplateFormat(req.Format) {
+		return "", nil
+	}
+	format := templateFormat(req.Format)
+	templateModel := l.svcCtx.GetReportTemplateModel(workspaceId)
+
+	if req.TemplateId != "" {
+		tpl, err := templateModel.FindById(l.ctx, req.TemplateId)
+		if err != nil {
+			return "", fmt.Errorf("报告模板不存在")
+		}
+		if tpl.Format != format {
+			return "", fmt.Errorf("模板格式(%s)与导出格式(%s)不匹配", tpl.Format, req.Format)
+		}
+		return tpl.Content, nil
+	}
+
+	tpl, err := templateModel.FindDefault(l.ctx, format)
+	if err != nil {
+		return "", nil
+	}
+	return tpl.Content, nil
+}
+
+// templateFormat 模板存储格式，PDF 与 HTML 共用 HTML 模板
+func templateFormat(format string) string {
+	format = report.NormalizeFormat(format)
+	if format == report.FormatPDF {
+		return report.FormatHTML
+	}
+	return format
+}
+
+// ReportTemplateLogic 报告模板管理
+type ReportTemplateLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewReportTemplateLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ReportTemplateLogic {
+	return &ReportTemplateLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// TemplateList 模板列表
+func (l *ReportTemplateLogic) TemplateList(workspaceId string) (*types.ReportTemplateListResp, error) {
+	docs, err := l.svcCtx.GetReportTemplateModel(workspaceId).FindAll(l.ctx)
+	if err != nil {
+		return &types.ReportTemplateListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.ReportTemplate, 0, len(docs))
+	for _, d := range docs {
+		list = append(list, types.ReportTemplate{
+			Id:          d.Id.Hex(),
+			Name:        d.Name,
+			Format:      d.Format,
+			Content:     d.Content,
+			Description: d.Description,
+			IsDefault:   d.IsDefault,
+			CreateTime:  d.CreateTime.Local().Format("2006-01-02 15:04:05"),
+			UpdateTime:  d.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	return &types.ReportTemplateListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+// TemplateSave 保存模板（Id为空时新增）
+func (l *ReportTemplateLogic) TemplateSave(req *types.ReportTemplateSaveReq, workspaceId string) (*types.BaseResp, error) {
+	if req.Name == "" || req.Content == "" {
+		return &types.BaseResp{Code: 400, Msg: "名称和模板内容不能为空"}, nil
+	}
+	format := templateFormat(req.Format)
+	if format != report.FormatHTML && format != report.FormatMarkdown {
+		return &types.BaseResp{Code: 400, Msg: "模板格式只支持 html 和 markdown"}, nil
+	}
+	if err := report.ValidateTemplate(format, req.Content); err != nil {
+		return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+	}
+
+	templateModel := l.svcCtx.GetReportTemplateModel(workspaceId)
+	// 同一格式只保留一个默认模板
+	if req.IsDefault {
+		if err := templateModel.ClearDefault(l.ctx, format); err != nil {
+			l.Logger.Errorf("TemplateSave: clear default failed: %v", err)
+		}
+	}
+
+	if req.Id == "" {
+		doc := &model.ReportTemplate{
+			Name:        req.Name,
+			Format:      format,
+			Content:     req.Content,
+			Description: req.Description,
+			IsDefault:   req.IsDefault,
+		}
+		if err := templateModel.Insert(l.ctx, doc); err != nil {
+			return &types.BaseResp{Code: 500, Msg: "保存失败: " + err.Error()}, nil
+		}
+		return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+	}
+
+	err := templateModel.Update(l.ctx, req.Id, bson.M{
+		"name":        req.Name,
+		"format":      format,
+		"content":     req.Content,
+		"description": req.Description,
+		"is_default":  req.IsDefault,
 	})
-	f.SetCellStyle("概览", "A1", "A1", titleStyle)
-	f.MergeCell("概览", "A1", "B1")
-
-	// 资产Sheet
-	f.NewSheet("资产列表")
-	assetHeaders := []string{"地址", "主机", "端口", "服务", "标题", "应用", "状态码", "Server", "IconHash", "发现时间"}
-	for i, h := range assetHeaders {
-		cell := fmt.Sprintf("%c1", 'A'+i)
-		f.SetCellValue("资产列表", cell, h)
-	}
-	for i, a := range assets {
-		row := i + 2
-		f.SetCellValue("资产列表", fmt.Sprintf("A%d", row), a.Authority)
-		f.SetCellValue("资产列表", fmt.Sprintf("B%d", row), a.Host)
-		f.SetCellValue("资产列表", fmt.Sprintf("C%d", row), a.Port)
-		f.SetCellValue("资产列表", fmt.Sprintf("D%d", row), a.Service)
-		f.SetCellValue("资产列表", fmt.Sprintf("E%d", row), a.Title)
-		f.SetCellValue("资产列表", fmt.Sprintf("F%d", row), strings.Join(a.App, ", "))
-		f.SetCellValue("资产列表", fmt.Sprintf("G%d", row), a.HttpStatus)
-		f.SetCellValue("资产列表", fmt.Sprintf("H%d", row), a.Server)
-		f.SetCellValue("资产列表", fmt.Sprintf("I%d", row), a.IconHash)
-		f.SetCellValue("资产列表", fmt.Sprintf("J%d", row), a.CreateTime.Local().Format("2006-01-02 15:04:05"))
-	}
-
-	// 漏洞Sheet
-	f.NewSheet("漏洞列表")
-	vulHeaders := []string{"地址", "URL", "POC", "严重级别", "结果", "发现时间"}
-	for i, h := range vulHeaders {
-		cell := fmt.Sprintf("%c1", 'A'+i)
-		f.SetCellValue("漏洞列表", cell, h)
-	}
-	for i, v := range vuls {
-		row := i + 2
-		f.SetCellValue("漏洞列表", fmt.Sprintf("A%d", row), v.Authority)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("B%d", row), v.Url)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("C%d", row), v.PocFile)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("D%d", row), v.Severity)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("E%d", row), v.Result)
-		f.SetCellValue("漏洞列表", fmt.Sprintf("F%d", row), v.CreateTime.Local().Format("2006-01-02 15:04:05"))
-	}
-
-	// 设置列宽
-	f.SetColWidth("资产列表", "A", "A", 30)
-	f.SetColWidth("资产列表", "B", "B", 15)
-	f.SetColWidth("资产列表", "E", "E", 40)
-	f.SetColWidth("资产列表", "F", "F", 30)
-	f.SetColWidth("漏洞列表", "A", "A", 30)
-	f.SetColWidth("漏洞列表", "B", "B", 50)
-	f.SetColWidth("漏洞列表", "C", "C", 40)
-	f.SetColWidth("漏洞列表", "E", "E", 50)
-
-	// 写入buffer
-	var buf bytes.Buffer
-	if err := f.Write(&buf); err != nil {
-		return nil, "", err
-	}
-
-	filename := fmt.Sprintf("report_%s_%s.xlsx", task.Name, time.Now().Format("20060102150405"))
-	return buf.Bytes(), filename, nil
+	if err != nil {
+		return &types.BaseResp{Code: 500, Msg: "保存失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+// TemplateDelete 删除模板
+func (l *ReportTemplateLogic) TemplateDelete(req *types.ReportTemplateIdReq, workspaceId string) (*types.BaseResp, error) {
+	if err := l.svcCtx.GetReportTemplateModel(workspaceId).Delete(l.ctx, req.Id); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// TemplateDefault 获取内置模板内容，作为自定义模板的起点
+func (l *ReportTemplateLogic) TemplateDefault(req *types.ReportTemplateDefaultReq) (*types.ReportTemplateDefaultResp, error) {
+	content := report.DefaultTemplate(req.Format)
+	if content == "" {
+		return &types.ReportTemplateDefaultResp{Code: 400, Msg: "该格式不支持自定义模板"}, nil
+	}
+	return &types.ReportTemplateDefaultResp{Code: 0, Msg: "success", Content: content}, nil
 }
@@ -0,0 +1,229 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/blob"
+	"cscan/pkg/imagehash"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// maxGalleryAssets 画廊单次聚类的资产上限（按更新时间取最新）
+const maxGalleryAssets = 20000
+
+// ScreenshotLogic 截图存储与相似度聚类
+type ScreenshotLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewScreenshotLogic(ctx context.Context, svcCtx *svc.ServiceContext) *ScreenshotLogic {
+	return &ScreenshotLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// Get 读取截图内容
+func (l *ScreenshotLogic) Get(key string) ([]byte, string, error) {
+	if !blob.IsKey(key) {
+		return nil, "", blob.ErrNotFound
+	}
+	data, err := l.svcCtx.Screenshots.Get(l.ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, blob.ContentType(key), nil
+}
+
+// hashedAssets 查询已计算感知哈希的截图资产
+func (l *ScreenshotLogic) hashedAssets(workspaceId string) []types.ScreenshotAsset {
+	list := make([]types.ScreenshotAsset, 0)
+	for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+		assets, err := l.svcCtx.GetAssetModel(wsId).FindScreenshots(l.ctx, bson.M{
+			"screenshot_hash": bson.M{"$exists": true, "$ne": ""},
+		})
+		if err != nil {
+			l.Logger.Errorf("[Screenshot] query workspace %s failed: %v", wsId, err)
+			continue
+		}
+		for _, a := range assets {
+			list = append(list, toScreenshotAsset(wsId, &a))
+		}
+	}
+	// 多个工作空间合并后按更新时间排序再截断
+	sort.SliceStable(list, func(i, j int) bool { return list[i].UpdateTime > list[j].UpdateTime })
+	if len(list) > maxGalleryAssets {
+		list = list[:maxGalleryAssets]
+	}
+	return list
+}
+
+func toScreenshotAsset(workspaceId string, a *model.Asset) types.ScreenshotAsset {
+	return types.ScreenshotAsset{
+		Id:             a.Id.Hex(),
+		WorkspaceId:    workspaceId,
+		Authority:      a.Authority,
+		Host:           a.Host,
+		Port:           a.Port,
+		Title:          a.Title,
+		HttpStatus:     a.HttpStatus,
+		App:            a.App,
+		Screenshot:     a.Screenshot,
+		ScreenshotHash: a.ScreenshotHash,
+		UpdateTime:     a.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+}
+
+func normalizeThreshold(t int) int {
+	if t < 0 || t > 64 {
+		return 8
+	}
+	return t
+}
+
+// Gallery 按截图相似度对资产聚类
+func (l *ScreenshotLogic) Gallery(req *types.ScreenshotGalleryReq, workspaceId string) (*types.ScreenshotGalleryResp, error) {
+	assets := l.hashedAssets(workspaceId)
+	hashes := make([]string, len(assets))
+	for i, a := range assets {
+		hashes[i] = a.ScreenshotHash
+	}
+
+	clusters := make([]types.ScreenshotCluster, 0)
+	for _, idx := range imagehash.Cluster(hashes, normalizeThreshold(req.Threshold)) {
+		if len(idx) < req.MinCount {
+			continue
+		}
+		rep := assets[idx[0]]
+		c := types.ScreenshotCluster{Hash: rep.ScreenshotHash, Count: len(idx), Representative: rep}
+		for i, n := range idx {
+			if req.MaxAssets > 0 && i >= req.MaxAssets {
+				break
+			}
+			c.Assets = append(c.Assets, assets[n])
+		}
+		clusters = append(clusters, c)
+	}
+
+	resp := &types.ScreenshotGalleryResp{Code: 0, Msg: "success", Total: len(clusters), Count: len(assets)}
+	resp.List = paginate(clusters, req.Page, req.PageSize)
+	return resp, nil
+}
+
+// Similar 查询与指定截图相似的资产，按相似度排序
+func (l *ScreenshotLogic) Similar(req *types.ScreenshotSimilarReq, workspaceId string) (*types.ScreenshotSimilarResp, error) {
+	hash := req.Hash
+	if hash == "" && req.AssetId != "" {
+		for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+			if asset, err := l.svcCtx.GetAssetModel(wsId).FindById(l.ctx, req.AssetId); err == nil {
+				hash = asset.ScreenshotHash
+				break
+			}
+		}
+	}
+	if imagehash.Distance(hash, hash) < 0 {
+		return &types.ScreenshotSimilarResp{Code: 400, Msg: "截图哈希无效或资产没有截图"}, nil
+	}
+
+	threshold := normalizeThreshold(req.Threshold)
+	type scored struct {
+		asset    types.ScreenshotAsset
+		distance int
+	}
+	var matched []scored
+	for _, a := range l.hashedAssets(workspaceId) {
+		if d := imagehash.Distance(hash, a.ScreenshotHash); d >= 0 && d <= threshold {
+			matched = append(matched, scored{a, d})
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].distance < matched[j].distance })
+
+	list := make([]types.ScreenshotAsset, 0, len(matched))
+	for _, m := range matched {
+		list = append(list, m.asset)
+	}
+	return &types.ScreenshotSimilarResp{
+		Code:  0,
+		Msg:   "success",
+		Total: len(list),
+		List:  paginate(list, req.Page, req.PageSize),
+	}, nil
+}
+
+// Migrate 将资产中内联的 base64 截图转存到对象存储，并为缺少哈希的截图补算感知哈希
+func (l *ScreenshotLogic) Migrate(workspaceId string) (*types.ScreenshotMigrateResp, error) {
+	migrated, failed := 0, 0
+	for _, wsId := range common.GetWorkspaceIds(l.ctx, l.svcCtx, workspaceId) {
+		assetModel := l.svcCtx.GetAssetModel(wsId)
+		assets, err := assetModel.FindScreenshots(l.ctx, bson.M{
+			"screenshot":      bson.M{"$exists": true, "$ne": ""},
+			"screenshot_hash": bson.M{"$in": bson.A{nil, ""}},
+		})
+		if err != nil {
+			l.Logger.Errorf("[Screenshot] query workspace %s failed: %v", wsId, err)
+			continue
+		}
+		for _, a := range assets {
+			key, hash, err := l.migrateOne(a.Screenshot)
+			if err != nil {
+				l.Logger.Errorf("[Screenshot] migrate asset %s failed: %v", a.Id.Hex(), err)
+				failed++
+				continue
+			}
+			if err := assetModel.Update(l.ctx, a.Id.Hex(), bson.M{"screenshot": key, "screenshot_hash": hash}); err != nil {
+				failed++
+				continue
+			}
+			migrated++
+		}
+	}
+	return &types.ScreenshotMigrateResp{
+		Code:     0,
+		Msg:      fmt.Sprintf("已迁移 %d 条截图，失败 %d 条", migrated, failed),
+		Migrated: migrated,
+		Failed:   failed,
+	}, nil
+}
+
+func (l *ScreenshotLogic) migrateOne(screenshot string) (string, string, error) {
+	if !blob.IsKey(screenshot) {
+		return common.StoreScreenshot(l.ctx, l.svcCtx.Screenshots, screenshot)
+	}
+	// 已在对象存储中，只补算哈希
+	data, err := l.svcCtx.Screenshots.Get(l.ctx, screenshot)
+	if err != nil {
+		return "", "", err
+	}
+	hash, err := imagehash.PHash(data)
+	return screenshot, hash, err
+}
+
+// paginate 内存分页
+func paginate[T any](list []T, page, pageSize int) []T {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize < 1 {
+		pageSize = 20
+	}
+	start := (page - 1) * pageSize
+	if start >= len(list) {
+		return []T{}
+	}
+	end := start + pageSize
+	if end > len(list) {
+		end = len(list)
+	}
+	return list[start:end]
+}
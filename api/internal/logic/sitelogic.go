@@ -35,6 +35,16 @@ func (l *SiteLogic) SiteList(req *types.SiteListReq, workspaceId string) (*types
 		return resp, nil
 	}
 
+	// 语法查询
+	var queryFilter bson.M
+	if req.Query != "" {
+		q, err := buildAssetQuery(l.ctx, l.svcCtx, req.Query)
+		if err != nil {
+			return &types.SiteListResp{Code: 400, Msg: err.Error()}, nil
+		}
+		queryFilter = q
+	}
+
 	orgMap := common.LoadOrgMap(l.ctx, l.svcCtx)
 
 	var allSites []types.Site
@@ -79,6 +89,9 @@ func (l *SiteLogic) SiteList(req *types.SiteListReq, workspaceId string) (*types
 		if req.OrgId != "" {
 			conditions = append(conditions, bson.M{"org_id": req.OrgId})
 		}
+		if len(queryFilter) > 0 {
+			conditions = append(conditions, queryFilter)
+		}
 
 		if len(conditions) > 1 {
 			filter["$and"] = conditions
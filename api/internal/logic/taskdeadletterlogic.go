@@ -0,0 +1,178 @@
+package logic
+
+import (
+	"context"
+	"fmt"
+
+	"cscan/api/internal/middleware"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scheduler"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// DeadLetterListLogic 死信任务列表
+type DeadLetterListLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeadLetterListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeadLetterListLogic {
+	return &DeadLetterListLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeadLetterListLogic) DeadLetterList(workspaceId string) (resp *types.DeadLetterListResp, err error) {
+	deadLetters, err := l.svcCtx.Scheduler.ListDeadLetters(l.ctx)
+	if err != nil {
+		l.Logger.Errorf("DeadLetterList: %v", err)
+		return &types.DeadLetterListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.DeadLetterTask, 0, len(deadLetters))
+	for _, dl := range deadLetters {
+		if !deadLetterVisible(l.ctx, dl, workspaceId) {
+			continue
+		}
+		list = append(list, types.DeadLetterTask{
+			TaskId:      dl.Task.TaskId,
+			MainTaskId:  dl.Task.MainTaskId,
+			WorkspaceId: dl.Task.WorkspaceId,
+			TaskName:    dl.Task.TaskName,
+			Worker:      dl.Worker,
+			Attempts:    dl.Attempts,
+			Reason:      dl.Reason,
+			DeadTime:    dl.DeadTime,
+		})
+	}
+
+	return &types.DeadLetterListResp{
+		Code:  0,
+		Msg:   "success",
+		Total: len(list),
+		List:  list,
+	}, nil
+}
+
+// DeadLetterReplayLogic 重放死信任务
+type DeadLetterReplayLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeadLetterReplayLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeadLetterReplayLogic {
+	return &DeadLetterReplayLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeadLetterReplayLogic) DeadLetterReplay(req *types.DeadLetterReq, workspaceId string) (resp *types.BaseResp, err error) {
+	if len(req.TaskIds) == 0 {
+		return &types.BaseResp{Code: 400, Msg: "请选择要重放的任务"}, nil
+	}
+
+	replayed := 0
+	for _, taskId := range req.TaskIds {
+		dl, err := l.svcCtx.Scheduler.GetDeadLetter(l.ctx, taskId)
+		if err != nil || !deadLetterVisible(l.ctx, dl, workspaceId) {
+			continue
+		}
+		task, err := l.svcCtx.Scheduler.ReplayDeadLetter(l.ctx, taskId)
+		if err != nil {
+			l.Logger.Errorf("DeadLetterReplay: taskId=%s, error=%v", taskId, err)
+			continue
+		}
+		replayed++
+
+		// 主任务因死信被标记为失败时，恢复为等待状态，Worker 领取后会更新为 STARTED
+		if task.MainTaskId != "" {
+			taskModel := l.svcCtx.GetMainTaskModel(task.WorkspaceId)
+			mainTask, err := taskModel.FindById(l.ctx, task.MainTaskId)
+			if err == nil && mainTask.Status == model.TaskStatusFailure {
+				taskModel.Update(l.ctx, task.MainTaskId, bson.M{
+					"status": model.TaskStatusPending,
+					"result": "",
+				})
+			}
+		}
+	}
+
+	if replayed == 0 {
+		return &types.BaseResp{Code: 404, Msg: "重放失败，任务不存在"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: fmt.Sprintf("成功重放 %d 个任务", replayed)}, nil
+}
+
+// DeadLetterDeleteLogic 删除死信任务
+type DeadLetterDeleteLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewDeadLetterDeleteLogic(ctx context.Context, svcCtx *svc.ServiceContext) *DeadLetterDeleteLogic {
+	return &DeadLetterDeleteLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *DeadLetterDeleteLogic) DeadLetterDelete(req *types.DeadLetterReq, workspaceId string) (resp *types.BaseResp, err error) {
+	if len(req.TaskIds) == 0 {
+		return &types.BaseResp{Code: 400, Msg: "请选择要删除的任务"}, nil
+	}
+
+	taskIds := make([]string, 0, len(req.TaskIds))
+	for _, taskId := range req.TaskIds {
+		dl, err := l.svcCtx.Scheduler.GetDeadLetter(l.ctx, taskId)
+		if err == nil && deadLetterVisible(l.ctx, dl, workspaceId) {
+			taskIds = append(taskIds, taskId)
+		}
+	}
+	if len(taskIds) == 0 {
+		return &types.BaseResp{Code: 404, Msg: "删除失败，任务不存在"}, nil
+	}
+
+	deleted, err := l.svcCtx.Scheduler.DeleteDeadLetter(l.ctx, taskIds...)
+	if err != nil {
+		l.Logger.Errorf("DeadLetterDelete: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: fmt.Sprintf("成功删除 %d 个任务", deleted)}, nil
+}
+
+// deadLetterVisible 死信任务是否属于当前工作空间。
+// 空值和 all 表示全部工作空间，非超级管理员只能看到所属工作空间的死信任务
+func deadLetterVisible(ctx context.Context, dl *scheduler.DeadLetter, workspaceId string) bool {
+	if dl == nil {
+		return false
+	}
+	taskWs := dl.Task.WorkspaceId
+	if taskWs == "" {
+		taskWs = "default"
+	}
+	if workspaceId != "" && workspaceId != "all" {
+		return taskWs == workspaceId
+	}
+	allowed := middleware.GetAllowedWorkspaces(ctx)
+	if allowed == nil {
+		return true
+	}
+	for _, id := range allowed {
+		if id == taskWs {
+			return true
+		}
+	}
+	return false
+}
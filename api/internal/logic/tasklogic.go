@@ -13,6 +13,7 @@ import (
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
+	"cscan/pkg/webhook"
 	"cscan/scheduler"
 
 	"github.com/google/uuid"
@@ -55,10 +56,9 @@ func (l *MainTaskListLogic) MainTaskList(req *types.MainTaskListReq, workspaceId
 
 	// 如果 workspaceId 为空，查询所有工作空间
 	if workspaceId == "" {
-		workspaces, _ := l.svcCtx.WorkspaceModel.Find(l.ctx, bson.M{}, 1, 100)
+		workspaceIds := common.GetWorkspaceIds(l.ctx, l.svcCtx, "")
 		
-		for _, ws := range workspaces {
-			wsId := ws.Id.Hex()
+		for _, wsId := range workspaceIds {
 			taskModel := l.svcCtx.GetMainTaskModel(wsId)
 			wsTotal, _ := taskModel.Count(l.ctx, filter)
 			total += wsTotal
@@ -187,6 +187,9 @@ func (l *MainTaskListLogic) MainTaskList(req *types.MainTaskListReq, workspaceId
 			SubTaskCount: t.SubTaskCount,
 			SubTaskDone:  subTaskDone,
 			WorkspaceId:  tw.workspaceId,
+			NotifyId:     t.NotifyId,
+			DiffSummary:  toDiffSummary(t.DiffSummary),
+			WindowPaused: t.WindowPaused,
 		})
 	}
 
@@ -214,9 +217,9 @@ func NewMainTaskCreateLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Ma
 
 func (l *MainTaskCreateLogic) MainTaskCreate(req *types.MainTaskCreateReq, workspaceId string) (resp *types.BaseRespWithId, err error) {
 	// 优先使用请求体中的 workspaceId
-	wsId := req.WorkspaceId
-	if wsId == "" {
-		wsId = workspaceId
+	wsId, ok := common.ResolveWorkspaceId(l.ctx, req.WorkspaceId, workspaceId)
+	if !ok {
+		return &types.BaseRespWithId{Code: 403, Msg: "无权访问该工作空间"}, nil
 	}
 	if wsId == "" {
 		return &types.BaseRespWithId{Code: 400, Msg: "workspaceId不能为空"}, nil
@@ -232,6 +235,12 @@ func (l *MainTaskCreateLogic) MainTaskCreate(req *types.MainTaskCreateReq, works
 	if validationErrors := common.ValidateTargets(req.Target); len(validationErrors) > 0 {
 		return &types.BaseRespWithId{Code: 400, Msg: common.FormatValidationErrors(validationErrors)}, nil
 	}
+	if msg := common.CheckTargetScope(l.ctx, l.svcCtx, wsId, req.Target); msg != "" {
+		return &types.BaseRespWithId{Code: 400, Msg: msg}, nil
+	}
+	if err := common.ValidateTaskConfig(req.Config); err != nil {
+		return &types.BaseRespWithId{Code: 400, Msg: err.Error()}, nil
+	}
 
 	taskModel := l.svcCtx.GetMainTaskModel(wsId)
 
@@ -292,6 +301,7 @@ func (l *MainTaskCreateLogic) MainTaskCreate(req *types.MainTaskCreateReq, works
 		OrgId:       req.OrgId,
 		IsCron:      req.IsCron,
 		CronRule:    req.CronRule,
+		NotifyId:    req.NotifyId,
 		Config:      string(configBytes),
 		Status:      model.TaskStatusCreated, // 设置初始状态
 	}
@@ -359,6 +369,10 @@ func NewTaskProfileSaveLogic(ctx context.Context, svcCtx *svc.ServiceContext) *T
 }
 
 func (l *TaskProfileSaveLogic) TaskProfileSave(req *types.TaskProfileSaveReq) (resp *types.BaseResp, err error) {
+	if err := common.ValidateTaskConfig(req.Config); err != nil {
+		return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+	}
+
 	profile := &model.TaskProfile{
 		Name:        req.Name,
 		Description: req.Description,
@@ -552,6 +566,24 @@ func (l *MainTaskRetryLogic) MainTaskRetry(req *types.MainTaskRetryReq, workspac
 
 	// 创建新任务（而不是复用旧任务）
 	newTaskId := uuid.New().String()
+
+	// 从配置中获取批次大小，默认50
+	// batchSize = 0 表示不拆分，使用一个很大的值
+	batchSize := 50
+	if bs, ok := taskConfig["batchSize"].(float64); ok {
+		if bs == 0 {
+			batchSize = 1000000 // 不拆分，使用一个很大的值
+		} else if bs > 0 {
+			batchSize = int(bs)
+		}
+	}
+
+	// 按工作空间扫描范围过滤并拆分目标
+	batches, err := common.SplitTargetsInScope(l.ctx, l.svcCtx, workspaceId, newTaskId, oldTask.Target, batchSize)
+	if err != nil {
+		return &types.BaseRespWithId{Code: 400, Msg: err.Error()}, nil
+	}
+
 	newTask := &model.MainTask{
 		TaskId:      newTaskId,
 		Name:        oldTask.Name + " (重试)",
@@ -559,6 +591,7 @@ func (l *MainTaskRetryLogic) MainTaskRetry(req *types.MainTaskRetryReq, workspac
 		ProfileId:   oldTask.ProfileId,
 		ProfileName: oldTask.ProfileName,
 		OrgId:       oldTask.OrgId,
+		NotifyId:    oldTask.NotifyId,
 		Config:      string(configBytes),
 		Status:      model.TaskStatusCreated, // 设置初始状态
 	}
@@ -568,25 +601,14 @@ func (l *MainTaskRetryLogic) MainTaskRetry(req *types.MainTaskRetryReq, workspac
 		return &types.BaseRespWithId{Code: 500, Msg: "创建新任务失败: " + err.Error()}, nil
 	}
 
-	// 从配置中获取批次大小，默认50
-	// batchSize = 0 表示不拆分，使用一个很大的值
-	batchSize := 50
-	if bs, ok := taskConfig["batchSize"].(float64); ok {
-		if bs == 0 {
-			batchSize = 1000000 // 不拆分，使用一个很大的值
-		} else if bs > 0 {
-			batchSize = int(bs)
-		}
-	}
-
-	// 使用目标拆分器判断是否需要拆分
-	splitter := scheduler.NewTargetSplitter(batchSize)
-	batches := splitter.SplitTargets(oldTask.Target)
 
 	// 解析任务配置，计算启用的扫描模块数量
 	config, _ := scheduler.ParseTaskConfig(string(configBytes))
 	enabledModules := 0
-	if config != nil {
+	if config != nil && config.Pipeline.Enabled() {
+		// 流水线的每个阶段对应一个子任务
+		enabledModules = len(config.Pipeline.Stages)
+	} else if config != nil {
 		if config.DomainScan != nil && config.DomainScan.Enable {
 			enabledModules++
 		}
@@ -602,6 +624,12 @@ func (l *MainTaskRetryLogic) MainTaskRetry(req *types.MainTaskRetryReq, workspac
 		if config.PocScan != nil && config.PocScan.Enable {
 			enabledModules++
 		}
+		if config.Brute != nil && config.Brute.Enable {
+			enabledModules++
+		}
+		if config.Crawler != nil && config.Crawler.Enable {
+			enabledModules++
+		}
 	}
 	if enabledModules == 0 {
 		enabledModules = 1 // 至少有一个模块
@@ -621,6 +649,7 @@ func (l *MainTaskRetryLogic) MainTaskRetry(req *types.MainTaskRetryReq, workspac
 		"sub_task_done":  0,
 		"start_time":     now,
 	})
+	l.svcCtx.Webhook.Emit(webhook.TaskStatusEvent(workspaceId, newTask, model.TaskStatusStarted, ""))
 
 	// 保存主任务信息到 Redis
 	taskInfoKey := "cscan:task:info:" + newTaskId
@@ -643,6 +672,9 @@ func (l *MainTaskRetryLogic) MainTaskRetry(req *types.MainTaskRetryReq, workspac
 		}
 	}
 
+	// 子任务需要满足的 Worker 标签选择器
+	requires := scheduler.TaskRequires(string(configBytes))
+
 	// 为每个批次创建子任务并推送到队列
 	for i, batch := range batches {
 		// 复制配置并替换目标
@@ -669,6 +701,7 @@ func (l *MainTaskRetryLogic) MainTaskRetry(req *types.MainTaskRetryReq, workspac
 			Config:      string(subConfigBytes),
 			Priority:    1,
 			Workers:     workers,
+			Requires:    requires,
 		}
 
 		l.Logger.Infof("Pushing retry sub-task %d/%d: taskId=%s, targets=%d", i+1, len(batches), subTaskId, len(strings.Split(batch, "\n")))
@@ -717,9 +750,9 @@ func (l *MainTaskStartLogic) MainTaskStart(req *types.MainTaskControlReq, worksp
 		req.Id, req.WorkspaceId, workspaceId)
 	
 	// 优先使用请求中的 workspaceId
-	wsId := req.WorkspaceId
-	if wsId == "" {
-		wsId = workspaceId
+	wsId, ok := common.ResolveWorkspaceId(l.ctx, req.WorkspaceId, workspaceId)
+	if !ok {
+		return &types.BaseResp{Code: 403, Msg: "无权访问该工作空间"}, nil
 	}
 	if wsId == "" {
 		l.Logger.Errorf("MainTaskStart: workspaceId is empty")
@@ -769,14 +802,19 @@ func (l *MainTaskStartLogic) MainTaskStart(req *types.MainTaskControlReq, worksp
 		}
 	}
 
-	// 使用目标拆分器判断是否需要拆分
-	splitter := scheduler.NewTargetSplitter(batchSize)
-	batches := splitter.SplitTargets(target)
+	// 按工作空间扫描范围过滤并拆分目标
+	batches, err := common.SplitTargetsInScope(l.ctx, l.svcCtx, wsId, task.TaskId, target, batchSize)
+	if err != nil {
+		return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+	}
 
 	// 解析任务配置，计算启用的扫描模块数量
 	config, _ := scheduler.ParseTaskConfig(task.Config)
 	enabledModules := 0
-	if config != nil {
+	if config != nil && config.Pipeline.Enabled() {
+		// 流水线的每个阶段对应一个子任务
+		enabledModules = len(config.Pipeline.Stages)
+	} else if config != nil {
 		if config.DomainScan != nil && config.DomainScan.Enable {
 			enabledModules++
 		}
@@ -792,6 +830,12 @@ func (l *MainTaskStartLogic) MainTaskStart(req *types.MainTaskControlReq, worksp
 		if config.PocScan != nil && config.PocScan.Enable {
 			enabledModules++
 		}
+		if config.Brute != nil && config.Brute.Enable {
+			enabledModules++
+		}
+		if config.Crawler != nil && config.Crawler.Enable {
+			enabledModules++
+		}
 	}
 	if enabledModules == 0 {
 		enabledModules = 1 // 至少有一个模块
@@ -821,6 +865,7 @@ func (l *MainTaskStartLogic) MainTaskStart(req *types.MainTaskControlReq, worksp
 	}
 	fmt.Printf("[MainTaskStart] SUCCESS: task %s updated, matchedCount=%d, modifiedCount=%d\n", req.Id, result.MatchedCount, result.ModifiedCount)
 	l.Logger.Infof("MainTaskStart: task %s status updated, matchedCount=%d, modifiedCount=%d", req.Id, result.MatchedCount, result.ModifiedCount)
+	l.svcCtx.Webhook.Emit(webhook.TaskStatusEvent(wsId, task, model.TaskStatusStarted, ""))
 
 	// 保存主任务信息到 Redis
 	taskInfoKey := "cscan:task:info:" + task.TaskId
@@ -843,6 +888,9 @@ func (l *MainTaskStartLogic) MainTaskStart(req *types.MainTaskControlReq, worksp
 		}
 	}
 
+	// 子任务需要满足的 Worker 标签选择器
+	requires := scheduler.TaskRequires(task.Config)
+
 	// 批量创建子任务
 	var schedTasks []*scheduler.TaskInfo
 	for i, batch := range batches {
@@ -870,6 +918,7 @@ func (l *MainTaskStartLogic) MainTaskStart(req *types.MainTaskControlReq, worksp
 			Config:      string(subConfigBytes),
 			Priority:    1,
 			Workers:     workers,
+			Requires:    requires,
 		}
 		schedTasks = append(schedTasks, schedTask)
 
@@ -913,9 +962,9 @@ func NewMainTaskPauseLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Mai
 
 func (l *MainTaskPauseLogic) MainTaskPause(req *types.MainTaskControlReq, workspaceId string) (resp *types.BaseResp, err error) {
 	// 优先使用请求中的 workspaceId
-	wsId := req.WorkspaceId
-	if wsId == "" {
-		wsId = workspaceId
+	wsId, ok := common.ResolveWorkspaceId(l.ctx, req.WorkspaceId, workspaceId)
+	if !ok {
+		return &types.BaseResp{Code: 403, Msg: "无权访问该工作空间"}, nil
 	}
 	l.Logger.Infof("MainTaskPause: received request, id=%s, reqWorkspaceId=%s, headerWorkspaceId=%s, using=%s", 
 		req.Id, req.WorkspaceId, workspaceId, wsId)
@@ -969,6 +1018,7 @@ func (l *MainTaskPauseLogic) MainTaskPause(req *types.MainTaskControlReq, worksp
 	if err := taskModel.Update(l.ctx, req.Id, update); err != nil {
 		return &types.BaseResp{Code: 500, Msg: "更新任务状态失败"}, nil
 	}
+	l.svcCtx.Webhook.Emit(webhook.TaskStatusEvent(wsId, task, model.TaskStatusPaused, ""))
 
 	l.Logger.Infof("Task paused: taskId=%s, subTaskCount=%d", task.TaskId, task.SubTaskCount)
 	return &types.BaseResp{Code: 0, Msg: "任务已暂停"}, nil
@@ -991,9 +1041,9 @@ func NewMainTaskResumeLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Ma
 
 func (l *MainTaskResumeLogic) MainTaskResume(req *types.MainTaskControlReq, workspaceId string) (resp *types.BaseResp, err error) {
 	// 优先使用请求中的 workspaceId
-	wsId := req.WorkspaceId
-	if wsId == "" {
-		wsId = workspaceId
+	wsId, ok := common.ResolveWorkspaceId(l.ctx, req.WorkspaceId, workspaceId)
+	if !ok {
+		return &types.BaseResp{Code: 403, Msg: "无权访问该工作空间"}, nil
 	}
 	if wsId == "" {
 		return &types.BaseResp{Code: 400, Msg: "workspaceId不能为空"}, nil
@@ -1018,6 +1068,23 @@ func (l *MainTaskResumeLogic) MainTaskResume(req *types.MainTaskControlReq, work
 		return &types.BaseResp{Code: 400, Msg: "只有已暂停的任务可以继续"}, nil
 	}
 
+	// 维护窗口关闭时不立即继续，由维护窗口检查在窗口打开后自动继续
+	windows, err := common.LoadWindows(l.ctx, l.svcCtx, wsId, task.OrgId)
+	if err != nil {
+		l.Logger.Errorf("MainTaskResume: load maintenance windows failed: %v", err)
+		return &types.BaseResp{Code: 500, Msg: "查询维护窗口失败"}, nil
+	}
+	if now := time.Now(); !windows.Open(now) {
+		next := windows.NextOpen(now)
+		if next.IsZero() {
+			return &types.BaseResp{Code: 400, Msg: "当前处于维护窗口之外，且窗口不会再打开"}, nil
+		}
+		if err := taskModel.Update(l.ctx, req.Id, bson.M{"window_paused": true}); err != nil {
+			return &types.BaseResp{Code: 500, Msg: "更新任务状态失败"}, nil
+		}
+		return &types.BaseResp{Code: 0, Msg: "当前处于维护窗口之外，任务将于 " + next.Local().Format("2006-01-02 15:04:05") + " 自动继续"}, nil
+	}
+
 	// 清除暂停信号
 	ctrlKey := "cscan:task:ctrl:" + task.TaskId
 	l.svcCtx.RedisClient.Del(l.ctx, ctrlKey)
@@ -1033,12 +1100,13 @@ func (l *MainTaskResumeLogic) MainTaskResume(req *types.MainTaskControlReq, work
 	}
 
 	// 更新状态为STARTED
-	update := bson.M{"status": model.TaskStatusStarted}
+	update := bson.M{"status": model.TaskStatusStarted, "window_paused": false}
 	if err := taskModel.Update(l.ctx, req.Id, update); err != nil {
 		l.Logger.Errorf("MainTaskResume: failed to update status, error=%v", err)
 		return &types.BaseResp{Code: 500, Msg: "更新任务状态失败"}, nil
 	}
 	l.Logger.Infof("MainTaskResume: status updated to STARTED")
+	l.svcCtx.Webhook.Emit(webhook.TaskStatusEvent(wsId, task, model.TaskStatusStarted, ""))
 
 	// 解析任务配置
 	var taskConfig map[string]interface{}
@@ -1070,15 +1138,21 @@ func (l *MainTaskResumeLogic) MainTaskResume(req *types.MainTaskControlReq, work
 		}
 	}
 
-	// 使用目标拆分器判断是否需要拆分
-	splitter := scheduler.NewTargetSplitter(batchSize)
-	batches := splitter.SplitTargets(target)
+	// 按工作空间扫描范围过滤并拆分目标，全部被过滤时恢复为暂停状态
+	batches, err := common.SplitTargetsInScope(l.ctx, l.svcCtx, wsId, task.TaskId, target, batchSize)
+	if err != nil {
+		taskModel.Update(l.ctx, req.Id, bson.M{"status": model.TaskStatusPaused})
+		return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+	}
 
 	// 如果任务有保存的状态，注入到配置中
 	if task.TaskState != "" {
 		taskConfig["resumeState"] = task.TaskState
 	}
 
+	// 子任务需要满足的 Worker 标签选择器，按阶段分布式执行时已完成的阶段组由 Worker 直接交接
+	requires := scheduler.TaskRequires(task.Config)
+
 	// 重新推送所有子任务到队列（从已完成的位置继续）
 	// 注意：这里简化处理，重新推送所有批次，Worker 会根据 resumeState 跳过已完成的阶段
 	var schedTasks []*scheduler.TaskInfo
@@ -1107,6 +1181,7 @@ func (l *MainTaskResumeLogic) MainTaskResume(req *types.MainTaskControlReq, work
 			Config:      string(subConfigBytes),
 			Priority:    1,
 			Workers:     workers,
+			Requires:    requires,
 		}
 		schedTasks = append(schedTasks, schedTask)
 
@@ -1151,9 +1226,9 @@ func NewMainTaskStopLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Main
 
 func (l *MainTaskStopLogic) MainTaskStop(req *types.MainTaskControlReq, workspaceId string) (resp *types.BaseResp, err error) {
 	// 优先使用请求中的 workspaceId
-	wsId := req.WorkspaceId
-	if wsId == "" {
-		wsId = workspaceId
+	wsId, ok := common.ResolveWorkspaceId(l.ctx, req.WorkspaceId, workspaceId)
+	if !ok {
+		return &types.BaseResp{Code: 403, Msg: "无权访问该工作空间"}, nil
 	}
 	if wsId == "" {
 		return &types.BaseResp{Code: 400, Msg: "workspaceId不能为空"}, nil
@@ -1204,6 +1279,7 @@ func (l *MainTaskStopLogic) MainTaskStop(req *types.MainTaskControlReq, workspac
 	if err := taskModel.Update(l.ctx, req.Id, update); err != nil {
 		return &types.BaseResp{Code: 500, Msg: "更新任务状态失败"}, nil
 	}
+	l.svcCtx.Webhook.Emit(webhook.TaskStatusEvent(wsId, task, model.TaskStatusStopped, "任务已手动停止"))
 
 	l.Logger.Infof("Task stopped: taskId=%s", task.TaskId)
 	return &types.BaseResp{Code: 0, Msg: "任务已停止"}, nil
@@ -1305,6 +1381,9 @@ func (l *MainTaskUpdateLogic) MainTaskUpdate(req *types.MainTaskUpdateReq, works
 		if validationErrors := common.ValidateTargets(req.Target); len(validationErrors) > 0 {
 			return &types.BaseResp{Code: 400, Msg: common.FormatValidationErrors(validationErrors)}, nil
 		}
+		if msg := common.CheckTargetScope(l.ctx, l.svcCtx, workspaceId, req.Target); msg != "" {
+			return &types.BaseResp{Code: 400, Msg: msg}, nil
+		}
 	}
 
 	// 获取任务
@@ -45,8 +45,10 @@ func (l *UserListLogic) UserList(req *types.PageReq) (resp *types.UserListResp,
 	for _, u := range users {
 		list = append(list, types.UserInfo{
 			Id:       u.Id.Hex(),
-			Username: u.Username,
-			Status:   u.Status,
+			Username:     u.Username,
+			Status:       u.Status,
+			Role:         u.GetRole(),
+			WorkspaceIds: u.WorkspaceIds,
 		})
 	}
 
@@ -84,11 +86,21 @@ func (l *UserCreateLogic) UserCreate(req *types.UserCreateReq) (resp *types.Base
 		return &types.BaseResp{Code: 400, Msg: "用户名已存在"}, nil
 	}
 
+	role := req.Role
+	if role == "" {
+		role = model.RoleOperator
+	}
+	if !model.IsValidRole(role) {
+		return &types.BaseResp{Code: 400, Msg: "无效的角色"}, nil
+	}
+
 	// 创建用户
 	user := &model.User{
-		Username: req.Username,
-		Password: req.Password, // 在model层会自动MD5加密
-		Status:   req.Status,
+		Username:     req.Username,
+		Password:     req.Password, // 在model层会自动MD5加密
+		Status:       req.Status,
+		Role:         role,
+		WorkspaceIds: req.WorkspaceIds,
 	}
 
 	err = l.svcCtx.UserModel.Insert(l.ctx, user)
@@ -144,6 +156,19 @@ func (l *UserUpdateLogic) UserUpdate(req *types.UserUpdateReq) (resp *types.Base
 		"status":   req.Status,
 		"update_time": time.Now(),
 	}
+	if req.Role != "" {
+		if !model.IsValidRole(req.Role) {
+			return &types.BaseResp{Code: 400, Msg: "无效的角色"}, nil
+		}
+		// admin 账号始终为超级管理员，避免系统失去管理员
+		if user.Username == "admin" && req.Role != model.RoleSuperAdmin {
+			return &types.BaseResp{Code: 400, Msg: "admin 账号不允许修改角色"}, nil
+		}
+		updateData["role"] = req.Role
+	}
+	if req.WorkspaceIds != nil {
+		updateData["workspace_ids"] = req.WorkspaceIds
+	}
 
 	err = l.svcCtx.UserModel.UpdateById(l.ctx, req.Id, updateData)
 	if err != nil {
@@ -192,6 +217,11 @@ func (l *UserDeleteLogic) UserDelete(req *types.UserDeleteReq) (resp *types.Base
 		return &types.BaseResp{Code: 500, Msg: "删除用户失败"}, nil
 	}
 
+	// 同时吊销该用户的 API Token
+	if _, err := l.svcCtx.ApiTokenModel.DeleteByUser(l.ctx, req.Id); err != nil {
+		logx.Errorf("删除用户Token失败: %v", err)
+	}
+
 	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
 }
 
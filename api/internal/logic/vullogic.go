@@ -2,11 +2,15 @@ package logic
 
 import (
 	"context"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/pkg/query"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
@@ -47,6 +51,26 @@ func (l *VulListLogic) VulList(req *types.VulListReq, workspaceId string) (resp
 	if req.Port > 0 {
 		filter["port"] = req.Port
 	}
+	if req.Status != "" {
+		statusFilter, ok := vulStatusQuery(req.Status)
+		if !ok {
+			return &types.VulListResp{Code: 400, Msg: "无效的处置状态"}, nil
+		}
+		filter["status"] = statusFilter
+	}
+	if req.Assignee != "" {
+		filter["assignee"] = req.Assignee
+	}
+	// 语法查询
+	if req.Query != "" {
+		q, err := query.Build(req.Query, query.VulSchema())
+		if err != nil {
+			return &types.VulListResp{Code: 400, Msg: err.Error()}, nil
+		}
+		if len(q) > 0 {
+			filter["$and"] = []bson.M{q}
+		}
+	}
 
 	// 查询总数
 	total, err := vulModel.Count(l.ctx, filter)
@@ -73,6 +97,8 @@ func (l *VulListLogic) VulList(req *types.VulListReq, workspaceId string) (resp
 			Result:     v.Result,
 			CreateTime: v.CreateTime.Local().Format("2006-01-02 15:04:05"),
 			ScanCount:  v.ScanCount,
+			Status:     vulStatus(v.Status),
+			Assignee:   v.Assignee,
 		}
 		// 新增字段 - 时间追踪 
 		if !v.FirstSeenTime.IsZero() {
@@ -92,6 +118,39 @@ func (l *VulListLogic) VulList(req *types.VulListReq, workspaceId string) (resp
 	}, nil
 }
 
+// vulStatus 未设置状态的历史数据视为 new
+func vulStatus(status string) string {
+	if status == "" {
+		return model.VulStatusNew
+	}
+	return status
+}
+
+// vulStatusQuery 构建处置状态查询条件，支持逗号分隔的多个状态
+func vulStatusQuery(status string) (interface{}, bool) {
+	var values []interface{}
+	for _, st := range strings.Split(status, ",") {
+		st = strings.TrimSpace(st)
+		if st == "" {
+			continue
+		}
+		if !model.IsValidVulStatus(st) {
+			return nil, false
+		}
+		values = append(values, st)
+		if st == model.VulStatusNew {
+			values = append(values, nil)
+		}
+	}
+	switch len(values) {
+	case 0:
+		return nil, false
+	case 1:
+		return values[0], true
+	}
+	return bson.M{"$in": values}, true
+}
+
 
 // VulLogic 漏洞管理逻辑
 type VulLogic struct {
@@ -134,6 +193,166 @@ func (l *VulLogic) VulClear(workspaceId string) (resp *types.BaseResp, err error
 	return &types.BaseResp{Code: 0, Msg: "成功清空 " + strconv.FormatInt(deleted, 10) + " 条漏洞"}, nil
 }
 
+// VulStatusUpdate 批量变更处置状态，标记误报时可同时创建抑制规则
+func (l *VulLogic) VulStatusUpdate(req *types.VulStatusUpdateReq, workspaceId, operator string) (resp *types.BaseResp, err error) {
+	if len(req.Ids) == 0 {
+		return &types.BaseResp{Code: 400, Msg: "请选择漏洞"}, nil
+	}
+	if !model.IsValidVulStatus(req.Status) {
+		return &types.BaseResp{Code: 400, Msg: "无效的处置状态"}, nil
+	}
+
+	vulModel := l.svcCtx.GetVulModel(workspaceId)
+	changed, err := vulModel.UpdateStatus(l.ctx, req.Ids, req.Status, operator, req.Reason)
+	if err != nil {
+		return &types.BaseResp{Code: 500, Msg: "更新失败: " + err.Error()}, nil
+	}
+
+	if req.Suppress && req.Status == model.VulStatusFalsePositive {
+		ruleModel := l.svcCtx.GetVulSuppressRuleModel(workspaceId)
+		for _, id := range req.Ids {
+			vul, err := vulModel.FindById(l.ctx, id)
+			if err != nil {
+				continue
+			}
+			if exists, _ := ruleModel.ExistsHostRule(l.ctx, vul.PocFile, vul.Host); exists {
+				continue
+			}
+			rule := &model.VulSuppressRule{
+				PocFile:    vul.PocFile,
+				Host:       vul.Host,
+				Reason:     req.Reason,
+				CreateUser: operator,
+			}
+			if err := ruleModel.Insert(l.ctx, rule); err != nil {
+				l.Logger.Errorf("VulStatusUpdate: create suppress rule failed: %v", err)
+			}
+		}
+	}
+	return &types.BaseResp{Code: 0, Msg: "成功更新 " + strconv.FormatInt(changed, 10) + " 条漏洞"}, nil
+}
+
+// VulAssign 批量指派处理人
+func (l *VulLogic) VulAssign(req *types.VulAssignReq, workspaceId string) (resp *types.BaseResp, err error) {
+	if len(req.Ids) == 0 {
+		return &types.BaseResp{Code: 400, Msg: "请选择漏洞"}, nil
+	}
+	updated, err := l.svcCtx.GetVulModel(workspaceId).UpdateAssignee(l.ctx, req.Ids, req.Assignee)
+	if err != nil {
+		return &types.BaseResp{Code: 500, Msg: "指派失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "成功指派 " + strconv.FormatInt(updated, 10) + " 条漏洞"}, nil
+}
+
+// VulComment 添加评论
+func (l *VulLogic) VulComment(req *types.VulCommentReq, workspaceId, author string) (resp *types.BaseResp, err error) {
+	content := strings.TrimSpace(req.Content)
+	if req.Id == "" || content == "" {
+		return &types.BaseResp{Code: 400, Msg: "评论内容不能为空"}, nil
+	}
+	comment := model.VulComment{Author: author, Content: content}
+	if err := l.svcCtx.GetVulModel(workspaceId).AddComment(l.ctx, req.Id, comment); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "评论失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "评论成功"}, nil
+}
+
+// VulSuppressLogic 漏洞误报抑制规则
+type VulSuppressLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewVulSuppressLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VulSuppressLogic {
+	return &VulSuppressLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+func (l *VulSuppressLogic) RuleList(workspaceId string) (resp *types.VulSuppressRuleListResp, err error) {
+	rules, err := l.svcCtx.GetVulSuppressRuleModel(workspaceId).FindAll(l.ctx)
+	if err != nil {
+		return &types.VulSuppressRuleListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.VulSuppressRule, 0, len(rules))
+	for _, r := range rules {
+		item := types.VulSuppressRule{
+			Id:         r.Id.Hex(),
+			PocFile:    r.PocFile,
+			Host:       r.Host,
+			Pattern:    r.Pattern,
+			Reason:     r.Reason,
+			Status:     r.Status,
+			HitCount:   r.HitCount,
+			CreateUser: r.CreateUser,
+			CreateTime: r.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		}
+		if !r.LastHit.IsZero() {
+			item.LastHit = r.LastHit.Local().Format("2006-01-02 15:04:05")
+		}
+		list = append(list, item)
+	}
+	return &types.VulSuppressRuleListResp{Code: 0, Msg: "success", List: list}, nil
+}
+
+func (l *VulSuppressLogic) RuleSave(req *types.VulSuppressRuleSaveReq, workspaceId, operator string) (resp *types.BaseResp, err error) {
+	if req.PocFile == "" {
+		return &types.BaseResp{Code: 400, Msg: "POC模板不能为空"}, nil
+	}
+	if req.Host == "" && req.Pattern == "" {
+		return &types.BaseResp{Code: 400, Msg: "主机和匹配规则不能同时为空"}, nil
+	}
+	if req.Pattern != "" {
+		if _, err := regexp.Compile(req.Pattern); err != nil {
+			return &types.BaseResp{Code: 400, Msg: "匹配规则不是有效的正则表达式: " + err.Error()}, nil
+		}
+	}
+	if req.Status != "" && req.Status != "enable" && req.Status != "disable" {
+		return &types.BaseResp{Code: 400, Msg: "无效的状态"}, nil
+	}
+
+	ruleModel := l.svcCtx.GetVulSuppressRuleModel(workspaceId)
+	if req.Id == "" {
+		rule := &model.VulSuppressRule{
+			PocFile:    req.PocFile,
+			Host:       req.Host,
+			Pattern:    req.Pattern,
+			Reason:     req.Reason,
+			Status:     req.Status,
+			CreateUser: operator,
+		}
+		if err := ruleModel.Insert(l.ctx, rule); err != nil {
+			return &types.BaseResp{Code: 500, Msg: "保存失败: " + err.Error()}, nil
+		}
+		return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+	}
+
+	update := bson.M{
+		"pocfile": req.PocFile,
+		"host":    req.Host,
+		"pattern": req.Pattern,
+		"reason":  req.Reason,
+	}
+	if req.Status != "" {
+		update["status"] = req.Status
+	}
+	if err := ruleModel.Update(l.ctx, req.Id, update); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "保存失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+func (l *VulSuppressLogic) RuleDelete(req *types.VulSuppressRuleDeleteReq, workspaceId string) (resp *types.BaseResp, err error) {
+	if err := l.svcCtx.GetVulSuppressRuleModel(workspaceId).Delete(l.ctx, req.Id); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "删除失败: " + err.Error()}, nil
+	}
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
 
 // VulStatLogic 漏洞统计逻辑
 type VulStatLogic struct {
@@ -150,26 +369,46 @@ func NewVulStatLogic(ctx context.Context, svcCtx *svc.ServiceContext) *VulStatLo
 	}
 }
 
-func (l *VulStatLogic) VulStat(workspaceId string) (resp *types.VulStatResp, err error) {
+func (l *VulStatLogic) VulStat(req *types.VulStatReq, workspaceId string) (resp *types.VulStatResp, err error) {
 	vulModel := l.svcCtx.GetVulModel(workspaceId)
 
+	// 按处置状态过滤
+	withStatus := func(filter bson.M) bson.M { return filter }
+	if req.Status != "" {
+		statusFilter, ok := vulStatusQuery(req.Status)
+		if !ok {
+			return &types.VulStatResp{Code: 400, Msg: "无效的处置状态"}, nil
+		}
+		withStatus = func(filter bson.M) bson.M {
+			filter["status"] = statusFilter
+			return filter
+		}
+	}
+
 	// 统计总数
-	total, _ := vulModel.Count(l.ctx, bson.M{})
+	total, _ := vulModel.Count(l.ctx, withStatus(bson.M{}))
 
 	// 按严重级别统计
-	critical, _ := vulModel.Count(l.ctx, bson.M{"severity": "critical"})
-	high, _ := vulModel.Count(l.ctx, bson.M{"severity": "high"})
-	medium, _ := vulModel.Count(l.ctx, bson.M{"severity": "medium"})
-	low, _ := vulModel.Count(l.ctx, bson.M{"severity": "low"})
-	info, _ := vulModel.Count(l.ctx, bson.M{"severity": "info"})
+	critical, _ := vulModel.Count(l.ctx, withStatus(bson.M{"severity": "critical"}))
+	high, _ := vulModel.Count(l.ctx, withStatus(bson.M{"severity": "high"}))
+	medium, _ := vulModel.Count(l.ctx, withStatus(bson.M{"severity": "medium"}))
+	low, _ := vulModel.Count(l.ctx, withStatus(bson.M{"severity": "low"}))
+	info, _ := vulModel.Count(l.ctx, withStatus(bson.M{"severity": "info"}))
 
 	// 近7天和近30天统计
 	now := time.Now()
 	weekAgo := now.AddDate(0, 0, -7)
 	monthAgo := now.AddDate(0, 0, -30)
 
-	week, _ := vulModel.Count(l.ctx, bson.M{"create_time": bson.M{"$gte": weekAgo}})
-	month, _ := vulModel.Count(l.ctx, bson.M{"create_time": bson.M{"$gte": monthAgo}})
+	week, _ := vulModel.Count(l.ctx, withStatus(bson.M{"create_time": bson.M{"$gte": weekAgo}}))
+	month, _ := vulModel.Count(l.ctx, withStatus(bson.M{"create_time": bson.M{"$gte": monthAgo}}))
+
+	// 各处置状态数量
+	statusCount := make(map[string]int, len(model.VulStatuses))
+	for _, status := range model.VulStatuses {
+		count, _ := vulModel.Count(l.ctx, bson.M{"status": model.VulStatusFilter(status)})
+		statusCount[status] = int(count)
+	}
 
 	return &types.VulStatResp{
 		Code:     0,
@@ -182,6 +421,7 @@ func (l *VulStatLogic) VulStat(workspaceId string) (resp *types.VulStatResp, err
 		Info:     int(info),
 		Week:     int(week),
 		Month:    int(month),
+		StatusCount: statusCount,
 	}, nil
 }
 
@@ -231,6 +471,27 @@ func (l *VulDetailLogic) VulDetail(req *types.VulDetailReq, workspaceId string)
 		References:  vul.References,
 		// 时间追踪 
 		ScanCount: vul.ScanCount,
+		// 处置流程
+		Status:        vulStatus(vul.Status),
+		Assignee:      vul.Assignee,
+		Comments:      make([]types.VulComment, 0, len(vul.Comments)),
+		StatusHistory: make([]types.VulStatusChange, 0, len(vul.StatusHistory)),
+	}
+	for _, c := range vul.Comments {
+		detail.Comments = append(detail.Comments, types.VulComment{
+			Author:     c.Author,
+			Content:    c.Content,
+			CreateTime: c.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		})
+	}
+	for _, h := range vul.StatusHistory {
+		detail.StatusHistory = append(detail.StatusHistory, types.VulStatusChange{
+			From:     h.From,
+			To:       h.To,
+			Operator: h.Operator,
+			Reason:   h.Reason,
+			Time:     h.Time.Local().Format("2006-01-02 15:04:05"),
+		})
 	}
 
 	// 时间追踪字段
@@ -242,7 +503,7 @@ func (l *VulDetailLogic) VulDetail(req *types.VulDetailReq, workspaceId string)
 	}
 
 	// 证据链 
-	if vul.MatcherName != "" || len(vul.ExtractedResults) > 0 || vul.CurlCommand != "" || vul.Request != "" || vul.Response != "" {
+	if vul.MatcherName != "" || len(vul.ExtractedResults) > 0 || vul.CurlCommand != "" || vul.Request != "" || vul.Response != "" || vul.Interaction != nil {
 		detail.Evidence = &types.VulEvidence{
 			MatcherName:       vul.MatcherName,
 			ExtractedResults:  vul.ExtractedResults,
@@ -251,6 +512,19 @@ func (l *VulDetailLogic) VulDetail(req *types.VulDetailReq, workspaceId string)
 			Response:          vul.Response,
 			ResponseTruncated: vul.ResponseTruncated,
 		}
+		if i := vul.Interaction; i != nil {
+			detail.Evidence.Interaction = &types.VulInteraction{
+				Protocol:      i.Protocol,
+				UniqueId:      i.UniqueId,
+				FullId:        i.FullId,
+				QType:         i.QType,
+				RawRequest:    i.RawRequest,
+				RawResponse:   i.RawResponse,
+				RemoteAddress: i.RemoteAddress,
+				SMTPFrom:      i.SMTPFrom,
+				Time:          i.Timestamp.Local().Format("2006-01-02 15:04:05"),
+			}
+		}
 	}
 
 	return &types.VulDetailResp{
@@ -0,0 +1,220 @@
+package logic
+
+import (
+	"context"
+	"net/url"
+	"strings"
+
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// WebhookLogic Webhook事件订阅和投递记录管理
+type WebhookLogic struct {
+	logx.Logger
+	ctx    context.Context
+	svcCtx *svc.ServiceContext
+}
+
+func NewWebhookLogic(ctx context.Context, svcCtx *svc.ServiceContext) *WebhookLogic {
+	return &WebhookLogic{
+		Logger: logx.WithContext(ctx),
+		ctx:    ctx,
+		svcCtx: svcCtx,
+	}
+}
+
+// List 订阅列表（密钥脱敏）
+func (l *WebhookLogic) List() (*types.WebhookListResp, error) {
+	docs, err := l.svcCtx.WebhookSubscriptionModel.FindAll(l.ctx)
+	if err != nil {
+		return &types.WebhookListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+
+	list := make([]types.WebhookSubscription, 0, len(docs))
+	for _, doc := range docs {
+		item := types.WebhookSubscription{
+			Id:           doc.Id.Hex(),
+			Name:         doc.Name,
+			Url:          doc.Url,
+			Events:       doc.Events,
+			WorkspaceIds: doc.WorkspaceIds,
+			Headers:      doc.Headers,
+			Status:       doc.Status,
+			CreateTime:   doc.CreateTime.Local().Format("2006-01-02 15:04:05"),
+			UpdateTime:   doc.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+		}
+		if doc.Secret != "" {
+			item.Secret = maskKey(doc.Secret)
+		}
+		list = append(list, item)
+	}
+
+	return &types.WebhookListResp{Code: 0, Msg: "success", List: list, Events: model.WebhookEvents}, nil
+}
+
+// Save 新增或更新订阅
+func (l *WebhookLogic) Save(req *types.WebhookSaveReq) (*types.BaseResp, error) {
+	if req.Name == "" {
+		return &types.BaseResp{Code: 400, Msg: "订阅名称不能为空"}, nil
+	}
+	req.Url = strings.TrimSpace(req.Url)
+	if u, err := url.Parse(req.Url); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return &types.BaseResp{Code: 400, Msg: "URL无效，仅支持 http/https"}, nil
+	}
+	for _, event := range req.Events {
+		if !isWebhookEvent(event) {
+			return &types.BaseResp{Code: 400, Msg: "不支持的事件: " + event}, nil
+		}
+	}
+
+	doc := &model.WebhookSubscription{
+		Name:         req.Name,
+		Url:          req.Url,
+		Secret:       req.Secret,
+		Events:       req.Events,
+		WorkspaceIds: req.WorkspaceIds,
+		Headers:      req.Headers,
+		Status:       req.Status,
+	}
+	if doc.Events == nil {
+		doc.Events = []string{}
+	}
+
+	if req.Id == "" {
+		if err := l.svcCtx.WebhookSubscriptionModel.Create(l.ctx, doc); err != nil {
+			return &types.BaseResp{Code: 500, Msg: "保存失败: " + err.Error()}, nil
+		}
+		l.svcCtx.Webhook.InvalidateCache()
+		return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+	}
+
+	// 更新时，脱敏后的密钥表示未修改
+	existing, err := l.svcCtx.WebhookSubscriptionModel.FindById(l.ctx, req.Id)
+	if err != nil {
+		return &types.BaseResp{Code: 400, Msg: "订阅不存在"}, nil
+	}
+	if strings.Contains(doc.Secret, "****") {
+		doc.Secret = existing.Secret
+	}
+
+	update := bson.M{
+		"name":          doc.Name,
+		"url":           doc.Url,
+		"secret":        doc.Secret,
+		"events":        doc.Events,
+		"workspace_ids": doc.WorkspaceIds,
+		"headers":       doc.Headers,
+	}
+	if doc.Status != "" {
+		update["status"] = doc.Status
+	}
+	if err := l.svcCtx.WebhookSubscriptionModel.UpdateById(l.ctx, req.Id, update); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "保存失败: " + err.Error()}, nil
+	}
+	l.svcCtx.Webhook.InvalidateCache()
+	return &types.BaseResp{Code: 0, Msg: "保存成功"}, nil
+}
+
+// Delete 删除订阅，未投递的记录在投递时标记为失败
+func (l *WebhookLogic) Delete(req *types.WebhookIdReq) (*types.BaseResp, error) {
+	if err := l.svcCtx.WebhookSubscriptionModel.DeleteById(l.ctx, req.Id); err != nil {
+		return &types.BaseResp{Code: 500, Msg: "删除失败"}, nil
+	}
+	l.svcCtx.Webhook.InvalidateCache()
+	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
+}
+
+// Test 向订阅发送 ping 事件（同步发送，返回投递结果）
+func (l *WebhookLogic) Test(req *types.WebhookIdReq) (*types.WebhookDeliveryResp, error) {
+	sub, err := l.svcCtx.WebhookSubscriptionModel.FindById(l.ctx, req.Id)
+	if err != nil {
+		return &types.WebhookDeliveryResp{Code: 400, Msg: "订阅不存在"}, nil
+	}
+	d, err := l.svcCtx.Webhook.Test(l.ctx, sub)
+	return l.deliveryResp(d, err)
+}
+
+// DeliveryList 投递记录列表
+func (l *WebhookLogic) DeliveryList(req *types.WebhookDeliveryListReq) (*types.WebhookDeliveryListResp, error) {
+	filter := bson.M{}
+	if req.SubscriptionId != "" {
+		filter["subscription_id"] = req.SubscriptionId
+	}
+	if req.Event != "" {
+		filter["event"] = req.Event
+	}
+	if req.Status != "" {
+		filter["status"] = req.Status
+	}
+
+	docs, total, err := l.svcCtx.WebhookDeliveryModel.Search(l.ctx, filter, req.Page, req.PageSize)
+	if err != nil {
+		return &types.WebhookDeliveryListResp{Code: 500, Msg: "查询失败"}, nil
+	}
+	list := make([]types.WebhookDelivery, 0, len(docs))
+	for i := range docs {
+		list = append(list, toWebhookDelivery(&docs[i]))
+	}
+	return &types.WebhookDeliveryListResp{Code: 0, Msg: "success", Total: total, List: list}, nil
+}
+
+// DeliveryReplay 重放投递记录（同步发送，返回新的投递记录）
+func (l *WebhookLogic) DeliveryReplay(req *types.WebhookIdReq) (*types.WebhookDeliveryResp, error) {
+	if _, err := l.svcCtx.WebhookDeliveryModel.FindById(l.ctx, req.Id); err != nil {
+		return &types.WebhookDeliveryResp{Code: 400, Msg: "投递记录不存在"}, nil
+	}
+	d, err := l.svcCtx.Webhook.Replay(l.ctx, req.Id)
+	return l.deliveryResp(d, err)
+}
+
+func (l *WebhookLogic) deliveryResp(d *model.WebhookDelivery, err error) (*types.WebhookDeliveryResp, error) {
+	if err != nil {
+		l.Logger.Errorf("Webhook delivery failed: %v", err)
+		return &types.WebhookDeliveryResp{Code: 500, Msg: "投递失败: " + err.Error()}, nil
+	}
+	item := toWebhookDelivery(d)
+	if d.Status != model.WebhookDeliverySuccess {
+		return &types.WebhookDeliveryResp{Code: 500, Msg: "投递失败: " + d.LastError, Data: &item}, nil
+	}
+	return &types.WebhookDeliveryResp{Code: 0, Msg: "投递成功", Data: &item}, nil
+}
+
+func toWebhookDelivery(d *model.WebhookDelivery) types.WebhookDelivery {
+	item := types.WebhookDelivery{
+		Id:               d.Id.Hex(),
+		SubscriptionId:   d.SubscriptionId,
+		SubscriptionName: d.SubscriptionName,
+		EventId:          d.EventId,
+		Event:            d.Event,
+		WorkspaceId:      d.WorkspaceId,
+		Payload:          d.Payload,
+		Status:           d.Status,
+		Attempts:         d.Attempts,
+		ResponseCode:     d.ResponseCode,
+		ResponseBody:     d.ResponseBody,
+		LastError:        d.LastError,
+		Duration:         d.Duration,
+		ReplayOf:         d.ReplayOf,
+		CreateTime:       d.CreateTime.Local().Format("2006-01-02 15:04:05"),
+		UpdateTime:       d.UpdateTime.Local().Format("2006-01-02 15:04:05"),
+	}
+	if d.Status == model.WebhookDeliveryRetrying {
+		item.NextRetryTime = d.NextRetryTime.Local().Format("2006-01-02 15:04:05")
+	}
+	return item
+}
+
+// isWebhookEvent 是否为可订阅的事件
+func isWebhookEvent(event string) bool {
+	for _, e := range model.WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
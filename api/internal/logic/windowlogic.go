@@ -0,0 +1,152 @@
+package logic
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/svc"
+	"cscan/api/internal/types"
+	"cscan/model"
+	"cscan/scheduler"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// windowWatcherLock 多个 API 实例时只有一个实例执行维护窗口检查
+const windowWatcherLock = "cscan:window:watcher:lock"
+
+// toModelWindow 转换并校验维护窗口配置，时段和日期均为空时返回 nil 表示清除
+func toModelWindow(w *types.MaintenanceWindow) (*model.MaintenanceWindow, error) {
+	window := &model.MaintenanceWindow{
+		Timezone:      strings.TrimSpace(w.Timezone),
+		AllowedHours:  trimLines(w.AllowedHours),
+		BlackoutDates: trimLines(w.BlackoutDates),
+	}
+	if _, err := scheduler.NewWindow(common.WindowRules(window)); err != nil {
+		return nil, err
+	}
+	if len(window.AllowedHours) == 0 && len(window.BlackoutDates) == 0 {
+		return nil, nil
+	}
+	return window, nil
+}
+
+// toTypesWindow 转换维护窗口为接口返回格式
+func toTypesWindow(w *model.MaintenanceWindow) *types.MaintenanceWindow {
+	if w == nil {
+		return nil
+	}
+	return &types.MaintenanceWindow{
+		Timezone:      w.Timezone,
+		AllowedHours:  w.AllowedHours,
+		BlackoutDates: w.BlackoutDates,
+	}
+}
+
+// StartMaintenanceWindowWatcher 定期检查维护窗口
+// 窗口关闭时暂停执行中的任务，重新打开时继续因窗口关闭而暂停的任务
+func StartMaintenanceWindowWatcher(ctx context.Context, svcCtx *svc.ServiceContext) {
+	ticker := time.NewTicker(60 * time.Second)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				checkMaintenanceWindows(ctx, svcCtx)
+			}
+		}
+	}()
+}
+
+func checkMaintenanceWindows(ctx context.Context, svcCtx *svc.ServiceContext) {
+	if ok, err := svcCtx.RedisClient.SetNX(ctx, windowWatcherLock, "1", 50*time.Second).Result(); err != nil || !ok {
+		return
+	}
+
+	orgWindows := make(map[string]*scheduler.Window)
+	orgs, err := svcCtx.OrganizationModel.Find(ctx, bson.M{"window": bson.M{"$ne": nil}}, 0, 0)
+	if err != nil {
+		logx.Errorf("[WindowWatcher] find organizations error: %v", err)
+		return
+	}
+	for _, org := range orgs {
+		if w, err := scheduler.NewWindow(common.WindowRules(org.Window)); err == nil && w != nil {
+			orgWindows[org.Id.Hex()] = w
+		}
+	}
+
+	workspaces, err := svcCtx.WorkspaceModel.Find(ctx, bson.M{}, 0, 0)
+	if err != nil {
+		logx.Errorf("[WindowWatcher] find workspaces error: %v", err)
+		return
+	}
+	wsWindows := map[string]*scheduler.Window{"default": nil}
+	for _, ws := range workspaces {
+		w, _ := scheduler.NewWindow(common.WindowRules(ws.Window))
+		wsWindows[ws.Id.Hex()] = w
+	}
+
+	now := time.Now()
+	for wsId, wsWindow := range wsWindows {
+		if wsWindow == nil && len(orgWindows) == 0 {
+			continue
+		}
+		tasks, err := svcCtx.GetMainTaskModel(wsId).Find(ctx, bson.M{"$or": []bson.M{
+			{"status": bson.M{"$in": []string{model.TaskStatusStarted, model.TaskStatusPending}}},
+			{"status": model.TaskStatusPaused, "window_paused": true},
+		}}, 0, 0)
+		if err != nil {
+			logx.Errorf("[WindowWatcher] find tasks in workspace %s error: %v", wsId, err)
+			continue
+		}
+		for i := range tasks {
+			task := &tasks[i]
+			windows := scheduler.Windows{wsWindow, orgWindows[task.OrgId]}
+			open := windows.Open(now)
+			switch {
+			case !open && task.Status != model.TaskStatusPaused:
+				pauseForWindow(ctx, svcCtx, wsId, task, windows.NextOpen(now))
+			case open && task.Status == model.TaskStatusPaused:
+				resumeForWindow(ctx, svcCtx, wsId, task)
+			}
+		}
+	}
+}
+
+// pauseForWindow 维护窗口关闭时暂停任务，Worker 收到暂停信号后保存任务进度
+func pauseForWindow(ctx context.Context, svcCtx *svc.ServiceContext, wsId string, task *model.MainTask, next time.Time) {
+	resp, _ := NewMainTaskPauseLogic(ctx, svcCtx).MainTaskPause(&types.MainTaskControlReq{Id: task.Id.Hex(), WorkspaceId: wsId}, wsId)
+	if resp == nil || resp.Code != 0 {
+		return
+	}
+	svcCtx.GetMainTaskModel(wsId).Update(ctx, task.Id.Hex(), bson.M{"window_paused": true})
+
+	if next.IsZero() {
+		common.WriteTaskLog(ctx, svcCtx, task.TaskId, "WARN", "[Window] Outside maintenance window, task paused; the window will not reopen, resume manually")
+	} else {
+		common.WriteTaskLog(ctx, svcCtx, task.TaskId, "INFO", "[Window] Outside maintenance window, task paused until %s", next.Local().Format("2006-01-02 15:04:05"))
+	}
+	logx.Infof("[WindowWatcher] task %s paused: outside maintenance window", task.TaskId)
+}
+
+// resumeForWindow 维护窗口重新打开时继续任务，从保存的进度继续扫描
+func resumeForWindow(ctx context.Context, svcCtx *svc.ServiceContext, wsId string, task *model.MainTask) {
+	resp, _ := NewMainTaskResumeLogic(ctx, svcCtx).MainTaskResume(&types.MainTaskControlReq{Id: task.Id.Hex(), WorkspaceId: wsId}, wsId)
+	if resp == nil || resp.Code != 0 {
+		// 继续失败时不再自动重试，保留暂停状态由用户处理
+		svcCtx.GetMainTaskModel(wsId).Update(ctx, task.Id.Hex(), bson.M{"window_paused": false})
+		msg := "unknown error"
+		if resp != nil {
+			msg = resp.Msg
+		}
+		common.WriteTaskLog(ctx, svcCtx, task.TaskId, "ERROR", "[Window] Maintenance window reopened but resume failed: %s", msg)
+		return
+	}
+	common.WriteTaskLog(ctx, svcCtx, task.TaskId, "INFO", "[Window] Maintenance window reopened, task resumed")
+	logx.Infof("[WindowWatcher] task %s resumed: maintenance window reopened", task.TaskId)
+}
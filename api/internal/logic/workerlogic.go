@@ -38,6 +38,7 @@ type WorkerStatus struct {
 	RunningTasks       int             `json:"runningTasks"`
 	UpdateTime         string          `json:"updateTime"`
 	Tools              map[string]bool `json:"tools"`
+	Labels             []string        `json:"labels"`
 }
 
 func (l *WorkerListLogic) WorkerList() (resp *types.WorkerListResp, err error) {
@@ -115,6 +116,7 @@ func (l *WorkerListLogic) WorkerList() (resp *types.WorkerListResp, err error) {
 			Status:       workerStatus,
 			UpdateTime:   status.UpdateTime,
 			Tools:        status.Tools,
+			Labels:       status.Labels,
 		})
 	}
 
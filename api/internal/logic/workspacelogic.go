@@ -2,13 +2,18 @@ package logic
 
 import (
 	"context"
+	"strings"
 
+	"cscan/api/internal/logic/common"
+	"cscan/api/internal/middleware"
 	"cscan/api/internal/svc"
 	"cscan/api/internal/types"
 	"cscan/model"
+	"cscan/scheduler"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type WorkspaceListLogic struct {
@@ -27,6 +32,16 @@ func NewWorkspaceListLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Wor
 
 func (l *WorkspaceListLogic) WorkspaceList(req *types.PageReq) (resp *types.WorkspaceListResp, err error) {
 	filter := bson.M{}
+	// 非超级管理员只返回所属工作空间
+	if allowed := middleware.GetAllowedWorkspaces(l.ctx); allowed != nil {
+		oids := make([]primitive.ObjectID, 0, len(allowed))
+		for _, id := range allowed {
+			if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+				oids = append(oids, oid)
+			}
+		}
+		filter["_id"] = bson.M{"$in": oids}
+	}
 
 	total, err := l.svcCtx.WorkspaceModel.Count(l.ctx, filter)
 	if err != nil {
@@ -40,13 +55,31 @@ func (l *WorkspaceListLogic) WorkspaceList(req *types.PageReq) (resp *types.Work
 
 	list := make([]types.Workspace, 0, len(workspaces))
 	for _, w := range workspaces {
-		list = append(list, types.Workspace{
+		item := types.Workspace{
 			Id:          w.Id.Hex(),
 			Name:        w.Name,
 			Description: w.Description,
 			Status:      w.Status,
+			Window:      toTypesWindow(w.Window),
 			CreateTime:  w.CreateTime.Local().Format("2006-01-02 15:04:05"),
-		})
+		}
+		if w.Scope != nil {
+			item.Scope = &types.WorkspaceScope{
+				AllowedCidrs:   w.Scope.AllowedCidrs,
+				AllowedDomains: w.Scope.AllowedDomains,
+				ExcludedHosts:  w.Scope.ExcludedHosts,
+				ExcludedPorts:  w.Scope.ExcludedPorts,
+			}
+		}
+		if w.RateLimit != nil {
+			item.RateLimit = &types.WorkspaceRateLimit{
+				HostRate:      w.RateLimit.HostRate,
+				CIDRRate:      w.RateLimit.CIDRRate,
+				CIDRPrefix:    w.RateLimit.CIDRPrefix,
+				WorkspaceRate: w.RateLimit.WorkspaceRate,
+			}
+		}
+		list = append(list, item)
 	}
 
 	return &types.WorkspaceListResp{
@@ -72,12 +105,53 @@ func NewWorkspaceSaveLogic(ctx context.Context, svcCtx *svc.ServiceContext) *Wor
 }
 
 func (l *WorkspaceSaveLogic) WorkspaceSave(req *types.WorkspaceSaveReq) (resp *types.BaseResp, err error) {
+	var scope *model.ScanScope
+	if req.Scope != nil {
+		scope = &model.ScanScope{
+			AllowedCidrs:   trimLines(req.Scope.AllowedCidrs),
+			AllowedDomains: trimLines(req.Scope.AllowedDomains),
+			ExcludedHosts:  trimLines(req.Scope.ExcludedHosts),
+			ExcludedPorts:  strings.TrimSpace(req.Scope.ExcludedPorts),
+		}
+		if _, err := scheduler.NewScope(common.ScopeRules(scope)); err != nil {
+			return &types.BaseResp{Code: 400, Msg: err.Error()}, nil
+		}
+	}
+	var rateLimit *model.RateLimit
+	if req.RateLimit != nil {
+		rateLimit = &model.RateLimit{
+			HostRate:      req.RateLimit.HostRate,
+			CIDRRate:      req.RateLimit.CIDRRate,
+			CIDRPrefix:    req.RateLimit.CIDRPrefix,
+			WorkspaceRate: req.RateLimit.WorkspaceRate,
+		}
+		if err := common.RateLimitBudget(rateLimit).Validate(); err != nil {
+			return &types.BaseResp{Code: 400, Msg: "限速配置错误: " + err.Error()}, nil
+		}
+	}
+	var window *model.MaintenanceWindow
+	if req.Window != nil {
+		if window, err = toModelWindow(req.Window); err != nil {
+			return &types.BaseResp{Code: 400, Msg: "维护窗口配置错误: " + err.Error()}, nil
+		}
+	}
+
 	if req.Id != "" {
 		// 更新
-		err = l.svcCtx.WorkspaceModel.Update(l.ctx, req.Id, bson.M{
+		update := bson.M{
 			"name":        req.Name,
 			"description": req.Description,
-		})
+		}
+		if scope != nil {
+			update["scope"] = scope
+		}
+		if rateLimit != nil {
+			update["rate_limit"] = rateLimit
+		}
+		if req.Window != nil {
+			update["window"] = window
+		}
+		err = l.svcCtx.WorkspaceModel.Update(l.ctx, req.Id, update)
 		if err != nil {
 			return &types.BaseResp{Code: 500, Msg: "更新失败"}, nil
 		}
@@ -88,6 +162,9 @@ func (l *WorkspaceSaveLogic) WorkspaceSave(req *types.WorkspaceSaveReq) (resp *t
 	workspace := &model.Workspace{
 		Name:        req.Name,
 		Description: req.Description,
+		Scope:       scope,
+		RateLimit:   rateLimit,
+		Window:      window,
 	}
 	if err = l.svcCtx.WorkspaceModel.Insert(l.ctx, workspace); err != nil {
 		return &types.BaseResp{Code: 500, Msg: "创建失败"}, nil
@@ -121,3 +198,14 @@ func (l *WorkspaceDeleteLogic) WorkspaceDelete(req *types.WorkspaceDeleteReq) (r
 
 	return &types.BaseResp{Code: 0, Msg: "删除成功"}, nil
 }
+
+// trimLines 去除空白项
+func trimLines(items []string) []string {
+	result := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			result = append(result, item)
+		}
+	}
+	return result
+}
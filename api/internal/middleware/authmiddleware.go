@@ -5,8 +5,13 @@ import (
 	"encoding/json"
 	"net/http"
 	"strings"
+	"time"
+
+	"cscan/model"
 
 	"github.com/golang-jwt/jwt/v4"
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 type ContextKey string
@@ -16,18 +21,54 @@ const (
 	UsernameKey    ContextKey = "username"
 	RoleKey        ContextKey = "role"
 	WorkspaceIdKey ContextKey = "workspaceId"
+	// AllowedWorkspacesKey 用户可访问的工作空间列表，不存在时表示不受限制
+	AllowedWorkspacesKey ContextKey = "allowedWorkspaces"
 )
 
+// UserLoader 按ID加载用户，用户不存在时返回 nil, nil
+type UserLoader func(ctx context.Context, userId string) (*model.User, error)
+
+// TokenStore API Token 存储
+type TokenStore interface {
+	FindByHash(ctx context.Context, hash string) (*model.ApiToken, error)
+	UpdateLastUsed(ctx context.Context, id primitive.ObjectID, ip string) error
+}
+
+// AuditRecorder 审计日志记录
+type AuditRecorder interface {
+	RecordAudit(ctx context.Context, log *model.AuditLog) error
+}
+
 type AuthMiddleware struct {
 	AccessSecret string
+	LoadUser     UserLoader
+	Tokens       TokenStore
+	Audit        AuditRecorder
 }
 
-func NewAuthMiddleware(accessSecret string) *AuthMiddleware {
+func NewAuthMiddleware(accessSecret string, loadUser UserLoader) *AuthMiddleware {
 	return &AuthMiddleware{
 		AccessSecret: accessSecret,
+		LoadUser:     loadUser,
 	}
 }
 
+// WithApiTokens 启用 API Token 认证
+func (m *AuthMiddleware) WithApiTokens(tokens TokenStore, audit AuditRecorder) *AuthMiddleware {
+	m.Tokens = tokens
+	m.Audit = audit
+	return m
+}
+
+// identity 认证后的调用方信息
+type identity struct {
+	userId   string
+	username string
+	role     string
+	allowed  []string        // 可访问的工作空间，nil 表示不受限制
+	token    *model.ApiToken // 使用 API Token 认证时非空
+}
+
 func (m *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		var tokenStr string
@@ -41,6 +82,9 @@ func (m *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 				tokenStr = parts[1]
 			}
 		}
+		if tokenStr == "" {
+			tokenStr = r.Header.Get("X-Api-Token")
+		}
 
 		// 如果Header中没有，尝试从URL查询参数获取（用于SSE等不支持自定义Header的场景）
 		if tokenStr == "" {
@@ -52,40 +96,232 @@ func (m *AuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 			return
 		}
 
-		// 验证Token
-		token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
-			return []byte(m.AccessSecret), nil
-		})
-		if err != nil || !token.Valid {
-			unauthorized(w, "Token无效或已过期")
-			return
+		var id *identity
+		var errMsg string
+		if strings.HasPrefix(tokenStr, model.ApiTokenPrefix) && m.Tokens != nil {
+			id, errMsg = m.authApiToken(r, tokenStr)
+		} else {
+			id, errMsg = m.authJWT(r, tokenStr)
 		}
-
-		// 提取Claims
-		claims, ok := token.Claims.(jwt.MapClaims)
-		if !ok {
-			unauthorized(w, "Token解析失败")
+		if id == nil {
+			unauthorized(w, errMsg)
 			return
 		}
 
 		// 将用户信息存入Context（确保类型为string）
 		ctx := r.Context()
-		if userId, ok := claims["userId"].(string); ok {
-			ctx = context.WithValue(ctx, UserIdKey, userId)
-		}
-		if username, ok := claims["username"].(string); ok {
-			ctx = context.WithValue(ctx, UsernameKey, username)
+		ctx = context.WithValue(ctx, UserIdKey, id.userId)
+		ctx = context.WithValue(ctx, UsernameKey, id.username)
+		ctx = context.WithValue(ctx, RoleKey, id.role)
+
+		// 接口权限检查
+		if required := RequiredRole(r.URL.Path); !model.RoleAtLeast(id.role, required) {
+			m.auditApiToken(r, id, http.StatusForbidden)
+			forbidden(w, "权限不足")
+			return
 		}
-		if role, ok := claims["role"].(string); ok {
-			ctx = context.WithValue(ctx, RoleKey, role)
+		if id.token != nil && !TokenScopeAllows(id.token.Scopes, r.URL.Path) {
+			m.auditApiToken(r, id, http.StatusForbidden)
+			forbidden(w, "Token权限范围不足")
+			return
 		}
 
-		// 从Header获取当前工作空间
+		// 从Header获取当前工作空间，非超级管理员只能访问所属工作空间
 		workspaceId := r.Header.Get("X-Workspace-Id")
+		if id.allowed != nil {
+			// 空值在单工作空间接口中对应 default，同样需要校验；只有一个工作空间时直接使用该工作空间
+			resolved := workspaceId
+			if resolved == "" {
+				resolved = "default"
+				if len(id.allowed) == 1 {
+					resolved = id.allowed[0]
+				}
+			}
+			if !workspaceAllowed(resolved, id.allowed) {
+				if workspaceId == "" {
+					m.auditApiToken(r, id, http.StatusBadRequest)
+					badRequest(w, "请通过 X-Workspace-Id 指定工作空间")
+					return
+				}
+				m.auditApiToken(r, id, http.StatusForbidden)
+				forbidden(w, "无权访问该工作空间")
+				return
+			}
+			workspaceId = resolved
+			ctx = context.WithValue(ctx, AllowedWorkspacesKey, id.allowed)
+		}
 		ctx = context.WithValue(ctx, WorkspaceIdKey, workspaceId)
 
-		next(w, r.WithContext(ctx))
+		if id.token == nil {
+			next(w, r.WithContext(ctx))
+			return
+		}
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		next(sw, r.WithContext(ctx))
+		m.auditApiToken(r, id, sw.status)
+	}
+}
+
+// authJWT 校验登录 Token
+func (m *AuthMiddleware) authJWT(r *http.Request, tokenStr string) (*identity, string) {
+	// 验证Token
+	token, err := jwt.Parse(tokenStr, func(token *jwt.Token) (interface{}, error) {
+		return []byte(m.AccessSecret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, "Token无效或已过期"
+	}
+
+	// 提取Claims
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, "Token解析失败"
+	}
+
+	id := &identity{}
+	id.userId, _ = claims["userId"].(string)
+	id.username, _ = claims["username"].(string)
+	id.role, _ = claims["role"].(string)
+
+	// 角色和工作空间以数据库为准，修改后无需重新登录即可生效
+	if m.LoadUser != nil {
+		user, msg := m.loadEnabledUser(r.Context(), id.userId)
+		if user == nil {
+			return nil, msg
+		}
+		id.role = user.GetRole()
+		id.allowed = user.AllowedWorkspaces()
 	}
+	return id, ""
+}
+
+// authApiToken 校验 API Token。个人Token继承用户的角色和工作空间，服务Token按权限范围确定角色
+func (m *AuthMiddleware) authApiToken(r *http.Request, tokenStr string) (*identity, string) {
+	token, err := m.Tokens.FindByHash(r.Context(), model.HashApiToken(tokenStr))
+	if err != nil {
+		logx.Errorf("[Auth] find api token failed: %v", err)
+		return nil, "Token校验失败"
+	}
+	if token == nil {
+		return nil, "Token无效"
+	}
+	if token.Expired() {
+		return nil, "Token已过期"
+	}
+
+	id := &identity{userId: token.UserId, username: token.Username, token: token}
+	if token.Type == model.ApiTokenTypeService {
+		id.role = model.RoleAuditor
+		if token.HasScope(model.ApiTokenScopeTaskCreate) || token.HasScope(model.ApiTokenScopeAssetWrite) {
+			id.role = model.RoleOperator
+		}
+		id.allowed = token.WorkspaceIds
+		if id.allowed == nil {
+			id.allowed = []string{}
+		}
+	} else {
+		if m.LoadUser == nil {
+			return nil, "Token无效"
+		}
+		user, msg := m.loadEnabledUser(r.Context(), token.UserId)
+		if user == nil {
+			return nil, msg
+		}
+		id.username = user.Username
+		id.role = user.GetRole()
+		id.allowed = user.AllowedWorkspaces()
+	}
+
+	// 最后使用时间精确到分钟即可，避免每个请求都写库
+	if token.LastUsedTime == nil || time.Since(*token.LastUsedTime) > time.Minute {
+		ip := getClientIPFromRequest(r)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := m.Tokens.UpdateLastUsed(ctx, token.Id, ip); err != nil {
+				logx.Errorf("[Auth] update api token last used failed: %v", err)
+			}
+		}()
+	}
+	return id, ""
+}
+
+func (m *AuthMiddleware) loadEnabledUser(ctx context.Context, userId string) (*model.User, string) {
+	user, err := m.LoadUser(ctx, userId)
+	if err != nil {
+		logx.Errorf("[Auth] load user %s failed: %v", userId, err)
+		return nil, "用户信息加载失败"
+	}
+	if user == nil || user.Status != model.StatusEnable {
+		return nil, "用户不存在或已禁用"
+	}
+	return user, ""
+}
+
+// auditApiToken 记录 API Token 调用
+func (m *AuthMiddleware) auditApiToken(r *http.Request, id *identity, status int) {
+	if id.token == nil || m.Audit == nil {
+		return
+	}
+	log := &model.AuditLog{
+		Type:     model.AuditLogTypeApiToken,
+		UserId:   id.userId,
+		Username: id.username,
+		ClientIP: getClientIPFromRequest(r),
+		Path:     r.URL.Path,
+		Success:  status < http.StatusBadRequest,
+		Details: map[string]interface{}{
+			"tokenId":     id.token.Id.Hex(),
+			"tokenName":   id.token.Name,
+			"method":      r.Method,
+			"status":      status,
+			"workspaceId": r.Header.Get("X-Workspace-Id"),
+		},
+		CreateTime: time.Now(),
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := m.Audit.RecordAudit(ctx, log); err != nil {
+			logx.Errorf("[Auth] record api token audit failed: %v", err)
+		}
+	}()
+}
+
+// statusWriter 记录响应状态码
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Flush 支持 SSE 等流式响应
+func (w *statusWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// workspaceAllowed 判断工作空间是否在允许列表中。
+// all 表示查询全部，由查询逻辑按允许列表过滤
+func workspaceAllowed(workspaceId string, allowed []string) bool {
+	if workspaceId == "all" {
+		return true
+	}
+	return containsWorkspace(allowed, workspaceId)
+}
+
+func containsWorkspace(list []string, workspaceId string) bool {
+	for _, id := range list {
+		if id == workspaceId {
+			return true
+		}
+	}
+	return false
 }
 
 func unauthorized(w http.ResponseWriter, msg string) {
@@ -121,6 +357,14 @@ func GetRole(ctx context.Context) string {
 	return ""
 }
 
+// GetAllowedWorkspaces 从Context获取用户可访问的工作空间，nil 表示不受限制
+func GetAllowedWorkspaces(ctx context.Context) []string {
+	if v, ok := ctx.Value(AllowedWorkspacesKey).([]string); ok {
+		return v
+	}
+	return nil
+}
+
 // GetWorkspaceId 从Context获取工作空间ID
 func GetWorkspaceId(ctx context.Context) string {
 	if v := ctx.Value(WorkspaceIdKey); v != nil {
@@ -129,19 +373,31 @@ func GetWorkspaceId(ctx context.Context) string {
 	return ""
 }
 
-// RequireAdmin 管理员权限中间件，需要先经过认证中间件
+// RequireAdmin 超级管理员权限中间件，需要先经过认证中间件
 func RequireAdmin(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		role := GetRole(r.Context())
-		if role != "admin" {
-			w.Header().Set("Content-Type", "application/json")
-			w.WriteHeader(http.StatusForbidden)
-			json.NewEncoder(w).Encode(map[string]interface{}{
-				"code": 403,
-				"msg":  "需要管理员权限",
-			})
+		if !model.RoleAtLeast(GetRole(r.Context()), model.RoleSuperAdmin) {
+			forbidden(w, "需要管理员权限")
 			return
 		}
 		next(w, r)
 	}
 }
+
+func badRequest(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code": 400,
+		"msg":  msg,
+	})
+}
+
+func forbidden(w http.ResponseWriter, msg string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusForbidden)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"code": 403,
+		"msg":  msg,
+	})
+}
@@ -6,11 +6,13 @@ import (
 	"net/http"
 	"time"
 
+	"cscan/model"
+
 	"github.com/redis/go-redis/v9"
 	"github.com/zeromicro/go-zero/core/logx"
 )
 
-// ConsoleAuthMiddleware 控制台权限中间件（仅管理员可访问）
+// ConsoleAuthMiddleware 控制台权限中间件（仅超级管理员可访问）
 type ConsoleAuthMiddleware struct {
 	RedisClient *redis.Client
 }
@@ -27,7 +29,7 @@ func (m *ConsoleAuthMiddleware) Handle(next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// 从Context获取用户角色（需要先经过AuthMiddleware）
 		role := GetRole(r.Context())
-		if role != "admin" {
+		if !model.RoleAtLeast(role, model.RoleSuperAdmin) {
 			logx.Errorf("[ConsoleAuth] Access denied for non-admin user, role: %s, path: %s", role, r.URL.Path)
 			consoleForbidden(w, "需要管理员权限访问控制台")
 			return
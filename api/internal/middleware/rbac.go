@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"strings"
+
+	"cscan/model"
+)
+
+// superAdminPaths 仅超级管理员可访问的接口（前缀匹配）
+var superAdminPaths = []string{
+	// 用户管理（个人扫描配置除外）
+	"/api/v1/user/list",
+	"/api/v1/user/create",
+	"/api/v1/user/update",
+	"/api/v1/user/delete",
+	"/api/v1/user/resetPassword",
+	// 工作空间管理
+	"/api/v1/workspace/save",
+	"/api/v1/workspace/delete",
+	// Worker 管理、安装及控制台
+	"/api/v1/worker/delete",
+	"/api/v1/worker/rename",
+	"/api/v1/worker/restart",
+	"/api/v1/worker/concurrency",
+	"/api/v1/worker/install/",
+	"/api/v1/worker/logs/clear",
+	"/api/v1/worker/console",
+	// Webhook 订阅会把所有工作空间的数据发送到外部系统
+	"/api/v1/webhook/",
+	// 离线IP库为全局配置
+	"/api/v1/geoip/upload",
+	"/api/v1/geoip/update",
+	// OOB回连服务为全局配置，含鉴权token
+	"/api/v1/interactsh/",
+	// 通知渠道为全局配置，会推送所有工作空间的事件
+	"/api/v1/notify/",
+}
+
+// adminActions 需要工作空间管理员权限的操作（路径最后一段）
+var adminActions = map[string]bool{
+	"delete":      true,
+	"batchDelete": true,
+	"clear":       true,
+	"clearAll":    true,
+	"clearCustom": true,
+}
+
+// readActions 只读操作（路径最后一段），审计员可访问
+var readActions = map[string]bool{
+	"list":        true,
+	"stat":        true,
+	"detail":      true,
+	"history":     true,
+	"logs":        true,
+	"stream":      true,
+	"get":         true,
+	"export":      true,
+	"diff":        true,
+	"info":        true,
+	"categories":  true,
+	"templates":   true,
+	"enabled":     true,
+	"queryResult": true,
+	"lookup":      true,
+	"gallery":     true,
+	"similar":     true,
+}
+
+// selfServicePrefix 个人设置类接口，所有登录用户均可访问（权限由业务逻辑按用户区分）
+const selfServicePrefix = "/api/v1/token/"
+
+// tokenScopePaths API Token 写操作权限范围对应的接口，只读接口统一由 read 范围控制
+var tokenScopePaths = map[string][]string{
+	model.ApiTokenScopeTaskCreate: {
+		"/api/v1/task/create",
+		"/api/v1/task/start",
+	},
+	model.ApiTokenScopeAssetWrite: {
+		"/api/v1/asset/import",
+		"/api/v1/onlineapi/import",
+		"/api/v1/onlineapi/importAll",
+	},
+}
+
+// RequiredRole 返回访问接口所需的最低角色
+func RequiredRole(path string) string {
+	if strings.HasPrefix(path, selfServicePrefix) {
+		return model.RoleAuditor
+	}
+	for _, prefix := range superAdminPaths {
+		if strings.HasPrefix(path, prefix) {
+			return model.RoleSuperAdmin
+		}
+	}
+	action := path[strings.LastIndex(path, "/")+1:]
+	switch {
+	case adminActions[action]:
+		return model.RoleWorkspaceAdmin
+	case readActions[action]:
+		return model.RoleAuditor
+	default:
+		return model.RoleOperator
+	}
+}
+
+// TokenScopeAllows 判断 API Token 的权限范围是否允许访问接口。
+// Token 不能管理 Token，写操作只开放 tokenScopePaths 中列出的接口
+func TokenScopeAllows(scopes []string, path string) bool {
+	if strings.HasPrefix(path, selfServicePrefix) {
+		return false
+	}
+	readOnly := RequiredRole(path) == model.RoleAuditor
+	for _, scope := range scopes {
+		if scope == model.ApiTokenScopeRead && readOnly {
+			return true
+		}
+		for _, p := range tokenScopePaths[scope] {
+			if p == path {
+				return true
+			}
+		}
+	}
+	return false
+}
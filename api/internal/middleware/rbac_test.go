@@ -0,0 +1,243 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"cscan/model"
+
+	"github.com/golang-jwt/jwt/v4"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestRequiredRole(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/api/v1/asset/list", model.RoleAuditor},
+		{"/api/v1/task/logs/stream", model.RoleAuditor},
+		{"/api/v1/user/scanConfig/get", model.RoleAuditor},
+		{"/api/v1/task/create", model.RoleOperator},
+		{"/api/v1/user/scanConfig/save", model.RoleOperator},
+		{"/api/v1/vul/clear", model.RoleWorkspaceAdmin},
+		{"/api/v1/poc/custom/clearAll", model.RoleWorkspaceAdmin},
+		{"/api/v1/asset/batchDelete", model.RoleWorkspaceAdmin},
+		{"/api/v1/user/list", model.RoleSuperAdmin},
+		{"/api/v1/user/delete", model.RoleSuperAdmin},
+		{"/api/v1/worker/delete", model.RoleSuperAdmin},
+		{"/api/v1/worker/install/command", model.RoleSuperAdmin},
+		{"/api/v1/worker/console/terminal/exec", model.RoleSuperAdmin},
+		{"/api/v1/workspace/save", model.RoleSuperAdmin},
+		{"/api/v1/webhook/delivery/list", model.RoleSuperAdmin},
+		{"/api/v1/geoip/upload", model.RoleSuperAdmin},
+		{"/api/v1/geoip/info", model.RoleAuditor},
+		{"/api/v1/geoip/enrich", model.RoleOperator},
+		{"/api/v1/interactsh/config/get", model.RoleSuperAdmin},
+		{"/api/v1/notify/channel/list", model.RoleSuperAdmin},
+		{"/api/v1/notify/channel/test", model.RoleSuperAdmin},
+		{"/api/v1/screenshot/get", model.RoleAuditor},
+		{"/api/v1/screenshot/gallery", model.RoleAuditor},
+		{"/api/v1/screenshot/migrate", model.RoleOperator},
+	}
+	for _, tt := range tests {
+		if got := RequiredRole(tt.path); got != tt.want {
+			t.Errorf("RequiredRole(%s) = %s, want %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestAuthMiddleware_RoleAndWorkspace(t *testing.T) {
+	const secret = "test-secret"
+	users := map[string]*model.User{
+		"root":    {Status: model.StatusEnable, Role: model.RoleSuperAdmin},
+		"legacy":  {Status: model.StatusEnable, Role: "admin"},
+		"auditor": {Status: model.StatusEnable, Role: model.RoleAuditor, WorkspaceIds: []string{"ws1"}},
+		"op":      {Status: model.StatusEnable, Role: model.RoleOperator, WorkspaceIds: []string{"ws1", "ws2"}},
+		"opdef":   {Status: model.StatusEnable, Role: model.RoleOperator, WorkspaceIds: []string{"ws1", "default"}},
+		"off":     {Status: model.StatusDisable, Role: model.RoleOperator},
+	}
+	m := NewAuthMiddleware(secret, func(ctx context.Context, userId string) (*model.User, error) {
+		return users[userId], nil
+	})
+
+	tests := []struct {
+		name      string
+		userId    string
+		path      string
+		workspace string
+		wantCode  int
+		wantWs    string
+	}{
+		{"superadmin", "root", "/api/v1/worker/delete", "", http.StatusOK, ""},
+		{"unmigrated legacy role is auditor", "legacy", "/api/v1/worker/delete", "", http.StatusForbidden, ""},
+		{"unmigrated legacy role reads default", "legacy", "/api/v1/asset/list", "", http.StatusOK, "default"},
+		{"auditor can read", "auditor", "/api/v1/asset/list", "ws1", http.StatusOK, "ws1"},
+		{"auditor cannot write", "auditor", "/api/v1/task/create", "ws1", http.StatusForbidden, ""},
+		{"auditor uses only workspace", "auditor", "/api/v1/asset/list", "", http.StatusOK, "ws1"},
+		{"operator cannot clear", "op", "/api/v1/vul/clear", "ws1", http.StatusForbidden, ""},
+		{"operator other workspace", "op", "/api/v1/task/create", "ws3", http.StatusForbidden, ""},
+		{"operator all workspaces", "op", "/api/v1/task/list", "all", http.StatusOK, "all"},
+		{"multi workspace empty header", "op", "/api/v1/task/create", "", http.StatusBadRequest, ""},
+		{"multi workspace empty header uses default", "opdef", "/api/v1/task/create", "", http.StatusOK, "default"},
+		{"disabled user", "off", "/api/v1/asset/list", "", http.StatusUnauthorized, ""},
+		{"unknown user", "nobody", "/api/v1/asset/list", "", http.StatusUnauthorized, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+				"userId": tt.userId,
+				"role":   "admin",
+				"exp":    time.Now().Add(time.Hour).Unix(),
+			})
+			tokenStr, _ := token.SignedString([]byte(secret))
+
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.Header.Set("Authorization", "Bearer "+tokenStr)
+			req.Header.Set("X-Workspace-Id", tt.workspace)
+			rr := httptest.NewRecorder()
+
+			var gotWs string
+			m.Handle(func(w http.ResponseWriter, r *http.Request) {
+				gotWs = GetWorkspaceId(r.Context())
+			})(rr, req)
+
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusOK && gotWs != tt.wantWs {
+				t.Errorf("workspace = %q, want %q", gotWs, tt.wantWs)
+			}
+		})
+	}
+}
+
+func TestRoleAtLeast(t *testing.T) {
+	if model.RoleAtLeast("", model.RoleAuditor) {
+		t.Error("empty role should have no permission")
+	}
+	if model.RoleAtLeast("admin", model.RoleAuditor) {
+		t.Error("legacy admin role should have no permission until migrated")
+	}
+	if model.NormalizeRole("") != model.RoleAuditor || model.NormalizeRole("admin") != model.RoleAuditor {
+		t.Error("empty and unknown roles should be normalized to auditor")
+	}
+	if model.RoleAtLeast(model.RoleOperator, model.RoleWorkspaceAdmin) {
+		t.Error("operator should not have workspace admin permission")
+	}
+}
+
+func TestTokenScopeAllows(t *testing.T) {
+	read := []string{model.ApiTokenScopeRead}
+	task := []string{model.ApiTokenScopeTaskCreate}
+	tests := []struct {
+		scopes []string
+		path   string
+		want   bool
+	}{
+		{read, "/api/v1/vul/list", true},
+		{read, "/api/v1/task/create", false},
+		{task, "/api/v1/task/create", true},
+		{task, "/api/v1/task/list", false},
+		{task, "/api/v1/task/delete", false},
+		{[]string{model.ApiTokenScopeAssetWrite}, "/api/v1/asset/import", true},
+		{[]string{model.ApiTokenScopeRead, model.ApiTokenScopeTaskCreate}, "/api/v1/token/list", false},
+	}
+	for _, tt := range tests {
+		if got := TokenScopeAllows(tt.scopes, tt.path); got != tt.want {
+			t.Errorf("TokenScopeAllows(%v, %s) = %v, want %v", tt.scopes, tt.path, got, tt.want)
+		}
+	}
+}
+
+type fakeTokenStore map[string]*model.ApiToken
+
+func (s fakeTokenStore) FindByHash(ctx context.Context, hash string) (*model.ApiToken, error) {
+	return s[hash], nil
+}
+
+func (s fakeTokenStore) UpdateLastUsed(ctx context.Context, id primitive.ObjectID, ip string) error {
+	return nil
+}
+
+type fakeAudit struct{ logs chan *model.AuditLog }
+
+func (a *fakeAudit) RecordAudit(ctx context.Context, log *model.AuditLog) error {
+	a.logs <- log
+	return nil
+}
+
+func TestAuthMiddleware_ApiToken(t *testing.T) {
+	expired := time.Now().Add(-time.Hour)
+	store := fakeTokenStore{
+		model.HashApiToken("cst_ci"): {
+			Id: primitive.NewObjectID(), Name: "ci", Type: model.ApiTokenTypeService,
+			Scopes: []string{model.ApiTokenScopeRead, model.ApiTokenScopeTaskCreate}, WorkspaceIds: []string{"ws1"},
+		},
+		model.HashApiToken("cst_multi"): {
+			Id: primitive.NewObjectID(), Name: "multi", Type: model.ApiTokenTypeService,
+			Scopes: []string{model.ApiTokenScopeRead}, WorkspaceIds: []string{"ws1", "ws2"},
+		},
+		model.HashApiToken("cst_mine"): {
+			Id: primitive.NewObjectID(), Name: "mine", Type: model.ApiTokenTypePersonal,
+			Scopes: []string{model.ApiTokenScopeRead}, UserId: "auditor",
+		},
+		model.HashApiToken("cst_old"): {
+			Id: primitive.NewObjectID(), Type: model.ApiTokenTypeService, ExpireTime: &expired,
+			Scopes: []string{model.ApiTokenScopeRead}, WorkspaceIds: []string{"ws1"},
+		},
+	}
+	audit := &fakeAudit{logs: make(chan *model.AuditLog, 16)}
+	m := NewAuthMiddleware("secret", func(ctx context.Context, userId string) (*model.User, error) {
+		if userId == "auditor" {
+			return &model.User{Username: "bob", Status: model.StatusEnable, Role: model.RoleAuditor, WorkspaceIds: []string{"ws1"}}, nil
+		}
+		return nil, nil
+	}).WithApiTokens(store, audit)
+
+	tests := []struct {
+		name      string
+		token     string
+		path      string
+		workspace string
+		wantCode  int
+	}{
+		{"service token creates task", "cst_ci", "/api/v1/task/create", "ws1", http.StatusOK},
+		{"service token outside workspace", "cst_ci", "/api/v1/task/create", "ws2", http.StatusForbidden},
+		{"service token without scope", "cst_ci", "/api/v1/asset/import", "ws1", http.StatusForbidden},
+		{"service token single workspace empty header", "cst_ci", "/api/v1/task/create", "", http.StatusOK},
+		{"multi workspace token reads", "cst_multi", "/api/v1/vul/list", "ws2", http.StatusOK},
+		{"multi workspace token empty header", "cst_multi", "/api/v1/vul/list", "", http.StatusBadRequest},
+		{"multi workspace token default workspace", "cst_multi", "/api/v1/vul/list", "default", http.StatusForbidden},
+		{"personal token reads", "cst_mine", "/api/v1/vul/list", "ws1", http.StatusOK},
+		{"personal token cannot manage tokens", "cst_mine", "/api/v1/token/create", "", http.StatusForbidden},
+		{"expired token", "cst_old", "/api/v1/vul/list", "ws1", http.StatusUnauthorized},
+		{"unknown token", "cst_nope", "/api/v1/vul/list", "ws1", http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			req.Header.Set("X-Api-Token", tt.token)
+			req.Header.Set("X-Workspace-Id", tt.workspace)
+			rr := httptest.NewRecorder()
+			m.Handle(func(w http.ResponseWriter, r *http.Request) {})(rr, req)
+			if rr.Code != tt.wantCode {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.wantCode)
+			}
+			if tt.wantCode == http.StatusUnauthorized {
+				return
+			}
+			select {
+			case log := <-audit.logs:
+				if log.Type != model.AuditLogTypeApiToken || log.Path != tt.path || log.Success != (tt.wantCode == http.StatusOK) {
+					t.Errorf("unexpected audit log %+v", log)
+				}
+			case <-time.After(time.Second):
+				t.Error("api token usage not audited")
+			}
+		})
+	}
+}
@@ -8,12 +8,17 @@ import (
 	"cscan/api/internal/config"
 	"cscan/api/internal/svc/sync"
 	"cscan/model"
+	"cscan/pkg/blob"
+	"cscan/pkg/geoip"
+	"cscan/pkg/notify"
+	"cscan/pkg/webhook"
 	"cscan/rpc/task/pb"
 	"cscan/scheduler"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/zeromicro/go-zero/core/logx"
 	"github.com/zeromicro/go-zero/zrpc"
+	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/mongo"
 	"go.mongodb.org/mongo-driver/mongo/options"
 	"google.golang.org/grpc"
@@ -37,6 +42,22 @@ type ServiceContext struct {
 	ActiveFingerprintModel   *model.ActiveFingerprintModel
 	CommandHistoryModel      *model.CommandHistoryModel
 	AuditLogModel            *model.AuditLogModel
+	ApiTokenModel            *model.ApiTokenModel
+	NotifyChannelModel       *model.NotifyChannelModel
+	WebhookSubscriptionModel *model.WebhookSubscriptionModel
+	WebhookDeliveryModel     *model.WebhookDeliveryModel
+
+	// 消息通知
+	Notifier *notify.Dispatcher
+
+	// 出站 Webhook 事件
+	Webhook *webhook.Emitter
+
+	// 离线IP归属查询
+	GeoIP *geoip.Resolver
+
+	// 截图对象存储
+	Screenshots blob.Store
 
 	// 调度器
 	Scheduler *scheduler.Scheduler
@@ -111,12 +132,24 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		ActiveFingerprintModel:   model.NewActiveFingerprintModel(mongoDB),
 		CommandHistoryModel:      model.NewCommandHistoryModel(mongoDB),
 		AuditLogModel:            model.NewAuditLogModel(mongoDB),
+		ApiTokenModel:            model.NewApiTokenModel(mongoDB),
+		NotifyChannelModel:       model.NewNotifyChannelModel(mongoDB),
+		WebhookSubscriptionModel: model.NewWebhookSubscriptionModel(mongoDB),
+		WebhookDeliveryModel:     model.NewWebhookDeliveryModel(mongoDB),
 		Scheduler:               scheduler.NewScheduler(rdb),
 		TemplateCategories:      []string{},
 		TemplateTags:            []string{},
 		TemplateStats:           map[string]int{},
 	}
 
+	svcCtx.Notifier = notify.NewDispatcher(svcCtx.NotifyChannelModel, rdb)
+	svcCtx.Webhook = webhook.NewEmitter(svcCtx.WebhookSubscriptionModel, svcCtx.WebhookDeliveryModel)
+	svcCtx.GeoIP = geoip.NewResolver(c.GeoIP.GetDir())
+	svcCtx.Screenshots, err = blob.New(c.Screenshot, mongoDB)
+	if err != nil {
+		panic(fmt.Sprintf("Failed to init screenshot store: %v", err))
+	}
+
 	// 初始化同步服务
 	svcCtx.SyncMethods = sync.NewSyncMethods(
 		svcCtx.NucleiTemplateModel,
@@ -161,6 +194,30 @@ func (s *ServiceContext) GetAssetHistoryModel(workspaceId string) *model.AssetHi
 	return model.NewAssetHistoryModel(s.MongoDB, workspaceId)
 }
 
+// GetVulSuppressRuleModel 根据workspaceId获取漏洞抑制规则模型
+func (s *ServiceContext) GetVulSuppressRuleModel(workspaceId string) *model.VulSuppressRuleModel {
+	if workspaceId == "" {
+		workspaceId = "default"
+	}
+	return model.NewVulSuppressRuleModel(s.MongoDB, workspaceId)
+}
+
+// GetDNSRecordModel 根据workspaceId获取DNS记录模型
+func (s *ServiceContext) GetDNSRecordModel(workspaceId string) *model.DNSRecordModel {
+	if workspaceId == "" {
+		workspaceId = "default"
+	}
+	return model.NewDNSRecordModel(s.MongoDB, workspaceId)
+}
+
+// GetReportTemplateModel 根据workspaceId获取报告模板模型
+func (s *ServiceContext) GetReportTemplateModel(workspaceId string) *model.ReportTemplateModel {
+	if workspaceId == "" {
+		workspaceId = "default"
+	}
+	return model.NewReportTemplateModel(s.MongoDB, workspaceId)
+}
+
 // RefreshTemplateCache 刷新模板元数据缓存
 func (s *ServiceContext) RefreshTemplateCache() {
 	ctx := context.Background()
@@ -195,3 +252,63 @@ func (s *ServiceContext) SyncWappalyzerFingerprints() {
 func (s *ServiceContext) ImportCustomPocAndFingerprints() {
 	s.SyncMethods.ImportCustomPocAndFingerprints()
 }
+
+// HandleTaskDeadLetter 子任务进入死信队列后将主任务标记为失败
+func (s *ServiceContext) HandleTaskDeadLetter(ctx context.Context, dl *scheduler.DeadLetter) {
+	if dl.Task.MainTaskId == "" {
+		return
+	}
+	taskModel := s.GetMainTaskModel(dl.Task.WorkspaceId)
+	err := taskModel.Update(ctx, dl.Task.MainTaskId, bson.M{
+		"status":   model.TaskStatusFailure,
+		"end_time": time.Now(),
+		"result":   fmt.Sprintf("子任务 %s 执行 %d 次均未完成(Worker: %s)，已移入死信队列", dl.Task.TaskId, dl.Attempts, dl.Worker),
+	})
+	if err != nil {
+		logx.Errorf("[DeadLetter] update main task %s failed: %v", dl.Task.MainTaskId, err)
+		return
+	}
+
+	if task, err := taskModel.FindById(ctx, dl.Task.MainTaskId); err == nil {
+		s.Webhook.Emit(webhook.TaskStatusEvent(dl.Task.WorkspaceId, task, task.Status, ""))
+		s.Notifier.DispatchOnce(ctx, notify.TaskOnceKey(model.NotifyEventTaskFailed, task), task.NotifyId,
+			notify.TaskMessage(task, model.NotifyEventTaskFailed))
+	}
+}
+
+// StartWorkerWatcher 定期检查心跳过期的 Worker 并发送离线通知
+func (s *ServiceContext) StartWorkerWatcher(ctx context.Context) {
+	ticker := time.NewTicker(60 * time.Second)
+	go func() {
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.checkWorkerOffline(ctx)
+			}
+		}
+	}()
+}
+
+// checkWorkerOffline 心跳 key 已过期但仍在 Worker 集合中的视为异常离线
+func (s *ServiceContext) checkWorkerOffline(ctx context.Context) {
+	names, err := s.RedisClient.SMembers(ctx, "cscan:workers").Result()
+	if err != nil {
+		return
+	}
+	for _, name := range names {
+		exists, err := s.RedisClient.Exists(ctx, "cscan:worker:"+name).Result()
+		if err != nil || exists > 0 {
+			continue
+		}
+		// SRem 成功才通知，避免多个 API 实例重复发送
+		if removed, err := s.RedisClient.SRem(ctx, "cscan:workers", name).Result(); err != nil || removed == 0 {
+			continue
+		}
+		logx.Infof("[WorkerWatcher] worker %s heartbeat expired", name)
+		s.Notifier.Dispatch("", notify.WorkerOfflineMessage(name, "心跳超时"))
+		s.Webhook.Emit(webhook.WorkerOfflineEvent(name, "心跳超时"))
+	}
+}
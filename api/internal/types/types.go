@@ -34,9 +34,11 @@ type LoginResp struct {
 }
 
 type UserInfo struct {
-	Id       string `json:"id"`
-	Username string `json:"username"`
-	Status   string `json:"status"`
+	Id           string   `json:"id"`
+	Username     string   `json:"username"`
+	Status       string   `json:"status"`
+	Role         string   `json:"role"`
+	WorkspaceIds []string `json:"workspaceIds"`
 }
 
 type UserListResp struct {
@@ -48,15 +50,19 @@ type UserListResp struct {
 
 // ==================== 用户管理 ====================
 type UserCreateReq struct {
-	Username string `json:"username"`
-	Password string `json:"password"`
-	Status   string `json:"status"`
+	Username     string   `json:"username"`
+	Password     string   `json:"password"`
+	Status       string   `json:"status"`
+	Role         string   `json:"role,optional"`         // superadmin/workspace_admin/operator/auditor，默认 operator
+	WorkspaceIds []string `json:"workspaceIds,optional"` // 所属工作空间
 }
 
 type UserUpdateReq struct {
-	Id       string `json:"id"`
-	Username string `json:"username"`
-	Status   string `json:"status"`
+	Id           string   `json:"id"`
+	Username     string   `json:"username"`
+	Status       string   `json:"status"`
+	Role         string   `json:"role,optional"`
+	WorkspaceIds []string `json:"workspaceIds,optional"`
 }
 
 type UserDeleteReq struct {
@@ -68,13 +74,84 @@ type UserResetPasswordReq struct {
 	NewPassword string `json:"newPassword"`
 }
 
+// ==================== API Token ====================
+type ApiTokenListReq struct {
+	All bool `json:"all,optional"` // 超级管理员查看全部用户的Token
+}
+
+type ApiTokenInfo struct {
+	Id           string   `json:"id"`
+	Name         string   `json:"name"`
+	Type         string   `json:"type"`
+	TokenPrefix  string   `json:"tokenPrefix"`
+	Scopes       []string `json:"scopes"`
+	Username     string   `json:"username"`
+	WorkspaceIds []string `json:"workspaceIds"`
+	ExpireTime   string   `json:"expireTime"`
+	LastUsedTime string   `json:"lastUsedTime"`
+	LastUsedIP   string   `json:"lastUsedIp"`
+	Expired      bool     `json:"expired"`
+	CreateTime   string   `json:"createTime"`
+}
+
+type ApiTokenListResp struct {
+	Code int            `json:"code"`
+	Msg  string         `json:"msg"`
+	List []ApiTokenInfo `json:"list"`
+}
+
+type ApiTokenCreateReq struct {
+	Name         string   `json:"name"`
+	Type         string   `json:"type,default=personal"` // personal/service
+	Scopes       []string `json:"scopes"`
+	ExpireDays   int      `json:"expireDays,optional"`   // 有效天数，0 表示永不过期
+	WorkspaceIds []string `json:"workspaceIds,optional"` // 服务Token可访问的工作空间
+}
+
+type ApiTokenCreateResp struct {
+	Code  int          `json:"code"`
+	Msg   string       `json:"msg"`
+	Token string       `json:"token,omitempty"` // Token明文，只返回一次
+	Info  ApiTokenInfo `json:"info"`
+}
+
+type ApiTokenRevokeReq struct {
+	Id string `json:"id"`
+}
+
 // ==================== 工作空间 ====================
 type Workspace struct {
-	Id          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	CreateTime  string `json:"createTime"`
+	Id          string              `json:"id"`
+	Name        string              `json:"name"`
+	Description string              `json:"description"`
+	Status      string              `json:"status"`
+	Scope       *WorkspaceScope     `json:"scope,omitempty"`
+	RateLimit   *WorkspaceRateLimit `json:"rateLimit,omitempty"`
+	Window      *MaintenanceWindow  `json:"window,omitempty"`
+	CreateTime  string              `json:"createTime"`
+}
+
+// WorkspaceScope 工作空间扫描范围
+type WorkspaceScope struct {
+	AllowedCidrs   []string `json:"allowedCidrs,optional"`
+	AllowedDomains []string `json:"allowedDomains,optional"`
+	ExcludedHosts  []string `json:"excludedHosts,optional"`
+	ExcludedPorts  string   `json:"excludedPorts,optional"`
+}
+
+// WorkspaceRateLimit 工作空间限速预算，速率为每秒请求数，0 表示不限制
+type WorkspaceRateLimit struct {
+	HostRate      int `json:"hostRate,optional"`      // 单个目标主机
+	CIDRRate      int `json:"cidrRate,optional"`      // 同一网段
+	CIDRPrefix    int `json:"cidrPrefix,optional"`    // IPv4 网段前缀长度，默认 24
+	WorkspaceRate int `json:"workspaceRate,optional"` // 整个工作空间
+}
+
+// MaintenanceWindow 维护窗口，窗口外定时任务推迟执行、运行中的任务自动暂停
+type MaintenanceWindow struct {
+	Timezone      string   `json:"timezone,optional"`      // IANA 时区，为空使用服务器时区
+	AllowedHours  []string `json:"allowedHours,optional"`  // 允许扫描的时段，如 09:00-18:00
+	BlackoutDates []string `json:"blackoutDates,optional"` // 禁止扫描的日期，如 2026-12-31 或 2026-12-24~2027-01-02
 }
 
 type WorkspaceListResp struct {
@@ -85,9 +162,12 @@ type WorkspaceListResp struct {
 }
 
 type WorkspaceSaveReq struct {
-	Id          string `json:"id,optional"`
-	Name        string `json:"name"`
-	Description string `json:"description,optional"`
+	Id          string              `json:"id,optional"`
+	Name        string              `json:"name"`
+	Description string              `json:"description,optional"`
+	Scope       *WorkspaceScope     `json:"scope,optional"`     // 为空时不修改扫描范围
+	RateLimit   *WorkspaceRateLimit `json:"rateLimit,optional"` // 为空时不修改限速预算
+	Window      *MaintenanceWindow  `json:"window,optional"`    // 为空时不修改维护窗口
 }
 
 type WorkspaceDeleteReq struct {
@@ -96,11 +176,12 @@ type WorkspaceDeleteReq struct {
 
 // ==================== 组织管理 ====================
 type Organization struct {
-	Id          string `json:"id"`
-	Name        string `json:"name"`
-	Description string `json:"description"`
-	Status      string `json:"status"`
-	CreateTime  string `json:"createTime"`
+	Id          string             `json:"id"`
+	Name        string             `json:"name"`
+	Description string             `json:"description"`
+	Status      string             `json:"status"`
+	Window      *MaintenanceWindow `json:"window,omitempty"`
+	CreateTime  string             `json:"createTime"`
 }
 
 type OrganizationListResp struct {
@@ -111,10 +192,11 @@ type OrganizationListResp struct {
 }
 
 type OrganizationSaveReq struct {
-	Id          string `json:"id,optional"`
-	Name        string `json:"name"`
-	Description string `json:"description,optional"`
-	Status      string `json:"status,optional"`
+	Id          string             `json:"id,optional"`
+	Name        string             `json:"name"`
+	Description string             `json:"description,optional"`
+	Status      string             `json:"status,optional"`
+	Window      *MaintenanceWindow `json:"window,optional"` // 为空时不修改维护窗口
 }
 
 type OrganizationDeleteReq struct {
@@ -153,6 +235,7 @@ type Asset struct {
 	Port       int      `json:"port"`
 	Category   string   `json:"category"`
 	Service    string   `json:"service"`
+	Transport  string   `json:"transport,omitempty"` // 传输层协议，空为tcp
 	Title      string   `json:"title"`
 	App        []string `json:"app"`
 	HttpStatus string   `json:"httpStatus"`
@@ -166,6 +249,9 @@ type Asset struct {
 	IP         *IPInfo  `json:"ip,omitempty"` // IP地址信息
 	IsCDN      bool     `json:"isCdn"`
 	IsCloud    bool     `json:"isCloud"`
+	CDNName    string   `json:"cdnName,omitempty"`
+	WAF        string   `json:"waf,omitempty"`
+	CloudName  string   `json:"cloudName,omitempty"`
 	IsNew      bool     `json:"isNew"`
 	IsUpdated  bool     `json:"isUpdated"`
 	CreateTime string   `json:"createTime"`
@@ -176,6 +262,22 @@ type Asset struct {
 	// 风险评分
 	RiskScore float64 `json:"riskScore,omitempty"`
 	RiskLevel string  `json:"riskLevel,omitempty"`
+	// TLS证书
+	TLS *AssetTLS `json:"tls,omitempty"`
+}
+
+// AssetTLS 资产TLS证书摘要
+type AssetTLS struct {
+	Subject  string   `json:"subject"`
+	Issuer   string   `json:"issuer"`
+	NotAfter string   `json:"notAfter"`
+	SANs     []string `json:"sans,omitempty"`
+	KeyType  string   `json:"keyType"`
+	KeyBits  int      `json:"keyBits"`
+	Versions []string `json:"versions,omitempty"`
+	Findings []string `json:"findings,omitempty"`
+	JA3S     string   `json:"ja3s,omitempty"`
+	JARM     string   `json:"jarm,omitempty"`
 }
 
 type AssetListReq struct {
@@ -284,10 +386,71 @@ type AssetHistoryResp struct {
 	List []AssetHistoryItem `json:"list"`
 }
 
+// AssetDiffReq 资产对比请求，按任务对比或按时间对比二选一
+type AssetDiffReq struct {
+	BaseTaskId   string `json:"baseTaskId,optional"`   // 基准任务ID
+	TargetTaskId string `json:"targetTaskId,optional"` // 对比任务ID
+	StartTime    string `json:"startTime,optional"`    // 基准时间 2006-01-02 15:04:05
+	EndTime      string `json:"endTime,optional"`      // 对比时间，默认当前时间
+}
+
+type AssetDiffSummary struct {
+	NewHosts      int    `json:"newHosts"`
+	NewPorts      int    `json:"newPorts"`
+	ClosedPorts   int    `json:"closedPorts"`
+	ChangedAssets int    `json:"changedAssets"`
+	NewVuls       int    `json:"newVuls"`
+	ResolvedVuls  int    `json:"resolvedVuls"`
+	BaseTime      string `json:"baseTime"`
+	TargetTime    string `json:"targetTime"`
+}
+
+type AssetDiffAsset struct {
+	Authority string   `json:"authority"`
+	Host      string   `json:"host"`
+	Port      int      `json:"port"`
+	Service   string   `json:"service"`
+	Title     string   `json:"title"`
+	App       []string `json:"app"`
+}
+
+type AssetDiffField struct {
+	Field string `json:"field"` // service/title/app/banner/cert
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+type AssetDiffChange struct {
+	Authority string           `json:"authority"`
+	Host      string           `json:"host"`
+	Port      int              `json:"port"`
+	Fields    []AssetDiffField `json:"fields"`
+}
+
+type AssetDiffVul struct {
+	Authority string `json:"authority"`
+	Url       string `json:"url"`
+	PocFile   string `json:"pocFile"`
+	Severity  string `json:"severity"`
+}
+
+type AssetDiffResp struct {
+	Code         int               `json:"code"`
+	Msg          string            `json:"msg"`
+	Summary      AssetDiffSummary  `json:"summary"`
+	NewHosts     []string          `json:"newHosts"`
+	NewPorts     []AssetDiffAsset  `json:"newPorts"`
+	ClosedPorts  []AssetDiffAsset  `json:"closedPorts"`
+	Changes      []AssetDiffChange `json:"changes"`
+	NewVuls      []AssetDiffVul    `json:"newVuls"`
+	ResolvedVuls []AssetDiffVul    `json:"resolvedVuls"`
+}
+
 // ==================== 站点管理 ====================
 type SiteListReq struct {
 	Page       int    `json:"page,default=1"`
 	PageSize   int    `json:"pageSize,default=20"`
+	Query      string `json:"query,optional"`
 	Site       string `json:"site,optional"`
 	Title      string `json:"title,optional"`
 	App        string `json:"app,optional"`
@@ -340,6 +503,7 @@ type SiteBatchDeleteReq struct {
 type DomainListReq struct {
 	Page       int    `json:"page,default=1"`
 	PageSize   int    `json:"pageSize,default=20"`
+	Query      string `json:"query,optional"`
 	Domain     string `json:"domain,optional"`
 	RootDomain string `json:"rootDomain,optional"`
 	IP         string `json:"ip,optional"`
@@ -352,6 +516,9 @@ type Domain struct {
 	RootDomain string   `json:"rootDomain"`
 	IPs        []string `json:"ips"`
 	CName      string   `json:"cname"`
+	CDNName    string   `json:"cdnName,omitempty"`
+	WAF        string   `json:"waf,omitempty"`
+	CloudName  string   `json:"cloudName,omitempty"`
 	Source     string   `json:"source"`
 	OrgId      string   `json:"orgId,omitempty"`
 	OrgName    string   `json:"orgName,omitempty"`
@@ -386,6 +553,7 @@ type DomainBatchDeleteReq struct {
 type IPListReq struct {
 	Page     int    `json:"page,default=1"`
 	PageSize int    `json:"pageSize,default=20"`
+	Query    string `json:"query,optional"`
 	IP       string `json:"ip,optional"`
 	Port     string `json:"port,optional"`
 	Service  string `json:"service,optional"`
@@ -421,11 +589,13 @@ type IPListResp struct {
 }
 
 type IPStatResp struct {
-	Code         int `json:"code"`
-	Total        int `json:"total"`
-	PortCount    int `json:"portCount"`
-	ServiceCount int `json:"serviceCount"`
-	NewCount     int `json:"newCount"`
+	Code         int        `json:"code"`
+	Total        int        `json:"total"`
+	PortCount    int        `json:"portCount"`
+	ServiceCount int        `json:"serviceCount"`
+	NewCount     int        `json:"newCount"`
+	TopASN       []StatItem `json:"topAsn"`
+	TopCountry   []StatItem `json:"topCountry"`
 }
 
 type IPDeleteReq struct {
@@ -436,6 +606,142 @@ type IPBatchDeleteReq struct {
 	IPs []string `json:"ips"`
 }
 
+// ==================== 离线IP库 ====================
+type GeoIPDBStatus struct {
+	Type         string `json:"type"` // region/city/asn
+	File         string `json:"file"`
+	Loaded       bool   `json:"loaded"`
+	Size         int64  `json:"size"`
+	DatabaseType string `json:"databaseType,omitempty"`
+	BuildTime    string `json:"buildTime,omitempty"`
+	UpdateTime   string `json:"updateTime,omitempty"`
+	Error        string `json:"error,omitempty"`
+}
+
+type GeoIPInfoResp struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	List []GeoIPDBStatus `json:"list"`
+}
+
+type GeoIPUploadReq struct {
+	Type string `json:"type"`
+	Data []byte `json:"data"` // base64 编码的 .mmdb/.xdb 文件或 tar.gz 包
+}
+
+type GeoIPUpdateReq struct {
+	Type string `json:"type"`
+	Url  string `json:"url"` // 下载地址，如 MaxMind 带 license_key 的下载链接
+}
+
+type GeoIPLookupReq struct {
+	IP string `json:"ip"`
+}
+
+type GeoIPLookupResp struct {
+	Code     int    `json:"code"`
+	Msg      string `json:"msg"`
+	IP       string `json:"ip"`
+	Location string `json:"location"`
+	Country  string `json:"country"`
+	Region   string `json:"region"`
+	City     string `json:"city"`
+	ASN      int    `json:"asn"`
+	Org      string `json:"org"`
+	ISP      string `json:"isp"`
+}
+
+type GeoIPEnrichReq struct {
+	Force bool `json:"force,optional"` // 覆盖已有的归属信息
+}
+
+type GeoIPEnrichResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Updated int    `json:"updated"`
+}
+
+// ==================== OOB回连 ====================
+type InteractshConfig struct {
+	Mode           string `json:"mode"`                    // public/self/disable
+	ServerURL      string `json:"serverUrl,optional"`      // 自建服务地址
+	Token          string `json:"token,optional"`          // 自建服务鉴权token
+	PollInterval   int    `json:"pollInterval,optional"`   // 轮询间隔(秒)
+	CooldownPeriod int    `json:"cooldownPeriod,optional"` // 扫描结束后等待回连时间(秒)
+	Eviction       int    `json:"eviction,optional"`       // 请求记录保留时间(秒)
+	UpdateTime     string `json:"updateTime,optional"`
+}
+
+type InteractshConfigGetResp struct {
+	Code int               `json:"code"`
+	Msg  string            `json:"msg"`
+	Data *InteractshConfig `json:"data"`
+}
+
+// ==================== 截图画廊 ====================
+type ScreenshotGetReq struct {
+	Key string `form:"key"`
+}
+
+type ScreenshotAsset struct {
+	Id             string   `json:"id"`
+	WorkspaceId    string   `json:"workspaceId"`
+	Authority      string   `json:"authority"`
+	Host           string   `json:"host"`
+	Port           int      `json:"port"`
+	Title          string   `json:"title"`
+	HttpStatus     string   `json:"httpStatus"`
+	App            []string `json:"app"`
+	Screenshot     string   `json:"screenshot"`
+	ScreenshotHash string   `json:"screenshotHash"`
+	UpdateTime     string   `json:"updateTime"`
+}
+
+type ScreenshotCluster struct {
+	Hash           string            `json:"hash"` // 代表截图的哈希
+	Count          int               `json:"count"`
+	Representative ScreenshotAsset   `json:"representative"`
+	Assets         []ScreenshotAsset `json:"assets"` // 簇内资产（最多 MaxAssets 条）
+}
+
+type ScreenshotGalleryReq struct {
+	Page      int `json:"page,default=1"`
+	PageSize  int `json:"pageSize,default=20"`
+	Threshold int `json:"threshold,default=8"`  // 汉明距离阈值，0-64，越小越严格
+	MaxAssets int `json:"maxAssets,default=20"` // 每个簇返回的资产数
+	MinCount  int `json:"minCount,optional"`    // 只返回资产数不少于该值的簇
+}
+
+type ScreenshotGalleryResp struct {
+	Code  int                 `json:"code"`
+	Msg   string              `json:"msg"`
+	Total int                 `json:"total"` // 簇数量
+	Count int                 `json:"count"` // 带截图的资产数量
+	List  []ScreenshotCluster `json:"list"`
+}
+
+type ScreenshotSimilarReq struct {
+	Hash      string `json:"hash,optional"`
+	AssetId   string `json:"assetId,optional"` // 以指定资产的截图为基准
+	Threshold int    `json:"threshold,default=8"`
+	Page      int    `json:"page,default=1"`
+	PageSize  int    `json:"pageSize,default=20"`
+}
+
+type ScreenshotSimilarResp struct {
+	Code  int               `json:"code"`
+	Msg   string            `json:"msg"`
+	Total int               `json:"total"`
+	List  []ScreenshotAsset `json:"list"`
+}
+
+type ScreenshotMigrateResp struct {
+	Code     int    `json:"code"`
+	Msg      string `json:"msg"`
+	Migrated int    `json:"migrated"`
+	Failed   int    `json:"failed"`
+}
+
 // ==================== 任务管理 ====================
 type MainTask struct {
 	Id           string `json:"id"`
@@ -457,6 +763,9 @@ type MainTask struct {
 	SubTaskCount int    `json:"subTaskCount"` // 子任务总数
 	SubTaskDone  int    `json:"subTaskDone"`  // 已完成子任务数
 	WorkspaceId  string `json:"workspaceId"`  // 所属工作空间ID
+	NotifyId     string `json:"notifyId"`     // 通知渠道ID
+	DiffSummary  *AssetDiffSummary `json:"diffSummary,omitempty"` // 定时任务本轮资产变化
+	WindowPaused bool              `json:"windowPaused,omitempty"` // 因维护窗口关闭而暂停
 }
 
 type MainTaskListReq struct {
@@ -483,6 +792,7 @@ type MainTaskCreateReq struct {
 	CronRule    string   `json:"cronRule,optional"`
 	Workers     []string `json:"workers,optional"`     // 指定执行任务的 Worker 列表
 	WorkspaceId string   `json:"workspaceId,optional"` // 任务所属工作空间ID
+	NotifyId    string   `json:"notifyId,optional"`    // 任务完成/失败时通知的渠道ID
 }
 
 type TaskProfile struct {
@@ -557,6 +867,31 @@ type GetTaskLogsResp struct {
 	List []TaskLogEntry `json:"list"`
 }
 
+// DeadLetterTask 死信任务（租约多次过期后不再自动重试的子任务）
+type DeadLetterTask struct {
+	TaskId      string `json:"taskId"`
+	MainTaskId  string `json:"mainTaskId"`
+	WorkspaceId string `json:"workspaceId"`
+	TaskName    string `json:"taskName"`
+	Worker      string `json:"worker"`   // 最后一次领取任务的 Worker
+	Attempts    int    `json:"attempts"` // 投递次数
+	Reason      string `json:"reason"`
+	DeadTime    string `json:"deadTime"`
+}
+
+// DeadLetterListResp 死信任务列表响应
+type DeadLetterListResp struct {
+	Code  int              `json:"code"`
+	Msg   string           `json:"msg"`
+	Total int              `json:"total"`
+	List  []DeadLetterTask `json:"list"`
+}
+
+// DeadLetterReq 死信任务操作请求（重放/删除）
+type DeadLetterReq struct {
+	TaskIds []string `json:"taskIds"`
+}
+
 // ==================== 漏洞管理 ====================
 type Vul struct {
 	Id         string `json:"id"`
@@ -571,6 +906,25 @@ type Vul struct {
 	FirstSeenTime string `json:"firstSeenTime,omitempty"`
 	LastSeenTime  string `json:"lastSeenTime,omitempty"`
 	ScanCount     int    `json:"scanCount,omitempty"`
+	// 处置流程
+	Status   string `json:"status"`
+	Assignee string `json:"assignee"`
+}
+
+// VulComment 漏洞评论
+type VulComment struct {
+	Author     string `json:"author"`
+	Content    string `json:"content"`
+	CreateTime string `json:"createTime"`
+}
+
+// VulStatusChange 漏洞状态变更记录
+type VulStatusChange struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Operator string `json:"operator"`
+	Reason   string `json:"reason"`
+	Time     string `json:"time"`
 }
 
 // VulEvidence 漏洞证据链
@@ -581,6 +935,21 @@ type VulEvidence struct {
 	Request           string   `json:"request,omitempty"`
 	Response          string   `json:"response,omitempty"`
 	ResponseTruncated bool     `json:"responseTruncated,omitempty"`
+
+	Interaction *VulInteraction `json:"interaction,omitempty"` // OOB 回连证据
+}
+
+// VulInteraction OOB 回连交互记录
+type VulInteraction struct {
+	Protocol      string `json:"protocol"`
+	UniqueId      string `json:"uniqueId"`
+	FullId        string `json:"fullId"`
+	QType         string `json:"qType,omitempty"`
+	RawRequest    string `json:"rawRequest,omitempty"`
+	RawResponse   string `json:"rawResponse,omitempty"`
+	RemoteAddress string `json:"remoteAddress"`
+	SMTPFrom      string `json:"smtpFrom,omitempty"`
+	Time          string `json:"time"`
 }
 
 // VulDetail 漏洞详情（包含知识库信息和证据链）
@@ -607,6 +976,11 @@ type VulDetail struct {
 	FirstSeenTime string `json:"firstSeenTime,omitempty"`
 	LastSeenTime  string `json:"lastSeenTime,omitempty"`
 	ScanCount     int    `json:"scanCount,omitempty"`
+	// 处置流程
+	Status        string            `json:"status"`
+	Assignee      string            `json:"assignee"`
+	Comments      []VulComment      `json:"comments"`
+	StatusHistory []VulStatusChange `json:"statusHistory"`
 }
 
 // VulDetailReq 漏洞详情请求
@@ -624,11 +998,14 @@ type VulDetailResp struct {
 type VulListReq struct {
 	Page      int    `json:"page,default=1"`
 	PageSize  int    `json:"pageSize,default=20"`
+	Query     string `json:"query,optional"`
 	Authority string `json:"authority,optional"`
 	Severity  string `json:"severity,optional"`
 	Source    string `json:"source,optional"`
 	Host      string `json:"host,optional"`
 	Port      int    `json:"port,optional"`
+	Status    string `json:"status,optional"`   // 处置状态，多个用逗号分隔
+	Assignee  string `json:"assignee,optional"` // 处理人
 }
 
 type VulListResp struct {
@@ -646,6 +1023,11 @@ type VulBatchDeleteReq struct {
 	Ids []string `json:"ids"`
 }
 
+// VulStatReq 漏洞统计请求
+type VulStatReq struct {
+	Status string `json:"status,optional"` // 处置状态，多个用逗号分隔
+}
+
 // VulStatResp 漏洞统计响应
 type VulStatResp struct {
 	Code     int `json:"code"`
@@ -658,6 +1040,60 @@ type VulStatResp struct {
 	Info     int `json:"info"`
 	Week     int `json:"week"`   // 近7天
 	Month    int `json:"month"`  // 近30天
+	StatusCount map[string]int `json:"statusCount"` // 各处置状态数量
+}
+
+// VulStatusUpdateReq 批量变更漏洞状态
+type VulStatusUpdateReq struct {
+	Ids      []string `json:"ids"`
+	Status   string   `json:"status"`
+	Reason   string   `json:"reason,optional"`
+	Suppress bool     `json:"suppress,optional"` // 标记误报时同时创建 模板+主机 抑制规则
+}
+
+// VulAssignReq 批量指派处理人
+type VulAssignReq struct {
+	Ids      []string `json:"ids"`
+	Assignee string   `json:"assignee,optional"` // 为空表示取消指派
+}
+
+// VulCommentReq 添加漏洞评论
+type VulCommentReq struct {
+	Id      string `json:"id"`
+	Content string `json:"content"`
+}
+
+// VulSuppressRule 漏洞误报抑制规则
+type VulSuppressRule struct {
+	Id         string `json:"id"`
+	PocFile    string `json:"pocFile"`
+	Host       string `json:"host"`
+	Pattern    string `json:"pattern"`
+	Reason     string `json:"reason"`
+	Status     string `json:"status"`
+	HitCount   int    `json:"hitCount"`
+	LastHit    string `json:"lastHit"`
+	CreateUser string `json:"createUser"`
+	CreateTime string `json:"createTime"`
+}
+
+type VulSuppressRuleListResp struct {
+	Code int               `json:"code"`
+	Msg  string            `json:"msg"`
+	List []VulSuppressRule `json:"list"`
+}
+
+type VulSuppressRuleSaveReq struct {
+	Id      string `json:"id,optional"`
+	PocFile string `json:"pocFile"`
+	Host    string `json:"host,optional"`
+	Pattern string `json:"pattern,optional"`
+	Reason  string `json:"reason,optional"`
+	Status  string `json:"status,optional"`
+}
+
+type VulSuppressRuleDeleteReq struct {
+	Id string `json:"id"`
 }
 
 // TaskStatResp 任务统计响应
@@ -687,6 +1123,7 @@ type Worker struct {
 	Status       string            `json:"status"`
 	UpdateTime   string            `json:"updateTime"`
 	Tools        map[string]bool   `json:"tools"`        // 工具安装状态
+	Labels       []string          `json:"labels"`       // Worker 标签（key=value）
 }
 
 type WorkerListResp struct {
@@ -1394,8 +1831,49 @@ type ReportDetailResp struct {
 }
 
 type ReportExportReq struct {
-	TaskId string `json:"taskId"`
-	Format string `json:"format,optional"` // excel, pdf (默认excel)
+	TaskId     string `json:"taskId"`
+	Format     string `json:"format,optional"`     // excel, html, pdf, markdown, json, csv (默认excel)
+	TemplateId string `json:"templateId,optional"` // 自定义模板ID，为空时使用工作空间默认模板
+}
+
+type ReportTemplate struct {
+	Id          string `json:"id"`
+	Name        string `json:"name"`
+	Format      string `json:"format"`
+	Content     string `json:"content"`
+	Description string `json:"description"`
+	IsDefault   bool   `json:"isDefault"`
+	CreateTime  string `json:"createTime"`
+	UpdateTime  string `json:"updateTime"`
+}
+
+type ReportTemplateListResp struct {
+	Code int              `json:"code"`
+	Msg  string           `json:"msg"`
+	List []ReportTemplate `json:"list"`
+}
+
+type ReportTemplateSaveReq struct {
+	Id          string `json:"id,optional"`
+	Name        string `json:"name"`
+	Format      string `json:"format"` // html, markdown
+	Content     string `json:"content"`
+	Description string `json:"description,optional"`
+	IsDefault   bool   `json:"isDefault,optional"`
+}
+
+type ReportTemplateIdReq struct {
+	Id string `json:"id"`
+}
+
+type ReportTemplateDefaultReq struct {
+	Format string `json:"format"`
+}
+
+type ReportTemplateDefaultResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Content string `json:"content"`
 }
 
 // ==================== 用户扫描配置 ====================
@@ -1448,6 +1926,136 @@ type SubfinderProviderInfoResp struct {
 	List []SubfinderProviderMeta `json:"list"`
 }
 
+// ==================== 消息通知 ====================
+type NotifyChannel struct {
+	Id         string            `json:"id"`
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`   // webhook/dingtalk/feishu/wecom/slack/email
+	Events     []string          `json:"events"` // task_complete/task_failed/vul_found/worker_offline
+	Status     string            `json:"status"` // enable/disable
+	WebhookUrl string            `json:"webhookUrl"`
+	Secret     string            `json:"secret"` // 脱敏后
+	Headers    map[string]string `json:"headers"`
+	SmtpHost   string            `json:"smtpHost"`
+	SmtpPort   int               `json:"smtpPort"`
+	SmtpUser   string            `json:"smtpUser"`
+	SmtpPass   string            `json:"smtpPass"` // 脱敏后
+	SmtpFrom   string            `json:"smtpFrom"`
+	SmtpTo     []string          `json:"smtpTo"`
+	SmtpSsl    bool              `json:"smtpSsl"`
+	CreateTime string            `json:"createTime"`
+	UpdateTime string            `json:"updateTime"`
+}
+
+type NotifyChannelListResp struct {
+	Code int             `json:"code"`
+	Msg  string          `json:"msg"`
+	List []NotifyChannel `json:"list"`
+}
+
+type NotifyChannelSaveReq struct {
+	Id         string            `json:"id,optional"`
+	Name       string            `json:"name"`
+	Type       string            `json:"type"`
+	Events     []string          `json:"events,optional"`
+	Status     string            `json:"status,optional"`
+	WebhookUrl string            `json:"webhookUrl,optional"`
+	Secret     string            `json:"secret,optional"`
+	Headers    map[string]string `json:"headers,optional"`
+	SmtpHost   string            `json:"smtpHost,optional"`
+	SmtpPort   int               `json:"smtpPort,optional"`
+	SmtpUser   string            `json:"smtpUser,optional"`
+	SmtpPass   string            `json:"smtpPass,optional"`
+	SmtpFrom   string            `json:"smtpFrom,optional"`
+	SmtpTo     []string          `json:"smtpTo,optional"`
+	SmtpSsl    bool              `json:"smtpSsl,optional"`
+}
+
+type NotifyChannelIdReq struct {
+	Id string `json:"id"`
+}
+
+type NotifyChannelTestReq struct {
+	Id string `json:"id"`
+}
+
+// ==================== Webhook 事件订阅 ====================
+type WebhookSubscription struct {
+	Id           string            `json:"id"`
+	Name         string            `json:"name"`
+	Url          string            `json:"url"`
+	Secret       string            `json:"secret"`       // 脱敏后
+	Events       []string          `json:"events"`       // 为空表示全部
+	WorkspaceIds []string          `json:"workspaceIds"` // 为空表示全部
+	Headers      map[string]string `json:"headers"`
+	Status       string            `json:"status"`
+	CreateTime   string            `json:"createTime"`
+	UpdateTime   string            `json:"updateTime"`
+}
+
+type WebhookListResp struct {
+	Code   int                   `json:"code"`
+	Msg    string                `json:"msg"`
+	List   []WebhookSubscription `json:"list"`
+	Events []string              `json:"events"` // 可订阅的事件
+}
+
+type WebhookSaveReq struct {
+	Id           string            `json:"id,optional"`
+	Name         string            `json:"name"`
+	Url          string            `json:"url"`
+	Secret       string            `json:"secret,optional"`
+	Events       []string          `json:"events,optional"`
+	WorkspaceIds []string          `json:"workspaceIds,optional"`
+	Headers      map[string]string `json:"headers,optional"`
+	Status       string            `json:"status,optional"`
+}
+
+type WebhookIdReq struct {
+	Id string `json:"id"`
+}
+
+type WebhookDelivery struct {
+	Id               string `json:"id"`
+	SubscriptionId   string `json:"subscriptionId"`
+	SubscriptionName string `json:"subscriptionName"`
+	EventId          string `json:"eventId"`
+	Event            string `json:"event"`
+	WorkspaceId      string `json:"workspaceId"`
+	Payload          string `json:"payload"`
+	Status           string `json:"status"`
+	Attempts         int    `json:"attempts"`
+	NextRetryTime    string `json:"nextRetryTime"`
+	ResponseCode     int    `json:"responseCode"`
+	ResponseBody     string `json:"responseBody"`
+	LastError        string `json:"lastError"`
+	Duration         int64  `json:"duration"`
+	ReplayOf         string `json:"replayOf"`
+	CreateTime       string `json:"createTime"`
+	UpdateTime       string `json:"updateTime"`
+}
+
+type WebhookDeliveryListReq struct {
+	Page           int    `json:"page,default=1"`
+	PageSize       int    `json:"pageSize,default=20"`
+	SubscriptionId string `json:"subscriptionId,optional"`
+	Event          string `json:"event,optional"`
+	Status         string `json:"status,optional"`
+}
+
+type WebhookDeliveryListResp struct {
+	Code  int               `json:"code"`
+	Msg   string            `json:"msg"`
+	Total int64             `json:"total"`
+	List  []WebhookDelivery `json:"list"`
+}
+
+type WebhookDeliveryResp struct {
+	Code int              `json:"code"`
+	Msg  string           `json:"msg"`
+	Data *WebhookDelivery `json:"data,omitempty"`
+}
+
 // ==================== AI辅助 ====================
 
 type GeneratePocReq struct {
@@ -1709,3 +2317,110 @@ type DirScanDictSimple struct {
 	PathCount int    `json:"pathCount"`
 	IsBuiltin bool   `json:"isBuiltin"`
 }
+
+// ==================== 弱口令爆破字典 ====================
+
+// BruteDict 弱口令爆破字典
+type BruteDict struct {
+	Id          string   `json:"id"`
+	Name        string   `json:"name"`        // 字典名称
+	Description string   `json:"description"` // 描述
+	Type        string   `json:"type"`        // username/password
+	Services    []string `json:"services"`    // 适用的服务，为空表示全部
+	Content     string   `json:"content"`     // 字典内容（每行一个）
+	WordCount   int      `json:"wordCount"`   // 条目数量
+	Enabled     bool     `json:"enabled"`     // 是否启用
+	CreateTime  string   `json:"createTime"`
+	UpdateTime  string   `json:"updateTime"`
+}
+
+// BruteDictListReq 爆破字典列表请求
+type BruteDictListReq struct {
+	Page     int    `json:"page,default=1"`
+	PageSize int    `json:"pageSize,default=20"`
+	Type     string `json:"type,optional"` // 类型筛选
+}
+
+// BruteDictListResp 爆破字典列表响应
+type BruteDictListResp struct {
+	Code  int         `json:"code"`
+	Msg   string      `json:"msg"`
+	Total int         `json:"total"`
+	List  []BruteDict `json:"list"`
+}
+
+// BruteDictSaveReq 保存爆破字典请求
+type BruteDictSaveReq struct {
+	Id          string   `json:"id,optional"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,optional"`
+	Type        string   `json:"type"`
+	Services    []string `json:"services,optional"`
+	Content     string   `json:"content"`
+	Enabled     bool     `json:"enabled"`
+}
+
+// BruteDictDeleteReq 删除爆破字典请求
+type BruteDictDeleteReq struct {
+	Id string `json:"id"`
+}
+
+// BruteDictEnabledListResp 启用的爆破字典列表响应（用于任务创建时选择）
+type BruteDictEnabledListResp struct {
+	Code int               `json:"code"`
+	Msg  string            `json:"msg"`
+	List []BruteDictSimple `json:"list"`
+}
+
+// BruteDictSimple 简化的爆破字典信息（用于选择列表）
+type BruteDictSimple struct {
+	Id        string   `json:"id"`
+	Name      string   `json:"name"`
+	Type      string   `json:"type"`
+	Services  []string `json:"services"`
+	WordCount int      `json:"wordCount"`
+}
+
+// DNSRecord DNS记录
+type DNSRecord struct {
+	Id         string `json:"id"`
+	Domain     string `json:"domain"`
+	Root       string `json:"root"` // 根域名
+	Type       string `json:"type"`
+	Value      string `json:"value"`
+	TTL        uint32 `json:"ttl"`
+	Source     string `json:"source"` // query/axfr
+	CreateTime string `json:"createTime"`
+	UpdateTime string `json:"updateTime"`
+}
+
+// DNSRecordListReq DNS记录列表请求
+type DNSRecordListReq struct {
+	Page     int    `json:"page,default=1"`
+	PageSize int    `json:"pageSize,default=20"`
+	Domain   string `json:"domain,optional"` // 域名关键词
+	Root     string `json:"root,optional"`
+	Type     string `json:"type,optional"`
+	Value    string `json:"value,optional"` // 记录值关键词
+	Source   string `json:"source,optional"`
+}
+
+// DNSRecordListResp DNS记录列表响应
+type DNSRecordListResp struct {
+	Code  int         `json:"code"`
+	Msg   string      `json:"msg"`
+	Total int         `json:"total"`
+	List  []DNSRecord `json:"list"`
+}
+
+// DNSRecordStatResp DNS记录按类型统计响应
+type DNSRecordStatResp struct {
+	Code int              `json:"code"`
+	Msg  string           `json:"msg"`
+	Stat map[string]int64 `json:"stat"`
+}
+
+// DNSRecordDeleteReq 删除DNS记录请求
+type DNSRecordDeleteReq struct {
+	Ids []string `json:"ids"`
+}
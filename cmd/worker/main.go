@@ -15,6 +15,7 @@ import (
 	"syscall"
 	"time"
 
+	"cscan/scheduler"
 	"cscan/worker"
 
 	"github.com/zeromicro/go-zero/core/logx"
@@ -28,6 +29,13 @@ var (
 	concurrency = flag.Int("c", getEnvIntOrDefault("CSCAN_CONCURRENCY", 5), "concurrency")
 	installKey  = flag.String("k", getEnvOrDefault("CSCAN_KEY", ""), "install key for authentication")
 
+	// Worker 标签，任务通过标签选择器（如 zone=dmz,tool=masscan）匹配 Worker
+	region    = flag.String("region", getEnvOrDefault("CSCAN_REGION", ""), "worker region label (e.g., cn-east)")
+	zone      = flag.String("zone", getEnvOrDefault("CSCAN_ZONE", ""), "worker network zone label (e.g., dmz)")
+	vantage   = flag.String("vantage", getEnvOrDefault("CSCAN_VANTAGE", ""), "worker vantage point label: internal or external")
+	labels    = flag.String("labels", getEnvOrDefault("CSCAN_LABELS", ""), "custom worker labels, comma separated key=value (e.g., team=red,isp=ctcc)")
+	labelOnly = flag.Bool("label-only", getEnvOrDefault("CSCAN_LABEL_ONLY", "") == "true", "only run tasks whose label selector matches this worker")
+
 	// 废弃参数（保留兼容性，但会输出警告）
 	redisAddr = flag.String("r", "", "[DEPRECATED] redis address - no longer needed, will be ignored")
 	redisPass = flag.String("rp", "", "[DEPRECATED] redis password - no longer needed, will be ignored")
@@ -52,6 +60,20 @@ func getEnvIntOrDefault(key string, defaultVal int) int {
 	return defaultVal
 }
 
+// parseWorkerLabels 合并 -region、-zone、-vantage 和 -labels 指定的标签
+func parseWorkerLabels() ([]string, error) {
+	if *vantage != "" && *vantage != "internal" && *vantage != "external" {
+		return nil, fmt.Errorf("invalid vantage %q, expected internal or external", *vantage)
+	}
+	items := []string{*labels}
+	for key, value := range map[string]string{"region": *region, "zone": *zone, "vantage": *vantage} {
+		if value != "" {
+			items = append(items, key+"="+value)
+		}
+	}
+	return scheduler.ParseLabels(strings.Join(items, ","))
+}
+
 // validateInstallKey 验证安装密钥
 func validateInstallKey(apiServer, key, name string) error {
 	reqBody := map[string]string{
@@ -151,6 +173,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	// 解析Worker标签
+	workerLabels, err := parseWorkerLabels()
+	if err != nil {
+		fmt.Printf("[Worker] Error: %v\n", err)
+		os.Exit(1)
+	}
+
 	// 获取本机IP
 	ip := worker.GetLocalIP()
 
@@ -161,6 +190,8 @@ func main() {
 		InstallKey:  *installKey,
 		Concurrency: *concurrency,
 		Timeout:     3600,
+		Labels:      workerLabels,
+		LabelOnly:   *labelOnly,
 	}
 
 	w, err := worker.NewWorker(config)
@@ -177,6 +208,12 @@ func main() {
 	fmt.Printf("  IP: %s\n", ip)
 	fmt.Printf("  API Server: %s\n", apiServer)
 	fmt.Printf("  Concurrency: %d\n", *concurrency)
+	if len(workerLabels) > 0 {
+		fmt.Printf("  Labels: %s\n", strings.Join(workerLabels, ","))
+	}
+	if *labelOnly {
+		fmt.Printf("  Label Only: true\n")
+	}
 
 	// 等待退出信号
 	quit := make(chan os.Signal, 1)
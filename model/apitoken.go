@@ -0,0 +1,150 @@
+package model
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ApiTokenPrefix API Token 前缀，用于和 JWT 区分
+const ApiTokenPrefix = "cst_"
+
+// API Token 类型
+const (
+	ApiTokenTypePersonal = "personal" // 个人Token，继承用户的角色和工作空间
+	ApiTokenTypeService  = "service"  // 服务Token，不依赖具体用户，使用自身配置的工作空间
+)
+
+// API Token 权限范围
+const (
+	ApiTokenScopeRead       = "read"        // 只读
+	ApiTokenScopeTaskCreate = "task:create" // 创建和启动任务
+	ApiTokenScopeAssetWrite = "asset:write" // 导入和维护资产
+)
+
+// ApiTokenScopes 全部权限范围
+var ApiTokenScopes = []string{ApiTokenScopeRead, ApiTokenScopeTaskCreate, ApiTokenScopeAssetWrite}
+
+// IsValidApiTokenScope 是否为合法权限范围
+func IsValidApiTokenScope(scope string) bool {
+	for _, s := range ApiTokenScopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// ApiToken API访问令牌，明文只在创建时返回一次，数据库中只保存哈希
+type ApiToken struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name"`
+	Type         string             `bson:"type" json:"type"`                // personal/service
+	TokenHash    string             `bson:"token_hash" json:"-"`             // SHA-256
+	TokenPrefix  string             `bson:"token_prefix" json:"tokenPrefix"` // 明文前几位，便于识别
+	Scopes       []string           `bson:"scopes" json:"scopes"`            // 权限范围
+	UserId       string             `bson:"user_id" json:"userId"`           // 个人Token所属用户 / 服务Token创建人
+	Username     string             `bson:"username" json:"username"`
+	WorkspaceIds []string           `bson:"workspace_ids,omitempty" json:"workspaceIds"` // 服务Token可访问的工作空间
+	ExpireTime   *time.Time         `bson:"expire_time,omitempty" json:"expireTime"`     // 为空表示永不过期
+	LastUsedTime *time.Time         `bson:"last_used_time,omitempty" json:"lastUsedTime"`
+	LastUsedIP   string             `bson:"last_used_ip,omitempty" json:"lastUsedIp"`
+	CreateTime   time.Time          `bson:"create_time" json:"createTime"`
+}
+
+// Expired 是否已过期
+func (t *ApiToken) Expired() bool {
+	return t.ExpireTime != nil && time.Now().After(*t.ExpireTime)
+}
+
+// HasScope 是否拥有权限范围
+func (t *ApiToken) HasScope(scope string) bool {
+	for _, s := range t.Scopes {
+		if s == scope {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateApiToken 生成Token明文
+func GenerateApiToken() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return ApiTokenPrefix + hex.EncodeToString(b), nil
+}
+
+// HashApiToken 计算Token哈希
+func HashApiToken(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+// ApiTokenModel API Token模型
+type ApiTokenModel struct {
+	*BaseModel[ApiToken]
+}
+
+// NewApiTokenModel 创建API Token模型
+func NewApiTokenModel(db *mongo.Database) *ApiTokenModel {
+	coll := db.Collection("api_token")
+	m := &ApiTokenModel{
+		BaseModel: NewBaseModel[ApiToken](coll),
+	}
+
+	ctx := context.Background()
+	m.EnsureIndexes(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "token_hash", Value: 1}}},
+		{Keys: bson.D{{Key: "user_id", Value: 1}}},
+	})
+
+	return m
+}
+
+// Create 保存Token，doc.TokenHash 需已设置
+func (m *ApiTokenModel) Create(ctx context.Context, doc *ApiToken) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	doc.CreateTime = time.Now()
+	return m.Insert(ctx, doc)
+}
+
+// FindByHash 根据哈希查找Token，不存在时返回 nil, nil
+func (m *ApiTokenModel) FindByHash(ctx context.Context, hash string) (*ApiToken, error) {
+	token, err := m.FindOne(ctx, bson.M{"token_hash": hash})
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	return token, err
+}
+
+// FindByUser 查找用户创建的Token，userId 为空时返回全部
+func (m *ApiTokenModel) FindByUser(ctx context.Context, userId string) ([]ApiToken, error) {
+	filter := bson.M{}
+	if userId != "" {
+		filter["user_id"] = userId
+	}
+	return m.FindWithSort(ctx, filter, 0, 0, "create_time", -1)
+}
+
+// UpdateLastUsed 记录最后使用时间和来源IP
+func (m *ApiTokenModel) UpdateLastUsed(ctx context.Context, id primitive.ObjectID, ip string) error {
+	return m.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"last_used_time": time.Now(),
+		"last_used_ip":   ip,
+	})
+}
+
+// DeleteByUser 删除用户的全部Token
+func (m *ApiTokenModel) DeleteByUser(ctx context.Context, userId string) (int64, error) {
+	return m.DeleteMany(ctx, bson.M{"user_id": userId})
+}
@@ -14,11 +14,23 @@ type IPV4 struct {
 	IPName   string `bson:"ip" json:"ip"`
 	IPInt    uint32 `bson:"uint32" json:"uint32"`
 	Location string `bson:"location" json:"location"`
+	Country  string `bson:"country,omitempty" json:"country,omitempty"`
+	Region   string `bson:"region,omitempty" json:"region,omitempty"`
+	City     string `bson:"city,omitempty" json:"city,omitempty"`
+	ASN      int    `bson:"asn,omitempty" json:"asn,omitempty"`
+	Org      string `bson:"org,omitempty" json:"org,omitempty"` // ASN 所属组织
+	ISP      string `bson:"isp,omitempty" json:"isp,omitempty"`
 }
 
 type IPV6 struct {
 	IPName   string `bson:"ip" json:"ip"`
 	Location string `bson:"location" json:"location"`
+	Country  string `bson:"country,omitempty" json:"country,omitempty"`
+	Region   string `bson:"region,omitempty" json:"region,omitempty"`
+	City     string `bson:"city,omitempty" json:"city,omitempty"`
+	ASN      int    `bson:"asn,omitempty" json:"asn,omitempty"`
+	Org      string `bson:"org,omitempty" json:"org,omitempty"`
+	ISP      string `bson:"isp,omitempty" json:"isp,omitempty"`
 }
 
 type IP struct {
@@ -27,46 +39,79 @@ type IP struct {
 }
 
 type Asset struct {
-	Id            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Authority     string             `bson:"authority" json:"authority"`
-	Host          string             `bson:"host" json:"host"`
-	Port          int                `bson:"port" json:"port"`
-	Category      string             `bson:"category" json:"category"`
-	Ip            IP                 `bson:"ip" json:"ip"`
-	Domain        string             `bson:"domain,omitempty" json:"domain"`
-	Service       string             `bson:"service,omitempty" json:"service"`
-	Server        string             `bson:"server,omitempty" json:"server"`
-	Banner        string             `bson:"banner,omitempty" json:"banner"`
-	Title         string             `bson:"title,omitempty" json:"title"`
-	App           []string           `bson:"app,omitempty" json:"app"`
-	HttpStatus    string             `bson:"status,omitempty" json:"httpStatus"`
-	HttpHeader    string             `bson:"header,omitempty" json:"httpHeader"`
-	HttpBody      string             `bson:"body,omitempty" json:"httpBody"`
-	Cert          string             `bson:"cert,omitempty" json:"cert"`
-	IconHash      string             `bson:"icon_hash,omitempty" json:"iconHash"`
-	IconHashFile  string             `bson:"icon_hash_file,omitempty" json:"iconHashFile"`
-	IconHashBytes []byte             `bson:"icon_hash_bytes,omitempty" json:"-"`
-	Screenshot    string             `bson:"screenshot,omitempty" json:"screenshot"`
-	OrgId         string             `bson:"org_id,omitempty" json:"orgId"`
-	ColorTag      string             `bson:"color,omitempty" json:"colorTag"`
-	Memo          string             `bson:"memo,omitempty" json:"memo"`
-	IsCDN         bool               `bson:"cdn,omitempty" json:"isCdn"`
-	CName         string             `bson:"cname,omitempty" json:"cname"`
-	IsCloud       bool               `bson:"cloud,omitempty" json:"isCloud"`
-	IsHTTP        bool               `bson:"is_http" json:"isHttp"`
-	IsNewAsset    bool               `bson:"new" json:"isNew"`
-	IsUpdated     bool               `bson:"update" json:"isUpdated"`
-	TaskId        string             `bson:"taskId" json:"taskId"`
-	LastTaskId    string             `bson:"last_task_id,omitempty" json:"lastTaskId"` // 上一个发现此资产的任务ID
-	Source        string             `bson:"source,omitempty" json:"source"`
-	CreateTime    time.Time          `bson:"create_time" json:"createTime"`
-	UpdateTime    time.Time          `bson:"update_time" json:"updateTime"`
+	Id             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Authority      string             `bson:"authority" json:"authority"`
+	Host           string             `bson:"host" json:"host"`
+	Port           int                `bson:"port" json:"port"`
+	Category       string             `bson:"category" json:"category"`
+	Ip             IP                 `bson:"ip" json:"ip"`
+	Domain         string             `bson:"domain,omitempty" json:"domain"`
+	Service        string             `bson:"service,omitempty" json:"service"`
+	Server         string             `bson:"server,omitempty" json:"server"`
+	Banner         string             `bson:"banner,omitempty" json:"banner"`
+	Title          string             `bson:"title,omitempty" json:"title"`
+	App            []string           `bson:"app,omitempty" json:"app"`
+	HttpStatus     string             `bson:"status,omitempty" json:"httpStatus"`
+	HttpHeader     string             `bson:"header,omitempty" json:"httpHeader"`
+	HttpBody       string             `bson:"body,omitempty" json:"httpBody"`
+	Cert           string             `bson:"cert,omitempty" json:"cert"`
+	IconHash       string             `bson:"icon_hash,omitempty" json:"iconHash"`
+	IconHashFile   string             `bson:"icon_hash_file,omitempty" json:"iconHashFile"`
+	IconHashBytes  []byte             `bson:"icon_hash_bytes,omitempty" json:"-"`
+	Screenshot     string             `bson:"screenshot,omitempty" json:"screenshot"`                    // 截图对象存储key（旧数据为base64）
+	ScreenshotHash string             `bson:"screenshot_hash,omitempty" json:"screenshotHash,omitempty"` // 截图感知哈希
+	OrgId          string             `bson:"org_id,omitempty" json:"orgId"`
+	ColorTag       string             `bson:"color,omitempty" json:"colorTag"`
+	Memo           string             `bson:"memo,omitempty" json:"memo"`
+	IsCDN          bool               `bson:"cdn,omitempty" json:"isCdn"`
+	CName          string             `bson:"cname,omitempty" json:"cname"`
+	IsCloud        bool               `bson:"cloud,omitempty" json:"isCloud"`
+	CDNName        string             `bson:"cdn_name,omitempty" json:"cdnName,omitempty"`     // CDN厂商
+	WAF            string             `bson:"waf,omitempty" json:"waf,omitempty"`              // WAF厂商
+	CloudName      string             `bson:"cloud_name,omitempty" json:"cloudName,omitempty"` // 云厂商
+	IsHTTP         bool               `bson:"is_http" json:"isHttp"`
+	Transport      string             `bson:"transport,omitempty" json:"transport,omitempty"` // 传输层协议，空为tcp
+	TLS            *TLSInfo           `bson:"tls,omitempty" json:"tls,omitempty"`             // TLS 证书和握手信息
+	IsNewAsset     bool               `bson:"new" json:"isNew"`
+	IsUpdated      bool               `bson:"update" json:"isUpdated"`
+	TaskId         string             `bson:"taskId" json:"taskId"`
+	LastTaskId     string             `bson:"last_task_id,omitempty" json:"lastTaskId"` // 上一个发现此资产的任务ID
+	Source         string             `bson:"source,omitempty" json:"source"`
+	CreateTime     time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime     time.Time          `bson:"update_time" json:"updateTime"`
 
 	// 新增字段 - 风险评分
 	RiskScore float64 `bson:"risk_score,omitempty" json:"riskScore,omitempty"` // 0-100
 	RiskLevel string  `bson:"risk_level,omitempty" json:"riskLevel,omitempty"` // critical/high/medium/low/info/unknown
 }
 
+// TLSInfo TLS 证书和握手信息，json 字段与 Worker 上报格式一致
+type TLSInfo struct {
+	Versions     []string  `bson:"versions" json:"versions"`
+	CipherSuites []string  `bson:"cipher_suites" json:"cipherSuites"`
+	ALPN         string    `bson:"alpn,omitempty" json:"alpn,omitempty"`
+	JA3S         string    `bson:"ja3s,omitempty" json:"ja3s,omitempty"`
+	JARM         string    `bson:"jarm,omitempty" json:"jarm,omitempty"`
+	Chain        []TLSCert `bson:"chain" json:"chain"` // 第一个为站点证书
+	Findings     []string  `bson:"findings,omitempty" json:"findings,omitempty"`
+}
+
+// TLSCert 证书信息
+type TLSCert struct {
+	Subject            string    `bson:"subject" json:"subject"`
+	Issuer             string    `bson:"issuer" json:"issuer"`
+	SerialNumber       string    `bson:"serial_number" json:"serialNumber"`
+	NotBefore          time.Time `bson:"not_before" json:"notBefore"`
+	NotAfter           time.Time `bson:"not_after" json:"notAfter"`
+	SANs               []string  `bson:"sans,omitempty" json:"sans,omitempty"`
+	KeyType            string    `bson:"key_type" json:"keyType"`
+	KeyBits            int       `bson:"key_bits" json:"keyBits"`
+	SignatureAlgorithm string    `bson:"signature_algorithm" json:"signatureAlgorithm"`
+	SHA256             string    `bson:"sha256" json:"sha256"`
+	IsCA               bool      `bson:"is_ca" json:"isCa"`
+	SelfSigned         bool      `bson:"self_signed" json:"selfSigned"`
+}
+
 type AssetModel struct {
 	coll *mongo.Collection
 }
@@ -84,6 +129,8 @@ func NewAssetModel(db *mongo.Database, workspaceId string) *AssetModel {
 		{Keys: bson.D{{Key: "app", Value: 1}}},
 		// 新增索引 - 支持按风险评分排序
 		{Keys: bson.D{{Key: "risk_score", Value: -1}}},
+		// 截图相似度聚类
+		{Keys: bson.D{{Key: "screenshot_hash", Value: 1}}, Options: options.Index().SetSparse(true)},
 	}
 	coll.Indexes().CreateMany(ctx, indexes)
 
@@ -148,6 +195,20 @@ func (m *AssetModel) FindByHostPort(ctx context.Context, host string, port int)
 	return &doc, nil
 }
 
+// FindByHostPortTransport 按host:port和传输层协议查找资产，transport 为空表示tcp
+func (m *AssetModel) FindByHostPortTransport(ctx context.Context, host string, port int, transport string) (*Asset, error) {
+	var doc Asset
+	filter := bson.M{"host": host, "port": port, "transport": transport}
+	if transport == "" {
+		filter["transport"] = bson.M{"$in": bson.A{nil, ""}}
+	}
+	err := m.coll.FindOne(ctx, filter).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
 func (m *AssetModel) Find(ctx context.Context, filter bson.M, page, pageSize int) ([]Asset, error) {
 	return m.FindWithSort(ctx, filter, page, pageSize, "update_time")
 }
@@ -173,6 +234,64 @@ func (m *AssetModel) FindWithSort(ctx context.Context, filter bson.M, page, page
 	return docs, nil
 }
 
+// briefProjection 排除资产中体积较大的字段（响应体、截图、图标等）
+var briefProjection = bson.M{
+	"body":            0,
+	"header":          0,
+	"screenshot":      0,
+	"icon_hash_bytes": 0,
+}
+
+// FindBrief 查询资产（不含大字段），用于资产对比等批量场景
+func (m *AssetModel) FindBrief(ctx context.Context, filter bson.M) ([]Asset, error) {
+	cursor, err := m.coll.Find(ctx, filter, options.Find().SetProjection(briefProjection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []Asset
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// screenshotProjection 截图画廊所需字段
+var screenshotProjection = bson.M{
+	"authority":       1,
+	"host":            1,
+	"port":            1,
+	"title":           1,
+	"status":          1,
+	"app":             1,
+	"screenshot":      1,
+	"screenshot_hash": 1,
+	"update_time":     1,
+}
+
+// FindScreenshots 查询带截图的资产（仅画廊所需字段）
+func (m *AssetModel) FindScreenshots(ctx context.Context, filter bson.M) ([]Asset, error) {
+	if filter == nil {
+		filter = bson.M{}
+	}
+	if _, ok := filter["screenshot"]; !ok {
+		filter["screenshot"] = bson.M{"$exists": true, "$ne": ""}
+	}
+	opts := options.Find().SetProjection(screenshotProjection).SetSort(bson.D{{Key: "update_time", Value: -1}})
+	cursor, err := m.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []Asset
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
 // FindByRiskScore 按风险评分排序查询资产
 func (m *AssetModel) FindByRiskScore(ctx context.Context, filter bson.M, page, pageSize int, ascending bool) ([]Asset, error) {
 	opts := options.Find()
@@ -448,6 +567,7 @@ type AssetHistory struct {
 	HttpHeader string             `bson:"header,omitempty" json:"httpHeader"`
 	HttpBody   string             `bson:"body,omitempty" json:"httpBody"`
 	Banner     string             `bson:"banner,omitempty" json:"banner"`
+	Cert       string             `bson:"cert,omitempty" json:"cert"`
 	IconHash   string             `bson:"icon_hash,omitempty" json:"iconHash"`
 	Screenshot string             `bson:"screenshot,omitempty" json:"screenshot"`
 	TaskId     string             `bson:"taskId" json:"taskId"`
@@ -514,6 +634,21 @@ func (m *AssetHistoryModel) FindByAuthority(ctx context.Context, authority strin
 	return docs, nil
 }
 
+// FindBrief 查询历史记录（不含大字段）
+func (m *AssetHistoryModel) FindBrief(ctx context.Context, filter bson.M) ([]AssetHistory, error) {
+	cursor, err := m.coll.Find(ctx, filter, options.Find().SetProjection(briefProjection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []AssetHistory
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
 // Clear 清空所有历史记录
 func (m *AssetHistoryModel) Clear(ctx context.Context) (int64, error) {
 	result, err := m.coll.DeleteMany(ctx, bson.M{})
@@ -592,6 +727,9 @@ func (m *AssetModel) BulkUpsert(ctx context.Context, assets []*Asset) (*mongo.Bu
 				"cdn":         asset.IsCDN,
 				"cname":       asset.CName,
 				"cloud":       asset.IsCloud,
+				"cdn_name":    asset.CDNName,
+				"waf":         asset.WAF,
+				"cloud_name":  asset.CloudName,
 				"is_http":     asset.IsHTTP,
 				"taskId":      asset.TaskId,
 				"source":      asset.Source,
@@ -23,6 +23,7 @@ const (
 	AuditLogTypeTerminalClose AuditLogType = "terminal_close" // 关闭终端
 	AuditLogTypeTerminalExec  AuditLogType = "terminal_exec"  // 执行命令
 	AuditLogTypeConsoleInfo   AuditLogType = "console_info"   // 查看Worker信息
+	AuditLogTypeApiToken      AuditLogType = "api_token"      // API Token 调用
 )
 
 // AuditLog 审计日志
@@ -0,0 +1,187 @@
+package model
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 爆破字典类型
+const (
+	BruteDictUsername = "username"
+	BruteDictPassword = "password"
+)
+
+// BruteDict 弱口令爆破字典，按工作空间管理
+type BruteDict struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	WorkspaceId string             `bson:"workspace_id" json:"workspaceId"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description" json:"description"`
+	Type        string             `bson:"type" json:"type"`            // username/password
+	Services    []string           `bson:"services" json:"services"`    // 适用的服务，为空表示全部
+	Content     string             `bson:"content" json:"content"`      // 字典内容（每行一个）
+	WordCount   int                `bson:"word_count" json:"wordCount"` // 条目数量
+	Enabled     bool               `bson:"enabled" json:"enabled"`      // 是否启用
+	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// BruteDictModel 弱口令爆破字典模型
+type BruteDictModel struct {
+	coll *mongo.Collection
+}
+
+func NewBruteDictModel(db *mongo.Database) *BruteDictModel {
+	coll := db.Collection("brute_dict")
+	coll.Indexes().CreateOne(context.Background(), mongo.IndexModel{
+		Keys: bson.D{{Key: "workspace_id", Value: 1}, {Key: "type", Value: 1}},
+	})
+	return &BruteDictModel{coll: coll}
+}
+
+func (m *BruteDictModel) Insert(ctx context.Context, doc *BruteDict) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	_, err := m.coll.InsertOne(ctx, doc)
+	return err
+}
+
+// Find 查询工作空间的字典，dictType 为空表示全部类型
+func (m *BruteDictModel) Find(ctx context.Context, workspaceId, dictType string, page, pageSize int) ([]BruteDict, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "type", Value: 1}, {Key: "create_time", Value: -1}})
+	if page > 0 && pageSize > 0 {
+		opts.SetSkip(int64((page - 1) * pageSize))
+		opts.SetLimit(int64(pageSize))
+	}
+	cursor, err := m.coll.Find(ctx, m.filter(workspaceId, dictType), opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []BruteDict
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (m *BruteDictModel) Count(ctx context.Context, workspaceId, dictType string) (int64, error) {
+	return m.coll.CountDocuments(ctx, m.filter(workspaceId, dictType))
+}
+
+func (m *BruteDictModel) filter(workspaceId, dictType string) bson.M {
+	filter := bson.M{"workspace_id": workspaceId}
+	if dictType != "" {
+		filter["type"] = dictType
+	}
+	return filter
+}
+
+// FindEnabled 查询工作空间启用的字典
+func (m *BruteDictModel) FindEnabled(ctx context.Context, workspaceId string) ([]BruteDict, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "type", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := m.coll.Find(ctx, bson.M{"workspace_id": workspaceId, "enabled": true}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []BruteDict
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// FindByIds 按ID查询字典，只返回属于该工作空间的字典
+func (m *BruteDictModel) FindByIds(ctx context.Context, workspaceId string, ids []string) ([]BruteDict, error) {
+	var oids []primitive.ObjectID
+	for _, id := range ids {
+		oid, err := primitive.ObjectIDFromHex(id)
+		if err != nil {
+			continue
+		}
+		oids = append(oids, oid)
+	}
+	if len(oids) == 0 {
+		return nil, nil
+	}
+
+	cursor, err := m.coll.Find(ctx, bson.M{"_id": bson.M{"$in": oids}, "workspace_id": workspaceId})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []BruteDict
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+func (m *BruteDictModel) Update(ctx context.Context, workspaceId, id string, doc *BruteDict) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	update := bson.M{
+		"name":        doc.Name,
+		"description": doc.Description,
+		"type":        doc.Type,
+		"services":    doc.Services,
+		"content":     doc.Content,
+		"word_count":  doc.WordCount,
+		"enabled":     doc.Enabled,
+		"update_time": time.Now(),
+	}
+	result, err := m.coll.UpdateOne(ctx, bson.M{"_id": oid, "workspace_id": workspaceId}, bson.M{"$set": update})
+	if err == nil && result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return err
+}
+
+func (m *BruteDictModel) Delete(ctx context.Context, workspaceId, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = m.coll.DeleteOne(ctx, bson.M{"_id": oid, "workspace_id": workspaceId})
+	return err
+}
+
+// BruteEmptyWord 字典中表示空用户名或空密码的条目
+const BruteEmptyWord = "<empty>"
+
+// ParseBruteWords 解析字典内容，忽略空行和 # 开头的注释，<empty> 表示空值，去重并保持顺序
+func ParseBruteWords(content string) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if line == BruteEmptyWord {
+			line = ""
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		words = append(words, line)
+	}
+	return words
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+// TestParseBruteWords 测试字典解析：忽略空行和注释，<empty> 表示空值，去重并保持顺序
+func TestParseBruteWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{"empty", "", nil},
+		{"comments and blank lines", "# users\nroot\n\n   \nadmin\n", []string{"root", "admin"}},
+		{"crlf", "root\r\nadmin\r\n", []string{"root", "admin"}},
+		{"empty word", "<empty>\nroot\n<empty>", []string{"", "root"}},
+		{"dedupe keeps first", "admin\nroot\nadmin", []string{"admin", "root"}},
+		{"surrounding spaces kept", " pass \npass", []string{" pass ", "pass"}},
+		{"hash inside word", "p#ss\n#comment", []string{"p#ss"}},
+		{"empty word with spaces is literal", " <empty>", []string{" <empty>"}},
+	}
+	for _, tt := range tests {
+		if got := ParseBruteWords(tt.content); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: ParseBruteWords() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
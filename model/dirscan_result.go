@@ -25,6 +25,7 @@ type DirScanResult struct {
 	ContentType   string             `bson:"content_type" json:"contentType"`
 	Title         string             `bson:"title" json:"title"`
 	RedirectURL   string             `bson:"redirect_url" json:"redirectUrl"`
+	Source        string             `bson:"source" json:"source"` // 来源: urlfinder/crawler
 	CreateTime    time.Time          `bson:"create_time" json:"createTime"`
 }
 
@@ -108,6 +109,7 @@ func (m *DirScanResultModel) Upsert(ctx context.Context, doc *DirScanResult) err
 			"content_type":   doc.ContentType,
 			"title":          doc.Title,
 			"redirect_url":   doc.RedirectURL,
+			"source":         doc.Source,
 		},
 		"$setOnInsert": bson.M{
 			"_id":         doc.Id,
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DNSRecord DNS记录，按 域名+类型+值 去重
+type DNSRecord struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Domain     string             `bson:"domain" json:"domain"`
+	Root       string             `bson:"root" json:"root"` // 根域名
+	Type       string             `bson:"type" json:"type"` // A/AAAA/CNAME/MX/NS/TXT/SOA/SRV/CAA
+	Value      string             `bson:"value" json:"value"`
+	TTL        uint32             `bson:"ttl" json:"ttl"`
+	Source     string             `bson:"source" json:"source"` // query/axfr
+	MainTaskId string             `bson:"main_task_id" json:"mainTaskId"`
+	CreateTime time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// DNSRecordModel DNS记录模型
+type DNSRecordModel struct {
+	*BaseModel[DNSRecord]
+}
+
+// NewDNSRecordModel 创建DNS记录模型
+func NewDNSRecordModel(db *mongo.Database, workspaceId string) *DNSRecordModel {
+	m := &DNSRecordModel{
+		BaseModel: NewBaseModel[DNSRecord](db.Collection(workspaceId + "_dns_record")),
+	}
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "domain", Value: 1}, {Key: "type", Value: 1}, {Key: "value", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "root", Value: 1}}},
+		{Keys: bson.D{{Key: "update_time", Value: -1}}},
+	})
+	return m
+}
+
+// BulkUpsert 批量写入记录，已存在的记录更新TTL和更新时间
+func (m *DNSRecordModel) BulkUpsert(ctx context.Context, docs []*DNSRecord) (int64, error) {
+	if len(docs) == 0 {
+		return 0, nil
+	}
+	now := time.Now()
+	models := make([]mongo.WriteModel, 0, len(docs))
+	for _, doc := range docs {
+		models = append(models, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"domain": doc.Domain, "type": doc.Type, "value": doc.Value}).
+			SetUpdate(bson.M{
+				"$set": bson.M{
+					"root":         doc.Root,
+					"ttl":          doc.TTL,
+					"source":       doc.Source,
+					"main_task_id": doc.MainTaskId,
+					"update_time":  now,
+				},
+				"$setOnInsert": bson.M{"create_time": now},
+			}).
+			SetUpsert(true))
+	}
+	result, err := m.BulkWrite(ctx, models)
+	if err != nil {
+		return 0, err
+	}
+	return result.UpsertedCount + result.ModifiedCount, nil
+}
+
+// StatByType 按记录类型统计
+func (m *DNSRecordModel) StatByType(ctx context.Context) (map[string]int64, error) {
+	cursor, err := m.Coll.Aggregate(ctx, []bson.M{
+		{"$group": bson.M{"_id": "$type", "count": bson.M{"$sum": 1}}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var results []struct {
+		Id    string `bson:"_id"`
+		Count int64  `bson:"count"`
+	}
+	if err = cursor.All(ctx, &results); err != nil {
+		return nil, err
+	}
+	stat := make(map[string]int64, len(results))
+	for _, r := range results {
+		stat[r.Id] = r.Count
+	}
+	return stat, nil
+}
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// interactshConfigId 全局OOB配置只有一条记录
+const interactshConfigId = "global"
+
+// InteractshConfig OOB回连(interactsh)全局配置
+type InteractshConfig struct {
+	Id             string    `bson:"_id" json:"-"`
+	Mode           string    `bson:"mode" json:"mode"`                      // public/self/disable
+	ServerURL      string    `bson:"server_url" json:"serverUrl"`           // 自建服务地址
+	Token          string    `bson:"token" json:"token"`                    // 自建服务鉴权token
+	PollInterval   int       `bson:"poll_interval" json:"pollInterval"`     // 轮询间隔(秒)
+	CooldownPeriod int       `bson:"cooldown_period" json:"cooldownPeriod"` // 扫描结束后等待回连时间(秒)
+	Eviction       int       `bson:"eviction" json:"eviction"`              // 请求记录保留时间(秒)
+	UpdateTime     time.Time `bson:"update_time" json:"updateTime"`
+}
+
+// InteractshConfigModel OOB回连配置模型
+type InteractshConfigModel struct {
+	coll *mongo.Collection
+}
+
+func NewInteractshConfigModel(db *mongo.Database) *InteractshConfigModel {
+	return &InteractshConfigModel{coll: db.Collection("interactsh_config")}
+}
+
+// Get 获取配置，未配置时返回 mongo.ErrNoDocuments
+func (m *InteractshConfigModel) Get(ctx context.Context) (*InteractshConfig, error) {
+	var doc InteractshConfig
+	err := m.coll.FindOne(ctx, bson.M{"_id": interactshConfigId}).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// Save 保存配置
+func (m *InteractshConfigModel) Save(ctx context.Context, doc *InteractshConfig) error {
+	doc.Id = interactshConfigId
+	doc.UpdateTime = time.Now()
+	_, err := m.coll.ReplaceOne(ctx, bson.M{"_id": interactshConfigId}, doc, options.Replace().SetUpsert(true))
+	return err
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// 通知渠道类型
+const (
+	NotifyTypeWebhook  = "webhook"  // 通用Webhook
+	NotifyTypeDingTalk = "dingtalk" // 钉钉机器人
+	NotifyTypeFeishu   = "feishu"   // 飞书/Lark机器人
+	NotifyTypeWeCom    = "wecom"    // 企业微信机器人
+	NotifyTypeSlack    = "slack"    // Slack Incoming Webhook
+	NotifyTypeEmail    = "email"    // SMTP邮件
+)
+
+// 通知事件
+const (
+	NotifyEventTaskComplete  = "task_complete"  // 任务完成
+	NotifyEventTaskFailed    = "task_failed"    // 任务失败
+	NotifyEventVulFound      = "vul_found"      // 发现严重/高危漏洞
+	NotifyEventWorkerOffline = "worker_offline" // Worker离线
+)
+
+// NotifyChannel 通知渠道配置
+type NotifyChannel struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name       string             `bson:"name" json:"name"`
+	Type       string             `bson:"type" json:"type"`     // webhook/dingtalk/feishu/wecom/slack/email
+	Events     []string           `bson:"events" json:"events"` // 订阅的事件
+	Status     string             `bson:"status" json:"status"` // enable/disable
+	WebhookUrl string             `bson:"webhook_url,omitempty" json:"webhookUrl"`
+	Secret     string             `bson:"secret,omitempty" json:"secret"`   // 钉钉/飞书加签密钥，通用Webhook的HMAC密钥
+	Headers    map[string]string  `bson:"headers,omitempty" json:"headers"` // 通用Webhook自定义请求头
+	// SMTP 邮件配置
+	SmtpHost   string    `bson:"smtp_host,omitempty" json:"smtpHost"`
+	SmtpPort   int       `bson:"smtp_port,omitempty" json:"smtpPort"`
+	SmtpUser   string    `bson:"smtp_user,omitempty" json:"smtpUser"`
+	SmtpPass   string    `bson:"smtp_pass,omitempty" json:"smtpPass"`
+	SmtpFrom   string    `bson:"smtp_from,omitempty" json:"smtpFrom"`
+	SmtpTo     []string  `bson:"smtp_to,omitempty" json:"smtpTo"`
+	SmtpSSL    bool      `bson:"smtp_ssl" json:"smtpSsl"` // 使用SSL直连（465），否则尝试STARTTLS
+	CreateTime time.Time `bson:"create_time" json:"createTime"`
+	UpdateTime time.Time `bson:"update_time" json:"updateTime"`
+}
+
+// HasEvent 是否订阅了指定事件
+func (c *NotifyChannel) HasEvent(event string) bool {
+	for _, e := range c.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NotifyChannelModel 通知渠道模型
+type NotifyChannelModel struct {
+	coll *mongo.Collection
+}
+
+// NewNotifyChannelModel 创建通知渠道模型
+func NewNotifyChannelModel(db *mongo.Database) *NotifyChannelModel {
+	coll := db.Collection("notify_channel")
+
+	// 创建索引
+	ctx := context.Background()
+	coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "events", Value: 1}},
+	})
+
+	return &NotifyChannelModel{coll: coll}
+}
+
+// Insert 插入渠道
+func (m *NotifyChannelModel) Insert(ctx context.Context, doc *NotifyChannel) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	if doc.Status == "" {
+		doc.Status = "enable"
+	}
+	_, err := m.coll.InsertOne(ctx, doc)
+	return err
+}
+
+// FindById 根据ID查找
+func (m *NotifyChannelModel) FindById(ctx context.Context, id string) (*NotifyChannel, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var doc NotifyChannel
+	err = m.coll.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc)
+	return &doc, err
+}
+
+// FindAll 查找所有渠道
+func (m *NotifyChannelModel) FindAll(ctx context.Context) ([]NotifyChannel, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}})
+	cursor, err := m.coll.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []NotifyChannel
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// FindEnabledByEvent 查找订阅了指定事件的启用渠道
+func (m *NotifyChannelModel) FindEnabledByEvent(ctx context.Context, event string) ([]NotifyChannel, error) {
+	cursor, err := m.coll.Find(ctx, bson.M{"status": "enable", "events": event})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []NotifyChannel
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// Update 更新渠道
+func (m *NotifyChannelModel) Update(ctx context.Context, id string, update bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	update["update_time"] = time.Now()
+	_, err = m.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update})
+	return err
+}
+
+// Delete 删除渠道
+func (m *NotifyChannelModel) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = m.coll.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
@@ -15,7 +15,8 @@ type Organization struct {
 	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
 	Name        string             `bson:"name" json:"name"`
 	Description string             `bson:"description" json:"description"`
-	Status      string             `bson:"status" json:"status"` // enable, disable
+	Status      string             `bson:"status" json:"status"`                     // enable, disable
+	Window      *MaintenanceWindow `bson:"window,omitempty" json:"window,omitempty"` // 维护窗口，为空不限制扫描时间
 	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
 	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
 }
@@ -0,0 +1,112 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReportTemplate 报告模板（按工作空间存储）
+type ReportTemplate struct {
+	Id          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Format      string             `bson:"format" json:"format"` // html, pdf, markdown
+	Content     string             `bson:"content" json:"content"`
+	Description string             `bson:"description" json:"description"`
+	IsDefault   bool               `bson:"is_default" json:"isDefault"` // 该格式的默认模板
+	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// ReportTemplateModel 报告模板模型
+type ReportTemplateModel struct {
+	coll *mongo.Collection
+}
+
+// NewReportTemplateModel 创建报告模板模型
+func NewReportTemplateModel(db *mongo.Database, workspaceId string) *ReportTemplateModel {
+	return &ReportTemplateModel{
+		coll: db.Collection(workspaceId + "_report_template"),
+	}
+}
+
+// Insert 插入模板
+func (m *ReportTemplateModel) Insert(ctx context.Context, doc *ReportTemplate) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	_, err := m.coll.InsertOne(ctx, doc)
+	return err
+}
+
+// FindById 根据ID查找
+func (m *ReportTemplateModel) FindById(ctx context.Context, id string) (*ReportTemplate, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var doc ReportTemplate
+	err = m.coll.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc)
+	return &doc, err
+}
+
+// FindAll 查找所有模板
+func (m *ReportTemplateModel) FindAll(ctx context.Context) ([]ReportTemplate, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}})
+	cursor, err := m.coll.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []ReportTemplate
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// FindDefault 查找指定格式的默认模板，不存在时返回 mongo.ErrNoDocuments
+func (m *ReportTemplateModel) FindDefault(ctx context.Context, format string) (*ReportTemplate, error) {
+	var doc ReportTemplate
+	err := m.coll.FindOne(ctx, bson.M{"format": format, "is_default": true}).Decode(&doc)
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// ClearDefault 取消指定格式的默认模板
+func (m *ReportTemplateModel) ClearDefault(ctx context.Context, format string) error {
+	_, err := m.coll.UpdateMany(ctx, bson.M{"format": format, "is_default": true},
+		bson.M{"$set": bson.M{"is_default": false, "update_time": time.Now()}})
+	return err
+}
+
+// Update 更新模板
+func (m *ReportTemplateModel) Update(ctx context.Context, id string, update bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	update["update_time"] = time.Now()
+	_, err = m.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update})
+	return err
+}
+
+// Delete 删除模板
+func (m *ReportTemplateModel) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = m.coll.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
@@ -48,6 +48,22 @@ type MainTask struct {
 	// 子任务拆分（用于分布式并发）
 	SubTaskCount int               `bson:"sub_task_count" json:"subTaskCount"` // 子任务总数
 	SubTaskDone  int               `bson:"sub_task_done" json:"subTaskDone"`   // 已完成子任务数
+	// 因维护窗口关闭而自动暂停，窗口重新打开时自动继续
+	WindowPaused bool `bson:"window_paused,omitempty" json:"windowPaused,omitempty"`
+	// 定时任务每次执行完成后与执行前的资产对比摘要
+	DiffSummary *AssetDiffSummary `bson:"diff_summary,omitempty" json:"diffSummary,omitempty"`
+}
+
+// AssetDiffSummary 资产变化摘要
+type AssetDiffSummary struct {
+	NewHosts      int       `bson:"new_hosts" json:"newHosts"`
+	NewPorts      int       `bson:"new_ports" json:"newPorts"`
+	ClosedPorts   int       `bson:"closed_ports" json:"closedPorts"`
+	ChangedAssets int       `bson:"changed_assets" json:"changedAssets"`
+	NewVuls       int       `bson:"new_vuls" json:"newVuls"`
+	ResolvedVuls  int       `bson:"resolved_vuls" json:"resolvedVuls"`
+	BaseTime      time.Time `bson:"base_time" json:"baseTime"`
+	TargetTime    time.Time `bson:"target_time" json:"targetTime"`
 }
 
 type ExecutorTask struct {
@@ -216,6 +232,19 @@ func (m *MainTaskModel) IncrSubTaskDone(ctx context.Context, id string) error {
 	return err
 }
 
+// IncrSubTaskCount 增加子任务总数，阶段交接拆分出更多子任务时使用
+func (m *MainTaskModel) IncrSubTaskCount(ctx context.Context, id string, n int) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = m.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{
+		"$inc": bson.M{"sub_task_count": n},
+		"$set": bson.M{"update_time": time.Now()},
+	})
+	return err
+}
+
 // ExecutorTaskModel
 type ExecutorTaskModel struct {
 	coll *mongo.Collection
@@ -17,16 +17,60 @@ const (
 	StatusDisable = "disable"
 )
 
+// 用户角色
+const (
+	RoleSuperAdmin     = "superadmin"      // 超级管理员：系统设置、用户、Worker 管理
+	RoleWorkspaceAdmin = "workspace_admin" // 工作空间管理员：可删除/清空所属工作空间数据
+	RoleOperator       = "operator"        // 操作员：创建任务、维护资产和配置
+	RoleAuditor        = "auditor"         // 审计员：只读
+)
+
+// Roles 全部角色，按权限从高到低排列
+var Roles = []string{RoleSuperAdmin, RoleWorkspaceAdmin, RoleOperator, RoleAuditor}
+
+var roleLevels = map[string]int{
+	RoleSuperAdmin:     4,
+	RoleWorkspaceAdmin: 3,
+	RoleOperator:       2,
+	RoleAuditor:        1,
+}
+
+// IsValidRole 是否为合法角色
+func IsValidRole(role string) bool {
+	for _, r := range Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeRole 未设置或未知的角色按权限最低的审计员处理。
+// 旧版本未设置角色的用户由 MigrateLegacyRoles 在启动时升级为超级管理员
+func NormalizeRole(role string) string {
+	if !IsValidRole(role) {
+		return RoleAuditor
+	}
+	return role
+}
+
+// RoleAtLeast 判断 role 是否具备 required 角色的权限，未知角色不具备任何权限
+func RoleAtLeast(role, required string) bool {
+	level, ok := roleLevels[role]
+	return ok && level >= roleLevels[required]
+}
+
 type User struct {
-	Id              primitive.ObjectID `bson:"_id,omitempty" json:"id"`
-	Username        string             `bson:"username" json:"username"`
-	Password        string             `bson:"password" json:"-"`
-	Status          string             `bson:"status" json:"status"`
-	WorkspaceIds    []string           `bson:"workspace_ids" json:"workspaceIds"`
-	ScanConfig      string             `bson:"scan_config" json:"scanConfig"` // 用户默认扫描配置JSON
-	LastLoginTime   *time.Time         `bson:"last_login_time" json:"lastLoginTime"`
-	CreateTime      time.Time          `bson:"create_time" json:"createTime"`
-	UpdateTime      time.Time          `bson:"update_time" json:"updateTime"`
+	Id            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Username      string             `bson:"username" json:"username"`
+	Password      string             `bson:"password" json:"-"`
+	Status        string             `bson:"status" json:"status"`
+	Role          string             `bson:"role" json:"role"`                  // 角色，见 Role* 常量
+	WorkspaceIds  []string           `bson:"workspace_ids" json:"workspaceIds"` // 所属工作空间，超级管理员不受限制
+	ScanConfig    string             `bson:"scan_config" json:"scanConfig"`     // 用户默认扫描配置JSON
+	LastLoginTime *time.Time         `bson:"last_login_time" json:"lastLoginTime"`
+	CreateTime    time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime    time.Time          `bson:"update_time" json:"updateTime"`
 }
 
 type UserModel struct {
@@ -178,6 +222,54 @@ func (m *UserModel) Delete(ctx context.Context, id string) error {
 	return err
 }
 
+// MigrateLegacyRoles 将旧版本用户升级为超级管理员，返回升级的用户数。
+// 旧版本没有角色字段且所有用户都是管理员，因此未设置角色或角色为 admin 的用户都视为旧用户；
+// 新建用户总会写入角色，不会被误升级。若升级后仍没有超级管理员，则将最早创建的用户升级，
+// 避免部署中无人可管理用户和角色
+func (m *UserModel) MigrateLegacyRoles(ctx context.Context) (int64, error) {
+	now := time.Now()
+	filter := bson.M{"role": bson.M{"$in": []interface{}{"", nil, "admin"}}}
+	result, err := m.coll.UpdateMany(ctx, filter, bson.M{"$set": bson.M{"role": RoleSuperAdmin, "update_time": now}})
+	if err != nil {
+		return 0, err
+	}
+	migrated := result.ModifiedCount
+
+	count, err := m.coll.CountDocuments(ctx, bson.M{"role": RoleSuperAdmin})
+	if err != nil {
+		return migrated, err
+	}
+	if count > 0 {
+		return migrated, nil
+	}
+	opts := options.FindOneAndUpdate().SetSort(bson.D{{Key: "create_time", Value: 1}, {Key: "_id", Value: 1}})
+	err = m.coll.FindOneAndUpdate(ctx, bson.M{}, bson.M{"$set": bson.M{"role": RoleSuperAdmin, "update_time": now}}, opts).Err()
+	if err == mongo.ErrNoDocuments {
+		return migrated, nil
+	}
+	if err != nil {
+		return migrated, err
+	}
+	return migrated + 1, nil
+}
+
+// GetRole 用户角色，未设置或未知角色按审计员处理
+func (u *User) GetRole() string {
+	return NormalizeRole(u.Role)
+}
+
+// AllowedWorkspaces 用户可访问的工作空间，nil 表示不受限制。
+// 未分配工作空间的用户只能访问 default 工作空间
+func (u *User) AllowedWorkspaces() []string {
+	if u.GetRole() == RoleSuperAdmin {
+		return nil
+	}
+	if len(u.WorkspaceIds) == 0 {
+		return []string{"default"}
+	}
+	return u.WorkspaceIds
+}
+
 func (m *UserModel) VerifyPassword(ctx context.Context, username, password string) (*User, bool) {
 	user, err := m.FindByUsername(ctx, username)
 	if err != nil {
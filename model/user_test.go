@@ -0,0 +1,104 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+func TestMigrateLegacyRoles(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	updated := func(n int32) bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+	}
+	count := func(n int32) bson.D {
+		if n == 0 {
+			return mtest.CreateCursorResponse(0, "cscan.user", mtest.FirstBatch)
+		}
+		return mtest.CreateCursorResponse(0, "cscan.user", mtest.FirstBatch, bson.D{{Key: "n", Value: n}})
+	}
+	findAndModify := func(found bool) bson.D {
+		if !found {
+			return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: nil})
+		}
+		return mtest.CreateSuccessResponse(bson.E{Key: "value", Value: bson.D{{Key: "username", Value: "alice"}}})
+	}
+
+	mt.Run("role-less legacy users become superadmin", func(mt *mtest.T) {
+		mt.AddMockResponses(updated(3), count(3))
+		m := &UserModel{coll: mt.Coll}
+		n, err := m.MigrateLegacyRoles(context.Background())
+		if err != nil {
+			mt.Fatalf("MigrateLegacyRoles: %v", err)
+		}
+		if n != 3 {
+			mt.Errorf("migrated = %d, want 3", n)
+		}
+
+		started := mt.GetAllStartedEvents()
+		if len(started) != 2 {
+			mt.Fatalf("commands = %d, want 2", len(started))
+		}
+		update := started[0].Command.Lookup("updates").Array().Index(0).Value().Document()
+		roles := update.Lookup("q", "role", "$in").Array()
+		values, _ := roles.Values()
+		var hasEmpty, hasNull, hasAdmin bool
+		for _, v := range values {
+			switch {
+			case v.Type == bson.TypeNull:
+				hasNull = true
+			case v.StringValue() == "":
+				hasEmpty = true
+			case v.StringValue() == "admin":
+				hasAdmin = true
+			}
+		}
+		if !hasEmpty || !hasNull || !hasAdmin {
+			mt.Errorf("legacy filter = %v, want empty, missing and admin roles", roles)
+		}
+		if got := update.Lookup("u", "$set", "role").StringValue(); got != RoleSuperAdmin {
+			mt.Errorf("migrated role = %q, want %q", got, RoleSuperAdmin)
+		}
+	})
+
+	mt.Run("oldest user promoted when no superadmin remains", func(mt *mtest.T) {
+		mt.AddMockResponses(updated(0), count(0), findAndModify(true))
+		m := &UserModel{coll: mt.Coll}
+		n, err := m.MigrateLegacyRoles(context.Background())
+		if err != nil {
+			mt.Fatalf("MigrateLegacyRoles: %v", err)
+		}
+		if n != 1 {
+			mt.Errorf("migrated = %d, want 1", n)
+		}
+		cmd := mt.GetStartedEvent()
+		for cmd != nil && cmd.CommandName != "findAndModify" {
+			cmd = mt.GetStartedEvent()
+		}
+		if cmd == nil {
+			mt.Fatal("oldest user was not promoted")
+		}
+		sort := cmd.Command.Lookup("sort").Document()
+		if v := sort.Lookup("create_time").Int32(); v != 1 {
+			mt.Errorf("sort create_time = %d, want ascending", v)
+		}
+		if got := cmd.Command.Lookup("update", "$set", "role").StringValue(); got != RoleSuperAdmin {
+			mt.Errorf("promoted role = %q, want %q", got, RoleSuperAdmin)
+		}
+	})
+
+	mt.Run("no users", func(mt *mtest.T) {
+		mt.AddMockResponses(updated(0), count(0), findAndModify(false))
+		m := &UserModel{coll: mt.Coll}
+		n, err := m.MigrateLegacyRoles(context.Background())
+		if err != nil {
+			mt.Fatalf("MigrateLegacyRoles: %v", err)
+		}
+		if n != 0 {
+			mt.Errorf("migrated = %d, want 0", n)
+		}
+	})
+}
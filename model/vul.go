@@ -40,10 +40,82 @@ type Vul struct {
 	Response          string   `bson:"response,omitempty" json:"response,omitempty"`
 	ResponseTruncated bool     `bson:"response_truncated,omitempty" json:"responseTruncated,omitempty"`
 
+	// OOB 回连证据
+	Interaction *VulInteraction `bson:"interaction,omitempty" json:"interaction,omitempty"`
+
 	// 时间追踪字段
 	FirstSeenTime time.Time `bson:"first_seen_time,omitempty" json:"firstSeenTime,omitempty"`
 	LastSeenTime  time.Time `bson:"last_seen_time,omitempty" json:"lastSeenTime,omitempty"`
 	ScanCount     int       `bson:"scan_count,omitempty" json:"scanCount,omitempty"`
+
+	// 处置流程字段
+	Status        string            `bson:"status,omitempty" json:"status"` // 为空视为 new
+	Assignee      string            `bson:"assignee,omitempty" json:"assignee,omitempty"`
+	Comments      []VulComment      `bson:"comments,omitempty" json:"comments,omitempty"`
+	StatusHistory []VulStatusChange `bson:"status_history,omitempty" json:"statusHistory,omitempty"`
+}
+
+// 漏洞处置状态
+const (
+	VulStatusNew           = "new"            // 新发现
+	VulStatusConfirmed     = "confirmed"      // 已确认
+	VulStatusFalsePositive = "false_positive" // 误报
+	VulStatusAcceptedRisk  = "accepted_risk"  // 接受风险
+	VulStatusFixed         = "fixed"          // 已修复
+	VulStatusReopened      = "reopened"       // 重新打开（已修复后再次发现）
+)
+
+// VulStatuses 全部处置状态
+var VulStatuses = []string{
+	VulStatusNew, VulStatusConfirmed, VulStatusFalsePositive,
+	VulStatusAcceptedRisk, VulStatusFixed, VulStatusReopened,
+}
+
+// IsValidVulStatus 是否为合法的处置状态
+func IsValidVulStatus(status string) bool {
+	for _, s := range VulStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// VulStatusFilter 按处置状态查询的条件，new 同时匹配未设置状态的历史数据
+func VulStatusFilter(status string) interface{} {
+	if status == VulStatusNew {
+		return bson.M{"$in": []interface{}{VulStatusNew, nil}}
+	}
+	return status
+}
+
+// VulInteraction OOB 回连证据，interactsh 服务收到的 DNS/HTTP/SMTP 等交互
+type VulInteraction struct {
+	Protocol      string    `bson:"protocol" json:"protocol"`
+	UniqueId      string    `bson:"unique_id" json:"uniqueId"`
+	FullId        string    `bson:"full_id" json:"fullId"`
+	QType         string    `bson:"q_type,omitempty" json:"qType,omitempty"`
+	RawRequest    string    `bson:"raw_request,omitempty" json:"rawRequest,omitempty"`
+	RawResponse   string    `bson:"raw_response,omitempty" json:"rawResponse,omitempty"`
+	RemoteAddress string    `bson:"remote_address" json:"remoteAddress"`
+	SMTPFrom      string    `bson:"smtp_from,omitempty" json:"smtpFrom,omitempty"`
+	Timestamp     time.Time `bson:"timestamp" json:"timestamp"`
+}
+
+// VulComment 漏洞评论
+type VulComment struct {
+	Author     string    `bson:"author" json:"author"`
+	Content    string    `bson:"content" json:"content"`
+	CreateTime time.Time `bson:"create_time" json:"createTime"`
+}
+
+// VulStatusChange 漏洞状态变更记录
+type VulStatusChange struct {
+	From     string    `bson:"from" json:"from"`
+	To       string    `bson:"to" json:"to"`
+	Operator string    `bson:"operator" json:"operator"` // 自动变更时为 system
+	Reason   string    `bson:"reason,omitempty" json:"reason,omitempty"`
+	Time     time.Time `bson:"time" json:"time"`
 }
 
 type VulModel struct {
@@ -98,6 +170,22 @@ func (m *VulModel) Find(ctx context.Context, filter bson.M, page, pageSize int)
 	return docs, nil
 }
 
+// FindBrief 查询漏洞（不含请求/响应等证据字段）
+func (m *VulModel) FindBrief(ctx context.Context, filter bson.M) ([]Vul, error) {
+	projection := bson.M{"request": 0, "response": 0, "curl_command": 0, "extra": 0}
+	cursor, err := m.coll.Find(ctx, filter, options.Find().SetProjection(projection))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []Vul
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
 func (m *VulModel) Count(ctx context.Context, filter bson.M) (int64, error) {
 	return m.coll.CountDocuments(ctx, filter)
 }
@@ -116,9 +204,11 @@ func (m *VulModel) DeleteByTaskId(ctx context.Context, taskId string) error {
 	return err
 }
 
-// Upsert 插入或更新漏洞（基于 host+port+pocFile+url 去重）
-func (m *VulModel) Upsert(ctx context.Context, doc *Vul) error {
+// Upsert 插入或更新漏洞（基于 host+port+pocFile+url 去重），返回是否为新发现的漏洞，
+// 以及是否为已修复漏洞再次被发现（此时状态自动变为 reopened）
+func (m *VulModel) Upsert(ctx context.Context, doc *Vul) (isNew bool, reopened bool, err error) {
 	now := time.Now()
+	id := primitive.NewObjectID()
 	filter := bson.M{
 		"host":    doc.Host,
 		"port":    doc.Port,
@@ -147,6 +237,7 @@ func (m *VulModel) Upsert(ctx context.Context, doc *Vul) error {
 			"request":            doc.Request,
 			"response":           doc.Response,
 			"response_truncated": doc.ResponseTruncated,
+			"interaction":        doc.Interaction,
 			// 新增字段 - 时间追踪
 			"last_seen_time": now,
 		},
@@ -154,14 +245,109 @@ func (m *VulModel) Upsert(ctx context.Context, doc *Vul) error {
 			"scan_count": 1, // 新增：扫描计数
 		},
 		"$setOnInsert": bson.M{
-			"_id":             primitive.NewObjectID(),
+			"_id":             id,
 			"create_time":     now,
 			"first_seen_time": now, // 新增：首次发现时间
+			"status":          VulStatusNew,
 		},
 	}
 	opts := options.Update().SetUpsert(true)
-	_, err := m.coll.UpdateOne(ctx, filter, update, opts)
-	return err
+	result, err := m.coll.UpdateOne(ctx, filter, update, opts)
+	if err != nil {
+		return false, false, err
+	}
+	if result.UpsertedCount > 0 {
+		doc.Id = id
+		return true, false, nil
+	}
+
+	// 已修复的漏洞再次被发现，自动重新打开
+	filter["status"] = VulStatusFixed
+	reopen := bson.M{
+		"$set": bson.M{"status": VulStatusReopened},
+		"$push": bson.M{"status_history": VulStatusChange{
+			From:     VulStatusFixed,
+			To:       VulStatusReopened,
+			Operator: "system",
+			Reason:   "扫描再次发现",
+			Time:     now,
+		}},
+	}
+	result, err = m.coll.UpdateOne(ctx, filter, reopen)
+	if err != nil {
+		return false, false, err
+	}
+	return false, result.ModifiedCount > 0, nil
+}
+
+// UpdateStatus 批量变更处置状态并记录变更历史，返回变更数量
+func (m *VulModel) UpdateStatus(ctx context.Context, ids []string, status, operator, reason string) (int64, error) {
+	var changed int64
+	now := time.Now()
+	for _, id := range ids {
+		vul, err := m.FindById(ctx, id)
+		if err != nil {
+			continue
+		}
+		from := vul.Status
+		if from == "" {
+			from = VulStatusNew
+		}
+		if from == status {
+			continue
+		}
+		update := bson.M{
+			"$set": bson.M{"status": status},
+			"$push": bson.M{"status_history": VulStatusChange{
+				From:     from,
+				To:       status,
+				Operator: operator,
+				Reason:   reason,
+				Time:     now,
+			}},
+		}
+		result, err := m.coll.UpdateOne(ctx, bson.M{"_id": vul.Id}, update)
+		if err != nil {
+			return changed, err
+		}
+		changed += result.ModifiedCount
+	}
+	return changed, nil
+}
+
+// UpdateAssignee 批量指派处理人
+func (m *VulModel) UpdateAssignee(ctx context.Context, ids []string, assignee string) (int64, error) {
+	oids := make([]primitive.ObjectID, 0, len(ids))
+	for _, id := range ids {
+		if oid, err := primitive.ObjectIDFromHex(id); err == nil {
+			oids = append(oids, oid)
+		}
+	}
+	if len(oids) == 0 {
+		return 0, nil
+	}
+	result, err := m.coll.UpdateMany(ctx, bson.M{"_id": bson.M{"$in": oids}}, bson.M{"$set": bson.M{"assignee": assignee}})
+	if err != nil {
+		return 0, err
+	}
+	return result.ModifiedCount, nil
+}
+
+// AddComment 添加评论
+func (m *VulModel) AddComment(ctx context.Context, id string, comment VulComment) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	comment.CreateTime = time.Now()
+	result, err := m.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$push": bson.M{"comments": comment}})
+	if err != nil {
+		return err
+	}
+	if result.MatchedCount == 0 {
+		return mongo.ErrNoDocuments
+	}
+	return nil
 }
 
 // BatchDelete 批量删除漏洞
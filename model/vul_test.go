@@ -0,0 +1,187 @@
+package model
+
+import (
+	"context"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestVulUpsert 测试漏洞入库：新漏洞插入，已存在的漏洞更新，已修复的漏洞再次发现时自动重新打开
+func TestVulUpsert(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	modified := func(n int32) bson.D {
+		return mtest.CreateSuccessResponse(bson.E{Key: "n", Value: n}, bson.E{Key: "nModified", Value: n})
+	}
+	newVul := func() *Vul {
+		return &Vul{Host: "10.0.0.1", Port: 80, PocFile: "CVE-2021-44228", Url: "http://10.0.0.1/", Severity: "critical"}
+	}
+
+	mt.Run("new vul", func(mt *mtest.T) {
+		upserted := mtest.CreateSuccessResponse(
+			bson.E{Key: "n", Value: int32(1)},
+			bson.E{Key: "nModified", Value: int32(0)},
+			bson.E{Key: "upserted", Value: bson.A{bson.D{{Key: "index", Value: int32(0)}, {Key: "_id", Value: primitive.NewObjectID()}}}},
+		)
+		mt.AddMockResponses(upserted)
+		m := &VulModel{coll: mt.Coll}
+		vul := newVul()
+		isNew, reopened, err := m.Upsert(context.Background(), vul)
+		if err != nil {
+			mt.Fatalf("Upsert: %v", err)
+		}
+		if !isNew || reopened {
+			mt.Errorf("Upsert() = %v, %v, want new", isNew, reopened)
+		}
+		if vul.Id.IsZero() {
+			mt.Error("Id not set on new vul")
+		}
+		update := mt.GetStartedEvent().Command.Lookup("updates").Array().Index(0).Value().Document()
+		if !update.Lookup("upsert").Boolean() {
+			mt.Error("update is not an upsert")
+		}
+		if status := update.Lookup("u", "$setOnInsert", "status").StringValue(); status != VulStatusNew {
+			mt.Errorf("inserted status = %q, want %q", status, VulStatusNew)
+		}
+		if _, ok := update.Lookup("u", "$set", "status").StringValueOK(); ok {
+			mt.Error("upsert overwrites the status of existing vuls")
+		}
+	})
+
+	mt.Run("fixed vul reopened", func(mt *mtest.T) {
+		mt.AddMockResponses(modified(1), modified(1))
+		m := &VulModel{coll: mt.Coll}
+		isNew, reopened, err := m.Upsert(context.Background(), newVul())
+		if err != nil {
+			mt.Fatalf("Upsert: %v", err)
+		}
+		if isNew || !reopened {
+			mt.Errorf("Upsert() = %v, %v, want reopened", isNew, reopened)
+		}
+
+		events := mt.GetAllStartedEvents()
+		if len(events) != 2 {
+			mt.Fatalf("commands = %d, want 2", len(events))
+		}
+		reopen := events[1].Command.Lookup("updates").Array().Index(0).Value().Document()
+		if status := reopen.Lookup("q", "status").StringValue(); status != VulStatusFixed {
+			mt.Errorf("reopen filter status = %q, want %q", status, VulStatusFixed)
+		}
+		if host := reopen.Lookup("q", "host").StringValue(); host != "10.0.0.1" {
+			mt.Errorf("reopen filter host = %q", host)
+		}
+		if status := reopen.Lookup("u", "$set", "status").StringValue(); status != VulStatusReopened {
+			mt.Errorf("reopen status = %q, want %q", status, VulStatusReopened)
+		}
+		change := reopen.Lookup("u", "$push", "status_history").Document()
+		if change.Lookup("from").StringValue() != VulStatusFixed || change.Lookup("to").StringValue() != VulStatusReopened ||
+			change.Lookup("operator").StringValue() != "system" {
+			mt.Errorf("status history = %v", change)
+		}
+	})
+
+	mt.Run("open vul seen again", func(mt *mtest.T) {
+		mt.AddMockResponses(modified(1), modified(0))
+		m := &VulModel{coll: mt.Coll}
+		isNew, reopened, err := m.Upsert(context.Background(), newVul())
+		if err != nil {
+			mt.Fatalf("Upsert: %v", err)
+		}
+		if isNew || reopened {
+			mt.Errorf("Upsert() = %v, %v, want neither new nor reopened", isNew, reopened)
+		}
+	})
+
+	mt.Run("upsert error", func(mt *mtest.T) {
+		mt.AddMockResponses(mtest.CreateCommandErrorResponse(mtest.CommandError{Code: 11000, Message: "duplicate key"}))
+		m := &VulModel{coll: mt.Coll}
+		if _, _, err := m.Upsert(context.Background(), newVul()); err == nil {
+			mt.Error("Upsert() succeeded on a write error")
+		}
+		if n := len(mt.GetAllStartedEvents()); n != 1 {
+			mt.Errorf("commands = %d, want 1", n)
+		}
+	})
+}
+
+// TestVulUpdateStatus 测试批量变更处置状态：记录变更历史，状态未变化或ID无效时跳过
+func TestVulUpdateStatus(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	found := func(id primitive.ObjectID, status string) bson.D {
+		doc := bson.D{{Key: "_id", Value: id}, {Key: "host", Value: "10.0.0.1"}}
+		if status != "" {
+			doc = append(doc, bson.E{Key: "status", Value: status})
+		}
+		return mtest.CreateCursorResponse(0, "cscan.vul", mtest.FirstBatch, doc)
+	}
+	modified := mtest.CreateSuccessResponse(bson.E{Key: "n", Value: int32(1)}, bson.E{Key: "nModified", Value: int32(1)})
+
+	mt.Run("records history", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(found(id, ""), modified)
+		m := &VulModel{coll: mt.Coll}
+		n, err := m.UpdateStatus(context.Background(), []string{id.Hex()}, VulStatusConfirmed, "alice", "复现成功")
+		if err != nil {
+			mt.Fatalf("UpdateStatus: %v", err)
+		}
+		if n != 1 {
+			mt.Errorf("changed = %d, want 1", n)
+		}
+		events := mt.GetAllStartedEvents()
+		update := events[len(events)-1].Command.Lookup("updates").Array().Index(0).Value().Document()
+		if got := update.Lookup("q", "_id").ObjectID(); got != id {
+			mt.Errorf("update filter _id = %v, want %v", got, id)
+		}
+		if status := update.Lookup("u", "$set", "status").StringValue(); status != VulStatusConfirmed {
+			mt.Errorf("status = %q, want %q", status, VulStatusConfirmed)
+		}
+		change := update.Lookup("u", "$push", "status_history").Document()
+		// 未设置状态的历史数据按 new 记录
+		if change.Lookup("from").StringValue() != VulStatusNew || change.Lookup("to").StringValue() != VulStatusConfirmed ||
+			change.Lookup("operator").StringValue() != "alice" || change.Lookup("reason").StringValue() != "复现成功" {
+			mt.Errorf("status history = %v", change)
+		}
+	})
+
+	mt.Run("skips unchanged, missing and invalid ids", func(mt *mtest.T) {
+		same, changed := primitive.NewObjectID(), primitive.NewObjectID()
+		mt.AddMockResponses(
+			found(same, VulStatusFixed),
+			mtest.CreateCursorResponse(0, "cscan.vul", mtest.FirstBatch), // 不存在
+			found(changed, VulStatusConfirmed),
+			modified,
+		)
+		m := &VulModel{coll: mt.Coll}
+		ids := []string{same.Hex(), "not-an-id", primitive.NewObjectID().Hex(), changed.Hex()}
+		n, err := m.UpdateStatus(context.Background(), ids, VulStatusFixed, "bob", "")
+		if err != nil {
+			mt.Fatalf("UpdateStatus: %v", err)
+		}
+		if n != 1 {
+			mt.Errorf("changed = %d, want 1", n)
+		}
+		var updated []primitive.ObjectID
+		for _, e := range mt.GetAllStartedEvents() {
+			if e.CommandName == "update" {
+				updated = append(updated, e.Command.Lookup("updates").Array().Index(0).Value().Document().Lookup("q", "_id").ObjectID())
+			}
+		}
+		if len(updated) != 1 || updated[0] != changed {
+			mt.Errorf("updated ids = %v, want only %v", updated, changed)
+		}
+	})
+
+	mt.Run("new to unset status is unchanged", func(mt *mtest.T) {
+		id := primitive.NewObjectID()
+		mt.AddMockResponses(found(id, ""))
+		m := &VulModel{coll: mt.Coll}
+		n, err := m.UpdateStatus(context.Background(), []string{id.Hex()}, VulStatusNew, "bob", "")
+		if err != nil || n != 0 {
+			mt.Errorf("UpdateStatus() = %d, %v, want 0, nil", n, err)
+		}
+	})
+}
@@ -0,0 +1,150 @@
+package model
+
+import (
+	"context"
+	"regexp"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// VulSuppressRule 漏洞误报抑制规则，按 模板+主机 或 模板+URL正则 匹配
+type VulSuppressRule struct {
+	Id         primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	PocFile    string             `bson:"pocfile" json:"pocFile"`                  // POC模板
+	Host       string             `bson:"host,omitempty" json:"host"`              // 主机，为空时使用 Pattern
+	Pattern    string             `bson:"pattern,omitempty" json:"pattern"`        // 匹配 URL 或 authority 的正则
+	Reason     string             `bson:"reason,omitempty" json:"reason"`          // 抑制原因
+	Status     string             `bson:"status" json:"status"`                    // enable/disable
+	HitCount   int                `bson:"hit_count" json:"hitCount"`               // 命中次数
+	LastHit    time.Time          `bson:"last_hit,omitempty" json:"lastHit"`       // 最后命中时间
+	CreateUser string             `bson:"create_user,omitempty" json:"createUser"` // 创建人
+	CreateTime time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime time.Time          `bson:"update_time" json:"updateTime"`
+
+	re *regexp.Regexp // Pattern 编译缓存
+}
+
+// Match 判断漏洞是否命中规则
+func (r *VulSuppressRule) Match(v *Vul) bool {
+	if r.PocFile != v.PocFile {
+		return false
+	}
+	if r.Host != "" {
+		return r.Host == v.Host
+	}
+	if r.Pattern == "" {
+		return false
+	}
+	if r.re == nil {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return false
+		}
+		r.re = re
+	}
+	return r.re.MatchString(v.Url) || r.re.MatchString(v.Authority)
+}
+
+// VulSuppressRuleModel 抑制规则模型
+type VulSuppressRuleModel struct {
+	coll *mongo.Collection
+}
+
+func NewVulSuppressRuleModel(db *mongo.Database, workspaceId string) *VulSuppressRuleModel {
+	coll := db.Collection(workspaceId + "_vul_suppress")
+
+	// 创建索引
+	ctx := context.Background()
+	coll.Indexes().CreateOne(ctx, mongo.IndexModel{
+		Keys: bson.D{{Key: "status", Value: 1}, {Key: "pocfile", Value: 1}},
+	})
+
+	return &VulSuppressRuleModel{coll: coll}
+}
+
+func (m *VulSuppressRuleModel) Insert(ctx context.Context, doc *VulSuppressRule) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	if doc.Status == "" {
+		doc.Status = "enable"
+	}
+	_, err := m.coll.InsertOne(ctx, doc)
+	return err
+}
+
+func (m *VulSuppressRuleModel) FindById(ctx context.Context, id string) (*VulSuppressRule, error) {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, err
+	}
+	var doc VulSuppressRule
+	err = m.coll.FindOne(ctx, bson.M{"_id": oid}).Decode(&doc)
+	return &doc, err
+}
+
+// FindAll 查找全部规则
+func (m *VulSuppressRuleModel) FindAll(ctx context.Context) ([]VulSuppressRule, error) {
+	return m.find(ctx, bson.M{})
+}
+
+// FindEnabled 查找启用的规则
+func (m *VulSuppressRuleModel) FindEnabled(ctx context.Context) ([]VulSuppressRule, error) {
+	return m.find(ctx, bson.M{"status": "enable"})
+}
+
+func (m *VulSuppressRuleModel) find(ctx context.Context, filter bson.M) ([]VulSuppressRule, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "create_time", Value: -1}})
+	cursor, err := m.coll.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var docs []VulSuppressRule
+	if err = cursor.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+	return docs, nil
+}
+
+// ExistsHostRule 是否已存在相同的 模板+主机 规则
+func (m *VulSuppressRuleModel) ExistsHostRule(ctx context.Context, pocFile, host string) (bool, error) {
+	count, err := m.coll.CountDocuments(ctx, bson.M{"pocfile": pocFile, "host": host})
+	return count > 0, err
+}
+
+func (m *VulSuppressRuleModel) Update(ctx context.Context, id string, update bson.M) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	update["update_time"] = time.Now()
+	_, err = m.coll.UpdateOne(ctx, bson.M{"_id": oid}, bson.M{"$set": update})
+	return err
+}
+
+// IncrHit 记录规则命中
+func (m *VulSuppressRuleModel) IncrHit(ctx context.Context, id primitive.ObjectID, count int) error {
+	_, err := m.coll.UpdateOne(ctx, bson.M{"_id": id}, bson.M{
+		"$inc": bson.M{"hit_count": count},
+		"$set": bson.M{"last_hit": time.Now()},
+	})
+	return err
+}
+
+func (m *VulSuppressRuleModel) Delete(ctx context.Context, id string) error {
+	oid, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return err
+	}
+	_, err = m.coll.DeleteOne(ctx, bson.M{"_id": oid})
+	return err
+}
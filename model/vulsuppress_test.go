@@ -0,0 +1,41 @@
+package model
+
+import "testing"
+
+// TestVulSuppressRuleMatch 测试抑制规则匹配：模板+主机精确匹配，模板+正则匹配 URL 或 authority
+func TestVulSuppressRuleMatch(t *testing.T) {
+	vul := &Vul{
+		Host:      "10.0.0.1",
+		Port:      8080,
+		Authority: "10.0.0.1:8080",
+		Url:       "http://10.0.0.1:8080/admin/login",
+		PocFile:   "weak-login.yaml",
+	}
+	tests := []struct {
+		name string
+		rule VulSuppressRule
+		want bool
+	}{
+		{"template and host", VulSuppressRule{PocFile: "weak-login.yaml", Host: "10.0.0.1"}, true},
+		{"other host", VulSuppressRule{PocFile: "weak-login.yaml", Host: "10.0.0.2"}, false},
+		{"other template", VulSuppressRule{PocFile: "CVE-2021-44228", Host: "10.0.0.1"}, false},
+		{"host wins over pattern", VulSuppressRule{PocFile: "weak-login.yaml", Host: "10.0.0.2", Pattern: ".*"}, false},
+		{"pattern matches url", VulSuppressRule{PocFile: "weak-login.yaml", Pattern: `/admin/`}, true},
+		{"pattern matches authority", VulSuppressRule{PocFile: "weak-login.yaml", Pattern: `^10\.0\.0\.\d+:8080$`}, true},
+		{"pattern mismatch", VulSuppressRule{PocFile: "weak-login.yaml", Pattern: `^https://`}, false},
+		{"pattern other template", VulSuppressRule{PocFile: "other.yaml", Pattern: `.*`}, false},
+		{"invalid pattern", VulSuppressRule{PocFile: "weak-login.yaml", Pattern: `(`}, false},
+		{"template only", VulSuppressRule{PocFile: "weak-login.yaml"}, false},
+		{"empty rule", VulSuppressRule{}, false},
+	}
+	for _, tt := range tests {
+		rule := tt.rule
+		if got := rule.Match(vul); got != tt.want {
+			t.Errorf("%s: Match() = %v, want %v", tt.name, got, tt.want)
+		}
+		// 第二次匹配使用编译缓存，结果不变
+		if got := rule.Match(vul); got != tt.want {
+			t.Errorf("%s: cached Match() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
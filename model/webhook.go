@@ -0,0 +1,202 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Webhook 事件类型
+const (
+	WebhookEventAssetCreated      = "asset.created"
+	WebhookEventAssetChanged      = "asset.changed"
+	WebhookEventVulCreated        = "vul.created"
+	WebhookEventTaskStatusChanged = "task.status_changed"
+	WebhookEventWorkerOffline     = "worker.offline"
+	WebhookEventPing              = "ping" // 测试订阅时发送
+)
+
+// WebhookEvents 可订阅的事件
+var WebhookEvents = []string{
+	WebhookEventAssetCreated,
+	WebhookEventAssetChanged,
+	WebhookEventVulCreated,
+	WebhookEventTaskStatusChanged,
+	WebhookEventWorkerOffline,
+}
+
+// Webhook 投递状态
+const (
+	WebhookDeliveryPending  = "pending"  // 等待投递
+	WebhookDeliveryRetrying = "retrying" // 投递失败，等待重试
+	WebhookDeliverySuccess  = "success"
+	WebhookDeliveryFailed   = "failed" // 超过最大重试次数
+)
+
+// WebhookSubscription Webhook订阅
+type WebhookSubscription struct {
+	Id           primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name         string             `bson:"name" json:"name"`
+	Url          string             `bson:"url" json:"url"`
+	Secret       string             `bson:"secret" json:"-"`                             // HMAC 签名密钥
+	Events       []string           `bson:"events" json:"events"`                        // 订阅的事件，为空表示全部
+	WorkspaceIds []string           `bson:"workspace_ids,omitempty" json:"workspaceIds"` // 限定工作空间，为空表示全部
+	Headers      map[string]string  `bson:"headers,omitempty" json:"headers"`            // 自定义请求头
+	Status       string             `bson:"status" json:"status"`                        // enable/disable
+	CreateTime   time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime   time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// Match 是否订阅了该工作空间的事件。worker.offline 等全局事件的 workspaceId 为空
+func (s *WebhookSubscription) Match(event, workspaceId string) bool {
+	if len(s.Events) > 0 && !containsString(s.Events, event) {
+		return false
+	}
+	if workspaceId != "" && len(s.WorkspaceIds) > 0 && !containsString(s.WorkspaceIds, workspaceId) {
+		return false
+	}
+	return true
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookSubscriptionModel Webhook订阅模型
+type WebhookSubscriptionModel struct {
+	*BaseModel[WebhookSubscription]
+}
+
+// NewWebhookSubscriptionModel 创建Webhook订阅模型
+func NewWebhookSubscriptionModel(db *mongo.Database) *WebhookSubscriptionModel {
+	return &WebhookSubscriptionModel{
+		BaseModel: NewBaseModel[WebhookSubscription](db.Collection("webhook_subscription")),
+	}
+}
+
+// Create 新增订阅
+func (m *WebhookSubscriptionModel) Create(ctx context.Context, doc *WebhookSubscription) error {
+	if doc.Id.IsZero() {
+		doc.Id = primitive.NewObjectID()
+	}
+	now := time.Now()
+	doc.CreateTime = now
+	doc.UpdateTime = now
+	if doc.Status == "" {
+		doc.Status = StatusEnable
+	}
+	return m.Insert(ctx, doc)
+}
+
+// FindEnabled 查找启用的订阅
+func (m *WebhookSubscriptionModel) FindEnabled(ctx context.Context) ([]WebhookSubscription, error) {
+	return m.Find(ctx, bson.M{"status": StatusEnable}, 0, 0)
+}
+
+// WebhookDelivery Webhook投递记录
+type WebhookDelivery struct {
+	Id               primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SubscriptionId   string             `bson:"subscription_id" json:"subscriptionId"`
+	SubscriptionName string             `bson:"subscription_name" json:"subscriptionName"`
+	EventId          string             `bson:"event_id" json:"eventId"` // 重放时保持不变，便于接收方去重
+	Event            string             `bson:"event" json:"event"`
+	WorkspaceId      string             `bson:"workspace_id,omitempty" json:"workspaceId"`
+	Payload          string             `bson:"payload" json:"payload"` // 请求体JSON
+	Status           string             `bson:"status" json:"status"`
+	Attempts         int                `bson:"attempts" json:"attempts"`
+	NextRetryTime    time.Time          `bson:"next_retry_time" json:"nextRetryTime"` // 下次投递时间，投递中时作为租约过期时间
+	ResponseCode     int                `bson:"response_code,omitempty" json:"responseCode"`
+	ResponseBody     string             `bson:"response_body,omitempty" json:"responseBody"`
+	LastError        string             `bson:"last_error,omitempty" json:"lastError"`
+	Duration         int64              `bson:"duration" json:"duration"` // 最后一次投递耗时(毫秒)
+	ReplayOf         string             `bson:"replay_of,omitempty" json:"replayOf"`
+	CreateTime       time.Time          `bson:"create_time" json:"createTime"`
+	UpdateTime       time.Time          `bson:"update_time" json:"updateTime"`
+}
+
+// WebhookDeliveryModel Webhook投递记录模型
+type WebhookDeliveryModel struct {
+	*BaseModel[WebhookDelivery]
+}
+
+// webhookDeliveryTTL 投递记录保留时间
+const webhookDeliveryTTL = 30 * 24 * time.Hour
+
+// NewWebhookDeliveryModel 创建Webhook投递记录模型
+func NewWebhookDeliveryModel(db *mongo.Database) *WebhookDeliveryModel {
+	m := &WebhookDeliveryModel{
+		BaseModel: NewBaseModel[WebhookDelivery](db.Collection("webhook_delivery")),
+	}
+	m.EnsureIndexes(context.Background(), []mongo.IndexModel{
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_retry_time", Value: 1}}},
+		{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "create_time", Value: -1}}},
+		{Keys: bson.D{{Key: "create_time", Value: 1}}, Options: options.Index().SetExpireAfterSeconds(int32(webhookDeliveryTTL.Seconds()))},
+	})
+	return m
+}
+
+// InsertMany 批量新增投递记录
+func (m *WebhookDeliveryModel) InsertMany(ctx context.Context, docs []*WebhookDelivery) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	now := time.Now()
+	list := make([]interface{}, 0, len(docs))
+	for _, doc := range docs {
+		if doc.Id.IsZero() {
+			doc.Id = primitive.NewObjectID()
+		}
+		doc.CreateTime = now
+		doc.UpdateTime = now
+		list = append(list, doc)
+	}
+	_, err := m.Coll.InsertMany(ctx, list, options.InsertMany().SetOrdered(false))
+	return err
+}
+
+// ClaimDue 领取一条到期待投递的记录，并把下次投递时间推迟 lease 作为租约，避免多个实例重复投递
+func (m *WebhookDeliveryModel) ClaimDue(ctx context.Context, lease time.Duration) (*WebhookDelivery, error) {
+	now := time.Now()
+	filter := bson.M{
+		"status":          bson.M{"$in": []string{WebhookDeliveryPending, WebhookDeliveryRetrying}},
+		"next_retry_time": bson.M{"$lte": now},
+	}
+	update := bson.M{"$set": bson.M{"next_retry_time": now.Add(lease), "update_time": now}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_retry_time", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var doc WebhookDelivery
+	err := m.Coll.FindOneAndUpdate(ctx, filter, update, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &doc, nil
+}
+
+// UpdateResult 记录投递结果
+func (m *WebhookDeliveryModel) UpdateResult(ctx context.Context, id primitive.ObjectID, update bson.M) error {
+	return m.UpdateOne(ctx, bson.M{"_id": id}, update)
+}
+
+// Search 分页查询投递记录，按创建时间倒序
+func (m *WebhookDeliveryModel) Search(ctx context.Context, filter bson.M, page, pageSize int) ([]WebhookDelivery, int64, error) {
+	total, err := m.Count(ctx, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	docs, err := m.Find(ctx, filter, page, pageSize)
+	return docs, total, err
+}
@@ -15,10 +15,36 @@ type Workspace struct {
 	Name        string             `bson:"name" json:"name"`
 	Description string             `bson:"description" json:"description"`
 	Status      string             `bson:"status" json:"status"`
+	Scope       *ScanScope         `bson:"scope,omitempty" json:"scope,omitempty"`          // 扫描范围，为空不限制
+	RateLimit   *RateLimit         `bson:"rate_limit,omitempty" json:"rateLimit,omitempty"` // 限速预算，为空不限速
+	Window      *MaintenanceWindow `bson:"window,omitempty" json:"window,omitempty"`        // 维护窗口，为空不限制扫描时间
 	CreateTime  time.Time          `bson:"create_time" json:"createTime"`
 	UpdateTime  time.Time          `bson:"update_time" json:"updateTime"`
 }
 
+// ScanScope 工作空间扫描范围，创建任务、拆分目标和 Worker 执行各扫描阶段前校验
+type ScanScope struct {
+	AllowedCidrs   []string `bson:"allowed_cidrs" json:"allowedCidrs"`     // 允许的IP/CIDR，为空不限制IP
+	AllowedDomains []string `bson:"allowed_domains" json:"allowedDomains"` // 允许的域名（含子域名），为空不限制域名
+	ExcludedHosts  []string `bson:"excluded_hosts" json:"excludedHosts"`   // 排除的IP/CIDR/域名
+	ExcludedPorts  string   `bson:"excluded_ports" json:"excludedPorts"`   // 排除的端口，如 22,3389,8000-8100
+}
+
+// RateLimit 工作空间限速预算，所有 Worker 和子任务共享，速率为每秒请求数（端口扫描为每秒探测包数），0 表示不限制
+type RateLimit struct {
+	HostRate      int `bson:"host_rate" json:"hostRate"`           // 单个目标主机
+	CIDRRate      int `bson:"cidr_rate" json:"cidrRate"`           // 同一网段
+	CIDRPrefix    int `bson:"cidr_prefix" json:"cidrPrefix"`       // IPv4 网段前缀长度，默认 24
+	WorkspaceRate int `bson:"workspace_rate" json:"workspaceRate"` // 整个工作空间
+}
+
+// MaintenanceWindow 维护窗口，窗口外定时任务推迟执行、运行中的任务自动暂停
+type MaintenanceWindow struct {
+	Timezone      string   `bson:"timezone" json:"timezone"`            // IANA 时区，为空使用服务器时区
+	AllowedHours  []string `bson:"allowed_hours" json:"allowedHours"`   // 允许扫描的时段，如 09:00-18:00
+	BlackoutDates []string `bson:"blackout_dates" json:"blackoutDates"` // 禁止扫描的日期，如 2026-12-31 或 2026-12-24~2027-01-02
+}
+
 type WorkspaceModel struct {
 	coll *mongo.Collection
 }
@@ -0,0 +1,226 @@
+package assetdiff
+
+import (
+	"context"
+	"time"
+
+	"cscan/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// Builder 从数据库构建资产快照
+type Builder struct {
+	assetModel   *model.AssetModel
+	historyModel *model.AssetHistoryModel
+	vulModel     *model.VulModel
+}
+
+// NewBuilder 创建快照构建器
+func NewBuilder(assetModel *model.AssetModel, historyModel *model.AssetHistoryModel, vulModel *model.VulModel) *Builder {
+	return &Builder{
+		assetModel:   assetModel,
+		historyModel: historyModel,
+		vulModel:     vulModel,
+	}
+}
+
+// taskIdFilter 匹配任务及其子任务ID（ObjectID 和 UUID 两种格式）
+func taskIdFilter(field string, task *model.MainTask) bson.M {
+	ids := []string{task.Id.Hex()}
+	if task.TaskId != "" {
+		ids = append(ids, task.TaskId)
+	}
+	var or []bson.M
+	for _, id := range ids {
+		or = append(or, bson.M{field: id}, bson.M{field: bson.M{"$regex": "^" + id + "-\\d+$"}})
+	}
+	return bson.M{"$or": or}
+}
+
+// ByTask 构建某个任务扫描到的资产快照。
+// 资产当前记录属于该任务时取当前状态，已被后续扫描覆盖的取该任务留下的历史记录。
+func (b *Builder) ByTask(ctx context.Context, task *model.MainTask) (*Snapshot, error) {
+	snapshotTime := task.CreateTime
+	if task.EndTime != nil {
+		snapshotTime = *task.EndTime
+	}
+	s := NewSnapshot(snapshotTime)
+
+	assets, err := b.assetModel.FindBrief(ctx, taskIdFilter("taskId", task))
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		s.AddAsset(fromAsset(&assets[i]))
+	}
+
+	histories, err := b.historyModel.FindBrief(ctx, taskIdFilter("taskId", task))
+	if err != nil {
+		return nil, err
+	}
+	// 同一资产可能有多条历史（定时任务重复执行），取最后一条
+	latest := make(map[string]*model.AssetHistory)
+	for i := range histories {
+		h := &histories[i]
+		if cur, ok := latest[h.Authority]; !ok || h.CreateTime.After(cur.CreateTime) {
+			latest[h.Authority] = h
+		}
+	}
+	for _, h := range latest {
+		s.AddAsset(fromHistory(h))
+	}
+
+	vulFilter := taskIdFilter("task_id", task)
+	if task.StartTime != nil {
+		// 漏洞只保留最后一次发现的任务ID，按任务执行时间段补充
+		window := bson.M{
+			"first_seen_time": bson.M{"$lte": snapshotTime},
+			"last_seen_time":  bson.M{"$gte": *task.StartTime},
+		}
+		vulFilter = bson.M{"$or": []bson.M{vulFilter, window}}
+	}
+	if err := b.addVuls(ctx, s, vulFilter); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// At 构建某一时刻的资产快照（该时刻已发现的全部资产及其当时的状态）
+func (b *Builder) At(ctx context.Context, t time.Time) (*Snapshot, error) {
+	s := NewSnapshot(t)
+
+	assets, err := b.assetModel.FindBrief(ctx, bson.M{"create_time": bson.M{"$lte": t}})
+	if err != nil {
+		return nil, err
+	}
+	superseded, err := b.supersededAfter(ctx, t)
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		a := &assets[i]
+		if h, ok := superseded[a.Id.Hex()]; ok {
+			s.AddAsset(fromHistory(h))
+		} else {
+			s.AddAsset(fromAsset(a))
+		}
+	}
+
+	err = b.addVuls(ctx, s, bson.M{"$or": []bson.M{
+		{"first_seen_time": bson.M{"$lte": t}},
+		{"first_seen_time": bson.M{"$exists": false}, "create_time": bson.M{"$lte": t}},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Between 构建时间段 (start, end] 内扫描到的资产快照，资产状态取 end 时刻的状态
+func (b *Builder) Between(ctx context.Context, start, end time.Time) (*Snapshot, error) {
+	s := NewSnapshot(end)
+	window := bson.M{"$gt": start, "$lte": end}
+
+	superseded, err := b.supersededAfter(ctx, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// 时间段内被新一轮扫描覆盖过的资产也说明在时间段内被扫描到
+	histories, err := b.historyModel.FindBrief(ctx, bson.M{"create_time": window})
+	if err != nil {
+		return nil, err
+	}
+	observed := make(map[string]bool)
+	for _, h := range histories {
+		observed[h.AssetId] = true
+	}
+
+	assets, err := b.assetModel.FindBrief(ctx, bson.M{"create_time": bson.M{"$lte": end}})
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		a := &assets[i]
+		id := a.Id.Hex()
+		inWindow := a.UpdateTime.After(start) && !a.UpdateTime.After(end)
+		if !inWindow && !observed[id] {
+			continue
+		}
+		if h, ok := superseded[id]; ok {
+			s.AddAsset(fromHistory(h))
+		} else {
+			s.AddAsset(fromAsset(a))
+		}
+	}
+
+	err = b.addVuls(ctx, s, bson.M{"$or": []bson.M{
+		{"last_seen_time": window},
+		{"last_seen_time": bson.M{"$exists": false}, "update_time": window},
+	}})
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// supersededAfter 返回每个资产在 t 之后最早被覆盖的历史记录，即该资产在 t 时刻的状态
+func (b *Builder) supersededAfter(ctx context.Context, t time.Time) (map[string]*model.AssetHistory, error) {
+	histories, err := b.historyModel.FindBrief(ctx, bson.M{"create_time": bson.M{"$gt": t}})
+	if err != nil {
+		return nil, err
+	}
+	result := make(map[string]*model.AssetHistory)
+	for i := range histories {
+		h := &histories[i]
+		if cur, ok := result[h.AssetId]; !ok || h.CreateTime.Before(cur.CreateTime) {
+			result[h.AssetId] = h
+		}
+	}
+	return result, nil
+}
+
+func (b *Builder) addVuls(ctx context.Context, s *Snapshot, filter bson.M) error {
+	vuls, err := b.vulModel.FindBrief(ctx, filter)
+	if err != nil {
+		return err
+	}
+	for _, v := range vuls {
+		s.AddVul(&VulState{
+			Authority: v.Authority,
+			Host:      v.Host,
+			Port:      v.Port,
+			Url:       v.Url,
+			PocFile:   v.PocFile,
+			Severity:  v.Severity,
+		})
+	}
+	return nil
+}
+
+func fromAsset(a *model.Asset) *AssetState {
+	return &AssetState{
+		Authority: a.Authority,
+		Host:      a.Host,
+		Port:      a.Port,
+		Service:   a.Service,
+		Title:     a.Title,
+		App:       a.App,
+		Banner:    a.Banner,
+		Cert:      a.Cert,
+	}
+}
+
+func fromHistory(h *model.AssetHistory) *AssetState {
+	return &AssetState{
+		Authority: h.Authority,
+		Host:      h.Host,
+		Port:      h.Port,
+		Service:   h.Service,
+		Title:     h.Title,
+		App:       h.App,
+		Banner:    h.Banner,
+		Cert:      h.Cert,
+	}
+}
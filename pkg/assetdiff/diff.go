@@ -0,0 +1,246 @@
+package assetdiff
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"cscan/model"
+)
+
+// maxValueLen 变化字段值的最大长度（证书、Banner 可能很长）
+const maxValueLen = 512
+
+// AssetState 某一时刻的资产状态
+type AssetState struct {
+	Authority string   `json:"authority"`
+	Host      string   `json:"host"`
+	Port      int      `json:"port"`
+	Service   string   `json:"service"`
+	Title     string   `json:"title"`
+	App       []string `json:"app"`
+	Banner    string   `json:"banner"`
+	Cert      string   `json:"cert"`
+}
+
+// VulState 某一时刻的漏洞状态
+type VulState struct {
+	Authority string `json:"authority"`
+	Host      string `json:"host"`
+	Port      int    `json:"port"`
+	Url       string `json:"url"`
+	PocFile   string `json:"pocFile"`
+	Severity  string `json:"severity"`
+}
+
+// Key 漏洞唯一标识，与 VulModel.Upsert 的去重条件一致
+func (v *VulState) Key() string {
+	return v.Host + ":" + strconv.Itoa(v.Port) + "|" + v.PocFile + "|" + v.Url
+}
+
+// Snapshot 资产快照
+type Snapshot struct {
+	Time   time.Time
+	Assets map[string]*AssetState // authority -> state
+	Vuls   map[string]*VulState   // VulState.Key() -> state
+}
+
+// NewSnapshot 创建空快照
+func NewSnapshot(t time.Time) *Snapshot {
+	return &Snapshot{
+		Time:   t,
+		Assets: make(map[string]*AssetState),
+		Vuls:   make(map[string]*VulState),
+	}
+}
+
+// AddAsset 添加资产，已存在时不覆盖
+func (s *Snapshot) AddAsset(a *AssetState) {
+	if a.Authority == "" {
+		a.Authority = a.Host + ":" + strconv.Itoa(a.Port)
+	}
+	if _, ok := s.Assets[a.Authority]; !ok {
+		s.Assets[a.Authority] = a
+	}
+}
+
+// AddVul 添加漏洞
+func (s *Snapshot) AddVul(v *VulState) {
+	s.Vuls[v.Key()] = v
+}
+
+// hosts 快照中出现过的主机
+func (s *Snapshot) hosts() map[string]bool {
+	hosts := make(map[string]bool, len(s.Assets))
+	for _, a := range s.Assets {
+		hosts[a.Host] = true
+	}
+	for _, v := range s.Vuls {
+		hosts[v.Host] = true
+	}
+	return hosts
+}
+
+// FieldChange 字段变化
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
+// Change 资产变化
+type Change struct {
+	Authority string        `json:"authority"`
+	Host      string        `json:"host"`
+	Port      int           `json:"port"`
+	Fields    []FieldChange `json:"fields"`
+}
+
+// Result 对比结果
+type Result struct {
+	BaseTime     time.Time     `json:"baseTime"`
+	TargetTime   time.Time     `json:"targetTime"`
+	NewHosts     []string      `json:"newHosts"`
+	NewPorts     []*AssetState `json:"newPorts"`
+	ClosedPorts  []*AssetState `json:"closedPorts"`
+	Changes      []*Change     `json:"changes"`
+	NewVuls      []*VulState   `json:"newVuls"`
+	ResolvedVuls []*VulState   `json:"resolvedVuls"`
+}
+
+// Compare 对比两个快照。
+// 目标快照通常只覆盖一次扫描的范围，因此关闭的端口和已修复的漏洞只统计目标快照中出现过的主机，
+// 避免把未扫描的主机误判为下线。
+func Compare(base, target *Snapshot) *Result {
+	r := &Result{BaseTime: base.Time, TargetTime: target.Time}
+	baseHosts := base.hosts()
+	targetHosts := target.hosts()
+
+	for host := range targetHosts {
+		if !baseHosts[host] {
+			r.NewHosts = append(r.NewHosts, host)
+		}
+	}
+
+	for authority, t := range target.Assets {
+		b, ok := base.Assets[authority]
+		if !ok {
+			r.NewPorts = append(r.NewPorts, t)
+			continue
+		}
+		if fields := compareAsset(b, t); len(fields) > 0 {
+			r.Changes = append(r.Changes, &Change{Authority: authority, Host: t.Host, Port: t.Port, Fields: fields})
+		}
+	}
+	for authority, b := range base.Assets {
+		if _, ok := target.Assets[authority]; !ok && targetHosts[b.Host] {
+			r.ClosedPorts = append(r.ClosedPorts, b)
+		}
+	}
+
+	for key, v := range target.Vuls {
+		if _, ok := base.Vuls[key]; !ok {
+			r.NewVuls = append(r.NewVuls, v)
+		}
+	}
+	for key, v := range base.Vuls {
+		if _, ok := target.Vuls[key]; !ok && targetHosts[v.Host] {
+			r.ResolvedVuls = append(r.ResolvedVuls, v)
+		}
+	}
+
+	r.sort()
+	return r
+}
+
+// compareAsset 对比单个资产。目标值为空时视为本次未采集该字段，不记为变化
+func compareAsset(b, t *AssetState) []FieldChange {
+	var fields []FieldChange
+	add := func(field, old, new string) {
+		if new != "" && old != new {
+			fields = append(fields, FieldChange{Field: field, Old: truncate(old), New: truncate(new)})
+		}
+	}
+	add("service", b.Service, t.Service)
+	add("title", b.Title, t.Title)
+	add("app", joinApps(b.App), joinApps(t.App))
+	add("banner", b.Banner, t.Banner)
+	add("cert", b.Cert, t.Cert)
+	return fields
+}
+
+// AssetChanges 对比资产更新前后的字段变化，用于单条资产保存时判断是否发生变化
+func AssetChanges(old, new *model.Asset) []FieldChange {
+	return compareAsset(fromAsset(old), fromAsset(new))
+}
+
+// joinApps 应用列表排序去重后拼接，忽略顺序差异
+func joinApps(apps []string) string {
+	set := make(map[string]bool, len(apps))
+	list := make([]string, 0, len(apps))
+	for _, app := range apps {
+		app = strings.TrimSpace(app)
+		if app != "" && !set[app] {
+			set[app] = true
+			list = append(list, app)
+		}
+	}
+	sort.Strings(list)
+	return strings.Join(list, ",")
+}
+
+func truncate(s string) string {
+	if len(s) <= maxValueLen {
+		return s
+	}
+	return strings.ToValidUTF8(s[:maxValueLen], "") + "..."
+}
+
+func (r *Result) sort() {
+	sort.Strings(r.NewHosts)
+	sortAssets := func(list []*AssetState) {
+		sort.Slice(list, func(i, j int) bool { return list[i].Authority < list[j].Authority })
+	}
+	sortVuls := func(list []*VulState) {
+		sort.Slice(list, func(i, j int) bool { return list[i].Key() < list[j].Key() })
+	}
+	sortAssets(r.NewPorts)
+	sortAssets(r.ClosedPorts)
+	sortVuls(r.NewVuls)
+	sortVuls(r.ResolvedVuls)
+	sort.Slice(r.Changes, func(i, j int) bool { return r.Changes[i].Authority < r.Changes[j].Authority })
+}
+
+// Empty 是否没有任何变化
+func (r *Result) Empty() bool {
+	return len(r.NewHosts) == 0 && len(r.NewPorts) == 0 && len(r.ClosedPorts) == 0 &&
+		len(r.Changes) == 0 && len(r.NewVuls) == 0 && len(r.ResolvedVuls) == 0
+}
+
+// Summary 对比结果摘要
+func (r *Result) Summary() *model.AssetDiffSummary {
+	return &model.AssetDiffSummary{
+		NewHosts:      len(r.NewHosts),
+		NewPorts:      len(r.NewPorts),
+		ClosedPorts:   len(r.ClosedPorts),
+		ChangedAssets: len(r.Changes),
+		NewVuls:       len(r.NewVuls),
+		ResolvedVuls:  len(r.ResolvedVuls),
+		BaseTime:      r.BaseTime,
+		TargetTime:    r.TargetTime,
+	}
+}
+
+// SummaryText 摘要文本，用于通知
+func SummaryText(s *model.AssetDiffSummary) string {
+	if s == nil {
+		return ""
+	}
+	if s.NewHosts+s.NewPorts+s.ClosedPorts+s.ChangedAssets+s.NewVuls+s.ResolvedVuls == 0 {
+		return "与上次执行相比无变化"
+	}
+	return fmt.Sprintf("新增主机 %d，新增端口 %d，关闭端口 %d，资产变更 %d，新增漏洞 %d，已修复漏洞 %d",
+		s.NewHosts, s.NewPorts, s.ClosedPorts, s.ChangedAssets, s.NewVuls, s.ResolvedVuls)
+}
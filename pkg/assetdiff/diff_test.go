@@ -0,0 +1,59 @@
+package assetdiff
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCompare(t *testing.T) {
+	base := NewSnapshot(time.Unix(0, 0))
+	base.AddAsset(&AssetState{Host: "10.0.0.1", Port: 80, Title: "old", App: []string{"nginx", "php"}})
+	base.AddAsset(&AssetState{Host: "10.0.0.1", Port: 22, Service: "ssh"})
+	base.AddAsset(&AssetState{Host: "10.0.0.2", Port: 3306, Service: "mysql"})
+	base.AddVul(&VulState{Host: "10.0.0.1", Port: 80, PocFile: "a.yaml"})
+	base.AddVul(&VulState{Host: "10.0.0.2", Port: 3306, PocFile: "b.yaml"})
+
+	target := NewSnapshot(time.Unix(100, 0))
+	target.AddAsset(&AssetState{Host: "10.0.0.1", Port: 80, Title: "new", App: []string{"php", "nginx"}})
+	target.AddAsset(&AssetState{Host: "10.0.0.1", Port: 443, Service: "https"})
+	target.AddAsset(&AssetState{Host: "10.0.0.3", Port: 80})
+	target.AddVul(&VulState{Host: "10.0.0.1", Port: 443, PocFile: "c.yaml"})
+
+	r := Compare(base, target)
+
+	if len(r.NewHosts) != 1 || r.NewHosts[0] != "10.0.0.3" {
+		t.Errorf("NewHosts = %v", r.NewHosts)
+	}
+	if len(r.NewPorts) != 2 || r.NewPorts[0].Authority != "10.0.0.1:443" {
+		t.Errorf("NewPorts = %+v", r.NewPorts)
+	}
+	// 10.0.0.2 未被扫描，不计入关闭端口和已修复漏洞
+	if len(r.ClosedPorts) != 1 || r.ClosedPorts[0].Authority != "10.0.0.1:22" {
+		t.Errorf("ClosedPorts = %+v", r.ClosedPorts)
+	}
+	if len(r.Changes) != 1 || len(r.Changes[0].Fields) != 1 || r.Changes[0].Fields[0].Field != "title" {
+		t.Errorf("Changes = %+v", r.Changes)
+	}
+	if len(r.NewVuls) != 1 || r.NewVuls[0].PocFile != "c.yaml" {
+		t.Errorf("NewVuls = %+v", r.NewVuls)
+	}
+	if len(r.ResolvedVuls) != 1 || r.ResolvedVuls[0].PocFile != "a.yaml" {
+		t.Errorf("ResolvedVuls = %+v", r.ResolvedVuls)
+	}
+
+	s := r.Summary()
+	if s.NewPorts != 2 || s.ClosedPorts != 1 || s.ChangedAssets != 1 {
+		t.Errorf("Summary = %+v", s)
+	}
+}
+
+func TestCompareIgnoresMissingFields(t *testing.T) {
+	base := NewSnapshot(time.Time{})
+	base.AddAsset(&AssetState{Host: "h", Port: 80, Title: "t", Banner: "b"})
+	target := NewSnapshot(time.Time{})
+	target.AddAsset(&AssetState{Host: "h", Port: 80})
+
+	if r := Compare(base, target); !r.Empty() {
+		t.Errorf("expected no changes, got %+v", r.Changes)
+	}
+}
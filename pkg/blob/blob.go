@@ -0,0 +1,111 @@
+// Package blob 二进制对象存储（截图等大文件），支持本地文件系统、S3 兼容存储和 GridFS
+package blob
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// 存储类型
+const (
+	TypeLocal  = "local"
+	TypeS3     = "s3"
+	TypeGridFS = "gridfs"
+)
+
+// ErrNotFound 对象不存在
+var ErrNotFound = errors.New("blob not found")
+
+// Store 对象存储接口，key 由 Key 生成，内容寻址，相同内容只保存一份
+type Store interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	Exists(ctx context.Context, key string) (bool, error)
+	Delete(ctx context.Context, key string) error
+}
+
+// Config 存储配置
+type Config struct {
+	Type string `json:",default=local,options=local|s3|gridfs"`
+	// 本地存储目录
+	Dir string `json:",default=data/blob"`
+	// S3 兼容存储（AWS S3 / MinIO 等）
+	Endpoint  string `json:",optional"`
+	Region    string `json:",default=us-east-1"`
+	Bucket    string `json:",optional"`
+	AccessKey string `json:",optional"`
+	SecretKey string `json:",optional"`
+	PathStyle bool   `json:",optional"` // MinIO 需设置为 true（path-style 访问）
+	Prefix    string `json:",optional"` // 对象 key 前缀
+	// GridFS bucket 名称
+	GridFSBucket string `json:",default=screenshot"`
+}
+
+// New 按配置创建存储
+func New(c Config, db *mongo.Database) (Store, error) {
+	switch c.Type {
+	case "", TypeLocal:
+		dir := c.Dir
+		if dir == "" {
+			dir = "data/blob"
+		}
+		return NewLocalStore(dir), nil
+	case TypeS3:
+		return NewS3Store(c)
+	case TypeGridFS:
+		if db == nil {
+			return nil, errors.New("gridfs store requires mongodb")
+		}
+		name := c.GridFSBucket
+		if name == "" {
+			name = "screenshot"
+		}
+		return NewGridFSStore(db, name)
+	}
+	return nil, fmt.Errorf("unknown blob store type %q", c.Type)
+}
+
+var keyPattern = regexp.MustCompile(`^[0-9a-f]{64}\.(png|jpg|gif|webp)$`)
+
+// IsKey 判断字符串是否为存储 key（而非内联的 base64 数据）
+func IsKey(s string) bool {
+	return keyPattern.MatchString(s)
+}
+
+// Key 根据内容生成 key：sha256 + 扩展名，同时返回 Content-Type
+func Key(data []byte) (string, string) {
+	sum := sha256.Sum256(data)
+	ct := http.DetectContentType(data)
+	ext := "png"
+	switch ct {
+	case "image/jpeg":
+		ext = "jpg"
+	case "image/gif":
+		ext = "gif"
+	case "image/webp":
+		ext = "webp"
+	default:
+		ct = "image/png"
+	}
+	return hex.EncodeToString(sum[:]) + "." + ext, ct
+}
+
+// ContentType 由 key 的扩展名得到 Content-Type
+func ContentType(key string) string {
+	switch {
+	case len(key) > 4 && key[len(key)-4:] == ".jpg":
+		return "image/jpeg"
+	case len(key) > 4 && key[len(key)-4:] == ".gif":
+		return "image/gif"
+	case len(key) > 5 && key[len(key)-5:] == ".webp":
+		return "image/webp"
+	}
+	return "image/png"
+}
@@ -0,0 +1,62 @@
+package blob
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestKey(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n0000")
+	key, ct := Key(png)
+	if !IsKey(key) || ct != "image/png" || key[len(key)-4:] != ".png" {
+		t.Errorf("Key(png) = %q, %q", key, ct)
+	}
+	if ContentType(key) != "image/png" {
+		t.Errorf("ContentType(%q) = %q", key, ContentType(key))
+	}
+
+	jpg := []byte("\xff\xd8\xff\xe0")
+	if key, ct := Key(jpg); ct != "image/jpeg" || ContentType(key) != "image/jpeg" {
+		t.Errorf("Key(jpeg) = %q, %q", key, ct)
+	}
+
+	for _, s := range []string{"", "iVBORw0KGgo=", "../etc/passwd", "data:image/png;base64,xxx"} {
+		if IsKey(s) {
+			t.Errorf("IsKey(%q) = true", s)
+		}
+	}
+}
+
+func TestLocalStore(t *testing.T) {
+	ctx := context.Background()
+	s := NewLocalStore(t.TempDir())
+	data := []byte("\x89PNG\r\n\x1a\nscreenshot")
+	key, ct := Key(data)
+
+	if ok, err := s.Exists(ctx, key); err != nil || ok {
+		t.Fatalf("Exists before put = %v, %v", ok, err)
+	}
+	if _, err := s.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get before put err = %v", err)
+	}
+	if err := s.Put(ctx, key, data, ct); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if ok, _ := s.Exists(ctx, key); !ok {
+		t.Error("Exists after put = false")
+	}
+	if got, err := s.Get(ctx, key); err != nil || string(got) != string(data) {
+		t.Errorf("Get = %q, %v", got, err)
+	}
+	if err := s.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if ok, _ := s.Exists(ctx, key); ok {
+		t.Error("Exists after delete = true")
+	}
+
+	if err := s.Put(ctx, "../escape.png", data, ct); err == nil {
+		t.Error("Put should reject invalid key")
+	}
+}
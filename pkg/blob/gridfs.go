@@ -0,0 +1,63 @@
+package blob
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/gridfs"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// GridFSStore MongoDB GridFS 存储，文件 _id 即 key
+type GridFSStore struct {
+	bucket *gridfs.Bucket
+	files  *mongo.Collection
+}
+
+func NewGridFSStore(db *mongo.Database, name string) (*GridFSStore, error) {
+	bucket, err := gridfs.NewBucket(db, options.GridFSBucket().SetName(name))
+	if err != nil {
+		return nil, err
+	}
+	return &GridFSStore{bucket: bucket, files: db.Collection(name + ".files")}, nil
+}
+
+func (s *GridFSStore) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	// 内容寻址，已存在则无需重复写入（重复 _id 会留下孤立的 chunk）
+	if ok, err := s.Exists(ctx, key); err != nil || ok {
+		return err
+	}
+	opts := options.GridFSUpload().SetMetadata(bson.M{"contentType": contentType})
+	err := s.bucket.UploadFromStreamWithID(key, key, bytes.NewReader(data), opts)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	return err
+}
+
+func (s *GridFSStore) Get(ctx context.Context, key string) ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := s.bucket.DownloadToStream(key, &buf); err != nil {
+		if errors.Is(err, gridfs.ErrFileNotFound) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *GridFSStore) Exists(ctx context.Context, key string) (bool, error) {
+	n, err := s.files.CountDocuments(ctx, bson.M{"_id": key}, options.Count().SetLimit(1))
+	return n > 0, err
+}
+
+func (s *GridFSStore) Delete(ctx context.Context, key string) error {
+	err := s.bucket.DeleteContext(ctx, key)
+	if errors.Is(err, gridfs.ErrFileNotFound) {
+		return nil
+	}
+	return err
+}
@@ -0,0 +1,327 @@
+// Package geoip 基于本地离线库的IP地理位置和ASN查询
+// 支持 MaxMind GeoLite2/GeoIP2 (.mmdb) City/ASN 库和 ip2region (.xdb) 库
+package geoip
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+// 数据库类型
+const (
+	DBCity   = "city"   // GeoLite2-City / GeoIP2-City
+	DBASN    = "asn"    // GeoLite2-ASN
+	DBRegion = "region" // ip2region
+)
+
+// DBTypes 支持的数据库类型
+var DBTypes = []string{DBRegion, DBCity, DBASN}
+
+var dbFiles = map[string]string{
+	DBCity:   "GeoLite2-City.mmdb",
+	DBASN:    "GeoLite2-ASN.mmdb",
+	DBRegion: "ip2region.xdb",
+}
+
+// MaxDownloadSize 下载数据库的大小上限
+const MaxDownloadSize = 512 * 1024 * 1024
+
+// Info IP归属信息
+type Info struct {
+	Country string `json:"country,omitempty"`
+	Region  string `json:"region,omitempty"`
+	City    string `json:"city,omitempty"`
+	ASN     int    `json:"asn,omitempty"`
+	Org     string `json:"org,omitempty"`
+	ISP     string `json:"isp,omitempty"`
+}
+
+// IsEmpty 是否未查询到任何信息
+func (i Info) IsEmpty() bool {
+	return i == Info{}
+}
+
+// Location 拼接为 "国家 省份 城市" 格式，去除重复的层级（如 "中国 北京 北京"）
+func (i Info) Location() string {
+	parts := make([]string, 0, 3)
+	for _, p := range []string{i.Country, i.Region, i.City} {
+		if p != "" && (len(parts) == 0 || parts[len(parts)-1] != p) {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// ASNName ASN 展示名，如 "AS13335 Cloudflare, Inc."
+func (i Info) ASNName() string {
+	if i.ASN == 0 {
+		return ""
+	}
+	if i.Org == "" {
+		return fmt.Sprintf("AS%d", i.ASN)
+	}
+	return fmt.Sprintf("AS%d %s", i.ASN, i.Org)
+}
+
+// DBStatus 数据库状态
+type DBStatus struct {
+	Type         string    `json:"type"`
+	File         string    `json:"file"`
+	Loaded       bool      `json:"loaded"`
+	Size         int64     `json:"size"`
+	DatabaseType string    `json:"databaseType,omitempty"`
+	BuildTime    time.Time `json:"buildTime"`
+	UpdateTime   time.Time `json:"updateTime"`
+	Error        string    `json:"error,omitempty"`
+}
+
+// Resolver 离线IP查询器，数据库文件保存在 dir 目录，可在运行时替换
+type Resolver struct {
+	dir    string
+	mu     sync.RWMutex
+	city   *mmdbReader
+	asn    *mmdbReader
+	region *xdbReader
+	status map[string]*DBStatus
+}
+
+// NewResolver 创建查询器并加载目录下已有的数据库，加载失败不影响其他库
+func NewResolver(dir string) *Resolver {
+	r := &Resolver{dir: dir, status: make(map[string]*DBStatus)}
+	for _, t := range DBTypes {
+		r.status[t] = &DBStatus{Type: t, File: dbFiles[t]}
+		data, err := os.ReadFile(filepath.Join(dir, dbFiles[t]))
+		if err != nil {
+			if !os.IsNotExist(err) {
+				r.status[t].Error = err.Error()
+			}
+			continue
+		}
+		if err := r.load(t, data); err != nil {
+			r.status[t].Error = err.Error()
+			logx.Errorf("[GeoIP] load %s failed: %v", dbFiles[t], err)
+			continue
+		}
+		if fi, err := os.Stat(filepath.Join(dir, dbFiles[t])); err == nil {
+			r.status[t].UpdateTime = fi.ModTime()
+		}
+		logx.Infof("[GeoIP] loaded %s", dbFiles[t])
+	}
+	return r
+}
+
+// load 解析数据库并替换当前实例
+func (r *Resolver) load(dbType string, data []byte) error {
+	st := &DBStatus{Type: dbType, File: dbFiles[dbType], Size: int64(len(data)), Loaded: true, UpdateTime: time.Now()}
+	switch dbType {
+	case DBCity, DBASN:
+		db, err := openMMDB(data)
+		if err != nil {
+			return err
+		}
+		isASN := strings.Contains(strings.ToLower(db.DatabaseType), "asn")
+		if isASN != (dbType == DBASN) {
+			return fmt.Errorf("database type %q does not match %s", db.DatabaseType, dbType)
+		}
+		st.DatabaseType = db.DatabaseType
+		st.BuildTime = db.BuildTime
+		r.mu.Lock()
+		if dbType == DBCity {
+			r.city = db
+		} else {
+			r.asn = db
+		}
+	case DBRegion:
+		db, err := openXDB(data)
+		if err != nil {
+			return err
+		}
+		st.DatabaseType = "ip2region"
+		st.BuildTime = db.BuildTime
+		r.mu.Lock()
+		r.region = db
+	default:
+		return fmt.Errorf("unknown database type %q", dbType)
+	}
+	r.status[dbType] = st
+	r.mu.Unlock()
+	return nil
+}
+
+// Available 是否加载了任一数据库
+func (r *Resolver) Available() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.city != nil || r.asn != nil || r.region != nil
+}
+
+// Lookup 查询IP归属。ip2region 的中文地名优先，缺失的字段由 GeoLite2 补充
+func (r *Resolver) Lookup(ipStr string) Info {
+	var info Info
+	ip := net.ParseIP(strings.TrimSpace(ipStr))
+	if ip == nil {
+		return info
+	}
+
+	r.mu.RLock()
+	city, asn, region := r.city, r.asn, r.region
+	r.mu.RUnlock()
+
+	if region != nil {
+		if s, err := region.Lookup(ip); err == nil && s != "" {
+			info = parseRegion(s)
+		}
+	}
+	if city != nil && (info.Country == "" || info.City == "") {
+		if rec, err := city.Lookup(ip); err == nil && rec != nil {
+			if info.Country == "" {
+				info.Country = mmdbName(mmdbPath(rec, "country", "names"))
+			}
+			if info.Region == "" {
+				if subs, ok := rec["subdivisions"].([]interface{}); ok && len(subs) > 0 {
+					if sub, ok := subs[0].(map[string]interface{}); ok {
+						info.Region = mmdbName(sub["names"])
+					}
+				}
+			}
+			if info.City == "" {
+				info.City = mmdbName(mmdbPath(rec, "city", "names"))
+			}
+		}
+	}
+	if asn != nil {
+		if rec, err := asn.Lookup(ip); err == nil && rec != nil {
+			info.ASN = int(toUint64(rec["autonomous_system_number"]))
+			info.Org, _ = rec["autonomous_system_organization"].(string)
+		}
+	}
+	return info
+}
+
+// mmdbName 从 names 字段中取中文名，没有则取英文名
+func mmdbName(v interface{}) string {
+	names, ok := v.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	for _, lang := range []string{"zh-CN", "en"} {
+		if s, ok := names[lang].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+// Status 返回各数据库状态
+func (r *Resolver) Status() []DBStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]DBStatus, 0, len(DBTypes))
+	for _, t := range DBTypes {
+		list = append(list, *r.status[t])
+	}
+	return list
+}
+
+// Install 校验并安装数据库文件，支持 .mmdb/.xdb 原始文件以及 MaxMind 提供的 tar.gz/gz 包
+func (r *Resolver) Install(dbType string, data []byte) error {
+	name, ok := dbFiles[dbType]
+	if !ok {
+		return fmt.Errorf("unknown database type %q", dbType)
+	}
+	data, err := unpack(data, filepath.Ext(name))
+	if err != nil {
+		return err
+	}
+	// 先解析校验，避免写入损坏的文件
+	if err := r.load(dbType, data); err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(r.dir, 0755); err != nil {
+		return err
+	}
+	tmp := filepath.Join(r.dir, name+".tmp")
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(r.dir, name))
+}
+
+// Download 从URL下载并安装数据库
+func (r *Resolver) Download(ctx context.Context, dbType, url string) error {
+	if _, ok := dbFiles[dbType]; !ok {
+		return fmt.Errorf("unknown database type %q", dbType)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("download failed: HTTP %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, MaxDownloadSize+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > MaxDownloadSize {
+		return errors.New("database file too large")
+	}
+	return r.Install(dbType, data)
+}
+
+// unpack 解压 gzip 或 tar.gz 包，取出扩展名为 ext 的文件
+func unpack(data []byte, ext string) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0x1f || data[1] != 0x8b {
+		return data, nil
+	}
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer gz.Close()
+	raw, err := io.ReadAll(io.LimitReader(gz, MaxDownloadSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) > MaxDownloadSize {
+		return nil, errors.New("database file too large")
+	}
+
+	// tar 包头部 257 字节处为 ustar 标记
+	if len(raw) < 262 || string(raw[257:262]) != "ustar" {
+		return raw, nil
+	}
+	tr := tar.NewReader(bytes.NewReader(raw))
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil, fmt.Errorf("no %s file found in archive", ext)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && strings.EqualFold(filepath.Ext(hdr.Name), ext) {
+			return io.ReadAll(tr)
+		}
+	}
+}
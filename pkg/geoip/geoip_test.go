@@ -0,0 +1,265 @@
+package geoip
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"net"
+	"sort"
+	"testing"
+)
+
+// ---- mmdb 测试数据构造 ----
+
+func mmdbCtrl(typ, size int) []byte {
+	var sizeBits int
+	var extra []byte
+	switch {
+	case size < 29:
+		sizeBits = size
+	case size < 285:
+		sizeBits, extra = 29, []byte{byte(size - 29)}
+	default:
+		n := size - 285
+		sizeBits, extra = 30, []byte{byte(n >> 8), byte(n)}
+	}
+	out := []byte{byte(typ<<5 | sizeBits)}
+	if typ > 7 {
+		out = []byte{byte(sizeBits), byte(typ - 7)}
+	}
+	return append(out, extra...)
+}
+
+func mmdbEncode(v interface{}) []byte {
+	switch x := v.(type) {
+	case string:
+		return append(mmdbCtrl(mmdbString, len(x)), x...)
+	case uint64:
+		var b []byte
+		for n := x; n > 0; n >>= 8 {
+			b = append([]byte{byte(n)}, b...)
+		}
+		return append(mmdbCtrl(mmdbUint64, len(b)), b...)
+	case []interface{}:
+		out := mmdbCtrl(mmdbArray, len(x))
+		for _, e := range x {
+			out = append(out, mmdbEncode(e)...)
+		}
+		return out
+	case map[string]interface{}:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		out := mmdbCtrl(mmdbMap, len(x))
+		for _, k := range keys {
+			out = append(out, mmdbEncode(k)...)
+			out = append(out, mmdbEncode(x[k])...)
+		}
+		return out
+	case mmdbRawPointer:
+		return []byte{byte(mmdbPointer<<5) | byte(x>>8&0x7), byte(x)}
+	}
+	panic("unsupported type")
+}
+
+type mmdbRawPointer uint16
+
+// buildMMDB 构造仅包含 1.0.0.0/8 一条记录的 IPv4 库（24位记录），记录位于数据段 offset 处
+func buildMMDB(dbType string, data []byte, offset int) []byte {
+	const nodeCount = 8
+	var tree []byte
+	put := func(v uint32) { tree = append(tree, byte(v>>16), byte(v>>8), byte(v)) }
+	for i := 0; i < 7; i++ {
+		put(uint32(i + 1))
+		put(nodeCount)
+	}
+	put(nodeCount)
+	put(uint32(nodeCount + mmdbDataSeparator + offset))
+
+	buf := append(tree, make([]byte, mmdbDataSeparator)...)
+	buf = append(buf, data...)
+	buf = append(buf, mmdbMetadataMarker...)
+	buf = append(buf, mmdbEncode(map[string]interface{}{
+		"node_count":    uint64(nodeCount),
+		"record_size":   uint64(24),
+		"ip_version":    uint64(4),
+		"database_type": dbType,
+		"build_epoch":   uint64(1700000000),
+	})...)
+	return buf
+}
+
+// ---- xdb 测试数据构造 ----
+
+type xdbSegment struct {
+	start, end string
+	region     string
+}
+
+func buildXDB(segs []xdbSegment) []byte {
+	buf := make([]byte, xdbHeaderLength+xdbVectorIndexLength)
+	binary.LittleEndian.PutUint16(buf[0:2], xdbStructureVersion)
+	binary.LittleEndian.PutUint32(buf[4:8], 1700000000)
+
+	ptrs := make([]int, len(segs))
+	for i, s := range segs {
+		ptrs[i] = len(buf)
+		buf = append(buf, s.region...)
+	}
+
+	first := len(buf)
+	for i, s := range segs {
+		p := len(buf)
+		seg := make([]byte, xdbSegmentIndexSize)
+		sip := binary.BigEndian.Uint32(net.ParseIP(s.start).To4())
+		eip := binary.BigEndian.Uint32(net.ParseIP(s.end).To4())
+		binary.LittleEndian.PutUint32(seg[0:4], sip)
+		binary.LittleEndian.PutUint32(seg[4:8], eip)
+		binary.LittleEndian.PutUint16(seg[8:10], uint16(len(s.region)))
+		binary.LittleEndian.PutUint32(seg[10:14], uint32(ptrs[i]))
+		buf = append(buf, seg...)
+
+		// 测试数据中每段不跨越 /16，直接更新对应的向量索引
+		idx := xdbHeaderLength + (int(sip>>24)*xdbVectorIndexCols+int(sip>>16&0xff))*xdbVectorIndexSize
+		if binary.LittleEndian.Uint32(buf[idx:]) == 0 {
+			binary.LittleEndian.PutUint32(buf[idx:], uint32(p))
+		}
+		binary.LittleEndian.PutUint32(buf[idx+4:], uint32(p))
+	}
+	binary.LittleEndian.PutUint32(buf[8:12], uint32(first))
+	binary.LittleEndian.PutUint32(buf[12:16], uint32(len(buf)-xdbSegmentIndexSize))
+	return buf
+}
+
+func TestMMDBLookup(t *testing.T) {
+	// 记录中 country 的 names 通过指针引用数据段开头的 map
+	names := mmdbEncode(map[string]interface{}{"en": "Australia", "zh-CN": "澳大利亚"})
+	data := append(names, mmdbEncode(map[string]interface{}{
+		"country":      map[string]interface{}{"names": mmdbRawPointer(0)},
+		"subdivisions": []interface{}{map[string]interface{}{"names": map[string]interface{}{"en": "New South Wales"}}},
+	})...)
+
+	db, err := openMMDB(buildMMDB("GeoLite2-City", data, len(names)))
+	if err != nil {
+		t.Fatalf("openMMDB: %v", err)
+	}
+	if db.DatabaseType != "GeoLite2-City" || db.BuildTime.Unix() != 1700000000 {
+		t.Errorf("metadata = %q %v", db.DatabaseType, db.BuildTime)
+	}
+
+	rec, err := db.Lookup(net.ParseIP("1.2.3.4"))
+	if err != nil || rec == nil {
+		t.Fatalf("Lookup(1.2.3.4) = %v, %v", rec, err)
+	}
+	if got := mmdbName(mmdbPath(rec, "country", "names")); got != "澳大利亚" {
+		t.Errorf("country = %q", got)
+	}
+	if rec, _ := db.Lookup(net.ParseIP("2.0.0.1")); rec != nil {
+		t.Errorf("Lookup(2.0.0.1) = %v, want nil", rec)
+	}
+	if rec, _ := db.Lookup(net.ParseIP("2001:db8::1")); rec != nil {
+		t.Errorf("ipv6 lookup in ipv4 db = %v, want nil", rec)
+	}
+
+	if _, err := openMMDB([]byte("not a database")); err == nil {
+		t.Error("openMMDB should reject invalid data")
+	}
+}
+
+func TestXDBLookup(t *testing.T) {
+	db, err := openXDB(buildXDB([]xdbSegment{
+		{"1.0.0.0", "1.0.0.255", "中国|0|广东省|深圳市|电信"},
+		{"1.0.1.0", "1.0.3.255", "中国|0|福建省|福州市|电信"},
+		{"8.8.0.0", "8.8.255.255", "美国|0|0|0|Level3"},
+	}))
+	if err != nil {
+		t.Fatalf("openXDB: %v", err)
+	}
+	tests := map[string]string{
+		"1.0.0.1":   "中国|0|广东省|深圳市|电信",
+		"1.0.2.200": "中国|0|福建省|福州市|电信",
+		"8.8.8.8":   "美国|0|0|0|Level3",
+		"1.0.4.1":   "",
+		"9.9.9.9":   "",
+	}
+	for ip, want := range tests {
+		if got, err := db.Lookup(net.ParseIP(ip)); err != nil || got != want {
+			t.Errorf("Lookup(%s) = %q, %v, want %q", ip, got, err, want)
+		}
+	}
+
+	info := parseRegion("中国|0|广东省|深圳市|电信")
+	if info.Location() != "中国 广东省 深圳市" || info.ISP != "电信" {
+		t.Errorf("parseRegion = %+v", info)
+	}
+	if got := parseRegion("中国|北京|北京|联通").Location(); got != "中国 北京" {
+		t.Errorf("parseRegion 4 fields location = %q", got)
+	}
+}
+
+func TestResolverInstallAndLookup(t *testing.T) {
+	r := NewResolver(t.TempDir())
+	if r.Available() {
+		t.Fatal("empty resolver should not be available")
+	}
+
+	region := buildXDB([]xdbSegment{{"1.0.0.0", "1.0.0.255", "中国|0|广东省|深圳市|电信"}})
+	if err := r.Install(DBRegion, region); err != nil {
+		t.Fatalf("Install region: %v", err)
+	}
+
+	city := buildMMDB("GeoLite2-City", mmdbEncode(map[string]interface{}{
+		"country": map[string]interface{}{"names": map[string]interface{}{"en": "Australia"}},
+		"city":    map[string]interface{}{"names": map[string]interface{}{"en": "Sydney"}},
+	}), 0)
+	if err := r.Install(DBASN, city); err == nil {
+		t.Error("installing a city database as asn should fail")
+	}
+	if err := r.Install(DBCity, city); err != nil {
+		t.Fatalf("Install city: %v", err)
+	}
+
+	// ASN 库以 MaxMind 下载格式（tar.gz）安装
+	asn := buildMMDB("GeoLite2-ASN", mmdbEncode(map[string]interface{}{
+		"autonomous_system_number":       uint64(13335),
+		"autonomous_system_organization": "Cloudflare, Inc.",
+	}), 0)
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	tw.WriteHeader(&tar.Header{Name: "GeoLite2-ASN_20240101/GeoLite2-ASN.mmdb", Mode: 0644, Size: int64(len(asn)), Typeflag: tar.TypeReg})
+	tw.Write(asn)
+	tw.Close()
+	gz.Close()
+	if err := r.Install(DBASN, archive.Bytes()); err != nil {
+		t.Fatalf("Install asn archive: %v", err)
+	}
+
+	// ip2region 命中时使用中文地名，ASN 来自 GeoLite2
+	info := r.Lookup("1.0.0.8")
+	if info.Location() != "中国 广东省 深圳市" || info.ASNName() != "AS13335 Cloudflare, Inc." {
+		t.Errorf("Lookup(1.0.0.8) = %+v", info)
+	}
+	// ip2region 未命中时使用 GeoLite2 City
+	info = r.Lookup("1.200.0.1")
+	if info.Location() != "Australia Sydney" || info.ASN != 13335 {
+		t.Errorf("Lookup(1.200.0.1) = %+v", info)
+	}
+	if info := r.Lookup("invalid"); !info.IsEmpty() {
+		t.Errorf("Lookup(invalid) = %+v", info)
+	}
+
+	// 重新加载目录中的文件
+	r2 := NewResolver(r.dir)
+	for _, st := range r2.Status() {
+		if !st.Loaded || st.Error != "" {
+			t.Errorf("status after reload = %+v", st)
+		}
+	}
+	if got := r2.Lookup("1.0.0.8").City; got != "深圳市" {
+		t.Errorf("reloaded lookup city = %q", got)
+	}
+}
@@ -0,0 +1,109 @@
+package geoip
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// ip2region xdb 格式说明：https://github.com/lionsoul2014/ip2region
+// 文件结构：256字节头 + 256*256 向量索引 + 数据区 + 二分查找的段索引
+const (
+	xdbHeaderLength      = 256
+	xdbVectorIndexCols   = 256
+	xdbVectorIndexSize   = 8
+	xdbSegmentIndexSize  = 14
+	xdbVectorIndexLength = xdbVectorIndexCols * xdbVectorIndexCols * xdbVectorIndexSize
+	xdbStructureVersion  = 2
+)
+
+// xdbReader ip2region xdb 只读解析器（IPv4），整个文件加载到内存
+type xdbReader struct {
+	buf       []byte
+	BuildTime time.Time
+}
+
+func openXDB(buf []byte) (*xdbReader, error) {
+	if len(buf) < xdbHeaderLength+xdbVectorIndexLength {
+		return nil, errors.New("invalid xdb: file too small")
+	}
+	if v := binary.LittleEndian.Uint16(buf[0:2]); v != xdbStructureVersion {
+		return nil, fmt.Errorf("unsupported xdb structure version %d", v)
+	}
+	start := binary.LittleEndian.Uint32(buf[8:12])
+	end := binary.LittleEndian.Uint32(buf[12:16])
+	if start > end || int(end)+xdbSegmentIndexSize > len(buf) {
+		return nil, errors.New("invalid xdb: segment index out of range")
+	}
+	r := &xdbReader{buf: buf}
+	if created := binary.LittleEndian.Uint32(buf[4:8]); created > 0 {
+		r.BuildTime = time.Unix(int64(created), 0)
+	}
+	return r, nil
+}
+
+// Lookup 查询IPv4对应的区域字符串，未命中返回空
+func (r *xdbReader) Lookup(ip net.IP) (string, error) {
+	v4 := ip.To4()
+	if v4 == nil {
+		return "", nil
+	}
+	n := binary.BigEndian.Uint32(v4)
+
+	idx := xdbHeaderLength + (int(v4[0])*xdbVectorIndexCols+int(v4[1]))*xdbVectorIndexSize
+	sPtr := int(binary.LittleEndian.Uint32(r.buf[idx:]))
+	ePtr := int(binary.LittleEndian.Uint32(r.buf[idx+4:]))
+	if sPtr == 0 && ePtr == 0 {
+		return "", nil
+	}
+	if sPtr > ePtr || ePtr+xdbSegmentIndexSize > len(r.buf) {
+		return "", errors.New("invalid xdb vector index")
+	}
+
+	l, h := 0, (ePtr-sPtr)/xdbSegmentIndexSize
+	for l <= h {
+		m := (l + h) >> 1
+		p := sPtr + m*xdbSegmentIndexSize
+		seg := r.buf[p : p+xdbSegmentIndexSize]
+		switch {
+		case n < binary.LittleEndian.Uint32(seg[0:4]):
+			h = m - 1
+		case n > binary.LittleEndian.Uint32(seg[4:8]):
+			l = m + 1
+		default:
+			dataLen := int(binary.LittleEndian.Uint16(seg[8:10]))
+			dataPtr := int(binary.LittleEndian.Uint32(seg[10:14]))
+			if dataPtr+dataLen > len(r.buf) {
+				return "", errors.New("invalid xdb data pointer")
+			}
+			return string(r.buf[dataPtr : dataPtr+dataLen]), nil
+		}
+	}
+	return "", nil
+}
+
+// parseRegion 解析 ip2region 区域字符串
+// 标准格式为 国家|区域|省份|城市|ISP，新版数据为 国家|省份|城市|ISP，未知字段为 0
+func parseRegion(s string) Info {
+	parts := strings.Split(s, "|")
+	for i, p := range parts {
+		if p == "0" {
+			parts[i] = ""
+		}
+	}
+	var info Info
+	switch len(parts) {
+	case 5:
+		info = Info{Country: parts[0], Region: parts[2], City: parts[3], ISP: parts[4]}
+	case 4:
+		info = Info{Country: parts[0], Region: parts[1], City: parts[2], ISP: parts[3]}
+	default:
+		if len(parts) > 0 {
+			info.Country = parts[0]
+		}
+	}
+	return info
+}
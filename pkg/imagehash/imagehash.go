@@ -0,0 +1,145 @@
+// Package imagehash 截图感知哈希（pHash），用于聚类外观相似的页面
+package imagehash
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+)
+
+const (
+	sampleSize = 32 // 缩放后的边长
+	hashSize   = 8  // 取 DCT 左上角 8x8 低频分量
+)
+
+// PHash 计算图片的 64 位感知哈希，以 16 位十六进制字符串返回
+func PHash(data []byte) (string, error) {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%016x", phash(img)), nil
+}
+
+func phash(img image.Image) uint64 {
+	pixels := grayscale(img)
+
+	// 二维 DCT：先按行再按列，只需要低频部分
+	var rows [sampleSize][hashSize]float64
+	for y := 0; y < sampleSize; y++ {
+		for u := 0; u < hashSize; u++ {
+			rows[y][u] = dct(u, func(x int) float64 { return pixels[y][x] })
+		}
+	}
+	coeffs := make([]float64, 0, hashSize*hashSize)
+	for v := 0; v < hashSize; v++ {
+		for u := 0; u < hashSize; u++ {
+			coeffs = append(coeffs, dct(v, func(y int) float64 { return rows[y][u] }))
+		}
+	}
+
+	// 以中位数为阈值（排除直流分量），高于中位数的位置 1
+	sorted := append([]float64(nil), coeffs[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, c := range coeffs {
+		if i > 0 && c > median {
+			hash |= 1 << uint(len(coeffs)-1-i)
+		}
+	}
+	return hash
+}
+
+func dct(k int, f func(int) float64) float64 {
+	sum := 0.0
+	for n := 0; n < sampleSize; n++ {
+		sum += f(n) * math.Cos(math.Pi/sampleSize*(float64(n)+0.5)*float64(k))
+	}
+	return sum
+}
+
+// grayscale 按区域平均缩放为 32x32 灰度矩阵
+func grayscale(img image.Image) [sampleSize][sampleSize]float64 {
+	var out [sampleSize][sampleSize]float64
+	var count [sampleSize][sampleSize]float64
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	if w == 0 || h == 0 {
+		return out
+	}
+	// 大图按步长采样，避免逐像素遍历整张全屏截图
+	step := 1
+	if n := w * h / (sampleSize * sampleSize * 64); n > 1 {
+		step = int(math.Sqrt(float64(n)))
+	}
+	for y := 0; y < h; y += step {
+		cy := y * sampleSize / h
+		for x := 0; x < w; x += step {
+			cx := x * sampleSize / w
+			r, g, bl, _ := img.At(b.Min.X+x, b.Min.Y+y).RGBA()
+			out[cy][cx] += (0.299*float64(r) + 0.587*float64(g) + 0.114*float64(bl)) / 257
+			count[cy][cx]++
+		}
+	}
+	for y := range out {
+		for x := range out[y] {
+			if count[y][x] > 0 {
+				out[y][x] /= count[y][x]
+			}
+		}
+	}
+	return out
+}
+
+// Distance 两个哈希的汉明距离，格式错误时返回 -1
+func Distance(a, b string) int {
+	x, err1 := strconv.ParseUint(a, 16, 64)
+	y, err2 := strconv.ParseUint(b, 16, 64)
+	if err1 != nil || err2 != nil || len(a) != 16 || len(b) != 16 {
+		return -1
+	}
+	return bits.OnesCount64(x ^ y)
+}
+
+// Cluster 按汉明距离阈值对哈希做贪心聚类，返回每个簇包含的下标，
+// 簇按大小降序，每个簇的第一个元素为代表
+func Cluster(hashes []string, threshold int) [][]int {
+	values := make([]uint64, len(hashes))
+	valid := make([]bool, len(hashes))
+	for i, h := range hashes {
+		if v, err := strconv.ParseUint(h, 16, 64); err == nil && len(h) == 16 {
+			values[i], valid[i] = v, true
+		}
+	}
+
+	var clusters [][]int
+	var centers []uint64
+	for i := range hashes {
+		if !valid[i] {
+			continue
+		}
+		placed := false
+		for c, center := range centers {
+			if bits.OnesCount64(values[i]^center) <= threshold {
+				clusters[c] = append(clusters[c], i)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			clusters = append(clusters, []int{i})
+			centers = append(centers, values[i])
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return len(clusters[i]) > len(clusters[j]) })
+	return clusters
+}
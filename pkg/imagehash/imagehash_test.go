@@ -0,0 +1,96 @@
+package imagehash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+)
+
+// page 生成一张模拟页面：顶部导航栏 + 左侧栏 + 内容块
+func page(w, h int, nav color.Color, withSidebar bool) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.Color(color.White)
+			switch {
+			case y < h/8:
+				c = nav
+			case withSidebar && x < w/5:
+				c = color.Gray{Y: 200}
+			case y > h/3 && y < h/2 && x > w/3 && x < w*2/3:
+				c = color.Black
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestPHashSimilarity(t *testing.T) {
+	base, err := PHash(encodePNG(t, page(1280, 800, color.RGBA{0, 0, 200, 255}, true)))
+	if err != nil {
+		t.Fatalf("PHash: %v", err)
+	}
+	if len(base) != 16 {
+		t.Fatalf("hash length = %d", len(base))
+	}
+
+	// 同一页面不同尺寸、JPEG 压缩，应非常接近
+	var jpg bytes.Buffer
+	jpeg.Encode(&jpg, page(1024, 640, color.RGBA{0, 0, 200, 255}, true), &jpeg.Options{Quality: 60})
+	scaled, err := PHash(jpg.Bytes())
+	if err != nil {
+		t.Fatalf("PHash jpeg: %v", err)
+	}
+	if d := Distance(base, scaled); d < 0 || d > 6 {
+		t.Errorf("distance to rescaled page = %d", d)
+	}
+
+	// 布局不同的页面应明显不同
+	other, _ := PHash(encodePNG(t, page(1280, 800, color.White, false)))
+	if d := Distance(base, other); d < 10 {
+		t.Errorf("distance to different page = %d", d)
+	}
+
+	if _, err := PHash([]byte("not an image")); err == nil {
+		t.Error("PHash should fail on invalid image")
+	}
+}
+
+func TestDistanceAndCluster(t *testing.T) {
+	if d := Distance("0000000000000000", "000000000000000f"); d != 4 {
+		t.Errorf("Distance = %d, want 4", d)
+	}
+	if d := Distance("xyz", "0000000000000000"); d != -1 {
+		t.Errorf("Distance invalid = %d, want -1", d)
+	}
+
+	hashes := []string{
+		"ffff000000000000",
+		"0000000000ffffff",
+		"ffff000000000001",
+		"",
+		"ffff000000000003",
+	}
+	clusters := Cluster(hashes, 4)
+	if len(clusters) != 2 {
+		t.Fatalf("clusters = %v", clusters)
+	}
+	if got := clusters[0]; len(got) != 3 || got[0] != 0 || got[1] != 2 || got[2] != 4 {
+		t.Errorf("first cluster = %v", got)
+	}
+	if got := clusters[1]; len(got) != 1 || got[0] != 1 {
+		t.Errorf("second cluster = %v", got)
+	}
+}
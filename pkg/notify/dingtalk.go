@@ -0,0 +1,64 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DingTalkNotifier 钉钉群机器人通知
+type DingTalkNotifier struct {
+	Url    string
+	Secret string // 加签密钥（SEC开头），为空表示使用关键词或IP白名单
+}
+
+// Send 发送消息
+func (n *DingTalkNotifier) Send(ctx context.Context, msg *Message) error {
+	webhook := n.Url
+	if n.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
+		sep := "?"
+		if strings.Contains(webhook, "?") {
+			sep = "&"
+		}
+		webhook += sep + "timestamp=" + timestamp + "&sign=" + url.QueryEscape(dingTalkSign(timestamp, n.Secret))
+	}
+
+	body := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"title": msg.Title,
+			"text":  msg.Markdown(),
+		},
+	}
+	respBody, err := postJSON(ctx, webhook, body, nil)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("invalid response: %s", string(respBody))
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("dingtalk error %d: %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
+
+// dingTalkSign 钉钉加签：base64(HmacSHA256(timestamp+"\n"+secret, secret))
+func dingTalkSign(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "\n" + secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"time"
+
+	"cscan/model"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+const (
+	sendTimeout = 30 * time.Second // 单个事件发送超时
+	onceTTL     = 24 * time.Hour   // 事件去重有效期
+)
+
+// Dispatcher 通知分发器，根据事件查找渠道并异步发送
+type Dispatcher struct {
+	channelModel *model.NotifyChannelModel
+	rdb          *redis.Client
+}
+
+// NewDispatcher 创建通知分发器
+func NewDispatcher(channelModel *model.NotifyChannelModel, rdb *redis.Client) *Dispatcher {
+	return &Dispatcher{channelModel: channelModel, rdb: rdb}
+}
+
+// DispatchOnce 按 key 去重后分发，用于多个子任务/多个服务实例可能重复触发的事件
+func (d *Dispatcher) DispatchOnce(ctx context.Context, key, channelId string, msg *Message) {
+	if d == nil || msg == nil {
+		return
+	}
+	if d.rdb != nil {
+		ok, err := d.rdb.SetNX(ctx, "cscan:notify:once:"+key, 1, onceTTL).Result()
+		if err == nil && !ok {
+			return
+		}
+	}
+	d.Dispatch(channelId, msg)
+}
+
+// Dispatch 异步分发事件通知
+// channelId 不为空时（如任务指定的 NotifyId）只发送到该渠道，否则发送到所有订阅该事件的渠道
+func (d *Dispatcher) Dispatch(channelId string, msg *Message) {
+	if d == nil || msg == nil {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+
+		channels, err := d.findChannels(ctx, channelId, msg.Event)
+		if err != nil {
+			logx.Errorf("[Notify] find channels for %s failed: %v", msg.Event, err)
+			return
+		}
+		for i := range channels {
+			if err := Send(ctx, &channels[i], msg); err != nil {
+				logx.Errorf("[Notify] send %s to channel %s(%s) failed: %v", msg.Event, channels[i].Name, channels[i].Type, err)
+			}
+		}
+	}()
+}
+
+// findChannels 查找需要发送的渠道
+func (d *Dispatcher) findChannels(ctx context.Context, channelId, event string) ([]model.NotifyChannel, error) {
+	if channelId == "" {
+		return d.channelModel.FindEnabledByEvent(ctx, event)
+	}
+	ch, err := d.channelModel.FindById(ctx, channelId)
+	if err != nil {
+		return nil, err
+	}
+	if ch.Status != "enable" {
+		return nil, nil
+	}
+	// 指定渠道未订阅任何事件时视为接收全部任务事件
+	if len(ch.Events) == 0 {
+		if event != model.NotifyEventTaskComplete && event != model.NotifyEventTaskFailed {
+			return nil, nil
+		}
+	} else if !ch.HasEvent(event) {
+		return nil, nil
+	}
+	return []model.NotifyChannel{*ch}, nil
+}
+
+// Send 同步发送消息到指定渠道
+func Send(ctx context.Context, ch *model.NotifyChannel, msg *Message) error {
+	n, err := New(ch)
+	if err != nil {
+		return err
+	}
+	return n.Send(ctx, msg)
+}
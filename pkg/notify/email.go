@@ -0,0 +1,123 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"html"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// EmailNotifier SMTP邮件通知
+type EmailNotifier struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	To       []string
+	SSL      bool // 465端口SSL直连，否则在服务端支持时使用STARTTLS
+}
+
+// Send 发送消息
+func (n *EmailNotifier) Send(ctx context.Context, msg *Message) error {
+	from := n.From
+	if from == "" {
+		from = n.Username
+	}
+	if from == "" || len(n.To) == 0 {
+		return fmt.Errorf("email sender and recipients are required")
+	}
+
+	addr := net.JoinHostPort(n.Host, strconv.Itoa(n.Port))
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	tlsConfig := &tls.Config{ServerName: n.Host}
+
+	var conn net.Conn
+	var err error
+	if n.SSL {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, n.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if !n.SSL {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if n.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", n.Username, n.Password, n.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, to := range n.To {
+		if err := client.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(n.buildMail(from, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMail 构造HTML邮件
+func (n *EmailNotifier) buildMail(from string, msg *Message) []byte {
+	var body strings.Builder
+	fmt.Fprintf(&body, "<h3>%s</h3>", html.EscapeString(msg.Title))
+	if msg.Content != "" {
+		fmt.Fprintf(&body, "<p>%s</p>", strings.ReplaceAll(html.EscapeString(msg.Content), "\n", "<br>"))
+	}
+	if len(msg.Fields) > 0 {
+		body.WriteString(`<table border="1" cellspacing="0" cellpadding="4">`)
+		for _, f := range msg.Fields {
+			fmt.Fprintf(&body, "<tr><td><b>%s</b></td><td>%s</td></tr>", html.EscapeString(f.Key), html.EscapeString(f.Value))
+		}
+		body.WriteString("</table>")
+	}
+	fmt.Fprintf(&body, "<p style=\"color:#999\">%s</p>", msg.Time.Local().Format("2006-01-02 15:04:05"))
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From: %s\r\n", from)
+	fmt.Fprintf(&sb, "To: %s\r\n", strings.Join(n.To, ", "))
+	fmt.Fprintf(&sb, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", msg.Title))
+	fmt.Fprintf(&sb, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	sb.WriteString("MIME-Version: 1.0\r\n")
+	sb.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+	sb.WriteString("\r\n")
+	sb.WriteString(body.String())
+	return []byte(sb.String())
+}
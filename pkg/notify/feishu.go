@@ -0,0 +1,89 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// FeishuNotifier 飞书/Lark群机器人通知
+type FeishuNotifier struct {
+	Url    string
+	Secret string // 签名校验密钥，为空表示未开启签名校验
+}
+
+// Send 发送消息
+func (n *FeishuNotifier) Send(ctx context.Context, msg *Message) error {
+	body := map[string]interface{}{
+		"msg_type": "interactive",
+		"card": map[string]interface{}{
+			"header": map[string]interface{}{
+				"title":    map[string]string{"tag": "plain_text", "content": msg.Title},
+				"template": feishuColor(msg.Level),
+			},
+			"elements": []interface{}{
+				map[string]interface{}{
+					"tag":  "div",
+					"text": map[string]string{"tag": "lark_md", "content": feishuContent(msg)},
+				},
+			},
+		},
+	}
+	if n.Secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		body["timestamp"] = timestamp
+		body["sign"] = feishuSign(timestamp, n.Secret)
+	}
+
+	respBody, err := postJSON(ctx, n.Url, body, nil)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		Code int    `json:"code"`
+		Msg  string `json:"msg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("invalid response: %s", string(respBody))
+	}
+	if result.Code != 0 {
+		return fmt.Errorf("feishu error %d: %s", result.Code, result.Msg)
+	}
+	return nil
+}
+
+// feishuContent 飞书卡片正文（lark_md 不支持标题语法）
+func feishuContent(msg *Message) string {
+	content := msg.Content
+	for _, f := range msg.Fields {
+		if content != "" {
+			content += "\n"
+		}
+		content += fmt.Sprintf("**%s**: %s", f.Key, f.Value)
+	}
+	return content + "\n" + msg.Time.Local().Format("2006-01-02 15:04:05")
+}
+
+// feishuColor 卡片标题颜色
+func feishuColor(level string) string {
+	switch level {
+	case LevelCritical:
+		return "red"
+	case LevelWarning:
+		return "orange"
+	default:
+		return "blue"
+	}
+}
+
+// feishuSign 飞书签名：base64(HmacSHA256(key=timestamp+"\n"+secret, msg=""))
+func feishuSign(timestamp, secret string) string {
+	mac := hmac.New(sha256.New, []byte(timestamp+"\n"+secret))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,121 @@
+package notify
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"cscan/model"
+	"cscan/pkg/assetdiff"
+)
+
+// maxVulsInMessage 单条消息中最多列出的漏洞数
+const maxVulsInMessage = 10
+
+// TaskMessage 任务完成/失败消息
+func TaskMessage(task *model.MainTask, event string) *Message {
+	msg := &Message{
+		Event: event,
+		Level: LevelInfo,
+		Title: fmt.Sprintf("[CSCAN] 任务完成: %s", task.Name),
+		Time:  time.Now(),
+	}
+	if event == model.NotifyEventTaskFailed {
+		msg.Level = LevelWarning
+		msg.Title = fmt.Sprintf("[CSCAN] 任务失败: %s", task.Name)
+	}
+
+	msg.Fields = append(msg.Fields, Field{Key: "任务ID", Value: task.TaskId})
+	msg.Fields = append(msg.Fields, Field{Key: "扫描目标", Value: truncate(strings.ReplaceAll(task.Target, "\n", ", "), 200)})
+	msg.Fields = append(msg.Fields, Field{Key: "状态", Value: task.Status})
+	if task.StartTime != nil && task.EndTime != nil {
+		msg.Fields = append(msg.Fields, Field{Key: "耗时", Value: task.EndTime.Sub(*task.StartTime).Round(time.Second).String()})
+	}
+	if task.DiffSummary != nil {
+		msg.Fields = append(msg.Fields, Field{Key: "资产变化", Value: assetdiff.SummaryText(task.DiffSummary)})
+	}
+	if task.Result != "" {
+		msg.Content = truncate(task.Result, 500)
+	}
+	return msg
+}
+
+// TaskOnceKey 任务通知去重键，包含本轮开始时间，定时任务每次执行各通知一次
+func TaskOnceKey(event string, task *model.MainTask) string {
+	key := event + ":" + task.Id.Hex()
+	if task.StartTime != nil {
+		key += fmt.Sprintf(":%d", task.StartTime.Unix())
+	}
+	return key
+}
+
+// VulMessage 新漏洞消息，多个漏洞合并为一条
+func VulMessage(workspaceId string, vuls []*model.Vul) *Message {
+	msg := &Message{
+		Event: model.NotifyEventVulFound,
+		Level: LevelCritical,
+		Title: fmt.Sprintf("[CSCAN] 发现 %d 个高危漏洞", len(vuls)),
+		Time:  time.Now(),
+	}
+
+	severityCount := make(map[string]int)
+	var lines []string
+	for i, v := range vuls {
+		severityCount[v.Severity]++
+		if i < maxVulsInMessage {
+			target := v.Url
+			if target == "" {
+				target = fmt.Sprintf("%s:%d", v.Host, v.Port)
+			}
+			lines = append(lines, fmt.Sprintf("[%s] %s %s", strings.ToUpper(v.Severity), v.PocFile, target))
+		}
+	}
+	if len(vuls) > maxVulsInMessage {
+		lines = append(lines, fmt.Sprintf("... 另有 %d 个", len(vuls)-maxVulsInMessage))
+	}
+	msg.Content = strings.Join(lines, "\n")
+
+	msg.Fields = append(msg.Fields, Field{Key: "工作空间", Value: workspaceId})
+	for _, severity := range sortedKeys(severityCount) {
+		msg.Fields = append(msg.Fields, Field{Key: severity, Value: fmt.Sprintf("%d", severityCount[severity])})
+	}
+	return msg
+}
+
+// WorkerOfflineMessage Worker离线消息
+func WorkerOfflineMessage(workerName, reason string) *Message {
+	return &Message{
+		Event:   model.NotifyEventWorkerOffline,
+		Level:   LevelWarning,
+		Title:   fmt.Sprintf("[CSCAN] Worker离线: %s", workerName),
+		Content: reason,
+		Fields:  []Field{{Key: "Worker", Value: workerName}},
+		Time:    time.Now(),
+	}
+}
+
+// TestMessage 测试消息
+func TestMessage(ch *model.NotifyChannel) *Message {
+	return &Message{
+		Event:   "test",
+		Level:   LevelInfo,
+		Title:   "[CSCAN] 通知测试",
+		Content: fmt.Sprintf("通知渠道 %s 配置正确", ch.Name),
+		Fields:  []Field{{Key: "类型", Value: ch.Type}},
+		Time:    time.Now(),
+	}
+}
+
+// IsNotifySeverity 是否为需要通知的漏洞等级
+func IsNotifySeverity(severity string) bool {
+	s := strings.ToLower(severity)
+	return s == "critical" || s == "high"
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n]) + "..."
+}
@@ -0,0 +1,178 @@
+// Package notify 提供任务、漏洞、Worker 事件的消息通知能力。
+// 支持通用Webhook、钉钉、飞书/Lark、企业微信、Slack 和 SMTP 邮件。
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"cscan/model"
+)
+
+// 消息级别
+const (
+	LevelInfo     = "info"
+	LevelWarning  = "warning"
+	LevelCritical = "critical"
+)
+
+// Field 消息字段
+type Field struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// Message 通知消息
+type Message struct {
+	Event   string    `json:"event"`
+	Level   string    `json:"level"`
+	Title   string    `json:"title"`
+	Content string    `json:"content"`
+	Fields  []Field   `json:"fields,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// Text 纯文本格式
+func (m *Message) Text() string {
+	var sb strings.Builder
+	sb.WriteString(m.Title)
+	sb.WriteString("\n")
+	if m.Content != "" {
+		sb.WriteString(m.Content)
+		sb.WriteString("\n")
+	}
+	for _, f := range m.Fields {
+		fmt.Fprintf(&sb, "%s: %s\n", f.Key, f.Value)
+	}
+	fmt.Fprintf(&sb, "时间: %s", m.Time.Local().Format("2006-01-02 15:04:05"))
+	return sb.String()
+}
+
+// Markdown Markdown格式
+func (m *Message) Markdown() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### %s\n\n", m.Title)
+	if m.Content != "" {
+		sb.WriteString(m.Content)
+		sb.WriteString("\n\n")
+	}
+	for _, f := range m.Fields {
+		fmt.Fprintf(&sb, "- **%s**: %s\n", f.Key, f.Value)
+	}
+	fmt.Fprintf(&sb, "\n> %s", m.Time.Local().Format("2006-01-02 15:04:05"))
+	return sb.String()
+}
+
+// Notifier 通知发送器
+type Notifier interface {
+	Send(ctx context.Context, msg *Message) error
+}
+
+// New 根据渠道配置创建通知发送器
+func New(ch *model.NotifyChannel) (Notifier, error) {
+	switch ch.Type {
+	case model.NotifyTypeWebhook:
+		return &WebhookNotifier{Url: ch.WebhookUrl, Secret: ch.Secret, Headers: ch.Headers}, nil
+	case model.NotifyTypeDingTalk:
+		return &DingTalkNotifier{Url: ch.WebhookUrl, Secret: ch.Secret}, nil
+	case model.NotifyTypeFeishu:
+		return &FeishuNotifier{Url: ch.WebhookUrl, Secret: ch.Secret}, nil
+	case model.NotifyTypeWeCom:
+		return &WeComNotifier{Url: ch.WebhookUrl}, nil
+	case model.NotifyTypeSlack:
+		return &SlackNotifier{Url: ch.WebhookUrl}, nil
+	case model.NotifyTypeEmail:
+		return &EmailNotifier{
+			Host:     ch.SmtpHost,
+			Port:     ch.SmtpPort,
+			Username: ch.SmtpUser,
+			Password: ch.SmtpPass,
+			From:     ch.SmtpFrom,
+			To:       ch.SmtpTo,
+			SSL:      ch.SmtpSSL,
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported notify type: %s", ch.Type)
+}
+
+// Validate 校验渠道配置
+func Validate(ch *model.NotifyChannel) error {
+	switch ch.Type {
+	case model.NotifyTypeWebhook, model.NotifyTypeDingTalk, model.NotifyTypeFeishu,
+		model.NotifyTypeWeCom, model.NotifyTypeSlack:
+		if !strings.HasPrefix(ch.WebhookUrl, "http://") && !strings.HasPrefix(ch.WebhookUrl, "https://") {
+			return fmt.Errorf("invalid webhook url")
+		}
+	case model.NotifyTypeEmail:
+		if ch.SmtpHost == "" || ch.SmtpPort <= 0 {
+			return fmt.Errorf("smtp host and port are required")
+		}
+		if len(ch.SmtpTo) == 0 {
+			return fmt.Errorf("at least one recipient is required")
+		}
+	default:
+		return fmt.Errorf("unsupported notify type: %s", ch.Type)
+	}
+	return nil
+}
+
+var httpClient = &http.Client{Timeout: 10 * time.Second}
+
+// postJSON 发送JSON请求，返回响应内容
+func postJSON(ctx context.Context, url string, body interface{}, headers map[string]string) ([]byte, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	return postRaw(ctx, url, data, headers)
+}
+
+// postRaw 发送已序列化的JSON请求
+func postRaw(ctx context.Context, url string, data []byte, headers map[string]string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return respBody, fmt.Errorf("http status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return respBody, nil
+}
+
+// fieldMap 字段转为map，便于JSON输出
+func fieldMap(fields []Field) map[string]string {
+	m := make(map[string]string, len(fields))
+	for _, f := range fields {
+		m[f.Key] = f.Value
+	}
+	return m
+}
+
+// sortedKeys 返回排序后的key，保证输出稳定
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
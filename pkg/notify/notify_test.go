@@ -0,0 +1,260 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"cscan/model"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo/integration/mtest"
+)
+
+// TestDingTalkSign 测试钉钉加签算法
+func TestDingTalkSign(t *testing.T) {
+	tests := []struct {
+		timestamp, secret, want string
+	}{
+		{"1700000000000", "SECtest", "aZLLrriXgn05YbwaGR7knYsLeJADjr9NwLaNNKpxh4g="},
+	}
+	for _, tt := range tests {
+		if got := dingTalkSign(tt.timestamp, tt.secret); got != tt.want {
+			t.Errorf("dingTalkSign(%s, %s) = %s, want %s", tt.timestamp, tt.secret, got, tt.want)
+		}
+	}
+}
+
+// TestFeishuSign 测试飞书签名算法：密钥为 timestamp+"\n"+secret，签名内容为空
+func TestFeishuSign(t *testing.T) {
+	tests := []struct {
+		timestamp, secret, want string
+	}{
+		{"1700000000", "feishu-secret", "OrBzY1Y01Gq+HgJsl+7OfWcMVwc7YocohQm5iiZwjhU="},
+	}
+	for _, tt := range tests {
+		if got := feishuSign(tt.timestamp, tt.secret); got != tt.want {
+			t.Errorf("feishuSign(%s, %s) = %s, want %s", tt.timestamp, tt.secret, got, tt.want)
+		}
+	}
+}
+
+// TestDingTalkSendSigned 测试钉钉加签参数追加到已有查询参数之后
+func TestDingTalkSendSigned(t *testing.T) {
+	var query map[string][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query = r.URL.Query()
+		w.Write([]byte(`{"errcode":0,"errmsg":"ok"}`))
+	}))
+	defer srv.Close()
+
+	n := &DingTalkNotifier{Url: srv.URL + "/robot/send?access_token=abc", Secret: "SECtest"}
+	if err := n.Send(context.Background(), &Message{Title: "t", Time: time.Now()}); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if got := query["access_token"]; len(got) != 1 || got[0] != "abc" {
+		t.Errorf("access_token = %v, want abc", got)
+	}
+	timestamp, sign := query["timestamp"], query["sign"]
+	if len(timestamp) != 1 || len(sign) != 1 {
+		t.Fatalf("query = %v, want timestamp and sign", query)
+	}
+	if want := dingTalkSign(timestamp[0], "SECtest"); sign[0] != want {
+		t.Errorf("sign = %s, want %s", sign[0], want)
+	}
+}
+
+// TestFeishuSendSigned 测试飞书签名写入请求体，未配置密钥时不携带签名
+func TestFeishuSendSigned(t *testing.T) {
+	var body map[string]interface{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body = nil
+		json.NewDecoder(r.Body).Decode(&body)
+		w.Write([]byte(`{"code":0}`))
+	}))
+	defer srv.Close()
+
+	msg := &Message{Title: "t", Time: time.Now()}
+	if err := (&FeishuNotifier{Url: srv.URL, Secret: "feishu-secret"}).Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	timestamp, _ := body["timestamp"].(string)
+	if timestamp == "" || body["sign"] != feishuSign(timestamp, "feishu-secret") {
+		t.Errorf("signed body timestamp = %v, sign = %v", body["timestamp"], body["sign"])
+	}
+
+	if err := (&FeishuNotifier{Url: srv.URL}).Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if _, ok := body["sign"]; ok {
+		t.Errorf("unsigned body contains sign: %v", body)
+	}
+}
+
+// TestEmailBuildMail 测试邮件构造：头部格式、标题编码防止头注入、正文HTML转义
+func TestEmailBuildMail(t *testing.T) {
+	n := &EmailNotifier{To: []string{"a@example.com", "b@example.com"}}
+	msg := &Message{
+		Title:   "任务完成\r\nBcc: evil@example.com",
+		Content: "line1\n<script>alert(1)</script>",
+		Fields:  []Field{{Key: "目标", Value: "a&b"}},
+		Time:    time.Date(2024, 1, 2, 3, 4, 5, 0, time.Local),
+	}
+	mail := string(n.buildMail("scanner@example.com", msg))
+
+	header, body, ok := strings.Cut(mail, "\r\n\r\n")
+	if !ok {
+		t.Fatalf("no header/body separator in %q", mail)
+	}
+	lines := strings.Split(header, "\r\n")
+	headers := make(map[string]string)
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, ": ")
+		if !ok {
+			t.Fatalf("malformed header line %q", line)
+		}
+		headers[key] = value
+	}
+	if len(headers) != len(lines) {
+		t.Errorf("duplicate header lines: %q", lines)
+	}
+	tests := map[string]string{
+		"From":         "scanner@example.com",
+		"To":           "a@example.com, b@example.com",
+		"MIME-Version": "1.0",
+		"Content-Type": "text/html; charset=UTF-8",
+	}
+	for key, want := range tests {
+		if got := headers[key]; got != want {
+			t.Errorf("header %s = %q, want %q", key, got, want)
+		}
+	}
+	if _, ok := headers["Bcc"]; ok {
+		t.Error("title injected a Bcc header")
+	}
+	if subject := headers["Subject"]; !strings.HasPrefix(subject, "=?UTF-8?b?") || strings.ContainsAny(subject, "\r\n") {
+		t.Errorf("Subject = %q, want RFC 2047 encoded word", subject)
+	}
+	if _, err := time.Parse(time.RFC1123Z, headers["Date"]); err != nil {
+		t.Errorf("Date = %q: %v", headers["Date"], err)
+	}
+
+	for _, want := range []string{
+		"<p>line1<br>&lt;script&gt;alert(1)&lt;/script&gt;</p>",
+		"<tr><td><b>目标</b></td><td>a&amp;b</td></tr>",
+		"2024-01-02 03:04:05",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q: %s", want, body)
+		}
+	}
+	if strings.Contains(body, "<script>") {
+		t.Errorf("body not escaped: %s", body)
+	}
+}
+
+// TestEmailSendRequiresAddresses 测试发件人和收件人校验
+func TestEmailSendRequiresAddresses(t *testing.T) {
+	tests := []*EmailNotifier{
+		{Host: "127.0.0.1", Port: 25, To: []string{"a@example.com"}},
+		{Host: "127.0.0.1", Port: 25, From: "scanner@example.com"},
+	}
+	for _, n := range tests {
+		if err := n.Send(context.Background(), &Message{Title: "t"}); err == nil {
+			t.Errorf("Send(%+v) succeeded without sender or recipients", n)
+		}
+	}
+}
+
+// TestFindChannels 测试渠道查找：未指定渠道时按事件订阅查询；指定渠道未订阅事件时接收全部任务事件
+func TestFindChannels(t *testing.T) {
+	mt := mtest.New(t, mtest.NewOptions().ClientType(mtest.Mock))
+
+	channelDoc := func(status string, events ...string) bson.D {
+		doc := bson.D{
+			{Key: "_id", Value: primitive.NewObjectID()},
+			{Key: "name", Value: "ops"},
+			{Key: "type", Value: model.NotifyTypeWebhook},
+			{Key: "status", Value: status},
+		}
+		if events != nil {
+			doc = append(doc, bson.E{Key: "events", Value: events})
+		}
+		return doc
+	}
+	newDispatcher := func(mt *mtest.T) *Dispatcher {
+		mt.AddMockResponses(mtest.CreateSuccessResponse()) // 创建索引
+		d := NewDispatcher(model.NewNotifyChannelModel(mt.DB), nil)
+		mt.ClearEvents()
+		return d
+	}
+
+	mt.Run("subscribed channels by event", func(mt *mtest.T) {
+		d := newDispatcher(mt)
+		ns := mt.DB.Name() + ".notify_channel"
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, ns, mtest.FirstBatch,
+			channelDoc("enable", model.NotifyEventVulFound), channelDoc("enable", model.NotifyEventVulFound)))
+		channels, err := d.findChannels(context.Background(), "", model.NotifyEventVulFound)
+		if err != nil {
+			mt.Fatalf("findChannels: %v", err)
+		}
+		if len(channels) != 2 {
+			mt.Errorf("channels = %d, want 2", len(channels))
+		}
+		filter := mt.GetStartedEvent().Command.Lookup("filter").Document()
+		if filter.Lookup("status").StringValue() != "enable" || filter.Lookup("events").StringValue() != model.NotifyEventVulFound {
+			mt.Errorf("filter = %v", filter)
+		}
+	})
+
+	tests := []struct {
+		name  string
+		doc   bson.D
+		event string
+		want  bool
+	}{
+		{"subscribed", channelDoc("enable", model.NotifyEventTaskComplete), model.NotifyEventTaskComplete, true},
+		{"not subscribed", channelDoc("enable", model.NotifyEventTaskComplete), model.NotifyEventTaskFailed, false},
+		{"no events, task complete", channelDoc("enable"), model.NotifyEventTaskComplete, true},
+		{"no events, task failed", channelDoc("enable"), model.NotifyEventTaskFailed, true},
+		{"empty events, task failed", channelDoc("enable", []string{}...), model.NotifyEventTaskFailed, true},
+		{"no events, vul found", channelDoc("enable"), model.NotifyEventVulFound, false},
+		{"no events, worker offline", channelDoc("enable"), model.NotifyEventWorkerOffline, false},
+		{"disabled", channelDoc("disable", model.NotifyEventTaskComplete), model.NotifyEventTaskComplete, false},
+		{"disabled, no events", channelDoc("disable"), model.NotifyEventTaskComplete, false},
+	}
+	for _, tt := range tests {
+		mt.Run(tt.name, func(mt *mtest.T) {
+			d := newDispatcher(mt)
+			mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".notify_channel", mtest.FirstBatch, tt.doc))
+			id := tt.doc[0].Value.(primitive.ObjectID).Hex()
+			channels, err := d.findChannels(context.Background(), id, tt.event)
+			if err != nil {
+				mt.Fatalf("findChannels: %v", err)
+			}
+			if got := len(channels) == 1; got != tt.want {
+				mt.Errorf("findChannels(%s) returned %d channels, want selected = %v", tt.event, len(channels), tt.want)
+			}
+		})
+	}
+
+	mt.Run("missing channel", func(mt *mtest.T) {
+		d := newDispatcher(mt)
+		mt.AddMockResponses(mtest.CreateCursorResponse(0, mt.DB.Name()+".notify_channel", mtest.FirstBatch))
+		if _, err := d.findChannels(context.Background(), primitive.NewObjectID().Hex(), model.NotifyEventTaskComplete); err == nil {
+			mt.Error("findChannels() succeeded for a missing channel")
+		}
+	})
+
+	mt.Run("invalid channel id", func(mt *mtest.T) {
+		d := newDispatcher(mt)
+		if _, err := d.findChannels(context.Background(), "not-an-id", model.NotifyEventTaskComplete); err == nil {
+			mt.Error("findChannels() succeeded for an invalid id")
+		}
+	})
+}
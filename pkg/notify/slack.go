@@ -0,0 +1,31 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// SlackNotifier Slack Incoming Webhook 通知
+type SlackNotifier struct {
+	Url string
+}
+
+// Send 发送消息
+func (n *SlackNotifier) Send(ctx context.Context, msg *Message) error {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s*\n", msg.Title)
+	if msg.Content != "" {
+		sb.WriteString(msg.Content)
+		sb.WriteString("\n")
+	}
+	for _, f := range msg.Fields {
+		fmt.Fprintf(&sb, "• *%s*: %s\n", f.Key, f.Value)
+	}
+
+	body := map[string]interface{}{
+		"text": sb.String(),
+	}
+	_, err := postJSON(ctx, n.Url, body, nil)
+	return err
+}
@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// WebhookNotifier 通用Webhook通知
+// 请求体为JSON格式的消息，配置了密钥时在 X-CScan-Signature 头中携带 HMAC-SHA256 签名
+type WebhookNotifier struct {
+	Url     string
+	Secret  string
+	Headers map[string]string
+}
+
+// Send 发送消息
+func (n *WebhookNotifier) Send(ctx context.Context, msg *Message) error {
+	data, err := json.Marshal(map[string]interface{}{
+		"event":   msg.Event,
+		"level":   msg.Level,
+		"title":   msg.Title,
+		"content": msg.Content,
+		"fields":  fieldMap(msg.Fields),
+		"time":    msg.Time.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	headers := make(map[string]string, len(n.Headers)+2)
+	for k, v := range n.Headers {
+		headers[k] = v
+	}
+	headers["X-CScan-Event"] = msg.Event
+	if n.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.Secret))
+		mac.Write(data)
+		headers["X-CScan-Signature"] = "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	_, err = postRaw(ctx, n.Url, data, headers)
+	return err
+}
@@ -0,0 +1,38 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// WeComNotifier 企业微信群机器人通知
+type WeComNotifier struct {
+	Url string
+}
+
+// Send 发送消息
+func (n *WeComNotifier) Send(ctx context.Context, msg *Message) error {
+	body := map[string]interface{}{
+		"msgtype": "markdown",
+		"markdown": map[string]string{
+			"content": msg.Markdown(),
+		},
+	}
+	respBody, err := postJSON(ctx, n.Url, body, nil)
+	if err != nil {
+		return err
+	}
+
+	var result struct {
+		ErrCode int    `json:"errcode"`
+		ErrMsg  string `json:"errmsg"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return fmt.Errorf("invalid response: %s", string(respBody))
+	}
+	if result.ErrCode != 0 {
+		return fmt.Errorf("wecom error %d: %s", result.ErrCode, result.ErrMsg)
+	}
+	return nil
+}
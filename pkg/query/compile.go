@@ -0,0 +1,215 @@
+package query
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// FieldType 字段值类型
+type FieldType int
+
+const (
+	TypeString FieldType = iota
+	TypeNumber
+	TypeBool
+)
+
+// BuildFunc 自定义条件构造，用于需要查库转换的字段（如组织名转组织ID）
+// op 为 = 或 ==，取反由编译器统一处理
+type BuildFunc func(op, value string) (bson.M, error)
+
+// Field 查询字段定义
+type Field struct {
+	Keys      []string            // 对应的文档字段，多个字段时任一匹配即可
+	Type      FieldType           // 值类型
+	Transform func(string) string // 值预处理
+	Build     BuildFunc           // 自定义构造，设置后忽略 Keys 和 Type
+}
+
+// Schema 查询字段表
+type Schema struct {
+	Fields  map[string]*Field
+	Default []string // 不带字段名的关键字搜索的文档字段
+}
+
+// Set 设置字段（支持多个别名）
+func (s *Schema) Set(f *Field, names ...string) {
+	for _, name := range names {
+		s.Fields[name] = f
+	}
+}
+
+// Build 解析并编译查询语句，空语句返回空条件
+func Build(input string, schema *Schema) (bson.M, error) {
+	n, err := Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	if n == nil {
+		return bson.M{}, nil
+	}
+	return Compile(n, schema)
+}
+
+// Compile 将语法树编译为 MongoDB 查询条件
+func Compile(n Node, schema *Schema) (bson.M, error) {
+	switch v := n.(type) {
+	case *AndNode:
+		children, err := compileAll(v.Children, schema)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$and": children}, nil
+
+	case *OrNode:
+		children, err := compileAll(v.Children, schema)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$or": children}, nil
+
+	case *NotNode:
+		child, err := Compile(v.Child, schema)
+		if err != nil {
+			return nil, err
+		}
+		return bson.M{"$nor": []bson.M{child}}, nil
+
+	case *TermNode:
+		if len(schema.Default) == 0 {
+			return nil, errorf(v.Pos, "缺少字段名，应为 字段=值 的形式")
+		}
+		return anyOf(schema.Default, func(key string) bson.M {
+			return bson.M{key: fuzzy(v.Value)}
+		}), nil
+
+	case *CondNode:
+		return compileCond(v, schema)
+	}
+	return nil, errorf(0, "未知的语法节点")
+}
+
+func compileAll(nodes []Node, schema *Schema) ([]bson.M, error) {
+	result := make([]bson.M, 0, len(nodes))
+	for _, n := range nodes {
+		m, err := Compile(n, schema)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, m)
+	}
+	return result, nil
+}
+
+func compileCond(c *CondNode, schema *Schema) (bson.M, error) {
+	f, ok := schema.Fields[c.Field]
+	if !ok {
+		return nil, errorf(c.Pos, "未知字段 '%s'", c.Field)
+	}
+
+	value := c.Value
+	if f.Transform != nil {
+		value = f.Transform(value)
+	}
+
+	// != 统一编译为 = 取反
+	op := c.Op
+	negate := op == "!="
+	if negate {
+		op = "="
+	}
+
+	var cond bson.M
+	if f.Build != nil {
+		if op != "=" && op != "==" {
+			return nil, errorf(c.Pos, "字段 '%s' 不支持运算符 '%s'", c.Field, c.Op)
+		}
+		m, err := f.Build(op, value)
+		if err != nil {
+			return nil, errorf(c.Pos, "%v", err)
+		}
+		cond = m
+	} else {
+		expr, err := compileValue(c, f, op, value)
+		if err != nil {
+			return nil, err
+		}
+		cond = anyOf(f.Keys, func(key string) bson.M {
+			return bson.M{key: expr}
+		})
+	}
+
+	if negate {
+		return bson.M{"$nor": []bson.M{cond}}, nil
+	}
+	return cond, nil
+}
+
+// compileValue 生成单个字段的匹配表达式
+func compileValue(c *CondNode, f *Field, op, value string) (interface{}, error) {
+	var v interface{} = value
+	switch f.Type {
+	case TypeNumber:
+		n, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, errorf(c.Pos, "字段 '%s' 的值必须是数字: %s", c.Field, value)
+		}
+		if n == float64(int64(n)) {
+			v = int64(n)
+		} else {
+			v = n
+		}
+	case TypeBool:
+		b, err := strconv.ParseBool(strings.ToLower(value))
+		if err != nil {
+			return nil, errorf(c.Pos, "字段 '%s' 的值必须是 true 或 false: %s", c.Field, value)
+		}
+		if op != "=" && op != "==" {
+			return nil, errorf(c.Pos, "字段 '%s' 不支持运算符 '%s'", c.Field, c.Op)
+		}
+		if !b {
+			// 布尔字段存储时省略了 false，需要兼容字段不存在的情况
+			return bson.M{"$ne": true}, nil
+		}
+		return true, nil
+	}
+
+	switch op {
+	case "=":
+		if f.Type == TypeString {
+			return fuzzy(value), nil
+		}
+		return v, nil
+	case "==":
+		return v, nil
+	case ">":
+		return bson.M{"$gt": v}, nil
+	case ">=":
+		return bson.M{"$gte": v}, nil
+	case "<":
+		return bson.M{"$lt": v}, nil
+	case "<=":
+		return bson.M{"$lte": v}, nil
+	}
+	return nil, errorf(c.Pos, "不支持的运算符 '%s'", c.Op)
+}
+
+// fuzzy 不区分大小写的包含匹配
+func fuzzy(value string) bson.M {
+	return bson.M{"$regex": regexp.QuoteMeta(value), "$options": "i"}
+}
+
+// anyOf 多个字段任一匹配
+func anyOf(keys []string, build func(key string) bson.M) bson.M {
+	if len(keys) == 1 {
+		return build(keys[0])
+	}
+	conds := make([]bson.M, 0, len(keys))
+	for _, key := range keys {
+		conds = append(conds, build(key))
+	}
+	return bson.M{"$or": conds}
+}
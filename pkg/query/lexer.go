@@ -0,0 +1,154 @@
+// Package query 实现资产搜索语法的词法分析、语法分析以及到 MongoDB 查询条件的编译
+//
+// 语法示例:
+//
+//	port=80 && service=http
+//	(title="后台" || title="login") && !app=nginx
+//	port>=8000 && port<9000 && icon_hash=="-1234567"
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// SyntaxError 查询语法错误，Pos 为出错位置（从1开始的字符序号）
+type SyntaxError struct {
+	Pos int
+	Msg string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("查询语法错误(位置%d): %s", e.Pos, e.Msg)
+}
+
+func errorf(pos int, format string, args ...interface{}) *SyntaxError {
+	return &SyntaxError{Pos: pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+type tokenKind int
+
+const (
+	tokEOF    tokenKind = iota
+	tokWord             // 未加引号的字段名或值
+	tokString           // 引号包裹的值
+	tokOp               // 比较运算符: = == != > >= < <=
+	tokAnd              // &&
+	tokOr               // ||
+	tokNot              // !
+	tokLParen           // (
+	tokRParen           // )
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) describe() string {
+	switch t.kind {
+	case tokEOF:
+		return "语句结尾"
+	case tokString:
+		return fmt.Sprintf("\"%s\"", t.text)
+	default:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+}
+
+// isWordRune 判断字符能否出现在未加引号的单词中
+func isWordRune(r rune) bool {
+	if unicode.IsSpace(r) {
+		return false
+	}
+	return !strings.ContainsRune(`()"'!=<>&|`, r)
+}
+
+// tokenize 将查询语句切分为 token 序列
+func tokenize(input string) ([]token, error) {
+	runes := []rune(input)
+	tokens := make([]token, 0, 16)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+
+		switch {
+		case unicode.IsSpace(r):
+			i++
+
+		case r == '(':
+			tokens = append(tokens, token{kind: tokLParen, text: "(", pos: pos})
+			i++
+
+		case r == ')':
+			tokens = append(tokens, token{kind: tokRParen, text: ")", pos: pos})
+			i++
+
+		case r == '&' || r == '|':
+			if i+1 >= len(runes) || runes[i+1] != r {
+				return nil, errorf(pos, "无效的运算符 '%c'，应为 '%c%c'", r, r, r)
+			}
+			kind := tokAnd
+			if r == '|' {
+				kind = tokOr
+			}
+			tokens = append(tokens, token{kind: kind, text: string([]rune{r, r}), pos: pos})
+			i += 2
+
+		case r == '!':
+			if i+1 < len(runes) && runes[i+1] == '=' {
+				tokens = append(tokens, token{kind: tokOp, text: "!=", pos: pos})
+				i += 2
+			} else {
+				tokens = append(tokens, token{kind: tokNot, text: "!", pos: pos})
+				i++
+			}
+
+		case r == '=' || r == '>' || r == '<':
+			op := string(r)
+			i++
+			if i < len(runes) && runes[i] == '=' {
+				op += "="
+				i++
+			}
+			tokens = append(tokens, token{kind: tokOp, text: op, pos: pos})
+
+		case r == '"' || r == '\'':
+			var sb strings.Builder
+			i++
+			closed := false
+			for i < len(runes) {
+				c := runes[i]
+				if c == '\\' && i+1 < len(runes) {
+					sb.WriteRune(runes[i+1])
+					i += 2
+					continue
+				}
+				if c == r {
+					closed = true
+					i++
+					break
+				}
+				sb.WriteRune(c)
+				i++
+			}
+			if !closed {
+				return nil, errorf(pos, "引号未闭合")
+			}
+			tokens = append(tokens, token{kind: tokString, text: sb.String(), pos: pos})
+
+		default:
+			start := i
+			for i < len(runes) && isWordRune(runes[i]) {
+				i++
+			}
+			tokens = append(tokens, token{kind: tokWord, text: string(runes[start:i]), pos: pos})
+		}
+	}
+
+	tokens = append(tokens, token{kind: tokEOF, pos: len(runes) + 1})
+	return tokens, nil
+}
@@ -0,0 +1,173 @@
+package query
+
+import "strings"
+
+// Node 语法树节点
+type Node interface {
+	node()
+}
+
+// AndNode 逻辑与
+type AndNode struct {
+	Children []Node
+}
+
+// OrNode 逻辑或
+type OrNode struct {
+	Children []Node
+}
+
+// NotNode 逻辑非
+type NotNode struct {
+	Child Node
+}
+
+// CondNode 字段条件，如 port>=8000
+type CondNode struct {
+	Field string
+	Op    string
+	Value string
+	Pos   int
+}
+
+// TermNode 不带字段名的关键字，在默认字段中模糊匹配
+type TermNode struct {
+	Value string
+	Pos   int
+}
+
+func (*AndNode) node()  {}
+func (*OrNode) node()   {}
+func (*NotNode) node()  {}
+func (*CondNode) node() {}
+func (*TermNode) node() {}
+
+// Parse 解析查询语句，返回语法树；空语句返回 nil
+//
+// 优先级从低到高: || , && , ! , 括号
+func Parse(input string) (Node, error) {
+	if strings.TrimSpace(input) == "" {
+		return nil, nil
+	}
+	tokens, err := tokenize(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	n, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokEOF {
+		if t.kind == tokRParen {
+			return nil, errorf(t.pos, "多余的右括号")
+		}
+		return nil, errorf(t.pos, "意外的 %s，条件之间需要使用 && 或 || 连接", t.describe())
+	}
+	return n, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	children := []Node{left}
+	for p.peek().kind == tokOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &OrNode{Children: children}, nil
+}
+
+func (p *parser) parseAnd() (Node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	children := []Node{left}
+	for p.peek().kind == tokAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, right)
+	}
+	if len(children) == 1 {
+		return left, nil
+	}
+	return &AndNode{Children: children}, nil
+}
+
+func (p *parser) parseUnary() (Node, error) {
+	if p.peek().kind == tokNot {
+		p.next()
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Node, error) {
+	t := p.next()
+	switch t.kind {
+	case tokLParen:
+		n, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, errorf(t.pos, "括号未闭合")
+		}
+		return n, nil
+
+	case tokWord:
+		if p.peek().kind != tokOp {
+			return &TermNode{Value: t.text, Pos: t.pos}, nil
+		}
+		op := p.next()
+		v := p.next()
+		if v.kind != tokWord && v.kind != tokString {
+			return nil, errorf(v.pos, "运算符 '%s' 后缺少值", op.text)
+		}
+		return &CondNode{Field: strings.ToLower(t.text), Op: op.text, Value: v.text, Pos: t.pos}, nil
+
+	case tokString:
+		return &TermNode{Value: t.text, Pos: t.pos}, nil
+
+	case tokEOF:
+		return nil, errorf(t.pos, "语句不完整，缺少条件")
+
+	default:
+		return nil, errorf(t.pos, "意外的 %s，此处应为条件", t.describe())
+	}
+}
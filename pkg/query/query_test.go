@@ -0,0 +1,119 @@
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestBuild(t *testing.T) {
+	schema := AssetSchema()
+	tests := []struct {
+		name  string
+		input string
+		want  bson.M
+	}{
+		{
+			name:  "empty",
+			input: "  ",
+			want:  bson.M{},
+		},
+		{
+			name:  "fuzzy",
+			input: `title="a.b"`,
+			want:  bson.M{"title": bson.M{"$regex": `a\.b`, "$options": "i"}},
+		},
+		{
+			name:  "exact",
+			input: `icon_hash=="-123"`,
+			want:  bson.M{"icon_hash": "-123"},
+		},
+		{
+			name:  "range",
+			input: "port>=8000 && port<9000",
+			want: bson.M{"$and": []bson.M{
+				{"port": bson.M{"$gte": int64(8000)}},
+				{"port": bson.M{"$lt": int64(9000)}},
+			}},
+		},
+		{
+			name:  "not equal",
+			input: "port!=80",
+			want:  bson.M{"$nor": []bson.M{{"port": int64(80)}}},
+		},
+		{
+			name:  "precedence",
+			input: "(port=80 || port=443) && !cdn=true",
+			want: bson.M{"$and": []bson.M{
+				{"$or": []bson.M{{"port": int64(80)}, {"port": int64(443)}}},
+				{"$nor": []bson.M{{"cdn": true}}},
+			}},
+		},
+		{
+			name:  "or binds looser than and",
+			input: "port=80 || port=443 && risk_level==high",
+			want: bson.M{"$or": []bson.M{
+				{"port": int64(80)},
+				{"$and": []bson.M{{"port": int64(443)}, {"risk_level": "high"}}},
+			}},
+		},
+		{
+			name:  "multi keys",
+			input: "ip==10.0.0.1",
+			want: bson.M{"$or": []bson.M{
+				{"host": "10.0.0.1"},
+				{"ip.ipv4.ip": "10.0.0.1"},
+			}},
+		},
+		{
+			name:  "tls fields",
+			input: `cert.san="example.com" && tls.finding==expired`,
+			want: bson.M{"$and": []bson.M{
+				{"tls.chain.0.sans": bson.M{"$regex": `example\.com`, "$options": "i"}},
+				{"tls.findings": "expired"},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Build(tt.input, schema)
+			if err != nil {
+				t.Fatalf("Build(%q) error: %v", tt.input, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Build(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildSyntaxError(t *testing.T) {
+	schema := AssetSchema()
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{"port=80 &", 9},
+		{"(port=80", 1},
+		{"port=80)", 8},
+		{"port=", 6},
+		{`title="abc`, 7},
+		{"port=80 title=a", 9},
+		{"unknown=1", 1},
+		{"port=abc", 1},
+		{"port=80 && ", 12},
+	}
+	for _, tt := range tests {
+		_, err := Build(tt.input, schema)
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Build(%q) error = %v, want SyntaxError", tt.input, err)
+			continue
+		}
+		if se.Pos != tt.pos {
+			t.Errorf("Build(%q) pos = %d, want %d (%v)", tt.input, se.Pos, tt.pos, se)
+		}
+	}
+}
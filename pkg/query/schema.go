@@ -0,0 +1,94 @@
+package query
+
+// str 字符串字段
+func str(keys ...string) *Field {
+	return &Field{Keys: keys, Type: TypeString}
+}
+
+// num 数值字段
+func num(keys ...string) *Field {
+	return &Field{Keys: keys, Type: TypeNumber}
+}
+
+// boolean 布尔字段
+func boolean(keys ...string) *Field {
+	return &Field{Keys: keys, Type: TypeBool}
+}
+
+// AssetSchema 资产（含 IP、域名、站点视图）查询字段
+func AssetSchema() *Schema {
+	s := &Schema{
+		Fields:  map[string]*Field{},
+		Default: []string{"host", "title", "banner", "app"},
+	}
+	s.Set(str("host", "ip.ipv4.ip"), "host", "ip")
+	s.Set(num("port"), "port")
+	s.Set(str("service"), "service", "protocol")
+	s.Set(str("title"), "title")
+	s.Set(str("app"), "app", "finger", "fingerprint")
+	s.Set(str("status"), "status", "httpstatus")
+	s.Set(str("domain"), "domain")
+	s.Set(str("authority"), "authority", "site")
+	s.Set(str("banner"), "banner")
+	s.Set(str("server"), "server")
+	s.Set(str("header"), "header")
+	s.Set(str("body"), "body")
+	s.Set(str("cert"), "cert")
+	s.Set(str("icon_hash"), "icon_hash", "iconhash")
+	s.Set(str("screenshot_hash"), "screenshot_hash", "phash")
+	s.Set(str("org_id"), "org")
+	s.Set(str("risk_level"), "risk_level")
+	s.Set(num("risk_score"), "risk_score")
+	s.Set(str("cname"), "cname")
+	s.Set(boolean("cdn"), "cdn", "is_cdn")
+	s.Set(boolean("cloud"), "cloud", "is_cloud")
+	s.Set(str("cdn_name"), "cdn_name", "cdn.provider")
+	s.Set(str("waf"), "waf")
+	s.Set(str("cloud_name"), "cloud_name", "cloud.provider")
+	s.Set(str("ip.ipv4.location"), "location")
+	s.Set(str("ip.ipv4.country"), "country")
+	s.Set(str("ip.ipv4.region"), "region", "province")
+	s.Set(str("ip.ipv4.city"), "city")
+	s.Set(num("ip.ipv4.asn"), "asn")
+	s.Set(str("ip.ipv4.org"), "asn.org", "as_org")
+	s.Set(str("ip.ipv4.isp"), "isp")
+	s.Set(str("source"), "source")
+	s.Set(str("category"), "category")
+	s.Set(str("transport"), "transport")
+	s.Set(str("tls.chain.0.subject"), "cert.subject")
+	s.Set(str("tls.chain.0.issuer"), "cert.issuer")
+	s.Set(str("tls.chain.0.sans"), "cert.san")
+	s.Set(str("tls.chain.0.serial_number"), "cert.serial")
+	s.Set(str("tls.chain.0.sha256"), "cert.sha256")
+	s.Set(str("tls.chain.0.key_type"), "cert.key")
+	s.Set(str("tls.versions"), "tls.version")
+	s.Set(str("tls.cipher_suites"), "tls.cipher")
+	s.Set(str("tls.findings"), "tls.finding")
+	s.Set(str("tls.ja3s"), "ja3s")
+	s.Set(str("tls.jarm"), "jarm")
+	return s
+}
+
+// VulSchema 漏洞查询字段
+func VulSchema() *Schema {
+	s := &Schema{
+		Fields:  map[string]*Field{},
+		Default: []string{"authority", "url", "pocfile", "result"},
+	}
+	s.Set(str("host"), "host", "ip")
+	s.Set(num("port"), "port")
+	s.Set(str("authority"), "authority")
+	s.Set(str("url"), "url")
+	s.Set(str("pocfile"), "poc", "pocfile", "template")
+	s.Set(str("severity"), "severity", "risk_level")
+	s.Set(str("source"), "source")
+	s.Set(str("result"), "result")
+	s.Set(str("cve_id"), "cve", "cve_id")
+	s.Set(str("cwe_id"), "cwe", "cwe_id")
+	s.Set(num("cvss_score"), "cvss", "cvss_score")
+	s.Set(str("matcher_name"), "matcher")
+	s.Set(str("task_id"), "task", "task_id")
+	s.Set(str("status"), "status", "state")
+	s.Set(str("assignee"), "assignee")
+	return s
+}
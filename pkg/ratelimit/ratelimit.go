@@ -0,0 +1,201 @@
+// Package ratelimit 基于 Redis 的分布式令牌桶，协调多个 Worker 和子任务对同一目标的访问速率
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// DefaultCIDRPrefix 默认按 IPv4 /24 网段限速，IPv6 固定按 /64 网段限速
+const DefaultCIDRPrefix = 24
+
+const keyPrefix = "cscan:ratelimit:"
+
+// Budget 工作空间的限速预算
+// 速率为每秒请求数（端口扫描为每秒探测包数），0 表示不限制
+type Budget struct {
+	HostRate      int `json:"hostRate"`      // 单个目标主机
+	CIDRRate      int `json:"cidrRate"`      // 同一网段
+	CIDRPrefix    int `json:"cidrPrefix"`    // IPv4 网段前缀长度，默认 24
+	WorkspaceRate int `json:"workspaceRate"` // 整个工作空间
+}
+
+// Bucket 令牌桶，容量为一秒的令牌数
+type Bucket struct {
+	Key  string `json:"key"`
+	Rate int    `json:"rate"`
+}
+
+// Enabled 是否配置了任一限速
+func (b *Budget) Enabled() bool {
+	return b != nil && (b.HostRate > 0 || b.CIDRRate > 0 || b.WorkspaceRate > 0)
+}
+
+// Validate 校验预算配置
+func (b *Budget) Validate() error {
+	if b.HostRate < 0 || b.CIDRRate < 0 || b.WorkspaceRate < 0 {
+		return fmt.Errorf("速率不能为负数")
+	}
+	if b.CIDRPrefix != 0 && (b.CIDRPrefix < 8 || b.CIDRPrefix > 32) {
+		return fmt.Errorf("网段前缀长度必须在 8-32 之间")
+	}
+	return nil
+}
+
+// Buckets 访问 host 需要获取令牌的令牌桶
+// 主机和网段的令牌桶不区分工作空间，多个工作空间扫描同一目标时共享
+func (b *Budget) Buckets(workspaceId, host string) []Bucket {
+	if !b.Enabled() {
+		return nil
+	}
+	host = NormalizeHost(host)
+	var buckets []Bucket
+	if b.HostRate > 0 && host != "" {
+		buckets = append(buckets, Bucket{Key: "host:" + host, Rate: b.HostRate})
+	}
+	if b.CIDRRate > 0 {
+		if network := b.network(host); network != "" {
+			buckets = append(buckets, Bucket{Key: "cidr:" + network, Rate: b.CIDRRate})
+		}
+	}
+	if b.WorkspaceRate > 0 {
+		buckets = append(buckets, Bucket{Key: "ws:" + workspaceId, Rate: b.WorkspaceRate})
+	}
+	return buckets
+}
+
+// Rate 访问 host 的最严格速率，扫描器据此限制自身的发包速率，0 表示不限制
+func (b *Budget) Rate(workspaceId, host string) int {
+	rate := 0
+	for _, bucket := range b.Buckets(workspaceId, host) {
+		if rate == 0 || bucket.Rate < rate {
+			rate = bucket.Rate
+		}
+	}
+	return rate
+}
+
+// network 主机所在网段，域名返回空
+func (b *Budget) network(host string) string {
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return ""
+	}
+	bits, prefix := 128, 64
+	if ip4 := ip.To4(); ip4 != nil {
+		ip, bits, prefix = ip4, 32, b.CIDRPrefix
+		if prefix <= 0 {
+			prefix = DefaultCIDRPrefix
+		}
+	}
+	ipNet := &net.IPNet{IP: ip.Mask(net.CIDRMask(prefix, bits)), Mask: net.CIDRMask(prefix, bits)}
+	return ipNet.String()
+}
+
+// NormalizeHost 提取目标中的主机，URL 和 host:port 取主机部分
+func NormalizeHost(target string) string {
+	target = strings.TrimSpace(target)
+	if i := strings.Index(target, "://"); i >= 0 {
+		target = target[i+3:]
+	}
+	if i := strings.IndexAny(target, "/?#"); i >= 0 {
+		target = target[:i]
+	}
+	if host, _, err := net.SplitHostPort(target); err == nil {
+		target = host
+	}
+	target = strings.ToLower(strings.Trim(target, "[]."))
+	if ip := net.ParseIP(target); ip != nil {
+		return ip.String()
+	}
+	return target
+}
+
+// ValidKey 令牌桶 Key 是否为 Buckets 生成的格式
+func ValidKey(key string) bool {
+	if len(key) > 256 {
+		return false
+	}
+	for _, prefix := range []string{"host:", "cidr:", "ws:"} {
+		if strings.HasPrefix(key, prefix) && len(key) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// takeScript 从全部令牌桶原子获取令牌，任一令牌桶不足时不扣减
+// KEYS: 令牌桶 ARGV: 请求令牌数, 各令牌桶速率
+// 使用 Redis 服务器时间计算补充的令牌，避免各 API 实例时钟不一致
+// 返回 {获得的令牌数, 需要等待的毫秒数}，请求数超过最小容量时只获取最小容量
+var takeScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local n = tonumber(ARGV[1])
+for i = 1, #KEYS do
+  local rate = tonumber(ARGV[1 + i])
+  if rate < n then n = rate end
+end
+local tokens = {}
+local wait = 0
+for i, key in ipairs(KEYS) do
+  local rate = tonumber(ARGV[1 + i])
+  local v = redis.call('HMGET', key, 'tokens', 'ts')
+  local t = tonumber(v[1]) or rate
+  local ts = tonumber(v[2]) or now
+  t = math.min(rate, t + math.max(0, now - ts) * rate / 1000)
+  tokens[i] = t
+  if t < n then
+    local w = math.ceil((n - t) * 1000 / rate)
+    if w > wait then wait = w end
+  end
+end
+if wait > 0 then
+  return {0, wait}
+end
+for i, key in ipairs(KEYS) do
+  redis.call('HSET', key, 'tokens', tostring(tokens[i] - n), 'ts', tostring(now))
+  redis.call('PEXPIRE', key, 60000)
+end
+return {n, 0}
+`)
+
+// Limiter Redis 分布式令牌桶
+type Limiter struct {
+	rdb *redis.Client
+}
+
+// NewLimiter 创建限速器
+func NewLimiter(rdb *redis.Client) *Limiter {
+	return &Limiter{rdb: rdb}
+}
+
+// Take 从全部令牌桶获取 n 个令牌
+// 返回获得的令牌数（不超过最小的令牌桶容量）；令牌不足时返回 0 和需要等待的时间
+func (l *Limiter) Take(ctx context.Context, buckets []Bucket, n int) (int, time.Duration, error) {
+	if len(buckets) == 0 || n <= 0 {
+		return n, 0, nil
+	}
+	keys := make([]string, 0, len(buckets))
+	args := []interface{}{n}
+	for _, b := range buckets {
+		if b.Rate <= 0 {
+			return 0, 0, fmt.Errorf("invalid rate %d for bucket %s", b.Rate, b.Key)
+		}
+		keys = append(keys, keyPrefix+b.Key)
+		args = append(args, b.Rate)
+	}
+	res, err := takeScript.Run(ctx, l.rdb, keys, args...).Int64Slice()
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(res) != 2 {
+		return 0, 0, fmt.Errorf("unexpected result: %v", res)
+	}
+	return int(res[0]), time.Duration(res[1]) * time.Millisecond, nil
+}
@@ -0,0 +1,86 @@
+package ratelimit
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestBuckets(t *testing.T) {
+	b := &Budget{HostRate: 10, CIDRRate: 50, WorkspaceRate: 200}
+	want := []Bucket{
+		{Key: "host:10.1.2.3", Rate: 10},
+		{Key: "cidr:10.1.2.0/24", Rate: 50},
+		{Key: "ws:ws1", Rate: 200},
+	}
+	if got := b.Buckets("ws1", "https://10.1.2.3:8443/login"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Buckets() = %v, want %v", got, want)
+	}
+	if got := b.Rate("ws1", "10.1.2.3"); got != 10 {
+		t.Errorf("Rate() = %d, want 10", got)
+	}
+
+	// 域名没有网段令牌桶
+	want = []Bucket{{Key: "host:example.com", Rate: 10}, {Key: "ws:ws1", Rate: 200}}
+	if got := b.Buckets("ws1", "Example.COM:80"); !reflect.DeepEqual(got, want) {
+		t.Errorf("Buckets(domain) = %v, want %v", got, want)
+	}
+
+	b = &Budget{CIDRRate: 50, CIDRPrefix: 16}
+	if got := b.Buckets("ws1", "[2001:db8::1]:443"); len(got) != 1 || got[0].Key != "cidr:2001:db8::/64" {
+		t.Errorf("Buckets(ipv6) = %v", got)
+	}
+	if got := b.Buckets("ws1", "172.16.9.9"); len(got) != 1 || got[0].Key != "cidr:172.16.0.0/16" {
+		t.Errorf("Buckets(/16) = %v", got)
+	}
+
+	if (&Budget{}).Buckets("ws1", "10.1.2.3") != nil {
+		t.Error("Buckets() without budget should be nil")
+	}
+	if !ValidKey("cidr:10.1.2.0/24") || ValidKey("session:abc") || ValidKey("host:") {
+		t.Error("ValidKey() mismatch")
+	}
+}
+
+func TestLimiterTake(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis: %v", err)
+	}
+	defer mr.Close()
+	// 令牌补充使用 Redis 服务器时间
+	now := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	mr.SetTime(now)
+	l := NewLimiter(redis.NewClient(&redis.Options{Addr: mr.Addr()}))
+	ctx := context.Background()
+	buckets := []Bucket{{Key: "host:10.1.2.3", Rate: 10}, {Key: "ws:ws1", Rate: 100}}
+
+	// 请求数超过最小容量时只获取最小容量
+	got, wait, err := l.Take(ctx, buckets, 25)
+	if err != nil || got != 10 || wait != 0 {
+		t.Fatalf("Take(25) = %d, %v, %v; want 10, 0, nil", got, wait, err)
+	}
+	// 主机令牌桶已耗尽，需要等待，工作空间令牌桶不扣减
+	got, wait, err = l.Take(ctx, buckets, 5)
+	if err != nil || got != 0 || wait <= 0 || wait > 500*time.Millisecond {
+		t.Fatalf("Take(5) = %d, %v, %v; want 0 and ~500ms wait", got, wait, err)
+	}
+	if tokens := mr.HGet("cscan:ratelimit:ws:ws1", "tokens"); tokens != "90" {
+		t.Errorf("workspace tokens = %q, want 90", tokens)
+	}
+	mr.SetTime(now.Add(500 * time.Millisecond))
+	if got, wait, err = l.Take(ctx, buckets, 5); err != nil || got != 5 || wait != 0 {
+		t.Fatalf("Take(5) after 500ms = %d, %v, %v; want 5, 0, nil", got, wait, err)
+	}
+
+	if _, _, err := l.Take(ctx, []Bucket{{Key: "ws:ws1", Rate: 0}}, 1); err == nil {
+		t.Error("Take() with zero rate expected error")
+	}
+	if got, _, _ := l.Take(ctx, nil, 3); got != 3 {
+		t.Errorf("Take() without buckets = %d, want 3", got)
+	}
+}
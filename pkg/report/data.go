@@ -0,0 +1,181 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// JSONRenderer 输出完整报告数据
+type JSONRenderer struct{}
+
+func (j *JSONRenderer) Render(r *Report) ([]byte, error) {
+	return json.MarshalIndent(r, "", "  ")
+}
+
+func (j *JSONRenderer) ContentType() string { return "application/json; charset=utf-8" }
+
+func (j *JSONRenderer) Extension() string { return "json" }
+
+// CSVRenderer 每行一个漏洞，便于导入工单系统
+type CSVRenderer struct{}
+
+var csvHeaders = []string{
+	"severity", "authority", "host", "port", "url", "poc", "source", "cve", "cwe", "cvss",
+	"result", "remediation", "references", "curl_command", "create_time",
+}
+
+func (c *CSVRenderer) Render(r *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	// UTF-8 BOM，避免 Excel 打开中文乱码
+	buf.WriteString("\xEF\xBB\xBF")
+
+	w := csv.NewWriter(&buf)
+	if err := w.Write(csvHeaders); err != nil {
+		return nil, err
+	}
+	for _, f := range r.Findings {
+		cvss := ""
+		if f.CvssScore > 0 {
+			cvss = strconv.FormatFloat(f.CvssScore, 'f', 1, 64)
+		}
+		record := []string{
+			f.Severity, f.Authority, f.Host, strconv.Itoa(f.Port), f.Url, f.PocFile, f.Source,
+			f.CveId, f.CweId, cvss, f.Result, f.Remediation, strings.Join(f.References, " "),
+			f.CurlCommand, f.CreateTime,
+		}
+		for i, v := range record {
+			record[i] = csvSafe(v)
+		}
+		if err := w.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// csvSafe 以 ' 前缀转义可能被表格软件当作公式执行的单元格（扫描结果来自目标站点，不可信）
+func csvSafe(v string) string {
+	if v != "" && strings.ContainsRune("=+-@\t\r", rune(v[0])) {
+		return "'" + v
+	}
+	return v
+}
+
+func (c *CSVRenderer) ContentType() string { return "text/csv; charset=utf-8" }
+
+func (c *CSVRenderer) Extension() string { return "csv" }
+
+// ExcelRenderer 输出包含概览、资产、漏洞三个工作表的 Excel
+type ExcelRenderer struct{}
+
+func (e *ExcelRenderer) Render(r *Report) ([]byte, error) {
+	f := excelize.NewFile()
+	defer f.Close()
+
+	// 概览Sheet
+	f.SetSheetName("Sheet1", "概览")
+	f.SetCellValue("概览", "A1", r.Title)
+	overview := [][]interface{}{
+		{"任务名称", r.TaskName},
+		{"扫描目标", r.Target},
+		{"任务状态", r.Status},
+		{"创建时间", r.CreateTime},
+		{"资产数量", r.Summary.AssetCount},
+		{"漏洞数量", r.Summary.VulCount},
+		{"整体风险", severityText[r.Summary.RiskLevel]},
+		{"执行摘要", r.Summary.Text},
+	}
+	row := 3
+	for _, kv := range overview {
+		f.SetCellValue("概览", fmt.Sprintf("A%d", row), kv[0])
+		f.SetCellValue("概览", fmt.Sprintf("B%d", row), kv[1])
+		row++
+	}
+	row++
+	f.SetCellValue("概览", fmt.Sprintf("A%d", row), "风险分布")
+	for _, item := range r.Summary.Distribution {
+		row++
+		f.SetCellValue("概览", fmt.Sprintf("A%d", row), item.Text)
+		f.SetCellValue("概览", fmt.Sprintf("B%d", row), item.Count)
+	}
+
+	titleStyle, _ := f.NewStyle(&excelize.Style{
+		Font:      &excelize.Font{Bold: true, Size: 16},
+		Alignment: &excelize.Alignment{Horizontal: "center"},
+	})
+	f.SetCellStyle("概览", "A1", "A1", titleStyle)
+	f.MergeCell("概览", "A1", "B1")
+	f.SetColWidth("概览", "A", "A", 15)
+	f.SetColWidth("概览", "B", "B", 80)
+
+	// 资产Sheet
+	f.NewSheet("资产列表")
+	assetHeaders := []string{"地址", "主机", "端口", "服务", "标题", "应用", "状态码", "Server", "IconHash", "风险", "发现时间"}
+	for i, h := range assetHeaders {
+		f.SetCellValue("资产列表", fmt.Sprintf("%c1", 'A'+i), h)
+	}
+	for i, a := range r.Assets {
+		row := i + 2
+		f.SetCellValue("资产列表", fmt.Sprintf("A%d", row), a.Authority)
+		f.SetCellValue("资产列表", fmt.Sprintf("B%d", row), a.Host)
+		f.SetCellValue("资产列表", fmt.Sprintf("C%d", row), a.Port)
+		f.SetCellValue("资产列表", fmt.Sprintf("D%d", row), a.Service)
+		f.SetCellValue("资产列表", fmt.Sprintf("E%d", row), a.Title)
+		f.SetCellValue("资产列表", fmt.Sprintf("F%d", row), strings.Join(a.App, ", "))
+		f.SetCellValue("资产列表", fmt.Sprintf("G%d", row), a.HttpStatus)
+		f.SetCellValue("资产列表", fmt.Sprintf("H%d", row), a.Server)
+		f.SetCellValue("资产列表", fmt.Sprintf("I%d", row), a.IconHash)
+		f.SetCellValue("资产列表", fmt.Sprintf("J%d", row), severityText[a.RiskLevel])
+		f.SetCellValue("资产列表", fmt.Sprintf("K%d", row), a.CreateTime)
+	}
+
+	// 漏洞Sheet
+	f.NewSheet("漏洞列表")
+	vulHeaders := []string{"地址", "URL", "POC", "严重级别", "结果", "修复建议", "复现命令", "发现时间"}
+	for i, h := range vulHeaders {
+		f.SetCellValue("漏洞列表", fmt.Sprintf("%c1", 'A'+i), h)
+	}
+	for i, v := range r.Findings {
+		row := i + 2
+		f.SetCellValue("漏洞列表", fmt.Sprintf("A%d", row), v.Authority)
+		f.SetCellValue("漏洞列表", fmt.Sprintf("B%d", row), v.Url)
+		f.SetCellValue("漏洞列表", fmt.Sprintf("C%d", row), v.PocFile)
+		f.SetCellValue("漏洞列表", fmt.Sprintf("D%d", row), v.Severity)
+		f.SetCellValue("漏洞列表", fmt.Sprintf("E%d", row), v.Result)
+		f.SetCellValue("漏洞列表", fmt.Sprintf("F%d", row), v.Remediation)
+		f.SetCellValue("漏洞列表", fmt.Sprintf("G%d", row), v.CurlCommand)
+		f.SetCellValue("漏洞列表", fmt.Sprintf("H%d", row), v.CreateTime)
+	}
+
+	// 设置列宽
+	f.SetColWidth("资产列表", "A", "A", 30)
+	f.SetColWidth("资产列表", "B", "B", 15)
+	f.SetColWidth("资产列表", "E", "E", 40)
+	f.SetColWidth("资产列表", "F", "F", 30)
+	f.SetColWidth("漏洞列表", "A", "A", 30)
+	f.SetColWidth("漏洞列表", "B", "B", 50)
+	f.SetColWidth("漏洞列表", "C", "C", 40)
+	f.SetColWidth("漏洞列表", "E", "G", 50)
+
+	var buf bytes.Buffer
+	if err := f.Write(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (e *ExcelRenderer) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+func (e *ExcelRenderer) Extension() string { return "xlsx" }
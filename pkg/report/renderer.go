@@ -0,0 +1,96 @@
+package report
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 报告格式
+const (
+	FormatExcel    = "excel"
+	FormatHTML     = "html"
+	FormatPDF      = "pdf" // 适合浏览器打印为 PDF 的 HTML
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatCSV      = "csv"
+)
+
+// Renderer 报告渲染器
+type Renderer interface {
+	Render(r *Report) ([]byte, error)
+	ContentType() string
+	Extension() string
+}
+
+// NormalizeFormat 统一格式名称，空值默认为 excel
+func NormalizeFormat(format string) string {
+	switch f := strings.ToLower(strings.TrimSpace(format)); f {
+	case "", "xlsx", "excel":
+		return FormatExcel
+	case "md", "markdown":
+		return FormatMarkdown
+	default:
+		return f
+	}
+}
+
+// IsTemplateFormat 是否为支持自定义模板的格式
+func IsTemplateFormat(format string) bool {
+	switch NormalizeFormat(format) {
+	case FormatHTML, FormatPDF, FormatMarkdown:
+		return true
+	}
+	return false
+}
+
+// NewRenderer 按格式创建渲染器
+// tpl 为自定义模板内容，仅对 html/pdf/markdown 生效，为空时使用内置模板
+func NewRenderer(format, tpl string) (Renderer, error) {
+	switch NormalizeFormat(format) {
+	case FormatExcel:
+		return &ExcelRenderer{}, nil
+	case FormatHTML:
+		return NewHTMLRenderer(tpl, false)
+	case FormatPDF:
+		return NewHTMLRenderer(tpl, true)
+	case FormatMarkdown:
+		return NewMarkdownRenderer(tpl)
+	case FormatJSON:
+		return &JSONRenderer{}, nil
+	case FormatCSV:
+		return &CSVRenderer{}, nil
+	}
+	return nil, fmt.Errorf("不支持的报告格式: %s", format)
+}
+
+// ValidateTemplate 校验模板能否解析并渲染示例报告
+func ValidateTemplate(format, tpl string) error {
+	if !IsTemplateFormat(format) {
+		return fmt.Errorf("格式 %s 不支持自定义模板", format)
+	}
+	renderer, err := NewRenderer(format, tpl)
+	if err != nil {
+		return err
+	}
+	_, err = renderer.Render(sampleReport())
+	return err
+}
+
+// sampleReport 用于模板校验的示例报告
+func sampleReport() *Report {
+	return New(Meta{TaskName: "示例任务", Target: "example.com", Status: "SUCCESS"},
+		[]*Asset{{Authority: "example.com:443", Host: "example.com", Port: 443, Service: "https", App: []string{"nginx"}}},
+		[]*Finding{{
+			Authority:   "example.com:443",
+			Host:        "example.com",
+			Port:        443,
+			Url:         "https://example.com/",
+			PocFile:     "example-poc",
+			Severity:    "high",
+			Request:     "GET / HTTP/1.1",
+			Response:    "HTTP/1.1 200 OK",
+			CurlCommand: "curl https://example.com/",
+			Remediation: "升级到最新版本",
+			References:  []string{"https://example.com/advisory"},
+		}})
+}
@@ -0,0 +1,235 @@
+// Package report 扫描报告的数据组织与多格式渲染
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 严重级别（按严重程度降序）
+var severityOrder = []string{"critical", "high", "medium", "low", "info", "unknown"}
+
+var severityText = map[string]string{
+	"critical": "严重",
+	"high":     "高危",
+	"medium":   "中危",
+	"low":      "低危",
+	"info":     "信息",
+	"unknown":  "未知",
+}
+
+// severityRank 严重级别排序值，越大越严重
+func severityRank(severity string) int {
+	for i, s := range severityOrder {
+		if s == severity {
+			return len(severityOrder) - i
+		}
+	}
+	return 0
+}
+
+// normalizeSeverity 统一严重级别写法
+func normalizeSeverity(severity string) string {
+	severity = strings.ToLower(strings.TrimSpace(severity))
+	if _, ok := severityText[severity]; !ok {
+		return "unknown"
+	}
+	return severity
+}
+
+// Meta 报告基本信息
+type Meta struct {
+	Title      string `json:"title"`
+	TaskId     string `json:"taskId"`
+	TaskName   string `json:"taskName"`
+	Target     string `json:"target"`
+	Status     string `json:"status"`
+	CreateTime string `json:"createTime"`
+}
+
+// Finding 漏洞发现（含证据与修复建议）
+type Finding struct {
+	Authority        string   `json:"authority"`
+	Host             string   `json:"host"`
+	Port             int      `json:"port"`
+	Url              string   `json:"url"`
+	PocFile          string   `json:"pocFile"`
+	Source           string   `json:"source"`
+	Severity         string   `json:"severity"`
+	Result           string   `json:"result"`
+	CveId            string   `json:"cveId,omitempty"`
+	CweId            string   `json:"cweId,omitempty"`
+	CvssScore        float64  `json:"cvssScore,omitempty"`
+	Remediation      string   `json:"remediation,omitempty"`
+	References       []string `json:"references,omitempty"`
+	MatcherName      string   `json:"matcherName,omitempty"`
+	ExtractedResults []string `json:"extractedResults,omitempty"`
+	Request          string   `json:"request,omitempty"`
+	Response         string   `json:"response,omitempty"`
+	CurlCommand      string   `json:"curlCommand,omitempty"`
+	CreateTime       string   `json:"createTime"`
+}
+
+// HasEvidence 是否有请求/响应证据
+func (f *Finding) HasEvidence() bool {
+	return f.Request != "" || f.Response != "" || f.CurlCommand != ""
+}
+
+// Asset 资产及其漏洞
+type Asset struct {
+	Authority  string     `json:"authority"`
+	Host       string     `json:"host"`
+	Port       int        `json:"port"`
+	Service    string     `json:"service"`
+	Title      string     `json:"title"`
+	App        []string   `json:"app"`
+	HttpStatus string     `json:"httpStatus"`
+	Server     string     `json:"server"`
+	IconHash   string     `json:"iconHash"`
+	CreateTime string     `json:"createTime"`
+	RiskLevel  string     `json:"riskLevel"` // 资产上最高的漏洞级别，无漏洞为空
+	Findings   []*Finding `json:"findings"`
+}
+
+// SeverityCount 风险分布项
+type SeverityCount struct {
+	Severity string  `json:"severity"`
+	Text     string  `json:"text"`
+	Count    int     `json:"count"`
+	Percent  float64 `json:"percent"`
+}
+
+// Summary 执行摘要
+type Summary struct {
+	AssetCount     int             `json:"assetCount"`
+	HostCount      int             `json:"hostCount"`
+	VulCount       int             `json:"vulCount"`
+	AffectedAssets int             `json:"affectedAssets"`
+	RiskLevel      string          `json:"riskLevel"` // 整体风险等级，取最高漏洞级别
+	Distribution   []SeverityCount `json:"distribution"`
+	Text           string          `json:"text"`
+}
+
+// Report 报告
+type Report struct {
+	Meta
+	GeneratedAt string     `json:"generatedAt"`
+	Summary     Summary    `json:"summary"`
+	Assets      []*Asset   `json:"assets"`
+	Findings    []*Finding `json:"findings"`
+}
+
+// New 组装报告：按资产归集漏洞并生成执行摘要
+func New(meta Meta, assets []*Asset, findings []*Finding) *Report {
+	if meta.Title == "" {
+		meta.Title = meta.TaskName + " 安全评估报告"
+	}
+	r := &Report{
+		Meta:        meta,
+		GeneratedAt: time.Now().Format("2006-01-02 15:04:05"),
+		Assets:      assets,
+		Findings:    findings,
+	}
+
+	for _, f := range findings {
+		f.Severity = normalizeSeverity(f.Severity)
+	}
+	sort.SliceStable(r.Findings, func(i, j int) bool {
+		return severityRank(r.Findings[i].Severity) > severityRank(r.Findings[j].Severity)
+	})
+
+	// 按 authority 归集漏洞到资产，找不到资产的漏洞单独建立条目
+	index := make(map[string]*Asset, len(assets))
+	for _, a := range r.Assets {
+		index[assetKey(a.Authority, a.Host, a.Port)] = a
+	}
+	for _, f := range r.Findings {
+		key := assetKey(f.Authority, f.Host, f.Port)
+		a, ok := index[key]
+		if !ok {
+			a = &Asset{Authority: f.Authority, Host: f.Host, Port: f.Port}
+			index[key] = a
+			r.Assets = append(r.Assets, a)
+		}
+		a.Findings = append(a.Findings, f)
+		if severityRank(f.Severity) > severityRank(a.RiskLevel) {
+			a.RiskLevel = f.Severity
+		}
+	}
+	sort.SliceStable(r.Assets, func(i, j int) bool {
+		ri, rj := severityRank(r.Assets[i].RiskLevel), severityRank(r.Assets[j].RiskLevel)
+		if ri != rj {
+			return ri > rj
+		}
+		return len(r.Assets[i].Findings) > len(r.Assets[j].Findings)
+	})
+
+	r.Summary = summarize(r)
+	return r
+}
+
+func assetKey(authority, host string, port int) string {
+	if authority != "" {
+		return authority
+	}
+	return host + ":" + strconv.Itoa(port)
+}
+
+// summarize 统计风险分布并生成摘要文字
+func summarize(r *Report) Summary {
+	s := Summary{
+		AssetCount: len(r.Assets),
+		VulCount:   len(r.Findings),
+	}
+
+	hosts := make(map[string]struct{})
+	for _, a := range r.Assets {
+		if a.Host != "" {
+			hosts[a.Host] = struct{}{}
+		}
+		if len(a.Findings) > 0 {
+			s.AffectedAssets++
+		}
+	}
+	s.HostCount = len(hosts)
+
+	counts := make(map[string]int)
+	for _, f := range r.Findings {
+		counts[f.Severity]++
+		if severityRank(f.Severity) > severityRank(s.RiskLevel) {
+			s.RiskLevel = f.Severity
+		}
+	}
+	for _, sev := range severityOrder {
+		item := SeverityCount{Severity: sev, Text: severityText[sev], Count: counts[sev]}
+		if s.VulCount > 0 {
+			item.Percent = float64(item.Count) * 100 / float64(s.VulCount)
+		}
+		s.Distribution = append(s.Distribution, item)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "本次对 %s 的扫描共发现 %d 个资产（%d 个主机），", r.Target, s.AssetCount, s.HostCount)
+	if s.VulCount == 0 {
+		sb.WriteString("未发现安全漏洞。")
+	} else {
+		fmt.Fprintf(&sb, "识别出 %d 个安全问题，涉及 %d 个资产", s.VulCount, s.AffectedAssets)
+		parts := make([]string, 0, len(severityOrder))
+		for _, item := range s.Distribution {
+			if item.Count > 0 {
+				parts = append(parts, fmt.Sprintf("%s %d 个", item.Text, item.Count))
+			}
+		}
+		fmt.Fprintf(&sb, "，其中%s。整体风险等级为%s", strings.Join(parts, "、"), severityText[s.RiskLevel])
+		if severityRank(s.RiskLevel) >= severityRank("high") {
+			sb.WriteString("，建议优先处置严重及高危问题。")
+		} else {
+			sb.WriteString("。")
+		}
+	}
+	s.Text = sb.String()
+	return s
+}
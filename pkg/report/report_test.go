@@ -0,0 +1,96 @@
+package report
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewGroupsFindingsByAsset(t *testing.T) {
+	r := New(Meta{TaskName: "t", Target: "example.com"},
+		[]*Asset{
+			{Authority: "a:80", Host: "a", Port: 80},
+			{Authority: "b:443", Host: "b", Port: 443},
+		},
+		[]*Finding{
+			{Authority: "b:443", Host: "b", Port: 443, Severity: "LOW"},
+			{Authority: "b:443", Host: "b", Port: 443, Severity: "critical"},
+			{Host: "c", Port: 22, Severity: "bogus"},
+		})
+
+	if r.Summary.AssetCount != 3 || r.Summary.VulCount != 3 || r.Summary.AffectedAssets != 2 {
+		t.Fatalf("unexpected summary: %+v", r.Summary)
+	}
+	if r.Summary.RiskLevel != "critical" {
+		t.Errorf("RiskLevel = %s, want critical", r.Summary.RiskLevel)
+	}
+	if r.Assets[0].Authority != "b:443" || r.Assets[0].RiskLevel != "critical" || len(r.Assets[0].Findings) != 2 {
+		t.Errorf("most risky asset should come first, got %+v", r.Assets[0])
+	}
+	if r.Findings[0].Severity != "critical" || r.Findings[2].Severity != "unknown" {
+		t.Errorf("findings not sorted by severity: %s, %s", r.Findings[0].Severity, r.Findings[2].Severity)
+	}
+}
+
+func TestRenderers(t *testing.T) {
+	for _, format := range []string{"", "html", "pdf", "md", "json", "csv"} {
+		renderer, err := NewRenderer(format, "")
+		if err != nil {
+			t.Fatalf("NewRenderer(%q): %v", format, err)
+		}
+		data, err := renderer.Render(sampleReport())
+		if err != nil {
+			t.Fatalf("Render(%q): %v", format, err)
+		}
+		if len(data) == 0 {
+			t.Errorf("Render(%q) returned empty output", format)
+		}
+	}
+
+	if _, err := NewRenderer("docx", ""); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+	if err := ValidateTemplate("html", "{{.NoSuchField}}"); err == nil {
+		t.Error("expected error for invalid template field")
+	}
+	if err := ValidateTemplate("markdown", "# {{.Title}} {{.Summary.VulCount}}"); err != nil {
+		t.Errorf("valid template rejected: %v", err)
+	}
+}
+
+func TestCSVEscapesFields(t *testing.T) {
+	r := New(Meta{}, nil, []*Finding{{Host: "h", Result: "a,\"b\"\nc", Severity: "high"}})
+	data, err := (&CSVRenderer{}).Render(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"a,""b""`+"\nc\"") {
+		t.Errorf("csv field not quoted: %s", data)
+	}
+}
+
+func TestCSVEscapesFormulas(t *testing.T) {
+	r := New(Meta{}, nil, []*Finding{{
+		Host:        "h",
+		Url:         "=HYPERLINK(\"http://evil\",\"x\")",
+		Result:      "+cmd|' /C calc'!A0",
+		Remediation: "-1+1",
+		PocFile:     "@SUM(A1)",
+		CveId:       "\t=1",
+		CweId:       "\r=1",
+		Source:      "a=b",
+		Severity:    "high",
+	}})
+	data, err := (&CSVRenderer{}).Render(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := string(data)
+	for _, want := range []string{`"'=HYPERLINK(""http://evil"",""x"")"`, `'+cmd|' /C calc'!A0`, `'-1+1`, `'@SUM(A1)`, "'\t=1", "\"'\r=1\"", ",a=b,"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("csv missing %q: %s", want, out)
+		}
+	}
+	if strings.Contains(out, ",=") || strings.Contains(out, ",+") || strings.Contains(out, ",@") {
+		t.Errorf("csv contains unescaped formula: %s", out)
+	}
+}
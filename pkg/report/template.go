@@ -0,0 +1,125 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+)
+
+//go:embed templates/report.html
+var defaultHTMLTemplate string
+
+//go:embed templates/report.md
+var defaultMarkdownTemplate string
+
+// maxEvidenceLen 模板中请求/响应证据的最大长度
+const maxEvidenceLen = 8192
+
+// DefaultTemplate 返回内置模板内容
+func DefaultTemplate(format string) string {
+	switch NormalizeFormat(format) {
+	case FormatHTML, FormatPDF:
+		return defaultHTMLTemplate
+	case FormatMarkdown:
+		return defaultMarkdownTemplate
+	}
+	return ""
+}
+
+// templateFuncs 模板可用函数
+var templateFuncs = map[string]interface{}{
+	"severityText": func(s string) string {
+		if t, ok := severityText[s]; ok {
+			return t
+		}
+		return s
+	},
+	"join": strings.Join,
+	"percent": func(p float64) string {
+		return fmt.Sprintf("%.1f%%", p)
+	},
+	"truncate": func(s string) string {
+		if len(s) <= maxEvidenceLen {
+			return s
+		}
+		return strings.ToValidUTF8(s[:maxEvidenceLen], "") + "\n...(truncated)"
+	},
+	// cell 转义 Markdown 表格单元格
+	"cell": func(s string) string {
+		s = strings.ReplaceAll(s, "|", "\\|")
+		return strings.ReplaceAll(s, "\n", " ")
+	},
+}
+
+// templateView 模板渲染数据
+type templateView struct {
+	*Report
+	Printable bool // 是否为打印版（PDF）
+}
+
+// HTMLRenderer 基于 html/template 的渲染器
+type HTMLRenderer struct {
+	tpl       *htmltemplate.Template
+	printable bool
+}
+
+// NewHTMLRenderer 创建 HTML 渲染器，printable 为 true 时输出适合打印为 PDF 的版式
+func NewHTMLRenderer(content string, printable bool) (*HTMLRenderer, error) {
+	if strings.TrimSpace(content) == "" {
+		content = defaultHTMLTemplate
+	}
+	tpl, err := htmltemplate.New("report").Funcs(templateFuncs).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("模板解析失败: %v", err)
+	}
+	return &HTMLRenderer{tpl: tpl, printable: printable}, nil
+}
+
+func (h *HTMLRenderer) Render(r *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := h.tpl.Execute(&buf, templateView{Report: r, Printable: h.printable}); err != nil {
+		return nil, fmt.Errorf("模板渲染失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (h *HTMLRenderer) ContentType() string { return "text/html; charset=utf-8" }
+
+func (h *HTMLRenderer) Extension() string {
+	if h.printable {
+		return "pdf.html"
+	}
+	return "html"
+}
+
+// MarkdownRenderer 基于 text/template 的 Markdown 渲染器
+type MarkdownRenderer struct {
+	tpl *texttemplate.Template
+}
+
+// NewMarkdownRenderer 创建 Markdown 渲染器
+func NewMarkdownRenderer(content string) (*MarkdownRenderer, error) {
+	if strings.TrimSpace(content) == "" {
+		content = defaultMarkdownTemplate
+	}
+	tpl, err := texttemplate.New("report").Funcs(templateFuncs).Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("模板解析失败: %v", err)
+	}
+	return &MarkdownRenderer{tpl: tpl}, nil
+}
+
+func (m *MarkdownRenderer) Render(r *Report) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := m.tpl.Execute(&buf, templateView{Report: r}); err != nil {
+		return nil, fmt.Errorf("模板渲染失败: %v", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (m *MarkdownRenderer) ContentType() string { return "text/markdown; charset=utf-8" }
+
+func (m *MarkdownRenderer) Extension() string { return "md" }
@@ -0,0 +1,340 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"cscan/model"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+const (
+	sendTimeout   = 15 * time.Second // 单次投递超时
+	claimLease    = 2 * time.Minute  // 投递租约，超时未回写结果的记录会被重新领取
+	pollInterval  = 15 * time.Second // 扫描到期重试记录的间隔
+	cacheTTL      = 30 * time.Second // 订阅缓存时间，API 和 RPC 服务各自缓存
+	queueSize     = 1024
+	workerCount   = 4
+	maxBodyLength = 2048 // 保存的响应体最大长度
+
+	// MaxAttempts 最大投递次数，超过后标记为失败，可手动重放
+	MaxAttempts = 8
+	baseBackoff = 30 * time.Second
+	maxBackoff  = time.Hour
+)
+
+// Backoff 第 attempt 次投递失败后的重试间隔：30s、1m、2m ... 最长1小时
+func Backoff(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := baseBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= maxBackoff {
+			return maxBackoff
+		}
+	}
+	return d
+}
+
+// Emitter Webhook 事件发送器。
+// Emit 将事件按订阅写入投递记录后交给本地队列投递；失败的记录由 Start 启动的轮询按退避时间重试，
+// 领取记录时使用租约，多个服务实例同时轮询也不会重复投递
+type Emitter struct {
+	subModel      *model.WebhookSubscriptionModel
+	deliveryModel *model.WebhookDeliveryModel
+	client        *http.Client
+	queue         chan *model.WebhookDelivery
+
+	mu        sync.Mutex
+	subs      []model.WebhookSubscription
+	subsTime  time.Time
+	startOnce sync.Once
+}
+
+// NewEmitter 创建事件发送器
+func NewEmitter(subModel *model.WebhookSubscriptionModel, deliveryModel *model.WebhookDeliveryModel) *Emitter {
+	return &Emitter{
+		subModel:      subModel,
+		deliveryModel: deliveryModel,
+		client:        &http.Client{Timeout: sendTimeout},
+		queue:         make(chan *model.WebhookDelivery, queueSize),
+	}
+}
+
+// Start 启动投递协程和重试轮询
+func (e *Emitter) Start(ctx context.Context) {
+	if e == nil {
+		return
+	}
+	e.startOnce.Do(func() {
+		for i := 0; i < workerCount; i++ {
+			go e.work(ctx)
+		}
+		go e.poll(ctx)
+	})
+}
+
+// Emit 异步发送事件，不阻塞结果保存流程
+func (e *Emitter) Emit(events ...*Event) {
+	if e == nil || len(events) == 0 {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), sendTimeout)
+		defer cancel()
+		if err := e.enqueue(ctx, events); err != nil {
+			logx.Errorf("[Webhook] enqueue %d events failed: %v", len(events), err)
+		}
+	}()
+}
+
+// InvalidateCache 订阅变更后清除缓存
+func (e *Emitter) InvalidateCache() {
+	if e == nil {
+		return
+	}
+	e.mu.Lock()
+	e.subs = nil
+	e.subsTime = time.Time{}
+	e.mu.Unlock()
+}
+
+func (e *Emitter) subscriptions(ctx context.Context) ([]model.WebhookSubscription, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.subs != nil && time.Since(e.subsTime) < cacheTTL {
+		return e.subs, nil
+	}
+	subs, err := e.subModel.FindEnabled(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if subs == nil {
+		subs = []model.WebhookSubscription{}
+	}
+	e.subs = subs
+	e.subsTime = time.Now()
+	return subs, nil
+}
+
+// enqueue 为匹配的订阅生成投递记录并放入本地队列
+func (e *Emitter) enqueue(ctx context.Context, events []*Event) error {
+	subs, err := e.subscriptions(ctx)
+	if err != nil || len(subs) == 0 {
+		return err
+	}
+
+	lease := time.Now().Add(claimLease)
+	var deliveries []*model.WebhookDelivery
+	for _, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		for i := range subs {
+			if !subs[i].Match(event.Type, event.WorkspaceId) {
+				continue
+			}
+			deliveries = append(deliveries, &model.WebhookDelivery{
+				SubscriptionId:   subs[i].Id.Hex(),
+				SubscriptionName: subs[i].Name,
+				EventId:          event.Id,
+				Event:            event.Type,
+				WorkspaceId:      event.WorkspaceId,
+				Payload:          string(payload),
+				Status:           model.WebhookDeliveryPending,
+				NextRetryTime:    lease,
+			})
+		}
+	}
+	if err := e.deliveryModel.InsertMany(ctx, deliveries); err != nil {
+		return err
+	}
+
+	for _, d := range deliveries {
+		select {
+		case e.queue <- d:
+		default:
+			// 队列已满，交给轮询尽快处理
+			e.deliveryModel.UpdateResult(ctx, d.Id, bson.M{"next_retry_time": time.Now()})
+		}
+	}
+	return nil
+}
+
+func (e *Emitter) work(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-e.queue:
+			e.deliver(ctx, d)
+		}
+	}
+}
+
+func (e *Emitter) poll(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for {
+			d, err := e.deliveryModel.ClaimDue(ctx, claimLease)
+			if err != nil {
+				logx.Errorf("[Webhook] claim due deliveries failed: %v", err)
+				break
+			}
+			if d == nil {
+				break
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case e.queue <- d:
+			}
+		}
+	}
+}
+
+// deliver 投递一次并记录结果
+func (e *Emitter) deliver(ctx context.Context, d *model.WebhookDelivery) {
+	update := bson.M{"attempts": d.Attempts + 1}
+	d.Attempts++
+
+	sub, err := e.subModel.FindById(ctx, d.SubscriptionId)
+	if err != nil || (sub.Status != model.StatusEnable && d.Event != model.WebhookEventPing) {
+		d.Status = model.WebhookDeliveryFailed
+		d.LastError = "subscription deleted or disabled"
+		update["status"] = d.Status
+		update["last_error"] = d.LastError
+		e.deliveryModel.UpdateResult(ctx, d.Id, update)
+		return
+	}
+
+	start := time.Now()
+	code, body, err := e.post(ctx, sub, d)
+	d.Duration = time.Since(start).Milliseconds()
+	d.ResponseCode = code
+	d.ResponseBody = body
+	d.LastError = ""
+	switch {
+	case err == nil:
+		d.Status = model.WebhookDeliverySuccess
+	case d.Attempts >= MaxAttempts || d.Event == model.WebhookEventPing:
+		// 测试事件不重试
+		d.Status = model.WebhookDeliveryFailed
+		d.LastError = err.Error()
+	default:
+		d.Status = model.WebhookDeliveryRetrying
+		d.LastError = err.Error()
+		d.NextRetryTime = time.Now().Add(Backoff(d.Attempts))
+		update["next_retry_time"] = d.NextRetryTime
+	}
+	if d.Status != model.WebhookDeliverySuccess {
+		logx.Infof("[Webhook] deliver %s to %s failed (attempt %d): %s", d.Event, sub.Name, d.Attempts, d.LastError)
+	}
+
+	update["status"] = d.Status
+	update["duration"] = d.Duration
+	update["response_code"] = d.ResponseCode
+	update["response_body"] = d.ResponseBody
+	update["last_error"] = d.LastError
+	if err := e.deliveryModel.UpdateResult(ctx, d.Id, update); err != nil {
+		logx.Errorf("[Webhook] update delivery %s failed: %v", d.Id.Hex(), err)
+	}
+}
+
+// post 发送请求，2xx 视为成功
+func (e *Emitter) post(ctx context.Context, sub *model.WebhookSubscription, d *model.WebhookDelivery) (int, string, error) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Url, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	for k, v := range sub.Headers {
+		req.Header.Set(k, v)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set(HeaderEvent, d.Event)
+	req.Header.Set(HeaderDelivery, d.Id.Hex())
+	req.Header.Set(HeaderTimestamp, timestamp)
+	if sub.Secret != "" {
+		req.Header.Set(HeaderSignature, Sign(sub.Secret, timestamp, body))
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxBodyLength))
+	text := string(bytes.ToValidUTF8(respBody, nil))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, text, fmt.Errorf("http status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, text, nil
+}
+
+// Replay 重放投递记录：复制一条新的投递记录（事件ID不变）并立即同步投递
+func (e *Emitter) Replay(ctx context.Context, deliveryId string) (*model.WebhookDelivery, error) {
+	old, err := e.deliveryModel.FindById(ctx, deliveryId)
+	if err != nil {
+		return nil, err
+	}
+	d := &model.WebhookDelivery{
+		SubscriptionId:   old.SubscriptionId,
+		SubscriptionName: old.SubscriptionName,
+		EventId:          old.EventId,
+		Event:            old.Event,
+		WorkspaceId:      old.WorkspaceId,
+		Payload:          old.Payload,
+		Status:           model.WebhookDeliveryPending,
+		NextRetryTime:    time.Now().Add(claimLease),
+		ReplayOf:         old.Id.Hex(),
+	}
+	return d, e.sendNow(ctx, d)
+}
+
+// Test 向订阅发送 ping 事件并同步返回投递结果
+func (e *Emitter) Test(ctx context.Context, sub *model.WebhookSubscription) (*model.WebhookDelivery, error) {
+	event := NewEvent(model.WebhookEventPing, "", map[string]string{"subscription": sub.Name})
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return nil, err
+	}
+	d := &model.WebhookDelivery{
+		SubscriptionId:   sub.Id.Hex(),
+		SubscriptionName: sub.Name,
+		EventId:          event.Id,
+		Event:            event.Type,
+		Payload:          string(payload),
+		Status:           model.WebhookDeliveryPending,
+		NextRetryTime:    time.Now().Add(claimLease),
+	}
+	return d, e.sendNow(ctx, d)
+}
+
+func (e *Emitter) sendNow(ctx context.Context, d *model.WebhookDelivery) error {
+	if err := e.deliveryModel.InsertMany(ctx, []*model.WebhookDelivery{d}); err != nil {
+		return err
+	}
+	e.deliver(ctx, d)
+	return nil
+}
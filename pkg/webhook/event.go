@@ -0,0 +1,143 @@
+// Package webhook 提供扫描结果的出站 Webhook 事件流。
+// 事件先写入投递记录再异步投递，失败按指数退避重试，投递记录可查询和重放。
+package webhook
+
+import (
+	"time"
+
+	"cscan/model"
+	"cscan/pkg/assetdiff"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Event Webhook 事件，即请求体
+type Event struct {
+	Id          string      `json:"id"` // 事件ID，重试和重放时不变，接收方可据此去重
+	Type        string      `json:"type"`
+	WorkspaceId string      `json:"workspaceId,omitempty"`
+	Time        time.Time   `json:"time"`
+	Data        interface{} `json:"data"`
+}
+
+// NewEvent 创建事件
+func NewEvent(eventType, workspaceId string, data interface{}) *Event {
+	return &Event{
+		Id:          primitive.NewObjectID().Hex(),
+		Type:        eventType,
+		WorkspaceId: workspaceId,
+		Time:        time.Now(),
+		Data:        data,
+	}
+}
+
+// AssetData 资产事件数据
+type AssetData struct {
+	Id        string                  `json:"id"`
+	Authority string                  `json:"authority"`
+	Host      string                  `json:"host"`
+	Port      int                     `json:"port"`
+	Service   string                  `json:"service,omitempty"`
+	Title     string                  `json:"title,omitempty"`
+	App       []string                `json:"app,omitempty"`
+	Server    string                  `json:"server,omitempty"`
+	Source    string                  `json:"source,omitempty"`
+	TaskId    string                  `json:"taskId,omitempty"`
+	Changes   []assetdiff.FieldChange `json:"changes,omitempty"`
+}
+
+func assetData(a *model.Asset) *AssetData {
+	return &AssetData{
+		Id:        a.Id.Hex(),
+		Authority: a.Authority,
+		Host:      a.Host,
+		Port:      a.Port,
+		Service:   a.Service,
+		Title:     a.Title,
+		App:       a.App,
+		Server:    a.Server,
+		Source:    a.Source,
+		TaskId:    a.TaskId,
+	}
+}
+
+// AssetCreatedEvent 新资产事件
+func AssetCreatedEvent(workspaceId string, a *model.Asset) *Event {
+	return NewEvent(model.WebhookEventAssetCreated, workspaceId, assetData(a))
+}
+
+// AssetChangedEvent 资产变化事件，changes 为发生变化的字段
+func AssetChangedEvent(workspaceId string, a *model.Asset, changes []assetdiff.FieldChange) *Event {
+	data := assetData(a)
+	data.Changes = changes
+	return NewEvent(model.WebhookEventAssetChanged, workspaceId, data)
+}
+
+// VulData 漏洞事件数据，不包含请求响应等证据，接收方可通过 API 查询详情
+type VulData struct {
+	Id        string  `json:"id"`
+	Authority string  `json:"authority"`
+	Host      string  `json:"host"`
+	Port      int     `json:"port"`
+	Url       string  `json:"url"`
+	PocFile   string  `json:"pocFile"`
+	Source    string  `json:"source"`
+	Severity  string  `json:"severity"`
+	CveId     string  `json:"cveId,omitempty"`
+	CvssScore float64 `json:"cvssScore,omitempty"`
+	Result    string  `json:"result,omitempty"`
+	TaskId    string  `json:"taskId,omitempty"`
+}
+
+// VulCreatedEvent 新漏洞事件
+func VulCreatedEvent(workspaceId string, v *model.Vul) *Event {
+	return NewEvent(model.WebhookEventVulCreated, workspaceId, &VulData{
+		Id:        v.Id.Hex(),
+		Authority: v.Authority,
+		Host:      v.Host,
+		Port:      v.Port,
+		Url:       v.Url,
+		PocFile:   v.PocFile,
+		Source:    v.Source,
+		Severity:  v.Severity,
+		CveId:     v.CveId,
+		CvssScore: v.CvssScore,
+		Result:    v.Result,
+		TaskId:    v.TaskId,
+	})
+}
+
+// TaskData 任务事件数据
+type TaskData struct {
+	Id       string `json:"id"`
+	TaskId   string `json:"taskId"`
+	Name     string `json:"name"`
+	Status   string `json:"status"`
+	Progress int    `json:"progress"`
+	Result   string `json:"result,omitempty"`
+	IsCron   bool   `json:"isCron"`
+}
+
+// TaskStatusEvent 任务状态变化事件，status 为变化后的状态（task 可能是更新前读取的）
+func TaskStatusEvent(workspaceId string, task *model.MainTask, status, result string) *Event {
+	if result == "" {
+		result = task.Result
+	}
+	return NewEvent(model.WebhookEventTaskStatusChanged, workspaceId, &TaskData{
+		Id:       task.Id.Hex(),
+		TaskId:   task.TaskId,
+		Name:     task.Name,
+		Status:   status,
+		Progress: task.Progress,
+		Result:   result,
+		IsCron:   task.IsCron,
+	})
+}
+
+// WorkerOfflineEvent Worker 离线事件，不属于任何工作空间
+func WorkerOfflineEvent(name, reason string) *Event {
+	return NewEvent(model.WebhookEventWorkerOffline, "", map[string]string{
+		"name":   name,
+		"reason": reason,
+	})
+}
@@ -0,0 +1,42 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// 请求头
+const (
+	HeaderEvent     = "X-CScan-Event"
+	HeaderDelivery  = "X-CScan-Delivery"
+	HeaderTimestamp = "X-CScan-Timestamp"
+	HeaderSignature = "X-CScan-Signature"
+)
+
+// Sign 计算签名：sha256=HMAC-SHA256(secret, timestamp + "." + body)。
+// 签名包含时间戳，接收方可拒绝时间偏差过大的请求以防重放
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验签名，maxSkew 大于0时同时校验时间戳偏差
+func Verify(secret, timestamp string, body []byte, signature string, maxSkew time.Duration) bool {
+	if maxSkew > 0 {
+		ts, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return false
+		}
+		skew := time.Since(time.Unix(ts, 0))
+		if skew > maxSkew || skew < -maxSkew {
+			return false
+		}
+	}
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"cscan/model"
+)
+
+func TestSignVerify(t *testing.T) {
+	body := []byte(`{"type":"vul.created"}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	sig := Sign("secret", ts, body)
+
+	if !Verify("secret", ts, body, sig, time.Minute) {
+		t.Error("valid signature rejected")
+	}
+	if Verify("other", ts, body, sig, time.Minute) {
+		t.Error("signature with wrong secret accepted")
+	}
+	if Verify("secret", ts, []byte(`{"type":"asset.created"}`), sig, time.Minute) {
+		t.Error("signature of modified body accepted")
+	}
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	if Verify("secret", old, body, Sign("secret", old, body), time.Minute) {
+		t.Error("stale timestamp accepted")
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, time.Hour},
+		{20, time.Hour},
+	}
+	for _, tt := range tests {
+		if got := Backoff(tt.attempt); got != tt.want {
+			t.Errorf("Backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestSubscriptionMatch(t *testing.T) {
+	sub := &model.WebhookSubscription{
+		Events:       []string{model.WebhookEventVulCreated, model.WebhookEventWorkerOffline},
+		WorkspaceIds: []string{"ws1"},
+	}
+	tests := []struct {
+		event     string
+		workspace string
+		want      bool
+	}{
+		{model.WebhookEventVulCreated, "ws1", true},
+		{model.WebhookEventVulCreated, "ws2", false},
+		{model.WebhookEventAssetCreated, "ws1", false},
+		{model.WebhookEventWorkerOffline, "", true},
+	}
+	for _, tt := range tests {
+		if got := sub.Match(tt.event, tt.workspace); got != tt.want {
+			t.Errorf("Match(%s, %s) = %v, want %v", tt.event, tt.workspace, got, tt.want)
+		}
+	}
+	if !(&model.WebhookSubscription{}).Match(model.WebhookEventTaskStatusChanged, "ws2") {
+		t.Error("subscription without filters should match all events")
+	}
+}
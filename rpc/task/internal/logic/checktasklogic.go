@@ -2,13 +2,10 @@ package logic
 
 import (
 	"context"
-	"encoding/json"
-	"strings"
 	"time"
 
 	"cscan/rpc/task/internal/svc"
 	"cscan/rpc/task/pb"
-	"cscan/scheduler"
 
 	"github.com/zeromicro/go-zero/core/logx"
 	"go.mongodb.org/mongo-driver/bson"
@@ -29,17 +26,16 @@ func NewCheckTaskLogic(ctx context.Context, svcCtx *svc.ServiceContext) *CheckTa
 }
 
 // 检查任务状态 - 从Redis队列中获取待执行的任务
-// 优先从 Worker 专属队列获取任务，然后从公共队列获取
+// 优先从 Worker 专属队列获取任务，然后从能力队列获取，最后从公共队列获取
 func (l *CheckTaskLogic) CheckTask(in *pb.CheckTaskReq) (*pb.CheckTaskResp, error) {
 	workerName := in.TaskId // TaskId 实际上是 Worker 名称
 	l.Logger.Infof("CheckTask: received request from worker '%s'", workerName)
 	
 	publicQueueKey := "cscan:task:queue"
-	workerQueueKey := "cscan:task:queue:worker:" + strings.ToLower(workerName)
-	processingKey := "cscan:task:processing"
+	workerQueueKey := l.svcCtx.Scheduler.GetWorkerQueueKey(workerName)
 
 	// 1. 优先从 Worker 专属队列获取任务（使用 ZPopMin 原子操作）
-	task, err := l.popTaskFromQueue(workerQueueKey, processingKey, workerName)
+	task, err := l.popTaskFromQueue(workerQueueKey, workerName)
 	if err != nil {
 		l.Logger.Errorf("CheckTask: failed to pop from worker queue: %v", err)
 	}
@@ -47,8 +43,28 @@ func (l *CheckTaskLogic) CheckTask(in *pb.CheckTaskReq) (*pb.CheckTaskResp, erro
 		return task, nil
 	}
 
-	// 2. 从公共队列获取任务（使用 ZPopMin 原子操作）
-	task, err = l.popTaskFromQueue(publicQueueKey, processingKey, workerName)
+	// 2. 从 Worker 标签满足选择器的标签队列获取任务
+	labelQueueKeys, err := l.svcCtx.Scheduler.GetLabelQueueKeys(l.ctx, in.Labels)
+	if err != nil {
+		l.Logger.Errorf("CheckTask: failed to get label queues: %v", err)
+	}
+	for _, queueKey := range labelQueueKeys {
+		task, err = l.popTaskFromQueue(queueKey, workerName)
+		if err != nil {
+			l.Logger.Errorf("CheckTask: failed to pop from label queue %s: %v", queueKey, err)
+		}
+		if task != nil {
+			return task, nil
+		}
+	}
+
+	// 只接受标签匹配任务的 Worker（如内网 Worker）不从公共队列获取任务
+	if in.LabelOnly {
+		return &pb.CheckTaskResp{IsExist: false}, nil
+	}
+
+	// 3. 从公共队列获取任务（使用 ZPopMin 原子操作）
+	task, err = l.popTaskFromQueue(publicQueueKey, workerName)
 	if err != nil {
 		l.Logger.Errorf("CheckTask: failed to pop from public queue: %v", err)
 	}
@@ -59,27 +75,17 @@ func (l *CheckTaskLogic) CheckTask(in *pb.CheckTaskReq) (*pb.CheckTaskResp, erro
 	return &pb.CheckTaskResp{IsExist: false}, nil
 }
 
-// popTaskFromQueue 从指定队列原子获取一个任务
-func (l *CheckTaskLogic) popTaskFromQueue(queueKey, processingKey, workerName string) (*pb.CheckTaskResp, error) {
-	// 使用 ZPopMin 原子获取优先级最高的任务
-	results, err := l.svcCtx.RedisClient.ZPopMin(l.ctx, queueKey, 1).Result()
+// popTaskFromQueue 从指定队列原子获取一个任务，并为其创建租约
+// 租约由 Worker 心跳续约，过期未续约的任务会被调度器回收重新入队
+func (l *CheckTaskLogic) popTaskFromQueue(queueKey, workerName string) (*pb.CheckTaskResp, error) {
+	task, err := l.svcCtx.Scheduler.PopTaskFromQueue(l.ctx, queueKey, workerName)
 	if err != nil {
 		return nil, err
 	}
-	if len(results) == 0 {
-		return nil, nil
-	}
-
-	taskData := results[0].Member.(string)
-	var task scheduler.TaskInfo
-	if err := json.Unmarshal([]byte(taskData), &task); err != nil {
-		l.Logger.Errorf("CheckTask: failed to parse task: %v", err)
+	if task == nil {
 		return nil, nil
 	}
 
-	// 添加到处理中集合
-	l.svcCtx.RedisClient.SAdd(l.ctx, processingKey, task.TaskId)
-
 	l.Logger.Infof("CheckTask: assigned task %s to worker %s from queue %s", task.TaskId, workerName, queueKey)
 
 	// 立即更新主任务状态为 STARTED
@@ -0,0 +1,48 @@
+package logic
+
+import (
+	"context"
+	"time"
+
+	"cscan/model"
+	"cscan/pkg/assetdiff"
+	"cscan/rpc/task/internal/svc"
+
+	"github.com/zeromicro/go-zero/core/logx"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// attachCronDiff 定时任务执行完成后，对比本轮扫描结果与执行前的资产状态，并把摘要保存到任务上
+func attachCronDiff(ctx context.Context, svcCtx *svc.ServiceContext, workspaceId string, task *model.MainTask) {
+	if !task.IsCron || task.StartTime == nil {
+		return
+	}
+	endTime := time.Now()
+	if task.EndTime != nil {
+		endTime = *task.EndTime
+	}
+
+	builder := assetdiff.NewBuilder(
+		svcCtx.GetAssetModel(workspaceId),
+		svcCtx.GetAssetHistoryModel(workspaceId),
+		svcCtx.GetVulModel(workspaceId),
+	)
+	base, err := builder.At(ctx, *task.StartTime)
+	if err != nil {
+		logx.Errorf("attachCronDiff: build base snapshot failed, task=%s, error=%v", task.Id.Hex(), err)
+		return
+	}
+	target, err := builder.Between(ctx, *task.StartTime, endTime)
+	if err != nil {
+		logx.Errorf("attachCronDiff: build target snapshot failed, task=%s, error=%v", task.Id.Hex(), err)
+		return
+	}
+
+	summary := assetdiff.Compare(base, target).Summary()
+	if err := svcCtx.GetMainTaskModel(workspaceId).Update(ctx, task.Id.Hex(), bson.M{"diff_summary": summary}); err != nil {
+		logx.Errorf("attachCronDiff: save diff summary failed, task=%s, error=%v", task.Id.Hex(), err)
+		return
+	}
+	task.DiffSummary = summary
+	logx.Infof("attachCronDiff: task=%s, %s", task.Id.Hex(), assetdiff.SummaryText(summary))
+}
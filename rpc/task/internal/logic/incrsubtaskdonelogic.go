@@ -4,6 +4,9 @@ import (
 	"context"
 	"time"
 
+	"cscan/model"
+	"cscan/pkg/notify"
+	"cscan/pkg/webhook"
 	"cscan/rpc/task/internal/svc"
 	"cscan/rpc/task/pb"
 
@@ -75,14 +78,22 @@ func (l *IncrSubTaskDoneLogic) IncrSubTaskDone(in *pb.IncrSubTaskDoneReq) (*pb.I
 	}
 
 	// 如果全部完成，更新状态
+	now := time.Now()
 	if allDone {
 		update["status"] = "SUCCESS"
 		update["progress"] = 100
-		update["end_time"] = time.Now()
+		update["end_time"] = now
 	}
 
 	if err := taskModel.Update(l.ctx, in.MainTaskId, update); err != nil {
 		l.Logger.Errorf("IncrSubTaskDone: failed to update progress, mainTaskId=%s, error=%v", in.MainTaskId, err)
+	} else if allDone {
+		task.Status = "SUCCESS"
+		task.EndTime = &now
+		attachCronDiff(l.ctx, l.svcCtx, in.WorkspaceId, task)
+		l.svcCtx.Webhook.Emit(webhook.TaskStatusEvent(in.WorkspaceId, task, task.Status, ""))
+		l.svcCtx.Notifier.DispatchOnce(l.ctx, notify.TaskOnceKey(model.NotifyEventTaskComplete, task), task.NotifyId,
+			notify.TaskMessage(task, model.NotifyEventTaskComplete))
 	}
 
 	return &pb.IncrSubTaskDoneResp{
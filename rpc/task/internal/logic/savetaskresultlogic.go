@@ -2,10 +2,14 @@ package logic
 
 import (
 	"context"
+	"encoding/json"
+	"regexp"
 	"time"
 
 	"cscan/model"
+	"cscan/pkg/assetdiff"
 	"cscan/pkg/utils"
+	"cscan/pkg/webhook"
 	"cscan/rpc/task/internal/svc"
 	"cscan/rpc/task/pb"
 
@@ -45,29 +49,39 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 
 	var totalAsset, newAsset, updateAsset int32
 	now := time.Now()
+	runStart := l.getRunStartTime(workspaceId, in.MainTaskId)
+	var events []*webhook.Event
 
 	for _, pbAsset := range in.Assets {
 		// 转换为model.Asset
 		asset := &model.Asset{
-			Authority:     pbAsset.Authority,
-			Host:          pbAsset.Host,
-			Port:          int(pbAsset.Port),
-			Category:      pbAsset.Category,
-			Service:       pbAsset.Service,
-			Title:         pbAsset.Title,
-			App:           pbAsset.App,
-			HttpStatus:    pbAsset.HttpStatus,
-			HttpHeader:    pbAsset.HttpHeader,
-			HttpBody:      pbAsset.HttpBody,
-			IconHash:      pbAsset.IconHash,
-			IconHashBytes: pbAsset.IconData,
-			Screenshot:    pbAsset.Screenshot,
-			Server:        pbAsset.Server,
-			Banner:        pbAsset.Banner,
-			IsHTTP:        pbAsset.IsHttp,
-			TaskId:        in.MainTaskId,
-			Source:        pbAsset.Source,
-			OrgId:         in.OrgId,
+			Authority:      pbAsset.Authority,
+			Host:           pbAsset.Host,
+			Port:           int(pbAsset.Port),
+			Category:       pbAsset.Category,
+			Service:        pbAsset.Service,
+			Title:          pbAsset.Title,
+			App:            pbAsset.App,
+			HttpStatus:     pbAsset.HttpStatus,
+			HttpHeader:     pbAsset.HttpHeader,
+			HttpBody:       pbAsset.HttpBody,
+			IconHash:       pbAsset.IconHash,
+			IconHashBytes:  pbAsset.IconData,
+			Screenshot:     pbAsset.Screenshot,
+			ScreenshotHash: pbAsset.ScreenshotHash,
+			Server:         pbAsset.Server,
+			Banner:         pbAsset.Banner,
+			Cert:           pbAsset.Cert,
+			IsHTTP:         pbAsset.IsHttp,
+			IsCDN:          pbAsset.IsCdn,
+			IsCloud:        pbAsset.IsCloud,
+			CDNName:        pbAsset.CdnName,
+			WAF:            pbAsset.Waf,
+			CloudName:      pbAsset.CloudName,
+			Transport:      pbAsset.Transport,
+			TaskId:         in.MainTaskId,
+			Source:         pbAsset.Source,
+			OrgId:          in.OrgId,
 		}
 
 		// 如果Source为空，设置默认值
@@ -80,7 +94,14 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 			for _, ip := range pbAsset.Ipv4 {
 				asset.Ip.IpV4 = append(asset.Ip.IpV4, model.IPV4{
 					IPName:   ip.Ip,
+					IPInt:    ip.IpInt,
 					Location: ip.Location,
+					Country:  ip.Country,
+					Region:   ip.Region,
+					City:     ip.City,
+					ASN:      int(ip.Asn),
+					Org:      ip.Org,
+					ISP:      ip.Isp,
 				})
 			}
 		}
@@ -89,10 +110,24 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 				asset.Ip.IpV6 = append(asset.Ip.IpV6, model.IPV6{
 					IPName:   ip.Ip,
 					Location: ip.Location,
+					Country:  ip.Country,
+					Region:   ip.Region,
+					City:     ip.City,
+					ASN:      int(ip.Asn),
+					Org:      ip.Org,
+					ISP:      ip.Isp,
 				})
 			}
 		}
 
+		// 处理TLS信息
+		if len(pbAsset.TlsInfo) > 0 {
+			var tlsInfo model.TLSInfo
+			if err := json.Unmarshal(pbAsset.TlsInfo, &tlsInfo); err == nil {
+				asset.TLS = &tlsInfo
+			}
+		}
+
 		// 处理CName
 		if pbAsset.Cname != "" {
 			asset.CName = pbAsset.Cname
@@ -108,8 +143,8 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 		var err error
 
 		if asset.Port > 0 {
-			// 有端口的资产，按host:port查找
-			existing, err = assetModel.FindByHostPort(l.ctx, asset.Host, asset.Port)
+			// 有端口的资产，按host:port查找，UDP 与 TCP 同端口为不同资产
+			existing, err = assetModel.FindByHostPortTransport(l.ctx, asset.Host, asset.Port, asset.Transport)
 		} else {
 			// 无端口的资产（如域名），按authority查找（不限制taskId）
 			existing, err = assetModel.FindByAuthorityOnly(l.ctx, asset.Authority)
@@ -129,17 +164,23 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 				continue
 			}
 			newAsset++
+			events = append(events, webhook.AssetCreatedEvent(workspaceId, asset))
 		} else {
 			// 更新已存在的资产
 			// 判断是否是不同任务的更新
 			isDifferentTask := existing.TaskId != "" && existing.TaskId != in.MainTaskId
+			// 同一任务再次执行（如定时任务），资产在本轮开始前更新过也视为新一轮扫描
+			isRerun := !isDifferentTask && runStart != nil && existing.UpdateTime.Before(*runStart)
 			
-			// 只有当任务ID不同时才保存历史记录（表示是新一轮扫描，需要记录上一次的状态）
-			if isDifferentTask {
+			// 只有新一轮扫描时才保存历史记录（需要记录上一次的状态）
+			if isDifferentTask || isRerun {
 				historyModel := l.svcCtx.GetAssetHistoryModel(workspaceId)
 				
-				// 检查是否已存在同一任务的历史记录（避免重复）
-				exists, _ := historyModel.ExistsByAssetIdAndTaskId(l.ctx, existing.Id.Hex(), existing.TaskId)
+				// 检查是否已存在同一任务的历史记录（避免重复），重复执行的任务每轮只会进入一次
+				exists := false
+				if isDifferentTask {
+					exists, _ = historyModel.ExistsByAssetIdAndTaskId(l.ctx, existing.Id.Hex(), existing.TaskId)
+				}
 				if !exists {
 					// 保存上一次扫描的状态作为历史记录
 					history := &model.AssetHistory{
@@ -156,6 +197,7 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 						IconHash:   existing.IconHash,
 						Screenshot: existing.Screenshot,
 						Banner:     existing.Banner,
+						Cert:       existing.Cert,
 						TaskId:     existing.TaskId, // 使用旧的任务ID
 						CreateTime: existing.UpdateTime, // 使用旧的更新时间
 					}
@@ -168,20 +210,27 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 
 			// 更新资产
 			updateFields := map[string]interface{}{
-				"authority":   asset.Authority,
-				"service":     asset.Service,
-				"title":       asset.Title,
-				"app":         asset.App,
-				"status":      asset.HttpStatus,
-				"header":      asset.HttpHeader,
-				"body":        asset.HttpBody,
-				"icon_hash":   asset.IconHash,
-				"screenshot":  asset.Screenshot,
-				"server":      asset.Server,
-				"banner":      asset.Banner,
-				"is_http":     asset.IsHTTP,
-				"taskId":      asset.TaskId,
-				"update_time": now,
+				"authority":       asset.Authority,
+				"service":         asset.Service,
+				"title":           asset.Title,
+				"app":             asset.App,
+				"status":          asset.HttpStatus,
+				"header":          asset.HttpHeader,
+				"body":            asset.HttpBody,
+				"icon_hash":       asset.IconHash,
+				"screenshot":      asset.Screenshot,
+				"screenshot_hash": asset.ScreenshotHash,
+				"server":          asset.Server,
+				"banner":          asset.Banner,
+				"is_http":         asset.IsHTTP,
+				"taskId":          asset.TaskId,
+				"update_time":     now,
+			}
+			if asset.Cert != "" {
+				updateFields["cert"] = asset.Cert
+			}
+			if asset.TLS != nil {
+				updateFields["tls"] = asset.TLS
 			}
 			
 			// 只有不同任务更新时才设置更新标签
@@ -208,6 +257,15 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 				updateFields["cname"] = asset.CName
 			}
 
+			// 更新CDN/WAF/云厂商，只在识别到时覆盖
+			if asset.CDNName != "" || asset.WAF != "" || asset.CloudName != "" {
+				updateFields["cdn"] = asset.IsCDN
+				updateFields["cloud"] = asset.IsCloud
+				updateFields["cdn_name"] = asset.CDNName
+				updateFields["waf"] = asset.WAF
+				updateFields["cloud_name"] = asset.CloudName
+			}
+
 			// 更新Domain
 			if asset.Domain != "" {
 				updateFields["domain"] = asset.Domain
@@ -232,11 +290,16 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 				l.Logger.Errorf("Update asset failed: %v", err)
 				continue
 			}
+			if changes := assetdiff.AssetChanges(existing, asset); len(changes) > 0 {
+				asset.Id = existing.Id
+				events = append(events, webhook.AssetChangedEvent(workspaceId, asset, changes))
+			}
 		}
 		totalAsset++
 	}
 
 	l.Logger.Infof("SaveTaskResult: total=%d, new=%d, update=%d", totalAsset, newAsset, updateAsset)
+	l.svcCtx.Webhook.Emit(events...)
 
 	return &pb.SaveTaskResultResp{
 		Success:     true,
@@ -246,3 +309,20 @@ func (l *SaveTaskResultLogic) SaveTaskResult(in *pb.SaveTaskResultReq) (*pb.Save
 		UpdateAsset: updateAsset,
 	}, nil
 }
+
+// subTaskSuffix 子任务ID后缀
+var subTaskSuffix = regexp.MustCompile(`-\d{1,6}$`)
+
+// getRunStartTime 获取主任务本轮执行的开始时间，子任务ID格式为 {mainTaskId}-{index}
+func (l *SaveTaskResultLogic) getRunStartTime(workspaceId, mainTaskId string) *time.Time {
+	mainTaskId = subTaskSuffix.ReplaceAllString(mainTaskId, "")
+	taskModel := l.svcCtx.GetMainTaskModel(workspaceId)
+	task, err := taskModel.FindById(l.ctx, mainTaskId)
+	if err != nil {
+		// 兼容：UUID格式的任务ID
+		if task, err = taskModel.FindByTaskId(l.ctx, mainTaskId); err != nil {
+			return nil
+		}
+	}
+	return task.StartTime
+}
@@ -2,8 +2,11 @@ package logic
 
 import (
 	"context"
+	"time"
 
 	"cscan/model"
+	"cscan/pkg/notify"
+	"cscan/pkg/webhook"
 	"cscan/rpc/task/internal/svc"
 	"cscan/rpc/task/pb"
 
@@ -40,7 +43,17 @@ func (l *SaveVulResultLogic) SaveVulResult(in *pb.SaveVulResultReq) (*pb.SaveVul
 	}
 
 	vulModel := l.svcCtx.GetVulModel(workspaceId)
-	var savedCount int32
+	var savedCount, suppressedCount int32
+	var notifyVuls []*model.Vul
+	var events []*webhook.Event
+
+	// 误报抑制规则
+	suppressModel := l.svcCtx.GetVulSuppressRuleModel(workspaceId)
+	rules, err := suppressModel.FindEnabled(l.ctx)
+	if err != nil {
+		l.Logger.Errorf("SaveVulResult: failed to load suppress rules: %v", err)
+	}
+	ruleHits := make(map[int]int)
 
 	for _, pbVul := range in.Vuls {
 		vul := &model.Vul{
@@ -92,16 +105,54 @@ func (l *SaveVulResultLogic) SaveVulResult(in *pb.SaveVulResultReq) (*pb.SaveVul
 		if pbVul.ResponseTruncated != nil {
 			vul.ResponseTruncated = *pbVul.ResponseTruncated
 		}
+		if i := pbVul.Interaction; i != nil {
+			vul.Interaction = &model.VulInteraction{
+				Protocol:      i.Protocol,
+				UniqueId:      i.UniqueId,
+				FullId:        i.FullId,
+				QType:         i.QType,
+				RawRequest:    i.RawRequest,
+				RawResponse:   i.RawResponse,
+				RemoteAddress: i.RemoteAddress,
+				SMTPFrom:      i.SmtpFrom,
+				Timestamp:     time.UnixMilli(i.Timestamp),
+			}
+		}
+
+		// 命中抑制规则的已知误报不再入库
+		if idx := matchSuppressRule(rules, vul); idx >= 0 {
+			ruleHits[idx]++
+			suppressedCount++
+			continue
+		}
 
 		// 使用Upsert避免重复
-		if err := vulModel.Upsert(l.ctx, vul); err != nil {
+		isNew, reopened, err := vulModel.Upsert(l.ctx, vul)
+		if err != nil {
 			l.Logger.Errorf("SaveVulResult: failed to upsert vul: %v", err)
 			continue
 		}
 		savedCount++
+		if isNew {
+			events = append(events, webhook.VulCreatedEvent(workspaceId, vul))
+		}
+
+		// 新发现或重新打开的严重/高危漏洞发送通知
+		if (isNew || reopened) && notify.IsNotifySeverity(vul.Severity) {
+			notifyVuls = append(notifyVuls, vul)
+		}
 	}
 
-	l.Logger.Infof("SaveVulResult: saved %d vulnerabilities", savedCount)
+	for idx, count := range ruleHits {
+		suppressModel.IncrHit(l.ctx, rules[idx].Id, count)
+	}
+
+	l.Logger.Infof("SaveVulResult: saved %d vulnerabilities, suppressed %d", savedCount, suppressedCount)
+
+	if len(notifyVuls) > 0 {
+		l.svcCtx.Notifier.Dispatch("", notify.VulMessage(workspaceId, notifyVuls))
+	}
+	l.svcCtx.Webhook.Emit(events...)
 
 	return &pb.SaveVulResultResp{
 		Success: true,
@@ -109,3 +160,13 @@ func (l *SaveVulResultLogic) SaveVulResult(in *pb.SaveVulResultReq) (*pb.SaveVul
 		Total:   savedCount,
 	}, nil
 }
+
+// matchSuppressRule 返回命中的抑制规则下标，未命中返回 -1
+func matchSuppressRule(rules []model.VulSuppressRule, vul *model.Vul) int {
+	for i := range rules {
+		if rules[i].Match(vul) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,35 @@
+package logic
+
+import (
+	"testing"
+
+	"cscan/model"
+)
+
+// TestMatchSuppressRule 测试返回第一条命中的抑制规则
+func TestMatchSuppressRule(t *testing.T) {
+	rules := []model.VulSuppressRule{
+		{PocFile: "weak-login.yaml", Host: "10.0.0.2"},
+		{PocFile: "weak-login.yaml", Pattern: `/admin/`},
+		{PocFile: "weak-login.yaml", Host: "10.0.0.1"},
+		{PocFile: "CVE-2021-44228", Host: "10.0.0.1"},
+	}
+	tests := []struct {
+		name string
+		vul  *model.Vul
+		want int
+	}{
+		{"pattern before host", &model.Vul{Host: "10.0.0.1", Url: "http://10.0.0.1/admin/", PocFile: "weak-login.yaml"}, 1},
+		{"host", &model.Vul{Host: "10.0.0.1", Url: "http://10.0.0.1/", PocFile: "weak-login.yaml"}, 2},
+		{"other template", &model.Vul{Host: "10.0.0.1", PocFile: "CVE-2021-44228"}, 3},
+		{"no match", &model.Vul{Host: "10.0.0.3", Url: "http://10.0.0.3/", PocFile: "weak-login.yaml"}, -1},
+	}
+	for _, tt := range tests {
+		if got := matchSuppressRule(rules, tt.vul); got != tt.want {
+			t.Errorf("%s: matchSuppressRule() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+	if got := matchSuppressRule(nil, tests[0].vul); got != -1 {
+		t.Errorf("matchSuppressRule(nil) = %d, want -1", got)
+	}
+}
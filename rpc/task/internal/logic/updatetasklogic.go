@@ -5,6 +5,9 @@ import (
 	"encoding/json"
 	"time"
 
+	"cscan/model"
+	"cscan/pkg/notify"
+	"cscan/pkg/webhook"
 	"cscan/rpc/task/internal/svc"
 	"cscan/rpc/task/pb"
 	"cscan/scheduler"
@@ -34,9 +37,17 @@ func (l *UpdateTaskLogic) UpdateTask(in *pb.UpdateTaskReq) (*pb.UpdateTaskResp,
 
 	l.Logger.Infof("UpdateTask: taskId=%s, state=%s", taskId, state)
 
-	// 从处理中集合移除
-	processingKey := "cscan:task:processing"
-	l.svcCtx.RedisClient.SRem(l.ctx, processingKey, taskId)
+	// 任务结束或暂停时释放租约，其余更新（开始、进度）视为续约
+	switch state {
+	case "SUCCESS", "FAILURE", "COMPLETED", "STOPPED", "PAUSED":
+		if err := l.svcCtx.Scheduler.CompleteTask(l.ctx, taskId); err != nil {
+			l.Logger.Errorf("UpdateTask: failed to release lease, taskId=%s, error=%v", taskId, err)
+		}
+	default:
+		if _, err := l.svcCtx.Scheduler.RenewLeases(l.ctx, []string{taskId}); err != nil {
+			l.Logger.Errorf("UpdateTask: failed to renew lease, taskId=%s, error=%v", taskId, err)
+		}
+	}
 
 	// 更新任务状态到Redis
 	statusKey := "cscan:task:status:" + taskId
@@ -157,6 +168,30 @@ func (l *UpdateTaskLogic) updateTaskInDB(taskId, state, result string) {
 			l.Logger.Errorf("UpdateTask: failed to update task in DB, mainTaskId=%s, error=%v", mainTaskId, err)
 		} else {
 			l.Logger.Infof("UpdateTask: task updated in DB, mainTaskId=%s, state=%s", mainTaskId, state)
+			l.notifyTaskStatus(taskModel, workspaceId, mainTaskId, state)
 		}
 	}
 }
+
+// notifyTaskStatus 任务状态变化时发送 Webhook 事件，完成或失败时发送通知
+func (l *UpdateTaskLogic) notifyTaskStatus(taskModel *model.MainTaskModel, workspaceId, mainTaskId, state string) {
+	task, err := taskModel.FindById(l.ctx, mainTaskId)
+	if err != nil {
+		return
+	}
+	l.svcCtx.Webhook.Emit(webhook.TaskStatusEvent(workspaceId, task, state, ""))
+
+	var event string
+	switch state {
+	case "SUCCESS", "COMPLETED":
+		event = model.NotifyEventTaskComplete
+	case "FAILURE":
+		event = model.NotifyEventTaskFailed
+	default:
+		return
+	}
+	if event == model.NotifyEventTaskComplete {
+		attachCronDiff(l.ctx, l.svcCtx, workspaceId, task)
+	}
+	l.svcCtx.Notifier.DispatchOnce(l.ctx, notify.TaskOnceKey(event, task), task.NotifyId, notify.TaskMessage(task, event))
+}
@@ -6,7 +6,10 @@ import (
 	"time"
 
 	"cscan/model"
+	"cscan/pkg/notify"
+	"cscan/pkg/webhook"
 	"cscan/rpc/task/internal/config"
+	"cscan/scheduler"
 
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/mongo"
@@ -24,6 +27,10 @@ type ServiceContext struct {
 	HttpServiceMappingModel *model.HttpServiceMappingModel
 	WorkspaceModel          *model.WorkspaceModel
 	SubfinderProviderModel  *model.SubfinderProviderModel
+	Scheduler               *scheduler.Scheduler
+	NotifyChannelModel      *model.NotifyChannelModel
+	Notifier                *notify.Dispatcher
+	Webhook                 *webhook.Emitter
 }
 
 func NewServiceContext(c config.Config) *ServiceContext {
@@ -58,6 +65,8 @@ func NewServiceContext(c config.Config) *ServiceContext {
 	}
 	fmt.Println("Redis connected successfully")
 
+	notifyChannelModel := model.NewNotifyChannelModel(mongoDB)
+
 	return &ServiceContext{
 		Config:                  c,
 		MongoClient:             mongoClient,
@@ -69,6 +78,10 @@ func NewServiceContext(c config.Config) *ServiceContext {
 		HttpServiceMappingModel: model.NewHttpServiceMappingModel(mongoDB),
 		WorkspaceModel:          model.NewWorkspaceModel(mongoDB),
 		SubfinderProviderModel:  model.NewSubfinderProviderModel(mongoDB),
+		Scheduler:               scheduler.NewScheduler(rdb),
+		NotifyChannelModel:      notifyChannelModel,
+		Notifier:                notify.NewDispatcher(notifyChannelModel, rdb),
+		Webhook:                 webhook.NewEmitter(model.NewWebhookSubscriptionModel(mongoDB), model.NewWebhookDeliveryModel(mongoDB)),
 	}
 }
 
@@ -93,6 +106,13 @@ func (s *ServiceContext) GetVulModel(workspaceId string) *model.VulModel {
 	return model.NewVulModel(s.MongoDB, workspaceId)
 }
 
+func (s *ServiceContext) GetVulSuppressRuleModel(workspaceId string) *model.VulSuppressRuleModel {
+	if workspaceId == "" {
+		workspaceId = "default"
+	}
+	return model.NewVulSuppressRuleModel(s.MongoDB, workspaceId)
+}
+
 func (s *ServiceContext) GetExecutorTaskModel(workspaceId string) *model.ExecutorTaskModel {
 	if workspaceId == "" {
 		workspaceId = "default"
@@ -25,6 +25,8 @@ type CheckTaskReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	TaskId        string                 `protobuf:"bytes,1,opt,name=taskId,proto3" json:"taskId,omitempty"`
 	MainTaskId    string                 `protobuf:"bytes,2,opt,name=mainTaskId,proto3" json:"mainTaskId,omitempty"`
+	Labels        []string               `protobuf:"bytes,3,rep,name=labels,proto3" json:"labels,omitempty"`        // Worker 标签（key=value），用于从标签队列获取任务
+	LabelOnly     bool                   `protobuf:"varint,4,opt,name=labelOnly,proto3" json:"labelOnly,omitempty"` // 只获取标签选择器匹配的任务，不从公共队列获取
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -73,6 +75,20 @@ func (x *CheckTaskReq) GetMainTaskId() string {
 	return ""
 }
 
+func (x *CheckTaskReq) GetLabels() []string {
+	if x != nil {
+		return x.Labels
+	}
+	return nil
+}
+
+func (x *CheckTaskReq) GetLabelOnly() bool {
+	if x != nil {
+		return x.LabelOnly
+	}
+	return false
+}
+
 type CheckTaskResp struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	IsExist       bool                   `protobuf:"varint,1,opt,name=isExist,proto3" json:"isExist,omitempty"`
@@ -430,32 +446,38 @@ func (x *NewTaskResp) GetMessage() string {
 }
 
 type AssetDocument struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Authority     string                 `protobuf:"bytes,1,opt,name=authority,proto3" json:"authority,omitempty"`
-	Host          string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
-	Port          int32                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
-	Category      string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
-	Service       string                 `protobuf:"bytes,5,opt,name=service,proto3" json:"service,omitempty"`
-	Server        string                 `protobuf:"bytes,6,opt,name=server,proto3" json:"server,omitempty"`
-	Banner        string                 `protobuf:"bytes,7,opt,name=banner,proto3" json:"banner,omitempty"`
-	Title         string                 `protobuf:"bytes,8,opt,name=title,proto3" json:"title,omitempty"`
-	App           []string               `protobuf:"bytes,9,rep,name=app,proto3" json:"app,omitempty"`
-	HttpStatus    string                 `protobuf:"bytes,10,opt,name=httpStatus,proto3" json:"httpStatus,omitempty"`
-	HttpHeader    string                 `protobuf:"bytes,11,opt,name=httpHeader,proto3" json:"httpHeader,omitempty"`
-	HttpBody      string                 `protobuf:"bytes,12,opt,name=httpBody,proto3" json:"httpBody,omitempty"`
-	Cert          string                 `protobuf:"bytes,13,opt,name=cert,proto3" json:"cert,omitempty"`
-	IconHash      string                 `protobuf:"bytes,14,opt,name=iconHash,proto3" json:"iconHash,omitempty"`
-	IsCdn         bool                   `protobuf:"varint,15,opt,name=isCdn,proto3" json:"isCdn,omitempty"`
-	Cname         string                 `protobuf:"bytes,16,opt,name=cname,proto3" json:"cname,omitempty"`
-	IsCloud       bool                   `protobuf:"varint,17,opt,name=isCloud,proto3" json:"isCloud,omitempty"`
-	Ipv4          []*IPV4                `protobuf:"bytes,18,rep,name=ipv4,proto3" json:"ipv4,omitempty"`
-	Ipv6          []*IPV6                `protobuf:"bytes,19,rep,name=ipv6,proto3" json:"ipv6,omitempty"`
-	Screenshot    string                 `protobuf:"bytes,20,opt,name=screenshot,proto3" json:"screenshot,omitempty"`
-	IsHttp        bool                   `protobuf:"varint,21,opt,name=isHttp,proto3" json:"isHttp,omitempty"`
-	Source        string                 `protobuf:"bytes,22,opt,name=source,proto3" json:"source,omitempty"`     // 资产来源: subfinder, portscan, etc.
-	IconData      []byte                 `protobuf:"bytes,23,opt,name=iconData,proto3" json:"iconData,omitempty"` // favicon 图片原始数据
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Authority      string                 `protobuf:"bytes,1,opt,name=authority,proto3" json:"authority,omitempty"`
+	Host           string                 `protobuf:"bytes,2,opt,name=host,proto3" json:"host,omitempty"`
+	Port           int32                  `protobuf:"varint,3,opt,name=port,proto3" json:"port,omitempty"`
+	Category       string                 `protobuf:"bytes,4,opt,name=category,proto3" json:"category,omitempty"`
+	Service        string                 `protobuf:"bytes,5,opt,name=service,proto3" json:"service,omitempty"`
+	Server         string                 `protobuf:"bytes,6,opt,name=server,proto3" json:"server,omitempty"`
+	Banner         string                 `protobuf:"bytes,7,opt,name=banner,proto3" json:"banner,omitempty"`
+	Title          string                 `protobuf:"bytes,8,opt,name=title,proto3" json:"title,omitempty"`
+	App            []string               `protobuf:"bytes,9,rep,name=app,proto3" json:"app,omitempty"`
+	HttpStatus     string                 `protobuf:"bytes,10,opt,name=httpStatus,proto3" json:"httpStatus,omitempty"`
+	HttpHeader     string                 `protobuf:"bytes,11,opt,name=httpHeader,proto3" json:"httpHeader,omitempty"`
+	HttpBody       string                 `protobuf:"bytes,12,opt,name=httpBody,proto3" json:"httpBody,omitempty"`
+	Cert           string                 `protobuf:"bytes,13,opt,name=cert,proto3" json:"cert,omitempty"`
+	IconHash       string                 `protobuf:"bytes,14,opt,name=iconHash,proto3" json:"iconHash,omitempty"`
+	IsCdn          bool                   `protobuf:"varint,15,opt,name=isCdn,proto3" json:"isCdn,omitempty"`
+	Cname          string                 `protobuf:"bytes,16,opt,name=cname,proto3" json:"cname,omitempty"`
+	IsCloud        bool                   `protobuf:"varint,17,opt,name=isCloud,proto3" json:"isCloud,omitempty"`
+	Ipv4           []*IPV4                `protobuf:"bytes,18,rep,name=ipv4,proto3" json:"ipv4,omitempty"`
+	Ipv6           []*IPV6                `protobuf:"bytes,19,rep,name=ipv6,proto3" json:"ipv6,omitempty"`
+	Screenshot     string                 `protobuf:"bytes,20,opt,name=screenshot,proto3" json:"screenshot,omitempty"`
+	IsHttp         bool                   `protobuf:"varint,21,opt,name=isHttp,proto3" json:"isHttp,omitempty"`
+	Source         string                 `protobuf:"bytes,22,opt,name=source,proto3" json:"source,omitempty"`                 // 资产来源: subfinder, portscan, etc.
+	IconData       []byte                 `protobuf:"bytes,23,opt,name=iconData,proto3" json:"iconData,omitempty"`             // favicon 图片原始数据
+	Transport      string                 `protobuf:"bytes,24,opt,name=transport,proto3" json:"transport,omitempty"`           // 传输层协议: 空为tcp, udp
+	TlsInfo        []byte                 `protobuf:"bytes,25,opt,name=tlsInfo,proto3" json:"tlsInfo,omitempty"`               // TLS 采集结果 JSON
+	CdnName        string                 `protobuf:"bytes,26,opt,name=cdnName,proto3" json:"cdnName,omitempty"`               // CDN厂商
+	Waf            string                 `protobuf:"bytes,27,opt,name=waf,proto3" json:"waf,omitempty"`                       // WAF厂商
+	CloudName      string                 `protobuf:"bytes,28,opt,name=cloudName,proto3" json:"cloudName,omitempty"`           // 云厂商
+	ScreenshotHash string                 `protobuf:"bytes,29,opt,name=screenshotHash,proto3" json:"screenshotHash,omitempty"` // 截图感知哈希(pHash)，截图为对象存储key
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *AssetDocument) Reset() {
@@ -649,11 +671,59 @@ func (x *AssetDocument) GetIconData() []byte {
 	return nil
 }
 
+func (x *AssetDocument) GetTransport() string {
+	if x != nil {
+		return x.Transport
+	}
+	return ""
+}
+
+func (x *AssetDocument) GetTlsInfo() []byte {
+	if x != nil {
+		return x.TlsInfo
+	}
+	return nil
+}
+
+func (x *AssetDocument) GetCdnName() string {
+	if x != nil {
+		return x.CdnName
+	}
+	return ""
+}
+
+func (x *AssetDocument) GetWaf() string {
+	if x != nil {
+		return x.Waf
+	}
+	return ""
+}
+
+func (x *AssetDocument) GetCloudName() string {
+	if x != nil {
+		return x.CloudName
+	}
+	return ""
+}
+
+func (x *AssetDocument) GetScreenshotHash() string {
+	if x != nil {
+		return x.ScreenshotHash
+	}
+	return ""
+}
+
 type IPV4 struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
 	IpInt         uint32                 `protobuf:"varint,2,opt,name=ipInt,proto3" json:"ipInt,omitempty"`
 	Location      string                 `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"`
+	Country       string                 `protobuf:"bytes,4,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,5,opt,name=region,proto3" json:"region,omitempty"`
+	City          string                 `protobuf:"bytes,6,opt,name=city,proto3" json:"city,omitempty"`
+	Asn           uint32                 `protobuf:"varint,7,opt,name=asn,proto3" json:"asn,omitempty"`
+	Org           string                 `protobuf:"bytes,8,opt,name=org,proto3" json:"org,omitempty"`
+	Isp           string                 `protobuf:"bytes,9,opt,name=isp,proto3" json:"isp,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -709,10 +779,58 @@ func (x *IPV4) GetLocation() string {
 	return ""
 }
 
+func (x *IPV4) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *IPV4) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *IPV4) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *IPV4) GetAsn() uint32 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+func (x *IPV4) GetOrg() string {
+	if x != nil {
+		return x.Org
+	}
+	return ""
+}
+
+func (x *IPV4) GetIsp() string {
+	if x != nil {
+		return x.Isp
+	}
+	return ""
+}
+
 type IPV6 struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Ip            string                 `protobuf:"bytes,1,opt,name=ip,proto3" json:"ip,omitempty"`
 	Location      string                 `protobuf:"bytes,2,opt,name=location,proto3" json:"location,omitempty"`
+	Country       string                 `protobuf:"bytes,3,opt,name=country,proto3" json:"country,omitempty"`
+	Region        string                 `protobuf:"bytes,4,opt,name=region,proto3" json:"region,omitempty"`
+	City          string                 `protobuf:"bytes,5,opt,name=city,proto3" json:"city,omitempty"`
+	Asn           uint32                 `protobuf:"varint,6,opt,name=asn,proto3" json:"asn,omitempty"`
+	Org           string                 `protobuf:"bytes,7,opt,name=org,proto3" json:"org,omitempty"`
+	Isp           string                 `protobuf:"bytes,8,opt,name=isp,proto3" json:"isp,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -761,6 +879,48 @@ func (x *IPV6) GetLocation() string {
 	return ""
 }
 
+func (x *IPV6) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *IPV6) GetRegion() string {
+	if x != nil {
+		return x.Region
+	}
+	return ""
+}
+
+func (x *IPV6) GetCity() string {
+	if x != nil {
+		return x.City
+	}
+	return ""
+}
+
+func (x *IPV6) GetAsn() uint32 {
+	if x != nil {
+		return x.Asn
+	}
+	return 0
+}
+
+func (x *IPV6) GetOrg() string {
+	if x != nil {
+		return x.Org
+	}
+	return ""
+}
+
+func (x *IPV6) GetIsp() string {
+	if x != nil {
+		return x.Isp
+	}
+	return ""
+}
+
 type SaveTaskResultReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspaceId,proto3" json:"workspaceId,omitempty"`
@@ -930,8 +1090,10 @@ type VulDocument struct {
 	Request           *string  `protobuf:"bytes,19,opt,name=request,proto3,oneof" json:"request,omitempty"`
 	Response          *string  `protobuf:"bytes,20,opt,name=response,proto3,oneof" json:"response,omitempty"`
 	ResponseTruncated *bool    `protobuf:"varint,21,opt,name=responseTruncated,proto3,oneof" json:"responseTruncated,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// OOB 回连证据
+	Interaction   *VulInteraction `protobuf:"bytes,22,opt,name=interaction,proto3" json:"interaction,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *VulDocument) Reset() {
@@ -1111,6 +1273,121 @@ func (x *VulDocument) GetResponseTruncated() bool {
 	return false
 }
 
+func (x *VulDocument) GetInteraction() *VulInteraction {
+	if x != nil {
+		return x.Interaction
+	}
+	return nil
+}
+
+type VulInteraction struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Protocol      string                 `protobuf:"bytes,1,opt,name=protocol,proto3" json:"protocol,omitempty"`
+	UniqueId      string                 `protobuf:"bytes,2,opt,name=uniqueId,proto3" json:"uniqueId,omitempty"`
+	FullId        string                 `protobuf:"bytes,3,opt,name=fullId,proto3" json:"fullId,omitempty"`
+	QType         string                 `protobuf:"bytes,4,opt,name=qType,proto3" json:"qType,omitempty"`
+	RawRequest    string                 `protobuf:"bytes,5,opt,name=rawRequest,proto3" json:"rawRequest,omitempty"`
+	RawResponse   string                 `protobuf:"bytes,6,opt,name=rawResponse,proto3" json:"rawResponse,omitempty"`
+	RemoteAddress string                 `protobuf:"bytes,7,opt,name=remoteAddress,proto3" json:"remoteAddress,omitempty"`
+	SmtpFrom      string                 `protobuf:"bytes,8,opt,name=smtpFrom,proto3" json:"smtpFrom,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"` // unix 毫秒
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VulInteraction) Reset() {
+	*x = VulInteraction{}
+	mi := &file_task_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VulInteraction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VulInteraction) ProtoMessage() {}
+
+func (x *VulInteraction) ProtoReflect() protoreflect.Message {
+	mi := &file_task_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VulInteraction.ProtoReflect.Descriptor instead.
+func (*VulInteraction) Descriptor() ([]byte, []int) {
+	return file_task_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *VulInteraction) GetProtocol() string {
+	if x != nil {
+		return x.Protocol
+	}
+	return ""
+}
+
+func (x *VulInteraction) GetUniqueId() string {
+	if x != nil {
+		return x.UniqueId
+	}
+	return ""
+}
+
+func (x *VulInteraction) GetFullId() string {
+	if x != nil {
+		return x.FullId
+	}
+	return ""
+}
+
+func (x *VulInteraction) GetQType() string {
+	if x != nil {
+		return x.QType
+	}
+	return ""
+}
+
+func (x *VulInteraction) GetRawRequest() string {
+	if x != nil {
+		return x.RawRequest
+	}
+	return ""
+}
+
+func (x *VulInteraction) GetRawResponse() string {
+	if x != nil {
+		return x.RawResponse
+	}
+	return ""
+}
+
+func (x *VulInteraction) GetRemoteAddress() string {
+	if x != nil {
+		return x.RemoteAddress
+	}
+	return ""
+}
+
+func (x *VulInteraction) GetSmtpFrom() string {
+	if x != nil {
+		return x.SmtpFrom
+	}
+	return ""
+}
+
+func (x *VulInteraction) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
 type SaveVulResultReq struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	WorkspaceId   string                 `protobuf:"bytes,1,opt,name=workspaceId,proto3" json:"workspaceId,omitempty"`
@@ -1122,7 +1399,7 @@ type SaveVulResultReq struct {
 
 func (x *SaveVulResultReq) Reset() {
 	*x = SaveVulResultReq{}
-	mi := &file_task_proto_msgTypes[12]
+	mi := &file_task_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1134,7 +1411,7 @@ func (x *SaveVulResultReq) String() string {
 func (*SaveVulResultReq) ProtoMessage() {}
 
 func (x *SaveVulResultReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[12]
+	mi := &file_task_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1147,7 +1424,7 @@ func (x *SaveVulResultReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SaveVulResultReq.ProtoReflect.Descriptor instead.
 func (*SaveVulResultReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{12}
+	return file_task_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *SaveVulResultReq) GetWorkspaceId() string {
@@ -1182,7 +1459,7 @@ type SaveVulResultResp struct {
 
 func (x *SaveVulResultResp) Reset() {
 	*x = SaveVulResultResp{}
-	mi := &file_task_proto_msgTypes[13]
+	mi := &file_task_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1194,7 +1471,7 @@ func (x *SaveVulResultResp) String() string {
 func (*SaveVulResultResp) ProtoMessage() {}
 
 func (x *SaveVulResultResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[13]
+	mi := &file_task_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1207,7 +1484,7 @@ func (x *SaveVulResultResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SaveVulResultResp.ProtoReflect.Descriptor instead.
 func (*SaveVulResultResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{13}
+	return file_task_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *SaveVulResultResp) GetSuccess() bool {
@@ -1246,7 +1523,7 @@ type KeepAliveReq struct {
 
 func (x *KeepAliveReq) Reset() {
 	*x = KeepAliveReq{}
-	mi := &file_task_proto_msgTypes[14]
+	mi := &file_task_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1258,7 +1535,7 @@ func (x *KeepAliveReq) String() string {
 func (*KeepAliveReq) ProtoMessage() {}
 
 func (x *KeepAliveReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[14]
+	mi := &file_task_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1271,7 +1548,7 @@ func (x *KeepAliveReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KeepAliveReq.ProtoReflect.Descriptor instead.
 func (*KeepAliveReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{14}
+	return file_task_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *KeepAliveReq) GetWorkerName() string {
@@ -1336,7 +1613,7 @@ type KeepAliveResp struct {
 
 func (x *KeepAliveResp) Reset() {
 	*x = KeepAliveResp{}
-	mi := &file_task_proto_msgTypes[15]
+	mi := &file_task_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1348,7 +1625,7 @@ func (x *KeepAliveResp) String() string {
 func (*KeepAliveResp) ProtoMessage() {}
 
 func (x *KeepAliveResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[15]
+	mi := &file_task_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1361,7 +1638,7 @@ func (x *KeepAliveResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use KeepAliveResp.ProtoReflect.Descriptor instead.
 func (*KeepAliveResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{15}
+	return file_task_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *KeepAliveResp) GetStatus() string {
@@ -1408,7 +1685,7 @@ type GetWorkerConfigReq struct {
 
 func (x *GetWorkerConfigReq) Reset() {
 	*x = GetWorkerConfigReq{}
-	mi := &file_task_proto_msgTypes[16]
+	mi := &file_task_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1420,7 +1697,7 @@ func (x *GetWorkerConfigReq) String() string {
 func (*GetWorkerConfigReq) ProtoMessage() {}
 
 func (x *GetWorkerConfigReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[16]
+	mi := &file_task_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1433,7 +1710,7 @@ func (x *GetWorkerConfigReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWorkerConfigReq.ProtoReflect.Descriptor instead.
 func (*GetWorkerConfigReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{16}
+	return file_task_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetWorkerConfigReq) GetWorkerName() string {
@@ -1452,7 +1729,7 @@ type GetWorkerConfigResp struct {
 
 func (x *GetWorkerConfigResp) Reset() {
 	*x = GetWorkerConfigResp{}
-	mi := &file_task_proto_msgTypes[17]
+	mi := &file_task_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1464,7 +1741,7 @@ func (x *GetWorkerConfigResp) String() string {
 func (*GetWorkerConfigResp) ProtoMessage() {}
 
 func (x *GetWorkerConfigResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[17]
+	mi := &file_task_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1477,7 +1754,7 @@ func (x *GetWorkerConfigResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetWorkerConfigResp.ProtoReflect.Descriptor instead.
 func (*GetWorkerConfigResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{17}
+	return file_task_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *GetWorkerConfigResp) GetConfig() string {
@@ -1497,7 +1774,7 @@ type RequestResourceReq struct {
 
 func (x *RequestResourceReq) Reset() {
 	*x = RequestResourceReq{}
-	mi := &file_task_proto_msgTypes[18]
+	mi := &file_task_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1509,7 +1786,7 @@ func (x *RequestResourceReq) String() string {
 func (*RequestResourceReq) ProtoMessage() {}
 
 func (x *RequestResourceReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[18]
+	mi := &file_task_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1522,7 +1799,7 @@ func (x *RequestResourceReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestResourceReq.ProtoReflect.Descriptor instead.
 func (*RequestResourceReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{18}
+	return file_task_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *RequestResourceReq) GetCategory() string {
@@ -1550,7 +1827,7 @@ type RequestResourceResp struct {
 
 func (x *RequestResourceResp) Reset() {
 	*x = RequestResourceResp{}
-	mi := &file_task_proto_msgTypes[19]
+	mi := &file_task_proto_msgTypes[20]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1562,7 +1839,7 @@ func (x *RequestResourceResp) String() string {
 func (*RequestResourceResp) ProtoMessage() {}
 
 func (x *RequestResourceResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[19]
+	mi := &file_task_proto_msgTypes[20]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1575,7 +1852,7 @@ func (x *RequestResourceResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use RequestResourceResp.ProtoReflect.Descriptor instead.
 func (*RequestResourceResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{19}
+	return file_task_proto_rawDescGZIP(), []int{20}
 }
 
 func (x *RequestResourceResp) GetPath() string {
@@ -1610,7 +1887,7 @@ type GetTemplatesByTagsReq struct {
 
 func (x *GetTemplatesByTagsReq) Reset() {
 	*x = GetTemplatesByTagsReq{}
-	mi := &file_task_proto_msgTypes[20]
+	mi := &file_task_proto_msgTypes[21]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1622,7 +1899,7 @@ func (x *GetTemplatesByTagsReq) String() string {
 func (*GetTemplatesByTagsReq) ProtoMessage() {}
 
 func (x *GetTemplatesByTagsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[20]
+	mi := &file_task_proto_msgTypes[21]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1635,7 +1912,7 @@ func (x *GetTemplatesByTagsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTemplatesByTagsReq.ProtoReflect.Descriptor instead.
 func (*GetTemplatesByTagsReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{20}
+	return file_task_proto_rawDescGZIP(), []int{21}
 }
 
 func (x *GetTemplatesByTagsReq) GetTags() []string {
@@ -1665,7 +1942,7 @@ type GetTemplatesByTagsResp struct {
 
 func (x *GetTemplatesByTagsResp) Reset() {
 	*x = GetTemplatesByTagsResp{}
-	mi := &file_task_proto_msgTypes[21]
+	mi := &file_task_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1677,7 +1954,7 @@ func (x *GetTemplatesByTagsResp) String() string {
 func (*GetTemplatesByTagsResp) ProtoMessage() {}
 
 func (x *GetTemplatesByTagsResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[21]
+	mi := &file_task_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1690,7 +1967,7 @@ func (x *GetTemplatesByTagsResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTemplatesByTagsResp.ProtoReflect.Descriptor instead.
 func (*GetTemplatesByTagsResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{21}
+	return file_task_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *GetTemplatesByTagsResp) GetSuccess() bool {
@@ -1731,7 +2008,7 @@ type GetCustomFingerprintsReq struct {
 
 func (x *GetCustomFingerprintsReq) Reset() {
 	*x = GetCustomFingerprintsReq{}
-	mi := &file_task_proto_msgTypes[22]
+	mi := &file_task_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1743,7 +2020,7 @@ func (x *GetCustomFingerprintsReq) String() string {
 func (*GetCustomFingerprintsReq) ProtoMessage() {}
 
 func (x *GetCustomFingerprintsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[22]
+	mi := &file_task_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1756,7 +2033,7 @@ func (x *GetCustomFingerprintsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCustomFingerprintsReq.ProtoReflect.Descriptor instead.
 func (*GetCustomFingerprintsReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{22}
+	return file_task_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *GetCustomFingerprintsReq) GetEnabledOnly() bool {
@@ -1790,7 +2067,7 @@ type FingerprintDocument struct {
 
 func (x *FingerprintDocument) Reset() {
 	*x = FingerprintDocument{}
-	mi := &file_task_proto_msgTypes[23]
+	mi := &file_task_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1802,7 +2079,7 @@ func (x *FingerprintDocument) String() string {
 func (*FingerprintDocument) ProtoMessage() {}
 
 func (x *FingerprintDocument) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[23]
+	mi := &file_task_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1815,7 +2092,7 @@ func (x *FingerprintDocument) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use FingerprintDocument.ProtoReflect.Descriptor instead.
 func (*FingerprintDocument) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{23}
+	return file_task_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *FingerprintDocument) GetId() string {
@@ -1936,7 +2213,7 @@ type GetCustomFingerprintsResp struct {
 
 func (x *GetCustomFingerprintsResp) Reset() {
 	*x = GetCustomFingerprintsResp{}
-	mi := &file_task_proto_msgTypes[24]
+	mi := &file_task_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1948,7 +2225,7 @@ func (x *GetCustomFingerprintsResp) String() string {
 func (*GetCustomFingerprintsResp) ProtoMessage() {}
 
 func (x *GetCustomFingerprintsResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[24]
+	mi := &file_task_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1961,7 +2238,7 @@ func (x *GetCustomFingerprintsResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetCustomFingerprintsResp.ProtoReflect.Descriptor instead.
 func (*GetCustomFingerprintsResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{24}
+	return file_task_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *GetCustomFingerprintsResp) GetSuccess() bool {
@@ -2004,7 +2281,7 @@ type ValidateFingerprintReq struct {
 
 func (x *ValidateFingerprintReq) Reset() {
 	*x = ValidateFingerprintReq{}
-	mi := &file_task_proto_msgTypes[25]
+	mi := &file_task_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2016,7 +2293,7 @@ func (x *ValidateFingerprintReq) String() string {
 func (*ValidateFingerprintReq) ProtoMessage() {}
 
 func (x *ValidateFingerprintReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[25]
+	mi := &file_task_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2029,7 +2306,7 @@ func (x *ValidateFingerprintReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidateFingerprintReq.ProtoReflect.Descriptor instead.
 func (*ValidateFingerprintReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{25}
+	return file_task_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *ValidateFingerprintReq) GetUrl() string {
@@ -2067,7 +2344,7 @@ type MatchedFingerprintInfo struct {
 
 func (x *MatchedFingerprintInfo) Reset() {
 	*x = MatchedFingerprintInfo{}
-	mi := &file_task_proto_msgTypes[26]
+	mi := &file_task_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2079,7 +2356,7 @@ func (x *MatchedFingerprintInfo) String() string {
 func (*MatchedFingerprintInfo) ProtoMessage() {}
 
 func (x *MatchedFingerprintInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[26]
+	mi := &file_task_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2092,7 +2369,7 @@ func (x *MatchedFingerprintInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use MatchedFingerprintInfo.ProtoReflect.Descriptor instead.
 func (*MatchedFingerprintInfo) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{26}
+	return file_task_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *MatchedFingerprintInfo) GetId() string {
@@ -2146,7 +2423,7 @@ type ValidateFingerprintResp struct {
 
 func (x *ValidateFingerprintResp) Reset() {
 	*x = ValidateFingerprintResp{}
-	mi := &file_task_proto_msgTypes[27]
+	mi := &file_task_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2158,7 +2435,7 @@ func (x *ValidateFingerprintResp) String() string {
 func (*ValidateFingerprintResp) ProtoMessage() {}
 
 func (x *ValidateFingerprintResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[27]
+	mi := &file_task_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2171,7 +2448,7 @@ func (x *ValidateFingerprintResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidateFingerprintResp.ProtoReflect.Descriptor instead.
 func (*ValidateFingerprintResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{27}
+	return file_task_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *ValidateFingerprintResp) GetSuccess() bool {
@@ -2243,7 +2520,7 @@ type ValidatePocReq struct {
 
 func (x *ValidatePocReq) Reset() {
 	*x = ValidatePocReq{}
-	mi := &file_task_proto_msgTypes[28]
+	mi := &file_task_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2255,7 +2532,7 @@ func (x *ValidatePocReq) String() string {
 func (*ValidatePocReq) ProtoMessage() {}
 
 func (x *ValidatePocReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[28]
+	mi := &file_task_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2268,7 +2545,7 @@ func (x *ValidatePocReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidatePocReq.ProtoReflect.Descriptor instead.
 func (*ValidatePocReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{28}
+	return file_task_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *ValidatePocReq) GetUrl() string {
@@ -2367,7 +2644,7 @@ type PocValidationResult struct {
 
 func (x *PocValidationResult) Reset() {
 	*x = PocValidationResult{}
-	mi := &file_task_proto_msgTypes[29]
+	mi := &file_task_proto_msgTypes[30]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2379,7 +2656,7 @@ func (x *PocValidationResult) String() string {
 func (*PocValidationResult) ProtoMessage() {}
 
 func (x *PocValidationResult) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[29]
+	mi := &file_task_proto_msgTypes[30]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2392,7 +2669,7 @@ func (x *PocValidationResult) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use PocValidationResult.ProtoReflect.Descriptor instead.
 func (*PocValidationResult) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{29}
+	return file_task_proto_rawDescGZIP(), []int{30}
 }
 
 func (x *PocValidationResult) GetPocId() string {
@@ -2483,7 +2760,7 @@ type ValidatePocResp struct {
 
 func (x *ValidatePocResp) Reset() {
 	*x = ValidatePocResp{}
-	mi := &file_task_proto_msgTypes[30]
+	mi := &file_task_proto_msgTypes[31]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2495,7 +2772,7 @@ func (x *ValidatePocResp) String() string {
 func (*ValidatePocResp) ProtoMessage() {}
 
 func (x *ValidatePocResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[30]
+	mi := &file_task_proto_msgTypes[31]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2508,7 +2785,7 @@ func (x *ValidatePocResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use ValidatePocResp.ProtoReflect.Descriptor instead.
 func (*ValidatePocResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{30}
+	return file_task_proto_rawDescGZIP(), []int{31}
 }
 
 func (x *ValidatePocResp) GetSuccess() bool {
@@ -2592,7 +2869,7 @@ type BatchValidatePocReq struct {
 
 func (x *BatchValidatePocReq) Reset() {
 	*x = BatchValidatePocReq{}
-	mi := &file_task_proto_msgTypes[31]
+	mi := &file_task_proto_msgTypes[32]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2604,7 +2881,7 @@ func (x *BatchValidatePocReq) String() string {
 func (*BatchValidatePocReq) ProtoMessage() {}
 
 func (x *BatchValidatePocReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[31]
+	mi := &file_task_proto_msgTypes[32]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2617,7 +2894,7 @@ func (x *BatchValidatePocReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BatchValidatePocReq.ProtoReflect.Descriptor instead.
 func (*BatchValidatePocReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{31}
+	return file_task_proto_rawDescGZIP(), []int{32}
 }
 
 func (x *BatchValidatePocReq) GetUrls() []string {
@@ -2701,7 +2978,7 @@ type BatchValidatePocResp struct {
 
 func (x *BatchValidatePocResp) Reset() {
 	*x = BatchValidatePocResp{}
-	mi := &file_task_proto_msgTypes[32]
+	mi := &file_task_proto_msgTypes[33]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2713,7 +2990,7 @@ func (x *BatchValidatePocResp) String() string {
 func (*BatchValidatePocResp) ProtoMessage() {}
 
 func (x *BatchValidatePocResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[32]
+	mi := &file_task_proto_msgTypes[33]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2726,7 +3003,7 @@ func (x *BatchValidatePocResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use BatchValidatePocResp.ProtoReflect.Descriptor instead.
 func (*BatchValidatePocResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{32}
+	return file_task_proto_rawDescGZIP(), []int{33}
 }
 
 func (x *BatchValidatePocResp) GetSuccess() bool {
@@ -2803,7 +3080,7 @@ type GetPocValidationResultReq struct {
 
 func (x *GetPocValidationResultReq) Reset() {
 	*x = GetPocValidationResultReq{}
-	mi := &file_task_proto_msgTypes[33]
+	mi := &file_task_proto_msgTypes[34]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2815,7 +3092,7 @@ func (x *GetPocValidationResultReq) String() string {
 func (*GetPocValidationResultReq) ProtoMessage() {}
 
 func (x *GetPocValidationResultReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[33]
+	mi := &file_task_proto_msgTypes[34]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2828,7 +3105,7 @@ func (x *GetPocValidationResultReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPocValidationResultReq.ProtoReflect.Descriptor instead.
 func (*GetPocValidationResultReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{33}
+	return file_task_proto_rawDescGZIP(), []int{34}
 }
 
 func (x *GetPocValidationResultReq) GetTaskId() string {
@@ -2862,7 +3139,7 @@ type GetPocValidationResultResp struct {
 
 func (x *GetPocValidationResultResp) Reset() {
 	*x = GetPocValidationResultResp{}
-	mi := &file_task_proto_msgTypes[34]
+	mi := &file_task_proto_msgTypes[35]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2874,7 +3151,7 @@ func (x *GetPocValidationResultResp) String() string {
 func (*GetPocValidationResultResp) ProtoMessage() {}
 
 func (x *GetPocValidationResultResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[34]
+	mi := &file_task_proto_msgTypes[35]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2887,7 +3164,7 @@ func (x *GetPocValidationResultResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPocValidationResultResp.ProtoReflect.Descriptor instead.
 func (*GetPocValidationResultResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{34}
+	return file_task_proto_rawDescGZIP(), []int{35}
 }
 
 func (x *GetPocValidationResultResp) GetSuccess() bool {
@@ -2957,7 +3234,7 @@ type GetPocByIdReq struct {
 
 func (x *GetPocByIdReq) Reset() {
 	*x = GetPocByIdReq{}
-	mi := &file_task_proto_msgTypes[35]
+	mi := &file_task_proto_msgTypes[36]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -2969,7 +3246,7 @@ func (x *GetPocByIdReq) String() string {
 func (*GetPocByIdReq) ProtoMessage() {}
 
 func (x *GetPocByIdReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[35]
+	mi := &file_task_proto_msgTypes[36]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2982,7 +3259,7 @@ func (x *GetPocByIdReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPocByIdReq.ProtoReflect.Descriptor instead.
 func (*GetPocByIdReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{35}
+	return file_task_proto_rawDescGZIP(), []int{36}
 }
 
 func (x *GetPocByIdReq) GetPocId() string {
@@ -3017,7 +3294,7 @@ type GetPocByIdResp struct {
 
 func (x *GetPocByIdResp) Reset() {
 	*x = GetPocByIdResp{}
-	mi := &file_task_proto_msgTypes[36]
+	mi := &file_task_proto_msgTypes[37]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3029,7 +3306,7 @@ func (x *GetPocByIdResp) String() string {
 func (*GetPocByIdResp) ProtoMessage() {}
 
 func (x *GetPocByIdResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[36]
+	mi := &file_task_proto_msgTypes[37]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3042,7 +3319,7 @@ func (x *GetPocByIdResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetPocByIdResp.ProtoReflect.Descriptor instead.
 func (*GetPocByIdResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{36}
+	return file_task_proto_rawDescGZIP(), []int{37}
 }
 
 func (x *GetPocByIdResp) GetSuccess() bool {
@@ -3119,7 +3396,7 @@ type GetTemplatesByIdsReq struct {
 
 func (x *GetTemplatesByIdsReq) Reset() {
 	*x = GetTemplatesByIdsReq{}
-	mi := &file_task_proto_msgTypes[37]
+	mi := &file_task_proto_msgTypes[38]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3131,7 +3408,7 @@ func (x *GetTemplatesByIdsReq) String() string {
 func (*GetTemplatesByIdsReq) ProtoMessage() {}
 
 func (x *GetTemplatesByIdsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[37]
+	mi := &file_task_proto_msgTypes[38]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3144,7 +3421,7 @@ func (x *GetTemplatesByIdsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTemplatesByIdsReq.ProtoReflect.Descriptor instead.
 func (*GetTemplatesByIdsReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{37}
+	return file_task_proto_rawDescGZIP(), []int{38}
 }
 
 func (x *GetTemplatesByIdsReq) GetNucleiTemplateIds() []string {
@@ -3174,7 +3451,7 @@ type GetTemplatesByIdsResp struct {
 
 func (x *GetTemplatesByIdsResp) Reset() {
 	*x = GetTemplatesByIdsResp{}
-	mi := &file_task_proto_msgTypes[38]
+	mi := &file_task_proto_msgTypes[39]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3186,7 +3463,7 @@ func (x *GetTemplatesByIdsResp) String() string {
 func (*GetTemplatesByIdsResp) ProtoMessage() {}
 
 func (x *GetTemplatesByIdsResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[38]
+	mi := &file_task_proto_msgTypes[39]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3199,7 +3476,7 @@ func (x *GetTemplatesByIdsResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTemplatesByIdsResp.ProtoReflect.Descriptor instead.
 func (*GetTemplatesByIdsResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{38}
+	return file_task_proto_rawDescGZIP(), []int{39}
 }
 
 func (x *GetTemplatesByIdsResp) GetSuccess() bool {
@@ -3240,7 +3517,7 @@ type GetHttpServiceMappingsReq struct {
 
 func (x *GetHttpServiceMappingsReq) Reset() {
 	*x = GetHttpServiceMappingsReq{}
-	mi := &file_task_proto_msgTypes[39]
+	mi := &file_task_proto_msgTypes[40]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3252,7 +3529,7 @@ func (x *GetHttpServiceMappingsReq) String() string {
 func (*GetHttpServiceMappingsReq) ProtoMessage() {}
 
 func (x *GetHttpServiceMappingsReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[39]
+	mi := &file_task_proto_msgTypes[40]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3265,7 +3542,7 @@ func (x *GetHttpServiceMappingsReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetHttpServiceMappingsReq.ProtoReflect.Descriptor instead.
 func (*GetHttpServiceMappingsReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{39}
+	return file_task_proto_rawDescGZIP(), []int{40}
 }
 
 func (x *GetHttpServiceMappingsReq) GetEnabledOnly() bool {
@@ -3289,7 +3566,7 @@ type HttpServiceMappingDocument struct {
 
 func (x *HttpServiceMappingDocument) Reset() {
 	*x = HttpServiceMappingDocument{}
-	mi := &file_task_proto_msgTypes[40]
+	mi := &file_task_proto_msgTypes[41]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3301,7 +3578,7 @@ func (x *HttpServiceMappingDocument) String() string {
 func (*HttpServiceMappingDocument) ProtoMessage() {}
 
 func (x *HttpServiceMappingDocument) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[40]
+	mi := &file_task_proto_msgTypes[41]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3314,7 +3591,7 @@ func (x *HttpServiceMappingDocument) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HttpServiceMappingDocument.ProtoReflect.Descriptor instead.
 func (*HttpServiceMappingDocument) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{40}
+	return file_task_proto_rawDescGZIP(), []int{41}
 }
 
 func (x *HttpServiceMappingDocument) GetId() string {
@@ -3365,7 +3642,7 @@ type GetHttpServiceMappingsResp struct {
 
 func (x *GetHttpServiceMappingsResp) Reset() {
 	*x = GetHttpServiceMappingsResp{}
-	mi := &file_task_proto_msgTypes[41]
+	mi := &file_task_proto_msgTypes[42]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3377,7 +3654,7 @@ func (x *GetHttpServiceMappingsResp) String() string {
 func (*GetHttpServiceMappingsResp) ProtoMessage() {}
 
 func (x *GetHttpServiceMappingsResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[41]
+	mi := &file_task_proto_msgTypes[42]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3390,7 +3667,7 @@ func (x *GetHttpServiceMappingsResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetHttpServiceMappingsResp.ProtoReflect.Descriptor instead.
 func (*GetHttpServiceMappingsResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{41}
+	return file_task_proto_rawDescGZIP(), []int{42}
 }
 
 func (x *GetHttpServiceMappingsResp) GetSuccess() bool {
@@ -3431,7 +3708,7 @@ type GetSubfinderProvidersReq struct {
 
 func (x *GetSubfinderProvidersReq) Reset() {
 	*x = GetSubfinderProvidersReq{}
-	mi := &file_task_proto_msgTypes[42]
+	mi := &file_task_proto_msgTypes[43]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3443,7 +3720,7 @@ func (x *GetSubfinderProvidersReq) String() string {
 func (*GetSubfinderProvidersReq) ProtoMessage() {}
 
 func (x *GetSubfinderProvidersReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[42]
+	mi := &file_task_proto_msgTypes[43]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3456,7 +3733,7 @@ func (x *GetSubfinderProvidersReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSubfinderProvidersReq.ProtoReflect.Descriptor instead.
 func (*GetSubfinderProvidersReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{42}
+	return file_task_proto_rawDescGZIP(), []int{43}
 }
 
 func (x *GetSubfinderProvidersReq) GetWorkspaceId() string {
@@ -3480,7 +3757,7 @@ type SubfinderProviderDocument struct {
 
 func (x *SubfinderProviderDocument) Reset() {
 	*x = SubfinderProviderDocument{}
-	mi := &file_task_proto_msgTypes[43]
+	mi := &file_task_proto_msgTypes[44]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3492,7 +3769,7 @@ func (x *SubfinderProviderDocument) String() string {
 func (*SubfinderProviderDocument) ProtoMessage() {}
 
 func (x *SubfinderProviderDocument) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[43]
+	mi := &file_task_proto_msgTypes[44]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3505,7 +3782,7 @@ func (x *SubfinderProviderDocument) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SubfinderProviderDocument.ProtoReflect.Descriptor instead.
 func (*SubfinderProviderDocument) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{43}
+	return file_task_proto_rawDescGZIP(), []int{44}
 }
 
 func (x *SubfinderProviderDocument) GetId() string {
@@ -3556,7 +3833,7 @@ type GetSubfinderProvidersResp struct {
 
 func (x *GetSubfinderProvidersResp) Reset() {
 	*x = GetSubfinderProvidersResp{}
-	mi := &file_task_proto_msgTypes[44]
+	mi := &file_task_proto_msgTypes[45]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3568,7 +3845,7 @@ func (x *GetSubfinderProvidersResp) String() string {
 func (*GetSubfinderProvidersResp) ProtoMessage() {}
 
 func (x *GetSubfinderProvidersResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[44]
+	mi := &file_task_proto_msgTypes[45]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3581,7 +3858,7 @@ func (x *GetSubfinderProvidersResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetSubfinderProvidersResp.ProtoReflect.Descriptor instead.
 func (*GetSubfinderProvidersResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{44}
+	return file_task_proto_rawDescGZIP(), []int{45}
 }
 
 func (x *GetSubfinderProvidersResp) GetSuccess() bool {
@@ -3625,7 +3902,7 @@ type IncrSubTaskDoneReq struct {
 
 func (x *IncrSubTaskDoneReq) Reset() {
 	*x = IncrSubTaskDoneReq{}
-	mi := &file_task_proto_msgTypes[45]
+	mi := &file_task_proto_msgTypes[46]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3637,7 +3914,7 @@ func (x *IncrSubTaskDoneReq) String() string {
 func (*IncrSubTaskDoneReq) ProtoMessage() {}
 
 func (x *IncrSubTaskDoneReq) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[45]
+	mi := &file_task_proto_msgTypes[46]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3650,7 +3927,7 @@ func (x *IncrSubTaskDoneReq) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IncrSubTaskDoneReq.ProtoReflect.Descriptor instead.
 func (*IncrSubTaskDoneReq) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{45}
+	return file_task_proto_rawDescGZIP(), []int{46}
 }
 
 func (x *IncrSubTaskDoneReq) GetTaskId() string {
@@ -3695,7 +3972,7 @@ type IncrSubTaskDoneResp struct {
 
 func (x *IncrSubTaskDoneResp) Reset() {
 	*x = IncrSubTaskDoneResp{}
-	mi := &file_task_proto_msgTypes[46]
+	mi := &file_task_proto_msgTypes[47]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -3707,7 +3984,7 @@ func (x *IncrSubTaskDoneResp) String() string {
 func (*IncrSubTaskDoneResp) ProtoMessage() {}
 
 func (x *IncrSubTaskDoneResp) ProtoReflect() protoreflect.Message {
-	mi := &file_task_proto_msgTypes[46]
+	mi := &file_task_proto_msgTypes[47]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -3720,7 +3997,7 @@ func (x *IncrSubTaskDoneResp) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use IncrSubTaskDoneResp.ProtoReflect.Descriptor instead.
 func (*IncrSubTaskDoneResp) Descriptor() ([]byte, []int) {
-	return file_task_proto_rawDescGZIP(), []int{46}
+	return file_task_proto_rawDescGZIP(), []int{47}
 }
 
 func (x *IncrSubTaskDoneResp) GetSuccess() bool {
@@ -3763,12 +4040,14 @@ var File_task_proto protoreflect.FileDescriptor
 const file_task_proto_rawDesc = "" +
 	"\n" +
 	"\n" +
-	"task.proto\x12\x04task\"F\n" +
+	"task.proto\x12\x04task\"|\n" +
 	"\fCheckTaskReq\x12\x16\n" +
 	"\x06taskId\x18\x01 \x01(\tR\x06taskId\x12\x1e\n" +
 	"\n" +
 	"mainTaskId\x18\x02 \x01(\tR\n" +
-	"mainTaskId\"\x81\x02\n" +
+	"mainTaskId\x12\x16\n" +
+	"\x06labels\x18\x03 \x03(\tR\x06labels\x12\x1c\n" +
+	"\tlabelOnly\x18\x04 \x01(\bR\tlabelOnly\"\x81\x02\n" +
 	"\rCheckTaskResp\x12\x18\n" +
 	"\aisExist\x18\x01 \x01(\bR\aisExist\x12\x1e\n" +
 	"\n" +
@@ -3802,7 +4081,7 @@ const file_task_proto_rawDesc = "" +
 	"\vworkspaceId\x18\x05 \x01(\tR\vworkspaceId\"A\n" +
 	"\vNewTaskResp\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\xe1\x04\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x8b\x06\n" +
 	"\rAssetDocument\x12\x1c\n" +
 	"\tauthority\x18\x01 \x01(\tR\tauthority\x12\x12\n" +
 	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
@@ -3835,14 +4114,32 @@ const file_task_proto_rawDesc = "" +
 	"screenshot\x12\x16\n" +
 	"\x06isHttp\x18\x15 \x01(\bR\x06isHttp\x12\x16\n" +
 	"\x06source\x18\x16 \x01(\tR\x06source\x12\x1a\n" +
-	"\biconData\x18\x17 \x01(\fR\biconData\"H\n" +
+	"\biconData\x18\x17 \x01(\fR\biconData\x12\x1c\n" +
+	"\ttransport\x18\x18 \x01(\tR\ttransport\x12\x18\n" +
+	"\atlsInfo\x18\x19 \x01(\fR\atlsInfo\x12\x18\n" +
+	"\acdnName\x18\x1a \x01(\tR\acdnName\x12\x10\n" +
+	"\x03waf\x18\x1b \x01(\tR\x03waf\x12\x1c\n" +
+	"\tcloudName\x18\x1c \x01(\tR\tcloudName\x12&\n" +
+	"\x0escreenshotHash\x18\x1d \x01(\tR\x0escreenshotHash\"\xc4\x01\n" +
 	"\x04IPV4\x12\x0e\n" +
 	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x14\n" +
 	"\x05ipInt\x18\x02 \x01(\rR\x05ipInt\x12\x1a\n" +
-	"\blocation\x18\x03 \x01(\tR\blocation\"2\n" +
+	"\blocation\x18\x03 \x01(\tR\blocation\x12\x18\n" +
+	"\acountry\x18\x04 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x05 \x01(\tR\x06region\x12\x12\n" +
+	"\x04city\x18\x06 \x01(\tR\x04city\x12\x10\n" +
+	"\x03asn\x18\a \x01(\rR\x03asn\x12\x10\n" +
+	"\x03org\x18\b \x01(\tR\x03org\x12\x10\n" +
+	"\x03isp\x18\t \x01(\tR\x03isp\"\xae\x01\n" +
 	"\x04IPV6\x12\x0e\n" +
 	"\x02ip\x18\x01 \x01(\tR\x02ip\x12\x1a\n" +
-	"\blocation\x18\x02 \x01(\tR\blocation\"\x98\x01\n" +
+	"\blocation\x18\x02 \x01(\tR\blocation\x12\x18\n" +
+	"\acountry\x18\x03 \x01(\tR\acountry\x12\x16\n" +
+	"\x06region\x18\x04 \x01(\tR\x06region\x12\x12\n" +
+	"\x04city\x18\x05 \x01(\tR\x04city\x12\x10\n" +
+	"\x03asn\x18\x06 \x01(\rR\x03asn\x12\x10\n" +
+	"\x03org\x18\a \x01(\tR\x03org\x12\x10\n" +
+	"\x03isp\x18\b \x01(\tR\x03isp\"\x98\x01\n" +
 	"\x11SaveTaskResultReq\x12 \n" +
 	"\vworkspaceId\x18\x01 \x01(\tR\vworkspaceId\x12\x1e\n" +
 	"\n" +
@@ -3857,7 +4154,7 @@ const file_task_proto_rawDesc = "" +
 	"totalAsset\x18\x03 \x01(\x05R\n" +
 	"totalAsset\x12\x1a\n" +
 	"\bnewAsset\x18\x04 \x01(\x05R\bnewAsset\x12 \n" +
-	"\vupdateAsset\x18\x05 \x01(\x05R\vupdateAsset\"\x87\x06\n" +
+	"\vupdateAsset\x18\x05 \x01(\x05R\vupdateAsset\"\xbf\x06\n" +
 	"\vVulDocument\x12\x1c\n" +
 	"\tauthority\x18\x01 \x01(\tR\tauthority\x12\x12\n" +
 	"\x04host\x18\x02 \x01(\tR\x04host\x12\x12\n" +
@@ -3882,7 +4179,8 @@ const file_task_proto_rawDesc = "" +
 	"\vcurlCommand\x18\x12 \x01(\tH\x05R\vcurlCommand\x88\x01\x01\x12\x1d\n" +
 	"\arequest\x18\x13 \x01(\tH\x06R\arequest\x88\x01\x01\x12\x1f\n" +
 	"\bresponse\x18\x14 \x01(\tH\aR\bresponse\x88\x01\x01\x121\n" +
-	"\x11responseTruncated\x18\x15 \x01(\bH\bR\x11responseTruncated\x88\x01\x01B\f\n" +
+	"\x11responseTruncated\x18\x15 \x01(\bH\bR\x11responseTruncated\x88\x01\x01\x126\n" +
+	"\vinteraction\x18\x16 \x01(\v2\x14.task.VulInteractionR\vinteractionB\f\n" +
 	"\n" +
 	"_cvssScoreB\b\n" +
 	"\x06_cveIdB\b\n" +
@@ -3893,7 +4191,19 @@ const file_task_proto_rawDesc = "" +
 	"\n" +
 	"\b_requestB\v\n" +
 	"\t_responseB\x14\n" +
-	"\x12_responseTruncated\"{\n" +
+	"\x12_responseTruncated\"\x98\x02\n" +
+	"\x0eVulInteraction\x12\x1a\n" +
+	"\bprotocol\x18\x01 \x01(\tR\bprotocol\x12\x1a\n" +
+	"\buniqueId\x18\x02 \x01(\tR\buniqueId\x12\x16\n" +
+	"\x06fullId\x18\x03 \x01(\tR\x06fullId\x12\x14\n" +
+	"\x05qType\x18\x04 \x01(\tR\x05qType\x12\x1e\n" +
+	"\n" +
+	"rawRequest\x18\x05 \x01(\tR\n" +
+	"rawRequest\x12 \n" +
+	"\vrawResponse\x18\x06 \x01(\tR\vrawResponse\x12$\n" +
+	"\rremoteAddress\x18\a \x01(\tR\rremoteAddress\x12\x1a\n" +
+	"\bsmtpFrom\x18\b \x01(\tR\bsmtpFrom\x12\x1c\n" +
+	"\ttimestamp\x18\t \x01(\x03R\ttimestamp\"{\n" +
 	"\x10SaveVulResultReq\x12 \n" +
 	"\vworkspaceId\x18\x01 \x01(\tR\vworkspaceId\x12\x1e\n" +
 	"\n" +
@@ -4178,7 +4488,7 @@ func file_task_proto_rawDescGZIP() []byte {
 	return file_task_proto_rawDescData
 }
 
-var file_task_proto_msgTypes = make([]protoimpl.MessageInfo, 51)
+var file_task_proto_msgTypes = make([]protoimpl.MessageInfo, 52)
 var file_task_proto_goTypes = []any{
 	(*CheckTaskReq)(nil),               // 0: task.CheckTaskReq
 	(*CheckTaskResp)(nil),              // 1: task.CheckTaskResp
@@ -4192,105 +4502,107 @@ var file_task_proto_goTypes = []any{
 	(*SaveTaskResultReq)(nil),          // 9: task.SaveTaskResultReq
 	(*SaveTaskResultResp)(nil),         // 10: task.SaveTaskResultResp
 	(*VulDocument)(nil),                // 11: task.VulDocument
-	(*SaveVulResultReq)(nil),           // 12: task.SaveVulResultReq
-	(*SaveVulResultResp)(nil),          // 13: task.SaveVulResultResp
-	(*KeepAliveReq)(nil),               // 14: task.KeepAliveReq
-	(*KeepAliveResp)(nil),              // 15: task.KeepAliveResp
-	(*GetWorkerConfigReq)(nil),         // 16: task.GetWorkerConfigReq
-	(*GetWorkerConfigResp)(nil),        // 17: task.GetWorkerConfigResp
-	(*RequestResourceReq)(nil),         // 18: task.RequestResourceReq
-	(*RequestResourceResp)(nil),        // 19: task.RequestResourceResp
-	(*GetTemplatesByTagsReq)(nil),      // 20: task.GetTemplatesByTagsReq
-	(*GetTemplatesByTagsResp)(nil),     // 21: task.GetTemplatesByTagsResp
-	(*GetCustomFingerprintsReq)(nil),   // 22: task.GetCustomFingerprintsReq
-	(*FingerprintDocument)(nil),        // 23: task.FingerprintDocument
-	(*GetCustomFingerprintsResp)(nil),  // 24: task.GetCustomFingerprintsResp
-	(*ValidateFingerprintReq)(nil),     // 25: task.ValidateFingerprintReq
-	(*MatchedFingerprintInfo)(nil),     // 26: task.MatchedFingerprintInfo
-	(*ValidateFingerprintResp)(nil),    // 27: task.ValidateFingerprintResp
-	(*ValidatePocReq)(nil),             // 28: task.ValidatePocReq
-	(*PocValidationResult)(nil),        // 29: task.PocValidationResult
-	(*ValidatePocResp)(nil),            // 30: task.ValidatePocResp
-	(*BatchValidatePocReq)(nil),        // 31: task.BatchValidatePocReq
-	(*BatchValidatePocResp)(nil),       // 32: task.BatchValidatePocResp
-	(*GetPocValidationResultReq)(nil),  // 33: task.GetPocValidationResultReq
-	(*GetPocValidationResultResp)(nil), // 34: task.GetPocValidationResultResp
-	(*GetPocByIdReq)(nil),              // 35: task.GetPocByIdReq
-	(*GetPocByIdResp)(nil),             // 36: task.GetPocByIdResp
-	(*GetTemplatesByIdsReq)(nil),       // 37: task.GetTemplatesByIdsReq
-	(*GetTemplatesByIdsResp)(nil),      // 38: task.GetTemplatesByIdsResp
-	(*GetHttpServiceMappingsReq)(nil),  // 39: task.GetHttpServiceMappingsReq
-	(*HttpServiceMappingDocument)(nil), // 40: task.HttpServiceMappingDocument
-	(*GetHttpServiceMappingsResp)(nil), // 41: task.GetHttpServiceMappingsResp
-	(*GetSubfinderProvidersReq)(nil),   // 42: task.GetSubfinderProvidersReq
-	(*SubfinderProviderDocument)(nil),  // 43: task.SubfinderProviderDocument
-	(*GetSubfinderProvidersResp)(nil),  // 44: task.GetSubfinderProvidersResp
-	(*IncrSubTaskDoneReq)(nil),         // 45: task.IncrSubTaskDoneReq
-	(*IncrSubTaskDoneResp)(nil),        // 46: task.IncrSubTaskDoneResp
-	nil,                                // 47: task.FingerprintDocument.HeadersEntry
-	nil,                                // 48: task.FingerprintDocument.CookiesEntry
-	nil,                                // 49: task.FingerprintDocument.MetaEntry
-	nil,                                // 50: task.BatchValidatePocResp.UrlStatsEntry
+	(*VulInteraction)(nil),             // 12: task.VulInteraction
+	(*SaveVulResultReq)(nil),           // 13: task.SaveVulResultReq
+	(*SaveVulResultResp)(nil),          // 14: task.SaveVulResultResp
+	(*KeepAliveReq)(nil),               // 15: task.KeepAliveReq
+	(*KeepAliveResp)(nil),              // 16: task.KeepAliveResp
+	(*GetWorkerConfigReq)(nil),         // 17: task.GetWorkerConfigReq
+	(*GetWorkerConfigResp)(nil),        // 18: task.GetWorkerConfigResp
+	(*RequestResourceReq)(nil),         // 19: task.RequestResourceReq
+	(*RequestResourceResp)(nil),        // 20: task.RequestResourceResp
+	(*GetTemplatesByTagsReq)(nil),      // 21: task.GetTemplatesByTagsReq
+	(*GetTemplatesByTagsResp)(nil),     // 22: task.GetTemplatesByTagsResp
+	(*GetCustomFingerprintsReq)(nil),   // 23: task.GetCustomFingerprintsReq
+	(*FingerprintDocument)(nil),        // 24: task.FingerprintDocument
+	(*GetCustomFingerprintsResp)(nil),  // 25: task.GetCustomFingerprintsResp
+	(*ValidateFingerprintReq)(nil),     // 26: task.ValidateFingerprintReq
+	(*MatchedFingerprintInfo)(nil),     // 27: task.MatchedFingerprintInfo
+	(*ValidateFingerprintResp)(nil),    // 28: task.ValidateFingerprintResp
+	(*ValidatePocReq)(nil),             // 29: task.ValidatePocReq
+	(*PocValidationResult)(nil),        // 30: task.PocValidationResult
+	(*ValidatePocResp)(nil),            // 31: task.ValidatePocResp
+	(*BatchValidatePocReq)(nil),        // 32: task.BatchValidatePocReq
+	(*BatchValidatePocResp)(nil),       // 33: task.BatchValidatePocResp
+	(*GetPocValidationResultReq)(nil),  // 34: task.GetPocValidationResultReq
+	(*GetPocValidationResultResp)(nil), // 35: task.GetPocValidationResultResp
+	(*GetPocByIdReq)(nil),              // 36: task.GetPocByIdReq
+	(*GetPocByIdResp)(nil),             // 37: task.GetPocByIdResp
+	(*GetTemplatesByIdsReq)(nil),       // 38: task.GetTemplatesByIdsReq
+	(*GetTemplatesByIdsResp)(nil),      // 39: task.GetTemplatesByIdsResp
+	(*GetHttpServiceMappingsReq)(nil),  // 40: task.GetHttpServiceMappingsReq
+	(*HttpServiceMappingDocument)(nil), // 41: task.HttpServiceMappingDocument
+	(*GetHttpServiceMappingsResp)(nil), // 42: task.GetHttpServiceMappingsResp
+	(*GetSubfinderProvidersReq)(nil),   // 43: task.GetSubfinderProvidersReq
+	(*SubfinderProviderDocument)(nil),  // 44: task.SubfinderProviderDocument
+	(*GetSubfinderProvidersResp)(nil),  // 45: task.GetSubfinderProvidersResp
+	(*IncrSubTaskDoneReq)(nil),         // 46: task.IncrSubTaskDoneReq
+	(*IncrSubTaskDoneResp)(nil),        // 47: task.IncrSubTaskDoneResp
+	nil,                                // 48: task.FingerprintDocument.HeadersEntry
+	nil,                                // 49: task.FingerprintDocument.CookiesEntry
+	nil,                                // 50: task.FingerprintDocument.MetaEntry
+	nil,                                // 51: task.BatchValidatePocResp.UrlStatsEntry
 }
 var file_task_proto_depIdxs = []int32{
 	7,  // 0: task.AssetDocument.ipv4:type_name -> task.IPV4
 	8,  // 1: task.AssetDocument.ipv6:type_name -> task.IPV6
 	6,  // 2: task.SaveTaskResultReq.assets:type_name -> task.AssetDocument
-	11, // 3: task.SaveVulResultReq.vuls:type_name -> task.VulDocument
-	47, // 4: task.FingerprintDocument.headers:type_name -> task.FingerprintDocument.HeadersEntry
-	48, // 5: task.FingerprintDocument.cookies:type_name -> task.FingerprintDocument.CookiesEntry
-	49, // 6: task.FingerprintDocument.meta:type_name -> task.FingerprintDocument.MetaEntry
-	23, // 7: task.GetCustomFingerprintsResp.fingerprints:type_name -> task.FingerprintDocument
-	26, // 8: task.ValidateFingerprintResp.matchedList:type_name -> task.MatchedFingerprintInfo
-	29, // 9: task.ValidatePocResp.results:type_name -> task.PocValidationResult
-	29, // 10: task.BatchValidatePocResp.results:type_name -> task.PocValidationResult
-	50, // 11: task.BatchValidatePocResp.urlStats:type_name -> task.BatchValidatePocResp.UrlStatsEntry
-	29, // 12: task.GetPocValidationResultResp.results:type_name -> task.PocValidationResult
-	40, // 13: task.GetHttpServiceMappingsResp.mappings:type_name -> task.HttpServiceMappingDocument
-	43, // 14: task.GetSubfinderProvidersResp.providers:type_name -> task.SubfinderProviderDocument
-	0,  // 15: task.TaskService.CheckTask:input_type -> task.CheckTaskReq
-	2,  // 16: task.TaskService.UpdateTask:input_type -> task.UpdateTaskReq
-	4,  // 17: task.TaskService.NewTask:input_type -> task.NewTaskReq
-	9,  // 18: task.TaskService.SaveTaskResult:input_type -> task.SaveTaskResultReq
-	12, // 19: task.TaskService.SaveVulResult:input_type -> task.SaveVulResultReq
-	14, // 20: task.TaskService.KeepAlive:input_type -> task.KeepAliveReq
-	16, // 21: task.TaskService.GetWorkerConfig:input_type -> task.GetWorkerConfigReq
-	18, // 22: task.TaskService.RequestResource:input_type -> task.RequestResourceReq
-	20, // 23: task.TaskService.GetTemplatesByTags:input_type -> task.GetTemplatesByTagsReq
-	22, // 24: task.TaskService.GetCustomFingerprints:input_type -> task.GetCustomFingerprintsReq
-	25, // 25: task.TaskService.ValidateFingerprint:input_type -> task.ValidateFingerprintReq
-	28, // 26: task.TaskService.ValidatePoc:input_type -> task.ValidatePocReq
-	31, // 27: task.TaskService.BatchValidatePoc:input_type -> task.BatchValidatePocReq
-	33, // 28: task.TaskService.GetPocValidationResult:input_type -> task.GetPocValidationResultReq
-	35, // 29: task.TaskService.GetPocById:input_type -> task.GetPocByIdReq
-	37, // 30: task.TaskService.GetTemplatesByIds:input_type -> task.GetTemplatesByIdsReq
-	39, // 31: task.TaskService.GetHttpServiceMappings:input_type -> task.GetHttpServiceMappingsReq
-	42, // 32: task.TaskService.GetSubfinderProviders:input_type -> task.GetSubfinderProvidersReq
-	45, // 33: task.TaskService.IncrSubTaskDone:input_type -> task.IncrSubTaskDoneReq
-	1,  // 34: task.TaskService.CheckTask:output_type -> task.CheckTaskResp
-	3,  // 35: task.TaskService.UpdateTask:output_type -> task.UpdateTaskResp
-	5,  // 36: task.TaskService.NewTask:output_type -> task.NewTaskResp
-	10, // 37: task.TaskService.SaveTaskResult:output_type -> task.SaveTaskResultResp
-	13, // 38: task.TaskService.SaveVulResult:output_type -> task.SaveVulResultResp
-	15, // 39: task.TaskService.KeepAlive:output_type -> task.KeepAliveResp
-	17, // 40: task.TaskService.GetWorkerConfig:output_type -> task.GetWorkerConfigResp
-	19, // 41: task.TaskService.RequestResource:output_type -> task.RequestResourceResp
-	21, // 42: task.TaskService.GetTemplatesByTags:output_type -> task.GetTemplatesByTagsResp
-	24, // 43: task.TaskService.GetCustomFingerprints:output_type -> task.GetCustomFingerprintsResp
-	27, // 44: task.TaskService.ValidateFingerprint:output_type -> task.ValidateFingerprintResp
-	30, // 45: task.TaskService.ValidatePoc:output_type -> task.ValidatePocResp
-	32, // 46: task.TaskService.BatchValidatePoc:output_type -> task.BatchValidatePocResp
-	34, // 47: task.TaskService.GetPocValidationResult:output_type -> task.GetPocValidationResultResp
-	36, // 48: task.TaskService.GetPocById:output_type -> task.GetPocByIdResp
-	38, // 49: task.TaskService.GetTemplatesByIds:output_type -> task.GetTemplatesByIdsResp
-	41, // 50: task.TaskService.GetHttpServiceMappings:output_type -> task.GetHttpServiceMappingsResp
-	44, // 51: task.TaskService.GetSubfinderProviders:output_type -> task.GetSubfinderProvidersResp
-	46, // 52: task.TaskService.IncrSubTaskDone:output_type -> task.IncrSubTaskDoneResp
-	34, // [34:53] is the sub-list for method output_type
-	15, // [15:34] is the sub-list for method input_type
-	15, // [15:15] is the sub-list for extension type_name
-	15, // [15:15] is the sub-list for extension extendee
-	0,  // [0:15] is the sub-list for field type_name
+	12, // 3: task.VulDocument.interaction:type_name -> task.VulInteraction
+	11, // 4: task.SaveVulResultReq.vuls:type_name -> task.VulDocument
+	48, // 5: task.FingerprintDocument.headers:type_name -> task.FingerprintDocument.HeadersEntry
+	49, // 6: task.FingerprintDocument.cookies:type_name -> task.FingerprintDocument.CookiesEntry
+	50, // 7: task.FingerprintDocument.meta:type_name -> task.FingerprintDocument.MetaEntry
+	24, // 8: task.GetCustomFingerprintsResp.fingerprints:type_name -> task.FingerprintDocument
+	27, // 9: task.ValidateFingerprintResp.matchedList:type_name -> task.MatchedFingerprintInfo
+	30, // 10: task.ValidatePocResp.results:type_name -> task.PocValidationResult
+	30, // 11: task.BatchValidatePocResp.results:type_name -> task.PocValidationResult
+	51, // 12: task.BatchValidatePocResp.urlStats:type_name -> task.BatchValidatePocResp.UrlStatsEntry
+	30, // 13: task.GetPocValidationResultResp.results:type_name -> task.PocValidationResult
+	41, // 14: task.GetHttpServiceMappingsResp.mappings:type_name -> task.HttpServiceMappingDocument
+	44, // 15: task.GetSubfinderProvidersResp.providers:type_name -> task.SubfinderProviderDocument
+	0,  // 16: task.TaskService.CheckTask:input_type -> task.CheckTaskReq
+	2,  // 17: task.TaskService.UpdateTask:input_type -> task.UpdateTaskReq
+	4,  // 18: task.TaskService.NewTask:input_type -> task.NewTaskReq
+	9,  // 19: task.TaskService.SaveTaskResult:input_type -> task.SaveTaskResultReq
+	13, // 20: task.TaskService.SaveVulResult:input_type -> task.SaveVulResultReq
+	15, // 21: task.TaskService.KeepAlive:input_type -> task.KeepAliveReq
+	17, // 22: task.TaskService.GetWorkerConfig:input_type -> task.GetWorkerConfigReq
+	19, // 23: task.TaskService.RequestResource:input_type -> task.RequestResourceReq
+	21, // 24: task.TaskService.GetTemplatesByTags:input_type -> task.GetTemplatesByTagsReq
+	23, // 25: task.TaskService.GetCustomFingerprints:input_type -> task.GetCustomFingerprintsReq
+	26, // 26: task.TaskService.ValidateFingerprint:input_type -> task.ValidateFingerprintReq
+	29, // 27: task.TaskService.ValidatePoc:input_type -> task.ValidatePocReq
+	32, // 28: task.TaskService.BatchValidatePoc:input_type -> task.BatchValidatePocReq
+	34, // 29: task.TaskService.GetPocValidationResult:input_type -> task.GetPocValidationResultReq
+	36, // 30: task.TaskService.GetPocById:input_type -> task.GetPocByIdReq
+	38, // 31: task.TaskService.GetTemplatesByIds:input_type -> task.GetTemplatesByIdsReq
+	40, // 32: task.TaskService.GetHttpServiceMappings:input_type -> task.GetHttpServiceMappingsReq
+	43, // 33: task.TaskService.GetSubfinderProviders:input_type -> task.GetSubfinderProvidersReq
+	46, // 34: task.TaskService.IncrSubTaskDone:input_type -> task.IncrSubTaskDoneReq
+	1,  // 35: task.TaskService.CheckTask:output_type -> task.CheckTaskResp
+	3,  // 36: task.TaskService.UpdateTask:output_type -> task.UpdateTaskResp
+	5,  // 37: task.TaskService.NewTask:output_type -> task.NewTaskResp
+	10, // 38: task.TaskService.SaveTaskResult:output_type -> task.SaveTaskResultResp
+	14, // 39: task.TaskService.SaveVulResult:output_type -> task.SaveVulResultResp
+	16, // 40: task.TaskService.KeepAlive:output_type -> task.KeepAliveResp
+	18, // 41: task.TaskService.GetWorkerConfig:output_type -> task.GetWorkerConfigResp
+	20, // 42: task.TaskService.RequestResource:output_type -> task.RequestResourceResp
+	22, // 43: task.TaskService.GetTemplatesByTags:output_type -> task.GetTemplatesByTagsResp
+	25, // 44: task.TaskService.GetCustomFingerprints:output_type -> task.GetCustomFingerprintsResp
+	28, // 45: task.TaskService.ValidateFingerprint:output_type -> task.ValidateFingerprintResp
+	31, // 46: task.TaskService.ValidatePoc:output_type -> task.ValidatePocResp
+	33, // 47: task.TaskService.BatchValidatePoc:output_type -> task.BatchValidatePocResp
+	35, // 48: task.TaskService.GetPocValidationResult:output_type -> task.GetPocValidationResultResp
+	37, // 49: task.TaskService.GetPocById:output_type -> task.GetPocByIdResp
+	39, // 50: task.TaskService.GetTemplatesByIds:output_type -> task.GetTemplatesByIdsResp
+	42, // 51: task.TaskService.GetHttpServiceMappings:output_type -> task.GetHttpServiceMappingsResp
+	45, // 52: task.TaskService.GetSubfinderProviders:output_type -> task.GetSubfinderProvidersResp
+	47, // 53: task.TaskService.IncrSubTaskDone:output_type -> task.IncrSubTaskDoneResp
+	35, // [35:54] is the sub-list for method output_type
+	16, // [16:35] is the sub-list for method input_type
+	16, // [16:16] is the sub-list for extension type_name
+	16, // [16:16] is the sub-list for extension extendee
+	0,  // [0:16] is the sub-list for field type_name
 }
 
 func init() { file_task_proto_init() }
@@ -4305,7 +4617,7 @@ func file_task_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_task_proto_rawDesc), len(file_task_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   51,
+			NumMessages:   52,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
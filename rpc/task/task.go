@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 
@@ -25,6 +26,9 @@ func main() {
 	conf.MustLoad(*configFile, &c)
 	ctx := svc.NewServiceContext(c)
 
+	// 启动Webhook投递和失败重试
+	ctx.Webhook.Start(context.Background())
+
 	// 增加gRPC消息大小限制到50MB，支持大量指纹数据传输
 	s := zrpc.MustNewServer(c.RpcServerConf, func(grpcServer *grpc.Server) {
 		pb.RegisterTaskServiceServer(grpcServer, server.NewTaskServiceServer(ctx))
@@ -0,0 +1,483 @@
+package scanner
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 弱口令爆破支持的服务
+const (
+	BruteSSH       = "ssh"
+	BruteFTP       = "ftp"
+	BruteMySQL     = "mysql"
+	BruteMSSQL     = "mssql"
+	BrutePostgres  = "postgresql"
+	BruteRedis     = "redis"
+	BruteMongoDB   = "mongodb"
+	BruteSMB       = "smb"
+	BruteRDP       = "rdp"
+	BruteTelnet    = "telnet"
+	BruteTomcat    = "tomcat"
+	BruteHTTPBasic = "http-basic"
+)
+
+// BruteServices 支持的全部服务
+var BruteServices = []string{
+	BruteSSH, BruteFTP, BruteMySQL, BruteMSSQL, BrutePostgres, BruteRedis,
+	BruteMongoDB, BruteSMB, BruteRDP, BruteTelnet, BruteTomcat, BruteHTTPBasic,
+}
+
+// BruteAny 字典适用于所有服务时使用的键
+const BruteAny = "*"
+
+// 登录结果分类
+var (
+	// errBruteAuth 用户名或密码错误
+	errBruteAuth = errors.New("authentication failed")
+	// errBruteLocked 账号已被锁定，继续尝试只会延长锁定
+	errBruteLocked = errors.New("account locked")
+	// errBruteBlocked 服务端已屏蔽本机（如 MySQL host blocked），应停止该目标
+	errBruteBlocked = errors.New("client blocked")
+	// errBruteUnsupported 服务配置不支持口令认证（如 RDP 未启用 NLA）
+	errBruteUnsupported = errors.New("password auth unsupported")
+)
+
+// 容易触发账号锁定的服务，默认限制单个用户名的尝试次数
+var bruteLockoutServices = map[string]bool{BruteSMB: true, BruteRDP: true, BruteMSSQL: true}
+
+// BruteOptions 弱口令爆破选项
+type BruteOptions struct {
+	Services         []string            `json:"services"`         // 限定爆破的服务，空为全部
+	Usernames        map[string][]string `json:"usernames"`        // 按服务的用户名字典，BruteAny 为通用
+	Passwords        map[string][]string `json:"passwords"`        // 按服务的密码字典，BruteAny 为通用
+	Threads          int                 `json:"threads"`          // 同时爆破的目标数，默认10
+	Timeout          int                 `json:"timeout"`          // 单次登录超时(秒)，默认5秒
+	Interval         int                 `json:"interval"`         // 同一目标两次尝试的间隔(毫秒)，默认0
+	MaxAttempts      int                 `json:"maxAttempts"`      // 单个目标最大尝试次数，0为不限
+	LockoutThreshold int                 `json:"lockoutThreshold"` // 易锁定服务(SMB/RDP/MSSQL)单个用户名最大尝试次数，默认3，-1不限
+	StopOnSuccess    bool                `json:"stopOnSuccess"`    // 目标发现一组口令后停止
+	UsernameDictIds  []string            `json:"usernameDictIds"`  // 工作空间用户名字典ID，由 Worker 加载到 Usernames
+	PasswordDictIds  []string            `json:"passwordDictIds"`  // 工作空间密码字典ID，由 Worker 加载到 Passwords
+}
+
+// BruteCredential 爆破成功的凭据，密码已脱敏
+type BruteCredential struct {
+	Service  string `json:"service"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// BruteScanner 弱口令爆破扫描器
+type BruteScanner struct {
+	BaseScanner
+}
+
+func init() {
+	Register("brute", Registration{
+		New:     func() Scanner { return NewBruteScanner() },
+		Options: func() interface{} { return &BruteOptions{} },
+	})
+}
+
+// NewBruteScanner 创建弱口令爆破扫描器
+func NewBruteScanner() *BruteScanner {
+	return &BruteScanner{
+		BaseScanner: BaseScanner{name: "brute"},
+	}
+}
+
+// bruteTarget 爆破目标
+type bruteTarget struct {
+	asset   *Asset
+	service string
+	host    string
+	port    int
+	url     string // tomcat / http-basic 的认证地址
+	timeout time.Duration
+}
+
+func (t *bruteTarget) addr() string {
+	return net.JoinHostPort(t.host, strconv.Itoa(t.port))
+}
+
+// bruteLoginFunc 尝试一次登录，成功返回 nil，口令错误返回 errBruteAuth
+type bruteLoginFunc func(ctx context.Context, t *bruteTarget, user, pass string) error
+
+var bruteLogins = map[string]bruteLoginFunc{
+	BruteSSH:       loginSSH,
+	BruteFTP:       loginFTP,
+	BruteMySQL:     loginMySQL,
+	BruteMSSQL:     loginMSSQL,
+	BrutePostgres:  loginPostgres,
+	BruteRedis:     loginRedis,
+	BruteMongoDB:   loginMongoDB,
+	BruteSMB:       loginSMB,
+	BruteRDP:       loginRDP,
+	BruteTelnet:    loginTelnet,
+	BruteTomcat:    loginHTTPBasic,
+	BruteHTTPBasic: loginHTTPBasic,
+}
+
+// 服务默认用户名
+var bruteDefaultUsers = map[string][]string{
+	BruteSSH:       {"root", "admin", "ubuntu", "test"},
+	BruteFTP:       {"anonymous", "ftp", "admin", "root"},
+	BruteMySQL:     {"root", "mysql"},
+	BruteMSSQL:     {"sa"},
+	BrutePostgres:  {"postgres"},
+	BruteRedis:     {""},
+	BruteMongoDB:   {"", "admin", "root"},
+	BruteSMB:       {"administrator", "admin"},
+	BruteRDP:       {"administrator", "admin"},
+	BruteTelnet:    {"root", "admin"},
+	BruteTomcat:    {"tomcat", "admin", "manager"},
+	BruteHTTPBasic: {"admin", "root"},
+}
+
+// 支持未授权访问的服务，空用户名和空密码即可登录
+var bruteNoAuthServices = map[string]bool{
+	BruteRedis:   true,
+	BruteMongoDB: true,
+}
+
+// 默认密码，%user% 替换为当前用户名
+var bruteDefaultPasswords = []string{
+	"", "%user%", "%user%123", "%user%@123", "123456", "12345678", "password",
+	"admin", "admin123", "Admin@123", "root", "toor", "P@ssw0rd", "Passw0rd",
+	"1qaz@WSX", "qwerty", "111111", "000000", "abc123", "test",
+}
+
+// 服务名（nmap/内置探测识别结果）到爆破服务的映射
+var bruteServiceAlias = map[string]string{
+	"ssh":            BruteSSH,
+	"ftp":            BruteFTP,
+	"mysql":          BruteMySQL,
+	"mariadb":        BruteMySQL,
+	"ms-sql-s":       BruteMSSQL,
+	"mssql":          BruteMSSQL,
+	"postgresql":     BrutePostgres,
+	"postgres":       BrutePostgres,
+	"redis":          BruteRedis,
+	"mongodb":        BruteMongoDB,
+	"mongod":         BruteMongoDB,
+	"microsoft-ds":   BruteSMB,
+	"smb":            BruteSMB,
+	"ms-wbt-server":  BruteRDP,
+	"rdp":            BruteRDP,
+	"telnet":         BruteTelnet,
+	"http-basic":     BruteHTTPBasic,
+	"tomcat":         BruteTomcat,
+	"tomcat-manager": BruteTomcat,
+}
+
+// 未识别服务时按默认端口推断
+var bruteDefaultPorts = map[int]string{
+	22: BruteSSH, 21: BruteFTP, 3306: BruteMySQL, 1433: BruteMSSQL, 5432: BrutePostgres,
+	6379: BruteRedis, 27017: BruteMongoDB, 445: BruteSMB, 3389: BruteRDP, 23: BruteTelnet,
+}
+
+// bruteServiceOf 推断资产对应的爆破服务
+func bruteServiceOf(asset *Asset) string {
+	if asset.Transport == TransportUDP {
+		return ""
+	}
+	if asset.IsHTTP {
+		if isTomcat(asset) {
+			return BruteTomcat
+		}
+		if asset.HttpStatus == "401" && strings.Contains(strings.ToLower(asset.HttpHeader), "www-authenticate: basic") {
+			return BruteHTTPBasic
+		}
+		return ""
+	}
+	service := strings.ToLower(strings.TrimPrefix(asset.Service, "ssl/"))
+	if s, ok := bruteServiceAlias[service]; ok {
+		return s
+	}
+	if service == "" || service == "unknown" || service == "tcpwrapped" {
+		return bruteDefaultPorts[asset.Port]
+	}
+	return ""
+}
+
+func isTomcat(asset *Asset) bool {
+	for _, app := range asset.App {
+		if strings.Contains(strings.ToLower(app), "tomcat") {
+			return true
+		}
+	}
+	return strings.Contains(asset.Title, "Apache Tomcat")
+}
+
+// Scan 执行弱口令爆破，发现的凭据以漏洞形式返回，密码已脱敏
+func (s *BruteScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	opts, ok := config.Options.(*BruteOptions)
+	if !ok || opts == nil {
+		opts = &BruteOptions{}
+	}
+	if opts.Threads <= 0 {
+		opts.Threads = 10
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5
+	}
+	if opts.LockoutThreshold == 0 {
+		opts.LockoutThreshold = 3
+	}
+
+	logf := func(level, format string, args ...interface{}) {
+		if config.TaskLogger != nil {
+			config.TaskLogger(level, format, args...)
+		}
+	}
+
+	allowed := make(map[string]bool)
+	for _, svc := range opts.Services {
+		allowed[svc] = true
+	}
+	var targets []*bruteTarget
+	for _, asset := range config.Assets {
+		svc := bruteServiceOf(asset)
+		if svc == "" || (len(allowed) > 0 && !allowed[svc]) {
+			continue
+		}
+		t := &bruteTarget{
+			asset:   asset,
+			service: svc,
+			host:    asset.Host,
+			port:    asset.Port,
+			timeout: time.Duration(opts.Timeout) * time.Second,
+		}
+		if svc == BruteTomcat || svc == BruteHTTPBasic {
+			t.url = httpAuthURL(asset, svc)
+		}
+		targets = append(targets, t)
+	}
+	logf("INFO", "Brute: %d targets", len(targets))
+
+	result := &ScanResult{WorkspaceId: config.WorkspaceId, MainTaskId: config.MainTaskId}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var done int
+	sem := make(chan struct{}, opts.Threads)
+	for _, t := range targets {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(t *bruteTarget) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			creds := bruteTargetRun(ctx, t, opts, logf)
+			mu.Lock()
+			defer mu.Unlock()
+			for _, c := range creds {
+				result.Vulnerabilities = append(result.Vulnerabilities, bruteVul(t, c))
+			}
+			done++
+			if config.OnProgress != nil {
+				config.OnProgress(done*100/len(targets), fmt.Sprintf("弱口令爆破 %d/%d", done, len(targets)))
+			}
+		}(t)
+	}
+	wg.Wait()
+
+	logf("INFO", "Brute completed: %d weak credentials", len(result.Vulnerabilities))
+	return result, nil
+}
+
+// bruteTargetRun 对单个目标顺序尝试口令。
+// 同一目标串行尝试并按 Interval 限速；易锁定服务限制单个用户名的尝试次数；
+// 连续网络错误时指数退避，超过3次放弃该目标
+func bruteTargetRun(ctx context.Context, t *bruteTarget, opts *BruteOptions, logf func(level, format string, args ...interface{})) []BruteCredential {
+	login := bruteLogins[t.service]
+	if login == nil {
+		return nil
+	}
+
+	// 用随机口令试探，能登录说明服务接受任意口令（如 SMB 来宾映射），结果不可信
+	canary := randomToken()
+	switch err := login(ctx, t, "cscan"+canary[:6], canary); {
+	case err == nil:
+		logf("WARN", "Brute %s %s: accepts any credential, skipped", t.service, t.addr())
+		return nil
+	case errors.Is(err, errBruteAuth), errors.Is(err, errBruteLocked):
+	default:
+		logf("INFO", "Brute %s %s: skipped: %v", t.service, t.addr(), err)
+		return nil
+	}
+
+	var creds []BruteCredential
+	attempts := 0
+	netErrors := 0
+	passwords := bruteWords(opts.Passwords, t.service, bruteDefaultPasswords)
+	users := bruteWords(opts.Usernames, t.service, bruteDefaultUsers[t.service])
+
+	// 自定义字典不含空用户名和空密码时，仍先检测一次未授权访问
+	if bruteNoAuthServices[t.service] && !(slices.Contains(users, "") && slices.Contains(passwords, "")) {
+		attempts++
+		if err := login(ctx, t, "", ""); err == nil {
+			logf("WARN", "Brute %s %s: unauthorized access", t.service, t.addr())
+			creds = append(creds, BruteCredential{Service: t.service, Password: MaskPassword("")})
+			if opts.StopOnSuccess {
+				return creds
+			}
+		}
+	}
+
+	for _, user := range users {
+		userAttempts := 0
+		for _, pattern := range passwords {
+			if ctx.Err() != nil {
+				return creds
+			}
+			if opts.MaxAttempts > 0 && attempts >= opts.MaxAttempts {
+				logf("INFO", "Brute %s %s: max attempts %d reached", t.service, t.addr(), opts.MaxAttempts)
+				return creds
+			}
+			if bruteLockoutServices[t.service] && opts.LockoutThreshold > 0 && userAttempts >= opts.LockoutThreshold {
+				break
+			}
+			if attempts > 0 && opts.Interval > 0 && !sleepCtx(ctx, time.Duration(opts.Interval)*time.Millisecond) {
+				return creds
+			}
+			pass := strings.ReplaceAll(pattern, "%user%", user)
+			attempts++
+			userAttempts++
+
+			err := login(ctx, t, user, pass)
+			switch {
+			case err == nil:
+				netErrors = 0
+				logf("WARN", "Brute %s %s: weak credential %s:%s", t.service, t.addr(), user, MaskPassword(pass))
+				creds = append(creds, BruteCredential{Service: t.service, Username: user, Password: MaskPassword(pass)})
+				if opts.StopOnSuccess {
+					return creds
+				}
+			case errors.Is(err, errBruteAuth):
+				netErrors = 0
+				continue
+			case errors.Is(err, errBruteLocked):
+				logf("WARN", "Brute %s %s: account %s locked, skipped", t.service, t.addr(), user)
+			case errors.Is(err, errBruteBlocked), errors.Is(err, errBruteUnsupported):
+				logf("WARN", "Brute %s %s: %v, stopped", t.service, t.addr(), err)
+				return creds
+			default:
+				netErrors++
+				if netErrors > 3 {
+					logf("WARN", "Brute %s %s: too many errors, stopped: %v", t.service, t.addr(), err)
+					return creds
+				}
+				if !sleepCtx(ctx, time.Duration(1<<netErrors)*time.Second) {
+					return creds
+				}
+				continue
+			}
+			// 成功或账号锁定后换下一个用户名
+			break
+		}
+	}
+	return creds
+}
+
+// bruteWords 合并服务专用字典和通用字典，均为空时使用默认值
+func bruteWords(dicts map[string][]string, service string, defaults []string) []string {
+	var words []string
+	seen := make(map[string]bool)
+	for _, list := range [][]string{dicts[service], dicts[BruteAny]} {
+		for _, w := range list {
+			if !seen[w] {
+				seen[w] = true
+				words = append(words, w)
+			}
+		}
+	}
+	if len(words) == 0 {
+		return defaults
+	}
+	return words
+}
+
+// MaskPassword 密码脱敏，只保留首尾字符
+func MaskPassword(pass string) string {
+	switch n := len([]rune(pass)); {
+	case n == 0:
+		return "<empty>"
+	case n <= 3:
+		return strings.Repeat("*", n)
+	default:
+		r := []rune(pass)
+		return string(r[0]) + strings.Repeat("*", n-2) + string(r[n-1])
+	}
+}
+
+// bruteVul 转换爆破结果为漏洞
+func bruteVul(t *bruteTarget, c BruteCredential) *Vulnerability {
+	extra, _ := json.Marshal(c)
+	user := c.Username
+	if user == "" {
+		user = "<none>"
+	}
+	url := t.url
+	if url == "" {
+		url = fmt.Sprintf("%s://%s", t.service, t.addr())
+	}
+	return &Vulnerability{
+		Authority:   t.asset.Authority,
+		Host:        t.host,
+		Port:        t.port,
+		Url:         url,
+		PocFile:     "brute-" + t.service,
+		Source:      "brute",
+		Severity:    "high",
+		Extra:       string(extra),
+		Result:      fmt.Sprintf("%s 弱口令 %s:%s", t.service, user, c.Password),
+		Remediation: "修改为强口令，限制服务的访问来源并启用登录失败锁定",
+	}
+}
+
+// httpAuthURL Tomcat 爆破管理后台，HTTP Basic 爆破资产地址
+func httpAuthURL(asset *Asset, service string) string {
+	scheme := "http"
+	if strings.HasPrefix(asset.Service, "https") || strings.HasPrefix(asset.Service, "ssl/") || asset.Port == 443 {
+		scheme = "https"
+	}
+	authority := asset.Authority
+	if authority == "" {
+		authority = net.JoinHostPort(asset.Host, strconv.Itoa(asset.Port))
+	}
+	base := scheme + "://" + authority
+	if service == BruteTomcat {
+		return base + "/manager/html"
+	}
+	return base + "/"
+}
+
+func randomToken() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// sleepCtx 可取消的等待，ctx 取消时返回 false
+func sleepCtx(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
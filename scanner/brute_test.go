@@ -0,0 +1,277 @@
+package scanner
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestBruteWords 测试服务字典与通用字典合并去重，空字符串是合法条目，均为空时使用默认值
+func TestBruteWords(t *testing.T) {
+	defaults := []string{"root"}
+	tests := []struct {
+		name  string
+		dicts map[string][]string
+		want  []string
+	}{
+		{"nil dicts", nil, defaults},
+		{"other service only", map[string][]string{BruteMySQL: {"mysql"}}, defaults},
+		{"service only", map[string][]string{BruteSSH: {"ubuntu", "admin"}}, []string{"ubuntu", "admin"}},
+		{"any only", map[string][]string{BruteAny: {"admin"}}, []string{"admin"}},
+		{"service before any, deduped", map[string][]string{BruteSSH: {"admin", "ubuntu"}, BruteAny: {"root", "admin"}}, []string{"admin", "ubuntu", "root"}},
+		{"empty entry kept", map[string][]string{BruteAny: {"", "admin", ""}}, []string{"", "admin"}},
+	}
+	for _, tt := range tests {
+		if got := bruteWords(tt.dicts, BruteSSH, defaults); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: bruteWords() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestBruteServiceOf 测试按服务名、HTTP 特征和默认端口识别爆破服务
+func TestBruteServiceOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		asset *Asset
+		want  string
+	}{
+		{"ssh", &Asset{Port: 2222, Service: "ssh"}, BruteSSH},
+		{"ssl prefix", &Asset{Port: 9999, Service: "ssl/ms-sql-s"}, BruteMSSQL},
+		{"alias case", &Asset{Port: 3307, Service: "MariaDB"}, BruteMySQL},
+		{"unknown by port", &Asset{Port: 6379, Service: "unknown"}, BruteRedis},
+		{"tcpwrapped by port", &Asset{Port: 445, Service: "tcpwrapped"}, BruteSMB},
+		{"empty by port", &Asset{Port: 22}, BruteSSH},
+		{"identified service wins over port", &Asset{Port: 22, Service: "http-proxy"}, ""},
+		{"unmapped port", &Asset{Port: 12345}, ""},
+		{"udp", &Asset{Port: 161, Service: "ssh", Transport: TransportUDP}, ""},
+		{"tomcat app", &Asset{Port: 8080, IsHTTP: true, App: []string{"Apache Tomcat"}}, BruteTomcat},
+		{"tomcat title", &Asset{Port: 8080, IsHTTP: true, Title: "Apache Tomcat/9.0.65"}, BruteTomcat},
+		{"basic auth", &Asset{Port: 80, IsHTTP: true, HttpStatus: "401", HttpHeader: "WWW-Authenticate: Basic realm=\"x\""}, BruteHTTPBasic},
+		{"digest auth", &Asset{Port: 80, IsHTTP: true, HttpStatus: "401", HttpHeader: "WWW-Authenticate: Digest realm=\"x\""}, ""},
+		{"plain http", &Asset{Port: 80, IsHTTP: true, Service: "http"}, ""},
+	}
+	for _, tt := range tests {
+		if got := bruteServiceOf(tt.asset); got != tt.want {
+			t.Errorf("%s: bruteServiceOf() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// bruteStub 模拟登录，记录每次尝试
+type bruteStub struct {
+	valid    map[string]bool  // 可登录的 user:pass
+	results  map[string]error // 指定 user:pass 或 user 的登录结果
+	anyCred  bool             // 接受任意口令
+	attempts []string
+}
+
+func (s *bruteStub) login(ctx context.Context, t *bruteTarget, user, pass string) error {
+	key := user + ":" + pass
+	if strings.HasPrefix(user, "cscan") {
+		if s.anyCred {
+			return nil
+		}
+		return errBruteAuth
+	}
+	s.attempts = append(s.attempts, key)
+	if err, ok := s.results[key]; ok {
+		return err
+	}
+	if err, ok := s.results[user]; ok {
+		return err
+	}
+	if s.anyCred || s.valid[key] {
+		return nil
+	}
+	return errBruteAuth
+}
+
+// TestBruteTargetRun 测试单个目标的爆破流程：未授权检测、成功后停止、锁定阈值和服务端屏蔽
+func TestBruteTargetRun(t *testing.T) {
+	tests := []struct {
+		name      string
+		service   string
+		stub      *bruteStub
+		opts      *BruteOptions
+		wantCreds []string // user:masked
+		wantTries []string
+	}{
+		{
+			name:    "next user after success",
+			service: BruteSSH,
+			stub:    &bruteStub{valid: map[string]bool{"root:toor": true, "admin:admin": true}},
+			opts: &BruteOptions{
+				Usernames: map[string][]string{BruteAny: {"root", "admin"}},
+				Passwords: map[string][]string{BruteAny: {"toor", "%user%"}},
+			},
+			wantCreds: []string{"root:t**r", "admin:a***n"},
+			wantTries: []string{"root:toor", "admin:toor", "admin:admin"},
+		},
+		{
+			name:    "stop on success",
+			service: BruteSSH,
+			stub:    &bruteStub{valid: map[string]bool{"root:toor": true, "admin:admin": true}},
+			opts: &BruteOptions{
+				Usernames:     map[string][]string{BruteAny: {"root", "admin"}},
+				Passwords:     map[string][]string{BruteAny: {"toor", "%user%"}},
+				StopOnSuccess: true,
+			},
+			wantCreds: []string{"root:t**r"},
+			wantTries: []string{"root:toor"},
+		},
+		{
+			name:    "max attempts",
+			service: BruteSSH,
+			stub:    &bruteStub{},
+			opts: &BruteOptions{
+				Usernames:   map[string][]string{BruteAny: {"root", "admin"}},
+				Passwords:   map[string][]string{BruteAny: {"a", "b"}},
+				MaxAttempts: 3,
+			},
+			wantTries: []string{"root:a", "root:b", "admin:a"},
+		},
+		{
+			name:    "lockout threshold per user",
+			service: BruteSMB,
+			stub:    &bruteStub{},
+			opts: &BruteOptions{
+				Usernames:        map[string][]string{BruteAny: {"administrator", "guest"}},
+				Passwords:        map[string][]string{BruteAny: {"a", "b", "c"}},
+				LockoutThreshold: 2,
+			},
+			wantTries: []string{"administrator:a", "administrator:b", "guest:a", "guest:b"},
+		},
+		{
+			name:    "lockout threshold disabled",
+			service: BruteSMB,
+			stub:    &bruteStub{},
+			opts: &BruteOptions{
+				Usernames:        map[string][]string{BruteAny: {"administrator"}},
+				Passwords:        map[string][]string{BruteAny: {"a", "b", "c"}},
+				LockoutThreshold: -1,
+			},
+			wantTries: []string{"administrator:a", "administrator:b", "administrator:c"},
+		},
+		{
+			name:    "threshold ignored for non-lockout service",
+			service: BruteSSH,
+			stub:    &bruteStub{},
+			opts: &BruteOptions{
+				Usernames:        map[string][]string{BruteAny: {"root"}},
+				Passwords:        map[string][]string{BruteAny: {"a", "b", "c"}},
+				LockoutThreshold: 1,
+			},
+			wantTries: []string{"root:a", "root:b", "root:c"},
+		},
+		{
+			name:    "locked account skipped",
+			service: BruteSSH,
+			stub:    &bruteStub{results: map[string]error{"root": errBruteLocked}, valid: map[string]bool{"admin:b": true}},
+			opts: &BruteOptions{
+				Usernames: map[string][]string{BruteAny: {"root", "admin"}},
+				Passwords: map[string][]string{BruteAny: {"a", "b"}},
+			},
+			wantCreds: []string{"admin:*"},
+			wantTries: []string{"root:a", "admin:a", "admin:b"},
+		},
+		{
+			name:    "blocked client stops target",
+			service: BruteMySQL,
+			stub:    &bruteStub{results: map[string]error{"root:b": errBruteBlocked}},
+			opts: &BruteOptions{
+				Usernames: map[string][]string{BruteAny: {"root", "admin"}},
+				Passwords: map[string][]string{BruteAny: {"a", "b", "c"}},
+			},
+			wantTries: []string{"root:a", "root:b"},
+		},
+		{
+			name:    "accepts any credential",
+			service: BruteSMB,
+			stub:    &bruteStub{anyCred: true},
+			opts: &BruteOptions{
+				Usernames: map[string][]string{BruteAny: {"administrator"}},
+				Passwords: map[string][]string{BruteAny: {"a"}},
+			},
+		},
+		{
+			name:    "no-auth probe with custom dicts",
+			service: BruteRedis,
+			stub:    &bruteStub{valid: map[string]bool{":": true}},
+			opts: &BruteOptions{
+				Usernames: map[string][]string{BruteAny: {"default"}},
+				Passwords: map[string][]string{BruteAny: {"redis"}},
+			},
+			wantCreds: []string{":<empty>"},
+			wantTries: []string{":", "default:redis"},
+		},
+		{
+			name:    "no-auth stop on success",
+			service: BruteMongoDB,
+			stub:    &bruteStub{valid: map[string]bool{":": true}},
+			opts: &BruteOptions{
+				Usernames:     map[string][]string{BruteAny: {"admin"}},
+				Passwords:     map[string][]string{BruteAny: {"admin"}},
+				StopOnSuccess: true,
+			},
+			wantCreds: []string{":<empty>"},
+			wantTries: []string{":"},
+		},
+		{
+			name:    "no extra probe when dicts contain empty entries",
+			service: BruteRedis,
+			stub:    &bruteStub{},
+			opts: &BruteOptions{
+				Usernames: map[string][]string{BruteAny: {""}},
+				Passwords: map[string][]string{BruteAny: {"", "redis"}},
+			},
+			wantTries: []string{":", ":redis"},
+		},
+		{
+			name:    "empty password entry",
+			service: BruteSSH,
+			stub:    &bruteStub{valid: map[string]bool{"root:": true}},
+			opts: &BruteOptions{
+				Usernames: map[string][]string{BruteAny: {"root"}},
+				Passwords: map[string][]string{BruteAny: {"", "toor"}},
+			},
+			wantCreds: []string{"root:<empty>"},
+			wantTries: []string{"root:"},
+		},
+	}
+
+	for _, tt := range tests {
+		orig := bruteLogins[tt.service]
+		bruteLogins[tt.service] = tt.stub.login
+		target := &bruteTarget{asset: &Asset{Host: "10.0.0.1"}, service: tt.service, host: "10.0.0.1", port: 1}
+		creds := bruteTargetRun(context.Background(), target, tt.opts, func(string, string, ...interface{}) {})
+		bruteLogins[tt.service] = orig
+
+		var got []string
+		for _, c := range creds {
+			got = append(got, c.Username+":"+c.Password)
+		}
+		if !reflect.DeepEqual(got, tt.wantCreds) {
+			t.Errorf("%s: creds = %q, want %q", tt.name, got, tt.wantCreds)
+		}
+		if !reflect.DeepEqual(tt.stub.attempts, tt.wantTries) {
+			t.Errorf("%s: attempts = %q, want %q", tt.name, tt.stub.attempts, tt.wantTries)
+		}
+	}
+}
+
+// TestMaskPassword 测试密码脱敏
+func TestMaskPassword(t *testing.T) {
+	tests := map[string]string{
+		"":         "<empty>",
+		"abc":      "***",
+		"toor":     "t**r",
+		"P@ssw0rd": "P******d",
+		"密码123":    "密***3",
+	}
+	for pass, want := range tests {
+		if got := MaskPassword(pass); got != want {
+			t.Errorf("MaskPassword(%q) = %q, want %q", pass, got, want)
+		}
+	}
+}
@@ -0,0 +1,504 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	mssql "github.com/microsoft/go-mssqldb"
+	"github.com/projectdiscovery/go-smb2"
+	"github.com/redis/go-redis/v9"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"golang.org/x/crypto/ssh"
+)
+
+// NTSTATUS 登录结果
+const (
+	ntStatusLogonFailure       = 0xC000006D
+	ntStatusAccountRestriction = 0xC000006E
+	ntStatusPasswordExpired    = 0xC0000071
+	ntStatusAccountDisabled    = 0xC0000072
+	ntStatusAccountLockedOut   = 0xC0000234
+	ntStatusPasswordMustChange = 0xC0000224
+)
+
+// ntStatusError 转换 Windows 认证状态码，口令正确但需修改密码也视为成功
+func ntStatusError(status uint32) error {
+	switch status {
+	case ntStatusPasswordExpired, ntStatusPasswordMustChange:
+		return nil
+	case ntStatusLogonFailure, ntStatusAccountRestriction:
+		return errBruteAuth
+	case ntStatusAccountLockedOut, ntStatusAccountDisabled:
+		return errBruteLocked
+	}
+	return fmt.Errorf("ntstatus 0x%08x", status)
+}
+
+// splitDomainUser 拆分 DOMAIN\user 格式的用户名
+func splitDomainUser(user string) (domain, name string) {
+	if i := strings.IndexByte(user, '\\'); i >= 0 {
+		return user[:i], user[i+1:]
+	}
+	return "", user
+}
+
+// dialBrute 建立 TCP 连接并设置整体超时
+func dialBrute(ctx context.Context, t *bruteTarget) (net.Conn, error) {
+	d := net.Dialer{Timeout: t.timeout}
+	conn, err := d.DialContext(ctx, "tcp", t.addr())
+	if err != nil {
+		return nil, err
+	}
+	conn.SetDeadline(time.Now().Add(t.timeout * 2))
+	return conn, nil
+}
+
+func loginSSH(ctx context.Context, t *bruteTarget, user, pass string) error {
+	conn, err := dialBrute(ctx, t)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	config := &ssh.ClientConfig{
+		User: user,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(pass),
+			ssh.KeyboardInteractive(func(_, _ string, questions []string, _ []bool) ([]string, error) {
+				answers := make([]string, len(questions))
+				for i := range answers {
+					answers[i] = pass
+				}
+				return answers, nil
+			}),
+		},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+		Timeout:         t.timeout,
+	}
+	c, chans, reqs, err := ssh.NewClientConn(conn, t.addr(), config)
+	if err != nil {
+		msg := err.Error()
+		if strings.Contains(msg, "attempted methods [none]") {
+			// 服务端只接受公钥等非口令认证
+			return errBruteUnsupported
+		}
+		if strings.Contains(msg, "unable to authenticate") {
+			return errBruteAuth
+		}
+		return err
+	}
+	ssh.NewClient(c, chans, reqs).Close()
+	return nil
+}
+
+func loginFTP(ctx context.Context, t *bruteTarget, user, pass string) error {
+	conn, err := dialBrute(ctx, t)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	cmd := func(format string, args ...interface{}) (int, error) {
+		if format != "" {
+			if _, err := fmt.Fprintf(conn, format+"\r\n", args...); err != nil {
+				return 0, err
+			}
+		}
+		return readFTPReply(r)
+	}
+
+	code, err := cmd("")
+	if err != nil {
+		return err
+	}
+	if code != 220 {
+		return fmt.Errorf("ftp greeting %d", code)
+	}
+	if code, err = cmd("USER %s", user); err != nil {
+		return err
+	}
+	if code == 331 {
+		if code, err = cmd("PASS %s", pass); err != nil {
+			return err
+		}
+	}
+	cmd("QUIT")
+	switch {
+	case code == 230:
+		return nil
+	case code >= 500:
+		// 530 口令错误，其余 5xx 多为空口令等参数被拒绝
+		return errBruteAuth
+	}
+	return fmt.Errorf("ftp reply %d", code)
+}
+
+// readFTPReply 读取 FTP 应答码，支持多行应答
+func readFTPReply(r *bufio.Reader) (int, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return 0, err
+	}
+	if len(line) < 4 {
+		return 0, fmt.Errorf("invalid ftp reply %q", line)
+	}
+	code, err := strconv.Atoi(line[:3])
+	if err != nil {
+		return 0, fmt.Errorf("invalid ftp reply %q", line)
+	}
+	if line[3] == '-' {
+		end := line[:3] + " "
+		for !strings.HasPrefix(line, end) {
+			if line, err = r.ReadString('\n'); err != nil {
+				return 0, err
+			}
+		}
+	}
+	return code, nil
+}
+
+func loginMySQL(ctx context.Context, t *bruteTarget, user, pass string) error {
+	cfg := mysql.NewConfig()
+	cfg.User = user
+	cfg.Passwd = pass
+	cfg.Net = "tcp"
+	cfg.Addr = t.addr()
+	cfg.Timeout = t.timeout
+	cfg.ReadTimeout = t.timeout
+	cfg.AllowNativePasswords = true
+	cfg.AllowCleartextPasswords = true
+	connector, err := mysql.NewConnector(cfg)
+	if err != nil {
+		return err
+	}
+	conn, err := connector.Connect(ctx)
+	if err == nil {
+		conn.Close()
+		return nil
+	}
+	var myErr *mysql.MySQLError
+	if errors.As(err, &myErr) {
+		switch myErr.Number {
+		case 1045, 1698:
+			return errBruteAuth
+		case 1129, 1130:
+			return errBruteBlocked
+		case 3118:
+			return errBruteLocked
+		}
+	}
+	return err
+}
+
+func loginMSSQL(ctx context.Context, t *bruteTarget, user, pass string) error {
+	query := url.Values{}
+	query.Set("dial timeout", strconv.Itoa(int(t.timeout.Seconds())))
+	query.Set("connection timeout", strconv.Itoa(int(t.timeout.Seconds())))
+	query.Set("encrypt", "disable")
+	u := &url.URL{
+		Scheme:   "sqlserver",
+		User:     url.UserPassword(user, pass),
+		Host:     t.addr(),
+		RawQuery: query.Encode(),
+	}
+	connector, err := mssql.NewConnector(u.String())
+	if err != nil {
+		return err
+	}
+	conn, err := connector.Connect(ctx)
+	if err == nil {
+		conn.Close()
+		return nil
+	}
+	var sqlErr interface{ SQLErrorNumber() int32 }
+	if errors.As(err, &sqlErr) {
+		switch sqlErr.SQLErrorNumber() {
+		case 18456:
+			return errBruteAuth
+		case 18486, 18470:
+			return errBruteLocked
+		case 18487, 18488:
+			// 口令正确但已过期
+			return nil
+		}
+	}
+	return err
+}
+
+func loginPostgres(ctx context.Context, t *bruteTarget, user, pass string) error {
+	quote := func(s string) string {
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'"
+	}
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=postgres sslmode=disable connect_timeout=%d",
+		quote(t.host), t.port, quote(user), quote(pass), int(t.timeout.Seconds()))
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return err
+	}
+	conn, err := connector.Connect(ctx)
+	if err == nil {
+		conn.Close()
+		return nil
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch pqErr.Code {
+		case "28P01":
+			return errBruteAuth
+		case "28000":
+			if strings.Contains(pqErr.Message, "pg_hba.conf") {
+				return errBruteBlocked
+			}
+			return errBruteAuth
+		case "3D000":
+			// 认证已通过，只是 postgres 库不存在
+			return nil
+		}
+	}
+	return err
+}
+
+func loginRedis(ctx context.Context, t *bruteTarget, user, pass string) error {
+	client := redis.NewClient(&redis.Options{
+		Addr:            t.addr(),
+		Username:        user,
+		Password:        pass,
+		Protocol:        2,
+		DisableIdentity: true,
+		DialTimeout:     t.timeout,
+		ReadTimeout:     t.timeout,
+		WriteTimeout:    t.timeout,
+		MaxRetries:      -1,
+		PoolSize:        1,
+	})
+	defer client.Close()
+	err := client.Ping(ctx).Err()
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	for _, s := range []string{"WRONGPASS", "NOAUTH", "invalid password", "invalid username-password", "without any password configured"} {
+		if strings.Contains(msg, s) {
+			return errBruteAuth
+		}
+	}
+	return err
+}
+
+func loginMongoDB(ctx context.Context, t *bruteTarget, user, pass string) error {
+	// 空用户名检测未授权访问，只需尝试一次
+	if (user == "") != (pass == "") {
+		return errBruteAuth
+	}
+	opts := options.Client().
+		SetHosts([]string{t.addr()}).
+		SetDirect(true).
+		SetConnectTimeout(t.timeout).
+		SetServerSelectionTimeout(t.timeout).
+		SetTimeout(t.timeout)
+	if user != "" {
+		opts.SetAuth(options.Credential{Username: user, Password: pass, AuthSource: "admin"})
+	}
+	client, err := mongo.Connect(ctx, opts)
+	if err != nil {
+		return err
+	}
+	defer client.Disconnect(context.Background())
+	err = client.Database("admin").RunCommand(ctx, bson.D{{Key: "listDatabases", Value: 1}, {Key: "nameOnly", Value: true}}).Err()
+	if err == nil {
+		return nil
+	}
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) && (cmdErr.Code == 13 || cmdErr.Code == 18) {
+		return errBruteAuth
+	}
+	msg := err.Error()
+	if strings.Contains(msg, "auth error") || strings.Contains(msg, "Authentication failed") {
+		return errBruteAuth
+	}
+	return err
+}
+
+func loginSMB(ctx context.Context, t *bruteTarget, user, pass string) error {
+	domain, name := splitDomainUser(user)
+	if name == "" {
+		return errBruteAuth
+	}
+	conn, err := dialBrute(ctx, t)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	d := &smb2.Dialer{
+		Initiator: &smb2.NTLMInitiator{User: name, Password: pass, Domain: domain},
+	}
+	s, err := d.DialContext(ctx, conn)
+	if err == nil {
+		s.Logoff()
+		return nil
+	}
+	var respErr *smb2.ResponseError
+	if errors.As(err, &respErr) {
+		return ntStatusError(respErr.Code)
+	}
+	return err
+}
+
+func loginTelnet(ctx context.Context, t *bruteTarget, user, pass string) error {
+	conn, err := dialBrute(ctx, t)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	tc := &telnetConn{conn: conn}
+
+	prompt, err := tc.readUntil("login:", "username:", "user name:", "password:")
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(prompt, "password:") {
+		if err := tc.writeLine(user); err != nil {
+			return err
+		}
+		if _, err := tc.readUntil("password:"); err != nil {
+			return err
+		}
+	}
+	if err := tc.writeLine(pass); err != nil {
+		return err
+	}
+	// 登录后出现 shell 提示符为成功，失败提示或重新出现登录提示为失败
+	out, err := tc.readUntil("login:", "username:", "password:", "$", "#", ">", "%")
+	for _, s := range []string{"incorrect", "failed", "denied", "invalid"} {
+		if strings.Contains(out, s) {
+			return errBruteAuth
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if strings.HasSuffix(out, ":") {
+		return errBruteAuth
+	}
+	return nil
+}
+
+// telnetConn 处理 Telnet 选项协商的简单客户端
+type telnetConn struct {
+	conn net.Conn
+	buf  [1024]byte
+}
+
+const (
+	telnetIAC  = 255
+	telnetDont = 254
+	telnetDo   = 253
+	telnetWont = 252
+	telnetWill = 251
+	telnetSB   = 250
+	telnetSE   = 240
+)
+
+// readUntil 读取到以任一关键字结尾（忽略大小写和尾部空白），返回已读取的小写文本
+func (c *telnetConn) readUntil(suffixes ...string) (string, error) {
+	var text []byte
+	for len(text) < 64*1024 {
+		n, err := c.conn.Read(c.buf[:])
+		text = append(text, c.negotiate(c.buf[:n])...)
+		out := strings.TrimRight(strings.ToLower(string(text)), " \t\r\n\x00")
+		if err != nil {
+			return out, err
+		}
+		for _, s := range suffixes {
+			if strings.HasSuffix(out, s) {
+				return out, nil
+			}
+		}
+	}
+	return "", errors.New("telnet prompt not found")
+}
+
+// negotiate 拒绝服务端的所有选项请求并去掉协商字节
+func (c *telnetConn) negotiate(data []byte) []byte {
+	var text, reply []byte
+	for i := 0; i < len(data); i++ {
+		if data[i] != telnetIAC || i+1 >= len(data) {
+			text = append(text, data[i])
+			continue
+		}
+		switch cmd := data[i+1]; cmd {
+		case telnetDo, telnetDont, telnetWill, telnetWont:
+			if i+2 < len(data) {
+				if cmd == telnetDo {
+					reply = append(reply, telnetIAC, telnetWont, data[i+2])
+				} else if cmd == telnetWill {
+					reply = append(reply, telnetIAC, telnetDont, data[i+2])
+				}
+			}
+			i += 2
+		case telnetSB:
+			for i += 2; i+1 < len(data) && !(data[i] == telnetIAC && data[i+1] == telnetSE); i++ {
+			}
+			i++
+		case telnetIAC:
+			text = append(text, telnetIAC)
+			i++
+		default:
+			i++
+		}
+	}
+	if len(reply) > 0 {
+		c.conn.Write(reply)
+	}
+	return text
+}
+
+func (c *telnetConn) writeLine(s string) error {
+	_, err := c.conn.Write([]byte(s + "\r\n"))
+	return err
+}
+
+func loginHTTPBasic(ctx context.Context, t *bruteTarget, user, pass string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, t.url, nil)
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(user, pass)
+	req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0 Safari/537.36")
+	client := &http.Client{
+		Timeout: t.timeout,
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return errBruteAuth
+	case t.service == BruteTomcat && resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("tomcat manager status %d", resp.StatusCode)
+	case resp.StatusCode < 400:
+		return nil
+	}
+	return fmt.Errorf("http status %d", resp.StatusCode)
+}
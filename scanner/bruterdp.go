@@ -0,0 +1,375 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rc4"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"time"
+	"unicode/utf16"
+
+	"golang.org/x/crypto/md4"
+)
+
+// RDP 只能在启用 NLA（CredSSP）时在握手阶段验证口令：
+// X.224 协商 HYBRID 协议 -> TLS -> CredSSP 内的 NTLMv2 认证 -> 提交加密的服务端公钥。
+// 服务端返回 pubKeyAuth 表示口令正确，返回 errorCode 或断开连接表示失败
+
+const (
+	rdpProtocolSSL      = 0x1
+	rdpProtocolHybrid   = 0x2
+	rdpProtocolHybridEx = 0x8
+
+	credSSPVersion = 6
+
+	secELogonDenied = 0x8009030C
+)
+
+// NTLM 协商标志
+const (
+	ntlmNegotiateUnicode         = 0x00000001
+	ntlmRequestTarget            = 0x00000004
+	ntlmNegotiateSign            = 0x00000010
+	ntlmNegotiateSeal            = 0x00000020
+	ntlmNegotiateNTLM            = 0x00000200
+	ntlmNegotiateAlwaysSign      = 0x00008000
+	ntlmNegotiateExtendedSession = 0x00080000
+	ntlmNegotiateTargetInfo      = 0x00800000
+	ntlmNegotiateVersion         = 0x02000000
+	ntlmNegotiate128             = 0x20000000
+	ntlmNegotiateKeyExch         = 0x40000000
+	ntlmNegotiate56              = 0x80000000
+
+	ntlmClientFlags = ntlmNegotiateUnicode | ntlmRequestTarget | ntlmNegotiateSign | ntlmNegotiateSeal |
+		ntlmNegotiateNTLM | ntlmNegotiateAlwaysSign | ntlmNegotiateExtendedSession |
+		ntlmNegotiateTargetInfo | ntlmNegotiateVersion | ntlmNegotiate128 | ntlmNegotiateKeyExch | ntlmNegotiate56
+)
+
+var (
+	ntlmSignature = []byte("NTLMSSP\x00")
+	// ntlmVersion Windows 10 (build 19041)，NTLM revision 15
+	ntlmVersion = []byte{10, 0, 0x61, 0x4a, 0, 0, 0, 0x0f}
+)
+
+// tsRequest CredSSP TSRequest 结构
+type tsRequest struct {
+	Version     int         `asn1:"explicit,tag:0"`
+	NegoTokens  []negoToken `asn1:"optional,explicit,tag:1"`
+	AuthInfo    []byte      `asn1:"optional,explicit,tag:2"`
+	PubKeyAuth  []byte      `asn1:"optional,explicit,tag:3"`
+	ErrorCode   int64       `asn1:"optional,explicit,tag:4"`
+	ClientNonce []byte      `asn1:"optional,explicit,tag:5"`
+}
+
+type negoToken struct {
+	Token []byte `asn1:"explicit,tag:0"`
+}
+
+func loginRDP(ctx context.Context, t *bruteTarget, user, pass string) error {
+	conn, err := dialBrute(ctx, t)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := rdpNegotiateHybrid(conn); err != nil {
+		return err
+	}
+	tlsConn := tls.Client(conn, &tls.Config{InsecureSkipVerify: true, MinVersion: tls.VersionTLS10})
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return err
+	}
+	certs := tlsConn.ConnectionState().PeerCertificates
+	if len(certs) == 0 {
+		return errors.New("rdp: no server certificate")
+	}
+	var spki struct {
+		Algorithm pkix.AlgorithmIdentifier
+		PublicKey asn1.BitString
+	}
+	if _, err := asn1.Unmarshal(certs[0].RawSubjectPublicKeyInfo, &spki); err != nil {
+		return err
+	}
+
+	// NTLM NEGOTIATE
+	negotiate := ntlmNegotiateMessage()
+	if err := writeTSRequest(tlsConn, &tsRequest{Version: credSSPVersion, NegoTokens: []negoToken{{negotiate}}}); err != nil {
+		return err
+	}
+	resp, err := readTSRequest(tlsConn)
+	if err != nil {
+		return err
+	}
+	if len(resp.NegoTokens) == 0 {
+		return errors.New("rdp: no ntlm challenge")
+	}
+	challenge, err := parseNTLMChallenge(resp.NegoTokens[0].Token)
+	if err != nil {
+		return err
+	}
+
+	// NTLM AUTHENTICATE + 加密的服务端公钥
+	domain, name := splitDomainUser(user)
+	authenticate, sessionKey := ntlmAuthenticateMessage(challenge, domain, name, pass)
+	pubKey := spki.PublicKey.Bytes
+	req := &tsRequest{Version: credSSPVersion, NegoTokens: []negoToken{{authenticate}}}
+	if resp.Version >= 5 {
+		req.ClientNonce = make([]byte, 32)
+		rand.Read(req.ClientNonce)
+		h := sha256.New()
+		h.Write([]byte("CredSSP Client-To-Server Binding Hash\x00"))
+		h.Write(req.ClientNonce)
+		h.Write(pubKey)
+		pubKey = h.Sum(nil)
+	}
+	req.PubKeyAuth = ntlmSeal(sessionKey, pubKey)
+	if err := writeTSRequest(tlsConn, req); err != nil {
+		return err
+	}
+
+	resp, err = readTSRequest(tlsConn)
+	if err != nil {
+		// 旧版本服务端认证失败时直接断开连接
+		if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) || strings.Contains(err.Error(), "reset") {
+			return errBruteAuth
+		}
+		return err
+	}
+	if len(resp.PubKeyAuth) > 0 {
+		return nil
+	}
+	if resp.ErrorCode != 0 {
+		if uint32(resp.ErrorCode) == secELogonDenied {
+			return errBruteAuth
+		}
+		return ntStatusError(uint32(resp.ErrorCode))
+	}
+	return errors.New("rdp: unexpected credssp response")
+}
+
+// rdpNegotiateHybrid 发送 X.224 Connection Request，要求服务端支持 NLA
+func rdpNegotiateHybrid(conn net.Conn) error {
+	req := []byte{
+		0x03, 0x00, 0x00, 0x13, // TPKT
+		0x0e, 0xe0, 0x00, 0x00, 0x00, 0x00, 0x00, // X.224 CR
+		0x01, 0x00, 0x08, 0x00, rdpProtocolSSL | rdpProtocolHybrid, 0x00, 0x00, 0x00, // RDP_NEG_REQ
+	}
+	if _, err := conn.Write(req); err != nil {
+		return err
+	}
+	header := make([]byte, 4)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return err
+	}
+	if header[0] != 0x03 {
+		return errors.New("rdp: invalid tpkt")
+	}
+	n := int(binary.BigEndian.Uint16(header[2:4]))
+	if n < 11 || n > 1024 {
+		return errors.New("rdp: invalid tpkt length")
+	}
+	body := make([]byte, n-4)
+	if _, err := io.ReadFull(conn, body); err != nil {
+		return err
+	}
+	if body[1]&0xf0 != 0xd0 || len(body) < 15 {
+		// 没有 RDP_NEG_RSP 的旧版本服务端只支持标准 RDP 安全层
+		return errBruteUnsupported
+	}
+	neg := body[7:]
+	if neg[0] != 0x02 {
+		return errBruteUnsupported
+	}
+	selected := binary.LittleEndian.Uint32(neg[4:8])
+	if selected&(rdpProtocolHybrid|rdpProtocolHybridEx) == 0 {
+		return errBruteUnsupported
+	}
+	return nil
+}
+
+func writeTSRequest(w io.Writer, req *tsRequest) error {
+	data, err := asn1.Marshal(*req)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+func readTSRequest(r io.Reader) (*tsRequest, error) {
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+	if header[0] != 0x30 {
+		return nil, errors.New("credssp: invalid tsrequest")
+	}
+	data := header
+	length := int(header[1])
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 3 {
+			return nil, errors.New("credssp: invalid length")
+		}
+		lenBytes := make([]byte, n)
+		if _, err := io.ReadFull(r, lenBytes); err != nil {
+			return nil, err
+		}
+		data = append(data, lenBytes...)
+		length = 0
+		for _, b := range lenBytes {
+			length = length<<8 | int(b)
+		}
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(r, body); err != nil {
+		return nil, err
+	}
+	var req tsRequest
+	if _, err := asn1.Unmarshal(append(data, body...), &req); err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+func ntlmNegotiateMessage() []byte {
+	msg := make([]byte, 40)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 1)
+	binary.LittleEndian.PutUint32(msg[12:], ntlmClientFlags)
+	copy(msg[32:], ntlmVersion)
+	return msg
+}
+
+// ntlmChallenge NTLM CHALLENGE 消息中认证需要的字段
+type ntlmChallenge struct {
+	flags      uint32
+	challenge  []byte
+	targetInfo []byte
+	timestamp  []byte
+}
+
+func parseNTLMChallenge(msg []byte) (*ntlmChallenge, error) {
+	if len(msg) < 48 || !bytes.Equal(msg[:8], ntlmSignature) || binary.LittleEndian.Uint32(msg[8:]) != 2 {
+		return nil, errors.New("ntlm: invalid challenge")
+	}
+	c := &ntlmChallenge{
+		flags:     binary.LittleEndian.Uint32(msg[20:]),
+		challenge: msg[24:32],
+	}
+	infoLen := int(binary.LittleEndian.Uint16(msg[40:]))
+	infoOff := int(binary.LittleEndian.Uint32(msg[44:]))
+	if infoOff+infoLen > len(msg) {
+		return nil, errors.New("ntlm: invalid target info")
+	}
+	c.targetInfo = msg[infoOff : infoOff+infoLen]
+	// 查找 MsvAvTimestamp
+	for info := c.targetInfo; len(info) >= 4; {
+		id := binary.LittleEndian.Uint16(info)
+		n := int(binary.LittleEndian.Uint16(info[2:]))
+		if id == 0 || 4+n > len(info) {
+			break
+		}
+		if id == 7 && n == 8 {
+			c.timestamp = info[4:12]
+		}
+		info = info[4+n:]
+	}
+	return c, nil
+}
+
+// ntlmAuthenticateMessage 生成 NTLMv2 AUTHENTICATE 消息，返回消息和导出的会话密钥
+func ntlmAuthenticateMessage(c *ntlmChallenge, domain, user, pass string) ([]byte, []byte) {
+	h := md4.New()
+	h.Write(utf16LE(pass))
+	ntowf := hmacMD5(h.Sum(nil), utf16LE(strings.ToUpper(user)+domain))
+
+	clientChallenge := make([]byte, 8)
+	rand.Read(clientChallenge)
+	timestamp := c.timestamp
+	if timestamp == nil {
+		timestamp = make([]byte, 8)
+		ft := uint64(time.Now().UnixNano()/100) + 116444736000000000
+		binary.LittleEndian.PutUint64(timestamp, ft)
+	}
+
+	var temp bytes.Buffer
+	temp.Write([]byte{1, 1, 0, 0, 0, 0, 0, 0})
+	temp.Write(timestamp)
+	temp.Write(clientChallenge)
+	temp.Write([]byte{0, 0, 0, 0})
+	temp.Write(c.targetInfo)
+	temp.Write([]byte{0, 0, 0, 0})
+	ntProof := hmacMD5(ntowf, append(append([]byte{}, c.challenge...), temp.Bytes()...))
+	ntResponse := append(ntProof, temp.Bytes()...)
+	lmResponse := make([]byte, 24)
+	if c.timestamp == nil {
+		lmResponse = append(hmacMD5(ntowf, append(append([]byte{}, c.challenge...), clientChallenge...)), clientChallenge...)
+	}
+
+	keyExchangeKey := hmacMD5(ntowf, ntProof)
+	sessionKey := make([]byte, 16)
+	rand.Read(sessionKey)
+	encryptedKey := make([]byte, 16)
+	cipher, _ := rc4.NewCipher(keyExchangeKey)
+	cipher.XORKeyStream(encryptedKey, sessionKey)
+
+	fields := [][]byte{lmResponse, ntResponse, utf16LE(domain), utf16LE(user), nil, encryptedKey}
+	msg := make([]byte, 72)
+	copy(msg, ntlmSignature)
+	binary.LittleEndian.PutUint32(msg[8:], 3)
+	for i, f := range fields {
+		off := 12 + i*8
+		binary.LittleEndian.PutUint16(msg[off:], uint16(len(f)))
+		binary.LittleEndian.PutUint16(msg[off+2:], uint16(len(f)))
+		binary.LittleEndian.PutUint32(msg[off+4:], uint32(len(msg)))
+		msg = append(msg, f...)
+	}
+	binary.LittleEndian.PutUint32(msg[60:], c.flags&ntlmClientFlags|ntlmNegotiateKeyExch)
+	copy(msg[64:], ntlmVersion)
+	return msg, sessionKey
+}
+
+// ntlmSeal 用客户端密钥加密并签名消息（序号0），返回 签名||密文
+func ntlmSeal(sessionKey, data []byte) []byte {
+	signKey := md5.Sum(append(append([]byte{}, sessionKey...), "session key to client-to-server signing key magic constant\x00"...))
+	sealKey := md5.Sum(append(append([]byte{}, sessionKey...), "session key to client-to-server sealing key magic constant\x00"...))
+	cipher, _ := rc4.NewCipher(sealKey[:])
+
+	sealed := make([]byte, len(data))
+	cipher.XORKeyStream(sealed, data)
+	mac := hmacMD5(signKey[:], append([]byte{0, 0, 0, 0}, data...))[:8]
+	checksum := make([]byte, 8)
+	cipher.XORKeyStream(checksum, mac)
+
+	out := make([]byte, 16, 16+len(sealed))
+	binary.LittleEndian.PutUint32(out, 1)
+	copy(out[4:], checksum)
+	return append(out, sealed...)
+}
+
+func hmacMD5(key, data []byte) []byte {
+	h := hmac.New(md5.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func utf16LE(s string) []byte {
+	u := utf16.Encode([]rune(s))
+	b := make([]byte, 2*len(u))
+	for i, r := range u {
+		binary.LittleEndian.PutUint16(b[2*i:], r)
+	}
+	return b
+}
@@ -0,0 +1,516 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+//go:embed cdndata/providers.json
+var cdnProvidersData []byte
+
+// CDN识别中的厂商类型
+const (
+	CDNTypeCDN   = "cdn"
+	CDNTypeWAF   = "waf"
+	CDNTypeCloud = "cloud"
+)
+
+// CDNRangesFile 从厂商官网更新的IP段缓存，存在时合并到内置数据
+var CDNRangesFile = filepath.Join(os.TempDir(), "cscan", "cdn_ranges.json")
+
+// cdnRangeSources 厂商公开发布的IP段地址
+var cdnRangeSources = []struct {
+	Provider string
+	URL      string
+}{
+	{"Cloudflare", "https://www.cloudflare.com/ips-v4"},
+	{"Cloudflare", "https://www.cloudflare.com/ips-v6"},
+	{"CloudFront", "https://d7uri8nf7uskq.cloudfront.net/tools/list-cloudfront-ips"},
+	{"Fastly", "https://api.fastly.com/public-ip-list"},
+}
+
+// cdnAttackPath 触发WAF拦截页的探测路径
+const cdnAttackPath = "/?id=1%27%20AND%201=1%20UNION%20SELECT%20NULL--&q=%3Cscript%3Ealert(1)%3C/script%3E&file=../../etc/passwd"
+
+// CDNProvider CDN/WAF/云厂商特征
+type CDNProvider struct {
+	Name    string   `json:"name"`
+	Type    string   `json:"type"`              // cdn/waf/cloud
+	WAF     bool     `json:"waf,omitempty"`     // CDN 同时提供WAF防护
+	CNAMEs  []string `json:"cnames,omitempty"`  // CNAME 后缀
+	CIDRs   []string `json:"cidrs,omitempty"`   // 节点IP段
+	ASNs    []int    `json:"asns,omitempty"`    // 自治系统号
+	Headers []string `json:"headers,omitempty"` // "头名称" 或 "头名称: 值包含"，名称以-结尾为前缀匹配
+	Cookies []string `json:"cookies,omitempty"` // Cookie 名称前缀
+	Bodies  []string `json:"bodies,omitempty"`  // 拦截页特征
+}
+
+// CDNInfo 主机的CDN/WAF/云厂商识别结果
+type CDNInfo struct {
+	Host     string
+	CNAMEs   []string
+	IPv4     []string
+	IPv6     []string
+	ASN      int
+	CDN      string
+	WAF      string
+	Cloud    string
+	Evidence []string // 命中依据，如 cname:xxx.cloudflare.net
+}
+
+// IsCDN 是否为CDN节点
+func (i *CDNInfo) IsCDN() bool {
+	return i.CDN != ""
+}
+
+func (i *CDNInfo) ips() []string {
+	return append(append([]string{}, i.IPv4...), i.IPv6...)
+}
+
+// apply 记录命中的厂商，同类型以先命中的为准
+func (i *CDNInfo) apply(p *cdnProvider, evidence string) {
+	changed := false
+	switch p.Type {
+	case CDNTypeCDN:
+		if i.CDN == "" {
+			i.CDN = p.Name
+			changed = true
+		}
+		if p.WAF && i.WAF == "" {
+			i.WAF = p.Name
+			changed = true
+		}
+	case CDNTypeWAF:
+		if i.WAF == "" {
+			i.WAF = p.Name
+			changed = true
+		}
+	case CDNTypeCloud:
+		if i.Cloud == "" {
+			i.Cloud = p.Name
+			changed = true
+		}
+	}
+	if changed {
+		i.Evidence = append(i.Evidence, evidence)
+	}
+}
+
+type cdnProvider struct {
+	CDNProvider
+	nets []*net.IPNet
+	asns map[int]bool
+}
+
+// CDNDetector CDN/WAF/云厂商识别引擎
+type CDNDetector struct {
+	providers []*cdnProvider
+	resolver  *dnsResolver
+	client    *http.Client
+	asn       bool
+	headers   bool
+
+	asnMu    sync.Mutex
+	asnCache map[string]int
+}
+
+// NewCDNDetector 创建识别引擎，asn 开启ASN查询，headers 开启HTTP响应特征探测
+func NewCDNDetector(resolvers []string, timeout time.Duration, asn, headers bool) *CDNDetector {
+	return &CDNDetector{
+		providers: loadCDNProviders(),
+		resolver:  newDNSResolver(resolvers, timeout),
+		client:    newCDNHTTPClient(2 * timeout),
+		asn:       asn,
+		headers:   headers,
+		asnCache:  make(map[string]int),
+	}
+}
+
+func newCDNHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+			DisableKeepAlives: true,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// loadCDNProviders 加载内置厂商数据，并合并已更新的IP段
+func loadCDNProviders() []*cdnProvider {
+	var list []CDNProvider
+	if err := json.Unmarshal(cdnProvidersData, &list); err != nil {
+		return nil
+	}
+	var updated map[string][]string
+	if data, err := os.ReadFile(CDNRangesFile); err == nil {
+		_ = json.Unmarshal(data, &updated)
+	}
+
+	providers := make([]*cdnProvider, 0, len(list))
+	for _, item := range list {
+		p := &cdnProvider{CDNProvider: item, asns: make(map[int]bool)}
+		seen := make(map[string]bool)
+		for _, cidr := range append(item.CIDRs, updated[item.Name]...) {
+			if seen[cidr] {
+				continue
+			}
+			seen[cidr] = true
+			if _, ipNet, err := net.ParseCIDR(strings.TrimSpace(cidr)); err == nil {
+				p.nets = append(p.nets, ipNet)
+			}
+		}
+		for _, asn := range item.ASNs {
+			p.asns[asn] = true
+		}
+		for i, c := range p.CNAMEs {
+			p.CNAMEs[i] = strings.ToLower(c)
+		}
+		providers = append(providers, p)
+	}
+	return providers
+}
+
+// Detect 识别单个主机（域名或IP）
+func (d *CDNDetector) Detect(ctx context.Context, host string) *CDNInfo {
+	info := &CDNInfo{Host: host}
+	if ip := net.ParseIP(host); ip != nil {
+		if ip.To4() != nil {
+			info.IPv4 = []string{host}
+		} else {
+			info.IPv6 = []string{host}
+		}
+	} else {
+		ans, err := d.resolver.resolve(ctx, host)
+		if err != nil || !ans.resolved() {
+			return info
+		}
+		info.CNAMEs = ans.CNAMEs
+		info.IPv4 = ans.IPv4
+		info.IPv6 = ans.IPv6
+	}
+
+	// CNAME 后缀
+	for _, cname := range info.CNAMEs {
+		for _, p := range d.providers {
+			if matchDomainSuffix(cname, p.CNAMEs) {
+				info.apply(p, "cname:"+cname)
+			}
+		}
+	}
+
+	// 节点IP段
+	for _, s := range info.ips() {
+		ip := net.ParseIP(s)
+		for _, p := range d.providers {
+			for _, ipNet := range p.nets {
+				if ipNet.Contains(ip) {
+					info.apply(p, "ip:"+s)
+					break
+				}
+			}
+		}
+	}
+
+	// ASN，CDN节点的ASN多属于CDN厂商，已识别时不再查询
+	if d.asn && !info.IsCDN() {
+		if ips := info.ips(); len(ips) > 0 {
+			if asn := d.lookupASN(ctx, ips[0]); asn > 0 {
+				info.ASN = asn
+				for _, p := range d.providers {
+					if p.asns[asn] {
+						info.apply(p, fmt.Sprintf("asn:AS%d", asn))
+					}
+				}
+			}
+		}
+	}
+
+	// HTTP 响应特征
+	if d.headers && (len(info.IPv4) > 0 || len(info.IPv6) > 0) {
+		d.detectHTTP(ctx, info)
+	}
+	return info
+}
+
+// detectHTTP 根据响应头、Cookie识别，未识别到WAF时发送攻击载荷查看拦截页
+func (d *CDNDetector) detectHTTP(ctx context.Context, info *CDNInfo) {
+	resp, base := d.fetch(ctx, info.Host, "/")
+	if resp == nil {
+		return
+	}
+	d.matchResponse(info, resp, nil)
+	if info.WAF != "" {
+		return
+	}
+	if attack, _ := d.fetch(ctx, info.Host, cdnAttackPath, base); attack != nil {
+		d.matchResponse(info, attack, attack.body)
+	}
+}
+
+type cdnResponse struct {
+	status  int
+	header  http.Header
+	cookies []string
+	body    []byte
+}
+
+// fetch 请求主机，优先https，返回响应和成功的协议
+func (d *CDNDetector) fetch(ctx context.Context, host, path string, schemes ...string) (*cdnResponse, string) {
+	if len(schemes) == 0 || schemes[0] == "" {
+		schemes = []string{"https", "http"}
+	}
+	for _, scheme := range schemes {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+hostForURL(host)+path, nil)
+		if err != nil {
+			return nil, ""
+		}
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		resp, err := d.client.Do(req)
+		if err != nil {
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+		r := &cdnResponse{status: resp.StatusCode, header: resp.Header, body: body}
+		for _, c := range resp.Cookies() {
+			r.cookies = append(r.cookies, strings.ToLower(c.Name))
+		}
+		return r, scheme
+	}
+	return nil, ""
+}
+
+// matchResponse 匹配响应特征，body 不为空时同时匹配拦截页
+func (d *CDNDetector) matchResponse(info *CDNInfo, resp *cdnResponse, body []byte) {
+	lowerBody := strings.ToLower(string(body))
+	for _, p := range d.providers {
+		if rule := matchHeaderRules(resp.header, p.Headers); rule != "" {
+			info.apply(p, "header:"+rule)
+			continue
+		}
+		if cookie := matchCookieRules(resp.cookies, p.Cookies); cookie != "" {
+			info.apply(p, "cookie:"+cookie)
+			continue
+		}
+		if lowerBody != "" {
+			for _, keyword := range p.Bodies {
+				if strings.Contains(lowerBody, strings.ToLower(keyword)) {
+					info.apply(p, "body:"+keyword)
+					break
+				}
+			}
+		}
+	}
+}
+
+func matchHeaderRules(header http.Header, rules []string) string {
+	for _, rule := range rules {
+		name, value, hasValue := strings.Cut(rule, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+		value = strings.ToLower(strings.TrimSpace(value))
+		for key, values := range header {
+			key = strings.ToLower(key)
+			if strings.HasSuffix(name, "-") {
+				if !strings.HasPrefix(key, name) {
+					continue
+				}
+			} else if key != name {
+				continue
+			}
+			if !hasValue {
+				return rule
+			}
+			for _, v := range values {
+				if strings.Contains(strings.ToLower(v), value) {
+					return rule
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func matchCookieRules(cookies, rules []string) string {
+	for _, rule := range rules {
+		for _, c := range cookies {
+			if strings.HasPrefix(c, rule) {
+				return c
+			}
+		}
+	}
+	return ""
+}
+
+// lookupASN 通过 Team Cymru 的DNS接口查询IP所属ASN
+func (d *CDNDetector) lookupASN(ctx context.Context, ip string) int {
+	d.asnMu.Lock()
+	asn, ok := d.asnCache[ip]
+	d.asnMu.Unlock()
+	if ok {
+		return asn
+	}
+
+	reverse, err := dns.ReverseAddr(ip)
+	if err == nil {
+		zone := "origin.asn.cymru.com"
+		if strings.HasSuffix(reverse, ".ip6.arpa.") {
+			zone = "origin6.asn.cymru.com"
+		}
+		name := strings.TrimSuffix(strings.TrimSuffix(reverse, ".in-addr.arpa."), ".ip6.arpa.") + "." + zone
+		if msg, err := d.resolver.query(ctx, name, dns.TypeTXT); err == nil {
+			for _, rr := range msg.Answer {
+				if txt, ok := rr.(*dns.TXT); ok && len(txt.Txt) > 0 {
+					// 格式: "13335 | 1.1.1.0/24 | AU | apnic | 2011-08-11"
+					field := strings.TrimSpace(strings.Split(txt.Txt[0], "|")[0])
+					if fields := strings.Fields(field); len(fields) > 0 {
+						asn, _ = strconv.Atoi(fields[0])
+					}
+					break
+				}
+			}
+		}
+	}
+
+	d.asnMu.Lock()
+	d.asnCache[ip] = asn
+	d.asnMu.Unlock()
+	return asn
+}
+
+// isCDNAddress IP是否属于CDN节点IP段
+func (d *CDNDetector) isCDNAddress(s string) bool {
+	ip := net.ParseIP(s)
+	if ip == nil {
+		return false
+	}
+	for _, p := range d.providers {
+		if p.Type != CDNTypeCDN && p.Type != CDNTypeWAF {
+			continue
+		}
+		for _, ipNet := range p.nets {
+			if ipNet.Contains(ip) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// UpdateCDNRanges 从厂商官网更新CDN节点IP段，缓存未过期时跳过
+func UpdateCDNRanges(ctx context.Context, maxAge time.Duration) error {
+	if stat, err := os.Stat(CDNRangesFile); err == nil && time.Since(stat.ModTime()) < maxAge {
+		return nil
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	ranges := make(map[string][]string)
+	var lastErr error
+	for _, source := range cdnRangeSources {
+		cidrs, err := fetchCDNRanges(ctx, client, source.URL)
+		if err != nil {
+			lastErr = fmt.Errorf("%s: %v", source.URL, err)
+			continue
+		}
+		ranges[source.Provider] = append(ranges[source.Provider], cidrs...)
+	}
+	if len(ranges) == 0 {
+		return lastErr
+	}
+
+	data, err := json.Marshal(ranges)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(CDNRangesFile), 0755); err != nil {
+		return err
+	}
+	// 先写临时文件再替换，避免并发任务读到不完整的内容
+	tmp := CDNRangesFile + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, CDNRangesFile); err != nil {
+		return err
+	}
+	return lastErr
+}
+
+// fetchCDNRanges 下载IP段列表，支持纯文本和各厂商的JSON格式
+func fetchCDNRanges(ctx context.Context, client *http.Client, rawURL string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return nil, err
+	}
+	return parseCDNRanges(body), nil
+}
+
+func parseCDNRanges(body []byte) []string {
+	var cidrs []string
+	var doc map[string]json.RawMessage
+	if json.Unmarshal(body, &doc) == nil {
+		// CloudFront: {"CLOUDFRONT_GLOBAL_IP_LIST": [...]}，Fastly: {"addresses": [...], "ipv6_addresses": [...]}
+		for _, raw := range doc {
+			var list []string
+			if json.Unmarshal(raw, &list) == nil {
+				cidrs = append(cidrs, list...)
+			}
+		}
+	} else {
+		cidrs = strings.Fields(string(body))
+	}
+
+	valid := cidrs[:0]
+	for _, cidr := range cidrs {
+		if _, _, err := net.ParseCIDR(cidr); err == nil {
+			valid = append(valid, cidr)
+		}
+	}
+	return valid
+}
+
+// matchDomainSuffix 域名是否等于或属于任一后缀
+func matchDomainSuffix(name string, suffixes []string) bool {
+	for _, suffix := range suffixes {
+		if name == suffix || strings.HasSuffix(name, "."+suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func hostForURL(host string) string {
+	if ip := net.ParseIP(host); ip != nil && ip.To4() == nil {
+		return "[" + host + "]"
+	}
+	return host
+}
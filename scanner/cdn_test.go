@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"net"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// TestMatchHeaderRules 测试响应头规则：名称精确匹配、前缀匹配和值包含匹配，均不区分大小写
+func TestMatchHeaderRules(t *testing.T) {
+	rules := []string{"CF-", "Server: cloudflare", "X-Cache-Lookup", "Via: Varnish"}
+	tests := []struct {
+		name   string
+		header http.Header
+		want   string
+	}{
+		{"prefix", http.Header{"Cf-Ray": {"7d0b1c"}}, "CF-"},
+		{"prefix needs dash", http.Header{"Cfg": {"x"}}, ""},
+		{"value contains", http.Header{"Server": {"CloudFlare-nginx"}}, "Server: cloudflare"},
+		{"value mismatch", http.Header{"Server": {"nginx"}}, ""},
+		{"exact name", http.Header{"X-Cache-Lookup": {"MISS"}}, "X-Cache-Lookup"},
+		{"exact name not prefix", http.Header{"X-Cache-Lookup-Extra": {"MISS"}}, ""},
+		{"second value", http.Header{"Via": {"1.1 squid", "1.1 varnish"}}, "Via: Varnish"},
+		{"rule order wins", http.Header{"Via": {"varnish"}, "Cf-Ray": {"1"}}, "CF-"},
+		{"empty header", http.Header{}, ""},
+	}
+	for _, tt := range tests {
+		if got := matchHeaderRules(tt.header, rules); got != tt.want {
+			t.Errorf("%s: matchHeaderRules() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestMatchCookieRules 测试Cookie名称前缀匹配
+func TestMatchCookieRules(t *testing.T) {
+	rules := []string{"__cf", "acw_tc"}
+	tests := []struct {
+		cookies []string
+		want    string
+	}{
+		{[]string{"session", "__cf_bm"}, "__cf_bm"},
+		{[]string{"acw_tc"}, "acw_tc"},
+		{[]string{"my__cf"}, ""},
+		{[]string{"ACW_TC"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		if got := matchCookieRules(tt.cookies, rules); got != tt.want {
+			t.Errorf("matchCookieRules(%v) = %q, want %q", tt.cookies, got, tt.want)
+		}
+	}
+}
+
+// TestParseCDNRanges 测试IP段列表解析：纯文本、CloudFront 和 Fastly 的JSON格式，无效条目被丢弃
+func TestParseCDNRanges(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want []string
+	}{
+		{"plain text", "173.245.48.0/20\n103.21.244.0/22\n\n2400:cb00::/32\n", []string{"103.21.244.0/22", "173.245.48.0/20", "2400:cb00::/32"}},
+		{"plain text invalid", "173.245.48.0/20\n<html>\n1.2.3.4\n999.0.0.0/8", []string{"173.245.48.0/20"}},
+		{"cloudfront", `{"CLOUDFRONT_GLOBAL_IP_LIST":["120.52.22.96/27","205.251.249.0/24"],"CLOUDFRONT_REGIONAL_EDGE_IP_LIST":["13.113.196.64/26"]}`, []string{"120.52.22.96/27", "13.113.196.64/26", "205.251.249.0/24"}},
+		{"fastly", `{"addresses":["23.235.32.0/20"],"ipv6_addresses":["2a04:4e40::/32"],"note":"public"}`, []string{"23.235.32.0/20", "2a04:4e40::/32"}},
+		{"json array", `["1.1.1.0/24"]`, nil},
+		{"empty", "", nil},
+	}
+	for _, tt := range tests {
+		got := parseCDNRanges([]byte(tt.body))
+		sort.Strings(got)
+		if len(got) == 0 && len(tt.want) == 0 {
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: parseCDNRanges() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestMatchDomainSuffix 测试CNAME后缀按标签边界匹配
+func TestMatchDomainSuffix(t *testing.T) {
+	suffixes := []string{"cloudflare.net", "kunlunca.com"}
+	tests := map[string]bool{
+		"cloudflare.net":                 true,
+		"example.com.cdn.cloudflare.net": true,
+		"a.kunlunca.com":                 true,
+		"notcloudflare.net":              false,
+		"cloudflare.net.evil.com":        false,
+		"":                               false,
+	}
+	for name, want := range tests {
+		if got := matchDomainSuffix(name, suffixes); got != want {
+			t.Errorf("matchDomainSuffix(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+// TestDedupeOriginCandidates 测试候选源站去重，排除无效、内网、回环和CDN节点IP，并限制数量
+func TestDedupeOriginCandidates(t *testing.T) {
+	_, cfNet, _ := net.ParseCIDR("104.16.0.0/13")
+	_, cloudNet, _ := net.ParseCIDR("47.88.0.0/16")
+	detector := &CDNDetector{providers: []*cdnProvider{
+		{CDNProvider: CDNProvider{Name: "Cloudflare", Type: CDNTypeCDN}, nets: []*net.IPNet{cfNet}},
+		{CDNProvider: CDNProvider{Name: "Aliyun", Type: CDNTypeCloud}, nets: []*net.IPNet{cloudNet}},
+	}}
+
+	list := []OriginCandidate{
+		{IP: "203.0.113.10", Source: "dns-history"},
+		{IP: "203.0.113.10", Source: "cert"},
+		{IP: "not-an-ip", Source: "sibling"},
+		{IP: "10.0.0.5", Source: "subdomain"},
+		{IP: "127.0.0.1", Source: "subdomain"},
+		{IP: "104.16.1.1", Source: "subdomain"},
+		{IP: "47.88.1.1", Source: "subdomain"},
+		{IP: "2001:db8::1", Source: "cert"},
+	}
+	want := []OriginCandidate{
+		{IP: "203.0.113.10", Source: "dns-history"},
+		{IP: "47.88.1.1", Source: "subdomain"},
+		{IP: "2001:db8::1", Source: "cert"},
+	}
+	if got := dedupeOriginCandidates(detector, list); !reflect.DeepEqual(got, want) {
+		t.Errorf("dedupeOriginCandidates() = %+v, want %+v", got, want)
+	}
+
+	var many []OriginCandidate
+	for i := 1; i <= 30; i++ {
+		many = append(many, OriginCandidate{IP: net.IPv4(198, 51, 100, byte(i)).String()})
+	}
+	if got := dedupeOriginCandidates(detector, many); len(got) != 20 || got[19].IP != "198.51.100.20" {
+		t.Errorf("dedupeOriginCandidates() kept %d candidates, want first 20", len(got))
+	}
+}
+
+// TestFilterOriginCandidates 测试超出扫描范围的候选源站IP不参与验证
+func TestFilterOriginCandidates(t *testing.T) {
+	list := []OriginCandidate{
+		{IP: "47.88.1.1", Source: "dns-history"},
+		{IP: "203.0.113.9", Source: "cert"},
+		{IP: "47.88.1.2", Source: "sibling"},
+	}
+	if got := filterOriginCandidates(list, nil); !reflect.DeepEqual(got, list) {
+		t.Errorf("filterOriginCandidates(nil) = %+v, want %+v", got, list)
+	}
+
+	var checked []string
+	allowed := func(host string) bool {
+		checked = append(checked, host)
+		return strings.HasPrefix(host, "47.88.")
+	}
+	want := []OriginCandidate{list[0], list[2]}
+	if got := filterOriginCandidates(list, allowed); !reflect.DeepEqual(got, want) {
+		t.Errorf("filterOriginCandidates() = %+v, want %+v", got, want)
+	}
+	if len(checked) != len(list) {
+		t.Errorf("checked %v, want every candidate", checked)
+	}
+	if got := filterOriginCandidates(list, func(string) bool { return false }); len(got) != 0 {
+		t.Errorf("filterOriginCandidates() = %+v, want none", got)
+	}
+}
@@ -0,0 +1,400 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cdnOriginPrefixes 常见的未接入CDN的子域名前缀
+var cdnOriginPrefixes = []string{"origin", "direct", "direct-connect", "real", "src", "source", "backend", "server", "ftp", "mail", "smtp", "cpanel", "webmail"}
+
+var cdnTitleRegex = regexp.MustCompile(`(?is)<title[^>]*>(.*?)</title>`)
+
+// CDNDetectOptions CDN识别选项
+type CDNDetectOptions struct {
+	ASN             bool     `json:"asn"`             // 查询IP所属ASN
+	Headers         bool     `json:"headers"`         // 通过HTTP响应特征识别CDN/WAF
+	OriginDiscovery bool     `json:"originDiscovery"` // 查找CDN后的源站IP
+	Resolvers       []string `json:"resolvers"`
+	Threads         int      `json:"threads"`
+	Timeout         int      `json:"timeout"`      // 单次查询/请求超时(秒)
+	UpdateRanges    bool     `json:"updateRanges"` // 扫描前由 Worker 从厂商官网更新CDN节点IP段
+	// OriginCandidates 查询历史解析、证书等来源的候选源站IP，key 为域名
+	OriginCandidates func(ctx context.Context, domains []string) map[string][]OriginCandidate `json:"-"`
+	// HostAllowed 检查候选源站IP是否在工作空间扫描范围内，为空时不限制
+	HostAllowed func(host string) bool `json:"-"`
+}
+
+// OriginCandidate 候选源站IP
+type OriginCandidate struct {
+	IP     string `json:"ip"`
+	Source string `json:"source"` // dns-history/cert/sibling/subdomain
+}
+
+// CDNDetectScanner CDN/WAF/云厂商识别
+type CDNDetectScanner struct {
+	BaseScanner
+}
+
+func init() {
+	Register("cdndetect", Registration{
+		New:     func() Scanner { return NewCDNDetectScanner() },
+		Options: func() interface{} { return &CDNDetectOptions{} },
+	})
+}
+
+// NewCDNDetectScanner 创建CDN识别扫描器
+func NewCDNDetectScanner() *CDNDetectScanner {
+	return &CDNDetectScanner{
+		BaseScanner: BaseScanner{name: "cdndetect"},
+	}
+}
+
+// Scan 识别目标中每个主机，返回带识别结果的主机资产；确认的源站IP作为 Source=cdn-origin 的资产返回
+func (s *CDNDetectScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	opts, _ := config.Options.(*CDNDetectOptions)
+	if opts == nil {
+		opts = &CDNDetectOptions{Headers: true}
+	}
+	if opts.Threads <= 0 {
+		opts.Threads = 20
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 5
+	}
+	logf := func(level, format string, args ...interface{}) {
+		if config.TaskLogger != nil {
+			config.TaskLogger(level, format, args...)
+		}
+	}
+
+	var hosts []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(config.Target, "\n") {
+		if host := TargetHost(line); host != "" && !seen[host] {
+			seen[host] = true
+			hosts = append(hosts, host)
+		}
+	}
+	result := &ScanResult{WorkspaceId: config.WorkspaceId, MainTaskId: config.MainTaskId}
+	if len(hosts) == 0 {
+		return result, nil
+	}
+
+	detector := NewCDNDetector(opts.Resolvers, time.Duration(opts.Timeout)*time.Second, opts.ASN, opts.Headers)
+	infos := make([]*CDNInfo, len(hosts))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.Threads)
+	for i, host := range hosts {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			infos[i] = detector.Detect(ctx, host)
+		}()
+	}
+	wg.Wait()
+
+	var cdnCount, wafCount, cloudCount int
+	for _, info := range infos {
+		if info == nil {
+			continue
+		}
+		if info.IsCDN() {
+			cdnCount++
+		}
+		if info.WAF != "" {
+			wafCount++
+		}
+		if info.Cloud != "" {
+			cloudCount++
+		}
+		if info.IsCDN() || info.WAF != "" || info.Cloud != "" {
+			logf("Info", "CDN detect: %s cdn=%s waf=%s cloud=%s (%s)", info.Host, info.CDN, info.WAF, info.Cloud, strings.Join(info.Evidence, ", "))
+		}
+		result.Assets = append(result.Assets, cdnInfoAsset(info))
+	}
+	logf("Info", "CDN detect: %d hosts, cdn=%d, waf=%d, cloud=%d", len(hosts), cdnCount, wafCount, cloudCount)
+
+	if opts.OriginDiscovery && ctx.Err() == nil {
+		origins, vuls := discoverOrigins(ctx, detector, infos, opts, logf)
+		result.Assets = append(result.Assets, origins...)
+		result.Vulnerabilities = vuls
+	}
+	return result, nil
+}
+
+// cdnInfoAsset 将识别结果转为主机资产
+func cdnInfoAsset(info *CDNInfo) *Asset {
+	asset := &Asset{
+		Authority: info.Host,
+		Host:      info.Host,
+		Category:  getCategory(info.Host),
+		IsCDN:     info.IsCDN(),
+		IsCloud:   info.Cloud != "",
+		CDNName:   info.CDN,
+		WAF:       info.WAF,
+		CloudName: info.Cloud,
+		Source:    "cdndetect",
+	}
+	if len(info.CNAMEs) > 0 {
+		asset.CName = info.CNAMEs[len(info.CNAMEs)-1]
+	}
+	for _, ip := range info.IPv4 {
+		asset.IPV4 = append(asset.IPV4, IPInfo{IP: ip})
+	}
+	for _, ip := range info.IPv6 {
+		asset.IPV6 = append(asset.IPV6, IPInfo{IP: ip})
+	}
+	return asset
+}
+
+// ApplyCDNInfo 将主机的识别结果写入同主机的资产
+func ApplyCDNInfo(asset, info *Asset) {
+	asset.IsCDN = info.IsCDN
+	asset.IsCloud = info.IsCloud
+	asset.CDNName = info.CDNName
+	asset.WAF = info.WAF
+	asset.CloudName = info.CloudName
+	if asset.CName == "" {
+		asset.CName = info.CName
+	}
+}
+
+// cdnPage 用于对比源站和CDN返回内容
+type cdnPage struct {
+	status int
+	title  string
+	length int
+}
+
+func (p *cdnPage) similar(o *cdnPage) bool {
+	if p.status != o.status {
+		return false
+	}
+	if p.title != "" || o.title != "" {
+		return p.title == o.title
+	}
+	if p.length == 0 || o.length == 0 {
+		return false
+	}
+	diff := p.length - o.length
+	if diff < 0 {
+		diff = -diff
+	}
+	return diff*20 <= p.length // 长度相差5%以内
+}
+
+// discoverOrigins 收集候选源站IP，并通过 Host 头请求候选IP对比页面确认
+func discoverOrigins(ctx context.Context, detector *CDNDetector, infos []*CDNInfo, opts *CDNDetectOptions, logf func(string, string, ...interface{})) ([]*Asset, []*Vulnerability) {
+	var cdnDomains []string
+	direct := make(map[string][]string) // 根域名 -> 未接入CDN的IP
+	for _, info := range infos {
+		if info == nil || net.ParseIP(info.Host) != nil {
+			continue
+		}
+		if info.IsCDN() {
+			cdnDomains = append(cdnDomains, info.Host)
+			continue
+		}
+		if root := crawlRootDomain(info.Host); root != "" {
+			direct[root] = append(direct[root], info.IPv4...)
+		}
+	}
+	if len(cdnDomains) == 0 {
+		return nil, nil
+	}
+
+	candidates := make(map[string][]OriginCandidate)
+	if opts.OriginCandidates != nil {
+		for domain, list := range opts.OriginCandidates(ctx, cdnDomains) {
+			candidates[domain] = append(candidates[domain], list...)
+		}
+	}
+	probed := make(map[string]bool)
+	for _, domain := range cdnDomains {
+		root := crawlRootDomain(domain)
+		for _, ip := range direct[root] {
+			candidates[domain] = append(candidates[domain], OriginCandidate{IP: ip, Source: "sibling"})
+		}
+		if root == "" || probed[root] {
+			continue
+		}
+		probed[root] = true
+		for _, prefix := range cdnOriginPrefixes {
+			ans, err := detector.resolver.resolve(ctx, prefix+"."+root)
+			if err != nil {
+				continue
+			}
+			for _, ip := range ans.IPv4 {
+				direct[root] = append(direct[root], ip)
+				candidates[domain] = append(candidates[domain], OriginCandidate{IP: ip, Source: "subdomain"})
+			}
+		}
+	}
+
+	client := &http.Client{
+		Timeout: time.Duration(opts.Timeout*2) * time.Second,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var assets []*Asset
+	var vuls []*Vulnerability
+	originSeen := make(map[string]bool)
+	sem := make(chan struct{}, opts.Threads)
+	for _, domain := range cdnDomains {
+		// 超出扫描范围的候选IP不发起请求
+		list := filterOriginCandidates(dedupeOriginCandidates(detector, candidates[domain]), opts.HostAllowed)
+		if len(list) == 0 || ctx.Err() != nil {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			scheme, baseline := fetchCDNPage(ctx, client, domain, "")
+			if baseline == nil {
+				return
+			}
+			for _, c := range list {
+				if ctx.Err() != nil {
+					return
+				}
+				_, page := fetchCDNPage(ctx, client, domain, c.IP, scheme)
+				if page == nil || !baseline.similar(page) {
+					continue
+				}
+				logf("Info", "CDN origin: %s -> %s (%s)", domain, c.IP, c.Source)
+				mu.Lock()
+				vuls = append(vuls, originVul(domain, scheme, c))
+				if !originSeen[c.IP] {
+					originSeen[c.IP] = true
+					assets = append(assets, &Asset{
+						Authority: c.IP,
+						Host:      c.IP,
+						Category:  getCategory(c.IP),
+						Source:    "cdn-origin",
+					})
+				}
+				mu.Unlock()
+				return
+			}
+		}()
+	}
+	wg.Wait()
+	sort.Slice(vuls, func(i, j int) bool { return vuls[i].Host < vuls[j].Host })
+	return assets, vuls
+}
+
+// filterOriginCandidates 只保留 allowed 允许的候选IP，allowed 为空时不过滤
+func filterOriginCandidates(list []OriginCandidate, allowed func(host string) bool) []OriginCandidate {
+	if allowed == nil {
+		return list
+	}
+	var result []OriginCandidate
+	for _, c := range list {
+		if allowed(c.IP) {
+			result = append(result, c)
+		}
+	}
+	return result
+}
+
+// dedupeOriginCandidates 去重并排除CDN节点IP，每个域名最多验证20个
+func dedupeOriginCandidates(detector *CDNDetector, list []OriginCandidate) []OriginCandidate {
+	seen := make(map[string]bool)
+	var result []OriginCandidate
+	for _, c := range list {
+		ip := net.ParseIP(c.IP)
+		if ip == nil || ip.IsPrivate() || ip.IsLoopback() || seen[c.IP] || detector.isCDNAddress(c.IP) {
+			continue
+		}
+		seen[c.IP] = true
+		result = append(result, c)
+		if len(result) >= 20 {
+			break
+		}
+	}
+	return result
+}
+
+// fetchCDNPage 请求域名首页，ip 不为空时直连该IP并携带 Host 头
+func fetchCDNPage(ctx context.Context, client *http.Client, domain, ip string, schemes ...string) (string, *cdnPage) {
+	if len(schemes) == 0 {
+		schemes = []string{"https", "http"}
+	}
+	for _, scheme := range schemes {
+		transport := &http.Transport{
+			TLSClientConfig:   &tls.Config{InsecureSkipVerify: true, ServerName: domain},
+			DisableKeepAlives: true,
+		}
+		if ip != "" {
+			dialer := &net.Dialer{Timeout: client.Timeout}
+			transport.DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				_, port, _ := net.SplitHostPort(addr)
+				return dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+			}
+		}
+		c := *client
+		c.Transport = transport
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+domain+"/", nil)
+		if err != nil {
+			return "", nil
+		}
+		req.Header.Set("User-Agent", "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36")
+		resp, err := c.Do(req)
+		if err != nil {
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256<<10))
+		resp.Body.Close()
+		page := &cdnPage{status: resp.StatusCode, length: len(body)}
+		if m := cdnTitleRegex.FindSubmatch(body); m != nil {
+			page.title = strings.TrimSpace(string(m[1]))
+		}
+		return scheme, page
+	}
+	return "", nil
+}
+
+func originVul(domain, scheme string, c OriginCandidate) *Vulnerability {
+	extra, _ := json.Marshal(map[string]string{
+		"originIp": c.IP,
+		"source":   c.Source,
+	})
+	port := 443
+	if scheme == "http" {
+		port = 80
+	}
+	return &Vulnerability{
+		Authority:   fmt.Sprintf("%s:%d", domain, port),
+		Host:        domain,
+		Port:        port,
+		Url:         scheme + "://" + domain,
+		PocFile:     "cdn-origin-exposed",
+		Source:      "cdndetect",
+		Severity:    "low",
+		Extra:       string(extra),
+		Result:      fmt.Sprintf("CDN源站IP暴露: %s 源站 %s (来源: %s)", domain, c.IP, c.Source),
+		Remediation: "源站仅允许CDN回源IP访问，并更换已暴露的源站IP",
+	}
+}
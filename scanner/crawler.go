@@ -0,0 +1,904 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/publicsuffix"
+)
+
+// 爬取范围
+const (
+	CrawlScopeSite   = "site"   // 同一站点（协议+主机+端口）
+	CrawlScopeHost   = "host"   // 同一主机的任意端口
+	CrawlScopeDomain = "domain" // 同一根域名及其子域名
+)
+
+// crawlMaxBody 单个响应最多读取的字节数
+const crawlMaxBody = 2 << 20
+
+// 不抓取内容的静态资源扩展名
+var crawlStaticExts = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true, ".ico": true, ".svg": true, ".webp": true,
+	".css": true, ".woff": true, ".woff2": true, ".ttf": true, ".eot": true, ".otf": true,
+	".mp3": true, ".mp4": true, ".avi": true, ".mov": true, ".webm": true, ".flv": true, ".wav": true,
+	".zip": true, ".rar": true, ".7z": true, ".gz": true, ".tar": true, ".exe": true, ".dmg": true, ".iso": true,
+	".pdf": true, ".doc": true, ".docx": true, ".xls": true, ".xlsx": true, ".ppt": true, ".pptx": true,
+	".map": true,
+}
+
+// CrawlerOptions 爬虫选项
+type CrawlerOptions struct {
+	MaxDepth      int               `json:"maxDepth"`      // 最大爬取深度，默认3
+	MaxPages      int               `json:"maxPages"`      // 每个站点最多抓取的页面数，默认200
+	Scope         string            `json:"scope"`         // 爬取范围 site/host/domain，默认site
+	Threads       int               `json:"threads"`       // 并发请求数，默认10
+	Timeout       int               `json:"timeout"`       // 单个请求超时(秒)，默认10
+	Forms         bool              `json:"forms"`         // 使用默认值提交GET表单，POST表单只记录地址
+	ParseJS       bool              `json:"parseJs"`       // 解析JS中的接口、敏感信息和子域名
+	Headless      bool              `json:"headless"`      // 使用浏览器渲染页面以发现SPA路由
+	HeadlessPages int               `json:"headlessPages"` // 每个站点最多渲染的页面数，默认20
+	UserAgent     string            `json:"userAgent"`
+	Headers       map[string]string `json:"headers"`
+	// AssetAllowed 检查主机和端口是否在工作空间扫描范围内，为空时不限制
+	AssetAllowed func(host string, port int) bool `json:"-"`
+}
+
+// CrawlerScanner 网页爬虫
+type CrawlerScanner struct {
+	BaseScanner
+}
+
+func init() {
+	Register("crawler", Registration{
+		New:     func() Scanner { return NewCrawlerScanner() },
+		Options: func() interface{} { return &CrawlerOptions{} },
+	})
+}
+
+// NewCrawlerScanner 创建网页爬虫
+func NewCrawlerScanner() *CrawlerScanner {
+	return &CrawlerScanner{
+		BaseScanner: BaseScanner{name: "crawler"},
+	}
+}
+
+// crawlItem 待抓取的URL
+type crawlItem struct {
+	u     *url.URL
+	depth int
+	kind  string // page/js/form
+}
+
+// crawlPage 抓取结果
+type crawlPage struct {
+	item          crawlItem
+	status        int
+	contentType   string
+	contentLength int64
+	title         string
+	body          []byte
+	location      string
+}
+
+// crawler 单次爬取的状态
+type crawler struct {
+	opts   *CrawlerOptions
+	client *http.Client
+	logf   func(level, format string, args ...interface{})
+
+	seeds      map[string]bool // 起始站点 scheme://host:port
+	seedHosts  map[string]bool // 起始主机
+	seedRoots  map[string]bool // 起始根域名
+	subdomains map[string]bool
+
+	mu       sync.Mutex
+	visited  map[string]bool
+	pages    map[string]int // 每个站点已抓取的页面数
+	rendered map[string]int // 每个站点已渲染的页面数
+	results  []*Asset
+	sites    map[string]*Asset
+	secrets  map[string]*Vulnerability
+	js       *jsExtractor
+	browser  *crawlBrowser
+}
+
+// Scan 执行爬取
+func (s *CrawlerScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	opts, ok := config.Options.(*CrawlerOptions)
+	if !ok || opts == nil {
+		opts = &CrawlerOptions{}
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 3
+	}
+	if opts.MaxPages <= 0 {
+		opts.MaxPages = 200
+	}
+	if opts.Threads <= 0 {
+		opts.Threads = 10
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 10
+	}
+	if opts.HeadlessPages <= 0 {
+		opts.HeadlessPages = 20
+	}
+	switch opts.Scope {
+	case CrawlScopeHost, CrawlScopeDomain:
+	default:
+		opts.Scope = CrawlScopeSite
+	}
+	if opts.UserAgent == "" {
+		opts.UserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36"
+	}
+
+	c := &crawler{
+		opts: opts,
+		client: &http.Client{
+			Transport: &http.Transport{
+				TLSClientConfig:     &tls.Config{InsecureSkipVerify: true},
+				MaxIdleConnsPerHost: opts.Threads,
+				IdleConnTimeout:     30 * time.Second,
+			},
+			Timeout: time.Duration(opts.Timeout) * time.Second,
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		logf: func(level, format string, args ...interface{}) {
+			if config.TaskLogger != nil {
+				config.TaskLogger(level, format, args...)
+			}
+		},
+		seeds:      make(map[string]bool),
+		seedHosts:  make(map[string]bool),
+		seedRoots:  make(map[string]bool),
+		subdomains: make(map[string]bool),
+		visited:    make(map[string]bool),
+		pages:      make(map[string]int),
+		rendered:   make(map[string]int),
+		sites:      make(map[string]*Asset),
+		secrets:    make(map[string]*Vulnerability),
+	}
+
+	var queue []crawlItem
+	for _, asset := range config.Assets {
+		if !asset.IsHTTP || asset.Path != "" || asset.Port <= 0 {
+			continue
+		}
+		base := crawlBaseURL(asset)
+		if c.seeds[base.Host] {
+			continue
+		}
+		c.seeds[base.Host] = true
+		c.seedHosts[strings.ToLower(base.Hostname())] = true
+		if root := crawlRootDomain(base.Hostname()); root != "" {
+			c.seedRoots[root] = true
+		}
+		c.sites[base.Scheme+"://"+base.Host] = asset
+		queue = append(queue, crawlItem{u: base, depth: 0, kind: "page"})
+	}
+	if len(queue) == 0 {
+		c.logf("INFO", "Crawler: no HTTP sites")
+		return &ScanResult{WorkspaceId: config.WorkspaceId, MainTaskId: config.MainTaskId}, nil
+	}
+	c.logf("INFO", "Crawler: %d sites, depth %d, scope %s", len(queue), opts.MaxDepth, opts.Scope)
+
+	if opts.ParseJS {
+		var roots []string
+		for root := range c.seedRoots {
+			roots = append(roots, root)
+		}
+		c.js = newJSExtractor(roots)
+	}
+	if opts.Headless {
+		browser, err := newCrawlBrowser(ctx, opts)
+		if err != nil {
+			c.logf("WARN", "Crawler: headless browser unavailable, falling back to static crawl: %v", err)
+		} else {
+			c.browser = browser
+			defer browser.Close()
+		}
+	}
+
+	// robots.txt 和 sitemap.xml 中的地址作为第一层
+	for _, item := range queue {
+		queue = append(queue, c.discoverSeedFiles(ctx, item.u)...)
+	}
+
+	// 按层广度优先抓取
+	for depth := 0; len(queue) > 0 && ctx.Err() == nil; depth++ {
+		next := c.crawlLevel(ctx, queue)
+		queue = queue[:0]
+		for _, item := range next {
+			if item.depth <= opts.MaxDepth {
+				queue = append(queue, item)
+			}
+		}
+		if config.OnProgress != nil {
+			progress := (depth + 1) * 100 / (opts.MaxDepth + 1)
+			if progress > 100 {
+				progress = 100
+			}
+			config.OnProgress(progress, fmt.Sprintf("爬取深度 %d/%d，已发现 %d 个URL", depth+1, opts.MaxDepth+1, len(c.results)))
+		}
+	}
+
+	result := &ScanResult{WorkspaceId: config.WorkspaceId, MainTaskId: config.MainTaskId}
+	result.Assets = append(result.Assets, c.results...)
+	for key, asset := range c.sites {
+		// 只返回爬取中新发现的站点，起始站点已是已知资产
+		if c.seeds[strings.TrimPrefix(strings.TrimPrefix(key, "https://"), "http://")] {
+			continue
+		}
+		result.Assets = append(result.Assets, asset)
+	}
+	subdomains := make([]string, 0, len(c.subdomains))
+	for domain := range c.subdomains {
+		subdomains = append(subdomains, domain)
+	}
+	sort.Strings(subdomains)
+	for _, domain := range subdomains {
+		result.Assets = append(result.Assets, &Asset{
+			Authority: domain,
+			Host:      domain,
+			Category:  "domain",
+			Source:    "crawler",
+		})
+	}
+	for _, vul := range c.secrets {
+		result.Vulnerabilities = append(result.Vulnerabilities, vul)
+	}
+
+	c.logf("INFO", "Crawler completed: %d URLs, %d subdomains, %d secrets", len(c.results), len(subdomains), len(c.secrets))
+	return result, ctx.Err()
+}
+
+// crawlLevel 并发抓取同一层的URL，返回下一层
+func (c *crawler) crawlLevel(ctx context.Context, items []crawlItem) []crawlItem {
+	var next []crawlItem
+	var nextMu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, c.opts.Threads)
+	for _, item := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		if !c.claim(item) {
+			continue
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item crawlItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			found := c.visit(ctx, item)
+			nextMu.Lock()
+			next = append(next, found...)
+			nextMu.Unlock()
+		}(item)
+	}
+	wg.Wait()
+	return next
+}
+
+// claim 检查URL是否已访问以及站点页面数上限，通过则标记为已访问
+func (c *crawler) claim(item crawlItem) bool {
+	key := crawlURLKey(item.u)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.visited[key] {
+		return false
+	}
+	if item.kind != "js" && c.pages[item.u.Host] >= c.opts.MaxPages {
+		return false
+	}
+	c.visited[key] = true
+	if item.kind != "js" {
+		c.pages[item.u.Host]++
+	}
+	return true
+}
+
+// visit 抓取单个URL并提取链接
+func (c *crawler) visit(ctx context.Context, item crawlItem) []crawlItem {
+	var links []string
+	var formLinks []string
+
+	// 前端路由只能由浏览器渲染
+	if isHashRoute(item.u) {
+		if c.browser == nil {
+			c.record(&crawlPage{item: item})
+			return nil
+		}
+	} else {
+		page, err := c.fetch(ctx, item)
+		if err != nil {
+			return nil
+		}
+		if page.location != "" {
+			links = append(links, page.location)
+		}
+		switch {
+		case item.kind == "js" || isJavaScript(page.contentType, item.u.Path):
+			if c.js != nil {
+				links = append(links, c.scanJS(item.u, page.body)...)
+			}
+		case strings.Contains(page.contentType, "html") || page.contentType == "":
+			doc := parseCrawlHTML(item.u, page.body)
+			page.title = doc.title
+			links = append(links, doc.links...)
+			if c.js != nil {
+				links = append(links, doc.scripts...)
+				for _, inline := range doc.inlineJS {
+					links = append(links, c.scanJS(item.u, []byte(inline))...)
+				}
+			}
+			for _, form := range doc.forms {
+				if form.method == http.MethodGet && c.opts.Forms {
+					formLinks = append(formLinks, form.url)
+				} else if u := c.resolve(item.u, form.url); u != nil {
+					// POST 表单只记录地址，不提交以免产生副作用
+					c.recordEndpoint(u, item.depth+1)
+				}
+			}
+		}
+		// 不存在的地址不记录，但仍跟随重定向
+		if page.status != http.StatusNotFound && page.status != http.StatusGone {
+			c.record(page)
+		}
+	}
+
+	if c.browser != nil && item.kind == "page" && c.claimRender(item.u) {
+		links = append(links, c.browser.Render(ctx, item.u.String())...)
+	}
+
+	var next []crawlItem
+	for _, link := range links {
+		if u := c.resolve(item.u, link); u != nil {
+			if crawlStaticExts[strings.ToLower(path.Ext(u.Path))] {
+				continue
+			}
+			kind := "page"
+			if isJavaScript("", u.Path) {
+				kind = "js"
+			}
+			next = append(next, crawlItem{u: u, depth: item.depth + 1, kind: kind})
+		}
+	}
+	for _, link := range formLinks {
+		if u := c.resolve(item.u, link); u != nil {
+			next = append(next, crawlItem{u: u, depth: item.depth + 1, kind: "form"})
+		}
+	}
+	return next
+}
+
+func (c *crawler) claimRender(u *url.URL) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.rendered[u.Host] >= c.opts.HeadlessPages {
+		return false
+	}
+	c.rendered[u.Host]++
+	return true
+}
+
+// fetch 发起GET请求
+func (c *crawler) fetch(ctx context.Context, item crawlItem) (*crawlPage, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, item.u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", c.opts.UserAgent)
+	req.Header.Set("Accept", "text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8")
+	for k, v := range c.opts.Headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	page := &crawlPage{
+		item:          item,
+		status:        resp.StatusCode,
+		contentType:   strings.ToLower(resp.Header.Get("Content-Type")),
+		contentLength: resp.ContentLength,
+		location:      resp.Header.Get("Location"),
+	}
+	page.body, _ = io.ReadAll(io.LimitReader(resp.Body, crawlMaxBody))
+	if page.contentLength < 0 {
+		page.contentLength = int64(len(page.body))
+	}
+	return page, nil
+}
+
+// record 记录抓取到的URL
+func (c *crawler) record(page *crawlPage) {
+	u := page.item.u
+	asset := crawlURLAsset(u)
+	if page.status > 0 {
+		asset.HttpStatus = strconv.Itoa(page.status)
+	}
+	asset.Title = page.title
+	asset.ContentLength = page.contentLength
+	asset.ContentType = page.contentType
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.results = append(c.results, asset)
+	siteKey := u.Scheme + "://" + u.Host
+	if _, ok := c.sites[siteKey]; !ok {
+		c.sites[siteKey] = &Asset{
+			Authority: u.Host,
+			Host:      u.Hostname(),
+			Port:      asset.Port,
+			Category:  getCategory(u.Hostname()),
+			Service:   u.Scheme,
+			IsHTTP:    true,
+			Source:    "crawler",
+		}
+	}
+}
+
+// recordEndpoint 记录不抓取的接口地址（POST表单等）
+func (c *crawler) recordEndpoint(u *url.URL, depth int) {
+	key := crawlURLKey(u)
+	c.mu.Lock()
+	if c.visited[key] {
+		c.mu.Unlock()
+		return
+	}
+	c.visited[key] = true
+	c.mu.Unlock()
+	c.record(&crawlPage{item: crawlItem{u: u, depth: depth, kind: "form"}})
+}
+
+// scanJS 提取JS中的接口、敏感信息和子域名，返回发现的接口地址
+func (c *crawler) scanJS(source *url.URL, body []byte) []string {
+	found := c.js.Extract(body)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, domain := range found.Subdomains {
+		if !c.seedHosts[domain] {
+			c.subdomains[domain] = true
+		}
+	}
+	for _, secret := range found.Secrets {
+		key := source.Host + "|" + secret.Kind + "|" + secret.Value
+		if _, ok := c.secrets[key]; ok {
+			continue
+		}
+		c.secrets[key] = secretVul(source, secret)
+	}
+	return found.Endpoints
+}
+
+// resolve 解析相对地址并检查范围，不在范围内返回 nil
+func (c *crawler) resolve(base *url.URL, ref string) *url.URL {
+	ref = strings.TrimSpace(ref)
+	u := base
+	if ref != "" {
+		lower := strings.ToLower(ref)
+		if strings.HasPrefix(lower, "javascript:") || strings.HasPrefix(lower, "mailto:") ||
+			strings.HasPrefix(lower, "tel:") || strings.HasPrefix(lower, "data:") {
+			return nil
+		}
+		parsed, err := base.Parse(ref)
+		if err != nil {
+			return nil
+		}
+		u = parsed
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil
+	}
+	u = normalizeCrawlURL(u)
+	if !c.inScope(u) {
+		return nil
+	}
+	return u
+}
+
+// inScope 判断URL是否在爬取范围内
+func (c *crawler) inScope(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	var ok bool
+	switch c.opts.Scope {
+	case CrawlScopeHost:
+		ok = c.seedHosts[host]
+	case CrawlScopeDomain:
+		root := crawlRootDomain(host)
+		ok = c.seedHosts[host] || (root != "" && c.seedRoots[root])
+	default:
+		ok = c.seeds[u.Host]
+	}
+	if !ok || c.opts.AssetAllowed == nil {
+		return ok
+	}
+	// 同一根域名下被工作空间排除的子域名和端口不入队
+	port, _ := strconv.Atoi(u.Port())
+	if port == 0 {
+		port = 80
+		if u.Scheme == "https" {
+			port = 443
+		}
+	}
+	return c.opts.AssetAllowed(host, port)
+}
+
+// discoverSeedFiles 解析站点的 robots.txt 和 sitemap.xml
+func (c *crawler) discoverSeedFiles(ctx context.Context, base *url.URL) []crawlItem {
+	var items []crawlItem
+	add := func(ref string) {
+		if u := c.resolve(base, ref); u != nil {
+			items = append(items, crawlItem{u: u, depth: 1, kind: "page"})
+		}
+	}
+
+	sitemaps := []string{"/sitemap.xml"}
+	if body := c.fetchText(ctx, base, "/robots.txt"); body != nil {
+		paths, maps := parseRobots(body)
+		for _, p := range paths {
+			add(p)
+		}
+		sitemaps = append(sitemaps, maps...)
+	}
+
+	// 站点地图可能嵌套，最多解析5个
+	seen := make(map[string]bool)
+	for i := 0; i < len(sitemaps) && len(seen) < 5; i++ {
+		if seen[sitemaps[i]] {
+			continue
+		}
+		seen[sitemaps[i]] = true
+		body := c.fetchText(ctx, base, sitemaps[i])
+		if body == nil {
+			continue
+		}
+		locs, nested := parseSitemap(body)
+		for j, loc := range locs {
+			if j >= c.opts.MaxPages {
+				break
+			}
+			add(loc)
+		}
+		sitemaps = append(sitemaps, nested...)
+	}
+	if len(items) > 0 {
+		c.logf("INFO", "Crawler %s: %d URLs from robots.txt/sitemap", base.Host, len(items))
+	}
+	return items
+}
+
+// fetchText 获取站点内的文本文件，非200返回 nil
+func (c *crawler) fetchText(ctx context.Context, base *url.URL, ref string) []byte {
+	u := c.resolve(base, ref)
+	if u == nil {
+		return nil
+	}
+	page, err := c.fetch(ctx, crawlItem{u: u, kind: "file"})
+	if err != nil || page.status != http.StatusOK || len(page.body) == 0 {
+		return nil
+	}
+	// 不存在时部分站点返回首页
+	if strings.Contains(page.contentType, "html") {
+		return nil
+	}
+	return page.body
+}
+
+// crawlHTML 页面解析结果
+type crawlHTML struct {
+	title    string
+	links    []string
+	scripts  []string
+	inlineJS []string
+	forms    []crawlForm
+}
+
+type crawlForm struct {
+	method string
+	url    string
+}
+
+// parseCrawlHTML 解析页面中的链接、脚本和表单
+func parseCrawlHTML(base *url.URL, body []byte) *crawlHTML {
+	doc := &crawlHTML{}
+	z := html.NewTokenizer(bytes.NewReader(body))
+	var form *crawlForm
+	var formValues url.Values
+	var inScript, inTitle bool
+	abs := func(ref string) string {
+		if u, err := base.Parse(strings.TrimSpace(ref)); err == nil {
+			return u.String()
+		}
+		return ref
+	}
+
+	flushForm := func() {
+		if form == nil {
+			return
+		}
+		if form.method == http.MethodGet && len(formValues) > 0 {
+			if u, err := url.Parse(form.url); err == nil {
+				u.RawQuery = formValues.Encode()
+				form.url = u.String()
+			}
+		}
+		doc.forms = append(doc.forms, *form)
+		form = nil
+	}
+
+	for {
+		tt := z.Next()
+		switch tt {
+		case html.ErrorToken:
+			flushForm()
+			return doc
+		case html.TextToken:
+			if inScript {
+				if text := strings.TrimSpace(string(z.Text())); text != "" {
+					doc.inlineJS = append(doc.inlineJS, text)
+				}
+			} else if inTitle && doc.title == "" {
+				doc.title = strings.TrimSpace(string(z.Text()))
+			}
+		case html.EndTagToken:
+			name, _ := z.TagName()
+			switch string(name) {
+			case "script":
+				inScript = false
+			case "title":
+				inTitle = false
+			case "form":
+				flushForm()
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, hasAttr := z.TagName()
+			attrs := make(map[string]string)
+			for hasAttr {
+				var k, v []byte
+				k, v, hasAttr = z.TagAttr()
+				attrs[string(k)] = string(v)
+			}
+			switch tag := string(name); tag {
+			case "a", "area", "link":
+				if href := attrs["href"]; href != "" {
+					doc.links = append(doc.links, abs(href))
+				}
+			case "iframe", "frame", "embed":
+				if src := attrs["src"]; src != "" {
+					doc.links = append(doc.links, abs(src))
+				}
+			case "base":
+				if href := attrs["href"]; href != "" {
+					if u, err := base.Parse(href); err == nil {
+						base = u
+					}
+				}
+			case "meta":
+				if strings.EqualFold(attrs["http-equiv"], "refresh") {
+					if i := strings.Index(strings.ToLower(attrs["content"]), "url="); i >= 0 {
+						doc.links = append(doc.links, abs(strings.Trim(attrs["content"][i+4:], `'" `)))
+					}
+				}
+			case "script":
+				if src := attrs["src"]; src != "" {
+					doc.scripts = append(doc.scripts, abs(src))
+				} else if tt == html.StartTagToken {
+					inScript = true
+				}
+			case "title":
+				inTitle = tt == html.StartTagToken
+			case "form":
+				flushForm()
+				method := strings.ToUpper(attrs["method"])
+				if method == "" {
+					method = http.MethodGet
+				}
+				form = &crawlForm{method: method, url: abs(attrs["action"])}
+				formValues = url.Values{}
+			case "input", "select", "textarea", "button":
+				if form == nil || attrs["name"] == "" {
+					continue
+				}
+				switch strings.ToLower(attrs["type"]) {
+				case "submit", "button", "image", "reset", "file":
+					continue
+				}
+				formValues.Set(attrs["name"], crawlFormValue(attrs))
+			}
+		}
+	}
+}
+
+// crawlFormValue 表单字段的默认填充值
+func crawlFormValue(attrs map[string]string) string {
+	if v := attrs["value"]; v != "" {
+		return v
+	}
+	switch strings.ToLower(attrs["type"]) {
+	case "email":
+		return "test@example.com"
+	case "number", "range":
+		return "1"
+	case "url":
+		return "http://example.com"
+	case "checkbox", "radio":
+		return "on"
+	}
+	return "test"
+}
+
+// parseRobots 提取 robots.txt 中的路径和站点地图
+func parseRobots(body []byte) (paths, sitemaps []string) {
+	for _, line := range strings.Split(string(body), "\n") {
+		line = strings.TrimSpace(line)
+		if i := strings.Index(line, "#"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "allow", "disallow":
+			// 通配规则只保留通配符之前的目录
+			if i := strings.IndexAny(value, "*$"); i >= 0 {
+				value = value[:i]
+			}
+			if strings.HasPrefix(value, "/") && value != "/" {
+				paths = append(paths, value)
+			}
+		case "sitemap":
+			if value != "" {
+				sitemaps = append(sitemaps, value)
+			}
+		}
+	}
+	return paths, sitemaps
+}
+
+// parseSitemap 解析站点地图，返回页面地址和嵌套的站点地图地址
+func parseSitemap(body []byte) (locs, nested []string) {
+	var doc struct {
+		XMLName xml.Name
+		URLs    []struct {
+			Loc string `xml:"loc"`
+		} `xml:"url"`
+		Sitemaps []struct {
+			Loc string `xml:"loc"`
+		} `xml:"sitemap"`
+	}
+	if err := xml.Unmarshal(body, &doc); err != nil {
+		return nil, nil
+	}
+	for _, u := range doc.URLs {
+		if loc := strings.TrimSpace(u.Loc); loc != "" {
+			locs = append(locs, loc)
+		}
+	}
+	for _, s := range doc.Sitemaps {
+		if loc := strings.TrimSpace(s.Loc); loc != "" {
+			nested = append(nested, loc)
+		}
+	}
+	return locs, nested
+}
+
+// crawlBaseURL 资产的站点根地址
+func crawlBaseURL(asset *Asset) *url.URL {
+	scheme := "http"
+	if strings.HasPrefix(asset.Service, "https") || strings.HasPrefix(asset.Service, "ssl/") || asset.Port == 443 || asset.Port == 8443 {
+		scheme = "https"
+	}
+	return normalizeCrawlURL(&url.URL{Scheme: scheme, Host: net.JoinHostPort(asset.Host, strconv.Itoa(asset.Port)), Path: "/"})
+}
+
+// crawlURLAsset 将URL转换为资产，Path 保存含查询参数的路径
+func crawlURLAsset(u *url.URL) *Asset {
+	port := 80
+	if u.Scheme == "https" {
+		port = 443
+	}
+	if p, err := strconv.Atoi(u.Port()); err == nil {
+		port = p
+	}
+	p := u.RequestURI()
+	if u.Fragment != "" {
+		p += "#" + u.Fragment
+	}
+	return &Asset{
+		Authority: u.Host,
+		Host:      u.Hostname(),
+		Port:      port,
+		Category:  "url",
+		Service:   u.Scheme,
+		IsHTTP:    true,
+		Source:    "crawler",
+		Path:      p,
+	}
+}
+
+// normalizeCrawlURL 规范化URL：小写主机、去掉默认端口，只保留前端路由形式的片段
+func normalizeCrawlURL(u *url.URL) *url.URL {
+	n := *u
+	n.Host = strings.ToLower(n.Host)
+	if (n.Scheme == "http" && n.Port() == "80") || (n.Scheme == "https" && n.Port() == "443") {
+		n.Host = n.Hostname()
+		if strings.Contains(n.Host, ":") {
+			n.Host = "[" + n.Host + "]"
+		}
+	}
+	if n.Path == "" {
+		n.Path = "/"
+	}
+	if !isHashRoute(&n) {
+		n.Fragment = ""
+		n.RawFragment = ""
+	}
+	n.User = nil
+	return &n
+}
+
+// crawlURLKey 去重键，查询参数只比较参数名，避免翻页、日历等无限URL
+func crawlURLKey(u *url.URL) string {
+	key := u.Scheme + "://" + u.Host + u.EscapedPath()
+	if q := u.Query(); len(q) > 0 {
+		names := make([]string, 0, len(q))
+		for name := range q {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		key += "?" + strings.Join(names, "&")
+	}
+	if u.Fragment != "" {
+		key += "#" + u.Fragment
+	}
+	return key
+}
+
+// isHashRoute 判断是否为 #/ 或 #!/ 形式的前端路由
+func isHashRoute(u *url.URL) bool {
+	return strings.HasPrefix(u.Fragment, "/") || strings.HasPrefix(u.Fragment, "!/")
+}
+
+func isJavaScript(contentType, p string) bool {
+	if strings.Contains(contentType, "javascript") || strings.Contains(contentType, "ecmascript") {
+		return true
+	}
+	ext := strings.ToLower(path.Ext(p))
+	return ext == ".js" || ext == ".mjs"
+}
+
+// crawlRootDomain 获取根域名，IP返回空
+func crawlRootDomain(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if net.ParseIP(host) != nil {
+		return ""
+	}
+	root, err := publicsuffix.EffectiveTLDPlusOne(host)
+	if err != nil {
+		return ""
+	}
+	return root
+}
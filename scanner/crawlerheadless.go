@@ -0,0 +1,104 @@
+package scanner
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/chromedp/cdproto/network"
+	"github.com/chromedp/chromedp"
+)
+
+// crawlRenderJS 收集渲染后页面中的链接、资源、表单地址和前端路由
+const crawlRenderJS = `(() => {
+	const out = new Set([location.href]);
+	document.querySelectorAll('a[href],area[href],link[href]').forEach(e => out.add(e.href));
+	document.querySelectorAll('script[src],iframe[src],frame[src]').forEach(e => out.add(e.src));
+	document.querySelectorAll('form').forEach(e => out.add(e.action));
+	document.querySelectorAll('[routerlink],[to],[data-href],[data-url]').forEach(e => {
+		for (const name of ['routerlink', 'to', 'data-href', 'data-url']) {
+			const v = e.getAttribute(name);
+			if (!v) continue;
+			try {
+				const hashRouter = location.hash.startsWith('#/') && v.startsWith('/');
+				out.add(new URL(hashRouter ? '#' + v : v, location.href).href);
+			} catch (err) {}
+		}
+	});
+	return Array.from(out).filter(u => typeof u === 'string' && u.startsWith('http'));
+})()`
+
+// crawlBrowser 复用同一个浏览器进程渲染页面
+type crawlBrowser struct {
+	ctx     context.Context
+	cancel  context.CancelFunc
+	timeout time.Duration
+	mu      sync.Mutex // 限制同时打开的标签页
+}
+
+func newCrawlBrowser(ctx context.Context, opts *CrawlerOptions) (*crawlBrowser, error) {
+	allocCtx, allocCancel := chromedp.NewExecAllocator(ctx, chromeAllocatorOptions()...)
+	browserCtx, browserCancel := chromedp.NewContext(allocCtx)
+	// 启动浏览器
+	if err := chromedp.Run(browserCtx); err != nil {
+		browserCancel()
+		allocCancel()
+		return nil, err
+	}
+	timeout := time.Duration(opts.Timeout)*time.Second + 5*time.Second
+	return &crawlBrowser{
+		ctx: browserCtx,
+		cancel: func() {
+			browserCancel()
+			allocCancel()
+		},
+		timeout: timeout,
+	}, nil
+}
+
+// Render 渲染页面，返回DOM中的链接和页面发起的请求地址
+func (b *crawlBrowser) Render(ctx context.Context, target string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	tabCtx, tabCancel := chromedp.NewContext(b.ctx)
+	defer tabCancel()
+	tabCtx, cancel := context.WithTimeout(tabCtx, b.timeout)
+	defer cancel()
+	stop := context.AfterFunc(ctx, cancel)
+	defer stop()
+
+	var reqMu sync.Mutex
+	var requests []string
+	chromedp.ListenTarget(tabCtx, func(ev any) {
+		if e, ok := ev.(*network.EventRequestWillBeSent); ok {
+			switch e.Type {
+			case network.ResourceTypeXHR, network.ResourceTypeFetch, network.ResourceTypeScript, network.ResourceTypeDocument:
+				reqMu.Lock()
+				requests = append(requests, e.Request.URL)
+				reqMu.Unlock()
+			}
+		}
+	})
+
+	var links []string
+	err := chromedp.Run(tabCtx,
+		network.Enable(),
+		chromedp.Navigate(target),
+		chromedp.WaitReady("body", chromedp.ByQuery),
+		// 等待前端路由和异步请求完成
+		chromedp.Sleep(2*time.Second),
+		chromedp.Evaluate(crawlRenderJS, &links),
+	)
+	if err != nil && len(links) == 0 {
+		return nil
+	}
+
+	reqMu.Lock()
+	defer reqMu.Unlock()
+	return append(links, requests...)
+}
+
+func (b *crawlBrowser) Close() {
+	b.cancel()
+}
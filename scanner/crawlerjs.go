@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// jsEndpointRegex 匹配JS字符串中的路径和URL
+var jsEndpointRegex = regexp.MustCompile(`["'` + "`" + `]((?:https?:)?//[a-zA-Z0-9.\-]+(?::\d+)?(?:/[^"'` + "`" + `\s<>]*)?|/[a-zA-Z0-9_\-.~%/]+(?:\?[^"'` + "`" + `\s<>]*)?|[a-zA-Z0-9_\-]+(?:/[a-zA-Z0-9_\-.]+)+\.(?:php|asp|aspx|jsp|json|action|do|html|htm|js|xml)(?:\?[^"'` + "`" + `\s<>]*)?|(?:api|v\d)/[a-zA-Z0-9_\-/.]+)["'` + "`" + `]`)
+
+// jsSecretRule 敏感信息匹配规则
+type jsSecretRule struct {
+	kind     string
+	severity string
+	re       *regexp.Regexp
+}
+
+// jsSecretRules 常见密钥和令牌格式，有分组时取第一个分组
+var jsSecretRules = []jsSecretRule{
+	{"aws-access-key", "high", regexp.MustCompile(`\b((?:AKIA|ASIA)[0-9A-Z]{16})\b`)},
+	{"aliyun-access-key", "high", regexp.MustCompile(`\b(LTAI[0-9A-Za-z]{12,20})\b`)},
+	{"tencent-secret-id", "high", regexp.MustCompile(`\b(AKID[0-9A-Za-z]{13,32})\b`)},
+	{"google-api-key", "medium", regexp.MustCompile(`\b(AIza[0-9A-Za-z\-_]{35})\b`)},
+	{"github-token", "high", regexp.MustCompile(`\b(gh[pousr]_[0-9A-Za-z]{36,})\b`)},
+	{"slack-token", "high", regexp.MustCompile(`\b(xox[abposr]-[0-9A-Za-z\-]{10,72})\b`)},
+	{"slack-webhook", "medium", regexp.MustCompile(`(https://hooks\.slack\.com/services/[A-Za-z0-9_/]{20,})`)},
+	{"stripe-secret-key", "high", regexp.MustCompile(`\b((?:sk|rk)_live_[0-9A-Za-z]{24,})\b`)},
+	{"private-key", "high", regexp.MustCompile(`(-----BEGIN (?:RSA |EC |DSA |OPENSSH |PGP )?PRIVATE KEY(?: BLOCK)?-----)`)},
+	{"jwt", "low", regexp.MustCompile(`\b(eyJ[A-Za-z0-9_\-]{10,}\.eyJ[A-Za-z0-9_\-]{10,}\.[A-Za-z0-9_\-]{10,})\b`)},
+	{"generic-secret", "medium", regexp.MustCompile(`(?i)["']?(?:api[_-]?key|api[_-]?secret|secret[_-]?key|access[_-]?token|access[_-]?key[_-]?secret|client[_-]?secret|app[_-]?secret)["']?\s*[:=]\s*["']([0-9A-Za-z_\-+/=]{16,128})["']`)},
+}
+
+// jsFindings JS解析结果
+type jsFindings struct {
+	Endpoints  []string
+	Secrets    []jsSecret
+	Subdomains []string
+}
+
+// jsSecret 发现的敏感信息，Value 为原值，只在内存中用于去重
+type jsSecret struct {
+	Kind     string
+	Severity string
+	Value    string
+}
+
+// jsExtractor 从JS中提取接口、敏感信息和子域名
+type jsExtractor struct {
+	subdomainRe *regexp.Regexp
+}
+
+func newJSExtractor(roots []string) *jsExtractor {
+	e := &jsExtractor{}
+	if len(roots) > 0 {
+		quoted := make([]string, 0, len(roots))
+		for _, root := range roots {
+			quoted = append(quoted, regexp.QuoteMeta(root))
+		}
+		e.subdomainRe = regexp.MustCompile(`(?i)\b((?:[a-z0-9](?:[a-z0-9\-]{0,61}[a-z0-9])?\.)+(?:` + strings.Join(quoted, "|") + `))\b`)
+	}
+	return e
+}
+
+// Extract 解析JS内容
+func (e *jsExtractor) Extract(body []byte) *jsFindings {
+	text := string(body)
+	found := &jsFindings{}
+
+	seen := make(map[string]bool)
+	for _, m := range jsEndpointRegex.FindAllStringSubmatch(text, -1) {
+		endpoint := m[1]
+		if seen[endpoint] || !isLikelyEndpoint(endpoint) {
+			continue
+		}
+		seen[endpoint] = true
+		found.Endpoints = append(found.Endpoints, endpoint)
+	}
+
+	for _, rule := range jsSecretRules {
+		for _, m := range rule.re.FindAllStringSubmatch(text, -1) {
+			value := m[0]
+			if len(m) > 1 {
+				value = m[1]
+			}
+			if seen[rule.kind+value] || isPlaceholderSecret(value) {
+				continue
+			}
+			seen[rule.kind+value] = true
+			found.Secrets = append(found.Secrets, jsSecret{Kind: rule.kind, Severity: rule.severity, Value: value})
+		}
+	}
+
+	if e.subdomainRe != nil {
+		for _, m := range e.subdomainRe.FindAllStringSubmatch(text, -1) {
+			domain := strings.ToLower(m[1])
+			if !seen[domain] {
+				seen[domain] = true
+				found.Subdomains = append(found.Subdomains, domain)
+			}
+		}
+	}
+	return found
+}
+
+// isLikelyEndpoint 过滤MIME类型、注释、模板等误报
+func isLikelyEndpoint(s string) bool {
+	if len(s) < 2 || len(s) > 512 || s == "//" {
+		return false
+	}
+	if strings.ContainsAny(s, "{}$<>\\") || strings.HasPrefix(s, "//") && !strings.Contains(s[2:], ".") {
+		return false
+	}
+	switch strings.SplitN(s, "/", 2)[0] {
+	case "text", "application", "image", "audio", "video", "font", "multipart":
+		return false
+	}
+	return true
+}
+
+// isPlaceholderSecret 过滤示例值
+func isPlaceholderSecret(s string) bool {
+	lower := strings.ToLower(s)
+	for _, word := range []string{"example", "xxxx", "your", "test", "dummy", "placeholder", "changeme"} {
+		if strings.Contains(lower, word) {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSecret 密钥脱敏，保留首尾各4个字符
+func MaskSecret(s string) string {
+	r := []rune(s)
+	if len(r) <= 12 {
+		return MaskPassword(s)
+	}
+	return string(r[:4]) + strings.Repeat("*", len(r)-8) + string(r[len(r)-4:])
+}
+
+// secretVul 将JS中的敏感信息转换为漏洞，只保存脱敏后的值
+func secretVul(source *url.URL, secret jsSecret) *Vulnerability {
+	masked := MaskSecret(secret.Value)
+	port := 80
+	if source.Scheme == "https" {
+		port = 443
+	}
+	fmt.Sscanf(source.Port(), "%d", &port)
+	extra, _ := json.Marshal(map[string]string{
+		"kind":  secret.Kind,
+		"value": masked,
+		"file":  source.String(),
+	})
+	return &Vulnerability{
+		Authority:   source.Host,
+		Host:        source.Hostname(),
+		Port:        port,
+		Url:         source.String(),
+		PocFile:     "js-secret-" + secret.Kind,
+		Source:      "crawler",
+		Severity:    secret.Severity,
+		Extra:       string(extra),
+		Result:      fmt.Sprintf("JS敏感信息泄露 %s: %s", secret.Kind, masked),
+		Remediation: "从前端代码中移除密钥，改由服务端代理调用，并立即轮换已泄露的凭据",
+	}
+}
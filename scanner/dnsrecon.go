@@ -0,0 +1,835 @@
+package scanner
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+//go:embed wordlists/subdomains.txt
+var defaultSubdomainWords string
+
+// 默认记录类型，SRV 记录按常见服务名查询
+var defaultDNSRecordTypes = []string{"A", "AAAA", "CNAME", "MX", "NS", "TXT", "SOA", "SRV", "CAA"}
+
+// 常见 SRV 服务名
+var dnsSRVServices = []string{
+	"_sip._tcp", "_sip._udp", "_sips._tcp", "_sipfederationtls._tcp",
+	"_ldap._tcp", "_kerberos._tcp", "_kerberos._udp", "_kpasswd._tcp", "_gc._tcp",
+	"_xmpp-server._tcp", "_xmpp-client._tcp", "_autodiscover._tcp",
+	"_caldav._tcp", "_carddav._tcp", "_imaps._tcp", "_submission._tcp", "_pop3s._tcp",
+	"_minecraft._tcp", "_matrix._tcp",
+}
+
+// 排列组合时插入的常见词
+var dnsPermutationWords = []string{
+	"dev", "test", "qa", "uat", "stage", "staging", "pre", "prod", "beta", "demo",
+	"api", "admin", "internal", "old", "new", "backup", "v1", "v2", "web", "app",
+}
+
+// 公共DNS，系统配置不可用时使用
+var defaultDNSResolvers = []string{"223.5.5.5", "119.29.29.29", "8.8.8.8", "1.1.1.1"}
+
+// DNSReconOptions 主动DNS侦察选项
+type DNSReconOptions struct {
+	Brute           bool     `json:"brute"`           // 字典爆破子域名
+	Wordlist        []string `json:"wordlist"`        // 爆破字典，为空使用内置字典
+	Permutation     bool     `json:"permutation"`     // 基于已发现子域名生成排列组合
+	MaxPermutations int      `json:"maxPermutations"` // 排列组合最大数量，默认2000
+	AXFR            bool     `json:"axfr"`            // 尝试域传送
+	Records         bool     `json:"records"`         // 采集DNS记录
+	RecordTypes     []string `json:"recordTypes"`     // 根域名采集的记录类型，为空使用默认
+	Takeover        bool     `json:"takeover"`        // 检测悬空CNAME子域名接管
+	Resolvers       []string `json:"resolvers"`       // DNS服务器，为空使用系统配置
+	Threads         int      `json:"threads"`         // 并发查询数，默认50
+	Timeout         int      `json:"timeout"`         // 单次查询超时(秒)，默认3
+}
+
+// DNSRecord DNS记录
+type DNSRecord struct {
+	Domain string `json:"domain"`
+	Root   string `json:"root"`
+	Type   string `json:"type"`
+	Value  string `json:"value"`
+	TTL    uint32 `json:"ttl"`
+	Source string `json:"source"` // query/axfr
+}
+
+// DNSReconScanner 主动DNS侦察：子域名爆破、排列组合、域传送、记录采集和子域名接管检测
+type DNSReconScanner struct {
+	BaseScanner
+}
+
+func init() {
+	Register("dnsrecon", Registration{
+		New:     func() Scanner { return NewDNSReconScanner() },
+		Options: func() interface{} { return &DNSReconOptions{} },
+	})
+}
+
+// NewDNSReconScanner 创建DNS侦察扫描器
+func NewDNSReconScanner() *DNSReconScanner {
+	return &DNSReconScanner{
+		BaseScanner: BaseScanner{name: "dnsrecon"},
+	}
+}
+
+// dnsAnswer 域名解析结果
+type dnsAnswer struct {
+	Name     string
+	CNAMEs   []string // CNAME 链，按解析顺序
+	IPv4     []string
+	IPv6     []string
+	TTL      uint32
+	NXDomain bool
+}
+
+func (a *dnsAnswer) resolved() bool {
+	return len(a.IPv4) > 0 || len(a.IPv6) > 0 || len(a.CNAMEs) > 0
+}
+
+// dnsRecon 单次侦察的状态
+type dnsRecon struct {
+	opts     *DNSReconOptions
+	resolver *dnsResolver
+	logf     func(level, format string, args ...interface{})
+
+	mu        sync.Mutex
+	wildcards map[string]*dnsWildcard // 父域名 -> 泛解析结果
+	records   map[string]*DNSRecord
+}
+
+// dnsWildcard 泛解析特征，detected 为 false 表示父域名没有泛解析
+type dnsWildcard struct {
+	once     sync.Once
+	detected bool
+	ips      map[string]bool
+	cnames   map[string]bool
+}
+
+// Scan 执行DNS侦察，Target 为根域名，Assets 为已知子域名
+func (s *DNSReconScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	opts, ok := config.Options.(*DNSReconOptions)
+	if !ok || opts == nil {
+		opts = &DNSReconOptions{Records: true}
+	}
+	if opts.Threads <= 0 {
+		opts.Threads = 50
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 3
+	}
+	if opts.MaxPermutations <= 0 {
+		opts.MaxPermutations = 2000
+	}
+	if len(opts.RecordTypes) == 0 {
+		opts.RecordTypes = defaultDNSRecordTypes
+	}
+
+	r := &dnsRecon{
+		opts:      opts,
+		resolver:  newDNSResolver(opts.Resolvers, time.Duration(opts.Timeout)*time.Second),
+		wildcards: make(map[string]*dnsWildcard),
+		records:   make(map[string]*DNSRecord),
+		logf: func(level, format string, args ...interface{}) {
+			if config.TaskLogger != nil {
+				config.TaskLogger(level, format, args...)
+			}
+		},
+	}
+	progress := func(p int, msg string) {
+		if config.OnProgress != nil {
+			config.OnProgress(p, msg)
+		}
+	}
+	result := &ScanResult{WorkspaceId: config.WorkspaceId, MainTaskId: config.MainTaskId}
+
+	// 根域名来自目标，目标中没有域名时从已知子域名推导
+	rootSet := make(map[string]bool)
+	for _, domain := range parseReconDomains(config.Target) {
+		rootSet[domain] = true
+	}
+	deriveRoots := len(rootSet) == 0
+	known := make(map[string]bool)
+	for _, asset := range config.Assets {
+		host := strings.ToLower(strings.TrimSuffix(asset.Host, "."))
+		if host == "" || net.ParseIP(host) != nil {
+			continue
+		}
+		known[host] = true
+		if deriveRoots {
+			if root := crawlRootDomain(host); root != "" {
+				rootSet[root] = true
+			}
+		}
+	}
+	roots := sortedKeys(rootSet)
+	if len(roots) == 0 {
+		r.logf("INFO", "DNS recon: no domains")
+		return result, nil
+	}
+	for _, root := range roots {
+		known[root] = true
+	}
+	r.logf("INFO", "DNS recon: %d root domains, %d known names, resolvers %s", len(roots), len(known), strings.Join(r.resolver.servers, ","))
+
+	// 新发现的子域名及来源
+	found := make(map[string]string)
+	answers := make(map[string]*dnsAnswer)
+	addFound := func(res map[string]*dnsAnswer, source string) int {
+		n := 0
+		for name, ans := range res {
+			answers[name] = ans
+			if !known[name] && found[name] == "" {
+				found[name] = source
+				n++
+			}
+		}
+		return n
+	}
+
+	// 域传送
+	if r.opts.AXFR {
+		progress(5, "尝试域传送")
+		var axfrNames []string
+		for _, root := range roots {
+			if ctx.Err() != nil {
+				break
+			}
+			names, vul := r.zoneTransfer(ctx, root)
+			if vul != nil {
+				result.Vulnerabilities = append(result.Vulnerabilities, vul)
+			}
+			axfrNames = append(axfrNames, names...)
+		}
+		var fresh []string
+		for _, name := range axfrNames {
+			if !known[name] {
+				fresh = append(fresh, name)
+			}
+		}
+		n := addFound(r.resolveAll(ctx, fresh, false), "axfr")
+		r.logf("INFO", "DNS recon: zone transfer found %d new names", n)
+	}
+
+	// 字典爆破
+	if r.opts.Brute && ctx.Err() == nil {
+		progress(15, "子域名爆破")
+		words := r.opts.Wordlist
+		if len(words) == 0 {
+			words = strings.Fields(defaultSubdomainWords)
+		}
+		var candidates []string
+		for _, root := range roots {
+			for _, word := range words {
+				word = strings.ToLower(strings.Trim(strings.TrimSpace(word), "."))
+				if word == "" || strings.HasPrefix(word, "#") {
+					continue
+				}
+				name := word + "." + root
+				if !known[name] {
+					candidates = append(candidates, name)
+				}
+			}
+		}
+		r.logf("INFO", "DNS recon: brute forcing %d names", len(candidates))
+		n := addFound(r.resolveAll(ctx, candidates, true), "dnsbrute")
+		r.logf("INFO", "DNS recon: brute force found %d new names", n)
+	}
+
+	// 排列组合
+	if r.opts.Permutation && ctx.Err() == nil {
+		progress(45, "子域名排列组合")
+		var seeds []string
+		for name := range known {
+			seeds = append(seeds, name)
+		}
+		for name := range found {
+			seeds = append(seeds, name)
+		}
+		exists := func(name string) bool { return known[name] || found[name] != "" }
+		candidates := permuteSubdomains(seeds, roots, exists, r.opts.MaxPermutations)
+		r.logf("INFO", "DNS recon: resolving %d permutations", len(candidates))
+		n := addFound(r.resolveAll(ctx, candidates, true), "permutation")
+		r.logf("INFO", "DNS recon: permutation found %d new names", n)
+	}
+
+	// 已知子域名需要解析结果用于记录采集和接管检测
+	if (r.opts.Records || r.opts.Takeover) && ctx.Err() == nil {
+		progress(65, "解析已知子域名")
+		var pending []string
+		for name := range known {
+			if answers[name] == nil {
+				pending = append(pending, name)
+			}
+		}
+		for name, ans := range r.resolveAll(ctx, pending, false) {
+			answers[name] = ans
+		}
+	}
+
+	if r.opts.Records && ctx.Err() == nil {
+		progress(75, "采集DNS记录")
+		for _, ans := range answers {
+			r.addAnswerRecords(ans)
+		}
+		for _, root := range roots {
+			if ctx.Err() != nil {
+				break
+			}
+			r.collectRootRecords(ctx, root)
+		}
+	}
+
+	if r.opts.Takeover && ctx.Err() == nil {
+		progress(90, "子域名接管检测")
+		result.Vulnerabilities = append(result.Vulnerabilities, r.checkTakeovers(ctx, answers)...)
+	}
+
+	for _, name := range sortedKeys(found) {
+		ans := answers[name]
+		asset := &Asset{
+			Authority: name,
+			Host:      name,
+			Category:  "domain",
+			Source:    found[name],
+		}
+		if ans != nil {
+			for _, ip := range ans.IPv4 {
+				asset.IPV4 = append(asset.IPV4, IPInfo{IP: ip})
+			}
+			for _, ip := range ans.IPv6 {
+				asset.IPV6 = append(asset.IPV6, IPInfo{IP: ip})
+			}
+			if len(ans.CNAMEs) > 0 {
+				asset.CName = ans.CNAMEs[0]
+			}
+		}
+		result.Assets = append(result.Assets, asset)
+	}
+	result.DNSRecords = r.sortedRecords()
+	progress(100, "DNS侦察完成")
+	r.logf("INFO", "DNS recon completed: %d new subdomains, %d records, %d issues", len(result.Assets), len(result.DNSRecords), len(result.Vulnerabilities))
+	return result, ctx.Err()
+}
+
+// resolveAll 并发解析域名，只返回有解析结果的域名，filterWildcard 时丢弃命中泛解析的结果
+func (r *dnsRecon) resolveAll(ctx context.Context, names []string, filterWildcard bool) map[string]*dnsAnswer {
+	results := make(map[string]*dnsAnswer)
+	if len(names) == 0 {
+		return results
+	}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	ch := make(chan string)
+	for i := 0; i < r.opts.Threads; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for name := range ch {
+				ans, err := r.resolver.resolve(ctx, name)
+				if err != nil || !ans.resolved() {
+					continue
+				}
+				if filterWildcard && r.isWildcard(ctx, ans) {
+					continue
+				}
+				mu.Lock()
+				results[name] = ans
+				mu.Unlock()
+			}
+		}()
+	}
+feed:
+	for _, name := range names {
+		select {
+		case <-ctx.Done():
+			break feed
+		case ch <- name:
+		}
+	}
+	close(ch)
+	wg.Wait()
+	return results
+}
+
+// isWildcard 判断解析结果是否与父域名的泛解析一致
+func (r *dnsRecon) isWildcard(ctx context.Context, ans *dnsAnswer) bool {
+	idx := strings.Index(ans.Name, ".")
+	if idx < 0 {
+		return false
+	}
+	wc := r.wildcard(ctx, ans.Name[idx+1:])
+	if !wc.detected {
+		return false
+	}
+	for _, cname := range ans.CNAMEs {
+		if wc.cnames[cname] {
+			return true
+		}
+	}
+	ips := append(append([]string{}, ans.IPv4...), ans.IPv6...)
+	if len(ips) == 0 {
+		return false
+	}
+	for _, ip := range ips {
+		if !wc.ips[ip] {
+			return false
+		}
+	}
+	return true
+}
+
+// wildcard 检测父域名是否存在泛解析，每个父域名只探测一次
+func (r *dnsRecon) wildcard(ctx context.Context, parent string) *dnsWildcard {
+	r.mu.Lock()
+	wc, ok := r.wildcards[parent]
+	if !ok {
+		wc = &dnsWildcard{ips: make(map[string]bool), cnames: make(map[string]bool)}
+		r.wildcards[parent] = wc
+	}
+	r.mu.Unlock()
+
+	wc.once.Do(func() {
+		// 用两个随机子域名探测，泛解析可能轮询返回不同地址
+		for i := 0; i < 2; i++ {
+			ans, err := r.resolver.resolve(ctx, fmt.Sprintf("%012x.%s", rand.Int64N(1<<48), parent))
+			if err != nil || !ans.resolved() {
+				continue
+			}
+			wc.detected = true
+			for _, ip := range append(ans.IPv4, ans.IPv6...) {
+				wc.ips[ip] = true
+			}
+			for _, cname := range ans.CNAMEs {
+				wc.cnames[cname] = true
+			}
+		}
+		if wc.detected {
+			r.logf("INFO", "DNS recon: wildcard detected for *.%s", parent)
+		}
+	})
+	return wc
+}
+
+// zoneTransfer 对根域名的每个NS尝试AXFR，返回区域中的域名
+func (r *dnsRecon) zoneTransfer(ctx context.Context, root string) ([]string, *Vulnerability) {
+	msg, err := r.resolver.query(ctx, root, dns.TypeNS)
+	if err != nil {
+		return nil, nil
+	}
+	var nameservers []string
+	for _, rr := range msg.Answer {
+		if ns, ok := rr.(*dns.NS); ok {
+			nameservers = append(nameservers, strings.ToLower(strings.TrimSuffix(ns.Ns, ".")))
+		}
+	}
+
+	names := make(map[string]bool)
+	var vulnerable []string
+	for _, ns := range nameservers {
+		ans, err := r.resolver.resolve(ctx, ns)
+		if err != nil || len(ans.IPv4) == 0 {
+			continue
+		}
+		count := 0
+		for _, ip := range ans.IPv4 {
+			count = r.transfer(ctx, root, net.JoinHostPort(ip, "53"), names)
+			if count > 0 {
+				break
+			}
+		}
+		if count > 0 {
+			r.logf("WARN", "DNS recon: zone transfer allowed by %s for %s (%d records)", ns, root, count)
+			vulnerable = append(vulnerable, ns)
+		}
+	}
+	if len(vulnerable) == 0 {
+		return sortedKeys(names), nil
+	}
+
+	extra, _ := json.Marshal(map[string]interface{}{
+		"domain":      root,
+		"nameservers": vulnerable,
+		"names":       len(names),
+	})
+	return sortedKeys(names), &Vulnerability{
+		Authority:   root,
+		Host:        root,
+		Port:        53,
+		Url:         "dns://" + vulnerable[0] + "/" + root,
+		PocFile:     "dns-zone-transfer",
+		Source:      "dnsrecon",
+		Severity:    "medium",
+		Extra:       string(extra),
+		Result:      fmt.Sprintf("DNS域传送: %s 允许对 %s 执行AXFR，泄露 %d 个域名", strings.Join(vulnerable, ","), root, len(names)),
+		Remediation: "在权威DNS服务器上限制AXFR只允许从授权的辅助DNS服务器发起",
+	}
+}
+
+// transfer 执行一次AXFR，返回记录数
+func (r *dnsRecon) transfer(ctx context.Context, root, server string, names map[string]bool) int {
+	m := new(dns.Msg)
+	m.SetAxfr(dns.Fqdn(root))
+	t := &dns.Transfer{
+		DialTimeout:  r.resolver.timeout,
+		ReadTimeout:  r.resolver.timeout * 3,
+		WriteTimeout: r.resolver.timeout,
+	}
+	ch, err := t.In(m, server)
+	if err != nil {
+		return 0
+	}
+	count := 0
+	for env := range ch {
+		if env.Error != nil || ctx.Err() != nil {
+			break
+		}
+		for _, rr := range env.RR {
+			if rr.Header().Rrtype == dns.TypeSOA {
+				continue
+			}
+			name := strings.ToLower(strings.TrimSuffix(rr.Header().Name, "."))
+			r.addRecord(name, rr, "axfr")
+			count++
+			// 通配和 _service 形式的名称不是主机
+			if name != root && strings.HasSuffix(name, "."+root) && !strings.ContainsAny(name, "*_") {
+				names[name] = true
+			}
+		}
+	}
+	return count
+}
+
+// collectRootRecords 采集根域名的各类记录
+func (r *dnsRecon) collectRootRecords(ctx context.Context, root string) {
+	for _, typ := range r.opts.RecordTypes {
+		qtype, ok := dns.StringToType[strings.ToUpper(typ)]
+		if !ok {
+			continue
+		}
+		names := []string{root}
+		if qtype == dns.TypeSRV {
+			names = names[:0]
+			for _, svc := range dnsSRVServices {
+				names = append(names, svc+"."+root)
+			}
+		}
+		for _, name := range names {
+			if ctx.Err() != nil {
+				return
+			}
+			msg, err := r.resolver.query(ctx, name, qtype)
+			if err != nil {
+				continue
+			}
+			for _, rr := range msg.Answer {
+				if rr.Header().Rrtype == qtype {
+					r.addRecord(name, rr, "query")
+				}
+			}
+		}
+	}
+}
+
+// addAnswerRecords 将解析结果转换为 A/AAAA/CNAME 记录
+func (r *dnsRecon) addAnswerRecords(ans *dnsAnswer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	add := func(typ, value string) {
+		rec := &DNSRecord{Domain: ans.Name, Root: crawlRootDomain(ans.Name), Type: typ, Value: value, TTL: ans.TTL, Source: "query"}
+		r.records[rec.Domain+"|"+rec.Type+"|"+rec.Value] = rec
+	}
+	if len(ans.CNAMEs) > 0 {
+		add("CNAME", ans.CNAMEs[0])
+	}
+	for _, ip := range ans.IPv4 {
+		add("A", ip)
+	}
+	for _, ip := range ans.IPv6 {
+		add("AAAA", ip)
+	}
+}
+
+func (r *dnsRecon) addRecord(name string, rr dns.RR, source string) {
+	rec := &DNSRecord{
+		Domain: name,
+		Root:   crawlRootDomain(name),
+		Type:   dns.TypeToString[rr.Header().Rrtype],
+		Value:  dnsRecordValue(rr),
+		TTL:    rr.Header().Ttl,
+		Source: source,
+	}
+	r.mu.Lock()
+	r.records[rec.Domain+"|"+rec.Type+"|"+rec.Value] = rec
+	r.mu.Unlock()
+}
+
+func (r *dnsRecon) sortedRecords() []*DNSRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	records := make([]*DNSRecord, 0, len(r.records))
+	for _, rec := range r.records {
+		records = append(records, rec)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].Domain != records[j].Domain {
+			return records[i].Domain < records[j].Domain
+		}
+		if records[i].Type != records[j].Type {
+			return records[i].Type < records[j].Type
+		}
+		return records[i].Value < records[j].Value
+	})
+	return records
+}
+
+// dnsRecordValue 记录值，TXT 合并为纯文本，其他类型使用标准表示
+func dnsRecordValue(rr dns.RR) string {
+	switch v := rr.(type) {
+	case *dns.A:
+		return v.A.String()
+	case *dns.AAAA:
+		return v.AAAA.String()
+	case *dns.CNAME:
+		return strings.TrimSuffix(v.Target, ".")
+	case *dns.NS:
+		return strings.TrimSuffix(v.Ns, ".")
+	case *dns.TXT:
+		return strings.Join(v.Txt, "")
+	case *dns.MX:
+		return fmt.Sprintf("%d %s", v.Preference, strings.TrimSuffix(v.Mx, "."))
+	case *dns.SRV:
+		return fmt.Sprintf("%d %d %d %s", v.Priority, v.Weight, v.Port, strings.TrimSuffix(v.Target, "."))
+	}
+	return strings.TrimSpace(strings.TrimPrefix(rr.String(), rr.Header().String()))
+}
+
+// permuteSubdomains 基于已发现子域名生成排列组合（插入常见词、连字符组合、数字递增）
+func permuteSubdomains(seeds, roots []string, exists func(string) bool, limit int) []string {
+	sort.Strings(seeds)
+	var out []string
+	seen := make(map[string]bool)
+	add := func(name string) bool {
+		if len(out) >= limit {
+			return false
+		}
+		if !seen[name] && !exists(name) && len(name) <= 253 {
+			seen[name] = true
+			out = append(out, name)
+		}
+		return true
+	}
+
+	for _, seed := range seeds {
+		root := ""
+		for _, rt := range roots {
+			if strings.HasSuffix(seed, "."+rt) && len(rt) > len(root) {
+				root = rt
+			}
+		}
+		if root == "" {
+			continue
+		}
+		sub := strings.TrimSuffix(seed, "."+root)
+		if strings.HasPrefix(sub, "*") {
+			continue
+		}
+		first, rest := sub, ""
+		if idx := strings.Index(sub, "."); idx >= 0 {
+			first, rest = sub[:idx], sub[idx:]
+		}
+		suffix := rest + "." + root
+
+		// 数字递增递减：api1 -> api0, api2, api3
+		if m := permNumberRegex.FindStringSubmatch(first); m != nil {
+			n, _ := strconv.Atoi(m[2])
+			for _, d := range []int{-1, 1, 2, 3} {
+				if n+d >= 0 && !add(fmt.Sprintf("%s%0*d%s", m[1], len(m[2]), n+d, suffix)) {
+					return out
+				}
+			}
+		}
+		for _, word := range dnsPermutationWords {
+			if word == first {
+				continue
+			}
+			if !add(word+"."+seed) || !add(word+"-"+first+suffix) || !add(first+"-"+word+suffix) {
+				return out
+			}
+		}
+	}
+	return out
+}
+
+var permNumberRegex = regexp.MustCompile(`^(.*?)(\d+)$`)
+
+// parseReconDomains 从目标中提取根域名，忽略IP和CIDR
+func parseReconDomains(target string) []string {
+	seen := make(map[string]bool)
+	var domains []string
+	for _, line := range strings.FieldsFunc(target, func(r rune) bool { return r == '\n' || r == ',' }) {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.Contains(line, "://") {
+			if u, err := url.Parse(line); err == nil {
+				line = u.Host
+			}
+		}
+		if host, _, err := net.SplitHostPort(line); err == nil {
+			line = host
+		}
+		line = strings.ToLower(strings.TrimPrefix(strings.TrimSuffix(line, "."), "*."))
+		if line == "" || net.ParseIP(line) != nil || strings.Contains(line, "/") || !strings.Contains(line, ".") {
+			continue
+		}
+		if !seen[line] {
+			seen[line] = true
+			domains = append(domains, line)
+		}
+	}
+	return domains
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// dnsResolver 轮询多个DNS服务器，带重试和TCP回退
+type dnsResolver struct {
+	servers []string
+	udp     *dns.Client
+	tcp     *dns.Client
+	timeout time.Duration
+	mu      sync.Mutex
+	next    int
+}
+
+func newDNSResolver(servers []string, timeout time.Duration) *dnsResolver {
+	var list []string
+	for _, s := range servers {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if _, _, err := net.SplitHostPort(s); err != nil {
+			s = net.JoinHostPort(s, "53")
+		}
+		list = append(list, s)
+	}
+	if len(list) == 0 {
+		if conf, err := dns.ClientConfigFromFile("/etc/resolv.conf"); err == nil {
+			for _, s := range conf.Servers {
+				list = append(list, net.JoinHostPort(s, conf.Port))
+			}
+		}
+	}
+	if len(list) == 0 {
+		for _, s := range defaultDNSResolvers {
+			list = append(list, net.JoinHostPort(s, "53"))
+		}
+	}
+	return &dnsResolver{
+		servers: list,
+		udp:     &dns.Client{Net: "udp", Timeout: timeout},
+		tcp:     &dns.Client{Net: "tcp", Timeout: timeout},
+		timeout: timeout,
+	}
+}
+
+func (r *dnsResolver) server() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.servers[r.next%len(r.servers)]
+	r.next++
+	return s
+}
+
+// query 查询一条记录，NXDOMAIN 作为正常结果返回
+func (r *dnsResolver) query(ctx context.Context, name string, qtype uint16) (*dns.Msg, error) {
+	m := new(dns.Msg)
+	m.SetQuestion(dns.Fqdn(name), qtype)
+	m.RecursionDesired = true
+
+	var lastErr error
+	for attempt := 0; attempt < 3; attempt++ {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		server := r.server()
+		resp, _, err := r.udp.ExchangeContext(ctx, m, server)
+		if err == nil && resp.Truncated {
+			resp, _, err = r.tcp.ExchangeContext(ctx, m, server)
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+			lastErr = fmt.Errorf("%s: %s", server, dns.RcodeToString[resp.Rcode])
+			continue
+		}
+		return resp, nil
+	}
+	return nil, lastErr
+}
+
+// resolve 解析A和AAAA记录，并记录CNAME链
+func (r *dnsResolver) resolve(ctx context.Context, name string) (*dnsAnswer, error) {
+	name = strings.ToLower(strings.TrimSuffix(name, "."))
+	ans := &dnsAnswer{Name: name}
+	seenCNAME := make(map[string]bool)
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		msg, err := r.query(ctx, name, qtype)
+		if err != nil {
+			if qtype == dns.TypeA {
+				return nil, err
+			}
+			continue
+		}
+		if msg.Rcode == dns.RcodeNameError {
+			ans.NXDomain = true
+		}
+		for _, rr := range msg.Answer {
+			if ans.TTL == 0 || rr.Header().Ttl < ans.TTL {
+				ans.TTL = rr.Header().Ttl
+			}
+			switch v := rr.(type) {
+			case *dns.CNAME:
+				target := strings.ToLower(strings.TrimSuffix(v.Target, "."))
+				if !seenCNAME[target] {
+					seenCNAME[target] = true
+					ans.CNAMEs = append(ans.CNAMEs, target)
+				}
+			case *dns.A:
+				ans.IPv4 = append(ans.IPv4, v.A.String())
+			case *dns.AAAA:
+				ans.IPv6 = append(ans.IPv6, v.AAAA.String())
+			}
+		}
+		// 目标不存在时无需再查AAAA
+		if ans.NXDomain && len(ans.CNAMEs) == 0 {
+			break
+		}
+	}
+	return ans, nil
+}
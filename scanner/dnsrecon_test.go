@@ -0,0 +1,226 @@
+package scanner
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+// TestPermuteSubdomains 测试排列组合：数字递增、常见词插入、最长根域名匹配、已存在过滤和数量上限
+func TestPermuteSubdomains(t *testing.T) {
+	none := func(string) bool { return false }
+
+	out := permuteSubdomains([]string{"api1.example.com"}, []string{"example.com"}, none, 1000)
+	for _, want := range []string{"api0.example.com", "api2.example.com", "api4.example.com", "dev.api1.example.com", "dev-api1.example.com", "api1-dev.example.com"} {
+		if !slices.Contains(out, want) {
+			t.Errorf("number seed: missing %s", want)
+		}
+	}
+
+	out = permuteSubdomains([]string{"web01.example.com"}, []string{"example.com"}, none, 4)
+	if want := []string{"web00.example.com", "web02.example.com", "web03.example.com", "web04.example.com"}; !reflect.DeepEqual(out, want) {
+		t.Errorf("zero padded seed = %v, want %v", out, want)
+	}
+
+	out = permuteSubdomains([]string{"api.corp.example.com"}, []string{"example.com", "corp.example.com"}, none, 1000)
+	if !slices.Contains(out, "dev-api.corp.example.com") || slices.Contains(out, "dev-api.example.com") {
+		t.Errorf("longest root not used: %v", out)
+	}
+
+	out = permuteSubdomains([]string{"dev.example.com"}, []string{"example.com"}, none, 1000)
+	if slices.Contains(out, "dev-dev.example.com") || slices.Contains(out, "dev.dev.example.com") {
+		t.Error("seed word combined with itself")
+	}
+
+	exists := func(name string) bool { return name == "test.api.example.com" }
+	out = permuteSubdomains([]string{"api.example.com"}, []string{"example.com"}, exists, 1000)
+	if slices.Contains(out, "test.api.example.com") {
+		t.Error("existing subdomain returned")
+	}
+	seen := make(map[string]bool)
+	for _, name := range out {
+		if seen[name] {
+			t.Errorf("duplicate permutation %s", name)
+		}
+		seen[name] = true
+	}
+
+	tests := []struct {
+		name  string
+		seeds []string
+		limit int
+		want  int
+	}{
+		{"limit", []string{"a.example.com", "b.example.com", "c.example.com"}, 25, 25},
+		{"zero limit", []string{"a.example.com"}, 0, 0},
+		{"outside roots", []string{"a.other.com"}, 100, 0},
+		{"wildcard seed", []string{"*.example.com"}, 100, 0},
+	}
+	for _, tt := range tests {
+		if got := permuteSubdomains(tt.seeds, []string{"example.com"}, none, tt.limit); len(got) != tt.want {
+			t.Errorf("%s: len = %d, want %d", tt.name, len(got), tt.want)
+		}
+	}
+}
+
+// TestParseReconDomains 测试从目标中提取根域名
+func TestParseReconDomains(t *testing.T) {
+	target := "Example.com\nhttps://www.example.org:8443/path\n*.wild.example.net\n10.0.0.1\n10.0.0.0/24\nlocalhost\n# comment\nexample.com.,api.example.io:443\n\n"
+	want := []string{"example.com", "www.example.org", "wild.example.net", "api.example.io"}
+	if got := parseReconDomains(target); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseReconDomains() = %v, want %v", got, want)
+	}
+}
+
+// startTestDNS 启动本地DNS服务：*.wild.test 泛解析到 1.2.3.4，*.cname.test 泛解析为 CNAME edge.cdn.test，其余返回 NXDOMAIN
+func startTestDNS(t *testing.T, probes *int32) string {
+	t.Helper()
+	pc, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	handler := dns.HandlerFunc(func(w dns.ResponseWriter, req *dns.Msg) {
+		m := new(dns.Msg)
+		m.SetReply(req)
+		q := req.Question[0]
+		name := strings.ToLower(q.Name)
+		switch {
+		case strings.HasSuffix(name, ".wild.test."):
+			atomic.AddInt32(probes, 1)
+			if q.Qtype == dns.TypeA {
+				rr, _ := dns.NewRR(q.Name + " 60 IN A 1.2.3.4")
+				m.Answer = append(m.Answer, rr)
+			}
+		case strings.HasSuffix(name, ".cname.test."):
+			cname, _ := dns.NewRR(q.Name + " 60 IN CNAME edge.cdn.test.")
+			m.Answer = append(m.Answer, cname)
+			if q.Qtype == dns.TypeA {
+				rr, _ := dns.NewRR("edge.cdn.test. 60 IN A 5.6.7.8")
+				m.Answer = append(m.Answer, rr)
+			}
+		default:
+			m.Rcode = dns.RcodeNameError
+		}
+		w.WriteMsg(m)
+	})
+	started := make(chan struct{})
+	srv := &dns.Server{PacketConn: pc, Handler: handler, NotifyStartedFunc: func() { close(started) }}
+	go srv.ActivateAndServe()
+	<-started
+	t.Cleanup(func() { srv.Shutdown() })
+	return pc.LocalAddr().String()
+}
+
+// TestDNSReconWildcard 测试泛解析过滤：IP 全部落在泛解析集合或 CNAME 命中时视为泛解析，父域名只探测一次
+func TestDNSReconWildcard(t *testing.T) {
+	var probes int32
+	addr := startTestDNS(t, &probes)
+	r := &dnsRecon{
+		resolver:  newDNSResolver([]string{addr}, time.Second),
+		logf:      func(string, string, ...interface{}) {},
+		wildcards: make(map[string]*dnsWildcard),
+	}
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		ans  *dnsAnswer
+		want bool
+	}{
+		{"wildcard ip", &dnsAnswer{Name: "a.wild.test", IPv4: []string{"1.2.3.4"}}, true},
+		{"real record", &dnsAnswer{Name: "b.wild.test", IPv4: []string{"9.9.9.9"}}, false},
+		{"mixed ips", &dnsAnswer{Name: "c.wild.test", IPv4: []string{"1.2.3.4", "9.9.9.9"}}, false},
+		{"no address", &dnsAnswer{Name: "d.wild.test", CNAMEs: []string{"other.test"}}, false},
+		{"wildcard cname", &dnsAnswer{Name: "a.cname.test", CNAMEs: []string{"edge.cdn.test"}, IPv4: []string{"7.7.7.7"}}, true},
+		{"cname ips", &dnsAnswer{Name: "b.cname.test", CNAMEs: []string{"own.cdn.test"}, IPv4: []string{"5.6.7.8"}}, true},
+		{"own cname", &dnsAnswer{Name: "c.cname.test", CNAMEs: []string{"own.cdn.test"}, IPv4: []string{"7.7.7.7"}}, false},
+		{"no wildcard", &dnsAnswer{Name: "www.plain.test", IPv4: []string{"1.2.3.4"}}, false},
+		{"single label", &dnsAnswer{Name: "test", IPv4: []string{"1.2.3.4"}}, false},
+	}
+	for _, tt := range tests {
+		if got := r.isWildcard(ctx, tt.ans); got != tt.want {
+			t.Errorf("%s: isWildcard() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+	// 两个随机子域名 × A/AAAA
+	if n := atomic.LoadInt32(&probes); n != 4 {
+		t.Errorf("wild.test probed %d times, want 4", n)
+	}
+}
+
+// TestMatchTakeoverFingerprint 测试按CNAME后缀匹配接管服务
+func TestMatchTakeoverFingerprint(t *testing.T) {
+	tests := []struct {
+		cnames []string
+		want   string
+	}{
+		{[]string{"acme.github.io"}, "github-pages"},
+		{[]string{"cdn.example.com", "acme.herokudns.com"}, "heroku"},
+		{[]string{"acme.s3.amazonaws.com"}, "aws-s3"},
+		{[]string{"shop.myshopify.com"}, "shopify"},
+		{[]string{"surge.sh"}, "surge"},
+		{[]string{"notgithub.io"}, ""},
+		{[]string{"github.io.example.com"}, ""},
+		{nil, ""},
+	}
+	for _, tt := range tests {
+		got := ""
+		if fp := matchTakeoverFingerprint(tt.cnames); fp != nil {
+			got = fp.Service
+		}
+		if got != tt.want {
+			t.Errorf("matchTakeoverFingerprint(%v) = %q, want %q", tt.cnames, got, tt.want)
+		}
+	}
+}
+
+// TestCheckTakeover 测试接管判定：NXDOMAIN 服务、外部悬空CNAME和未认领页面特征
+func TestCheckTakeover(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte("<h1>404</h1><p>There isn't a GitHub Pages site here.</p>"))
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	r := &dnsRecon{}
+	tests := []struct {
+		name     string
+		ans      *dnsAnswer
+		service  string
+		severity string
+	}{
+		{"nxdomain service", &dnsAnswer{Name: "app.example.com", CNAMEs: []string{"gone.herokuapp.com"}, NXDomain: true}, "heroku", "high"},
+		{"nxdomain service resolved", &dnsAnswer{Name: "app.example.com", CNAMEs: []string{"live.azurewebsites.net"}, IPv4: []string{"1.1.1.1"}}, "", ""},
+		{"dangling external", &dnsAnswer{Name: "old.example.com", CNAMEs: []string{"expired-vendor.net"}, NXDomain: true}, "dangling-cname", "low"},
+		{"dangling same root", &dnsAnswer{Name: "old.example.com", CNAMEs: []string{"gone.example.com"}, NXDomain: true}, "", ""},
+		{"unknown provider", &dnsAnswer{Name: "www.example.com", CNAMEs: []string{"lb.vendor.net"}, IPv4: []string{"1.1.1.1"}}, "", ""},
+		{"unclaimed page", &dnsAnswer{Name: host, CNAMEs: []string{"acme.github.io"}, IPv4: []string{"127.0.0.1"}}, "github-pages", "high"},
+		{"claimed page", &dnsAnswer{Name: host, CNAMEs: []string{"acme.ghost.io"}, IPv4: []string{"127.0.0.1"}}, "", ""},
+	}
+	for _, tt := range tests {
+		vul := r.checkTakeover(context.Background(), srv.Client(), tt.ans)
+		if tt.service == "" {
+			if vul != nil {
+				t.Errorf("%s: unexpected vulnerability %s", tt.name, vul.PocFile)
+			}
+			continue
+		}
+		if vul == nil {
+			t.Errorf("%s: no vulnerability, want %s", tt.name, tt.service)
+			continue
+		}
+		if vul.PocFile != "subdomain-takeover-"+tt.service || vul.Severity != tt.severity {
+			t.Errorf("%s: got %s/%s, want %s/%s", tt.name, vul.PocFile, vul.Severity, tt.service, tt.severity)
+		}
+	}
+}
@@ -0,0 +1,160 @@
+package scanner
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// takeoverFingerprint 第三方服务的子域名接管特征
+type takeoverFingerprint struct {
+	Service  string
+	CNAMEs   []string // CNAME 目标后缀
+	Body     string   // 未认领时的页面特征
+	NXDomain bool     // CNAME 目标不存在即可认领
+}
+
+// takeoverFingerprints 参考 can-i-take-over-xyz 中确认可接管的服务
+var takeoverFingerprints = []takeoverFingerprint{
+	{Service: "github-pages", CNAMEs: []string{"github.io"}, Body: "There isn't a GitHub Pages site here"},
+	{Service: "heroku", CNAMEs: []string{"herokuapp.com", "herokudns.com", "herokussl.com"}, Body: "No such app", NXDomain: true},
+	{Service: "aws-s3", CNAMEs: []string{"s3.amazonaws.com", "amazonaws.com"}, Body: "The specified bucket does not exist"},
+	{Service: "aws-elasticbeanstalk", CNAMEs: []string{"elasticbeanstalk.com"}, NXDomain: true},
+	{Service: "azure", CNAMEs: []string{"cloudapp.net", "cloudapp.azure.com", "azurewebsites.net", "blob.core.windows.net", "trafficmanager.net", "azureedge.net", "azure-api.net", "azurefd.net", "azurecontainer.io", "servicebus.windows.net", "database.windows.net"}, NXDomain: true},
+	{Service: "shopify", CNAMEs: []string{"myshopify.com"}, Body: "Sorry, this shop is currently unavailable"},
+	{Service: "fastly", CNAMEs: []string{"fastly.net"}, Body: "Fastly error: unknown domain"},
+	{Service: "ghost", CNAMEs: []string{"ghost.io"}, Body: "The thing you were looking for is no longer here"},
+	{Service: "pantheon", CNAMEs: []string{"pantheonsite.io"}, Body: "The gods are wise, but do not know of the site which you seek"},
+	{Service: "surge", CNAMEs: []string{"surge.sh"}, Body: "project not found"},
+	{Service: "bitbucket", CNAMEs: []string{"bitbucket.io"}, Body: "Repository not found"},
+	{Service: "zendesk", CNAMEs: []string{"zendesk.com"}, Body: "Help Center Closed"},
+	{Service: "readme", CNAMEs: []string{"readme.io"}, Body: "Project doesnt exist... yet!"},
+	{Service: "netlify", CNAMEs: []string{"netlify.app", "netlify.com"}, Body: "Not Found - Request ID"},
+	{Service: "unbounce", CNAMEs: []string{"unbouncepages.com"}, Body: "The requested URL was not found on this server"},
+	{Service: "tumblr", CNAMEs: []string{"domains.tumblr.com"}, Body: "Whatever you were looking for doesn't currently exist at this address"},
+	{Service: "wordpress", CNAMEs: []string{"wordpress.com"}, Body: "Do you want to register"},
+	{Service: "helpscout", CNAMEs: []string{"helpscoutdocs.com"}, Body: "No settings were found for this company"},
+	{Service: "cargo", CNAMEs: []string{"cargocollective.com"}, Body: "404 Not Found"},
+	{Service: "uservoice", CNAMEs: []string{"uservoice.com"}, Body: "This UserVoice subdomain is currently available"},
+}
+
+// matchTakeoverFingerprint 按CNAME链匹配服务
+func matchTakeoverFingerprint(cnames []string) *takeoverFingerprint {
+	for i := range takeoverFingerprints {
+		fp := &takeoverFingerprints[i]
+		for _, cname := range cnames {
+			for _, suffix := range fp.CNAMEs {
+				if cname == suffix || strings.HasSuffix(cname, "."+suffix) {
+					return fp
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkTakeovers 检查存在CNAME的域名是否可被接管
+func (r *dnsRecon) checkTakeovers(ctx context.Context, answers map[string]*dnsAnswer) []*Vulnerability {
+	client := &http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
+		},
+	}
+
+	var names []string
+	for name, ans := range answers {
+		if len(ans.CNAMEs) > 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	var vuls []*Vulnerability
+	sem := make(chan struct{}, 10)
+	for _, name := range names {
+		if ctx.Err() != nil {
+			break
+		}
+		ans := answers[name]
+		wg.Add(1)
+		sem <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+			if vul := r.checkTakeover(ctx, client, ans); vul != nil {
+				mu.Lock()
+				vuls = append(vuls, vul)
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	sort.Slice(vuls, func(i, j int) bool { return vuls[i].Host < vuls[j].Host })
+	return vuls
+}
+
+func (r *dnsRecon) checkTakeover(ctx context.Context, client *http.Client, ans *dnsAnswer) *Vulnerability {
+	target := ans.CNAMEs[len(ans.CNAMEs)-1]
+	fp := matchTakeoverFingerprint(ans.CNAMEs)
+	if fp == nil {
+		// 指向外部且已不存在的CNAME，可能可以注册该域名
+		if ans.NXDomain && crawlRootDomain(target) != crawlRootDomain(ans.Name) {
+			return takeoverVul(ans, "dangling-cname", "low", "CNAME目标不存在")
+		}
+		return nil
+	}
+	if fp.NXDomain && ans.NXDomain {
+		return takeoverVul(ans, fp.Service, "high", "CNAME目标不存在")
+	}
+	if fp.Body == "" {
+		return nil
+	}
+	for _, scheme := range []string{"https", "http"} {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, scheme+"://"+ans.Name+"/", nil)
+		if err != nil {
+			return nil
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			continue
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+		if strings.Contains(string(body), fp.Body) {
+			return takeoverVul(ans, fp.Service, "high", "页面包含未认领特征: "+fp.Body)
+		}
+		return nil
+	}
+	return nil
+}
+
+func takeoverVul(ans *dnsAnswer, service, severity, reason string) *Vulnerability {
+	target := ans.CNAMEs[len(ans.CNAMEs)-1]
+	extra, _ := json.Marshal(map[string]interface{}{
+		"service": service,
+		"cname":   ans.CNAMEs,
+		"reason":  reason,
+	})
+	return &Vulnerability{
+		Authority:   ans.Name,
+		Host:        ans.Name,
+		Port:        80,
+		Url:         "http://" + ans.Name,
+		PocFile:     "subdomain-takeover-" + service,
+		Source:      "dnsrecon",
+		Severity:    severity,
+		Extra:       string(extra),
+		Result:      fmt.Sprintf("子域名接管: %s CNAME %s (%s)，%s", ans.Name, target, service, reason),
+		Remediation: "删除不再使用的CNAME记录，或在对应服务上重新认领该域名",
+	}
+}
@@ -43,6 +43,13 @@ type AppDetectionResult struct {
 	CustomIDs    []string // 自定义指纹的ID列表
 }
 
+func init() {
+	Register("fingerprint", Registration{
+		New:     func() Scanner { return NewFingerprintScanner() },
+		Options: func() interface{} { return defaultFingerprintOptions() },
+	})
+}
+
 // NewFingerprintScanner 创建指纹扫描器
 func NewFingerprintScanner() *FingerprintScanner {
 	wappalyzerClient, _ := wappalyzer.New()
@@ -85,11 +92,9 @@ type FingerprintOptions struct {
 	Concurrency   int    `json:"concurrency"`   // 并发数，默认10
 }
 
-
-// Scan 执行指纹识别
-func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
-	// 解析配置
-	opts := &FingerprintOptions{
+// defaultFingerprintOptions 默认扫描选项
+func defaultFingerprintOptions() *FingerprintOptions {
+	return &FingerprintOptions{
 		Enable:        true,
 		Tool:          "httpx", // 默认使用httpx
 		IconHash:      true,
@@ -99,6 +104,12 @@ func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*Sca
 		Timeout:       300, // 总超时默认5分钟
 		TargetTimeout: 30,  // 单目标超时默认30秒
 	}
+}
+
+// Scan 执行指纹识别
+func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	// 解析配置
+	opts := defaultFingerprintOptions()
 	if config.Options != nil {
 		switch v := config.Options.(type) {
 		case *FingerprintOptions:
@@ -160,7 +171,7 @@ func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*Sca
 	if useHttpx {
 		// 使用httpx库进行扫描（不再依赖命令行工具）
 		taskLog("DEBUG", "Using httpx library for fingerprint detection")
-		s.runHttpxLib(ctx, httpAssets, opts)
+		s.runHttpxLib(ctx, httpAssets, opts, config.RateLimiter)
 	} else {
 		taskLog("DEBUG", "Using builtin method for fingerprint detection")
 	}
@@ -172,6 +183,10 @@ func (s *FingerprintScanner) Scan(ctx context.Context, config *ScanConfig) (*Sca
 			logx.Info("Fingerprint scan cancelled by context")
 			return result, ctx.Err()
 		default:
+			// 指纹识别、图标等附加请求同样计入限速预算
+			if err := waitRate(ctx, config.RateLimiter, asset.Host, 1); err != nil {
+				return result, err
+			}
 			// 如果使用httpx且已获取到基本信息，只执行附加功能
 			if useHttpx && asset.Title != "" && asset.HttpStatus != "" {
 				logx.Debugf("Fingerprint [%d/%d]: %s:%d (additional)", i+1, len(httpAssets), asset.Host, asset.Port)
@@ -931,13 +946,8 @@ func calculateIconHash(data []byte) string {
 	return hex.EncodeToString(sum[:])
 }
 
-// takeScreenshot 使用chromedp截图
-func (s *FingerprintScanner) takeScreenshot(ctx context.Context, targetUrl string) string {
-	// 创建chromedp上下文，设置超时
-	screenshotCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
-	defer cancel()
-
-	// 配置chromedp选项，支持 Docker 环境中的 Chromium
+// chromeAllocatorOptions chromedp 启动选项，支持 Docker 环境中的 Chromium
+func chromeAllocatorOptions() []chromedp.ExecAllocatorOption {
 	opts := append(chromedp.DefaultExecAllocatorOptions[:],
 		chromedp.Flag("headless", true),
 		chromedp.Flag("disable-gpu", false), // 启用GPU渲染
@@ -958,8 +968,16 @@ func (s *FingerprintScanner) takeScreenshot(ctx context.Context, targetUrl strin
 	if chromePath := os.Getenv("CHROME_BIN"); chromePath != "" {
 		opts = append(opts, chromedp.ExecPath(chromePath))
 	}
+	return opts
+}
+
+// takeScreenshot 使用chromedp截图
+func (s *FingerprintScanner) takeScreenshot(ctx context.Context, targetUrl string) string {
+	// 创建chromedp上下文，设置超时
+	screenshotCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
 
-	allocCtx, allocCancel := chromedp.NewExecAllocator(screenshotCtx, opts...)
+	allocCtx, allocCancel := chromedp.NewExecAllocator(screenshotCtx, chromeAllocatorOptions()...)
 	defer allocCancel()
 
 	taskCtx, taskCancel := chromedp.NewContext(allocCtx)
@@ -40,7 +40,8 @@ type HttpxLibResult struct {
 }
 
 // RunHttpxLib 使用httpx库进行批量HTTP探测
-func (s *FingerprintScanner) RunHttpxLib(ctx context.Context, assets []*Asset, opts *FingerprintOptions) error {
+// limiter 为空表示不限速，否则每个目标的结果回调中获取令牌，阻塞 httpx 的工作协程以控制速率
+func (s *FingerprintScanner) RunHttpxLib(ctx context.Context, assets []*Asset, opts *FingerprintOptions, limiter RateLimiter) error {
 	if len(assets) == 0 {
 		return nil
 	}
@@ -84,8 +85,10 @@ func (s *FingerprintScanner) RunHttpxLib(ctx context.Context, assets []*Asset, o
 		OutputIP:              true,
 		LeaveDefaultPorts:     false,
 		HostMaxErrors:         30,
+		RateLimit:             capRate(limiter, "", 0),
 		// 设置结果回调
 		OnResult: func(result runner.Result) {
+			waitRate(ctx, limiter, result.Input, 1)
 			mu.Lock()
 			defer mu.Unlock()
 
@@ -215,8 +218,8 @@ func (s *FingerprintScanner) RunHttpxLib(ctx context.Context, assets []*Asset, o
 }
 
 // runHttpxLib 使用httpx库进行批量探测（替代原有的runHttpx命令行方式）
-func (s *FingerprintScanner) runHttpxLib(ctx context.Context, assets []*Asset, opts *FingerprintOptions) {
-	if err := s.RunHttpxLib(ctx, assets, opts); err != nil {
+func (s *FingerprintScanner) runHttpxLib(ctx context.Context, assets []*Asset, opts *FingerprintOptions, limiter RateLimiter) {
+	if err := s.RunHttpxLib(ctx, assets, opts, limiter); err != nil {
 		logx.Errorf("httpx library scan failed: %v", err)
 	}
 }
@@ -0,0 +1,108 @@
+package scanner
+
+import (
+	"strings"
+	"time"
+
+	nuclei "github.com/projectdiscovery/nuclei/v3/lib"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// OOB 回连模式
+const (
+	InteractshModePublic  = "public"  // 使用 nuclei 默认的公共 interactsh 服务（oast.pro 等）
+	InteractshModeSelf    = "self"    // 使用自建 interactsh 服务
+	InteractshModeDisable = "disable" // 禁用 OOB，依赖回连的模板不会执行
+)
+
+// InteractshOptions OOB 回连配置，由服务端下发
+type InteractshOptions struct {
+	Mode           string `json:"mode"`
+	ServerURL      string `json:"serverUrl"`      // 自建服务地址，如 https://oast.example.com
+	Token          string `json:"token"`          // 服务端 -token 参数
+	PollInterval   int    `json:"pollInterval"`   // 轮询间隔(秒)，默认5
+	CooldownPeriod int    `json:"cooldownPeriod"` // 扫描结束后继续等待回连的时间(秒)，默认5
+	Eviction       int    `json:"eviction"`       // 请求记录保留时间(秒)，默认60
+}
+
+// Enabled 是否启用 OOB 回连
+func (o *InteractshOptions) Enabled() bool {
+	return o == nil || o.Mode != InteractshModeDisable
+}
+
+// sdkOption 转换为 nuclei SDK 选项，未配置时使用 nuclei 默认值
+func (o *InteractshOptions) sdkOption() nuclei.NucleiSDKOptions {
+	opts := o.interactshOpts()
+	if opts == nil {
+		return nil
+	}
+	return nuclei.WithInteractshOptions(*opts)
+}
+
+// interactshOpts 按回连模式生成 interactsh 选项，公共服务返回 nil
+func (o *InteractshOptions) interactshOpts() *nuclei.InteractshOpts {
+	if o == nil || o.Mode == "" || o.Mode == InteractshModePublic {
+		return nil
+	}
+	opts := nuclei.InteractshOpts{
+		CacheSize:           5000,
+		Eviction:            seconds(o.Eviction, 60),
+		CooldownPeriod:      seconds(o.CooldownPeriod, 5),
+		PollDuration:        seconds(o.PollInterval, 5),
+		DisableHttpFallback: true,
+		NoInteractsh:        o.Mode == InteractshModeDisable,
+	}
+	if o.Mode == InteractshModeSelf {
+		opts.ServerURL = strings.TrimRight(o.ServerURL, "/")
+		opts.Authorization = o.Token
+		// 自建服务常用 http 部署，允许回退
+		opts.DisableHttpFallback = false
+	}
+	return &opts
+}
+
+func seconds(v, def int) time.Duration {
+	if v <= 0 {
+		v = def
+	}
+	return time.Duration(v) * time.Second
+}
+
+// VulInteraction OOB 回连证据，记录 interactsh 服务收到的 DNS/HTTP/SMTP 等交互
+type VulInteraction struct {
+	Protocol      string    `json:"protocol"`           // dns/http/smtp/ldap 等
+	UniqueId      string    `json:"uniqueId"`           // 关联ID（子域名）
+	FullId        string    `json:"fullId"`             // 完整子域名
+	QType         string    `json:"qType,omitempty"`    // DNS 查询类型
+	RawRequest    string    `json:"rawRequest"`         // 服务端收到的原始请求
+	RawResponse   string    `json:"rawResponse"`        // 服务端返回的原始响应
+	RemoteAddress string    `json:"remoteAddress"`      // 回连来源地址
+	SMTPFrom      string    `json:"smtpFrom,omitempty"` // SMTP 发件人
+	Timestamp     time.Time `json:"timestamp"`          // 回连时间
+}
+
+// CollectInteraction 从 Nuclei 结果中提取 OOB 交互证据
+func CollectInteraction(event *output.ResultEvent) *VulInteraction {
+	if event == nil || event.Interaction == nil {
+		return nil
+	}
+	i := event.Interaction
+	v := &VulInteraction{
+		Protocol:      i.Protocol,
+		UniqueId:      i.UniqueID,
+		FullId:        i.FullId,
+		QType:         i.QType,
+		RawRequest:    i.RawRequest,
+		RawResponse:   i.RawResponse,
+		RemoteAddress: i.RemoteAddress,
+		SMTPFrom:      i.SMTPFrom,
+		Timestamp:     i.Timestamp,
+	}
+	if len(v.RawRequest) > MaxResponseSize {
+		v.RawRequest = v.RawRequest[:MaxResponseSize]
+	}
+	if len(v.RawResponse) > MaxResponseSize {
+		v.RawResponse = v.RawResponse[:MaxResponseSize]
+	}
+	return v
+}
@@ -0,0 +1,125 @@
+package scanner
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/projectdiscovery/interactsh/pkg/server"
+	"github.com/projectdiscovery/nuclei/v3/pkg/output"
+)
+
+// TestInteractshOpts 测试回连模式到 nuclei SDK 选项的转换：公共服务使用默认值，禁用时关闭 OOB，自建服务允许 HTTP 回退
+func TestInteractshOpts(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          *InteractshOptions
+		isDefault     bool
+		serverURL     string
+		token         string
+		noInteractsh  bool
+		httpFallback  bool
+		poll, cool, e time.Duration
+	}{
+		{name: "nil", opts: nil, isDefault: true},
+		{name: "empty mode", opts: &InteractshOptions{ServerURL: "https://oast.example.com"}, isDefault: true},
+		{name: "public", opts: &InteractshOptions{Mode: InteractshModePublic, Token: "t"}, isDefault: true},
+		{
+			name:         "disable",
+			opts:         &InteractshOptions{Mode: InteractshModeDisable, ServerURL: "https://oast.example.com"},
+			noInteractsh: true,
+			poll:         5 * time.Second, cool: 5 * time.Second, e: time.Minute,
+		},
+		{
+			name:         "self hosted",
+			opts:         &InteractshOptions{Mode: InteractshModeSelf, ServerURL: "http://oast.example.com:8080/", Token: "secret", PollInterval: 2, CooldownPeriod: 10, Eviction: 120},
+			serverURL:    "http://oast.example.com:8080",
+			token:        "secret",
+			httpFallback: true,
+			poll:         2 * time.Second, cool: 10 * time.Second, e: 2 * time.Minute,
+		},
+		{
+			name:         "self hosted negative intervals",
+			opts:         &InteractshOptions{Mode: InteractshModeSelf, ServerURL: "https://oast.example.com", PollInterval: -1},
+			serverURL:    "https://oast.example.com",
+			httpFallback: true,
+			poll:         5 * time.Second, cool: 5 * time.Second, e: time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		got := tt.opts.interactshOpts()
+		if (tt.opts.sdkOption() == nil) != tt.isDefault {
+			t.Errorf("%s: sdkOption() default = %v, want %v", tt.name, tt.opts.sdkOption() == nil, tt.isDefault)
+		}
+		if tt.isDefault {
+			if got != nil {
+				t.Errorf("%s: interactshOpts() = %+v, want nil", tt.name, got)
+			}
+			continue
+		}
+		if got == nil {
+			t.Errorf("%s: interactshOpts() = nil", tt.name)
+			continue
+		}
+		if got.ServerURL != tt.serverURL || got.Authorization != tt.token {
+			t.Errorf("%s: server = %q, token = %q, want %q, %q", tt.name, got.ServerURL, got.Authorization, tt.serverURL, tt.token)
+		}
+		if got.NoInteractsh != tt.noInteractsh {
+			t.Errorf("%s: NoInteractsh = %v, want %v", tt.name, got.NoInteractsh, tt.noInteractsh)
+		}
+		if got.DisableHttpFallback == tt.httpFallback {
+			t.Errorf("%s: DisableHttpFallback = %v, want %v", tt.name, got.DisableHttpFallback, !tt.httpFallback)
+		}
+		if got.PollDuration != tt.poll || got.CooldownPeriod != tt.cool || got.Eviction != tt.e {
+			t.Errorf("%s: poll/cooldown/eviction = %v/%v/%v, want %v/%v/%v", tt.name, got.PollDuration, got.CooldownPeriod, got.Eviction, tt.poll, tt.cool, tt.e)
+		}
+	}
+
+	if !(*InteractshOptions)(nil).Enabled() || (&InteractshOptions{Mode: InteractshModeDisable}).Enabled() {
+		t.Error("Enabled() should be false only in disable mode")
+	}
+}
+
+// TestCollectInteraction 测试提取回连证据，原始请求和响应超长时截断
+func TestCollectInteraction(t *testing.T) {
+	now := time.Now()
+	long := strings.Repeat("A", MaxResponseSize+100)
+	tests := []struct {
+		name            string
+		event           *output.ResultEvent
+		want            bool
+		reqLen, respLen int
+	}{
+		{name: "nil event", event: nil},
+		{name: "no interaction", event: &output.ResultEvent{}},
+		{
+			name:   "short",
+			event:  &output.ResultEvent{Interaction: &server.Interaction{Protocol: "dns", UniqueID: "abc", FullId: "abc.oast.example.com", QType: "A", RawRequest: "req", RawResponse: "resp", RemoteAddress: "1.2.3.4", Timestamp: now}},
+			want:   true,
+			reqLen: 3, respLen: 4,
+		},
+		{
+			name:   "truncated",
+			event:  &output.ResultEvent{Interaction: &server.Interaction{Protocol: "http", RawRequest: long, RawResponse: long[:MaxResponseSize]}},
+			want:   true,
+			reqLen: MaxResponseSize, respLen: MaxResponseSize,
+		},
+	}
+	for _, tt := range tests {
+		got := CollectInteraction(tt.event)
+		if (got != nil) != tt.want {
+			t.Errorf("%s: CollectInteraction() = %+v, want present = %v", tt.name, got, tt.want)
+			continue
+		}
+		if got == nil {
+			continue
+		}
+		if len(got.RawRequest) != tt.reqLen || len(got.RawResponse) != tt.respLen {
+			t.Errorf("%s: request/response length = %d/%d, want %d/%d", tt.name, len(got.RawRequest), len(got.RawResponse), tt.reqLen, tt.respLen)
+		}
+		if i := tt.event.Interaction; got.Protocol != i.Protocol || got.UniqueId != i.UniqueID || got.FullId != i.FullId ||
+			got.QType != i.QType || got.RemoteAddress != i.RemoteAddress || !got.Timestamp.Equal(i.Timestamp) {
+			t.Errorf("%s: CollectInteraction() = %+v, want fields of %+v", tt.name, got, i)
+		}
+	}
+}
@@ -17,6 +17,13 @@ type MasscanScanner struct {
 	BaseScanner
 }
 
+func init() {
+	Register("masscan", Registration{
+		New:     func() Scanner { return NewMasscanScanner() },
+		Options: func() interface{} { return defaultMasscanOptions() },
+	})
+}
+
 // NewMasscanScanner 创建Masscan扫描器
 func NewMasscanScanner() *MasscanScanner {
 	return &MasscanScanner{
@@ -33,6 +40,16 @@ type MasscanOptions struct {
 	SkipHostDiscovery bool   `json:"skipHostDiscovery"` // 跳过主机发现 (-Pn)
 }
 
+// defaultMasscanOptions 默认扫描选项
+func defaultMasscanOptions() *MasscanOptions {
+	return &MasscanOptions{
+		Ports:         "21,22,23,25,80,443,3306,3389,6379,8080",
+		Rate:          1000,
+		Timeout:       3,
+		PortThreshold: 0, // 默认不限制
+	}
+}
+
 // MasscanResult Masscan输出结果
 type MasscanResult struct {
 	IP    string `json:"ip"`
@@ -43,15 +60,13 @@ type MasscanResult struct {
 	} `json:"ports"`
 }
 
+// masscanRateLimitBatch 限速时每次运行masscan扫描的目标数
+const masscanRateLimitBatch = 16
+
 // Scan 执行Masscan扫描
 func (s *MasscanScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
 	// 默认配置
-	opts := &MasscanOptions{
-		Ports:         "21,22,23,25,80,443,3306,3389,6379,8080",
-		Rate:          1000,
-		Timeout:       3,
-		PortThreshold: 0, // 默认不限制
-	}
+	opts := defaultMasscanOptions()
 
 	// 尝试从不同类型的Options中提取配置
 	if config.Options != nil {
@@ -119,13 +134,38 @@ func (s *MasscanScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanRes
 	}
 
 	// 执行masscan扫描（传入阈值参数）
-	assets := s.runMasscan(ctx, targets, opts)
+	var assets []*Asset
+	var err error
+	if config.RateLimiter == nil {
+		assets = s.runMasscan(ctx, targets, opts)
+	} else {
+		assets, err = s.runMasscanLimited(ctx, targets, opts, config.RateLimiter)
+	}
 
 	return &ScanResult{
 		WorkspaceId: config.WorkspaceId,
 		MainTaskId:  config.MainTaskId,
 		Assets:      assets,
-	}, nil
+	}, err
+}
+
+// runMasscanLimited 限速时按批次运行masscan
+// 每批开始前获取该批目标全部探测包的令牌，发包速率限制在该批目标中最严格的预算内
+func (s *MasscanScanner) runMasscanLimited(ctx context.Context, targets []string, opts *MasscanOptions, limiter RateLimiter) ([]*Asset, error) {
+	var assets []*Asset
+	portCount := len(parsePorts(opts.Ports))
+	for i := 0; i < len(targets); i += masscanRateLimitBatch {
+		batch := targets[i:min(i+masscanRateLimitBatch, len(targets))]
+		batchOpts := *opts
+		for _, target := range batch {
+			if err := limiter.Wait(ctx, target, portCount); err != nil {
+				return assets, err
+			}
+			batchOpts.Rate = capRate(limiter, target, batchOpts.Rate)
+		}
+		assets = append(assets, s.runMasscan(ctx, batch, &batchOpts)...)
+	}
+	return assets, nil
 }
 
 // runMasscan 运行masscan
@@ -24,6 +24,13 @@ type NaabuScanner struct {
 	BaseScanner
 }
 
+func init() {
+	Register("naabu", Registration{
+		New:     func() Scanner { return NewNaabuScanner() },
+		Options: func() interface{} { return defaultNaabuOptions() },
+	})
+}
+
 // NewNaabuScanner 创建Naabu扫描器
 func NewNaabuScanner() *NaabuScanner {
 	return &NaabuScanner{
@@ -41,16 +48,21 @@ type NaabuOptions struct {
 	SkipHostDiscovery bool   `json:"skipHostDiscovery"` // 跳过主机发现 (-Pn)
 }
 
-// Scan 执行Naabu扫描
-func (s *NaabuScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
-	// 默认配置
-	opts := &NaabuOptions{
+// defaultNaabuOptions 默认扫描选项
+func defaultNaabuOptions() *NaabuOptions {
+	return &NaabuOptions{
 		Ports:         "80,443,8080",
 		Rate:          1000,
 		Timeout:       60,  // 单个目标扫描超时，默认60秒
 		ScanType:      "c", // 默认 CONNECT 扫描（无需 root 权限）
 		PortThreshold: 0,   // 默认不限制
 	}
+}
+
+// Scan 执行Naabu扫描
+func (s *NaabuScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	// 默认配置
+	opts := defaultNaabuOptions()
 
 	// 日志函数，优先使用任务日志回调
 	logInfo := func(format string, args ...interface{}) {
@@ -136,7 +148,7 @@ func (s *NaabuScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResul
 	}
 
 	// 执行Naabu扫描
-	assets, thresholdExceeded := s.runNaabuWithLogger(ctx, targets, opts, logInfo, logWarn, onProgress)
+	assets, thresholdExceeded := s.runNaabuWithLogger(ctx, targets, opts, config.RateLimiter, logInfo, logWarn, onProgress)
 
 	if thresholdExceeded {
 		return &ScanResult{
@@ -162,7 +174,7 @@ type progressFunc func(progress int, message string)
 // runNaabuWithLogger 运行Naabu扫描（带日志回调）
 // 按单个目标拆分，串行执行，每个目标独立超时控制
 // 返回值: assets - 发现的资产, thresholdExceeded - 是否有任何目标超过端口阈值
-func (s *NaabuScanner) runNaabuWithLogger(ctx context.Context, targets []string, opts *NaabuOptions, logInfo, logWarn logFunc, onProgress progressFunc) ([]*Asset, bool) {
+func (s *NaabuScanner) runNaabuWithLogger(ctx context.Context, targets []string, opts *NaabuOptions, limiter RateLimiter, logInfo, logWarn logFunc, onProgress progressFunc) ([]*Asset, bool) {
 	var allAssets []*Asset
 	anyThresholdExceeded := false // 记录是否有任何目标超过阈值
 
@@ -185,6 +197,12 @@ func (s *NaabuScanner) runNaabuWithLogger(ctx context.Context, targets []string,
 		timeout = 60
 	}
 
+	// 限速时每个目标的探测包数
+	portCount := 0
+	if limiter != nil {
+		portCount = len(parsePorts(opts.Ports))
+	}
+
 	totalTargets := len(targets)
 	logInfo("Naabu: scanning %d targets, timeout %ds/target, skipHostDiscovery=%v, ports=%s", totalTargets, timeout, opts.SkipHostDiscovery, opts.Ports)
 
@@ -204,7 +222,19 @@ func (s *NaabuScanner) runNaabuWithLogger(ctx context.Context, targets []string,
 			onProgress(progress, fmt.Sprintf("Port scan: %d/%d", i, totalTargets))
 		}
 
-		assets, thresholdExceeded := s.scanSingleTargetWithLogger(ctx, target, portsStr, topPorts, opts, logInfo, logWarn)
+		// 获取该目标全部探测包的令牌，并将发包速率限制在预算内
+		targetOpts := opts
+		if limiter != nil {
+			if err := limiter.Wait(ctx, target, portCount); err != nil {
+				logInfo("Naabu: cancelled at %d/%d targets while waiting for rate limit", i, totalTargets)
+				return allAssets, anyThresholdExceeded
+			}
+			capped := *opts
+			capped.Rate = capRate(limiter, target, opts.Rate)
+			targetOpts = &capped
+		}
+
+		assets, thresholdExceeded := s.scanSingleTargetWithLogger(ctx, target, portsStr, topPorts, targetOpts, logInfo, logWarn)
 		
 		if thresholdExceeded {
 			// 单个目标超过阈值，记录并跳过该目标，继续扫描其他目标
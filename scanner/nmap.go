@@ -16,6 +16,13 @@ type NmapScanner struct {
 	BaseScanner
 }
 
+func init() {
+	Register("nmap", Registration{
+		New:     func() Scanner { return NewNmapScanner() },
+		Options: func() interface{} { return defaultNmapOptions() },
+	})
+}
+
 // NewNmapScanner 创建Nmap扫描器
 func NewNmapScanner() *NmapScanner {
 	return &NmapScanner{
@@ -31,6 +38,14 @@ type NmapOptions struct {
 	Args    string `json:"args"` // 额外参数
 }
 
+// defaultNmapOptions 默认扫描选项
+func defaultNmapOptions() *NmapOptions {
+	return &NmapOptions{
+		Ports:   "21,22,23,25,80,443,3306,3389,6379,8080",
+		Timeout: 3,
+	}
+}
+
 // NmapRun Nmap XML输出结构
 type NmapRun struct {
 	XMLName xml.Name   `xml:"nmaprun"`
@@ -93,10 +108,7 @@ type NmapService struct {
 // Scan 执行Nmap扫描
 func (s *NmapScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
 	// 默认配置
-	opts := &NmapOptions{
-		Ports:   "21,22,23,25,80,443,3306,3389,6379,8080",
-		Timeout: 3,
-	}
+	opts := defaultNmapOptions()
 
 	// 尝试从不同类型的Options中提取配置
 	if config.Options != nil {
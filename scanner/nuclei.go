@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"cscan/pkg/mapping"
@@ -61,6 +62,13 @@ type NucleiScanner struct {
 	BaseScanner
 }
 
+func init() {
+	Register("nuclei", Registration{
+		New:     func() Scanner { return NewNucleiScanner() },
+		Options: func() interface{} { return defaultNucleiOptions() },
+	})
+}
+
 // NewNucleiScanner 创建Nuclei扫描器
 func NewNucleiScanner() *NucleiScanner {
 	return &NucleiScanner{
@@ -86,9 +94,22 @@ type NucleiOptions struct {
 	CustomTemplates      []string                      `json:"customTemplates"`      // 自定义模板内容(YAML)
 	CustomPocOnly        bool                          `json:"customPocOnly"`        // 只使用自定义POC
 	NucleiTemplates      []string                      `json:"nucleiTemplates"`      // 从数据库加载的Nuclei模板内容
+	Interactsh           *InteractshOptions            `json:"interactsh,omitempty"` // OOB 回连配置，为空使用nuclei默认
 	OnVulnerabilityFound func(vul *Vulnerability)      `json:"-"`                    // 发现漏洞时的回调函数
 }
 
+// defaultNucleiOptions 默认扫描选项
+func defaultNucleiOptions() *NucleiOptions {
+	return &NucleiOptions{
+		Severity:      "critical,high,medium",
+		RateLimit:     150,
+		Concurrency:   25,
+		Timeout:       600,  // 总超时默认10分钟
+		TargetTimeout: 600,  // 单目标超时默认600秒
+		Retries:       1,
+	}
+}
+
 // Scan 执行Nuclei扫描
 func (s *NucleiScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
 	result := &ScanResult{
@@ -98,14 +119,7 @@ func (s *NucleiScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResu
 	}
 
 	// 解析选项
-	opts := &NucleiOptions{
-		Severity:      "critical,high,medium",
-		RateLimit:     150,
-		Concurrency:   25,
-		Timeout:       600,  // 总超时默认10分钟
-		TargetTimeout: 600,  // 单目标超时默认600秒
-		Retries:       1,
-	}
+	opts := defaultNucleiOptions()
 	if config.Options != nil {
 		if o, ok := config.Options.(*NucleiOptions); ok {
 			opts = o
@@ -225,8 +239,16 @@ func (s *NucleiScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResu
 		// 显示目标
 		taskLog("INFO", "POC [%d/%d]: %s", i+1, len(targets), target)
 
+		// 将引擎的全局速率限制在该目标的限速预算内
+		targetOpts := opts
+		if rate := capRate(config.RateLimiter, target, opts.RateLimit); rate != opts.RateLimit {
+			capped := *opts
+			capped.RateLimit = rate
+			targetOpts = &capped
+		}
+
 		// 扫描单个目标（内部已处理超时，使用独立context避免任务间相互影响）
-		targetVuls := s.scanSingleTarget(ctx, target, opts, customTemplatePaths, templateNames, config.TaskLogger)
+		targetVuls := s.scanSingleTarget(ctx, target, targetOpts, config.RateLimiter, customTemplatePaths, templateNames, config.TaskLogger)
 
 		// 合并结果并去重
 		for _, vul := range targetVuls {
@@ -248,7 +270,8 @@ func (s *NucleiScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResu
 }
 
 // scanSingleTarget 扫描单个目标
-func (s *NucleiScanner) scanSingleTarget(ctx context.Context, target string, opts *NucleiOptions, customTemplatePaths []string, templateNames []string, taskLogger func(level, format string, args ...interface{})) []*Vulnerability {
+// limiter 为空表示不限速，否则每个模板执行完成的回调中获取令牌，阻塞引擎的执行协程以控制速率
+func (s *NucleiScanner) scanSingleTarget(ctx context.Context, target string, opts *NucleiOptions, limiter RateLimiter, customTemplatePaths []string, templateNames []string, taskLogger func(level, format string, args ...interface{})) []*Vulnerability {
 	var vuls []*Vulnerability
 	startTime := time.Now()
 
@@ -270,7 +293,9 @@ func (s *NucleiScanner) scanSingleTarget(ctx context.Context, target string, opt
 		}
 		return vuls
 	}
-	defer ne.Close()
+	// 关闭引擎时会等待 OOB 回连冷却期，期间收到的交互仍会触发回调
+	closeEngine := sync.OnceFunc(ne.Close)
+	defer closeEngine()
 
 	// 启用请求/响应存储（用于证据链）
 	if engineOpts := ne.Options(); engineOpts != nil {
@@ -342,7 +367,12 @@ func (s *NucleiScanner) scanSingleTarget(ctx context.Context, target string, opt
 
 	// 执行扫描并通过回调收集结果
 	// 使用EnableMatcherStatus后，回调会在每个模板执行完成后触发（无论是否匹配）
+	// OOB 回连结果由 interactsh 轮询协程触发，需要加锁
+	var mu sync.Mutex
 	err = ne.ExecuteCallbackWithCtx(engineCtx, func(event *output.ResultEvent) {
+		waitRate(engineCtx, limiter, target, 1)
+		mu.Lock()
+		defer mu.Unlock()
 		scannedCount++
 		
 		// 判断是否匹配成功（发现漏洞）
@@ -367,6 +397,9 @@ func (s *NucleiScanner) scanSingleTarget(ctx context.Context, target string, opt
 
 				vul := s.convertResult(event)
 				if vul != nil {
+					if vul.Interaction != nil && taskLogger != nil {
+						taskLogger("INFO", "    OOB %s interaction from %s", vul.Interaction.Protocol, vul.Interaction.RemoteAddress)
+					}
 					vuls = append(vuls, vul)
 				}
 			}
@@ -404,6 +437,11 @@ func (s *NucleiScanner) scanSingleTarget(ctx context.Context, target string, opt
 		}
 	}
 
+	// 等待迟到的 OOB 回连后再统计结果
+	closeEngine()
+	mu.Lock()
+	defer mu.Unlock()
+
 	// 扫描完成后输出统计
 	elapsed := int(time.Since(startTime).Seconds())
 	if taskLogger != nil {
@@ -492,7 +530,8 @@ func (s *NucleiScanner) ScanBatch(ctx context.Context, targets []string, opts *N
 		taskLog("ERROR", "Failed to create nuclei engine: %v", err)
 		return nil, err
 	}
-	defer ne.Close()
+	closeEngine := sync.OnceFunc(ne.Close)
+	defer closeEngine()
 
 	// 启用请求/响应存储（用于证据链）
 	if engineOpts := ne.Options(); engineOpts != nil {
@@ -538,7 +577,10 @@ func (s *NucleiScanner) ScanBatch(ctx context.Context, targets []string, opts *N
 
 	// 执行扫描
 	taskLog("INFO", "Starting batch scan (timeout: %ds)...", timeout)
+	var mu sync.Mutex
 	err = ne.ExecuteCallbackWithCtx(engineCtx, func(event *output.ResultEvent) {
+		mu.Lock()
+		defer mu.Unlock()
 		scannedCount++
 
 		// 判断是否匹配成功
@@ -552,6 +594,9 @@ func (s *NucleiScanner) ScanBatch(ctx context.Context, targets []string, opts *N
 
 				vul := s.convertResult(event)
 				if vul != nil {
+					if vul.Interaction != nil {
+						taskLog("INFO", "    OOB %s interaction from %s", vul.Interaction.Protocol, vul.Interaction.RemoteAddress)
+					}
 					vuls = append(vuls, vul)
 					// 实时回调
 					if opts.OnVulnerabilityFound != nil {
@@ -567,6 +612,11 @@ func (s *NucleiScanner) ScanBatch(ctx context.Context, targets []string, opts *N
 		}
 	})
 
+	// 等待迟到的 OOB 回连后再统计结果
+	closeEngine()
+	mu.Lock()
+	defer mu.Unlock()
+
 	elapsed := time.Since(startTime).Seconds()
 
 	if err != nil {
@@ -609,6 +659,15 @@ func (s *NucleiScanner) prepareTargets(assets []*Asset) []string {
 			seen[target] = true
 			targets = append(targets, target)
 		}
+
+		// 爬虫发现的带参数URL单独作为目标，供参数类模板检测
+		if asset.Source == "crawler" && strings.Contains(asset.Path, "?") {
+			urlTarget := target + strings.SplitN(asset.Path, "#", 2)[0]
+			if !seen[urlTarget] {
+				seen[urlTarget] = true
+				targets = append(targets, urlTarget)
+			}
+		}
 	}
 
 	if skipped > 0 {
@@ -673,6 +732,11 @@ func (s *NucleiScanner) buildNucleiOptions(opts *NucleiOptions, customTemplatePa
 		nucleiOpts = append(nucleiOpts, nuclei.WithGlobalRateLimit(opts.RateLimit, 1))
 	}
 
+	// OOB 回连服务
+	if opt := opts.Interactsh.sdkOption(); opt != nil {
+		nucleiOpts = append(nucleiOpts, opt)
+	}
+
 	// 禁用更新检查
 	nucleiOpts = append(nucleiOpts, nuclei.DisableUpdateCheck())
 
@@ -746,6 +810,9 @@ func (s *NucleiScanner) convertResult(event *output.ResultEvent) *Vulnerability
 		vul.ResponseTruncated = evidence.ResponseTruncated
 	}
 
+	// OOB 回连证据
+	vul.Interaction = CollectInteraction(event)
+
 	return vul
 }
 
@@ -13,6 +13,13 @@ type PortScanner struct {
 	BaseScanner
 }
 
+func init() {
+	Register("portscan", Registration{
+		New:     func() Scanner { return NewPortScanner() },
+		Options: func() interface{} { return &PortScanOptions{} },
+	})
+}
+
 // NewPortScanner 创建端口扫描器
 func NewPortScanner() *PortScanner {
 	return &PortScanner{
@@ -0,0 +1,57 @@
+package scanner
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// Registration 扫描器注册信息，Worker 和流水线按名称创建扫描器并解析选项
+type Registration struct {
+	New     func() Scanner
+	Options func() interface{} // 返回选项结构体指针，为空表示扫描器没有选项
+}
+
+var registry = make(map[string]Registration)
+
+// Register 注册扫描器，在扫描器文件的 init 中调用
+func Register(name string, r Registration) {
+	if _, ok := registry[name]; ok {
+		panic("scanner: duplicate registration " + name)
+	}
+	registry[name] = r
+}
+
+// Lookup 按名称查找扫描器
+func Lookup(name string) (Registration, bool) {
+	r, ok := registry[name]
+	return r, ok
+}
+
+// Registered 已注册的扫描器名称
+func Registered() []string {
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// DecodeOptions 将 JSON 选项解析为扫描器的选项类型
+func DecodeOptions(name string, raw json.RawMessage) (interface{}, error) {
+	r, ok := registry[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown scanner: %s", name)
+	}
+	if r.Options == nil {
+		return nil, nil
+	}
+	opts := r.Options()
+	if len(raw) > 0 && string(raw) != "null" {
+		if err := json.Unmarshal(raw, opts); err != nil {
+			return nil, fmt.Errorf("invalid %s options: %w", name, err)
+		}
+	}
+	return opts, nil
+}
@@ -24,6 +24,35 @@ type ScanConfig struct {
 	TaskLogger func(level, format string, args ...interface{}) `json:"-"`
 	// OnProgress 进度回调，参数为当前进度(0-100)和描述
 	OnProgress func(progress int, message string) `json:"-"`
+	// RateLimiter 工作空间的分布式限速器，为空表示不限速
+	RateLimiter RateLimiter `json:"-"`
+}
+
+// RateLimiter 分布式限速器，多个 Worker 和子任务共享同一目标、网段和工作空间的令牌桶
+type RateLimiter interface {
+	// Wait 阻塞直到获取访问 host 的 n 个令牌（请求数或探测包数）
+	Wait(ctx context.Context, host string, n int) error
+	// Rate 访问 host 的速率上限（每秒），host 为空时为工作空间的上限，0 表示不限制
+	Rate(host string) int
+}
+
+// waitRate 获取访问 host 的 n 个令牌，未配置限速器时直接返回
+func waitRate(ctx context.Context, limiter RateLimiter, host string, n int) error {
+	if limiter == nil || n <= 0 {
+		return nil
+	}
+	return limiter.Wait(ctx, host, n)
+}
+
+// capRate 将扫描器自身的速率限制在限速预算内，rate 为 0 表示扫描器不限速
+func capRate(limiter RateLimiter, host string, rate int) int {
+	if limiter == nil {
+		return rate
+	}
+	if max := limiter.Rate(host); max > 0 && (rate <= 0 || rate > max) {
+		return max
+	}
+	return rate
 }
 
 // ScanResult 扫描结果
@@ -32,6 +61,7 @@ type ScanResult struct {
 	MainTaskId      string           `json:"mainTaskId"`
 	Assets          []*Asset         `json:"assets"`
 	Vulnerabilities []*Vulnerability `json:"vulnerabilities"`
+	DNSRecords      []*DNSRecord     `json:"dnsRecords,omitempty"` // DNS侦察采集的记录
 }
 
 // Asset 资产
@@ -55,7 +85,12 @@ type Asset struct {
 	IsCDN      bool     `json:"isCdn"`
 	CName      string   `json:"cname"`
 	IsCloud    bool     `json:"isCloud"`
+	CDNName    string   `json:"cdnName,omitempty"`   // CDN厂商
+	WAF        string   `json:"waf,omitempty"`       // WAF厂商
+	CloudName  string   `json:"cloudName,omitempty"` // 云厂商
 	IsHTTP     bool     `json:"isHttp"`   // 是否为HTTP服务
+	Transport  string   `json:"transport,omitempty"` // 传输层协议，空为tcp，udp 为 UDP 扫描发现
+	TLS        *TLSInfo `json:"tls,omitempty"`       // TLS 证书和握手信息
 	IPV4       []IPInfo `json:"ipv4"`
 	IPV6       []IPInfo `json:"ipv6"`
 	Source     string   `json:"source"`   // 资产来源: subfinder, portscan, urlfinder, etc.
@@ -97,6 +132,9 @@ type Vulnerability struct {
 	Request           string   `json:"request,omitempty"`
 	Response          string   `json:"response,omitempty"`
 	ResponseTruncated bool     `json:"responseTruncated,omitempty"`
+
+	// OOB 回连证据（blind SSRF/RCE/XXE 等模板）
+	Interaction *VulInteraction `json:"interaction,omitempty"`
 }
 
 // BaseScanner 基础扫描器
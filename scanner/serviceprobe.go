@@ -0,0 +1,722 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/tls"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/zeromicro/go-zero/core/logx"
+)
+
+//go:embed probes/nmap-service-probes
+var defaultServiceProbes string
+
+// ServiceProbeScanner 纯 Go 实现的服务识别扫描器，无需安装 nmap。
+// 使用与 nmap-service-probes 兼容的探测库，依次发送探测并用正则匹配响应
+type ServiceProbeScanner struct {
+	BaseScanner
+}
+
+func init() {
+	Register("serviceprobe", Registration{
+		New:     func() Scanner { return NewServiceProbeScanner() },
+		Options: func() interface{} { return &ServiceProbeOptions{} },
+	})
+}
+
+// NewServiceProbeScanner 创建服务识别扫描器
+func NewServiceProbeScanner() *ServiceProbeScanner {
+	return &ServiceProbeScanner{
+		BaseScanner: BaseScanner{name: "serviceprobe"},
+	}
+}
+
+// ServiceProbeOptions 服务识别选项
+type ServiceProbeOptions struct {
+	Ports      string `json:"ports"`
+	Timeout    int    `json:"timeout"`    // 单个探测等待响应的超时时间(秒)，默认3秒
+	Intensity  int    `json:"intensity"`  // 探测强度0-9，只发送 rarity 不大于该值的探测，默认7
+	Concurrent int    `json:"concurrent"` // 并发数，默认20
+	ProbeFile  string `json:"probeFile"`  // nmap-service-probes 文件路径，为空使用内置探测库
+}
+
+// Scan 对目标端口执行服务识别，只返回可连接的端口
+func (s *ServiceProbeScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	opts := &ServiceProbeOptions{}
+	if config.Options != nil {
+		switch v := config.Options.(type) {
+		case *ServiceProbeOptions:
+			*opts = *v
+		default:
+			if data, err := json.Marshal(config.Options); err == nil {
+				json.Unmarshal(data, opts)
+			}
+		}
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 3
+	}
+	if opts.Intensity <= 0 || opts.Intensity > 9 {
+		opts.Intensity = 7
+	}
+	if opts.Concurrent <= 0 {
+		opts.Concurrent = 20
+	}
+
+	db := DefaultServiceProbeDB()
+	if opts.ProbeFile != "" {
+		custom, err := LoadServiceProbes(opts.ProbeFile)
+		if err != nil {
+			return nil, err
+		}
+		db = custom
+	}
+
+	targets := parseTargets(config.Target)
+	if len(config.Targets) > 0 {
+		targets = append(targets, config.Targets...)
+	}
+	ports := parsePorts(opts.Ports)
+
+	var assets []*Asset
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	type job struct {
+		host string
+		port int
+	}
+	jobs := make(chan job, opts.Concurrent)
+
+	for i := 0; i < opts.Concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				asset := db.Identify(ctx, j.host, j.port, opts)
+				if asset == nil {
+					continue
+				}
+				mu.Lock()
+				assets = append(assets, asset)
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, host := range targets {
+		for _, port := range ports {
+			select {
+			case <-ctx.Done():
+				break dispatch
+			case jobs <- job{host, port}:
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+		Assets:      assets,
+	}, nil
+}
+
+// ServiceProbeDB 服务探测库
+type ServiceProbeDB struct {
+	probes  []*serviceProbe
+	byName  map[string]*serviceProbe
+	Skipped int // 因正则不兼容 RE2 而跳过的匹配规则数
+}
+
+// serviceProbe 一条 Probe 指令及其匹配规则
+type serviceProbe struct {
+	name      string
+	payload   []byte
+	rarity    int
+	totalWait time.Duration
+	ports     []portRange
+	sslPorts  []portRange
+	fallback  []string
+	matches   []*serviceMatch
+}
+
+type portRange struct{ start, end int }
+
+// serviceMatch 一条 match/softmatch 规则
+type serviceMatch struct {
+	service string
+	soft    bool
+	pattern *regexp.Regexp
+	// 产品和版本模板，可引用 $1 等捕获组；i/h/o/d 等其他字段不使用
+	product, version string
+}
+
+// serviceResult 服务识别结果
+type serviceResult struct {
+	service string
+	soft    bool
+	product string
+	version string
+	banner  []byte
+}
+
+var (
+	defaultProbeDB     *ServiceProbeDB
+	defaultProbeDBOnce sync.Once
+)
+
+// DefaultServiceProbeDB 获取内置探测库
+func DefaultServiceProbeDB() *ServiceProbeDB {
+	defaultProbeDBOnce.Do(func() {
+		db, err := ParseServiceProbes(strings.NewReader(defaultServiceProbes))
+		if err != nil {
+			logx.Errorf("parse builtin service probes error: %v", err)
+			db = &ServiceProbeDB{byName: map[string]*serviceProbe{}}
+		}
+		defaultProbeDB = db
+	})
+	return defaultProbeDB
+}
+
+// LoadServiceProbes 从文件加载 nmap-service-probes 探测库
+func LoadServiceProbes(path string) (*ServiceProbeDB, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseServiceProbes(f)
+}
+
+// ParseServiceProbes 解析 nmap-service-probes 格式的探测库，只加载 TCP 探测
+func ParseServiceProbes(r io.Reader) (*ServiceProbeDB, error) {
+	db := &ServiceProbeDB{byName: make(map[string]*serviceProbe)}
+	var cur *serviceProbe
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		directive, rest, _ := strings.Cut(line, " ")
+		rest = strings.TrimSpace(rest)
+
+		if directive == "Probe" {
+			p, err := parseProbeLine(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			cur = p
+			if p != nil {
+				db.probes = append(db.probes, p)
+				db.byName[p.name] = p
+			}
+			continue
+		}
+		if directive == "Exclude" {
+			continue
+		}
+		// 当前为 UDP 探测时忽略其下的所有指令
+		if cur == nil {
+			continue
+		}
+
+		switch directive {
+		case "match", "softmatch":
+			m, err := parseMatchLine(rest, directive == "softmatch")
+			if err != nil {
+				if _, ok := err.(*unsupportedPatternError); ok {
+					db.Skipped++
+					continue
+				}
+				return nil, fmt.Errorf("line %d: %v", lineNo, err)
+			}
+			cur.matches = append(cur.matches, m)
+		case "ports":
+			cur.ports = parsePortRanges(rest)
+		case "sslports":
+			cur.sslPorts = parsePortRanges(rest)
+		case "rarity":
+			cur.rarity, _ = strconv.Atoi(rest)
+		case "totalwaitms":
+			if ms, err := strconv.Atoi(rest); err == nil {
+				cur.totalWait = time.Duration(ms) * time.Millisecond
+			}
+		case "fallback":
+			for _, name := range strings.Split(rest, ",") {
+				if name = strings.TrimSpace(name); name != "" {
+					cur.fallback = append(cur.fallback, name)
+				}
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return db, nil
+}
+
+// parseProbeLine 解析 "TCP NAME q|payload| [no-payload]"，UDP 探测返回 nil
+func parseProbeLine(rest string) (*serviceProbe, error) {
+	fields := strings.SplitN(rest, " ", 3)
+	if len(fields) < 3 {
+		return nil, fmt.Errorf("invalid probe: %s", rest)
+	}
+	if fields[0] != "TCP" {
+		return nil, nil
+	}
+	q := fields[2]
+	if len(q) < 3 || q[0] != 'q' {
+		return nil, fmt.Errorf("invalid probe payload: %s", q)
+	}
+	delim := q[1]
+	end := strings.IndexByte(q[2:], delim)
+	if end < 0 {
+		return nil, fmt.Errorf("unterminated probe payload: %s", q)
+	}
+	payload, err := unescapeProbeString(q[2 : 2+end])
+	if err != nil {
+		return nil, err
+	}
+	return &serviceProbe{name: fields[1], payload: payload}, nil
+}
+
+// unsupportedPatternError 正则无法被 RE2 编译
+type unsupportedPatternError struct{ err error }
+
+func (e *unsupportedPatternError) Error() string { return e.err.Error() }
+
+// parseMatchLine 解析 "service m|regex|flags p/product/ v/version/ ..."
+func parseMatchLine(rest string, soft bool) (*serviceMatch, error) {
+	service, rest, ok := strings.Cut(rest, " ")
+	if !ok || len(rest) < 3 || rest[0] != 'm' {
+		return nil, fmt.Errorf("invalid match: %s", rest)
+	}
+	delim := rest[1]
+	end := strings.IndexByte(rest[2:], delim)
+	if end < 0 {
+		return nil, fmt.Errorf("unterminated match pattern: %s", rest)
+	}
+	pattern := rest[2 : 2+end]
+	rest = rest[3+end:]
+
+	prefix := ""
+	for len(rest) > 0 && rest[0] != ' ' {
+		switch rest[0] {
+		case 'i':
+			prefix += "(?i)"
+		case 's':
+			prefix += "(?s)"
+		}
+		rest = rest[1:]
+	}
+	re, err := regexp.Compile(prefix + pattern)
+	if err != nil {
+		return nil, &unsupportedPatternError{err}
+	}
+
+	m := &serviceMatch{service: service, soft: soft, pattern: re}
+	// 版本字段格式为 <字母><分隔符>内容<分隔符>，cpe:/.../ 忽略
+	for rest = strings.TrimSpace(rest); rest != ""; rest = strings.TrimSpace(rest) {
+		if strings.HasPrefix(rest, "cpe:") {
+			rest = rest[3:]
+		}
+		if len(rest) < 3 {
+			break
+		}
+		field, delim := rest[0], rest[1]
+		end := strings.IndexByte(rest[2:], delim)
+		if end < 0 {
+			break
+		}
+		value := rest[2 : 2+end]
+		rest = rest[3+end:]
+		// cpe 后可带 a 标记
+		if len(rest) > 0 && rest[0] == 'a' {
+			rest = rest[1:]
+		}
+		switch field {
+		case 'p':
+			m.product = value
+		case 'v':
+			m.version = value
+		}
+	}
+	return m, nil
+}
+
+// unescapeProbeString 解析探测载荷中的 C 风格转义
+func unescapeProbeString(s string) ([]byte, error) {
+	var buf bytes.Buffer
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			buf.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			return nil, fmt.Errorf("trailing backslash in probe: %s", s)
+		}
+		switch s[i] {
+		case '0':
+			buf.WriteByte(0)
+		case 'a':
+			buf.WriteByte('\a')
+		case 'b':
+			buf.WriteByte('\b')
+		case 'f':
+			buf.WriteByte('\f')
+		case 'n':
+			buf.WriteByte('\n')
+		case 'r':
+			buf.WriteByte('\r')
+		case 't':
+			buf.WriteByte('\t')
+		case 'v':
+			buf.WriteByte('\v')
+		case 'x':
+			if i+2 >= len(s) {
+				return nil, fmt.Errorf("invalid hex escape in probe: %s", s)
+			}
+			v, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+			if err != nil {
+				return nil, fmt.Errorf("invalid hex escape in probe: %s", s)
+			}
+			buf.WriteByte(byte(v))
+			i += 2
+		default:
+			buf.WriteByte(s[i])
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// parsePortRanges 解析 "80,443,8000-8010"
+func parsePortRanges(s string) []portRange {
+	var ranges []portRange
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		start, end, err := parsePortRangeValue(part)
+		if err != nil {
+			continue
+		}
+		ranges = append(ranges, portRange{start, end})
+	}
+	return ranges
+}
+
+func parsePortRangeValue(v string) (int, int, error) {
+	startStr, endStr, found := strings.Cut(v, "-")
+	if !found {
+		endStr = startStr
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endStr))
+	if err != nil {
+		return 0, 0, err
+	}
+	return start, end, nil
+}
+
+func inPortRanges(ranges []portRange, port int) bool {
+	for _, r := range ranges {
+		if port >= r.start && port <= r.end {
+			return true
+		}
+	}
+	return false
+}
+
+// probesFor 按 nmap 的顺序排列探测：NULL 探测、端口匹配的探测、其余 rarity 不超过强度的探测
+func (db *ServiceProbeDB) probesFor(port int, useTLS bool, intensity int) []*serviceProbe {
+	var null, matched, others []*serviceProbe
+	for _, p := range db.probes {
+		switch {
+		case len(p.payload) == 0:
+			null = append(null, p)
+		case inPortRanges(p.ports, port) || (useTLS && inPortRanges(p.sslPorts, port)):
+			matched = append(matched, p)
+		case p.rarity <= intensity:
+			others = append(others, p)
+		}
+	}
+	sort.SliceStable(matched, func(i, j int) bool { return matched[i].rarity < matched[j].rarity })
+	sort.SliceStable(others, func(i, j int) bool { return others[i].rarity < others[j].rarity })
+	return append(append(null, matched...), others...)
+}
+
+// Identify 识别 host:port 上的服务，端口无法连接时返回 nil
+func (db *ServiceProbeDB) Identify(ctx context.Context, host string, port int, opts *ServiceProbeOptions) *Asset {
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	timeout := time.Duration(opts.Timeout) * time.Second
+
+	result, reachable := db.identify(ctx, addr, port, false, opts)
+	if !reachable {
+		return nil
+	}
+	// 明文未识别或识别为 SSL 时尝试 TLS 握手，在 TLS 之上重新探测
+	if result == nil || result.service == "ssl" {
+		if conn, err := dialProbe(ctx, addr, true, timeout); err == nil {
+			conn.Close()
+			tlsResult, _ := db.identify(ctx, addr, port, true, opts)
+			if tlsResult == nil {
+				tlsResult = &serviceResult{}
+			}
+			if tlsResult.service == "" {
+				tlsResult.service = "ssl"
+			} else {
+				tlsResult.service = "ssl/" + tlsResult.service
+			}
+			if len(tlsResult.banner) == 0 && result != nil {
+				tlsResult.banner = result.banner
+			}
+			result = tlsResult
+		}
+	}
+
+	asset := &Asset{
+		Authority: fmt.Sprintf("%s:%d", host, port),
+		Host:      host,
+		Port:      port,
+		Category:  getCategory(host),
+	}
+	if result == nil {
+		return asset
+	}
+	asset.Service = result.service
+	asset.Banner = printableBanner(bytes.TrimRight(result.banner, "\r\n\x00"))
+	if result.product != "" {
+		asset.Server = strings.TrimSpace(result.product + " " + result.version)
+		productInfo := result.product
+		if result.version != "" {
+			productInfo += ":" + result.version
+		}
+		asset.App = []string{productInfo}
+	}
+	return asset
+}
+
+// identify 依次发送探测直到命中 match，返回结果及端口是否可连接
+func (db *ServiceProbeDB) identify(ctx context.Context, addr string, port int, useTLS bool, opts *ServiceProbeOptions) (*serviceResult, bool) {
+	timeout := time.Duration(opts.Timeout) * time.Second
+	var soft *serviceResult
+	var banner []byte
+	reachable := false
+
+	for _, p := range db.probesFor(port, useTLS, opts.Intensity) {
+		if ctx.Err() != nil {
+			break
+		}
+		wait := timeout
+		if p.totalWait > 0 && p.totalWait < wait {
+			wait = p.totalWait
+		}
+		resp, err := sendProbe(ctx, addr, useTLS, p.payload, timeout, wait)
+		if err != nil {
+			// 第一个探测就无法连接，视为端口关闭
+			if !reachable {
+				return nil, false
+			}
+			continue
+		}
+		reachable = true
+		if len(resp) == 0 {
+			continue
+		}
+		if banner == nil {
+			banner = resp
+		}
+
+		result := db.match(p, resp, soft)
+		if result == nil {
+			continue
+		}
+		result.banner = resp
+		if !result.soft {
+			return result, true
+		}
+		if soft == nil {
+			soft = result
+		}
+	}
+	if soft != nil {
+		return soft, reachable
+	}
+	if banner != nil {
+		return &serviceResult{banner: banner}, reachable
+	}
+	return nil, reachable
+}
+
+// match 用探测自身、fallback 探测和 NULL 探测的规则匹配响应。
+// 已有 softmatch 时只接受同一服务的规则
+func (db *ServiceProbeDB) match(p *serviceProbe, resp []byte, soft *serviceResult) *serviceResult {
+	subject := latin1String(resp)
+	candidates := []*serviceProbe{p}
+	for _, name := range p.fallback {
+		if fp, ok := db.byName[name]; ok && fp != p {
+			candidates = append(candidates, fp)
+		}
+	}
+	if null, ok := db.byName["NULL"]; ok && null != p {
+		candidates = append(candidates, null)
+	}
+
+	for _, cp := range candidates {
+		for _, m := range cp.matches {
+			if soft != nil && m.service != soft.service {
+				continue
+			}
+			if soft != nil && m.soft {
+				continue
+			}
+			groups := m.pattern.FindStringSubmatch(subject)
+			if groups == nil {
+				continue
+			}
+			return &serviceResult{
+				service: m.service,
+				soft:    m.soft,
+				product: expandVersionTemplate(m.product, groups),
+				version: expandVersionTemplate(m.version, groups),
+			}
+		}
+	}
+	return nil
+}
+
+// dialProbe 建立 TCP 或 TLS 连接
+func dialProbe(ctx context.Context, addr string, useTLS bool, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !useTLS {
+		return dialer.DialContext(ctx, "tcp", addr)
+	}
+	tlsDialer := &tls.Dialer{
+		NetDialer: dialer,
+		Config:    &tls.Config{InsecureSkipVerify: true},
+	}
+	return tlsDialer.DialContext(ctx, "tcp", addr)
+}
+
+// sendProbe 发送探测载荷并读取响应，首包到达后再短暂等待后续数据
+func sendProbe(ctx context.Context, addr string, useTLS bool, payload []byte, timeout, wait time.Duration) ([]byte, error) {
+	conn, err := dialProbe(ctx, addr, useTLS, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if len(payload) > 0 {
+		conn.SetWriteDeadline(time.Now().Add(timeout))
+		if _, err := conn.Write(payload); err != nil {
+			return nil, nil
+		}
+	}
+
+	var resp []byte
+	buf := make([]byte, 4096)
+	conn.SetReadDeadline(time.Now().Add(wait))
+	for len(resp) < 64*1024 {
+		n, err := conn.Read(buf)
+		resp = append(resp, buf[:n]...)
+		if err != nil {
+			break
+		}
+		conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+	}
+	return resp, nil
+}
+
+// latin1String 将每个字节映射为同值的字符，使正则中的 \xHH 按字节匹配
+func latin1String(b []byte) string {
+	runes := make([]rune, len(b))
+	for i, c := range b {
+		runes[i] = rune(c)
+	}
+	return string(runes)
+}
+
+// latin1Bytes latin1String 的逆操作
+func latin1Bytes(s string) []byte {
+	b := make([]byte, 0, len(s))
+	for _, r := range s {
+		b = append(b, byte(r))
+	}
+	return b
+}
+
+var versionTemplateRe = regexp.MustCompile(`\$(?:(\d)|P\((\d)\)|SUBST\((\d),"([^"]*)","([^"]*)"\)|I\((\d),"([<>])"\))`)
+
+// expandVersionTemplate 替换版本模板中的 $1、$P(1)、$SUBST(1,"a","b")、$I(1,">")
+func expandVersionTemplate(tpl string, groups []string) string {
+	if tpl == "" || !strings.Contains(tpl, "$") {
+		return tpl
+	}
+	group := func(s string) []byte {
+		i, _ := strconv.Atoi(s)
+		if i <= 0 || i >= len(groups) {
+			return nil
+		}
+		return latin1Bytes(groups[i])
+	}
+	out := versionTemplateRe.ReplaceAllStringFunc(tpl, func(ref string) string {
+		sm := versionTemplateRe.FindStringSubmatch(ref)
+		switch {
+		case sm[1] != "":
+			return string(group(sm[1]))
+		case sm[2] != "":
+			var sb strings.Builder
+			for _, c := range group(sm[2]) {
+				if c >= 0x20 && c < 0x7f {
+					sb.WriteByte(c)
+				}
+			}
+			return sb.String()
+		case sm[3] != "":
+			return strings.ReplaceAll(string(group(sm[3])), sm[4], sm[5])
+		default:
+			b := group(sm[6])
+			if len(b) == 0 || len(b) > 8 {
+				return ""
+			}
+			var v uint64
+			for i := range b {
+				c := b[i]
+				if sm[7] == "<" {
+					c = b[len(b)-1-i]
+				}
+				v = v<<8 | uint64(c)
+			}
+			return strconv.FormatUint(v, 10)
+		}
+	})
+	return strings.TrimSpace(out)
+}
@@ -0,0 +1,280 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"strings"
+	"testing"
+)
+
+// TestDefaultServiceProbeDB 测试内置探测库的解析结果
+func TestDefaultServiceProbeDB(t *testing.T) {
+	db := DefaultServiceProbeDB()
+	if db.Skipped != 0 {
+		t.Errorf("builtin probes skipped %d rules, want 0", db.Skipped)
+	}
+	for _, name := range []string{"NULL", "GetRequest", "redis-server", "TerminalServerCookie", "SMBProgNeg", "LDAPSearchReq", "PostgreSQLStartup", "mongodb"} {
+		p, ok := db.byName[name]
+		if !ok {
+			t.Fatalf("probe %s not loaded", name)
+		}
+		if len(p.matches) == 0 {
+			t.Errorf("probe %s has no match rules", name)
+		}
+	}
+
+	null := db.byName["NULL"]
+	if len(null.payload) != 0 || null.totalWait.Milliseconds() != 6000 {
+		t.Errorf("NULL probe = payload %q, totalwait %v", null.payload, null.totalWait)
+	}
+	redis := db.byName["redis-server"]
+	if string(redis.payload) != "*1\r\n$4\r\ninfo\r\n" || redis.rarity != 8 || !inPortRanges(redis.ports, 6380) {
+		t.Errorf("redis-server probe = payload %q, rarity %d, ports %v", redis.payload, redis.rarity, redis.ports)
+	}
+	ldap := db.byName["LDAPSearchReq"]
+	if !inPortRanges(ldap.sslPorts, 636) || inPortRanges(ldap.sslPorts, 389) {
+		t.Errorf("LDAPSearchReq sslports = %v", ldap.sslPorts)
+	}
+}
+
+// TestServiceProbeMatchBanners 用内置探测库匹配各服务的典型响应
+func TestServiceProbeMatchBanners(t *testing.T) {
+	db := DefaultServiceProbeDB()
+	tests := []struct {
+		name     string
+		probe    string
+		resp     string
+		service  string
+		product  string
+		version  string
+		wantSoft bool
+	}{
+		{"openssh", "NULL", "SSH-2.0-OpenSSH_8.9p1 Ubuntu-3ubuntu0.6\r\n", "ssh", "OpenSSH", "8.9p1", false},
+		{"dropbear", "NULL", "SSH-2.0-dropbear_2022.83\r\n", "ssh", "Dropbear sshd", "2022.83", false},
+		{"ssh other", "NULL", "SSH-2.0-Go\r\n", "ssh", "Go", "", false},
+		{"mysql", "NULL", "J\x00\x00\x00\x0a8.0.36-0ubuntu0.22.04.1\x00\x08\x00\x00\x00", "mysql", "MySQL", "8.0.36-0ubuntu0.22.04.1", false},
+		{"mariadb", "NULL", "Y\x00\x00\x00\x0a5.5.5-10.11.6-MariaDB-0+deb12u1\x00\x1f\x00\x00\x00", "mysql", "MariaDB", "5.5.5-10.11.6-MariaDB-0+deb12u1", false},
+		{"mysql host denied", "NULL", "E\x00\x00\x00\xffj\x04Host '10.0.0.5' is not allowed to connect to this MySQL server", "mysql", "MySQL", "", false},
+		{"redis info", "redis-server", "$3860\r\n# Server\r\nredis_version:7.2.4\r\nredis_git_sha1:00000000\r\n", "redis", "Redis key-value store", "7.2.4", false},
+		{"redis auth", "redis-server", "-NOAUTH Authentication required.\r\n", "redis", "Redis key-value store", "", false},
+		{"redis via GetRequest", "GetRequest", "-ERR wrong number of arguments for 'get' command\r\n", "redis", "Redis key-value store", "", false},
+		{"rdp", "TerminalServerCookie", "\x03\x00\x00\x13\x0e\xd0\x00\x00\x12\x34\x00\x02\x1f\x08\x00\x02\x00\x00\x00", "ms-wbt-server", "Microsoft Terminal Services", "", false},
+		{"xrdp", "TerminalServerCookie", "\x03\x00\x00\x13\x0e\xd0\x00\x00\x00\x00\x00\x02\x01\x08\x00\x01\x00\x00\x00", "ms-wbt-server", "xrdp", "", false},
+		{"smb2", "SMBProgNeg", "\x00\x00\x00\xf8\xfeSMB@\x00\x00\x00\x00\x00", "microsoft-ds", "Microsoft Windows or Samba smbd", "", false},
+		{"samba smb1", "SMBProgNeg", "\x00\x00\x00\x55\xffSMBr\x00\x00\x00\x00\x88\x01\xc0W\x00O\x00R\x00K\x00\x00\x00S\x00a\x00m\x00b\x00a\x00", "microsoft-ds", "Samba smbd", "", false},
+		{"ldap ad", "LDAPSearchReq", "0\x84\x00\x00\x00\x10\x02\x01\x01d\x84\x00\x00\x00\x20\x04\x00\x30\x18\x04\x16rootDomainNamingContext", "ldap", "Microsoft Windows Active Directory LDAP", "", false},
+		{"openldap", "LDAPSearchReq", "0\x1c\x02\x01\x01d\x17\x04\x00\x30\x13\x04\x0bobjectClass1\x04\x04\x0fOpenLDAProotDSE", "ldap", "OpenLDAP", "", false},
+		{"postgresql auth", "PostgreSQLStartup", "R\x00\x00\x00\x0c\x00\x00\x00\x05\x12\x34\x56\x78", "postgresql", "PostgreSQL DB", "", false},
+		{"postgresql denied", "PostgreSQLStartup", "E\x00\x00\x00\x5cSFATAL\x00VFATAL\x00C28000\x00Mno pg_hba.conf entry\x00", "postgresql", "PostgreSQL DB", "", false},
+		{"mongodb", "mongodb", "\xd1\x00\x00\x00\x2a\x00\x00\x00\x01\x00\x00\x00\x01\x00\x00\x00\x08\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x00\x01\x00\x00\x00\xad\x00\x00\x00\x08ismaster\x00\x01", "mongodb", "MongoDB", "", false},
+		{"nginx", "GetRequest", "HTTP/1.1 200 OK\r\nServer: nginx/1.24.0\r\nContent-Length: 0\r\n\r\n", "http", "nginx", "1.24.0", false},
+		{"http softmatch", "GetRequest", "HTTP/1.1 404 Not Found\r\n\r\n", "http", "", "", true},
+		{"ftp softmatch", "NULL", "220 welcome to the ftp service\r\n", "ftp", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.match(db.byName[tt.probe], []byte(tt.resp), nil)
+			if got == nil {
+				t.Fatalf("match(%s) = nil", tt.probe)
+			}
+			if got.service != tt.service || got.product != tt.product || got.version != tt.version || got.soft != tt.wantSoft {
+				t.Errorf("match(%s) = %s/%q/%q soft=%v, want %s/%q/%q soft=%v",
+					tt.probe, got.service, got.product, got.version, got.soft, tt.service, tt.product, tt.version, tt.wantSoft)
+			}
+		})
+	}
+
+	// 无法识别的响应
+	if got := db.match(db.byName["NULL"], []byte("\x00\x01garbage"), nil); got != nil {
+		t.Errorf("match(garbage) = %+v, want nil", got)
+	}
+}
+
+func TestUnescapeProbeString(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []byte
+	}{
+		{`GET / HTTP/1.0\r\n\r\n`, []byte("GET / HTTP/1.0\r\n\r\n")},
+		{`\0\0\x14\0\x03`, []byte{0, 0, 0x14, 0, 3}},
+		{`\xff\xFESMB`, []byte("\xff\xfeSMB")},
+		{`\a\b\f\t\v`, []byte("\a\b\f\t\v")},
+		{`\\\|a`, []byte(`\|a`)},
+		{``, nil},
+	}
+	for _, tt := range tests {
+		got, err := unescapeProbeString(tt.in)
+		if err != nil || !bytes.Equal(got, tt.want) {
+			t.Errorf("unescapeProbeString(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	for _, in := range []string{`abc\`, `\x4`, `\xzz`} {
+		if _, err := unescapeProbeString(in); err == nil {
+			t.Errorf("unescapeProbeString(%q) expected error", in)
+		}
+	}
+}
+
+func TestExpandVersionTemplate(t *testing.T) {
+	groups := []string{"all", "7_4_1", "a\x01b\x7fc", latin1String([]byte{0x01, 0x02}), "123456789"}
+	tests := []struct {
+		tpl  string
+		want string
+	}{
+		{"", ""},
+		{"OpenSSH", "OpenSSH"},
+		{"$1", "7_4_1"},
+		{"v$1 ($9)", "v7_4_1 ()"},
+		{"$P(2)", "abc"},
+		{`$SUBST(1,"_",".")`, "7.4.1"},
+		{`$I(3,">")`, "258"},
+		{`$I(3,"<")`, "513"},
+		{`build $I(4,">")`, "build"},
+	}
+	for _, tt := range tests {
+		if got := expandVersionTemplate(tt.tpl, groups); got != tt.want {
+			t.Errorf("expandVersionTemplate(%q) = %q, want %q", tt.tpl, got, tt.want)
+		}
+	}
+}
+
+const testServiceProbes = `
+# 测试探测库
+Probe TCP NULL q||
+softmatch ssh m|^SSH-|
+match ssh m|^SSH-2\.0-Null\r\n| p/Null sshd/
+match dup m|^(a)\1| p/backreference/
+match look m|^(?=x)y| p/lookahead/
+
+Probe UDP DNSStatusRequest q|\0\0\x10\0\0\0\0\0\0\0\0\0|
+ports 53
+match dns m|^\0\0\x90|
+
+Probe TCP Alpha q|alpha\r\n|
+rarity 3
+ports 8000-8010
+fallback Beta
+match alpha m|^ALPHA ([\d.]+)| p/Alpha/ v/$1/
+match ssh m|^SSH-2\.0-Alpha\r\n| p/Alpha sshd/
+
+Probe TCP Beta q|beta|
+rarity 5
+match beta m|^BETA|
+match alpha m|^ALPHA| p/Alpha via beta/
+
+Probe TCP Rare q|rare|
+rarity 9
+match rare m|^RARE|
+`
+
+// TestParseServiceProbes 测试自定义探测库的解析、RE2 不兼容规则跳过和匹配优先级
+func TestParseServiceProbes(t *testing.T) {
+	db, err := ParseServiceProbes(strings.NewReader(testServiceProbes))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db.Skipped != 2 {
+		t.Errorf("Skipped = %d, want 2", db.Skipped)
+	}
+	if _, ok := db.byName["DNSStatusRequest"]; ok {
+		t.Error("UDP probe should be ignored")
+	}
+	if n := len(db.byName["NULL"].matches); n != 2 {
+		t.Errorf("NULL matches = %d, want 2", n)
+	}
+
+	alpha := db.byName["Alpha"]
+	tests := []struct {
+		name    string
+		probe   *serviceProbe
+		resp    string
+		soft    *serviceResult
+		service string
+		product string
+	}{
+		// 探测自身的规则优先于 fallback 探测
+		{"own rule first", alpha, "ALPHA 1.2", nil, "alpha", "Alpha"},
+		{"fallback probe", alpha, "BETA", nil, "beta", ""},
+		// 所有探测都会尝试 NULL 探测的规则，同一探测内先匹配的规则生效
+		{"null rules", db.byName["Beta"], "SSH-2.0-Null\r\n", nil, "ssh", ""},
+		{"null hard match after soft", db.byName["Beta"], "SSH-2.0-Null\r\n", &serviceResult{service: "ssh", soft: true}, "ssh", "Null sshd"},
+		// 已有 softmatch 时只接受同一服务的 match
+		{"soft keeps service", alpha, "SSH-2.0-Alpha\r\n", &serviceResult{service: "ssh", soft: true}, "ssh", "Alpha sshd"},
+		{"soft rejects other service", alpha, "ALPHA 1.2", &serviceResult{service: "ssh", soft: true}, "", ""},
+		{"soft ignores softmatch", alpha, "SSH-2.0-Other\r\n", &serviceResult{service: "ssh", soft: true}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.match(tt.probe, []byte(tt.resp), tt.soft)
+			if tt.service == "" {
+				if got != nil {
+					t.Errorf("match() = %+v, want nil", got)
+				}
+				return
+			}
+			if got == nil || got.service != tt.service || got.product != tt.product {
+				t.Errorf("match() = %+v, want %s/%q", got, tt.service, tt.product)
+			}
+		})
+	}
+
+	// NULL 探测最先发送，其次是端口匹配的探测，最后是 rarity 不超过强度的其他探测
+	var names []string
+	for _, p := range db.probesFor(8001, false, 7) {
+		names = append(names, p.name)
+	}
+	if got := strings.Join(names, ","); got != "NULL,Alpha,Beta" {
+		t.Errorf("probesFor(8001) = %s", got)
+	}
+	names = names[:0]
+	for _, p := range db.probesFor(22, false, 4) {
+		names = append(names, p.name)
+	}
+	if got := strings.Join(names, ","); got != "NULL,Alpha" {
+		t.Errorf("probesFor(22, intensity 4) = %s", got)
+	}
+
+	for _, bad := range []string{
+		"Probe TCP Broken q|unterminated",
+		"Probe TCP Bad q|\\x4|",
+		"Probe TCP NULL q||\nmatch ssh m|^SSH",
+	} {
+		if _, err := ParseServiceProbes(strings.NewReader(bad)); err == nil {
+			t.Errorf("ParseServiceProbes(%q) expected error", bad)
+		}
+	}
+}
+
+// TestServiceProbeIdentify 对本地模拟服务执行完整的识别流程
+func TestServiceProbeIdentify(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			conn.Write([]byte("SSH-2.0-OpenSSH_9.6p1 Debian-3\r\n"))
+			conn.Close()
+		}
+	}()
+
+	port := ln.Addr().(*net.TCPAddr).Port
+	asset := DefaultServiceProbeDB().Identify(context.Background(), "127.0.0.1", port, &ServiceProbeOptions{Timeout: 2, Intensity: 7})
+	if asset == nil {
+		t.Fatal("Identify() = nil")
+	}
+	if asset.Service != "ssh" || asset.Server != "OpenSSH 9.6p1" || asset.Banner != "SSH-2.0-OpenSSH_9.6p1 Debian-3" {
+		t.Errorf("Identify() = service %q, server %q, banner %q", asset.Service, asset.Server, asset.Banner)
+	}
+
+	ln.Close()
+	if asset := DefaultServiceProbeDB().Identify(context.Background(), "127.0.0.1", port, &ServiceProbeOptions{Timeout: 1, Intensity: 7}); asset != nil {
+		t.Errorf("Identify(closed port) = %+v, want nil", asset)
+	}
+}
@@ -21,6 +21,13 @@ type SubfinderScanner struct {
 	BaseScanner
 }
 
+func init() {
+	Register("subfinder", Registration{
+		New:     func() Scanner { return NewSubfinderScanner() },
+		Options: func() interface{} { return defaultSubfinderOptions() },
+	})
+}
+
 // NewSubfinderScanner 创建Subfinder扫描器
 func NewSubfinderScanner() *SubfinderScanner {
 	return &SubfinderScanner{
@@ -44,6 +51,19 @@ type SubfinderOptions struct {
 	Concurrent         int                 `json:"concurrent"`         // DNS解析并发数
 }
 
+// defaultSubfinderOptions 默认扫描选项
+func defaultSubfinderOptions() *SubfinderOptions {
+	return &SubfinderOptions{
+		Timeout:            30,
+		MaxEnumerationTime: 10,
+		Threads:            10,
+		RateLimit:          0,
+		RemoveWildcard:     true,
+		ResolveDNS:         true,
+		Concurrent:         50,
+	}
+}
+
 // Scan 执行Subfinder子域名扫描
 func (s *SubfinderScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
 	result := &ScanResult{
@@ -53,15 +73,7 @@ func (s *SubfinderScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanR
 	}
 
 	// 解析选项
-	opts := &SubfinderOptions{
-		Timeout:            30,
-		MaxEnumerationTime: 10,
-		Threads:            10,
-		RateLimit:          0,
-		RemoveWildcard:     true,
-		ResolveDNS:         true,
-		Concurrent:         50,
-	}
+	opts := defaultSubfinderOptions()
 	if config.Options != nil {
 		if o, ok := config.Options.(*SubfinderOptions); ok {
 			opts = o
@@ -0,0 +1,526 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TLS 证书和配置问题
+const (
+	TLSFindingExpired          = "expired"
+	TLSFindingSelfSigned       = "self-signed"
+	TLSFindingWeakKey          = "weak-key"
+	TLSFindingHostnameMismatch = "hostname-mismatch"
+	TLSFindingDeprecatedTLS    = "deprecated-tls"
+)
+
+// TLSInfo TLS 采集结果
+type TLSInfo struct {
+	Versions     []string  `json:"versions"`           // 支持的协议版本，如 TLS1.2
+	CipherSuites []string  `json:"cipherSuites"`       // 各版本协商的密码套件，格式 版本/套件
+	ALPN         string    `json:"alpn,omitempty"`     // 协商的应用层协议
+	JA3S         string    `json:"ja3s,omitempty"`     // ServerHello 的 JA3S 指纹
+	JARM         string    `json:"jarm,omitempty"`     // JARM 风格的多次握手指纹
+	Chain        []TLSCert `json:"chain"`              // 证书链，第一个为站点证书
+	Findings     []string  `json:"findings,omitempty"` // 证书和配置问题
+}
+
+// TLSCert 证书信息
+type TLSCert struct {
+	Subject            string    `json:"subject"`
+	Issuer             string    `json:"issuer"`
+	SerialNumber       string    `json:"serialNumber"`
+	NotBefore          time.Time `json:"notBefore"`
+	NotAfter           time.Time `json:"notAfter"`
+	SANs               []string  `json:"sans,omitempty"` // DNS 和 IP 备用名称
+	KeyType            string    `json:"keyType"`        // RSA, ECDSA, Ed25519
+	KeyBits            int       `json:"keyBits"`
+	SignatureAlgorithm string    `json:"signatureAlgorithm"`
+	SHA256             string    `json:"sha256"`
+	IsCA               bool      `json:"isCa"`
+	SelfSigned         bool      `json:"selfSigned"`
+}
+
+// TLSCollectOptions TLS 采集选项
+type TLSCollectOptions struct {
+	Timeout int  `json:"timeout"` // 单次握手超时(秒)，默认5秒
+	Jarm    bool `json:"jarm"`    // 计算 JARM 风格指纹，需要额外的握手
+}
+
+var tlsVersionNames = map[uint16]string{
+	tls.VersionTLS10: "TLS1.0",
+	tls.VersionTLS11: "TLS1.1",
+	tls.VersionTLS12: "TLS1.2",
+	tls.VersionTLS13: "TLS1.3",
+}
+
+// allCipherSuites 包括不安全套件，用于探测老旧服务器
+func allCipherSuites() []uint16 {
+	var ids []uint16
+	for _, s := range tls.CipherSuites() {
+		ids = append(ids, s.ID)
+	}
+	for _, s := range tls.InsecureCipherSuites() {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
+
+// CollectTLS 采集 host:port 的 TLS 信息，端口不支持 TLS 时返回 nil
+func CollectTLS(ctx context.Context, host string, port int, opts *TLSCollectOptions) *TLSInfo {
+	if opts == nil {
+		opts = &TLSCollectOptions{}
+	}
+	timeout := time.Duration(opts.Timeout) * time.Second
+	if timeout <= 0 {
+		timeout = 5 * time.Second
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(port))
+	serverName := ""
+	if net.ParseIP(host) == nil {
+		serverName = host
+	}
+
+	state, err := tlsHandshake(ctx, addr, serverName, tls.VersionTLS10, tls.VersionTLS13, timeout)
+	if err != nil {
+		return nil
+	}
+	info := &TLSInfo{ALPN: state.NegotiatedProtocol}
+	for _, cert := range state.PeerCertificates {
+		info.Chain = append(info.Chain, newTLSCert(cert))
+	}
+
+	// 逐个版本握手，记录支持的版本和服务端选择的套件
+	for _, v := range []uint16{tls.VersionTLS13, tls.VersionTLS12, tls.VersionTLS11, tls.VersionTLS10} {
+		if ctx.Err() != nil {
+			break
+		}
+		vs := &state
+		if v != state.Version {
+			s, err := tlsHandshake(ctx, addr, serverName, v, v, timeout)
+			if err != nil {
+				continue
+			}
+			vs = &s
+		}
+		info.Versions = append(info.Versions, tlsVersionNames[v])
+		info.CipherSuites = append(info.CipherSuites, tlsVersionNames[v]+"/"+tls.CipherSuiteName(vs.CipherSuite))
+	}
+
+	if hello, err := rawServerHello(ctx, addr, serverName, tls.VersionTLS13, allCipherSuites(), []string{"h2", "http/1.1"}, timeout); err == nil {
+		info.JA3S = hello.ja3s()
+	}
+	if opts.Jarm {
+		info.JARM = jarmFingerprint(ctx, addr, serverName, timeout)
+	}
+
+	info.Findings = tlsFindings(info, state.PeerCertificates, serverName)
+	return info
+}
+
+// tlsHandshake 使用指定版本范围握手，不校验证书
+func tlsHandshake(ctx context.Context, addr, serverName string, minVersion, maxVersion uint16, timeout time.Duration) (tls.ConnectionState, error) {
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config: &tls.Config{
+			ServerName:         serverName,
+			InsecureSkipVerify: true,
+			MinVersion:         minVersion,
+			MaxVersion:         maxVersion,
+			CipherSuites:       allCipherSuites(),
+			NextProtos:         []string{"h2", "http/1.1"},
+		},
+	}
+	hsCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	conn, err := dialer.DialContext(hsCtx, "tcp", addr)
+	if err != nil {
+		return tls.ConnectionState{}, err
+	}
+	defer conn.Close()
+	return conn.(*tls.Conn).ConnectionState(), nil
+}
+
+func newTLSCert(cert *x509.Certificate) TLSCert {
+	c := TLSCert{
+		Subject:            cert.Subject.String(),
+		Issuer:             cert.Issuer.String(),
+		SerialNumber:       cert.SerialNumber.Text(16),
+		NotBefore:          cert.NotBefore,
+		NotAfter:           cert.NotAfter,
+		SignatureAlgorithm: cert.SignatureAlgorithm.String(),
+		IsCA:               cert.IsCA,
+	}
+	c.SANs = append(c.SANs, cert.DNSNames...)
+	for _, ip := range cert.IPAddresses {
+		c.SANs = append(c.SANs, ip.String())
+	}
+	switch key := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		c.KeyType, c.KeyBits = "RSA", key.N.BitLen()
+	case *ecdsa.PublicKey:
+		c.KeyType, c.KeyBits = "ECDSA", key.Curve.Params().BitSize
+	case ed25519.PublicKey:
+		c.KeyType, c.KeyBits = "Ed25519", 256
+	default:
+		c.KeyType = cert.PublicKeyAlgorithm.String()
+	}
+	sum := sha256.Sum256(cert.Raw)
+	c.SHA256 = hex.EncodeToString(sum[:])
+	// 不使用 CheckSignatureFrom，它要求签发者为 CA，会漏掉设备常见的非 CA 自签名证书
+	c.SelfSigned = bytes.Equal(cert.RawSubject, cert.RawIssuer) &&
+		cert.CheckSignature(cert.SignatureAlgorithm, cert.RawTBSCertificate, cert.Signature) == nil
+	return c
+}
+
+// tlsFindings 根据站点证书和支持的版本得出问题列表
+func tlsFindings(info *TLSInfo, certs []*x509.Certificate, serverName string) []string {
+	var findings []string
+	if len(certs) > 0 {
+		leaf := certs[0]
+		lc := info.Chain[0]
+		if time.Now().After(leaf.NotAfter) {
+			findings = append(findings, TLSFindingExpired)
+		}
+		if lc.SelfSigned {
+			findings = append(findings, TLSFindingSelfSigned)
+		}
+		if (lc.KeyType == "RSA" && lc.KeyBits < 2048) || (lc.KeyType == "ECDSA" && lc.KeyBits < 256) {
+			findings = append(findings, TLSFindingWeakKey)
+		}
+		// IP 访问时证书通常不包含 IP，只检查域名
+		if serverName != "" && leaf.VerifyHostname(serverName) != nil {
+			findings = append(findings, TLSFindingHostnameMismatch)
+		}
+	}
+	for _, v := range info.Versions {
+		if v == "TLS1.0" || v == "TLS1.1" {
+			findings = append(findings, TLSFindingDeprecatedTLS)
+			break
+		}
+	}
+	return findings
+}
+
+// CertSummary 生成证书摘要文本，用于 Asset.Cert
+func (t *TLSInfo) CertSummary() string {
+	if t == nil || len(t.Chain) == 0 {
+		return ""
+	}
+	c := t.Chain[0]
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Subject: %s\n", c.Subject)
+	fmt.Fprintf(&sb, "Issuer: %s\n", c.Issuer)
+	if len(c.SANs) > 0 {
+		fmt.Fprintf(&sb, "SAN: %s\n", strings.Join(c.SANs, ", "))
+	}
+	fmt.Fprintf(&sb, "Not Before: %s\n", c.NotBefore.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Not After: %s\n", c.NotAfter.Format(time.RFC3339))
+	fmt.Fprintf(&sb, "Key: %s %d\n", c.KeyType, c.KeyBits)
+	fmt.Fprintf(&sb, "SHA256: %s\n", c.SHA256)
+	if len(t.Versions) > 0 {
+		fmt.Fprintf(&sb, "Versions: %s\n", strings.Join(t.Versions, ", "))
+	}
+	return strings.TrimSpace(sb.String())
+}
+
+// SANDomains 返回证书链站点证书中的域名，通配符域名去掉 "*." 前缀
+func (t *TLSInfo) SANDomains() []string {
+	if t == nil || len(t.Chain) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var domains []string
+	for _, san := range t.Chain[0].SANs {
+		d := strings.ToLower(strings.TrimPrefix(san, "*."))
+		if d == "" || net.ParseIP(d) != nil || !strings.Contains(d, ".") || seen[d] {
+			continue
+		}
+		seen[d] = true
+		domains = append(domains, d)
+	}
+	return domains
+}
+
+var tlsFindingSeverity = map[string]string{
+	TLSFindingExpired:          "medium",
+	TLSFindingSelfSigned:       "low",
+	TLSFindingWeakKey:          "medium",
+	TLSFindingHostnameMismatch: "low",
+	TLSFindingDeprecatedTLS:    "low",
+}
+
+// TLSFindingVuls 将资产的 TLS 问题转换为漏洞记录
+func TLSFindingVuls(asset *Asset) []*Vulnerability {
+	if asset.TLS == nil {
+		return nil
+	}
+	var vuls []*Vulnerability
+	for _, f := range asset.TLS.Findings {
+		result := f
+		if len(asset.TLS.Chain) > 0 {
+			leaf := asset.TLS.Chain[0]
+			switch f {
+			case TLSFindingExpired:
+				result = "certificate expired at " + leaf.NotAfter.Format(time.RFC3339)
+			case TLSFindingWeakKey:
+				result = fmt.Sprintf("%s %d bit key", leaf.KeyType, leaf.KeyBits)
+			case TLSFindingHostnameMismatch:
+				result = "certificate not valid for " + asset.Host + ", SAN: " + strings.Join(leaf.SANs, ", ")
+			case TLSFindingSelfSigned:
+				result = "self-signed certificate: " + leaf.Subject
+			}
+		}
+		if f == TLSFindingDeprecatedTLS {
+			result = "supported versions: " + strings.Join(asset.TLS.Versions, ", ")
+		}
+		vuls = append(vuls, &Vulnerability{
+			Authority: asset.Authority,
+			Host:      asset.Host,
+			Port:      asset.Port,
+			Url:       asset.Authority,
+			PocFile:   "tls-" + f,
+			Source:    "tlsscan",
+			Severity:  tlsFindingSeverity[f],
+			Result:    result,
+		})
+	}
+	return vuls
+}
+
+// ==================== 原始握手 ====================
+
+// serverHello 解析后的 ServerHello
+type serverHello struct {
+	legacy     uint16 // ServerHello 中的版本字段
+	version    uint16 // 实际协商的版本
+	cipher     uint16
+	extensions []uint16
+	alpn       string
+}
+
+// ja3s 计算 JA3S：md5("版本,套件,扩展列表")
+func (h *serverHello) ja3s() string {
+	exts := make([]string, len(h.extensions))
+	for i, e := range h.extensions {
+		exts[i] = strconv.Itoa(int(e))
+	}
+	s := fmt.Sprintf("%d,%d,%s", h.legacy, h.cipher, strings.Join(exts, "-"))
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+// rawServerHello 发送自构造的 ClientHello 并解析 ServerHello
+func rawServerHello(ctx context.Context, addr, serverName string, maxVersion uint16, ciphers []uint16, alpn []string, timeout time.Duration) (*serverHello, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(timeout))
+	if _, err := conn.Write(buildClientHello(serverName, maxVersion, ciphers, alpn)); err != nil {
+		return nil, err
+	}
+	return readServerHello(conn)
+}
+
+// buildClientHello 构造 ClientHello 记录，maxVersion 为 TLS1.3 时携带 supported_versions 和 key_share
+func buildClientHello(serverName string, maxVersion uint16, ciphers []uint16, alpn []string) []byte {
+	var exts bytes.Buffer
+	addExt := func(typ uint16, data []byte) {
+		binary.Write(&exts, binary.BigEndian, typ)
+		binary.Write(&exts, binary.BigEndian, uint16(len(data)))
+		exts.Write(data)
+	}
+	if serverName != "" {
+		name := []byte(serverName)
+		sni := make([]byte, 0, len(name)+5)
+		sni = binary.BigEndian.AppendUint16(sni, uint16(len(name)+3))
+		sni = append(sni, 0)
+		sni = binary.BigEndian.AppendUint16(sni, uint16(len(name)))
+		addExt(0, append(sni, name...))
+	}
+	addExt(10, []byte{0, 6, 0x00, 0x1d, 0x00, 0x17, 0x00, 0x18}) // supported_groups: x25519, P-256, P-384
+	addExt(11, []byte{1, 0})                                     // ec_point_formats
+	addExt(13, []byte{0, 16, 0x04, 0x03, 0x08, 0x04, 0x04, 0x01, 0x05, 0x03,
+		0x08, 0x05, 0x05, 0x01, 0x08, 0x06, 0x06, 0x01}) // signature_algorithms
+	addExt(23, nil)           // extended_master_secret
+	addExt(0xff01, []byte{0}) // renegotiation_info
+	if len(alpn) > 0 {
+		var list []byte
+		for _, p := range alpn {
+			list = append(list, byte(len(p)))
+			list = append(list, p...)
+		}
+		addExt(16, append(binary.BigEndian.AppendUint16(nil, uint16(len(list))), list...))
+	}
+	if maxVersion >= tls.VersionTLS13 {
+		addExt(43, []byte{4, 0x03, 0x04, 0x03, 0x03}) // supported_versions
+		addExt(45, []byte{1, 1})                      // psk_key_exchange_modes
+		key := make([]byte, 32)
+		rand.Read(key)
+		share := []byte{0, 36, 0x00, 0x1d, 0, 32}
+		addExt(51, append(share, key...)) // key_share: x25519
+	}
+
+	clientVersion := maxVersion
+	if clientVersion > tls.VersionTLS12 {
+		clientVersion = tls.VersionTLS12
+	}
+	var body bytes.Buffer
+	binary.Write(&body, binary.BigEndian, clientVersion)
+	random := make([]byte, 32)
+	rand.Read(random)
+	body.Write(random)
+	sessionId := make([]byte, 32)
+	rand.Read(sessionId)
+	body.WriteByte(32)
+	body.Write(sessionId)
+	binary.Write(&body, binary.BigEndian, uint16(len(ciphers)*2))
+	for _, c := range ciphers {
+		binary.Write(&body, binary.BigEndian, c)
+	}
+	body.Write([]byte{1, 0}) // compression: null
+	binary.Write(&body, binary.BigEndian, uint16(exts.Len()))
+	body.Write(exts.Bytes())
+
+	hs := []byte{1, byte(body.Len() >> 16), byte(body.Len() >> 8), byte(body.Len())}
+	hs = append(hs, body.Bytes()...)
+	record := []byte{0x16, 0x03, 0x01, byte(len(hs) >> 8), byte(len(hs))}
+	return append(record, hs...)
+}
+
+// readServerHello 读取握手记录并解析第一个 ServerHello
+func readServerHello(r io.Reader) (*serverHello, error) {
+	var hs []byte
+	msgLen := func() int { return 4 + (int(hs[1])<<16 | int(hs[2])<<8 | int(hs[3])) }
+	for len(hs) < 4 || len(hs) < msgLen() {
+		header := make([]byte, 5)
+		if _, err := io.ReadFull(r, header); err != nil {
+			return nil, err
+		}
+		if header[0] != 0x16 {
+			return nil, fmt.Errorf("unexpected record type %d", header[0])
+		}
+		payload := make([]byte, binary.BigEndian.Uint16(header[3:5]))
+		if _, err := io.ReadFull(r, payload); err != nil {
+			return nil, err
+		}
+		hs = append(hs, payload...)
+		if len(hs) > 64*1024 {
+			return nil, fmt.Errorf("handshake too large")
+		}
+	}
+	if hs[0] != 2 {
+		return nil, fmt.Errorf("unexpected handshake type %d", hs[0])
+	}
+	b := hs[4:msgLen()]
+	if len(b) < 35 {
+		return nil, fmt.Errorf("short server hello")
+	}
+	h := &serverHello{legacy: binary.BigEndian.Uint16(b)}
+	h.version = h.legacy
+	b = b[34:]
+	sidLen := int(b[0])
+	if len(b) < 1+sidLen+3 {
+		return nil, fmt.Errorf("short server hello")
+	}
+	b = b[1+sidLen:]
+	h.cipher = binary.BigEndian.Uint16(b)
+	b = b[3:]
+	if len(b) < 2 {
+		return h, nil
+	}
+	extLen := int(binary.BigEndian.Uint16(b))
+	b = b[2:]
+	if extLen > len(b) {
+		return nil, fmt.Errorf("short server hello extensions")
+	}
+	b = b[:extLen]
+	for len(b) >= 4 {
+		typ := binary.BigEndian.Uint16(b)
+		n := int(binary.BigEndian.Uint16(b[2:]))
+		if len(b) < 4+n {
+			break
+		}
+		data := b[4 : 4+n]
+		h.extensions = append(h.extensions, typ)
+		switch typ {
+		case 43: // supported_versions
+			if n == 2 {
+				h.version = binary.BigEndian.Uint16(data)
+			}
+		case 16: // alpn
+			if n > 3 {
+				h.alpn = string(data[3:])
+			}
+		}
+		b = b[4+n:]
+	}
+	return h, nil
+}
+
+// jarmFingerprint 计算 JARM 风格指纹（与官方 JARM 取值不同）：
+// 以不同版本、套件顺序和 ALPN 发送多次 ClientHello，拼接每次选择的套件和版本，
+// 再附加所有扩展列表的 sha256 前32位
+func jarmFingerprint(ctx context.Context, addr, serverName string, timeout time.Duration) string {
+	forward := allCipherSuites()
+	forward = append([]uint16{0x1301, 0x1302, 0x1303}, forward...)
+	reverse := make([]uint16, len(forward))
+	for i, c := range forward {
+		reverse[len(forward)-1-i] = c
+	}
+	probes := []struct {
+		version uint16
+		ciphers []uint16
+		alpn    []string
+	}{
+		{tls.VersionTLS12, forward, []string{"http/1.1"}},
+		{tls.VersionTLS12, reverse, []string{"http/1.1"}},
+		{tls.VersionTLS12, forward, nil},
+		{tls.VersionTLS11, forward, nil},
+		{tls.VersionTLS13, forward, []string{"h2", "http/1.1"}},
+		{tls.VersionTLS13, reverse, []string{"h2", "http/1.1"}},
+		{tls.VersionTLS13, forward, nil},
+	}
+	var sb strings.Builder
+	var extSummary []string
+	responded := false
+	for _, p := range probes {
+		h, err := rawServerHello(ctx, addr, serverName, p.version, p.ciphers, p.alpn, timeout)
+		if err != nil {
+			sb.WriteString("00000000")
+			extSummary = append(extSummary, "")
+			continue
+		}
+		responded = true
+		fmt.Fprintf(&sb, "%04x%04x", h.cipher, h.version)
+		exts := make([]string, len(h.extensions))
+		for i, e := range h.extensions {
+			exts[i] = strconv.Itoa(int(e))
+		}
+		extSummary = append(extSummary, h.alpn+"|"+strings.Join(exts, "-"))
+	}
+	if !responded {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join(extSummary, ",")))
+	return sb.String() + hex.EncodeToString(sum[:])[:32]
+}
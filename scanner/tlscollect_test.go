@@ -0,0 +1,343 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testCert 生成测试证书，parent 为空时自签名
+type testCert struct {
+	cn        string
+	dnsNames  []string
+	ips       []net.IP
+	notAfter  time.Time
+	key       crypto.Signer
+	parent    *x509.Certificate
+	parentKey crypto.Signer
+	isCA      bool
+}
+
+func (c testCert) generate(t *testing.T) *x509.Certificate {
+	t.Helper()
+	if c.notAfter.IsZero() {
+		c.notAfter = time.Now().Add(24 * time.Hour)
+	}
+	if c.key == nil {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c.key = key
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(0x1234),
+		Subject:               pkix.Name{CommonName: c.cn},
+		NotBefore:             c.notAfter.Add(-48 * time.Hour),
+		NotAfter:              c.notAfter,
+		DNSNames:              c.dnsNames,
+		IPAddresses:           c.ips,
+		IsCA:                  c.isCA,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+	}
+	parent, parentKey := tmpl, c.key
+	if c.parent != nil {
+		parent, parentKey = c.parent, c.parentKey
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, parent, c.key.Public(), parentKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// TestTLSFindings 测试证书问题：过期、自签名、弱密钥、域名不匹配和废弃协议版本
+func TestTLSFindings(t *testing.T) {
+	caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	ca := testCert{cn: "Test CA", key: caKey, isCA: true}.generate(t)
+	signed := func(c testCert) *x509.Certificate {
+		c.parent, c.parentKey = ca, caKey
+		return c.generate(t)
+	}
+	rsa1024, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p224, _ := ecdsa.GenerateKey(elliptic.P224(), rand.Reader)
+
+	tests := []struct {
+		name       string
+		cert       *x509.Certificate
+		serverName string
+		versions   []string
+		want       []string
+	}{
+		{"valid", signed(testCert{cn: "www.example.com", dnsNames: []string{"www.example.com"}}), "www.example.com", []string{"TLS1.3", "TLS1.2"}, nil},
+		{"wildcard", signed(testCert{cn: "example.com", dnsNames: []string{"*.example.com"}}), "api.example.com", nil, nil},
+		{"expired", signed(testCert{cn: "www.example.com", dnsNames: []string{"www.example.com"}, notAfter: time.Now().Add(-time.Hour)}), "www.example.com", nil, []string{TLSFindingExpired}},
+		{"self-signed", testCert{cn: "www.example.com", dnsNames: []string{"www.example.com"}}.generate(t), "www.example.com", nil, []string{TLSFindingSelfSigned}},
+		{"weak rsa", signed(testCert{cn: "www.example.com", dnsNames: []string{"www.example.com"}, key: rsa1024}), "www.example.com", nil, []string{TLSFindingWeakKey}},
+		{"weak ecdsa", signed(testCert{cn: "www.example.com", dnsNames: []string{"www.example.com"}, key: p224}), "www.example.com", nil, []string{TLSFindingWeakKey}},
+		{"hostname mismatch", signed(testCert{cn: "www.example.com", dnsNames: []string{"www.example.com"}}), "admin.example.org", nil, []string{TLSFindingHostnameMismatch}},
+		{"ip access skips hostname", signed(testCert{cn: "www.example.com", dnsNames: []string{"www.example.com"}}), "", nil, nil},
+		{"deprecated tls", signed(testCert{cn: "www.example.com", dnsNames: []string{"www.example.com"}}), "www.example.com", []string{"TLS1.2", "TLS1.1", "TLS1.0"}, []string{TLSFindingDeprecatedTLS}},
+		{
+			"all",
+			testCert{cn: "old", dnsNames: []string{"old.example.com"}, key: rsa1024, notAfter: time.Now().Add(-time.Hour)}.generate(t),
+			"www.example.com", []string{"TLS1.0"},
+			[]string{TLSFindingExpired, TLSFindingSelfSigned, TLSFindingWeakKey, TLSFindingHostnameMismatch, TLSFindingDeprecatedTLS},
+		},
+	}
+	for _, tt := range tests {
+		info := &TLSInfo{Versions: tt.versions, Chain: []TLSCert{newTLSCert(tt.cert)}}
+		if got := tlsFindings(info, []*x509.Certificate{tt.cert}, tt.serverName); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: tlsFindings() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if got := tlsFindings(&TLSInfo{Versions: []string{"TLS1.1"}}, nil, "www.example.com"); !reflect.DeepEqual(got, []string{TLSFindingDeprecatedTLS}) {
+		t.Errorf("no certificate: tlsFindings() = %v", got)
+	}
+}
+
+// TestNewTLSCert 测试证书信息提取
+func TestNewTLSCert(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert := testCert{cn: "www.example.com", dnsNames: []string{"www.example.com", "*.example.com"}, ips: []net.IP{net.ParseIP("10.0.0.1")}, key: rsaKey}.generate(t)
+	c := newTLSCert(cert)
+	if c.Subject != "CN=www.example.com" || c.Issuer != "CN=www.example.com" || c.SerialNumber != "1234" {
+		t.Errorf("subject/issuer/serial = %q/%q/%q", c.Subject, c.Issuer, c.SerialNumber)
+	}
+	if want := []string{"www.example.com", "*.example.com", "10.0.0.1"}; !reflect.DeepEqual(c.SANs, want) {
+		t.Errorf("SANs = %v, want %v", c.SANs, want)
+	}
+	if c.KeyType != "RSA" || c.KeyBits != 1024 || !c.SelfSigned || len(c.SHA256) != 64 {
+		t.Errorf("key = %s %d, selfSigned = %v, sha256 = %q", c.KeyType, c.KeyBits, c.SelfSigned, c.SHA256)
+	}
+}
+
+// TestTLSInfoSummary 测试证书摘要和SAN域名提取
+func TestTLSInfoSummary(t *testing.T) {
+	notBefore := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	info := &TLSInfo{
+		Versions: []string{"TLS1.3", "TLS1.2"},
+		Chain: []TLSCert{{
+			Subject:   "CN=www.example.com",
+			Issuer:    "CN=R3,O=Let's Encrypt",
+			SANs:      []string{"www.example.com", "*.Example.com", "example.com", "10.0.0.1", "::1", "localhost", "*.example.com"},
+			NotBefore: notBefore,
+			NotAfter:  notBefore.Add(90 * 24 * time.Hour),
+			KeyType:   "ECDSA",
+			KeyBits:   256,
+			SHA256:    "abcd",
+		}},
+	}
+	want := "Subject: CN=www.example.com\n" +
+		"Issuer: CN=R3,O=Let's Encrypt\n" +
+		"SAN: www.example.com, *.Example.com, example.com, 10.0.0.1, ::1, localhost, *.example.com\n" +
+		"Not Before: 2024-01-01T00:00:00Z\n" +
+		"Not After: 2024-03-31T00:00:00Z\n" +
+		"Key: ECDSA 256\n" +
+		"SHA256: abcd\n" +
+		"Versions: TLS1.3, TLS1.2"
+	if got := info.CertSummary(); got != want {
+		t.Errorf("CertSummary() = %q, want %q", got, want)
+	}
+	if got, want := info.SANDomains(), []string{"www.example.com", "example.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SANDomains() = %v, want %v", got, want)
+	}
+
+	for _, empty := range []*TLSInfo{nil, {Versions: []string{"TLS1.2"}}} {
+		if empty.CertSummary() != "" || empty.SANDomains() != nil {
+			t.Errorf("empty info %+v: summary = %q, domains = %v", empty, empty.CertSummary(), empty.SANDomains())
+		}
+	}
+}
+
+// helloRecord 按长度字段封装 ServerHello 握手消息和 TLS 记录
+func helloRecord(legacy, cipher uint16, sessionId []byte, exts ...[]byte) []byte {
+	body := []byte{byte(legacy >> 8), byte(legacy)}
+	body = append(body, bytes.Repeat([]byte{0xaa}, 32)...) // random
+	body = append(body, byte(len(sessionId)))
+	body = append(body, sessionId...)
+	body = append(body, byte(cipher>>8), byte(cipher), 0) // 套件, 压缩方法
+	if exts != nil {
+		all := bytes.Join(exts, nil)
+		body = append(body, byte(len(all)>>8), byte(len(all)))
+		body = append(body, all...)
+	}
+	return tlsRecord(append([]byte{2, 0, byte(len(body) >> 8), byte(len(body))}, body...))
+}
+
+func tlsRecord(payload []byte) []byte {
+	return append([]byte{0x16, 0x03, 0x03, byte(len(payload) >> 8), byte(len(payload))}, payload...)
+}
+
+// TestReadServerHello 测试 ServerHello 解析，包括分片、截断和格式错误的记录
+func TestReadServerHello(t *testing.T) {
+	sid := bytes.Repeat([]byte{0x11}, 32)
+	supportedVersions := []byte{0x00, 0x2b, 0x00, 0x02, 0x03, 0x04}
+	keyShare := append([]byte{0x00, 0x33, 0x00, 0x24, 0x00, 0x1d, 0x00, 0x20}, bytes.Repeat([]byte{0xbb}, 32)...)
+	renegotiation := []byte{0xff, 0x01, 0x00, 0x01, 0x00}
+	alpnH2 := []byte{0x00, 0x10, 0x00, 0x05, 0x00, 0x03, 0x02, 'h', '2'}
+
+	tls13 := helloRecord(tls.VersionTLS12, 0x1301, sid, supportedVersions, keyShare)
+	// 握手消息拆分到两个记录
+	fragmented := append(tlsRecord(tls13[5:20]), tlsRecord(tls13[20:])...)
+	// 单个记录声明 16MB 的握手消息
+	var oversized []byte
+	oversized = append(oversized, tlsRecord(append([]byte{2, 0xff, 0xff, 0xff}, make([]byte, 16000)...))...)
+	for i := 0; i < 5; i++ {
+		oversized = append(oversized, tlsRecord(make([]byte, 16000))...)
+	}
+
+	// 扩展总长度字段大于剩余数据
+	extOverflow := helloRecord(tls.VersionTLS12, 0x009c, nil, []byte{0x00, 0x17, 0x00, 0x00})
+	extOverflow[47], extOverflow[48] = 0x00, 0xff
+
+	tests := []struct {
+		name    string
+		data    []byte
+		want    *serverHello
+		wantErr bool
+	}{
+		{"tls1.3", tls13, &serverHello{legacy: 0x0303, version: 0x0304, cipher: 0x1301, extensions: []uint16{43, 51}}, false},
+		{"tls1.2 alpn", helloRecord(tls.VersionTLS12, 0xc02f, nil, renegotiation, alpnH2), &serverHello{legacy: 0x0303, version: 0x0303, cipher: 0xc02f, extensions: []uint16{0xff01, 16}, alpn: "h2"}, false},
+		{"no extensions", helloRecord(tls.VersionTLS10, 0x002f, sid), &serverHello{legacy: 0x0301, version: 0x0301, cipher: 0x002f}, false},
+		{"fragmented", fragmented, &serverHello{legacy: 0x0303, version: 0x0304, cipher: 0x1301, extensions: []uint16{43, 51}}, false},
+		{"trailing handshake messages", append(helloRecord(tls.VersionTLS12, 0x009c, nil), tlsRecord([]byte{0x0b, 0, 0, 0})...), &serverHello{legacy: 0x0303, version: 0x0303, cipher: 0x009c}, false},
+		{"truncated extension ignored", helloRecord(tls.VersionTLS12, 0x009c, nil, []byte{0x00, 0x10, 0x00, 0x09, 0x00}), &serverHello{legacy: 0x0303, version: 0x0303, cipher: 0x009c}, false},
+		{"empty", nil, nil, true},
+		{"truncated header", tls13[:3], nil, true},
+		{"truncated record", tls13[:len(tls13)-10], nil, true},
+		{"alert", []byte{0x15, 0x03, 0x03, 0x00, 0x02, 0x02, 0x28}, nil, true},
+		{"not server hello", tlsRecord([]byte{0x0b, 0, 0, 1, 0}), nil, true},
+		{"short body", tlsRecord([]byte{2, 0, 0, 4, 3, 3, 0, 0}), nil, true},
+		{"session id overflow", tlsRecord(append([]byte{2, 0, 0, 36, 3, 3}, append(make([]byte, 32), 0xff, 0, 0)...)), nil, true},
+		{"extensions overflow", extOverflow, nil, true},
+		{"oversized", oversized, nil, true},
+	}
+	for _, tt := range tests {
+		got, err := readServerHello(bytes.NewReader(tt.data))
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("%s: readServerHello() = %+v, want error", tt.name, got)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: readServerHello() error: %v", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: readServerHello() = %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestJA3S 测试 JA3S 计算使用 ServerHello 版本字段而不是协商版本
+func TestJA3S(t *testing.T) {
+	tests := []struct {
+		hello *serverHello
+		want  string
+	}{
+		{&serverHello{legacy: 0x0303, version: 0x0304, cipher: 0x1301, extensions: []uint16{43, 51}}, "f4febc55ea12b31ae17cfb7e614afda8"},
+		{&serverHello{legacy: 0x0303, version: 0x0303, cipher: 0xc02f, extensions: []uint16{0xff01, 16}}, "7bee5c1d424b7e5f943b06983bb11422"},
+		{&serverHello{legacy: 0x0301, cipher: 0x002f}, "18e962e106761869a61045bed0e81c2c"},
+	}
+	for _, tt := range tests {
+		if got := tt.hello.ja3s(); got != tt.want {
+			t.Errorf("ja3s(%+v) = %s, want %s", tt.hello, got, tt.want)
+		}
+	}
+}
+
+// TestBuildClientHello 测试构造的 ClientHello 长度字段一致，并按版本携带 TLS1.3 扩展
+func TestBuildClientHello(t *testing.T) {
+	for _, v := range []uint16{tls.VersionTLS11, tls.VersionTLS12, tls.VersionTLS13} {
+		hello := buildClientHello("www.example.com", v, []uint16{0x1301, 0xc02f}, []string{"h2"})
+		if hello[0] != 0x16 || int(hello[3])<<8|int(hello[4]) != len(hello)-5 {
+			t.Errorf("version %x: bad record header % x", v, hello[:5])
+			continue
+		}
+		hs := hello[5:]
+		if hs[0] != 1 || int(hs[1])<<16|int(hs[2])<<8|int(hs[3]) != len(hs)-4 {
+			t.Errorf("version %x: bad handshake header % x", v, hs[:4])
+		}
+		clientVersion := uint16(hs[4])<<8 | uint16(hs[5])
+		if want := min(v, tls.VersionTLS12); clientVersion != want {
+			t.Errorf("version %x: client version = %x, want %x", v, clientVersion, want)
+		}
+		if !bytes.Contains(hello, []byte("www.example.com")) {
+			t.Errorf("version %x: SNI missing", v)
+		}
+		hasKeyShare := bytes.Contains(hello, []byte{0x00, 0x33, 0x00, 0x26, 0x00, 0x24, 0x00, 0x1d})
+		if hasKeyShare != (v == tls.VersionTLS13) {
+			t.Errorf("version %x: key_share present = %v", v, hasKeyShare)
+		}
+	}
+}
+
+// TestCollectTLS 测试对本地 TLS 服务的完整采集
+func TestCollectTLS(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+	host, portStr, _ := net.SplitHostPort(srv.Listener.Addr().String())
+	port, _ := strconv.Atoi(portStr)
+
+	info := CollectTLS(context.Background(), host, port, &TLSCollectOptions{Timeout: 2})
+	if info == nil {
+		t.Fatal("CollectTLS() = nil")
+	}
+	if !slices.Contains(info.Versions, "TLS1.3") || !slices.Contains(info.Versions, "TLS1.2") {
+		t.Errorf("versions = %v", info.Versions)
+	}
+	if info.ALPN != "h2" {
+		t.Errorf("ALPN = %q, want h2", info.ALPN)
+	}
+	if len(info.JA3S) != 32 {
+		t.Errorf("JA3S = %q", info.JA3S)
+	}
+	if len(info.Chain) == 0 || !slices.Contains(info.Chain[0].SANs, "example.com") {
+		t.Fatalf("chain = %+v", info.Chain)
+	}
+	if !strings.Contains(info.CertSummary(), "SAN: ") {
+		t.Errorf("summary = %q", info.CertSummary())
+	}
+
+	// 非 TLS 端口
+	plain := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer plain.Close()
+	host, portStr, _ = net.SplitHostPort(plain.Listener.Addr().String())
+	port, _ = strconv.Atoi(portStr)
+	if info := CollectTLS(context.Background(), host, port, &TLSCollectOptions{Timeout: 1}); info != nil {
+		t.Errorf("CollectTLS() on plain HTTP = %+v, want nil", info)
+	}
+}
@@ -0,0 +1,449 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TransportUDP UDP 资产的传输层标记，TCP 资产为空
+const TransportUDP = "udp"
+
+// DefaultUDPPorts 默认 UDP 端口：DNS、TFTP、NTP、SNMP、IPMI、SSDP
+const DefaultUDPPorts = "53,69,123,161,623,1900"
+
+// UDPScanner UDP 端口扫描器。
+// UDP 无连接，只有收到探测响应的端口才视为开放，因此每个端口按协议发送专用载荷
+type UDPScanner struct {
+	BaseScanner
+}
+
+func init() {
+	Register("udpscan", Registration{
+		New:     func() Scanner { return NewUDPScanner() },
+		Options: func() interface{} { return &UDPScanOptions{} },
+	})
+}
+
+// NewUDPScanner 创建 UDP 扫描器
+func NewUDPScanner() *UDPScanner {
+	return &UDPScanner{
+		BaseScanner: BaseScanner{name: "udpscan"},
+	}
+}
+
+// UDPScanOptions UDP 扫描选项
+type UDPScanOptions struct {
+	Ports      string `json:"ports"`
+	Timeout    int    `json:"timeout"`    // 等待响应的超时时间(秒)，默认2秒
+	Retries    int    `json:"retries"`    // 无响应时的重发次数，默认1次
+	Concurrent int    `json:"concurrent"` // 并发数，默认50
+}
+
+// udpProbe UDP 协议探测
+type udpProbe struct {
+	service string
+	ports   []int
+	payload []byte
+	// parse 校验响应是否属于该协议，返回产品信息和 banner
+	parse func(resp []byte) (app, banner string, ok bool)
+}
+
+var udpProbes = []*udpProbe{
+	{service: "dns", ports: []int{53, 5353}, payload: dnsVersionQuery, parse: parseDNSResponse},
+	{service: "tftp", ports: []int{69}, payload: tftpReadRequest, parse: parseTFTPResponse},
+	{service: "ntp", ports: []int{123}, payload: ntpClientRequest, parse: parseNTPResponse},
+	{service: "snmp", ports: []int{161}, payload: snmpGetSysDescr, parse: parseSNMPResponse},
+	{service: "ipmi", ports: []int{623}, payload: ipmiGetChannelAuth, parse: parseIPMIResponse},
+	{service: "ssdp", ports: []int{1900}, payload: ssdpMSearch, parse: parseSSDPResponse},
+}
+
+// probesForPort 端口对应的探测，未知端口发送全部探测
+func probesForPort(port int) []*udpProbe {
+	for _, p := range udpProbes {
+		for _, pp := range p.ports {
+			if pp == port {
+				return []*udpProbe{p}
+			}
+		}
+	}
+	return udpProbes
+}
+
+// Scan 执行 UDP 端口扫描
+func (s *UDPScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
+	opts, ok := config.Options.(*UDPScanOptions)
+	if !ok || opts == nil {
+		opts = &UDPScanOptions{}
+	}
+	if opts.Ports == "" {
+		opts.Ports = DefaultUDPPorts
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 2
+	}
+	if opts.Retries < 0 {
+		opts.Retries = 0
+	} else if opts.Retries == 0 {
+		opts.Retries = 1
+	}
+	if opts.Concurrent <= 0 {
+		opts.Concurrent = 50
+	}
+
+	targets := parseTargets(config.Target)
+	if len(config.Targets) > 0 {
+		targets = append(targets, config.Targets...)
+	}
+	ports := parsePorts(opts.Ports)
+
+	logf := func(level, format string, args ...interface{}) {
+		if config.TaskLogger != nil {
+			config.TaskLogger(level, format, args...)
+		}
+	}
+	logf("INFO", "UDP scan: %d targets, %d ports", len(targets), len(ports))
+
+	var assets []*Asset
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	type job struct {
+		host string
+		port int
+	}
+	jobs := make(chan job, opts.Concurrent)
+
+	for i := 0; i < opts.Concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				if ctx.Err() != nil {
+					continue
+				}
+				asset := probeUDP(ctx, j.host, j.port, probesForPort(j.port), opts)
+				if asset == nil {
+					continue
+				}
+				mu.Lock()
+				assets = append(assets, asset)
+				mu.Unlock()
+			}
+		}()
+	}
+
+dispatch:
+	for _, host := range targets {
+		for _, port := range ports {
+			select {
+			case <-ctx.Done():
+				break dispatch
+			case jobs <- job{host, port}:
+			}
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	logf("INFO", "UDP scan completed: %d open ports", len(assets))
+	return &ScanResult{
+		WorkspaceId: config.WorkspaceId,
+		MainTaskId:  config.MainTaskId,
+		Assets:      assets,
+	}, nil
+}
+
+// IdentifyUDP 使用全部协议探测识别 UDP 资产的服务，识别成功时更新服务、banner 和产品信息
+func IdentifyUDP(ctx context.Context, asset *Asset, timeout int) bool {
+	if timeout <= 0 {
+		timeout = 2
+	}
+	// 优先使用端口对应的探测，再尝试其他协议
+	probes := probesForPort(asset.Port)
+	if len(probes) == 1 {
+		for _, p := range udpProbes {
+			if p != probes[0] {
+				probes = append(probes, p)
+			}
+		}
+	}
+	for _, p := range probes {
+		result := probeUDP(ctx, asset.Host, asset.Port, []*udpProbe{p}, &UDPScanOptions{Timeout: timeout, Retries: 1})
+		if result == nil || result.Service == "" {
+			continue
+		}
+		asset.Service = result.Service
+		asset.Banner = result.Banner
+		if len(result.App) > 0 {
+			asset.App = result.App
+		}
+		return true
+	}
+	return false
+}
+
+// probeUDP 向 host:port 发送探测并等待响应，无响应返回 nil。
+// 使用非连接的套接字，以便接收从其他端口返回的响应（如 TFTP）
+func probeUDP(ctx context.Context, host string, port int, probes []*udpProbe, opts *UDPScanOptions) *Asset {
+	addr, err := net.ResolveUDPAddr("udp", net.JoinHostPort(host, fmt.Sprintf("%d", port)))
+	if err != nil {
+		return nil
+	}
+	network := "udp4"
+	if addr.IP.To4() == nil {
+		network = "udp6"
+	}
+	conn, err := net.ListenPacket(network, "")
+	if err != nil {
+		return nil
+	}
+	defer conn.Close()
+
+	buf := make([]byte, 4096)
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if ctx.Err() != nil {
+			return nil
+		}
+		for _, p := range probes {
+			conn.WriteTo(p.payload, addr)
+		}
+		deadline := time.Now().Add(time.Duration(opts.Timeout) * time.Second)
+		conn.SetReadDeadline(deadline)
+		for {
+			n, from, err := conn.ReadFrom(buf)
+			if err != nil {
+				break
+			}
+			udpFrom, ok := from.(*net.UDPAddr)
+			if !ok || !udpFrom.IP.Equal(addr.IP) || n == 0 {
+				continue
+			}
+			return newUDPAsset(host, port, probes, buf[:n])
+		}
+	}
+	return nil
+}
+
+// newUDPAsset 根据响应创建资产，无法识别协议的响应只记录端口开放
+func newUDPAsset(host string, port int, probes []*udpProbe, resp []byte) *Asset {
+	asset := &Asset{
+		Authority: fmt.Sprintf("%s:%d", host, port),
+		Host:      host,
+		Port:      port,
+		Category:  getCategory(host),
+		Transport: TransportUDP,
+	}
+	for _, p := range probes {
+		if app, banner, ok := p.parse(resp); ok {
+			asset.Service = p.service
+			asset.Banner = banner
+			if app != "" {
+				asset.App = []string{app}
+			}
+			return asset
+		}
+	}
+	asset.Banner = printableBanner(resp)
+	return asset
+}
+
+// printableBanner 截取响应中的可打印字符
+func printableBanner(b []byte) string {
+	if len(b) > 256 {
+		b = b[:256]
+	}
+	var sb strings.Builder
+	for _, c := range b {
+		if c >= 0x20 && c < 0x7f {
+			sb.WriteByte(c)
+		} else {
+			sb.WriteByte('.')
+		}
+	}
+	return sb.String()
+}
+
+// ==================== DNS ====================
+
+// dnsVersionQuery version.bind CHAOS TXT 查询，递归和权威服务器都会响应（可能拒绝）
+var dnsVersionQuery = []byte{
+	0x43, 0x53, 0x01, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x07, 'v', 'e', 'r', 's', 'i', 'o', 'n', 0x04, 'b', 'i', 'n', 'd', 0x00,
+	0x00, 0x10, 0x00, 0x03,
+}
+
+func parseDNSResponse(resp []byte) (string, string, bool) {
+	// 校验事务ID和QR标志
+	if len(resp) < 12 || resp[0] != 0x43 || resp[1] != 0x53 || resp[2]&0x80 == 0 {
+		return "", "", false
+	}
+	rcode := resp[3] & 0x0f
+	if binary.BigEndian.Uint16(resp[6:8]) == 0 || rcode != 0 {
+		return "", fmt.Sprintf("DNS rcode=%d", rcode), true
+	}
+	// 跳过问题部分，读取第一条 TXT 回答
+	off := len(dnsVersionQuery)
+	if off >= len(resp) {
+		return "", "", true
+	}
+	off = skipDNSName(resp, off)
+	if off < 0 || off+10 > len(resp) {
+		return "", "", true
+	}
+	rdlen := int(binary.BigEndian.Uint16(resp[off+8 : off+10]))
+	off += 10
+	if off+rdlen > len(resp) || rdlen < 1 {
+		return "", "", true
+	}
+	txtLen := int(resp[off])
+	if off+1+txtLen > len(resp) {
+		return "", "", true
+	}
+	version := string(resp[off+1 : off+1+txtLen])
+	return version, "version.bind: " + version, true
+}
+
+// skipDNSName 跳过域名（支持压缩指针），返回之后的偏移
+func skipDNSName(b []byte, off int) int {
+	for off < len(b) {
+		l := int(b[off])
+		switch {
+		case l == 0:
+			return off + 1
+		case l&0xc0 == 0xc0:
+			return off + 2
+		default:
+			off += l + 1
+		}
+	}
+	return -1
+}
+
+// ==================== TFTP ====================
+
+// tftpReadRequest 读取不存在的文件，服务器返回 DATA 或 ERROR 报文
+var tftpReadRequest = append(append([]byte{0x00, 0x01}, "cscan-probe.txt\x00"...), "octet\x00"...)
+
+func parseTFTPResponse(resp []byte) (string, string, bool) {
+	if len(resp) < 4 || resp[0] != 0x00 {
+		return "", "", false
+	}
+	switch resp[1] {
+	case 0x03:
+		return "", "TFTP DATA", true
+	case 0x05:
+		msg := strings.TrimRight(string(resp[4:]), "\x00")
+		return "", "TFTP ERROR: " + msg, true
+	}
+	return "", "", false
+}
+
+// ==================== NTP ====================
+
+// ntpClientRequest NTPv4 客户端请求（mode 3）
+var ntpClientRequest = append([]byte{0xe3, 0x00, 0x04, 0xfa}, make([]byte, 44)...)
+
+func parseNTPResponse(resp []byte) (string, string, bool) {
+	if len(resp) < 48 || resp[0]&0x07 != 4 {
+		return "", "", false
+	}
+	version := (resp[0] >> 3) & 0x07
+	stratum := resp[1]
+	return "", fmt.Sprintf("NTP v%d stratum %d", version, stratum), true
+}
+
+// ==================== SNMP ====================
+
+// snmpGetSysDescr SNMPv2c GetRequest，community public，OID 1.3.6.1.2.1.1.1.0 (sysDescr)
+var snmpGetSysDescr = []byte{
+	0x30, 0x29, 0x02, 0x01, 0x01, 0x04, 0x06, 'p', 'u', 'b', 'l', 'i', 'c',
+	0xa0, 0x1c, 0x02, 0x04, 0x43, 0x53, 0x43, 0x4e, 0x02, 0x01, 0x00, 0x02, 0x01, 0x00,
+	0x30, 0x0e, 0x30, 0x0c, 0x06, 0x08, 0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x01, 0x00, 0x05, 0x00,
+}
+
+var snmpSysDescrOid = []byte{0x06, 0x08, 0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x01, 0x00}
+
+func parseSNMPResponse(resp []byte) (string, string, bool) {
+	// 请求ID一致
+	if len(resp) < 20 || resp[0] != 0x30 || !bytes.Contains(resp, []byte{0x02, 0x04, 0x43, 0x53, 0x43, 0x4e}) {
+		return "", "", false
+	}
+	idx := bytes.Index(resp, snmpSysDescrOid)
+	if idx < 0 {
+		return "", "SNMP (community public)", true
+	}
+	off := idx + len(snmpSysDescrOid)
+	if off+2 > len(resp) || resp[off] != 0x04 {
+		return "", "SNMP (community public)", true
+	}
+	l := int(resp[off+1])
+	off += 2
+	if l&0x80 != 0 {
+		n := l & 0x7f
+		if n == 0 || n > 2 || off+n > len(resp) {
+			return "", "SNMP (community public)", true
+		}
+		l = 0
+		for i := 0; i < n; i++ {
+			l = l<<8 | int(resp[off+i])
+		}
+		off += n
+	}
+	if off+l > len(resp) {
+		l = len(resp) - off
+	}
+	descr := strings.TrimSpace(string(resp[off : off+l]))
+	app := descr
+	if i := strings.IndexAny(app, "\r\n"); i > 0 {
+		app = app[:i]
+	}
+	return app, "sysDescr: " + descr, true
+}
+
+// ==================== IPMI ====================
+
+// ipmiGetChannelAuth RMCP + IPMI Get Channel Authentication Capabilities
+var ipmiGetChannelAuth = []byte{
+	0x06, 0x00, 0xff, 0x07, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x09,
+	0x20, 0x18, 0xc8, 0x81, 0x00, 0x38, 0x8e, 0x04, 0xb5,
+}
+
+func parseIPMIResponse(resp []byte) (string, string, bool) {
+	// RMCP v1.0，消息类型 IPMI
+	if len(resp) < 4 || resp[0] != 0x06 || resp[3]&0x0f != 0x07 {
+		return "", "", false
+	}
+	banner := "IPMI RMCP"
+	// 完成码之后为认证能力，bit7 表示支持 IPMI 2.0
+	if len(resp) >= 23 && resp[20] == 0x00 {
+		if resp[22]&0x80 != 0 {
+			banner = "IPMI 2.0"
+		} else {
+			banner = "IPMI 1.5"
+		}
+	}
+	return "", banner, true
+}
+
+// ==================== SSDP ====================
+
+var ssdpMSearch = []byte("M-SEARCH * HTTP/1.1\r\nHOST: 239.255.255.250:1900\r\nMAN: \"ssdp:discover\"\r\nMX: 1\r\nST: ssdp:all\r\n\r\n")
+
+func parseSSDPResponse(resp []byte) (string, string, bool) {
+	if !bytes.HasPrefix(resp, []byte("HTTP/1.")) && !bytes.HasPrefix(resp, []byte("NOTIFY")) {
+		return "", "", false
+	}
+	var server string
+	for _, line := range strings.Split(string(resp), "\r\n") {
+		if k, v, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(k), "server") {
+			server = strings.TrimSpace(v)
+			break
+		}
+	}
+	return server, printableBanner(resp), true
+}
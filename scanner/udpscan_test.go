@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type udpParseCase struct {
+	name   string
+	resp   []byte
+	app    string
+	banner string
+	ok     bool
+}
+
+func runUDPParseCases(t *testing.T, parse func([]byte) (string, string, bool), tests []udpParseCase) {
+	t.Helper()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, banner, ok := parse(tt.resp)
+			if app != tt.app || banner != tt.banner || ok != tt.ok {
+				t.Errorf("parse() = %q, %q, %v; want %q, %q, %v", app, banner, ok, tt.app, tt.banner, tt.ok)
+			}
+		})
+	}
+}
+
+// checkTruncated 逐字节截断响应，解析不应 panic
+func checkTruncated(t *testing.T, parse func([]byte) (string, string, bool), resp []byte) {
+	t.Helper()
+	for i := 0; i < len(resp); i++ {
+		parse(resp[:i])
+	}
+}
+
+func concat(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func TestParseDNSResponse(t *testing.T) {
+	question := dnsVersionQuery[12:]
+	version := "9.18.24-1ubuntu1.3-Ubuntu"
+	answer := concat(
+		[]byte{0xc0, 0x0c, 0x00, 0x10, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, byte(len(version) + 1), byte(len(version))},
+		[]byte(version),
+	)
+	bind := concat([]byte{0x43, 0x53, 0x85, 0x80, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}, question, answer)
+	refused := concat([]byte{0x43, 0x53, 0x81, 0x85, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, question)
+	// 回答中的域名不使用压缩指针
+	uncompressed := concat([]byte{0x43, 0x53, 0x85, 0x80, 0x00, 0x01, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00}, question,
+		question[:len(question)-4], []byte{0x00, 0x10, 0x00, 0x03, 0x00, 0x00, 0x00, 0x00, 0x00, 0x05, 0x04}, []byte("dnsd"))
+
+	runUDPParseCases(t, parseDNSResponse, []udpParseCase{
+		{"bind version", bind, version, "version.bind: " + version, true},
+		{"uncompressed name", uncompressed, "dnsd", "version.bind: dnsd", true},
+		{"refused", refused, "", "DNS rcode=5", true},
+		{"no answer", concat([]byte{0x43, 0x53, 0x81, 0x80, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00}, question), "", "DNS rcode=0", true},
+		{"truncated answer", bind[:len(bind)-10], "", "", true},
+		{"truncated rdata header", bind[:len(dnsVersionQuery)+6], "", "", true},
+		{"header only", bind[:12], "", "", true},
+		{"short header", bind[:11], "", "", false},
+		{"wrong transaction id", concat([]byte{0x12, 0x34}, bind[2:]), "", "", false},
+		{"query not response", dnsVersionQuery, "", "", false},
+	})
+	checkTruncated(t, parseDNSResponse, bind)
+	checkTruncated(t, parseDNSResponse, uncompressed)
+}
+
+func TestParseSNMPResponse(t *testing.T) {
+	snmpResp := func(reqId []byte, varbind []byte) []byte {
+		vb := concat([]byte{0x30, byte(len(varbind))}, varbind)
+		vbl := concat([]byte{0x30, byte(len(vb))}, vb)
+		pdu := concat([]byte{0x02, 0x04}, reqId, []byte{0x02, 0x01, 0x00, 0x02, 0x01, 0x00}, vbl)
+		pdu = concat([]byte{0xa2, byte(len(pdu))}, pdu)
+		msg := concat([]byte{0x02, 0x01, 0x01, 0x04, 0x06}, []byte("public"), pdu)
+		return concat([]byte{0x30, byte(len(msg))}, msg)
+	}
+	reqId := []byte{0x43, 0x53, 0x43, 0x4e}
+	descr := "Linux router 5.15.0-91-generic #101-Ubuntu SMP x86_64\r\nbuilt by cscan"
+	linux := snmpResp(reqId, concat(snmpSysDescrOid, []byte{0x04, byte(len(descr))}, []byte(descr)))
+	// 超过 127 字节的值使用长格式长度
+	long := strings.Repeat("Cisco IOS Software, C2960 Software ", 4)
+	cisco := concat(snmpSysDescrOid, []byte{0x04, 0x81, byte(len(long))}, []byte(long))
+	cisco = concat([]byte{0x30, 0x81, byte(len(cisco))}, cisco)
+	cisco = concat([]byte{0x30, 0x81, byte(len(cisco))}, cisco)
+	cisco = concat([]byte{0x30, 0x81, 0x00, 0x02, 0x01, 0x01, 0x04, 0x06}, []byte("public"), []byte{0xa2, 0x81, 0x00, 0x02, 0x04}, reqId, []byte{0x02, 0x01, 0x00, 0x02, 0x01, 0x00}, cisco)
+	noSuchObject := snmpResp(reqId, concat(snmpSysDescrOid, []byte{0x80, 0x00}))
+	otherOid := snmpResp(reqId, []byte{0x06, 0x08, 0x2b, 0x06, 0x01, 0x02, 0x01, 0x01, 0x05, 0x00, 0x04, 0x02, 'r', '1'})
+
+	runUDPParseCases(t, parseSNMPResponse, []udpParseCase{
+		{"linux sysDescr", linux, "Linux router 5.15.0-91-generic #101-Ubuntu SMP x86_64", "sysDescr: " + descr, true},
+		{"long form length", cisco, strings.TrimSpace(long), "sysDescr: " + strings.TrimSpace(long), true},
+		{"no such object", noSuchObject, "", "SNMP (community public)", true},
+		{"other oid", otherOid, "", "SNMP (community public)", true},
+		{"truncated value", linux[:len(linux)-10], "Linux router 5.15.0-91-generic #101-Ubuntu SMP x86_64", "sysDescr: " + descr[:len(descr)-10], true},
+		{"truncated after oid", linux[:bytes.Index(linux, snmpSysDescrOid)+len(snmpSysDescrOid)+1], "", "SNMP (community public)", true},
+		{"wrong request id", snmpResp([]byte{0x01, 0x02, 0x03, 0x04}, concat(snmpSysDescrOid, []byte{0x04, 0x01, 'x'})), "", "", false},
+		{"not sequence", concat([]byte{0x31}, linux[1:]), "", "", false},
+		{"too short", linux[:19], "", "", false},
+	})
+	checkTruncated(t, parseSNMPResponse, linux)
+	checkTruncated(t, parseSNMPResponse, cisco)
+}
+
+func TestParseIPMIResponse(t *testing.T) {
+	// RMCP 头、IPMI 1.5 会话头、Get Channel Authentication Capabilities 响应
+	ipmiResp := func(completion, authCaps byte) []byte {
+		return []byte{
+			0x06, 0x00, 0xff, 0x07,
+			0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x10,
+			0x81, 0x1c, 0x63, 0x20, 0x00, 0x38, completion, 0x01, authCaps, 0x04, 0x03, 0x00, 0x00, 0x00, 0x00, 0x09,
+		}
+	}
+	v2 := ipmiResp(0x00, 0x97)
+
+	runUDPParseCases(t, parseIPMIResponse, []udpParseCase{
+		{"ipmi 2.0", v2, "", "IPMI 2.0", true},
+		{"ipmi 1.5", ipmiResp(0x00, 0x17), "", "IPMI 1.5", true},
+		{"error completion code", ipmiResp(0xcc, 0x97), "", "IPMI RMCP", true},
+		{"truncated message", v2[:20], "", "IPMI RMCP", true},
+		{"rmcp ack", []byte{0x06, 0x00, 0xff, 0x86}, "", "", false},
+		{"asf presence pong", []byte{0x06, 0x00, 0xff, 0x06, 0x00, 0x00, 0x11, 0xbe}, "", "", false},
+		{"not rmcp", []byte{0x05, 0x00, 0xff, 0x07}, "", "", false},
+		{"too short", v2[:3], "", "", false},
+	})
+	checkTruncated(t, parseIPMIResponse, v2)
+}
+
+func TestParseNTPResponse(t *testing.T) {
+	ntpResp := func(first, stratum byte) []byte {
+		b := make([]byte, 48)
+		b[0], b[1], b[2], b[3] = first, stratum, 0x03, 0xe9
+		copy(b[12:16], "GPS\x00")
+		return b
+	}
+	v4 := ntpResp(0x24, 1)
+
+	runUDPParseCases(t, parseNTPResponse, []udpParseCase{
+		{"ntpv4 server", v4, "", "NTP v4 stratum 1", true},
+		{"ntpv3 server", ntpResp(0x1c, 3), "", "NTP v3 stratum 3", true},
+		{"unsynchronized", ntpResp(0xe4, 16), "", "NTP v4 stratum 16", true},
+		{"with extension", append(ntpResp(0x24, 2), make([]byte, 20)...), "", "NTP v4 stratum 2", true},
+		{"client request echo", ntpClientRequest, "", "", false},
+		{"broadcast mode", ntpResp(0x25, 2), "", "", false},
+		{"truncated", v4[:47], "", "", false},
+	})
+	checkTruncated(t, parseNTPResponse, v4)
+}
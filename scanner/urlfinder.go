@@ -20,6 +20,13 @@ type URLFinderScanner struct {
 	BaseScanner
 }
 
+func init() {
+	Register("urlfinder", Registration{
+		New:     func() Scanner { return NewURLFinderScanner() },
+		Options: func() interface{} { return defaultURLFinderOptions() },
+	})
+}
+
 // NewURLFinderScanner 创建目录扫描器
 func NewURLFinderScanner() *URLFinderScanner {
 	return &URLFinderScanner{
@@ -39,6 +46,17 @@ type URLFinderOptions struct {
 	Headers       map[string]string `json:"headers"` // 自定义请求头
 }
 
+// defaultURLFinderOptions 默认扫描选项
+func defaultURLFinderOptions() *URLFinderOptions {
+	return &URLFinderOptions{
+		Threads:        50,
+		Timeout:        10,
+		StatusCodes:    []int{200, 201, 204, 301, 302, 307, 308, 401, 403, 405, 500},
+		FollowRedirect: false,
+		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
+	}
+}
+
 // URLFinderResult 目录扫描结果
 type URLFinderResult struct {
 	URL           string `json:"url"`
@@ -52,13 +70,7 @@ type URLFinderResult struct {
 // Scan 执行目录扫描
 func (s *URLFinderScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanResult, error) {
 	// 默认配置
-	opts := &URLFinderOptions{
-		Threads:        50,
-		Timeout:        10,
-		StatusCodes:    []int{200, 201, 204, 301, 302, 307, 308, 401, 403, 405, 500},
-		FollowRedirect: false,
-		UserAgent:      "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/120.0.0.0 Safari/537.36",
-	}
+	opts := defaultURLFinderOptions()
 
 	// 日志函数
 	logInfo := func(format string, args ...interface{}) {
@@ -102,8 +114,12 @@ func (s *URLFinderScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanR
 	// 获取目标列表
 	var targets []string
 	if config.Assets != nil && len(config.Assets) > 0 {
+		// 爬虫发现的URL与站点同属一个 host:port，只扫描一次
+		seen := make(map[string]bool)
 		for _, asset := range config.Assets {
-			if asset.IsHTTP {
+			key := fmt.Sprintf("%s:%d", asset.Host, asset.Port)
+			if asset.IsHTTP && !seen[key] {
+				seen[key] = true
 				scheme := "http"
 				if asset.Port == 443 || strings.HasPrefix(asset.Service, "https") {
 					scheme = "https"
@@ -186,6 +202,10 @@ func (s *URLFinderScanner) Scan(ctx context.Context, config *ScanConfig) (*ScanR
 				default:
 				}
 
+				if err := waitRate(ctx, config.RateLimiter, task.baseURL, 1); err != nil {
+					return
+				}
+
 				result := s.scanPath(client, task.baseURL, task.path, opts, validStatusCodes, logDebug)
 				if result != nil {
 					resultsMu.Lock()
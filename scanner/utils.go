@@ -1,7 +1,9 @@
 package scanner
 
 import (
+	"fmt"
 	"net"
+	"sort"
 	"strconv"
 	"strings"
 )
@@ -82,6 +84,63 @@ func parsePorts(portStr string) []int {
 	return ports
 }
 
+// ExcludePorts 从端口配置中去掉被排除的端口，返回压缩为范围的端口列表，如 "80,443,8000-8080"
+func ExcludePorts(portStr string, excluded func(port int) bool) string {
+	var kept []int
+	seen := make(map[int]bool)
+	for _, p := range parsePorts(portStr) {
+		if p < 1 || p > 65535 || seen[p] || excluded(p) {
+			continue
+		}
+		seen[p] = true
+		kept = append(kept, p)
+	}
+	sort.Ints(kept)
+
+	var parts []string
+	for i := 0; i < len(kept); {
+		j := i
+		for j+1 < len(kept) && kept[j+1] == kept[j]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, fmt.Sprintf("%d-%d", kept[i], kept[j]))
+		} else {
+			parts = append(parts, strconv.Itoa(kept[i]))
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// TargetHost 提取单行目标中的主机（域名或IP），URL和host:port取主机部分，CIDR和IP范围返回空
+func TargetHost(line string) string {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return ""
+	}
+	if i := strings.Index(line, "://"); i >= 0 {
+		line = line[i+3:]
+		if j := strings.IndexAny(line, "/?#"); j >= 0 {
+			line = line[:j]
+		}
+	}
+	if strings.Contains(line, "/") {
+		return ""
+	}
+	if host, _, err := net.SplitHostPort(line); err == nil {
+		line = host
+	}
+	line = strings.ToLower(strings.Trim(line, "[]."))
+	if ip := net.ParseIP(line); ip != nil {
+		return ip.String()
+	}
+	if strings.Contains(line, "-") && net.ParseIP(strings.Split(line, "-")[0]) != nil {
+		return ""
+	}
+	return line
+}
+
 // getCategory 获取目标类型
 func getCategory(target string) string {
 	ip := net.ParseIP(target)
@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"github.com/robfig/cron/v3"
+	"github.com/zeromicro/go-zero/core/logx"
 )
 
 // CronTask 定时任务
@@ -21,15 +23,26 @@ type CronTask struct {
 	Status      string `json:"status"` // enable/disable
 	LastRunTime string `json:"lastRunTime"`
 	NextRunTime string `json:"nextRunTime"`
-	EntryId     cron.EntryID `json:"-"`
+	// 维护窗口关闭时推迟到的执行时间，为空表示未推迟
+	DeferredUntil string       `json:"deferredUntil,omitempty"`
+	EntryId       cron.EntryID `json:"-"`
+	deferTimer    *time.Timer
+	deferSeq      uint64 // 每次推迟或取消推迟时递增，用于忽略过期的定时器
 }
 
+// WindowLoader 加载工作空间和组织的维护窗口
+type WindowLoader func(ctx context.Context, workspaceId, orgId string) (Windows, error)
+
 // CronManager 定时任务管理器
 type CronManager struct {
 	scheduler *Scheduler
 	rdb       *redis.Client
-	tasks     map[string]*CronTask
-	cronKey   string
+	// mu 保护 tasks 及任务状态，cron 触发、推迟执行的定时器和增删改操作可能并发
+	mu      sync.Mutex
+	tasks   map[string]*CronTask
+	cronKey string
+	// 维护窗口加载函数，为空时不检查维护窗口
+	loadWindows WindowLoader
 }
 
 // NewCronManager 创建定时任务管理器
@@ -42,6 +55,11 @@ func NewCronManager(scheduler *Scheduler, rdb *redis.Client) *CronManager {
 	}
 }
 
+// SetWindowLoader 设置维护窗口加载函数
+func (m *CronManager) SetWindowLoader(loader WindowLoader) {
+	m.loadWindows = loader
+}
+
 // LoadTasks 从Redis加载定时任务
 func (m *CronManager) LoadTasks(ctx context.Context) error {
 	data, err := m.rdb.HGetAll(ctx, m.cronKey).Result()
@@ -49,6 +67,9 @@ func (m *CronManager) LoadTasks(ctx context.Context) error {
 		return err
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	for id, taskData := range data {
 		var task CronTask
 		if err := json.Unmarshal([]byte(taskData), &task); err != nil {
@@ -57,6 +78,10 @@ func (m *CronManager) LoadTasks(ctx context.Context) error {
 		task.Id = id
 		if task.Status == "enable" {
 			m.startTask(&task)
+			// 恢复重启前被推迟的执行
+			if until, err := time.ParseInLocation("2006-01-02 15:04:05", task.DeferredUntil, time.Local); err == nil {
+				m.deferTask(&task, until)
+			}
 		}
 		m.tasks[id] = &task
 	}
@@ -73,6 +98,9 @@ func (m *CronManager) AddTask(ctx context.Context, task *CronTask) error {
 		return fmt.Errorf("invalid cron spec: %v", err)
 	}
 
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	task.NextRunTime = schedule.Next(time.Now()).Local().Format("2006-01-02 15:04:05")
 	task.Status = "enable"
 
@@ -91,6 +119,9 @@ func (m *CronManager) AddTask(ctx context.Context, task *CronTask) error {
 
 // RemoveTask 移除定时任务
 func (m *CronManager) RemoveTask(ctx context.Context, taskId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	task, ok := m.tasks[taskId]
 	if !ok {
 		return fmt.Errorf("task not found: %s", taskId)
@@ -100,6 +131,7 @@ func (m *CronManager) RemoveTask(ctx context.Context, taskId string) error {
 	if task.EntryId > 0 {
 		m.scheduler.RemoveCronTask(task.EntryId)
 	}
+	task.stopDeferTimer()
 
 	// 从Redis删除
 	if err := m.rdb.HDel(ctx, m.cronKey, taskId).Err(); err != nil {
@@ -112,6 +144,9 @@ func (m *CronManager) RemoveTask(ctx context.Context, taskId string) error {
 
 // EnableTask 启用定时任务
 func (m *CronManager) EnableTask(ctx context.Context, taskId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	task, ok := m.tasks[taskId]
 	if !ok {
 		return fmt.Errorf("task not found: %s", taskId)
@@ -131,6 +166,9 @@ func (m *CronManager) EnableTask(ctx context.Context, taskId string) error {
 
 // DisableTask 禁用定时任务
 func (m *CronManager) DisableTask(ctx context.Context, taskId string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	task, ok := m.tasks[taskId]
 	if !ok {
 		return fmt.Errorf("task not found: %s", taskId)
@@ -145,6 +183,8 @@ func (m *CronManager) DisableTask(ctx context.Context, taskId string) error {
 		m.scheduler.RemoveCronTask(task.EntryId)
 		task.EntryId = 0
 	}
+	task.stopDeferTimer()
+	task.DeferredUntil = ""
 
 	task.Status = "disable"
 
@@ -153,11 +193,16 @@ func (m *CronManager) DisableTask(ctx context.Context, taskId string) error {
 	return m.rdb.HSet(ctx, m.cronKey, taskId, data).Err()
 }
 
-// GetTasks 获取所有定时任务
+// GetTasks 获取所有定时任务的副本
 func (m *CronManager) GetTasks() []*CronTask {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	tasks := make([]*CronTask, 0, len(m.tasks))
 	for _, task := range m.tasks {
-		tasks = append(tasks, task)
+		t := *task
+		t.deferTimer = nil
+		tasks = append(tasks, &t)
 	}
 	return tasks
 }
@@ -176,26 +221,122 @@ func (m *CronManager) startTask(task *CronTask) {
 // executeTask 执行定时任务
 func (m *CronManager) executeTask(task *CronTask) {
 	ctx := context.Background()
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	// 更新最后执行时间
-	task.LastRunTime = time.Now().Local().Format("2006-01-02 15:04:05")
+	// 任务已被删除或禁用
+	if m.tasks[task.Id] != task || task.Status != "enable" {
+		return
+	}
 
 	// 计算下次执行时间
 	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
 	schedule, _ := parser.Parse(task.CronSpec)
 	task.NextRunTime = schedule.Next(time.Now()).Local().Format("2006-01-02 15:04:05")
 
-	// 更新Redis
+	// 维护窗口关闭时推迟执行，已推迟的任务不重复推迟
+	if task.DeferredUntil != "" {
+		m.saveTask(ctx, task)
+		return
+	}
+	if until, open := m.checkWindows(ctx, task); !open {
+		if !until.IsZero() {
+			m.deferTask(task, until)
+		}
+		m.saveTask(ctx, task)
+		return
+	}
+	m.runTask(ctx, task)
+}
+
+// runDeferred 推迟的时间到达后重新检查维护窗口并执行
+// 定时器已被停止或替换（任务被删除、禁用或重新推迟）时不执行
+func (m *CronManager) runDeferred(task *CronTask, seq uint64) {
+	ctx := context.Background()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.tasks[task.Id] != task || task.Status != "enable" || task.deferSeq != seq {
+		return
+	}
+	task.deferTimer = nil
+	task.DeferredUntil = ""
+	if until, open := m.checkWindows(ctx, task); !open {
+		// 窗口配置在推迟期间被修改
+		if !until.IsZero() {
+			m.deferTask(task, until)
+		}
+		m.saveTask(ctx, task)
+		return
+	}
+	m.runTask(ctx, task)
+}
+
+// checkWindows 检查任务所属工作空间和组织的维护窗口
+// 窗口关闭时返回下次打开的时间，不会再打开时返回零值并跳过本次执行
+func (m *CronManager) checkWindows(ctx context.Context, task *CronTask) (time.Time, bool) {
+	if m.loadWindows == nil {
+		return time.Time{}, true
+	}
+	var config struct {
+		OrgId string `json:"orgId"`
+	}
+	json.Unmarshal([]byte(task.Config), &config)
+	windows, err := m.loadWindows(ctx, task.WorkspaceId, config.OrgId)
+	if err != nil {
+		// 无法确认窗口状态时按原计划执行，避免定时任务被无限推迟
+		logx.Errorf("[Cron] load maintenance windows for task %s failed: %v", task.Id, err)
+		return time.Time{}, true
+	}
+	now := time.Now()
+	if windows.Open(now) {
+		return time.Time{}, true
+	}
+	until := windows.NextOpen(now)
+	if until.IsZero() {
+		logx.Infof("[Cron] task %s skipped: maintenance window will not reopen", task.Id)
+	} else {
+		logx.Infof("[Cron] task %s deferred until %s: outside maintenance window", task.Id, until.Local().Format("2006-01-02 15:04:05"))
+	}
+	return until, false
+}
+
+// deferTask 推迟到 until 执行，调用方需持有 m.mu
+func (m *CronManager) deferTask(task *CronTask, until time.Time) {
+	task.stopDeferTimer()
+	task.DeferredUntil = until.Local().Format("2006-01-02 15:04:05")
+	seq := task.deferSeq
+	task.deferTimer = time.AfterFunc(time.Until(until), func() {
+		m.runDeferred(task, seq)
+	})
+}
+
+func (t *CronTask) stopDeferTimer() {
+	t.deferSeq++
+	if t.deferTimer != nil {
+		t.deferTimer.Stop()
+		t.deferTimer = nil
+	}
+}
+
+// saveTask 保存定时任务到Redis
+func (m *CronManager) saveTask(ctx context.Context, task *CronTask) {
 	data, _ := json.Marshal(task)
 	m.rdb.HSet(ctx, m.cronKey, task.Id, data)
+}
+
+// runTask 更新最后执行时间并推送任务到队列
+func (m *CronManager) runTask(ctx context.Context, task *CronTask) {
+	task.LastRunTime = time.Now().Local().Format("2006-01-02 15:04:05")
+	m.saveTask(ctx, task)
 
-	// 推送任务到队列
 	taskInfo := &TaskInfo{
 		MainTaskId:  task.MainTaskId,
 		WorkspaceId: task.WorkspaceId,
 		TaskName:    task.Name,
 		Config:      task.Config,
 		Priority:    0,
+		Requires:    TaskRequires(task.Config),
 	}
 	m.scheduler.PushTask(ctx, taskInfo)
 }
@@ -0,0 +1,77 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+// TestCronDeferredRun 测试推迟执行与禁用、重新推迟并发时不会执行过期的推迟
+func TestCronDeferredRun(t *testing.T) {
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis: %v", err)
+	}
+	defer mr.Close()
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	s := NewScheduler(rdb)
+	m := NewCronManager(s, rdb)
+	ctx := context.Background()
+
+	task := &CronTask{Id: "c1", Name: "daily", CronSpec: "0 0 0 1 1 *", WorkspaceId: "ws1"}
+	if err := m.AddTask(ctx, task); err != nil {
+		t.Fatal(err)
+	}
+	deferFor := func(d time.Duration) {
+		m.mu.Lock()
+		m.deferTask(task, time.Now().Add(d))
+		m.mu.Unlock()
+	}
+	queued := func() int64 {
+		n, err := s.GetQueueLength(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// 禁用后推迟的执行取消
+	deferFor(20 * time.Millisecond)
+	if err := m.DisableTask(ctx, task.Id); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(60 * time.Millisecond)
+	if n := queued(); n != 0 {
+		t.Fatalf("disabled task queued %d times", n)
+	}
+
+	// 重新推迟后旧的定时器不执行
+	if err := m.EnableTask(ctx, task.Id); err != nil {
+		t.Fatal(err)
+	}
+	deferFor(20 * time.Millisecond)
+	deferFor(time.Hour)
+	time.Sleep(60 * time.Millisecond)
+	if n := queued(); n != 0 {
+		t.Fatalf("superseded deferral queued %d times", n)
+	}
+
+	deferFor(20 * time.Millisecond)
+	time.Sleep(60 * time.Millisecond)
+	if n := queued(); n != 1 {
+		t.Fatalf("deferred task queued %d times, want 1", n)
+	}
+	if tasks := m.GetTasks(); len(tasks) != 1 || tasks[0].DeferredUntil != "" || tasks[0].LastRunTime == "" {
+		t.Errorf("GetTasks() = %+v", tasks[0])
+	}
+
+	if err := m.RemoveTask(ctx, task.Id); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.GetTasks()) != 0 {
+		t.Error("task not removed")
+	}
+}
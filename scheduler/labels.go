@@ -0,0 +1,121 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Worker 标签
+// Worker 启动时通过 -labels 指定自定义标签（如 region=cn-east,zone=dmz,vantage=internal），
+// 并自动附加已安装工具的 tool=<工具名> 标签。同一个键可以有多个值，如 tool=nmap 和 tool=masscan。
+//
+// 标签选择器由逗号分隔的条件组成，全部满足时匹配：
+//   key=value   Worker 具有该标签
+//   key!=value  Worker 不具有该标签
+
+// LabelTool 已安装工具的标签键
+const LabelTool = "tool"
+
+// ToolLabel 已安装工具对应的标签
+func ToolLabel(tool string) string {
+	return LabelTool + "=" + strings.ToLower(tool)
+}
+
+// ParseLabels 解析逗号分隔的 key=value 标签，返回去重排序后的标签
+func ParseLabels(s string) ([]string, error) {
+	var labels []string
+	for _, item := range splitList(s) {
+		key, value, ok := strings.Cut(item, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || !validLabelPart(key) || !validLabelPart(value) {
+			return nil, fmt.Errorf("invalid label %q, expected key=value", item)
+		}
+		labels = append(labels, strings.ToLower(key)+"="+strings.ToLower(value))
+	}
+	return normalizeTerms(labels), nil
+}
+
+// ParseSelector 解析逗号分隔的标签选择器，返回去重排序后的条件
+func ParseSelector(s string) ([]string, error) {
+	var terms []string
+	for _, item := range splitList(s) {
+		op := "="
+		key, value, ok := strings.Cut(item, "!=")
+		if ok {
+			op = "!="
+		} else {
+			key, value, ok = strings.Cut(item, "=")
+		}
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !ok || !validLabelPart(key) || !validLabelPart(value) {
+			return nil, fmt.Errorf("invalid selector %q, expected key=value or key!=value", item)
+		}
+		terms = append(terms, strings.ToLower(key)+op+strings.ToLower(value))
+	}
+	return normalizeTerms(terms), nil
+}
+
+// MatchSelector Worker 标签是否满足选择器的全部条件
+func MatchSelector(selector, labels []string) bool {
+	for _, term := range selector {
+		if key, value, ok := strings.Cut(term, "!="); ok {
+			if containsString(labels, key+"="+value) {
+				return false
+			}
+		} else if !containsString(labels, term) {
+			return false
+		}
+	}
+	return true
+}
+
+// selectorKey 将选择器规范化为排序后以逗号连接的字符串，满足相同选择器的任务进入同一队列
+func selectorKey(selector []string) string {
+	terms := make([]string, 0, len(selector))
+	for _, term := range selector {
+		terms = append(terms, strings.ToLower(term))
+	}
+	return strings.Join(normalizeTerms(terms), ",")
+}
+
+// normalizeTerms 去重并排序
+func normalizeTerms(terms []string) []string {
+	return mergeTerms(nil, terms)
+}
+
+func splitList(s string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// validLabelPart 标签键和值只允许字母、数字和 -_./: 字符
+func validLabelPart(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case strings.ContainsRune("-_./:", r):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func mergeTerms(a, b []string) []string {
+	for _, t := range b {
+		if !containsString(a, t) {
+			a = append(a, t)
+		}
+	}
+	sort.Strings(a)
+	return a
+}
@@ -0,0 +1,63 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLabels(t *testing.T) {
+	labels, err := ParseLabels("Zone=DMZ, region=cn-east,zone=dmz")
+	if err != nil {
+		t.Fatalf("ParseLabels() error = %v", err)
+	}
+	if want := []string{"region=cn-east", "zone=dmz"}; !reflect.DeepEqual(labels, want) {
+		t.Errorf("ParseLabels() = %v, want %v", labels, want)
+	}
+	for _, s := range []string{"zone", "zone=", "=dmz", "zone=d m z", "zone!=dmz"} {
+		if _, err := ParseLabels(s); err == nil {
+			t.Errorf("ParseLabels(%q) expected error", s)
+		}
+	}
+}
+
+func TestParseSelector(t *testing.T) {
+	selector, err := ParseSelector("tool=masscan,zone!=dmz,tool=masscan")
+	if err != nil {
+		t.Fatalf("ParseSelector() error = %v", err)
+	}
+	if want := []string{"tool=masscan", "zone!=dmz"}; !reflect.DeepEqual(selector, want) {
+		t.Errorf("ParseSelector() = %v, want %v", selector, want)
+	}
+	if selector, err := ParseSelector(" "); err != nil || selector != nil {
+		t.Errorf("ParseSelector(empty) = %v, %v", selector, err)
+	}
+	if _, err := ParseSelector("zone==dmz"); err == nil {
+		t.Error("ParseSelector(zone==dmz) expected error")
+	}
+}
+
+func TestMatchSelector(t *testing.T) {
+	labels := []string{"tool=masscan", "tool=nmap", "vantage=internal", "zone=dmz"}
+	tests := []struct {
+		selector []string
+		want     bool
+	}{
+		{nil, true},
+		{[]string{"tool=masscan", "zone=dmz"}, true},
+		{[]string{"tool=chromium"}, false},
+		{[]string{"vantage!=external"}, true},
+		{[]string{"vantage!=internal"}, false},
+		{[]string{"region!=cn-east"}, true},
+	}
+	for _, tt := range tests {
+		if got := MatchSelector(tt.selector, labels); got != tt.want {
+			t.Errorf("MatchSelector(%v) = %v, want %v", tt.selector, got, tt.want)
+		}
+	}
+	if MatchSelector([]string{"zone=dmz"}, nil) {
+		t.Error("MatchSelector() without labels should not match a positive term")
+	}
+	if got := selectorKey([]string{"Zone=dmz", "tool=nmap", "zone=dmz"}); got != "tool=nmap,zone=dmz" {
+		t.Errorf("selectorKey() = %q", got)
+	}
+}
@@ -0,0 +1,384 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// 租约默认参数
+const (
+	DefaultLeaseTimeout = 5 * time.Minute // 租约可见性超时，超时未续约视为 Worker 崩溃
+	DefaultMaxAttempts  = 3               // 最大投递次数，超过后进入死信队列
+)
+
+// LeaseInfo 任务租约信息
+type LeaseInfo struct {
+	Task      *TaskInfo `json:"task"`
+	Worker    string    `json:"worker"`
+	LeaseTime string    `json:"leaseTime"`
+}
+
+// DeadLetter 死信任务
+type DeadLetter struct {
+	Task     *TaskInfo `json:"task"`
+	Worker   string    `json:"worker"` // 最后一次领取任务的 Worker
+	Attempts int       `json:"attempts"`
+	Reason   string    `json:"reason"`
+	DeadTime string    `json:"deadTime"`
+}
+
+// DeadLetterHandler 任务进入死信队列时的回调
+type DeadLetterHandler func(ctx context.Context, dl *DeadLetter)
+
+// SetDeadLetterHandler 设置死信回调
+func (s *Scheduler) SetDeadLetterHandler(handler DeadLetterHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.deadLetterHandler = handler
+}
+
+// SetLeasePolicy 设置租约超时和最大投递次数
+func (s *Scheduler) SetLeasePolicy(timeout time.Duration, maxAttempts int) {
+	if timeout > 0 {
+		s.leaseTimeout = timeout
+	}
+	if maxAttempts > 0 {
+		s.maxAttempts = maxAttempts
+	}
+}
+
+// popLeaseScript 出队并创建租约，两步在同一脚本中完成，避免出队后租约写入失败导致任务丢失
+// KEYS: 队列, 租约 ZSET, 租约详情 HASH  ARGV: Worker 名称, 领取时间, 租约过期时间
+// 返回出队的任务，队列为空时返回 nil；任务缺少 taskId 时不创建租约
+var popLeaseScript = redis.NewScript(`
+local popped = redis.call('ZPOPMIN', KEYS[1])
+if #popped == 0 then
+  return false
+end
+local member = popped[1]
+local ok, task = pcall(cjson.decode, member)
+if not ok or type(task) ~= 'table' or type(task.taskId) ~= 'string' then
+  return member
+end
+local lease = '{"task":' .. member .. ',"worker":' .. cjson.encode(ARGV[1]) .. ',"leaseTime":' .. cjson.encode(ARGV[2]) .. '}'
+redis.call('HSET', KEYS[3], task.taskId, lease)
+redis.call('ZADD', KEYS[2], ARGV[3], task.taskId)
+return member
+`)
+
+// PopTaskFromQueue 从指定队列获取任务并为其创建租约
+func (s *Scheduler) PopTaskFromQueue(ctx context.Context, queueKey, workerName string) (*TaskInfo, error) {
+	now := time.Now()
+	member, err := popLeaseScript.Run(ctx, s.rdb, []string{queueKey, s.leaseKey, s.leaseDataKey},
+		workerName, now.Local().Format("2006-01-02 15:04:05"), now.Add(s.leaseTimeout).Unix()).Text()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var task TaskInfo
+	if err := json.Unmarshal([]byte(member), &task); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// LeaseTask 为已出队的任务创建租约
+func (s *Scheduler) LeaseTask(ctx context.Context, task *TaskInfo, workerName string) error {
+	lease := LeaseInfo{
+		Task:      task,
+		Worker:    workerName,
+		LeaseTime: time.Now().Local().Format("2006-01-02 15:04:05"),
+	}
+	data, err := json.Marshal(lease)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.TxPipeline()
+	pipe.HSet(ctx, s.leaseDataKey, task.TaskId, data)
+	pipe.ZAdd(ctx, s.leaseKey, redis.Z{
+		Score:  float64(time.Now().Add(s.leaseTimeout).Unix()),
+		Member: task.TaskId,
+	})
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// RenewLeases 续约 Worker 正在执行的任务，返回成功续约的数量
+// 只续约仍然存在的租约，已被回收的任务不会被重新加入
+func (s *Scheduler) RenewLeases(ctx context.Context, taskIds []string) (int64, error) {
+	if len(taskIds) == 0 {
+		return 0, nil
+	}
+
+	expireAt := float64(time.Now().Add(s.leaseTimeout).Unix())
+	pipe := s.rdb.Pipeline()
+	cmds := make([]*redis.IntCmd, 0, len(taskIds))
+	for _, taskId := range taskIds {
+		// XX: 仅更新已存在的租约；CH: 返回分数发生变化的成员数
+		cmds = append(cmds, pipe.ZAddArgs(ctx, s.leaseKey, redis.ZAddArgs{
+			XX:      true,
+			Ch:      true,
+			Members: []redis.Z{{Score: expireAt, Member: taskId}},
+		}))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, err
+	}
+
+	var renewed int64
+	for _, cmd := range cmds {
+		renewed += cmd.Val()
+	}
+	return renewed, nil
+}
+
+// ReleaseLease 释放任务租约（任务结束或暂停时调用）
+func (s *Scheduler) ReleaseLease(ctx context.Context, taskId string) error {
+	pipe := s.rdb.TxPipeline()
+	pipe.ZRem(ctx, s.leaseKey, taskId)
+	pipe.HDel(ctx, s.leaseDataKey, taskId)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// GetLease 获取任务租约
+func (s *Scheduler) GetLease(ctx context.Context, taskId string) (*LeaseInfo, error) {
+	data, err := s.rdb.HGet(ctx, s.leaseDataKey, taskId).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var lease LeaseInfo
+	if err := json.Unmarshal([]byte(data), &lease); err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// reapRetries 回收租约的事务因并发修改失败时的重试次数，仍失败的留到下一轮回收
+const reapRetries = 3
+
+// ReapExpiredLeases 回收过期租约
+// 未超过最大投递次数的任务重新入队，否则移入死信队列
+func (s *Scheduler) ReapExpiredLeases(ctx context.Context) (requeued int, dead int, err error) {
+	now := time.Now().Unix()
+	taskIds, err := s.rdb.ZRangeByScore(ctx, s.leaseKey, &redis.ZRangeBy{Min: "-inf", Max: strconv.FormatInt(now, 10)}).Result()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, taskId := range taskIds {
+		var dl *DeadLetter
+		var reaped bool
+		for i := 0; i < reapRetries; i++ {
+			dl, reaped, err = s.reapLease(ctx, taskId, now)
+			if err != redis.TxFailedErr {
+				break
+			}
+		}
+		if err == redis.TxFailedErr {
+			continue
+		}
+		if err != nil {
+			return requeued, dead, err
+		}
+		if !reaped {
+			continue
+		}
+		if dl == nil {
+			requeued++
+			continue
+		}
+		dead++
+
+		s.mu.Lock()
+		handler := s.deadLetterHandler
+		s.mu.Unlock()
+		if handler != nil {
+			handler(ctx, dl)
+		}
+	}
+	return requeued, dead, nil
+}
+
+// reapLease 回收单个过期租约。重新入队或写入死信队列与删除租约在同一事务中提交，
+// 任一步失败时租约保持不变，下一轮回收会再次处理。
+// 租约已被续约、释放或被其他调度实例回收时 reaped 为 false；移入死信队列时返回死信
+func (s *Scheduler) reapLease(ctx context.Context, taskId string, now int64) (dl *DeadLetter, reaped bool, err error) {
+	err = s.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		score, err := tx.ZScore(ctx, s.leaseKey, taskId).Result()
+		if err == redis.Nil {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if int64(score) > now {
+			return nil
+		}
+
+		var lease LeaseInfo
+		data, err := tx.HGet(ctx, s.leaseDataKey, taskId).Result()
+		if err != nil && err != redis.Nil {
+			return err
+		}
+		if data == "" || json.Unmarshal([]byte(data), &lease) != nil || lease.Task == nil {
+			// 租约详情缺失或损坏，无法恢复任务，只删除租约
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.ZRem(ctx, s.leaseKey, taskId)
+				pipe.HDel(ctx, s.leaseDataKey, taskId)
+				return nil
+			})
+			return err
+		}
+
+		task := lease.Task
+		task.Attempt++
+		var write func(pipe redis.Pipeliner)
+		if task.Attempt >= s.maxAttempts {
+			dl = &DeadLetter{
+				Task:     task,
+				Worker:   lease.Worker,
+				Attempts: task.Attempt,
+				Reason:   fmt.Sprintf("lease expired on worker %s", lease.Worker),
+				DeadTime: time.Now().Local().Format("2006-01-02 15:04:05"),
+			}
+			dlData, err := json.Marshal(dl)
+			if err != nil {
+				return err
+			}
+			write = func(pipe redis.Pipeliner) {
+				pipe.HSet(ctx, s.deadLetterKey, taskId, dlData)
+			}
+		} else {
+			z, err := queueEntry(task)
+			if err != nil {
+				return err
+			}
+			write = func(pipe redis.Pipeliner) {
+				s.queueTask(ctx, pipe, task, z)
+			}
+		}
+
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.ZRem(ctx, s.leaseKey, taskId)
+			pipe.HDel(ctx, s.leaseDataKey, taskId)
+			write(pipe)
+			return nil
+		})
+		if err != nil {
+			dl = nil
+			return err
+		}
+		reaped = true
+		return nil
+	}, s.leaseKey, s.leaseDataKey)
+	if !reaped {
+		dl = nil
+	}
+	return dl, reaped, err
+}
+
+// ListDeadLetters 获取死信任务列表（按进入时间倒序）
+func (s *Scheduler) ListDeadLetters(ctx context.Context) ([]*DeadLetter, error) {
+	values, err := s.rdb.HGetAll(ctx, s.deadLetterKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]*DeadLetter, 0, len(values))
+	for _, v := range values {
+		var dl DeadLetter
+		if err := json.Unmarshal([]byte(v), &dl); err != nil || dl.Task == nil {
+			continue
+		}
+		list = append(list, &dl)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].DeadTime > list[j].DeadTime
+	})
+	return list, nil
+}
+
+// GetDeadLetterCount 获取死信任务数
+func (s *Scheduler) GetDeadLetterCount(ctx context.Context) (int64, error) {
+	return s.rdb.HLen(ctx, s.deadLetterKey).Result()
+}
+
+// GetDeadLetter 获取死信任务，不存在时返回 nil
+func (s *Scheduler) GetDeadLetter(ctx context.Context, taskId string) (*DeadLetter, error) {
+	data, err := s.rdb.HGet(ctx, s.deadLetterKey, taskId).Result()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var dl DeadLetter
+	if err := json.Unmarshal([]byte(data), &dl); err != nil || dl.Task == nil {
+		return nil, fmt.Errorf("invalid dead letter %s", taskId)
+	}
+	return &dl, nil
+}
+
+// ReplayDeadLetter 重放死信任务，重置投递次数后重新入队
+// 删除死信与入队在同一事务中提交，并发重放时只有一个请求成功
+func (s *Scheduler) ReplayDeadLetter(ctx context.Context, taskId string) (*TaskInfo, error) {
+	var task *TaskInfo
+	err := s.rdb.Watch(ctx, func(tx *redis.Tx) error {
+		data, err := tx.HGet(ctx, s.deadLetterKey, taskId).Result()
+		if err == redis.Nil {
+			return fmt.Errorf("dead letter %s not found", taskId)
+		}
+		if err != nil {
+			return err
+		}
+		var dl DeadLetter
+		if err := json.Unmarshal([]byte(data), &dl); err != nil || dl.Task == nil {
+			return fmt.Errorf("invalid dead letter %s", taskId)
+		}
+
+		dl.Task.Attempt = 0
+		z, err := queueEntry(dl.Task)
+		if err != nil {
+			return err
+		}
+		_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+			pipe.HDel(ctx, s.deadLetterKey, taskId)
+			s.queueTask(ctx, pipe, dl.Task, z)
+			return nil
+		})
+		if err == nil {
+			task = dl.Task
+		}
+		return err
+	}, s.deadLetterKey)
+	if err == redis.TxFailedErr {
+		return nil, fmt.Errorf("dead letter %s was modified concurrently", taskId)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return task, nil
+}
+
+// DeleteDeadLetter 删除死信任务
+func (s *Scheduler) DeleteDeadLetter(ctx context.Context, taskIds ...string) (int64, error) {
+	if len(taskIds) == 0 {
+		return 0, nil
+	}
+	return s.rdb.HDel(ctx, s.deadLetterKey, taskIds...).Result()
+}
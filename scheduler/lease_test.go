@@ -0,0 +1,257 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newLeaseTestScheduler(t *testing.T) (*Scheduler, *miniredis.Miniredis) {
+	t.Helper()
+	mr, err := miniredis.Run()
+	if err != nil {
+		t.Fatalf("miniredis: %v", err)
+	}
+	t.Cleanup(mr.Close)
+	return NewScheduler(redis.NewClient(&redis.Options{Addr: mr.Addr()})), mr
+}
+
+// expireLease 将租约过期时间改到过去
+func expireLease(t *testing.T, s *Scheduler, taskId string) {
+	t.Helper()
+	ctx := context.Background()
+	if err := s.rdb.ZAdd(ctx, s.leaseKey, redis.Z{Score: float64(time.Now().Add(-time.Minute).Unix()), Member: taskId}).Err(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestPopTaskCreatesLease 测试出队时同时创建租约
+func TestPopTaskCreatesLease(t *testing.T) {
+	s, _ := newLeaseTestScheduler(t)
+	ctx := context.Background()
+
+	if task, err := s.PopTask(ctx); err != nil || task != nil {
+		t.Fatalf("PopTask() on empty queue = %v, %v, want nil, nil", task, err)
+	}
+
+	if err := s.PushTask(ctx, &TaskInfo{TaskId: "t1", Config: "{}"}); err != nil {
+		t.Fatal(err)
+	}
+	task, err := s.PopTaskFromQueue(ctx, s.queueKey, "worker-1")
+	if err != nil {
+		t.Fatalf("PopTaskFromQueue: %v", err)
+	}
+	if task == nil || task.TaskId != "t1" || task.Config != "{}" {
+		t.Fatalf("PopTaskFromQueue() = %+v, want t1", task)
+	}
+	if n, _ := s.GetQueueLength(ctx); n != 0 {
+		t.Errorf("queue length = %d, want 0", n)
+	}
+
+	lease, err := s.GetLease(ctx, "t1")
+	if err != nil {
+		t.Fatalf("GetLease: %v", err)
+	}
+	if lease == nil || lease.Worker != "worker-1" || lease.Task == nil || lease.Task.TaskId != "t1" || lease.LeaseTime == "" {
+		t.Fatalf("lease = %+v, want worker-1 holding t1", lease)
+	}
+	score, err := s.rdb.ZScore(ctx, s.leaseKey, "t1").Result()
+	if err != nil {
+		t.Fatalf("lease score: %v", err)
+	}
+	if expire := time.Unix(int64(score), 0); expire.Before(time.Now().Add(DefaultLeaseTimeout - time.Minute)) {
+		t.Errorf("lease expires at %v, want about %v from now", expire, DefaultLeaseTimeout)
+	}
+}
+
+// TestRenewLeases 测试续约只更新仍然存在的租约
+func TestRenewLeases(t *testing.T) {
+	s, _ := newLeaseTestScheduler(t)
+	ctx := context.Background()
+
+	if err := s.LeaseTask(ctx, &TaskInfo{TaskId: "t1"}, "worker-1"); err != nil {
+		t.Fatal(err)
+	}
+	expireLease(t, s, "t1")
+
+	renewed, err := s.RenewLeases(ctx, []string{"t1", "gone"})
+	if err != nil {
+		t.Fatalf("RenewLeases: %v", err)
+	}
+	if renewed != 1 {
+		t.Errorf("renewed = %d, want 1", renewed)
+	}
+	if score, _ := s.rdb.ZScore(ctx, s.leaseKey, "t1").Result(); int64(score) <= time.Now().Unix() {
+		t.Errorf("lease not extended: expires at %d", int64(score))
+	}
+	if _, err := s.rdb.ZScore(ctx, s.leaseKey, "gone").Result(); err != redis.Nil {
+		t.Errorf("renew created a lease for a released task: err = %v", err)
+	}
+}
+
+// TestReapExpiredLeases 测试过期租约重新入队，超过最大投递次数后进入死信队列
+func TestReapExpiredLeases(t *testing.T) {
+	s, _ := newLeaseTestScheduler(t)
+	s.SetLeasePolicy(time.Minute, 2)
+	ctx := context.Background()
+
+	var handled []*DeadLetter
+	s.SetDeadLetterHandler(func(ctx context.Context, dl *DeadLetter) {
+		handled = append(handled, dl)
+	})
+
+	if err := s.PushTask(ctx, &TaskInfo{TaskId: "t1"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.LeaseTask(ctx, &TaskInfo{TaskId: "live"}, "worker-2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// 第一次过期：重新入队
+	if _, err := s.PopTaskFromQueue(ctx, s.queueKey, "worker-1"); err != nil {
+		t.Fatal(err)
+	}
+	expireLease(t, s, "t1")
+	requeued, dead, err := s.ReapExpiredLeases(ctx)
+	if err != nil {
+		t.Fatalf("ReapExpiredLeases: %v", err)
+	}
+	if requeued != 1 || dead != 0 {
+		t.Fatalf("reap = %d requeued, %d dead, want 1, 0", requeued, dead)
+	}
+	if lease, _ := s.GetLease(ctx, "t1"); lease != nil {
+		t.Errorf("expired lease still present: %+v", lease)
+	}
+	if lease, _ := s.GetLease(ctx, "live"); lease == nil {
+		t.Error("unexpired lease was reaped")
+	}
+
+	// 第二次过期：达到最大投递次数，进入死信队列
+	task, err := s.PopTaskFromQueue(ctx, s.queueKey, "worker-1")
+	if err != nil || task == nil {
+		t.Fatalf("requeued task not found: %v, %v", task, err)
+	}
+	if task.Attempt != 1 {
+		t.Errorf("attempt = %d, want 1", task.Attempt)
+	}
+	expireLease(t, s, "t1")
+	requeued, dead, err = s.ReapExpiredLeases(ctx)
+	if err != nil {
+		t.Fatalf("ReapExpiredLeases: %v", err)
+	}
+	if requeued != 0 || dead != 1 {
+		t.Fatalf("reap = %d requeued, %d dead, want 0, 1", requeued, dead)
+	}
+	if n, _ := s.GetQueueLength(ctx); n != 0 {
+		t.Errorf("queue length = %d, want 0", n)
+	}
+	dl, err := s.GetDeadLetter(ctx, "t1")
+	if err != nil || dl == nil {
+		t.Fatalf("dead letter not found: %v, %v", dl, err)
+	}
+	if dl.Worker != "worker-1" || dl.Attempts != 2 {
+		t.Errorf("dead letter = %+v, want worker-1 after 2 attempts", dl)
+	}
+	if len(handled) != 1 || handled[0].Task.TaskId != "t1" {
+		t.Errorf("dead letter handler calls = %v", handled)
+	}
+}
+
+// TestReapKeepsLeaseOnError 测试回收失败时租约保持不变，任务不会丢失
+func TestReapKeepsLeaseOnError(t *testing.T) {
+	s, mr := newLeaseTestScheduler(t)
+	ctx := context.Background()
+
+	if err := s.LeaseTask(ctx, &TaskInfo{TaskId: "t1"}, "worker-1"); err != nil {
+		t.Fatal(err)
+	}
+	expireLease(t, s, "t1")
+
+	now := time.Now().Unix()
+	mr.SetError("LOADING Redis is loading the dataset in memory")
+	if _, _, err := s.reapLease(ctx, "t1", now); err == nil {
+		t.Fatal("reapLease() succeeded while Redis was failing")
+	}
+	mr.SetError("")
+
+	if lease, _ := s.GetLease(ctx, "t1"); lease == nil {
+		t.Fatal("lease removed by a failed reap")
+	}
+	requeued, _, err := s.ReapExpiredLeases(ctx)
+	if err != nil || requeued != 1 {
+		t.Fatalf("retry reap = %d, %v, want 1 requeued", requeued, err)
+	}
+}
+
+// TestReapRenewedLease 测试租约在回收前被续约时不回收
+func TestReapRenewedLease(t *testing.T) {
+	s, _ := newLeaseTestScheduler(t)
+	ctx := context.Background()
+
+	if err := s.LeaseTask(ctx, &TaskInfo{TaskId: "t1"}, "worker-1"); err != nil {
+		t.Fatal(err)
+	}
+	// 回收时间点早于租约过期时间，模拟查询过期租约后 Worker 完成续约
+	dl, reaped, err := s.reapLease(ctx, "t1", time.Now().Unix())
+	if err != nil || reaped || dl != nil {
+		t.Fatalf("reapLease() = %v, %v, %v, want not reaped", dl, reaped, err)
+	}
+	if lease, _ := s.GetLease(ctx, "t1"); lease == nil {
+		t.Fatal("renewed lease was removed")
+	}
+}
+
+// TestReapRequeuesToLabelQueue 测试回收的任务按标签选择器重新进入标签队列
+func TestReapRequeuesToLabelQueue(t *testing.T) {
+	s, _ := newLeaseTestScheduler(t)
+	ctx := context.Background()
+
+	if err := s.LeaseTask(ctx, &TaskInfo{TaskId: "t1", Requires: []string{"gpu"}}, "worker-1"); err != nil {
+		t.Fatal(err)
+	}
+	expireLease(t, s, "t1")
+	if requeued, _, err := s.ReapExpiredLeases(ctx); err != nil || requeued != 1 {
+		t.Fatalf("reap = %d, %v, want 1 requeued", requeued, err)
+	}
+	if n, _ := s.rdb.ZCard(ctx, s.GetLabelQueueKey("gpu")).Result(); n != 1 {
+		t.Errorf("label queue length = %d, want 1", n)
+	}
+	if n, _ := s.GetQueueLength(ctx); n != 0 {
+		t.Errorf("public queue length = %d, want 0", n)
+	}
+}
+
+// TestReplayDeadLetter 测试重放死信任务重置投递次数并重新入队，重复重放失败
+func TestReplayDeadLetter(t *testing.T) {
+	s, _ := newLeaseTestScheduler(t)
+	s.SetLeasePolicy(time.Minute, 1)
+	ctx := context.Background()
+
+	if err := s.LeaseTask(ctx, &TaskInfo{TaskId: "t1"}, "worker-1"); err != nil {
+		t.Fatal(err)
+	}
+	expireLease(t, s, "t1")
+	if _, dead, err := s.ReapExpiredLeases(ctx); err != nil || dead != 1 {
+		t.Fatalf("reap = %d dead, %v, want 1", dead, err)
+	}
+
+	task, err := s.ReplayDeadLetter(ctx, "t1")
+	if err != nil {
+		t.Fatalf("ReplayDeadLetter: %v", err)
+	}
+	if task.Attempt != 0 {
+		t.Errorf("attempt = %d, want 0", task.Attempt)
+	}
+	if n, _ := s.GetDeadLetterCount(ctx); n != 0 {
+		t.Errorf("dead letter count = %d, want 0", n)
+	}
+	if n, _ := s.GetQueueLength(ctx); n != 1 {
+		t.Errorf("queue length = %d, want 1", n)
+	}
+	if _, err := s.ReplayDeadLetter(ctx, "t1"); err == nil {
+		t.Error("second replay succeeded")
+	}
+}
@@ -0,0 +1,166 @@
+package scheduler
+
+import "strings"
+
+// 阶段需要的工具标签，与 Worker 为已安装工具附加的 tool=<工具名> 标签一致
+const (
+	LabelToolMasscan  = LabelTool + "=masscan"
+	LabelToolNmap     = LabelTool + "=nmap"
+	LabelToolChromium = LabelTool + "=chromium"
+)
+
+// phaseOrder 阶段执行顺序，与 Worker 执行任务的顺序一致
+var phaseOrder = []string{
+	"domainscan", "cdn", "portscan",
+	"portidentify", "tlsscan", "fingerprint", "crawler", "dirscan", "brute", "pocscan",
+}
+
+// subTaskModules 计入子任务数的阶段，与启动任务时计算子任务数的模块一致
+var subTaskModules = map[string]bool{
+	"domainscan":   true,
+	"portscan":     true,
+	"portidentify": true,
+	"fingerprint":  true,
+	"crawler":      true,
+	"brute":        true,
+	"pocscan":      true,
+}
+
+// PhaseGroup 按阶段分布式执行时在同一个子任务中执行的连续阶段
+type PhaseGroup struct {
+	Phases   []string `json:"phases"`
+	Requires []string `json:"requires,omitempty"` // 执行该组需要的 Worker 工具标签
+}
+
+// Modules 组内计入子任务数的阶段数
+func (g PhaseGroup) Modules() int {
+	n := 0
+	for _, phase := range g.Phases {
+		if subTaskModules[phase] {
+			n++
+		}
+	}
+	return n
+}
+
+// PhaseEnabled 阶段是否启用
+func (c *TaskConfig) PhaseEnabled(phase string) bool {
+	switch phase {
+	case "domainscan":
+		return c.DomainScan != nil && c.DomainScan.Enable
+	case "cdn":
+		return c.CDN != nil && c.CDN.Enable
+	case "portscan":
+		return c.PortScan != nil && c.PortScan.Enable
+	case "portidentify":
+		return c.PortIdentify != nil && c.PortIdentify.Enable
+	case "tlsscan":
+		return c.TLSScan != nil && c.TLSScan.Enable
+	case "fingerprint":
+		return c.Fingerprint != nil && c.Fingerprint.Enable
+	case "crawler":
+		return c.Crawler != nil && c.Crawler.Enable
+	case "dirscan":
+		return c.DirScan != nil && c.DirScan.Enable
+	case "brute":
+		return c.Brute != nil && c.Brute.Enable
+	case "pocscan":
+		return c.PocScan != nil && c.PocScan.Enable
+	}
+	return false
+}
+
+// phaseRequires 阶段需要的 Worker 工具标签
+func (c *TaskConfig) phaseRequires(phase string) []string {
+	switch phase {
+	case "portscan":
+		if c.PortScan.Tool == "masscan" {
+			return []string{LabelToolMasscan}
+		}
+	case "portidentify":
+		if c.PortIdentify.Tool == "" || c.PortIdentify.Tool == "nmap" {
+			return []string{LabelToolNmap}
+		}
+	case "fingerprint":
+		if c.Fingerprint.Screenshot {
+			return []string{LabelToolChromium}
+		}
+	case "crawler":
+		if c.Crawler.Headless {
+			return []string{LabelToolChromium}
+		}
+	}
+	return nil
+}
+
+// PhaseGroups 按阶段分布式执行时的阶段分组
+// 端口扫描及之前的阶段（子域名、CDN识别）为第一组，之后需要相同工具的连续阶段为一组
+func (c *TaskConfig) PhaseGroups() []PhaseGroup {
+	var groups []PhaseGroup
+	discovery := false // 最后一组是否为发现组
+	for _, phase := range phaseOrder {
+		if !c.PhaseEnabled(phase) {
+			continue
+		}
+		requires := c.phaseRequires(phase)
+		isDiscovery := phase == "domainscan" || phase == "cdn" || phase == "portscan"
+		n := len(groups)
+		switch {
+		case n > 0 && discovery && isDiscovery:
+			groups[n-1].Requires = mergeTerms(groups[n-1].Requires, requires)
+		case n > 0 && !discovery && !isDiscovery && sameTerms(groups[n-1].Requires, requires):
+		default:
+			groups = append(groups, PhaseGroup{Requires: requires})
+			n++
+		}
+		groups[n-1].Phases = append(groups[n-1].Phases, phase)
+		discovery = isDiscovery
+	}
+	return groups
+}
+
+// PhaseGroupIndex 阶段所在的分组下标，阶段未启用时返回 -1
+func PhaseGroupIndex(groups []PhaseGroup, phase string) int {
+	for i, g := range groups {
+		for _, p := range g.Phases {
+			if p == phase {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Requires 执行指定阶段组的子任务需要满足的 Worker 标签选择器
+// 由任务的标签选择器和按阶段分布式执行时阶段组需要的工具标签组成
+func (c *TaskConfig) Requires(group int) []string {
+	requires, _ := ParseSelector(c.WorkerSelector)
+	if c.PhaseSplit && !c.Pipeline.Enabled() {
+		if groups := c.PhaseGroups(); group < len(groups) {
+			requires = mergeTerms(requires, groups[group].Requires)
+		}
+	}
+	return requires
+}
+
+// TaskRequires 新建子任务需要满足的 Worker 标签选择器
+func TaskRequires(configStr string) []string {
+	config, err := ParseTaskConfig(configStr)
+	if err != nil {
+		return nil
+	}
+	return config.Requires(0)
+}
+
+func sameTerms(a, b []string) bool {
+	return strings.Join(a, ",") == strings.Join(b, ",")
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
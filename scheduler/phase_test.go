@@ -0,0 +1,70 @@
+package scheduler
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPhaseGroups(t *testing.T) {
+	config := &TaskConfig{
+		DomainScan:   &DomainScanConfig{Enable: true},
+		PortScan:     &PortScanConfig{Enable: true, Tool: "masscan"},
+		PortIdentify: &PortIdentifyConfig{Enable: true},
+		TLSScan:      &TLSScanConfig{Enable: true},
+		Fingerprint:  &FingerprintConfig{Enable: true, Screenshot: true},
+		Crawler:      &CrawlerConfig{Enable: true, Headless: true},
+		Brute:        &BruteConfig{Enable: true},
+		PocScan:      &PocScanConfig{Enable: true},
+	}
+	want := []PhaseGroup{
+		{Phases: []string{"domainscan", "portscan"}, Requires: []string{LabelToolMasscan}},
+		{Phases: []string{"portidentify"}, Requires: []string{LabelToolNmap}},
+		{Phases: []string{"tlsscan"}},
+		{Phases: []string{"fingerprint", "crawler"}, Requires: []string{LabelToolChromium}},
+		{Phases: []string{"brute", "pocscan"}},
+	}
+	groups := config.PhaseGroups()
+	if !reflect.DeepEqual(groups, want) {
+		t.Fatalf("PhaseGroups() = %+v, want %+v", groups, want)
+	}
+	if got := groups[3].Modules(); got != 2 {
+		t.Errorf("Modules() = %d, want 2", got)
+	}
+	if got := PhaseGroupIndex(groups, "pocscan"); got != 4 {
+		t.Errorf("PhaseGroupIndex(pocscan) = %d, want 4", got)
+	}
+	if got := PhaseGroupIndex(groups, "dirscan"); got != -1 {
+		t.Errorf("PhaseGroupIndex(dirscan) = %d, want -1", got)
+	}
+
+	// 端口扫描未启用时从第一个启用的阶段开始分组
+	config = &TaskConfig{
+		PortIdentify: &PortIdentifyConfig{Enable: true, Tool: "native"},
+		PocScan:      &PocScanConfig{Enable: true},
+	}
+	want = []PhaseGroup{{Phases: []string{"portidentify", "pocscan"}}}
+	if groups := config.PhaseGroups(); !reflect.DeepEqual(groups, want) {
+		t.Errorf("PhaseGroups() = %+v, want %+v", groups, want)
+	}
+}
+
+func TestTaskRequires(t *testing.T) {
+	config := &TaskConfig{
+		PortScan:       &PortScanConfig{Enable: true, Tool: "masscan"},
+		PortIdentify:   &PortIdentifyConfig{Enable: true},
+		WorkerSelector: "zone=dmz, vantage!=external",
+	}
+	if got, want := config.Requires(0), []string{"vantage!=external", "zone=dmz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Requires(0) without phaseSplit = %v, want %v", got, want)
+	}
+	config.PhaseSplit = true
+	if got, want := config.Requires(0), []string{"tool=masscan", "vantage!=external", "zone=dmz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Requires(0) = %v, want %v", got, want)
+	}
+	if got, want := config.Requires(1), []string{"tool=nmap", "vantage!=external", "zone=dmz"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Requires(1) = %v, want %v", got, want)
+	}
+	if got := TaskRequires(`{"portscan":{"enable":true}}`); got != nil {
+		t.Errorf("TaskRequires() = %v, want nil", got)
+	}
+}
@@ -0,0 +1,118 @@
+package scheduler
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// PipelineTarget 输入名称，表示以任务目标作为阶段输入
+const PipelineTarget = "target"
+
+// Pipeline 声明式扫描流水线，配置后替代固定的阶段顺序
+// 阶段之间通过 Inputs 组成有向无环图，没有依赖关系的阶段并行执行
+type Pipeline struct {
+	Stages []PipelineStage `json:"stages"`
+}
+
+// PipelineStage 流水线阶段，每个阶段由一个注册的扫描器执行
+type PipelineStage struct {
+	Name    string          `json:"name"`              // 阶段名称，流水线内唯一
+	Scanner string          `json:"scanner"`           // 扫描器名称，如 naabu/fingerprint/nuclei
+	Inputs  []StageInput    `json:"inputs,omitempty"`  // 上游阶段，为空时以任务目标为输入
+	Options json.RawMessage `json:"options,omitempty"` // 扫描器选项，结构与扫描器选项一致
+	FanOut  int             `json:"fanOut,omitempty"`  // 将输入拆分为多组并行扫描，默认1
+	Timeout int             `json:"timeout,omitempty"` // 阶段总超时(秒)，0为不限制
+}
+
+// StageInput 阶段输入边，When 为空时传递上游全部资产
+type StageInput struct {
+	From string          `json:"from"` // 上游阶段名称或 target
+	When *StageCondition `json:"when,omitempty"`
+}
+
+// StageCondition 输入边条件，字段之间为与关系，字段内多个值为或关系
+type StageCondition struct {
+	Apps       []string `json:"apps,omitempty"`       // 资产指纹包含任一应用，不区分大小写
+	Services   []string `json:"services,omitempty"`   // 服务名
+	Ports      []int    `json:"ports,omitempty"`      // 端口
+	Categories []string `json:"categories,omitempty"` // 资产类型 ipv4/ipv6/domain
+	HTTP       *bool    `json:"http,omitempty"`       // 是否为HTTP服务
+}
+
+// Enabled 是否配置了流水线
+func (p *Pipeline) Enabled() bool {
+	return p != nil && len(p.Stages) > 0
+}
+
+// Levels 校验流水线并按依赖关系分层，同一层的阶段互不依赖，返回阶段下标
+func (p *Pipeline) Levels() ([][]int, error) {
+	index := make(map[string]int, len(p.Stages))
+	for i, stage := range p.Stages {
+		if stage.Name == "" {
+			return nil, fmt.Errorf("第%d个阶段缺少名称", i+1)
+		}
+		if stage.Name == PipelineTarget {
+			return nil, fmt.Errorf("阶段名称不能为保留字 %s", PipelineTarget)
+		}
+		if stage.Scanner == "" {
+			return nil, fmt.Errorf("阶段 %s 缺少扫描器", stage.Name)
+		}
+		if stage.FanOut < 0 || stage.Timeout < 0 {
+			return nil, fmt.Errorf("阶段 %s 的 fanOut/timeout 不能为负数", stage.Name)
+		}
+		if _, ok := index[stage.Name]; ok {
+			return nil, fmt.Errorf("阶段名称重复: %s", stage.Name)
+		}
+		index[stage.Name] = i
+	}
+
+	// 按入度分层（Kahn 算法），剩余未分层的阶段构成环
+	indegree := make([]int, len(p.Stages))
+	next := make([][]int, len(p.Stages))
+	for i, stage := range p.Stages {
+		for _, in := range stage.Inputs {
+			if in.From == PipelineTarget {
+				continue
+			}
+			from, ok := index[in.From]
+			if !ok {
+				return nil, fmt.Errorf("阶段 %s 的输入 %s 不存在", stage.Name, in.From)
+			}
+			if from == i {
+				return nil, fmt.Errorf("阶段 %s 不能以自身为输入", stage.Name)
+			}
+			indegree[i]++
+			next[from] = append(next[from], i)
+		}
+	}
+
+	var levels [][]int
+	var current []int
+	for i, d := range indegree {
+		if d == 0 {
+			current = append(current, i)
+		}
+	}
+	done := 0
+	for len(current) > 0 {
+		levels = append(levels, current)
+		done += len(current)
+		var following []int
+		for _, i := range current {
+			for _, j := range next[i] {
+				indegree[j]--
+				if indegree[j] == 0 {
+					following = append(following, j)
+				}
+			}
+		}
+		// 同一层按声明顺序执行
+		sort.Ints(following)
+		current = following
+	}
+	if done != len(p.Stages) {
+		return nil, fmt.Errorf("流水线存在循环依赖")
+	}
+	return levels, nil
+}
@@ -0,0 +1,48 @@
+package scheduler
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPipelineLevels(t *testing.T) {
+	p := &Pipeline{Stages: []PipelineStage{
+		{Name: "poc", Scanner: "nuclei", Inputs: []StageInput{{From: "fp", When: &StageCondition{Apps: []string{"shiro"}}}}},
+		{Name: "port", Scanner: "naabu"},
+		{Name: "sub", Scanner: "subfinder"},
+		{Name: "fp", Scanner: "fingerprint", Inputs: []StageInput{{From: "port"}, {From: "sub"}}},
+		{Name: "brute", Scanner: "brute", Inputs: []StageInput{{From: "port"}, {From: PipelineTarget}}},
+	}}
+	levels, err := p.Levels()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]int{{1, 2}, {3, 4}, {0}}
+	if !reflect.DeepEqual(levels, want) {
+		t.Errorf("Levels() = %v, want %v", levels, want)
+	}
+}
+
+func TestPipelineLevelsInvalid(t *testing.T) {
+	tests := []struct {
+		stages []PipelineStage
+		want   string
+	}{
+		{[]PipelineStage{{Name: "a", Scanner: "naabu"}, {Name: "a", Scanner: "nuclei"}}, "重复"},
+		{[]PipelineStage{{Name: "a"}}, "缺少扫描器"},
+		{[]PipelineStage{{Name: "target", Scanner: "naabu"}}, "保留字"},
+		{[]PipelineStage{{Name: "a", Scanner: "naabu", Inputs: []StageInput{{From: "b"}}}}, "不存在"},
+		{[]PipelineStage{{Name: "a", Scanner: "naabu", Inputs: []StageInput{{From: "a"}}}}, "自身"},
+		{[]PipelineStage{
+			{Name: "a", Scanner: "naabu", Inputs: []StageInput{{From: "b"}}},
+			{Name: "b", Scanner: "nuclei", Inputs: []StageInput{{From: "a"}}},
+		}, "循环"},
+	}
+	for _, tt := range tests {
+		_, err := (&Pipeline{Stages: tt.stages}).Levels()
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("Levels(%+v) error = %v, want %q", tt.stages, err, tt.want)
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -32,17 +33,25 @@ type TaskInfo struct {
 	Config      string   `json:"config"`
 	Priority    int      `json:"priority"`
 	CreateTime  string   `json:"createTime"`
-	Workers     []string `json:"workers,omitempty"` // 指定执行任务的 Worker 列表，为空表示任意 Worker
+	Workers     []string `json:"workers,omitempty"`  // 指定执行任务的 Worker 列表，为空表示任意 Worker
+	Requires    []string `json:"requires,omitempty"` // Worker 标签选择器条件，指定 Workers 时忽略
+	Attempt     int      `json:"attempt,omitempty"`  // 租约过期后的重新投递次数
 }
 
 // Scheduler 任务调度器
 type Scheduler struct {
-	rdb         *redis.Client
-	cron        *cron.Cron
-	queueKey    string
-	processingKey string
-	mu          sync.Mutex
-	handlers    map[string]TaskHandler
+	rdb               *redis.Client
+	cron              *cron.Cron
+	queueKey          string
+	labelQueuesKey    string // 标签队列集合 SET，成员为排序后以逗号连接的选择器条件
+	leaseKey          string // 租约 ZSET，分数为租约过期时间
+	leaseDataKey      string // 租约详情 HASH
+	deadLetterKey     string // 死信队列 HASH
+	leaseTimeout      time.Duration
+	maxAttempts       int
+	mu                sync.Mutex
+	deadLetterHandler DeadLetterHandler
+	handlers          map[string]TaskHandler
 }
 
 // TaskHandler 任务处理函数
@@ -51,11 +60,16 @@ type TaskHandler func(ctx context.Context, task *TaskInfo) error
 // NewScheduler 创建调度器
 func NewScheduler(rdb *redis.Client) *Scheduler {
 	return &Scheduler{
-		rdb:           rdb,
-		cron:          cron.New(cron.WithSeconds()),
-		queueKey:      "cscan:task:queue",
-		processingKey: "cscan:task:processing",
-		handlers:      make(map[string]TaskHandler),
+		rdb:            rdb,
+		cron:           cron.New(cron.WithSeconds()),
+		queueKey:       "cscan:task:queue",
+		labelQueuesKey: "cscan:task:queue:labels",
+		leaseKey:       "cscan:task:lease",
+		leaseDataKey:   "cscan:task:lease:data",
+		deadLetterKey:  "cscan:task:deadletter",
+		leaseTimeout:   DefaultLeaseTimeout,
+		maxAttempts:    DefaultMaxAttempts,
+		handlers:       make(map[string]TaskHandler),
 	}
 }
 
@@ -93,8 +107,21 @@ func (s *Scheduler) GetWorkerQueueKey(workerName string) string {
 
 // PushTask 推送任务到队列
 // 如果任务指定了 Workers，则推送到每个 Worker 的专属队列
-// 否则推送到公共队列
+// 如果任务指定了 Requires，则推送到标签队列，否则推送到公共队列
 func (s *Scheduler) PushTask(ctx context.Context, task *TaskInfo) error {
+	z, err := queueEntry(task)
+	if err != nil {
+		return err
+	}
+
+	pipe := s.rdb.Pipeline()
+	s.queueTask(ctx, pipe, task, z)
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// queueEntry 生成任务的队列成员，未设置 TaskId 时自动生成
+func queueEntry(task *TaskInfo) (redis.Z, error) {
 	if task.TaskId == "" {
 		task.TaskId = uuid.New().String()
 	}
@@ -102,36 +129,53 @@ func (s *Scheduler) PushTask(ctx context.Context, task *TaskInfo) error {
 
 	data, err := json.Marshal(task)
 	if err != nil {
-		return err
+		return redis.Z{}, err
 	}
 
 	// 使用优先级队列，分数越小优先级越高
 	score := float64(time.Now().Unix()) - float64(task.Priority*1000)
+	return redis.Z{Score: score, Member: data}, nil
+}
 
-	// 如果指定了 Workers，推送到每个 Worker 的专属队列
-	if len(task.Workers) > 0 {
-		pipe := s.rdb.Pipeline()
+// queueTask 按任务的 Workers 和 Requires 选择队列：Worker 专属队列、标签队列或公共队列
+func (s *Scheduler) queueTask(ctx context.Context, pipe redis.Pipeliner, task *TaskInfo, z redis.Z) {
+	switch {
+	case len(task.Workers) > 0:
 		for _, workerName := range task.Workers {
-			workerQueueKey := s.GetWorkerQueueKey(workerName)
-			pipe.ZAdd(ctx, workerQueueKey, redis.Z{
-				Score:  score,
-				Member: data,
-			})
+			pipe.ZAdd(ctx, s.GetWorkerQueueKey(workerName), z)
 		}
-		_, err = pipe.Exec(ctx)
-		return err
+	case len(task.Requires) > 0:
+		selector := selectorKey(task.Requires)
+		pipe.SAdd(ctx, s.labelQueuesKey, selector)
+		pipe.ZAdd(ctx, s.GetLabelQueueKey(selector), z)
+	default:
+		pipe.ZAdd(ctx, s.queueKey, z)
 	}
+}
 
-	// 没有指定 Worker，推送到公共队列
-	return s.rdb.ZAdd(ctx, s.queueKey, redis.Z{
-		Score:  score,
-		Member: data,
-	}).Err()
+// GetLabelQueueKey 获取标签队列的 Key，选择器相同的任务进入同一队列
+func (s *Scheduler) GetLabelQueueKey(selector string) string {
+	return "cscan:task:queue:label:" + selector
+}
+
+// GetLabelQueueKeys 获取 Worker 标签满足选择器的标签队列
+func (s *Scheduler) GetLabelQueueKeys(ctx context.Context, labels []string) ([]string, error) {
+	members, err := s.rdb.SMembers(ctx, s.labelQueuesKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(members)
+	var keys []string
+	for _, selector := range members {
+		if MatchSelector(strings.Split(selector, ","), labels) {
+			keys = append(keys, s.GetLabelQueueKey(selector))
+		}
+	}
+	return keys, nil
 }
 
 // PushTaskBatch 批量推送任务到队列（使用 Pipeline 提高性能）
-// 如果任务指定了 Workers，则推送到每个 Worker 的专属队列
-// 否则推送到公共队列
+// 队列选择规则与 PushTask 一致
 func (s *Scheduler) PushTaskBatch(ctx context.Context, tasks []*TaskInfo) error {
 	if len(tasks) == 0 {
 		return nil
@@ -155,53 +199,21 @@ func (s *Scheduler) PushTaskBatch(ctx context.Context, tasks []*TaskInfo) error
 		// 同一批次的任务按顺序递增分数，保持顺序
 		score := float64(baseTime.Unix()) - float64(task.Priority*1000) + float64(i)*0.001
 
-		// 如果指定了 Workers，推送到每个 Worker 的专属队列
-		if len(task.Workers) > 0 {
-			for _, workerName := range task.Workers {
-				workerQueueKey := s.GetWorkerQueueKey(workerName)
-				pipe.ZAdd(ctx, workerQueueKey, redis.Z{
-					Score:  score,
-					Member: data,
-				})
-			}
-		} else {
-			// 没有指定 Worker，推送到公共队列
-			pipe.ZAdd(ctx, s.queueKey, redis.Z{
-				Score:  score,
-				Member: data,
-			})
-		}
+		s.queueTask(ctx, pipe, task, redis.Z{Score: score, Member: data})
 	}
 
 	_, err := pipe.Exec(ctx)
 	return err
 }
 
-// PopTask 从队列获取任务
+// PopTask 从公共队列获取任务
 func (s *Scheduler) PopTask(ctx context.Context) (*TaskInfo, error) {
-	// 获取优先级最高的任务
-	results, err := s.rdb.ZPopMin(ctx, s.queueKey, 1).Result()
-	if err != nil {
-		return nil, err
-	}
-	if len(results) == 0 {
-		return nil, nil
-	}
-
-	var task TaskInfo
-	if err := json.Unmarshal([]byte(results[0].Member.(string)), &task); err != nil {
-		return nil, err
-	}
-
-	// 添加到处理中集合
-	s.rdb.SAdd(ctx, s.processingKey, task.TaskId)
-
-	return &task, nil
+	return s.PopTaskFromQueue(ctx, s.queueKey, "")
 }
 
-// CompleteTask 完成任务
+// CompleteTask 完成任务，释放租约
 func (s *Scheduler) CompleteTask(ctx context.Context, taskId string) error {
-	return s.rdb.SRem(ctx, s.processingKey, taskId).Err()
+	return s.ReleaseLease(ctx, taskId)
 }
 
 // GetQueueLength 获取队列长度
@@ -209,19 +221,73 @@ func (s *Scheduler) GetQueueLength(ctx context.Context) (int64, error) {
 	return s.rdb.ZCard(ctx, s.queueKey).Result()
 }
 
-// GetProcessingCount 获取处理中任务数
+// GetProcessingCount 获取处理中任务数（持有租约的任务数）
 func (s *Scheduler) GetProcessingCount(ctx context.Context) (int64, error) {
-	return s.rdb.SCard(ctx, s.processingKey).Result()
+	return s.rdb.ZCard(ctx, s.leaseKey).Result()
 }
 
 // TaskConfig 任务配置
 type TaskConfig struct {
-	PortScan     *PortScanConfig     `json:"portscan,omitempty"`
-	PortIdentify *PortIdentifyConfig `json:"portidentify,omitempty"` // 端口识别（Nmap服务识别）
-	DomainScan   *DomainScanConfig   `json:"domainscan,omitempty"`
-	Fingerprint  *FingerprintConfig  `json:"fingerprint,omitempty"`
-	PocScan      *PocScanConfig      `json:"pocscan,omitempty"`
-	DirScan      *DirScanConfig      `json:"dirscan,omitempty"` // 目录扫描
+	PortScan       *PortScanConfig     `json:"portscan,omitempty"`
+	PortIdentify   *PortIdentifyConfig `json:"portidentify,omitempty"` // 端口识别（Nmap服务识别）
+	DomainScan     *DomainScanConfig   `json:"domainscan,omitempty"`
+	Fingerprint    *FingerprintConfig  `json:"fingerprint,omitempty"`
+	PocScan        *PocScanConfig      `json:"pocscan,omitempty"`
+	DirScan        *DirScanConfig      `json:"dirscan,omitempty"`        // 目录扫描
+	TLSScan        *TLSScanConfig      `json:"tlsscan,omitempty"`        // TLS证书采集
+	Brute          *BruteConfig        `json:"brute,omitempty"`          // 弱口令爆破
+	Crawler        *CrawlerConfig      `json:"crawler,omitempty"`        // 网页爬虫
+	CDN            *CDNConfig          `json:"cdn,omitempty"`            // CDN/WAF/云厂商识别
+	Pipeline       *Pipeline           `json:"pipeline,omitempty"`       // 声明式流水线，配置后忽略以上阶段开关
+	PhaseSplit     bool                `json:"phaseSplit,omitempty"`     // 按阶段分布式执行，端口扫描后将资产交给具备相应能力的 Worker
+	PhaseGroup     int                 `json:"phaseGroup,omitempty"`     // 当前子任务执行的阶段组下标，由阶段交接设置
+	WorkerSelector string              `json:"workerSelector,omitempty"` // Worker 标签选择器，如 zone=dmz,tool=masscan
+}
+
+// CDNConfig CDN/WAF/云厂商识别配置
+type CDNConfig struct {
+	Enable          bool `json:"enable"`
+	ASN             bool `json:"asn"`             // 查询IP所属ASN识别云厂商
+	Headers         bool `json:"headers"`         // 通过HTTP响应头、Cookie和拦截页识别
+	SkipPortScan    bool `json:"skipPortScan"`    // CDN节点不做端口扫描，只探测80/443
+	OriginDiscovery bool `json:"originDiscovery"` // 通过历史解析和证书查找源站IP
+	UpdateRanges    bool `json:"updateRanges"`    // 扫描前从厂商官网更新CDN节点IP段
+}
+
+// CrawlerConfig 网页爬虫配置
+type CrawlerConfig struct {
+	Enable         bool   `json:"enable"`
+	MaxDepth       int    `json:"maxDepth"`       // 最大爬取深度，默认3
+	MaxPages       int    `json:"maxPages"`       // 每个站点最多抓取的页面数，默认200
+	Scope          string `json:"scope"`          // 爬取范围 site/host/domain，默认site
+	Threads        int    `json:"threads"`        // 并发请求数，默认10
+	Timeout        int    `json:"timeout"`        // 单个请求超时(秒)，默认10
+	Forms          bool   `json:"forms"`          // 提交GET表单，POST表单只记录地址
+	ParseJS        bool   `json:"parseJs"`        // 解析JS中的接口、敏感信息和子域名
+	Headless       bool   `json:"headless"`       // 使用浏览器渲染SPA路由
+	SaveSubdomains bool   `json:"saveSubdomains"` // 将JS中发现的子域名保存为域名资产
+}
+
+// BruteConfig 弱口令爆破配置
+type BruteConfig struct {
+	Enable           bool     `json:"enable"`
+	Services         []string `json:"services"`         // 限定爆破的服务，空为全部
+	UsernameDictIds  []string `json:"usernameDictIds"`  // 用户名字典ID，为空使用内置字典
+	PasswordDictIds  []string `json:"passwordDictIds"`  // 密码字典ID，为空使用内置字典
+	Threads          int      `json:"threads"`          // 同时爆破的目标数，默认10
+	Timeout          int      `json:"timeout"`          // 单次登录超时(秒)，默认5秒
+	Interval         int      `json:"interval"`         // 同一目标两次尝试的间隔(毫秒)
+	MaxAttempts      int      `json:"maxAttempts"`      // 单个目标最大尝试次数，0为不限
+	LockoutThreshold int      `json:"lockoutThreshold"` // SMB/RDP/MSSQL 单个用户名最大尝试次数，默认3，-1不限
+	StopOnSuccess    bool     `json:"stopOnSuccess"`    // 目标发现一组口令后停止
+}
+
+// TLSScanConfig TLS证书采集配置
+type TLSScanConfig struct {
+	Enable     bool `json:"enable"`
+	Timeout    int  `json:"timeout"`    // 单次握手超时(秒)，默认5秒
+	Jarm       bool `json:"jarm"`       // 计算JARM风格指纹，每个端口额外握手7次
+	SanDomains bool `json:"sanDomains"` // 将证书SAN中的域名保存为新的域名资产
 }
 
 // DirScanConfig 目录扫描配置
@@ -244,11 +310,13 @@ type PortScanConfig struct {
 	PortThreshold     int    `json:"portThreshold"`     // 开放端口数量阈值，超过则过滤该主机
 	ScanType          string `json:"scanType"`          // s=SYN, c=CONNECT，默认 c
 	SkipHostDiscovery bool   `json:"skipHostDiscovery"` // 跳过主机发现 (-Pn)
+	UdpPorts          string `json:"udpPorts"`          // UDP端口，为空不扫描UDP，如 53,69,123,161,623,1900
 }
 
-// PortIdentifyConfig 端口识别配置（Nmap服务识别）
+// PortIdentifyConfig 端口识别配置（服务识别）
 type PortIdentifyConfig struct {
 	Enable  bool   `json:"enable"`
+	Tool    string `json:"tool"`    // nmap, native（内置探测库，无需安装nmap），默认nmap，未安装nmap时使用native
 	Timeout int    `json:"timeout"` // 单个主机超时时间(秒)，默认30秒
 	Args    string `json:"args"`    // Nmap额外参数，如 "-sV --version-intensity 5"
 }
@@ -267,6 +335,13 @@ type DomainScanConfig struct {
 	RemoveWildcard     bool     `json:"removeWildcard"`     // 移除泛解析域名
 	ResolveDNS         bool     `json:"resolveDNS"`         // 是否解析DNS
 	Concurrent         int      `json:"concurrent"`         // DNS解析并发数
+	Brute              bool     `json:"brute"`              // 字典爆破子域名
+	BruteWordlist      string   `json:"bruteWordlist"`      // 爆破字典，每行一个，为空使用内置字典
+	Permutation        bool     `json:"permutation"`        // 基于已发现子域名生成排列组合
+	AXFR               bool     `json:"axfr"`               // 尝试域传送
+	DNSRecords         bool     `json:"dnsRecords"`         // 采集MX/NS/TXT/SRV/CAA等记录
+	Takeover           bool     `json:"takeover"`           // 检测悬空CNAME子域名接管
+	Resolvers          []string `json:"resolvers"`          // 主动侦察使用的DNS服务器，为空使用系统配置
 }
 
 type FingerprintConfig struct {
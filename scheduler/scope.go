@@ -0,0 +1,487 @@
+package scheduler
+
+import (
+	"fmt"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ScopeRules 工作空间扫描范围规则
+// AllowedCidrs 为空时不限制IP，AllowedDomains 为空时不限制域名；排除规则优先于允许规则
+type ScopeRules struct {
+	AllowedCidrs   []string `json:"allowedCidrs,omitempty"`   // 允许的IP/CIDR
+	AllowedDomains []string `json:"allowedDomains,omitempty"` // 允许的域名，包含子域名
+	ExcludedHosts  []string `json:"excludedHosts,omitempty"`  // 排除的IP/CIDR/域名，域名包含子域名
+	ExcludedPorts  string   `json:"excludedPorts,omitempty"`  // 排除的端口，如 22,3389,8000-8100
+}
+
+// IsEmpty 是否未配置任何规则
+func (r *ScopeRules) IsEmpty() bool {
+	return r == nil || (len(r.AllowedCidrs) == 0 && len(r.AllowedDomains) == 0 &&
+		len(r.ExcludedHosts) == 0 && strings.TrimSpace(r.ExcludedPorts) == "")
+}
+
+// ScopeDrop 被扫描范围过滤掉的目标
+type ScopeDrop struct {
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+func (d ScopeDrop) String() string {
+	return d.Target + ": " + d.Reason
+}
+
+// Scope 编译后的扫描范围，nil 表示不限制
+type Scope struct {
+	allowNets      []*net.IPNet
+	allowDomains   []string
+	excludeNets    []*net.IPNet
+	excludeDomains []string
+	excludePorts   map[int]bool
+}
+
+// NewScope 编译扫描范围规则，规则为空时返回 nil
+func NewScope(rules *ScopeRules) (*Scope, error) {
+	if rules.IsEmpty() {
+		return nil, nil
+	}
+	s := &Scope{excludePorts: make(map[int]bool)}
+	for _, v := range rules.AllowedCidrs {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		ipnet, err := parseNet(v)
+		if err != nil {
+			return nil, fmt.Errorf("允许的IP段格式错误: %s", v)
+		}
+		s.allowNets = append(s.allowNets, ipnet)
+	}
+	for _, v := range rules.AllowedDomains {
+		if d := normalizeDomain(v); d != "" {
+			s.allowDomains = append(s.allowDomains, d)
+		}
+	}
+	for _, v := range rules.ExcludedHosts {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+		if ipnet, err := parseNet(v); err == nil {
+			s.excludeNets = append(s.excludeNets, ipnet)
+		} else if strings.ContainsAny(v, "/:") {
+			return nil, fmt.Errorf("排除的主机格式错误: %s", v)
+		} else if d := normalizeDomain(v); d != "" {
+			s.excludeDomains = append(s.excludeDomains, d)
+		}
+	}
+	for _, part := range strings.Split(rules.ExcludedPorts, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		start, end, err := parsePortRange(part)
+		if err != nil {
+			return nil, fmt.Errorf("排除的端口格式错误: %s", part)
+		}
+		for p := start; p <= end; p++ {
+			s.excludePorts[p] = true
+		}
+	}
+	return s, nil
+}
+
+// HasPortRules 是否配置了端口排除
+func (s *Scope) HasPortRules() bool {
+	return s != nil && len(s.excludePorts) > 0
+}
+
+// AllowPort 端口是否允许扫描
+func (s *Scope) AllowPort(port int) bool {
+	return s == nil || !s.excludePorts[port]
+}
+
+// AllowHost 主机（IP或域名）是否在扫描范围内，不在时返回原因
+func (s *Scope) AllowHost(host string) (bool, string) {
+	if s == nil {
+		return true, ""
+	}
+	host = strings.TrimSuffix(strings.ToLower(strings.Trim(strings.TrimSpace(host), "[]")), ".")
+	if host == "" {
+		return true, ""
+	}
+
+	if ip := net.ParseIP(host); ip != nil {
+		for _, n := range s.excludeNets {
+			if n.Contains(ip) {
+				return false, "命中排除主机 " + n.String()
+			}
+		}
+		if len(s.allowNets) == 0 {
+			return true, ""
+		}
+		for _, n := range s.allowNets {
+			if n.Contains(ip) {
+				return true, ""
+			}
+		}
+		return false, "不在允许的IP段内"
+	}
+
+	for _, d := range s.excludeDomains {
+		if matchDomain(host, d) {
+			return false, "命中排除主机 " + d
+		}
+	}
+	if len(s.allowDomains) == 0 {
+		return true, ""
+	}
+	for _, d := range s.allowDomains {
+		if matchDomain(host, d) {
+			return true, ""
+		}
+	}
+	return false, "不在允许的域名内"
+}
+
+// AllowAsset 主机和端口是否都在扫描范围内，port 为0时只检查主机
+func (s *Scope) AllowAsset(host string, port int) (bool, string) {
+	if ok, reason := s.AllowHost(host); !ok {
+		return false, reason
+	}
+	if port > 0 && !s.AllowPort(port) {
+		return false, "命中排除端口 " + strconv.Itoa(port)
+	}
+	return true, ""
+}
+
+// FilterTargets 过滤目标文本（每行一个目标），返回保留的目标和被过滤的目标。
+// 部分超出范围的CIDR和IP范围按地址区间与扫描范围求交集，保留的部分以IP范围输出，不逐个展开IP
+func (s *Scope) FilterTargets(target string) (string, []ScopeDrop) {
+	if s == nil {
+		return target, nil
+	}
+	var kept []string
+	var drops []ScopeDrop
+	for _, line := range strings.Split(target, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		if isIPBlock(line) {
+			if s.coversBlock(line) {
+				kept = append(kept, line)
+				continue
+			}
+			block, ok := blockRange(line)
+			if !ok {
+				drops = append(drops, ScopeDrop{Target: line, Reason: "IP段格式错误"})
+				continue
+			}
+			ranges := s.intersectRange(block)
+			n := new(big.Int)
+			for _, r := range ranges {
+				kept = append(kept, r.String())
+				n.Add(n, r.size())
+			}
+			if total := block.size(); n.Cmp(total) < 0 {
+				drops = append(drops, ScopeDrop{Target: line, Reason: fmt.Sprintf("%s/%s 个IP不在扫描范围内", new(big.Int).Sub(total, n), total)})
+			}
+			continue
+		}
+
+		host, port := targetHostPort(line)
+		if ok, reason := s.AllowAsset(host, port); !ok {
+			drops = append(drops, ScopeDrop{Target: line, Reason: reason})
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n"), drops
+}
+
+// coversBlock CIDR或IP范围是否完整落在允许范围内且不含排除的IP
+func (s *Scope) coversBlock(line string) bool {
+	var first, last net.IP
+	if strings.Contains(line, "/") {
+		_, ipnet, err := net.ParseCIDR(line)
+		if err != nil {
+			return false
+		}
+		first = ipnet.IP
+		last = make(net.IP, len(first))
+		for i := range first {
+			last[i] = first[i] | ^ipnet.Mask[i]
+		}
+	} else {
+		parts := strings.SplitN(line, "-", 2)
+		first = net.ParseIP(strings.TrimSpace(parts[0]))
+		last = net.ParseIP(strings.TrimSpace(parts[1]))
+		if first == nil || last == nil {
+			return false
+		}
+	}
+
+	for _, n := range s.excludeNets {
+		if n.Contains(first) || n.Contains(last) || ipBetween(n.IP, first, last) {
+			return false
+		}
+	}
+	if len(s.allowNets) == 0 {
+		return true
+	}
+	for _, n := range s.allowNets {
+		if n.Contains(first) && n.Contains(last) {
+			return true
+		}
+	}
+	return false
+}
+
+// addrRange IP地址区间 [from, to]
+type addrRange struct {
+	from, to netip.Addr
+}
+
+func (r addrRange) String() string {
+	if r.from == r.to {
+		return r.from.String()
+	}
+	return r.from.String() + "-" + r.to.String()
+}
+
+// size 区间内的地址数
+func (r addrRange) size() *big.Int {
+	from, to := r.from.As16(), r.to.As16()
+	n := new(big.Int).Sub(new(big.Int).SetBytes(to[:]), new(big.Int).SetBytes(from[:]))
+	return n.Add(n, big.NewInt(1))
+}
+
+// prefixRange CIDR覆盖的地址区间
+func prefixRange(p netip.Prefix) addrRange {
+	from := p.Masked().Addr()
+	b := from.AsSlice()
+	for i := p.Bits(); i < len(b)*8; i++ {
+		b[i/8] |= 0x80 >> (i % 8)
+	}
+	to, _ := netip.AddrFromSlice(b)
+	return addrRange{from: from, to: to}
+}
+
+// netRange net.IPNet 覆盖的地址区间
+func netRange(n *net.IPNet) (addrRange, bool) {
+	addr, ok := netip.AddrFromSlice(n.IP)
+	if !ok {
+		return addrRange{}, false
+	}
+	ones, _ := n.Mask.Size()
+	return prefixRange(netip.PrefixFrom(addr.Unmap(), ones)), true
+}
+
+// blockRange 解析CIDR或IP范围为地址区间。
+// 与目标拆分时展开CIDR一致，超过两个地址的CIDR不含网络地址和广播地址
+func blockRange(line string) (addrRange, bool) {
+	if strings.Contains(line, "/") {
+		p, err := netip.ParsePrefix(line)
+		if err != nil {
+			return addrRange{}, false
+		}
+		r := prefixRange(netip.PrefixFrom(p.Addr().Unmap(), p.Bits()))
+		if p.Addr().BitLen()-p.Bits() > 1 {
+			r.from, r.to = r.from.Next(), r.to.Prev()
+		}
+		return r, true
+	}
+	parts := strings.SplitN(line, "-", 2)
+	from, err1 := netip.ParseAddr(strings.TrimSpace(parts[0]))
+	to, err2 := netip.ParseAddr(strings.TrimSpace(parts[1]))
+	if err1 != nil || err2 != nil || from.Unmap().BitLen() != to.Unmap().BitLen() || to.Unmap().Less(from.Unmap()) {
+		return addrRange{}, false
+	}
+	return addrRange{from: from.Unmap(), to: to.Unmap()}, true
+}
+
+// intersectRange 地址区间与扫描范围的交集：与允许的IP段求交后去掉排除的IP段，按地址升序返回
+func (s *Scope) intersectRange(block addrRange) []addrRange {
+	var ranges []addrRange
+	if len(s.allowNets) == 0 {
+		ranges = []addrRange{block}
+	} else {
+		for _, n := range s.allowNets {
+			if r, ok := netRange(n); ok {
+				if r, ok = overlapRange(block, r); ok {
+					ranges = append(ranges, r)
+				}
+			}
+		}
+		ranges = mergeRanges(ranges)
+	}
+	for _, n := range s.excludeNets {
+		ex, ok := netRange(n)
+		if !ok {
+			continue
+		}
+		var rest []addrRange
+		for _, r := range ranges {
+			rest = append(rest, subtractRange(r, ex)...)
+		}
+		ranges = rest
+	}
+	return ranges
+}
+
+// overlapRange 两个区间的交集
+func overlapRange(a, b addrRange) (addrRange, bool) {
+	if a.from.BitLen() != b.from.BitLen() {
+		return addrRange{}, false
+	}
+	from, to := a.from, a.to
+	if from.Less(b.from) {
+		from = b.from
+	}
+	if b.to.Less(to) {
+		to = b.to
+	}
+	if to.Less(from) {
+		return addrRange{}, false
+	}
+	return addrRange{from: from, to: to}, true
+}
+
+// subtractRange 区间 r 去掉区间 ex 后剩余的部分
+func subtractRange(r, ex addrRange) []addrRange {
+	if _, ok := overlapRange(r, ex); !ok {
+		return []addrRange{r}
+	}
+	var rest []addrRange
+	if r.from.Less(ex.from) {
+		rest = append(rest, addrRange{from: r.from, to: ex.from.Prev()})
+	}
+	if ex.to.Less(r.to) {
+		rest = append(rest, addrRange{from: ex.to.Next(), to: r.to})
+	}
+	return rest
+}
+
+// mergeRanges 排序并合并重叠或相邻的区间
+func mergeRanges(ranges []addrRange) []addrRange {
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].from.Less(ranges[j].from) })
+	var merged []addrRange
+	for _, r := range ranges {
+		if last := len(merged) - 1; last >= 0 && (!merged[last].to.Next().IsValid() || !merged[last].to.Next().Less(r.from)) {
+			if merged[last].to.Less(r.to) {
+				merged[last].to = r.to
+			}
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// isIPBlock 是否为CIDR或IP范围
+func isIPBlock(line string) bool {
+	if strings.Contains(line, "://") {
+		return false
+	}
+	if strings.Contains(line, "/") {
+		_, _, err := net.ParseCIDR(line)
+		return err == nil
+	}
+	if strings.Contains(line, "-") {
+		parts := strings.SplitN(line, "-", 2)
+		return net.ParseIP(strings.TrimSpace(parts[0])) != nil && net.ParseIP(strings.TrimSpace(parts[1])) != nil
+	}
+	return false
+}
+
+// targetHostPort 从 IP、域名、host:port 或 URL 中提取主机和端口
+func targetHostPort(line string) (string, int) {
+	if strings.Contains(line, "://") {
+		u, err := url.Parse(line)
+		if err != nil {
+			return line, 0
+		}
+		port, _ := strconv.Atoi(u.Port())
+		return u.Hostname(), port
+	}
+	if host, p, err := net.SplitHostPort(line); err == nil {
+		port, _ := strconv.Atoi(p)
+		return host, port
+	}
+	return line, 0
+}
+
+// ipBetween ip 是否在 [first, last] 内
+func ipBetween(ip, first, last net.IP) bool {
+	ip, first, last = ip.To16(), first.To16(), last.To16()
+	return ip != nil && first != nil && last != nil &&
+		compareIP(ip, first) >= 0 && compareIP(ip, last) <= 0
+}
+
+func compareIP(a, b net.IP) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// parseNet 解析IP或CIDR
+func parseNet(v string) (*net.IPNet, error) {
+	if !strings.Contains(v, "/") {
+		ip := net.ParseIP(v)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid ip: %s", v)
+		}
+		if ip4 := ip.To4(); ip4 != nil {
+			return &net.IPNet{IP: ip4, Mask: net.CIDRMask(32, 32)}, nil
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+	_, ipnet, err := net.ParseCIDR(v)
+	return ipnet, err
+}
+
+// normalizeDomain 规范化域名，去掉通配符前缀
+func normalizeDomain(v string) string {
+	v = strings.ToLower(strings.TrimSpace(v))
+	v = strings.TrimPrefix(v, "*.")
+	v = strings.TrimPrefix(v, ".")
+	return strings.TrimSuffix(v, ".")
+}
+
+// matchDomain host 是否为 domain 或其子域名
+func matchDomain(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// parsePortRange 解析单个端口或端口范围
+func parsePortRange(v string) (int, int, error) {
+	startStr, endStr := v, v
+	if i := strings.Index(v, "-"); i > 0 {
+		startStr, endStr = v[:i], v[i+1:]
+	}
+	start, err := strconv.Atoi(strings.TrimSpace(startStr))
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := strconv.Atoi(strings.TrimSpace(endStr))
+	if err != nil {
+		return 0, 0, err
+	}
+	if start < 1 || end > 65535 || start > end {
+		return 0, 0, fmt.Errorf("port out of range: %s", v)
+	}
+	return start, end, nil
+}
@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestScopeAllowAsset(t *testing.T) {
+	scope, err := NewScope(&ScopeRules{
+		AllowedCidrs:   []string{"10.0.0.0/24", "192.168.1.10"},
+		AllowedDomains: []string{"*.example.com"},
+		ExcludedHosts:  []string{"10.0.0.5", "admin.example.com"},
+		ExcludedPorts:  "22, 8000-8002",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		host string
+		port int
+		want bool
+	}{
+		{"10.0.0.1", 80, true},
+		{"10.0.0.5", 80, false},
+		{"10.0.1.1", 80, false},
+		{"192.168.1.10", 0, true},
+		{"example.com", 443, true},
+		{"www.EXAMPLE.com.", 443, true},
+		{"a.admin.example.com", 443, false},
+		{"example.org", 443, false},
+		{"notexample.com", 443, false},
+		{"10.0.0.1", 22, false},
+		{"10.0.0.1", 8001, false},
+		{"10.0.0.1", 8003, true},
+	}
+	for _, tt := range tests {
+		if got, reason := scope.AllowAsset(tt.host, tt.port); got != tt.want {
+			t.Errorf("AllowAsset(%s, %d) = %v (%s), want %v", tt.host, tt.port, got, reason, tt.want)
+		}
+	}
+
+	var nilScope *Scope
+	if ok, _ := nilScope.AllowAsset("1.1.1.1", 22); !ok {
+		t.Error("nil scope should allow everything")
+	}
+}
+
+func TestScopeOnlyExclusions(t *testing.T) {
+	scope, err := NewScope(&ScopeRules{ExcludedHosts: []string{"gov.cn"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := scope.AllowHost("8.8.8.8"); !ok {
+		t.Error("ip should be allowed without allow rules")
+	}
+	if ok, _ := scope.AllowHost("www.gov.cn"); ok {
+		t.Error("excluded subdomain should be dropped")
+	}
+}
+
+func TestScopeFilterTargets(t *testing.T) {
+	scope, err := NewScope(&ScopeRules{
+		AllowedCidrs:   []string{"10.0.0.0/24"},
+		AllowedDomains: []string{"example.com"},
+		ExcludedHosts:  []string{"10.0.0.2"},
+		ExcludedPorts:  "22",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	target := strings.Join([]string{
+		"10.0.0.0/30",
+		"10.0.0.8/30",
+		"10.0.1.0/24",
+		"www.example.com",
+		"http://evil.org/login",
+		"10.0.0.9:22",
+		"10.0.0.9:80",
+	}, "\n")
+	kept, drops := scope.FilterTargets(target)
+
+	want := "10.0.0.1\n10.0.0.8/30\nwww.example.com\n10.0.0.9:80"
+	if kept != want {
+		t.Errorf("kept = %q, want %q", kept, want)
+	}
+	if len(drops) != 4 {
+		t.Errorf("drops = %v, want 4 entries", drops)
+	}
+}
+
+func TestScopeFilterLargeBlocks(t *testing.T) {
+	scope, err := NewScope(&ScopeRules{
+		AllowedCidrs:  []string{"10.1.0.0/16", "10.2.0.0/24", "2001:db8::/64"},
+		ExcludedHosts: []string{"10.1.128.0/17", "10.2.0.10", "2001:db8::100/120"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 大网段和IPv6网段按区间求交集，不逐个展开IP
+	kept, drops := scope.FilterTargets("10.0.0.0/8\n2001:db8::/48\n10.2.0.5-10.2.1.20\n192.168.0.0/16")
+	want := strings.Join([]string{
+		"10.1.0.0-10.1.127.255",
+		"10.2.0.0-10.2.0.9",
+		"10.2.0.11-10.2.0.255",
+		"2001:db8::1-2001:db8::ff",
+		"2001:db8::200-2001:db8::ffff:ffff:ffff:ffff",
+		"10.2.0.5-10.2.0.9",
+		"10.2.0.11-10.2.0.255",
+	}, "\n")
+	if kept != want {
+		t.Errorf("kept = %q, want %q", kept, want)
+	}
+	if len(drops) != 4 {
+		t.Fatalf("drops = %v, want 4 entries", drops)
+	}
+	if drops[0].Reason != "16744191/16777214 个IP不在扫描范围内" {
+		t.Errorf("drop reason = %q", drops[0].Reason)
+	}
+	if drops[3].Target != "192.168.0.0/16" {
+		t.Errorf("drops[3] = %v", drops[3])
+	}
+}
+
+func TestSplitTargetsWithScope(t *testing.T) {
+	scope, _ := NewScope(&ScopeRules{ExcludedHosts: []string{"10.0.0.0/24"}})
+	var dropped []ScopeDrop
+	splitter := NewTargetSplitter(2).SetScope(scope, func(d ScopeDrop) {
+		dropped = append(dropped, d)
+	})
+
+	batches := splitter.SplitTargets("10.0.0.1\n10.0.1.1\n10.0.1.2\n10.0.1.3")
+	if len(batches) != 2 || batches[0] != "10.0.1.1\n10.0.1.2" {
+		t.Errorf("batches = %q", batches)
+	}
+	if len(dropped) != 1 || dropped[0].Target != "10.0.0.1" {
+		t.Errorf("dropped = %v", dropped)
+	}
+	if got := splitter.SplitTargets("10.0.0.1"); got != nil {
+		t.Errorf("all targets dropped, got %q", got)
+	}
+}
+
+func TestNewScopeInvalid(t *testing.T) {
+	invalid := []*ScopeRules{
+		{AllowedCidrs: []string{"10.0.0.0/33"}},
+		{ExcludedHosts: []string{"10.0.0.300/24"}},
+		{ExcludedPorts: "70000"},
+		{ExcludedPorts: "abc"},
+	}
+	for _, rules := range invalid {
+		if _, err := NewScope(rules); err == nil {
+			t.Errorf("NewScope(%+v) expected error", rules)
+		}
+	}
+	if scope, err := NewScope(&ScopeRules{}); scope != nil || err != nil {
+		t.Error("empty rules should compile to nil scope")
+	}
+}
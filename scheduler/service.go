@@ -46,6 +46,11 @@ func (s *SchedulerService) Start() {
 	// 加载定时任务
 	s.cronManager.LoadTasks(context.Background())
 
+	// 定期回收过期租约（Worker 崩溃或失联后任务重新入队）
+	if _, err := s.scheduler.AddCronTask("*/30 * * * * *", s.reapExpiredLeases); err != nil {
+		logx.Errorf("Failed to start lease reaper: %v", err)
+	}
+
 	// 启动后台同步任务
 	if s.syncMethods != nil {
 		// 先加载缓存
@@ -67,6 +72,18 @@ func (s *SchedulerService) Stop() {
 	logx.Info("Scheduler service stopped")
 }
 
+// reapExpiredLeases 回收过期租约
+func (s *SchedulerService) reapExpiredLeases() {
+	requeued, dead, err := s.scheduler.ReapExpiredLeases(context.Background())
+	if err != nil {
+		logx.Errorf("Reap expired leases failed: %v", err)
+		return
+	}
+	if requeued > 0 || dead > 0 {
+		logx.Infof("Reaped expired leases: requeued=%d, deadLetter=%d", requeued, dead)
+	}
+}
+
 // GetScheduler 获取调度器
 func (s *SchedulerService) GetScheduler() *Scheduler {
 	return s.scheduler
@@ -8,6 +8,8 @@ import (
 // TargetSplitter 目标拆分器
 type TargetSplitter struct {
 	batchSize int // 每批次的IP数量
+	scope     *Scope
+	onDrop    func(ScopeDrop)
 }
 
 // NewTargetSplitter 创建目标拆分器
@@ -18,9 +20,29 @@ func NewTargetSplitter(batchSize int) *TargetSplitter {
 	return &TargetSplitter{batchSize: batchSize}
 }
 
+// SetScope 设置扫描范围，拆分前过滤超出范围的目标，onDrop 接收被过滤的目标
+func (s *TargetSplitter) SetScope(scope *Scope, onDrop func(ScopeDrop)) *TargetSplitter {
+	s.scope = scope
+	s.onDrop = onDrop
+	return s
+}
+
 // SplitTargets 拆分目标为多个批次
-// 返回拆分后的目标列表，每个元素是一批目标（换行分隔）
+// 返回拆分后的目标列表，每个元素是一批目标（换行分隔）；设置了扫描范围且全部目标被过滤时返回空
 func (s *TargetSplitter) SplitTargets(target string) []string {
+	if s.scope != nil {
+		var drops []ScopeDrop
+		target, drops = s.scope.FilterTargets(target)
+		if s.onDrop != nil {
+			for _, d := range drops {
+				s.onDrop(d)
+			}
+		}
+		if target == "" {
+			return nil
+		}
+	}
+
 	// 解析所有目标
 	allTargets := s.parseAllTargets(target)
 
@@ -0,0 +1,193 @@
+package scheduler
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// 维护窗口
+// 工作空间和组织可以配置允许扫描的时段、禁止扫描的日期和时区。
+// 窗口关闭时定时任务推迟到窗口重新打开后执行，运行中的任务暂停并在窗口重新打开时自动继续。
+
+// maxWindowSearchDays 查找下次打开时间的最大天数，超过视为不会再打开
+const maxWindowSearchDays = 400
+
+const dateLayout = "2006-01-02"
+
+// WindowRules 维护窗口配置
+type WindowRules struct {
+	Timezone      string   `json:"timezone,omitempty"`      // IANA 时区，如 Asia/Shanghai，为空使用服务器时区
+	AllowedHours  []string `json:"allowedHours,omitempty"`  // 允许扫描的时段，如 09:00-18:00，结束早于开始表示跨天，为空全天允许
+	BlackoutDates []string `json:"blackoutDates,omitempty"` // 禁止扫描的日期，如 2026-12-31 或 2026-12-24~2027-01-02
+}
+
+// Window 解析后的维护窗口
+type Window struct {
+	loc      *time.Location
+	hours    []hourRange
+	blackout []dateRange
+}
+
+// hourRange 一天中的分钟区间 [start, end)，start > end 表示跨天
+type hourRange struct {
+	start, end int
+}
+
+// dateRange 日期区间，包含两端
+type dateRange struct {
+	from, to string
+}
+
+// NewWindow 解析维护窗口配置，未配置时段和禁止日期时返回 nil
+func NewWindow(r *WindowRules) (*Window, error) {
+	if r == nil || (len(r.AllowedHours) == 0 && len(r.BlackoutDates) == 0) {
+		return nil, nil
+	}
+	w := &Window{loc: time.Local}
+	if tz := strings.TrimSpace(r.Timezone); tz != "" {
+		loc, err := time.LoadLocation(tz)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timezone %q", tz)
+		}
+		w.loc = loc
+	}
+	for _, s := range r.AllowedHours {
+		h, err := parseHourRange(s)
+		if err != nil {
+			return nil, err
+		}
+		w.hours = append(w.hours, h)
+	}
+	for _, s := range r.BlackoutDates {
+		d, err := parseDateRange(s)
+		if err != nil {
+			return nil, err
+		}
+		w.blackout = append(w.blackout, d)
+	}
+	return w, nil
+}
+
+// parseHourRange 解析 HH:MM-HH:MM 格式的时段，结束时间可以为 24:00
+func parseHourRange(s string) (hourRange, error) {
+	from, to, ok := strings.Cut(strings.TrimSpace(s), "-")
+	start, err1 := parseMinute(from, false)
+	end, err2 := parseMinute(to, true)
+	if !ok || err1 != nil || err2 != nil || start == end {
+		return hourRange{}, fmt.Errorf("invalid allowed hours %q, expected HH:MM-HH:MM", s)
+	}
+	return hourRange{start: start, end: end}, nil
+}
+
+func parseMinute(s string, allowEndOfDay bool) (int, error) {
+	s = strings.TrimSpace(s)
+	if allowEndOfDay && s == "24:00" {
+		return 24 * 60, nil
+	}
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// parseDateRange 解析单个日期或以 ~ 分隔的日期区间
+func parseDateRange(s string) (dateRange, error) {
+	s = strings.TrimSpace(s)
+	from, to, ok := strings.Cut(s, "~")
+	if !ok {
+		to = from
+	}
+	from, to = strings.TrimSpace(from), strings.TrimSpace(to)
+	_, err1 := time.Parse(dateLayout, from)
+	_, err2 := time.Parse(dateLayout, to)
+	if err1 != nil || err2 != nil || from > to {
+		return dateRange{}, fmt.Errorf("invalid blackout date %q, expected YYYY-MM-DD or YYYY-MM-DD~YYYY-MM-DD", s)
+	}
+	return dateRange{from: from, to: to}, nil
+}
+
+// Open 时间 t 是否处于允许扫描的时段，nil 表示不限制
+func (w *Window) Open(t time.Time) bool {
+	if w == nil {
+		return true
+	}
+	t = t.In(w.loc)
+	date := t.Format(dateLayout)
+	for _, d := range w.blackout {
+		if date >= d.from && date <= d.to {
+			return false
+		}
+	}
+	if len(w.hours) == 0 {
+		return true
+	}
+	m := t.Hour()*60 + t.Minute()
+	for _, h := range w.hours {
+		if h.start < h.end && m >= h.start && m < h.end {
+			return true
+		}
+		if h.start > h.end && (m >= h.start || m < h.end) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen 时间 t 之后（含 t）窗口最近一次打开的时间，不会再打开时返回零值
+func (w *Window) NextOpen(t time.Time) time.Time {
+	if w.Open(t) {
+		return t
+	}
+	// 窗口只会在某天零点（禁止日期结束）或某个时段开始时打开
+	lt := t.In(w.loc)
+	for d := 0; d <= maxWindowSearchDays; d++ {
+		candidates := []time.Time{time.Date(lt.Year(), lt.Month(), lt.Day()+d, 0, 0, 0, 0, w.loc)}
+		for _, h := range w.hours {
+			candidates = append(candidates, time.Date(lt.Year(), lt.Month(), lt.Day()+d, h.start/60, h.start%60, 0, 0, w.loc))
+		}
+		sort.Slice(candidates, func(i, j int) bool { return candidates[i].Before(candidates[j]) })
+		for _, c := range candidates {
+			if c.After(t) && w.Open(c) {
+				return c
+			}
+		}
+	}
+	return time.Time{}
+}
+
+// Windows 同时生效的多个维护窗口（如工作空间和所属组织），全部打开时才允许扫描
+type Windows []*Window
+
+// Open 时间 t 是否所有窗口都打开
+func (ws Windows) Open(t time.Time) bool {
+	for _, w := range ws {
+		if !w.Open(t) {
+			return false
+		}
+	}
+	return true
+}
+
+// NextOpen 所有窗口同时打开的最近时间，不会再打开时返回零值
+func (ws Windows) NextOpen(t time.Time) time.Time {
+	limit := t.AddDate(0, 0, maxWindowSearchDays)
+	for t.Before(limit) {
+		moved := false
+		for _, w := range ws {
+			if w.Open(t) {
+				continue
+			}
+			if t = w.NextOpen(t); t.IsZero() {
+				return t
+			}
+			moved = true
+		}
+		if !moved {
+			return t
+		}
+	}
+	return time.Time{}
+}
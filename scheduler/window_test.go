@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWindowOpen(t *testing.T) {
+	w, err := NewWindow(&WindowRules{
+		Timezone:      "Asia/Shanghai",
+		AllowedHours:  []string{"09:00-12:00", "22:00-02:00"},
+		BlackoutDates: []string{"2026-10-01~2026-10-07", "2026-12-31"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	at := func(s string) time.Time {
+		tm, err := time.ParseInLocation("2006-01-02 15:04", s, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tm
+	}
+
+	tests := []struct {
+		time string
+		want bool
+	}{
+		{"2026-10-20 09:00", true},
+		{"2026-10-20 11:59", true},
+		{"2026-10-20 12:00", false},
+		{"2026-10-20 23:30", true},
+		{"2026-10-21 01:59", true},
+		{"2026-10-21 02:00", false},
+		{"2026-10-03 10:00", false},
+		{"2026-12-31 10:00", false},
+	}
+	for _, tt := range tests {
+		if got := w.Open(at(tt.time)); got != tt.want {
+			t.Errorf("Open(%s) = %v, want %v", tt.time, got, tt.want)
+		}
+	}
+	// 时区不同的时间按窗口时区判断
+	if !w.Open(at("2026-10-20 10:00").UTC()) {
+		t.Error("Open() should use window timezone")
+	}
+
+	nextTests := []struct {
+		from, want string
+	}{
+		{"2026-10-20 10:00", "2026-10-20 10:00"},
+		{"2026-10-20 13:00", "2026-10-20 22:00"},
+		{"2026-10-21 03:00", "2026-10-21 09:00"},
+		{"2026-09-30 23:00", "2026-09-30 23:00"},
+		{"2026-10-01 00:30", "2026-10-08 00:00"},
+	}
+	for _, tt := range nextTests {
+		if got := w.NextOpen(at(tt.from)); !got.Equal(at(tt.want)) {
+			t.Errorf("NextOpen(%s) = %s, want %s", tt.from, got.In(loc).Format("2006-01-02 15:04"), tt.want)
+		}
+	}
+
+	// 工作空间和组织窗口同时生效
+	org, _ := NewWindow(&WindowRules{Timezone: "Asia/Shanghai", AllowedHours: []string{"11:00-18:00"}})
+	ws := Windows{w, org, nil}
+	if ws.Open(at("2026-10-20 09:30")) || !ws.Open(at("2026-10-20 11:30")) {
+		t.Error("Windows.Open() should require all windows open")
+	}
+	if got := ws.NextOpen(at("2026-10-20 12:30")); !got.Equal(at("2026-10-21 11:00")) {
+		t.Errorf("Windows.NextOpen() = %s", got.In(loc).Format("2006-01-02 15:04"))
+	}
+}
+
+func TestNewWindowInvalid(t *testing.T) {
+	for _, r := range []*WindowRules{
+		{Timezone: "Mars/Base", AllowedHours: []string{"09:00-18:00"}},
+		{AllowedHours: []string{"9-18"}},
+		{AllowedHours: []string{"09:00-09:00"}},
+		{BlackoutDates: []string{"2026-12-31~2026-12-01"}},
+	} {
+		if _, err := NewWindow(r); err == nil {
+			t.Errorf("NewWindow(%+v) expected error", r)
+		}
+	}
+	if w, err := NewWindow(&WindowRules{Timezone: "UTC"}); w != nil || err != nil {
+		t.Errorf("NewWindow() without rules = %v, %v; want nil, nil", w, err)
+	}
+}
@@ -24,6 +24,10 @@ export function getAssetHistory(data) {
   return request.post('/asset/history', data)
 }
 
+export function getAssetDiff(data) {
+  return request.post('/asset/diff', data)
+}
+
 export function importAsset(data) {
   return request.post('/asset/import', data)
 }
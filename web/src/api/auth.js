@@ -23,3 +23,15 @@ export function deleteUser(data) {
 export function resetUserPassword(data) {
   return request.post('/user/resetPassword', data)
 }
+
+export function getApiTokenList(data) {
+  return request.post('/token/list', data)
+}
+
+export function createApiToken(data) {
+  return request.post('/token/create', data)
+}
+
+export function revokeApiToken(data) {
+  return request.post('/token/revoke', data)
+}
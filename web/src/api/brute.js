@@ -0,0 +1,27 @@
+import request from './request'
+
+// 弱口令爆破字典列表
+export function getBruteDictList(data) {
+  return request.post('/brute/dict/list', data)
+}
+
+// 保存弱口令爆破字典
+export function saveBruteDict(data) {
+  return request.post('/brute/dict/save', data)
+}
+
+// 删除弱口令爆破字典
+export function deleteBruteDict(data) {
+  return request.post('/brute/dict/delete', data)
+}
+
+// 获取启用的弱口令爆破字典列表（用于任务创建时选择）
+export function getBruteDictEnabledList() {
+  return request.post('/brute/dict/enabled')
+}
+
+// 支持爆破的服务
+export const bruteServices = [
+  'ssh', 'ftp', 'mysql', 'mssql', 'postgresql', 'redis',
+  'mongodb', 'smb', 'rdp', 'telnet', 'tomcat', 'http-basic'
+]
@@ -0,0 +1,21 @@
+import request from './request'
+
+// DNS记录列表
+export function getDNSRecordList(data) {
+  return request.post('/dnsrecord/list', data)
+}
+
+// DNS记录按类型统计
+export function getDNSRecordStat() {
+  return request.post('/dnsrecord/stat', {})
+}
+
+// 删除DNS记录
+export function deleteDNSRecords(data) {
+  return request.post('/dnsrecord/delete', data)
+}
+
+// 清空DNS记录
+export function clearDNSRecords() {
+  return request.post('/dnsrecord/clear', {})
+}
@@ -0,0 +1,22 @@
+import request from './request'
+
+export function getGeoIPInfo() {
+  return request.post('/geoip/info', {})
+}
+
+// data 为 base64 编码的数据库文件
+export function uploadGeoIPDB(data) {
+  return request.post('/geoip/upload', data, { timeout: 300000 })
+}
+
+export function updateGeoIPDB(data) {
+  return request.post('/geoip/update', data, { timeout: 300000 })
+}
+
+export function lookupGeoIP(data) {
+  return request.post('/geoip/lookup', data)
+}
+
+export function enrichGeoIP(data) {
+  return request.post('/geoip/enrich', data, { timeout: 300000 })
+}
@@ -0,0 +1,9 @@
+import request from './request'
+
+export function getInteractshConfig() {
+  return request.post('/interactsh/config/get', {})
+}
+
+export function saveInteractshConfig(data) {
+  return request.post('/interactsh/config/save', data)
+}
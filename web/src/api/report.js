@@ -18,3 +18,39 @@ export function exportReport(data) {
     responseType: 'blob'
   })
 }
+
+// 报告模板列表
+export function getReportTemplateList(data) {
+  return request({
+    url: '/report/template/list',
+    method: 'post',
+    data
+  })
+}
+
+// 保存报告模板
+export function saveReportTemplate(data) {
+  return request({
+    url: '/report/template/save',
+    method: 'post',
+    data
+  })
+}
+
+// 删除报告模板
+export function deleteReportTemplate(data) {
+  return request({
+    url: '/report/template/delete',
+    method: 'post',
+    data
+  })
+}
+
+// 获取内置报告模板
+export function getDefaultReportTemplate(data) {
+  return request({
+    url: '/report/template/default',
+    method: 'post',
+    data
+  })
+}
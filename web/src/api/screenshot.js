@@ -0,0 +1,19 @@
+import request from './request'
+
+// 对象存储中截图的访问地址，<img> 无法携带请求头，通过 token 参数认证
+export function screenshotFileUrl(key) {
+  const token = localStorage.getItem('token') || ''
+  return `/api/v1/screenshot/get?key=${encodeURIComponent(key)}&token=${encodeURIComponent(token)}`
+}
+
+export function getScreenshotGallery(data) {
+  return request.post('/screenshot/gallery', data, { timeout: 120000 })
+}
+
+export function getSimilarScreenshots(data) {
+  return request.post('/screenshot/similar', data, { timeout: 120000 })
+}
+
+export function migrateScreenshots() {
+  return request.post('/screenshot/migrate', {}, { timeout: 600000 })
+}
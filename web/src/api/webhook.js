@@ -0,0 +1,25 @@
+import request from './request'
+
+export function getWebhookList() {
+  return request.post('/webhook/list', {})
+}
+
+export function saveWebhook(data) {
+  return request.post('/webhook/save', data)
+}
+
+export function deleteWebhook(data) {
+  return request.post('/webhook/delete', data)
+}
+
+export function testWebhook(data) {
+  return request.post('/webhook/test', data)
+}
+
+export function getWebhookDeliveryList(data) {
+  return request.post('/webhook/delivery/list', data)
+}
+
+export function replayWebhookDelivery(data) {
+  return request.post('/webhook/delivery/replay', data)
+}
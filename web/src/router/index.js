@@ -113,7 +113,7 @@ const routes = [
         path: 'worker/console/:name',
         name: 'WorkerConsole',
         component: lazyLoad(() => import('@/views/WorkerConsole.vue')),
-        meta: { title: 'Worker控制台', icon: 'Monitor', hidden: true }
+        meta: { title: 'Worker控制台', icon: 'Monitor', roles: ['superadmin'], hidden: true }
       },
       {
         path: 'poc',
@@ -168,6 +168,8 @@ router.beforeEach((to, from, next) => {
     next('/login')
   } else if (to.path === '/login' && userStore.token) {
     next('/dashboard')
+  } else if (to.meta.roles && !to.meta.roles.includes(userStore.role)) {
+    next('/dashboard')
   } else {
     next()
   }
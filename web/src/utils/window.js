@@ -0,0 +1,36 @@
+/**
+ * 维护窗口表单转换工具
+ */
+
+/**
+ * 空的维护窗口表单
+ */
+export function emptyWindowForm() {
+  return { timezone: '', allowedHours: '', blackoutDates: '' }
+}
+
+/**
+ * 接口返回的维护窗口转换为表单
+ * @param {object|null} window - 维护窗口
+ */
+export function toWindowForm(window) {
+  const w = window || {}
+  return {
+    timezone: w.timezone || '',
+    allowedHours: (w.allowedHours || []).join('\n'),
+    blackoutDates: (w.blackoutDates || []).join('\n')
+  }
+}
+
+/**
+ * 表单转换为接口请求的维护窗口，时段和日期都为空时清除维护窗口
+ * @param {object} form - 维护窗口表单
+ */
+export function fromWindowForm(form) {
+  const splitLines = text => text.split('\n').map(s => s.trim()).filter(Boolean)
+  return {
+    timezone: form.timezone || '',
+    allowedHours: splitLines(form.allowedHours),
+    blackoutDates: splitLines(form.blackoutDates)
+  }
+}
@@ -0,0 +1,54 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// handoffPhases 按阶段分布式执行时，阶段属于后续阶段组则将资产交给新的子任务，由具备相应能力的 Worker 执行
+// 返回 true 表示已交接，当前子任务结束；交接失败时在本 Worker 继续执行
+func (w *Worker) handoffPhases(ctx context.Context, task *scheduler.TaskInfo, config *scheduler.TaskConfig, phase string, completedPhases map[string]bool, assets []*scanner.Asset) bool {
+	if !config.PhaseSplit || config.Pipeline.Enabled() || completedPhases[phase] || !config.PhaseEnabled(phase) || len(assets) == 0 {
+		return false
+	}
+	groups := config.PhaseGroups()
+	next := scheduler.PhaseGroupIndex(groups, phase)
+	if next <= config.PhaseGroup {
+		return false
+	}
+
+	var phases []string
+	for p, done := range completedPhases {
+		if done {
+			phases = append(phases, p)
+		}
+	}
+	sort.Strings(phases)
+
+	resp, err := w.httpClient.HandoffTask(ctx, &TaskHandoffReq{
+		TaskId:          task.TaskId,
+		MainTaskId:      task.MainTaskId,
+		WorkspaceId:     task.WorkspaceId,
+		Config:          task.Config,
+		PhaseGroup:      next,
+		CompletedPhases: phases,
+		Assets:          assets,
+	})
+	if err == nil && resp.Code != 0 {
+		err = fmt.Errorf("%s", resp.Msg)
+	}
+	if err != nil {
+		w.taskLog(task.TaskId, LevelWarn, "[Handoff] Failed to hand off %s, continuing on this worker: %v", strings.Join(groups[next].Phases, ", "), err)
+		return false
+	}
+
+	result := fmt.Sprintf("Handed off %d assets to %d sub-tasks (%s)", len(assets), len(resp.TaskIds), strings.Join(groups[next].Phases, ", "))
+	w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusSuccess, result)
+	w.taskLog(task.TaskId, LevelInfo, "Completed: %s", result)
+	return true
+}
@@ -8,6 +8,10 @@ import (
 	"io"
 	"net/http"
 	"time"
+
+	"cscan/pkg/ratelimit"
+	"cscan/scanner"
+	"cscan/scheduler"
 )
 
 // WorkerHTTPClient Worker HTTP 客户端
@@ -35,7 +39,9 @@ func NewWorkerHTTPClient(baseURL, installKey, workerName string) *WorkerHTTPClie
 
 // TaskCheckReq 任务拉取请求
 type TaskCheckReq struct {
-	WorkerName string `json:"workerName"`
+	WorkerName string   `json:"workerName"`
+	Labels     []string `json:"labels,omitempty"`    // Worker 标签（key=value）
+	LabelOnly  bool     `json:"labelOnly,omitempty"` // 只获取标签选择器匹配的任务
 }
 
 // TaskCheckResp 任务拉取响应
@@ -83,29 +89,34 @@ type IPV6Info struct {
 
 // AssetDocument 资产文档
 type AssetDocument struct {
-	Authority  string     `json:"authority"`
-	Host       string     `json:"host"`
-	Port       int32      `json:"port"`
-	Category   string     `json:"category"`
-	Service    string     `json:"service"`
-	Server     string     `json:"server"`
-	Banner     string     `json:"banner"`
-	Title      string     `json:"title"`
-	App        []string   `json:"app"`
-	HttpStatus string     `json:"httpStatus"`
-	HttpHeader string     `json:"httpHeader"`
-	HttpBody   string     `json:"httpBody"`
-	Cert       string     `json:"cert"`
-	IconHash   string     `json:"iconHash"`
-	IsCdn      bool       `json:"isCdn"`
-	Cname      string     `json:"cname"`
-	IsCloud    bool       `json:"isCloud"`
-	Ipv4       []IPV4Info `json:"ipv4"`
-	Ipv6       []IPV6Info `json:"ipv6"`
-	Screenshot string     `json:"screenshot"`
-	IsHttp     bool       `json:"isHttp"`
-	Source     string     `json:"source"`
-	IconData   []byte     `json:"iconData"`
+	Authority  string           `json:"authority"`
+	Host       string           `json:"host"`
+	Port       int32            `json:"port"`
+	Category   string           `json:"category"`
+	Service    string           `json:"service"`
+	Server     string           `json:"server"`
+	Banner     string           `json:"banner"`
+	Title      string           `json:"title"`
+	App        []string         `json:"app"`
+	HttpStatus string           `json:"httpStatus"`
+	HttpHeader string           `json:"httpHeader"`
+	HttpBody   string           `json:"httpBody"`
+	Cert       string           `json:"cert"`
+	IconHash   string           `json:"iconHash"`
+	IsCdn      bool             `json:"isCdn"`
+	Cname      string           `json:"cname"`
+	IsCloud    bool             `json:"isCloud"`
+	CdnName    string           `json:"cdnName,omitempty"`
+	Waf        string           `json:"waf,omitempty"`
+	CloudName  string           `json:"cloudName,omitempty"`
+	Ipv4       []IPV4Info       `json:"ipv4"`
+	Ipv6       []IPV6Info       `json:"ipv6"`
+	Screenshot string           `json:"screenshot"`
+	IsHttp     bool             `json:"isHttp"`
+	Source     string           `json:"source"`
+	IconData   []byte           `json:"iconData"`
+	Transport  string           `json:"transport,omitempty"`
+	TLS        *scanner.TLSInfo `json:"tls,omitempty"`
 }
 
 // TaskResultReq 资产结果上报请求
@@ -149,6 +160,8 @@ type VulDocument struct {
 	Request           *string  `json:"request,omitempty"`
 	Response          *string  `json:"response,omitempty"`
 	ResponseTruncated *bool    `json:"responseTruncated,omitempty"`
+
+	Interaction *scanner.VulInteraction `json:"interaction,omitempty"` // OOB 回连证据
 }
 
 // VulResultReq 漏洞结果上报请求
@@ -169,14 +182,16 @@ type VulResultResp struct {
 
 // HeartbeatReq 心跳请求
 type HeartbeatReq struct {
-	WorkerName         string  `json:"workerName"`
-	IP                 string  `json:"ip"`
-	CpuLoad            float64 `json:"cpuLoad"`
-	MemUsed            float64 `json:"memUsed"`
-	TaskStartedNumber  int32   `json:"taskStartedNumber"`
-	TaskExecutedNumber int32   `json:"taskExecutedNumber"`
-	IsDaemon           bool    `json:"isDaemon"`
-	Concurrency        int     `json:"concurrency"`
+	WorkerName         string   `json:"workerName"`
+	IP                 string   `json:"ip"`
+	CpuLoad            float64  `json:"cpuLoad"`
+	MemUsed            float64  `json:"memUsed"`
+	TaskStartedNumber  int32    `json:"taskStartedNumber"`
+	TaskExecutedNumber int32    `json:"taskExecutedNumber"`
+	IsDaemon           bool     `json:"isDaemon"`
+	Concurrency        int      `json:"concurrency"`
+	TaskIds            []string `json:"taskIds,omitempty"` // 正在执行的任务ID，服务端据此续约
+	Labels             []string `json:"labels,omitempty"`  // Worker 标签，用于在 Worker 列表中展示
 }
 
 // HeartbeatResp 心跳响应
@@ -208,6 +223,24 @@ type SubTaskDoneResp struct {
 	AllDone      bool   `json:"allDone"`
 }
 
+// TaskHandoffReq 阶段交接请求
+type TaskHandoffReq struct {
+	TaskId          string           `json:"taskId"`
+	MainTaskId      string           `json:"mainTaskId"`
+	WorkspaceId     string           `json:"workspaceId"`
+	Config          string           `json:"config"`
+	PhaseGroup      int              `json:"phaseGroup"`
+	CompletedPhases []string         `json:"completedPhases"`
+	Assets          []*scanner.Asset `json:"assets"`
+}
+
+// TaskHandoffResp 阶段交接响应
+type TaskHandoffResp struct {
+	Code    int      `json:"code"`
+	Msg     string   `json:"msg"`
+	TaskIds []string `json:"taskIds"`
+}
+
 // TemplatesReq 模板获取请求
 type TemplatesReq struct {
 	Tags              []string `json:"tags,omitempty"`
@@ -282,6 +315,59 @@ type SubfinderResp struct {
 	Count     int32               `json:"count"`
 }
 
+// ScopeReq 扫描范围获取请求
+type ScopeReq struct {
+	WorkspaceId string `json:"workspaceId"`
+}
+
+// ScopeResp 扫描范围获取响应，Scope 为空表示不限制
+type ScopeResp struct {
+	Code  int                   `json:"code"`
+	Msg   string                `json:"msg"`
+	Scope *scheduler.ScopeRules `json:"scope,omitempty"`
+}
+
+// RateLimitConfigResp 限速预算获取响应，Budget 为空表示不限速
+type RateLimitConfigResp struct {
+	Code   int               `json:"code"`
+	Msg    string            `json:"msg"`
+	Budget *ratelimit.Budget `json:"budget,omitempty"`
+}
+
+// RateLimitAcquireReq 令牌获取请求
+type RateLimitAcquireReq struct {
+	Buckets []ratelimit.Bucket `json:"buckets"`
+	Tokens  int                `json:"tokens"`
+}
+
+// RateLimitAcquireResp 令牌获取响应，Granted 为 0 时需等待 WaitMs 后重试
+type RateLimitAcquireResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Granted int    `json:"granted"`
+	WaitMs  int64  `json:"waitMs"`
+}
+
+// OriginReq 候选源站查询请求
+type OriginReq struct {
+	WorkspaceId string   `json:"workspaceId"`
+	Domains     []string `json:"domains"`
+}
+
+// OriginResp 候选源站查询响应，key 为域名
+type OriginResp struct {
+	Code       int                                  `json:"code"`
+	Msg        string                               `json:"msg"`
+	Candidates map[string][]scanner.OriginCandidate `json:"candidates"`
+}
+
+// InteractshResp OOB回连配置获取响应，Config 为空表示使用 nuclei 默认公共服务
+type InteractshResp struct {
+	Code   int                        `json:"code"`
+	Msg    string                     `json:"msg"`
+	Config *scanner.InteractshOptions `json:"config,omitempty"`
+}
+
 // HttpServiceReq HTTP服务映射获取请求
 type HttpServiceReq struct {
 	EnabledOnly bool `json:"enabledOnly"`
@@ -490,10 +576,13 @@ func (c *WorkerHTTPClient) doRequestOnce(ctx context.Context, method, path strin
 	return respBody, nil
 }
 
-// CheckTask 任务拉取
-func (c *WorkerHTTPClient) CheckTask(ctx context.Context) (*TaskCheckResp, error) {
+// CheckTask 任务拉取，labels 为 Worker 标签，用于获取标签选择器匹配的任务
+// labelOnly 为 true 时不从公共队列获取任务
+func (c *WorkerHTTPClient) CheckTask(ctx context.Context, labels []string, labelOnly bool) (*TaskCheckResp, error) {
 	req := &TaskCheckReq{
 		WorkerName: c.workerName,
+		Labels:     labels,
+		LabelOnly:  labelOnly,
 	}
 
 	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/check", req)
@@ -585,6 +674,21 @@ func (c *WorkerHTTPClient) IncrSubTaskDone(ctx context.Context, req *SubTaskDone
 	return &resp, nil
 }
 
+// HandoffTask 阶段交接，将资产交给执行下一组阶段的子任务
+func (c *WorkerHTTPClient) HandoffTask(ctx context.Context, req *TaskHandoffReq) (*TaskHandoffResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/handoff", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp TaskHandoffResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
 // GetTemplates 获取POC模板
 func (c *WorkerHTTPClient) GetTemplates(ctx context.Context, req *TemplatesReq) (*TemplatesResp, error) {
 	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/templates", req)
@@ -634,6 +738,81 @@ func (c *WorkerHTTPClient) GetSubfinderProviders(ctx context.Context, workspaceI
 	return &resp, nil
 }
 
+// GetScanScope 获取工作空间扫描范围
+func (c *WorkerHTTPClient) GetScanScope(ctx context.Context, workspaceId string) (*ScopeResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/scope", &ScopeReq{WorkspaceId: workspaceId})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp ScopeResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GetRateLimitBudget 获取工作空间限速预算
+func (c *WorkerHTTPClient) GetRateLimitBudget(ctx context.Context, workspaceId string) (*RateLimitConfigResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/ratelimit", &ScopeReq{WorkspaceId: workspaceId})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp RateLimitConfigResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// AcquireRateLimit 从分布式令牌桶获取令牌
+func (c *WorkerHTTPClient) AcquireRateLimit(ctx context.Context, req *RateLimitAcquireReq) (*RateLimitAcquireResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/ratelimit/acquire", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp RateLimitAcquireResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GetOriginCandidates 查询CDN域名的历史解析和证书关联IP
+func (c *WorkerHTTPClient) GetOriginCandidates(ctx context.Context, req *OriginReq) (*OriginResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/origin", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp OriginResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
+// GetInteractshConfig 获取OOB回连配置
+func (c *WorkerHTTPClient) GetInteractshConfig(ctx context.Context) (*InteractshResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/interactsh", struct{}{})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp InteractshResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
 // GetHttpServiceMappings 获取HTTP服务映射
 func (c *WorkerHTTPClient) GetHttpServiceMappings(ctx context.Context, enabledOnly bool) (*HttpServiceResp, error) {
 	req := &HttpServiceReq{
@@ -714,6 +893,51 @@ func (c *WorkerHTTPClient) GetDirScanDicts(ctx context.Context, dictIds []string
 	return &resp, nil
 }
 
+// ==================== Brute Dict ====================
+
+// BruteDictReq 爆破字典获取请求
+type BruteDictReq struct {
+	WorkspaceId string   `json:"workspaceId"`
+	DictIds     []string `json:"dictIds"`
+}
+
+// BruteDictItem 爆破字典项
+type BruteDictItem struct {
+	Id       string   `json:"id"`
+	Name     string   `json:"name"`
+	Type     string   `json:"type"`
+	Services []string `json:"services"`
+	Words    []string `json:"words"`
+}
+
+// BruteDictResp 爆破字典获取响应
+type BruteDictResp struct {
+	Code  int             `json:"code"`
+	Msg   string          `json:"msg"`
+	Dicts []BruteDictItem `json:"dicts"`
+	Count int             `json:"count"`
+}
+
+// GetBruteDicts 获取工作空间的爆破字典
+func (c *WorkerHTTPClient) GetBruteDicts(ctx context.Context, workspaceId string, dictIds []string) (*BruteDictResp, error) {
+	req := &BruteDictReq{
+		WorkspaceId: workspaceId,
+		DictIds:     dictIds,
+	}
+
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/config/brutedict", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp BruteDictResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
+
 // ==================== Active Fingerprints ====================
 
 // ActiveFingerprintsReq 主动指纹获取请求
@@ -861,6 +1085,7 @@ type DirScanResultDocument struct {
 	ContentType   string `json:"contentType"`
 	Title         string `json:"title"`
 	RedirectURL   string `json:"redirectUrl"`
+	Source        string `json:"source"`
 }
 
 // DirScanResultReq 目录扫描结果上报请求
@@ -892,3 +1117,35 @@ func (c *WorkerHTTPClient) SaveDirScanResult(ctx context.Context, req *DirScanRe
 
 	return &resp, nil
 }
+
+// ==================== DNS Record ====================
+
+// DNSRecordReq DNS记录上报请求
+type DNSRecordReq struct {
+	WorkspaceId string               `json:"workspaceId"`
+	MainTaskId  string               `json:"mainTaskId"`
+	Records     []*scanner.DNSRecord `json:"records"`
+}
+
+// DNSRecordResp DNS记录上报响应
+type DNSRecordResp struct {
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+	Success bool   `json:"success"`
+	Total   int64  `json:"total"`
+}
+
+// SaveDNSRecords 保存DNS侦察采集的记录
+func (c *WorkerHTTPClient) SaveDNSRecords(ctx context.Context, req *DNSRecordReq) (*DNSRecordResp, error) {
+	respBody, err := c.doRequest(ctx, http.MethodPost, "/api/v1/worker/task/dnsrecord", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp DNSRecordResp
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("unmarshal response failed: %w", err)
+	}
+
+	return &resp, nil
+}
@@ -0,0 +1,30 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+
+	"cscan/scanner"
+)
+
+// loadInteractsh 获取OOB回连配置，获取失败时回退到 nuclei 默认公共服务
+func (w *Worker) loadInteractsh(ctx context.Context, taskId string) *scanner.InteractshOptions {
+	resp, err := w.httpClient.GetInteractshConfig(ctx)
+	if err == nil && resp.Code != 0 {
+		err = fmt.Errorf("%s", resp.Msg)
+	}
+	if err != nil {
+		w.taskLog(taskId, LevelWarn, "[OOB] Failed to load interactsh config, using default: %v", err)
+		return nil
+	}
+	opts := resp.Config
+	switch {
+	case opts == nil || opts.Mode == "" || opts.Mode == scanner.InteractshModePublic:
+		w.taskLog(taskId, LevelInfo, "[OOB] Using public interactsh servers")
+	case !opts.Enabled():
+		w.taskLog(taskId, LevelInfo, "[OOB] Interactsh disabled, OOB templates will be skipped")
+	default:
+		w.taskLog(taskId, LevelInfo, "[OOB] Using self-hosted interactsh server %s", opts.ServerURL)
+	}
+	return opts
+}
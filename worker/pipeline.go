@@ -0,0 +1,587 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// stagePreparer 在阶段执行前补全需要从服务端获取的选项（模板、指纹、字典、数据源密钥等）。
+// s 为本次分组独享的扫描器实例，可以安全地加载状态
+type stagePreparer func(w *Worker, ctx context.Context, task *scheduler.TaskInfo, s scanner.Scanner, opts interface{}, assets []*scanner.Asset) error
+
+// stagePreparers 按扫描器名称注册，没有注册的扫描器直接使用流水线中的选项
+var stagePreparers = map[string]stagePreparer{
+	"subfinder":   (*Worker).prepareSubfinderStage,
+	"fingerprint": (*Worker).prepareFingerprintStage,
+	"nuclei":      (*Worker).prepareNucleiStage,
+	"brute":       (*Worker).prepareBruteStage,
+	"cdndetect":   (*Worker).prepareCDNDetectStage,
+}
+
+// stageScoper 在阶段执行前把工作空间扫描范围应用到选项，如去掉排除的端口
+type stageScoper func(w *Worker, taskId string, opts interface{}, scope *scheduler.Scope) error
+
+// stageScopers 按扫描器名称注册，只在工作空间配置了扫描范围时调用
+var stageScopers = map[string]stageScoper{
+	"naabu": func(w *Worker, taskId string, opts interface{}, scope *scheduler.Scope) error {
+		return excludeScopePorts(&opts.(*scanner.NaabuOptions).Ports, scope)
+	},
+	"masscan": func(w *Worker, taskId string, opts interface{}, scope *scheduler.Scope) error {
+		return excludeScopePorts(&opts.(*scanner.MasscanOptions).Ports, scope)
+	},
+	"udpscan": func(w *Worker, taskId string, opts interface{}, scope *scheduler.Scope) error {
+		udpOpts := opts.(*scanner.UDPScanOptions)
+		// 未配置时扫描器使用默认端口，同样需要排除
+		if udpOpts.Ports == "" {
+			udpOpts.Ports = scanner.DefaultUDPPorts
+		}
+		return excludeScopePorts(&udpOpts.Ports, scope)
+	},
+	"cdndetect": func(w *Worker, taskId string, opts interface{}, scope *scheduler.Scope) error {
+		if cdnOpts := opts.(*scanner.CDNDetectOptions); cdnOpts.OriginDiscovery {
+			cdnOpts.HostAllowed = w.scopeHostAllowed(taskId, "CDN origin", scope)
+		}
+		return nil
+	},
+	"crawler": func(w *Worker, taskId string, opts interface{}, scope *scheduler.Scope) error {
+		opts.(*scanner.CrawlerOptions).AssetAllowed = w.scopeAssetAllowed(taskId, "Crawler", scope)
+		return nil
+	},
+}
+
+// stageFilters 按扫描器名称过滤阶段输入，与传统流程中对应阶段的过滤保持一致
+var stageFilters = map[string]func([]*scanner.Asset) []*scanner.Asset{
+	"brute": bruteTargets,
+}
+
+// pipelineRun 一次流水线执行的状态
+type pipelineRun struct {
+	w      *Worker
+	task   *scheduler.TaskInfo
+	target string
+	orgId  string
+	scope  *scheduler.Scope
+	// 工作空间限速，为空时不限速
+	limiter scanner.RateLimiter
+
+	seedOnce sync.Once
+	seed     []*scanner.Asset
+
+	prepareMu sync.Mutex // 预处理会修改 Worker 的全局状态（如HTTP服务映射），分组之间串行执行
+	mu        sync.Mutex
+	outputs   map[string][]*scanner.Asset
+	completed map[string]bool
+	vulCount  int
+}
+
+// executePipeline 按声明式流水线执行任务，同一层的阶段并行执行
+func (w *Worker) executePipeline(ctx context.Context, task *scheduler.TaskInfo, p *scheduler.Pipeline, target, orgId string, scope *scheduler.Scope, limiter scanner.RateLimiter, taskConfig map[string]interface{}, startTime time.Time) {
+	levels, err := p.Levels()
+	if err != nil {
+		w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusFailure, "流水线配置错误: "+err.Error())
+		return
+	}
+	for _, stage := range p.Stages {
+		if _, ok := scanner.Lookup(stage.Scanner); !ok {
+			w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusFailure, fmt.Sprintf("流水线配置错误: 阶段 %s 的扫描器 %s 不存在", stage.Name, stage.Scanner))
+			return
+		}
+	}
+
+	run := &pipelineRun{
+		w:         w,
+		task:      task,
+		target:    target,
+		orgId:     orgId,
+		scope:     scope,
+		limiter:   limiter,
+		outputs:   make(map[string][]*scanner.Asset),
+		completed: make(map[string]bool),
+	}
+	run.restore(taskConfig)
+
+	var names []string
+	for _, level := range levels {
+		var group []string
+		for _, i := range level {
+			group = append(group, p.Stages[i].Name)
+		}
+		names = append(names, strings.Join(group, " + "))
+	}
+	w.taskLog(task.TaskId, LevelInfo, "Pipeline: %s", strings.Join(names, " → "))
+
+	// 只统计本流水线中已完成的阶段，恢复状态里可能有旧配置的阶段名
+	done := 0
+	for _, stage := range p.Stages {
+		if run.completed[stage.Name] {
+			done++
+		}
+	}
+	for _, level := range levels {
+		if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
+			w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+			return
+		} else if ctrl == "PAUSE" {
+			w.taskLog(task.TaskId, LevelInfo, "Task paused, saving progress...")
+			run.saveProgress(ctx)
+			return
+		}
+
+		var wg sync.WaitGroup
+		ran := 0
+		for _, i := range level {
+			stage := p.Stages[i]
+			if run.completed[stage.Name] {
+				continue
+			}
+			ran++
+			w.updateTaskProgressWithPhase(ctx, task.TaskId, 10+done*80/len(p.Stages), stage.Name+"执行中", stage.Name)
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				output := run.runStage(ctx, stage)
+				run.mu.Lock()
+				run.outputs[stage.Name] = output
+				run.completed[stage.Name] = true
+				run.mu.Unlock()
+				w.incrSubTaskDone(ctx, task, stage.Name)
+			}()
+		}
+		wg.Wait()
+		done += ran
+
+		if ctx.Err() != nil {
+			w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+			return
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, assets := range run.outputs {
+		for _, asset := range assets {
+			seen[pipelineAssetKey(asset)] = true
+		}
+	}
+	duration := time.Since(startTime).Seconds()
+	result := fmt.Sprintf("Assets:%d Vuls:%d Duration:%.0fs", len(seen), run.vulCount, duration)
+	w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusSuccess, result)
+	w.taskLog(task.TaskId, LevelInfo, "Completed: %s", result)
+}
+
+// runStage 执行单个阶段，返回传递给下游的资产
+func (r *pipelineRun) runStage(ctx context.Context, stage scheduler.PipelineStage) []*scanner.Asset {
+	w, taskId := r.w, r.task.TaskId
+	targetLines, assets := r.stageInput(stage)
+	assets = w.filterAssetsInScope(taskId, stage.Name, r.scope, assets)
+	scanAssets := assets
+	if filter, ok := stageFilters[stage.Scanner]; ok {
+		scanAssets = filter(assets)
+	}
+	if len(targetLines) == 0 && len(scanAssets) == 0 {
+		w.taskLog(taskId, LevelInfo, "[%s] skipped (no input)", stage.Name)
+		return nil
+	}
+
+	if stage.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(stage.Timeout)*time.Second)
+		defer cancel()
+	}
+
+	fanOut := stage.FanOut
+	if fanOut < 1 {
+		fanOut = 1
+	}
+	lineChunks := splitChunks(targetLines, fanOut)
+	assetChunks := splitChunks(scanAssets, fanOut)
+	w.taskLog(taskId, LevelInfo, "[%s] %s: %d targets, %d assets, fan-out %d", stage.Name, stage.Scanner, len(targetLines), len(scanAssets), len(assetChunks))
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		result = &scanner.ScanResult{}
+	)
+	for i := 0; i < fanOut; i++ {
+		var lines []string
+		var chunk []*scanner.Asset
+		if i < len(lineChunks) {
+			lines = lineChunks[i]
+		}
+		if i < len(assetChunks) {
+			chunk = assetChunks[i]
+		}
+		if len(lines) == 0 && len(chunk) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			res, err := r.scanChunk(ctx, stage, lines, chunk)
+			if err != nil {
+				w.taskLog(taskId, LevelError, "[%s] %v", stage.Name, err)
+			}
+			if res == nil {
+				return
+			}
+			mu.Lock()
+			result.Assets = append(result.Assets, res.Assets...)
+			result.Vulnerabilities = append(result.Vulnerabilities, res.Vulnerabilities...)
+			result.DNSRecords = append(result.DNSRecords, res.DNSRecords...)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if ctx.Err() == context.DeadlineExceeded {
+		w.taskLog(taskId, LevelWarn, "[%s] timeout after %ds, continuing with partial results", stage.Name, stage.Timeout)
+	}
+
+	// 目录、爬虫发现的 URL 保存到目录扫描结果，其余保存为资产
+	found := w.filterAssetsInScope(taskId, stage.Name, r.scope, result.Assets)
+	var urlAssets, hostAssets []*scanner.Asset
+	for _, asset := range found {
+		if asset.Path != "" {
+			urlAssets = append(urlAssets, asset)
+		} else {
+			hostAssets = append(hostAssets, asset)
+		}
+	}
+	if len(hostAssets) > 0 {
+		w.saveAssetResult(ctx, r.task.WorkspaceId, r.task.MainTaskId, r.orgId, hostAssets)
+	}
+	if len(urlAssets) > 0 {
+		w.saveDirScanResults(ctx, r.task, urlAssets)
+	}
+	if len(result.DNSRecords) > 0 {
+		w.saveDNSRecords(ctx, r.task, result.DNSRecords)
+	}
+	if len(result.Vulnerabilities) > 0 {
+		w.saveVulResult(ctx, r.task.WorkspaceId, r.task.MainTaskId, result.Vulnerabilities)
+		r.mu.Lock()
+		r.vulCount += len(result.Vulnerabilities)
+		r.mu.Unlock()
+	}
+	w.taskLog(taskId, LevelInfo, "[%s] completed: %d assets, %d vulnerabilities", stage.Name, len(found), len(result.Vulnerabilities))
+
+	// 只产出漏洞的阶段（如 nuclei、brute）将输入原样传递给下游
+	if len(found) == 0 {
+		return assets
+	}
+	return found
+}
+
+// scanChunk 使用独立的扫描器和选项实例扫描一组输入，避免并行分组共享扫描器状态
+func (r *pipelineRun) scanChunk(ctx context.Context, stage scheduler.PipelineStage, lines []string, assets []*scanner.Asset) (*scanner.ScanResult, error) {
+	w, taskId := r.w, r.task.TaskId
+	reg, ok := scanner.Lookup(stage.Scanner)
+	if !ok {
+		return nil, fmt.Errorf("unknown scanner: %s", stage.Scanner)
+	}
+	s := reg.New()
+	opts, err := scanner.DecodeOptions(stage.Scanner, stage.Options)
+	if err != nil {
+		return nil, err
+	}
+	if prepare, ok := stagePreparers[stage.Scanner]; ok && opts != nil {
+		r.prepareMu.Lock()
+		err := prepare(w, ctx, r.task, s, opts, assets)
+		r.prepareMu.Unlock()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if applyScope, ok := stageScopers[stage.Scanner]; ok && r.scope != nil && opts != nil {
+		if err := applyScope(w, taskId, opts, r.scope); err != nil {
+			return nil, err
+		}
+	}
+
+	// 面向目标的扫描器（端口扫描、子域名等）使用 Target，面向资产的扫描器使用 Assets
+	targets := append([]string{}, lines...)
+	seen := make(map[string]bool, len(lines))
+	for _, line := range lines {
+		seen[line] = true
+	}
+	for _, asset := range assets {
+		if !seen[asset.Host] {
+			seen[asset.Host] = true
+			targets = append(targets, asset.Host)
+		}
+	}
+
+	return s.Scan(ctx, &scanner.ScanConfig{
+		Target:      strings.Join(targets, "\n"),
+		Assets:      assets,
+		Options:     opts,
+		WorkspaceId: r.task.WorkspaceId,
+		MainTaskId:  r.task.MainTaskId,
+		RateLimiter: r.limiter,
+		TaskLogger: func(level, format string, args ...interface{}) {
+			w.taskLog(taskId, level, "["+stage.Name+"] "+format, args...)
+		},
+	})
+}
+
+// excludeScopePorts 从端口配置中去掉工作空间排除的端口
+func excludeScopePorts(ports *string, scope *scheduler.Scope) error {
+	if *ports == "" || !scope.HasPortRules() {
+		return nil
+	}
+	*ports = scanner.ExcludePorts(*ports, func(port int) bool {
+		return !scope.AllowPort(port)
+	})
+	if *ports == "" {
+		// 端口为空时扫描器会回退到默认端口，不能继续执行
+		return fmt.Errorf("all ports are excluded by workspace scope")
+	}
+	return nil
+}
+
+// stageInput 汇总阶段的输入，返回原始目标行和经过条件过滤的资产
+func (r *pipelineRun) stageInput(stage scheduler.PipelineStage) ([]string, []*scanner.Asset) {
+	inputs := stage.Inputs
+	if len(inputs) == 0 {
+		inputs = []scheduler.StageInput{{From: scheduler.PipelineTarget}}
+	}
+
+	var lines []string
+	var assets []*scanner.Asset
+	seen := make(map[string]bool)
+	for _, in := range inputs {
+		var src []*scanner.Asset
+		if in.From == scheduler.PipelineTarget {
+			// 无条件的目标输入直接传递原始目标，保留 CIDR、IP段等格式
+			if in.When == nil {
+				lines = append(lines, splitTargetLines(r.target)...)
+				continue
+			}
+			src = r.seedAssets()
+		} else {
+			r.mu.Lock()
+			src = r.outputs[in.From]
+			r.mu.Unlock()
+		}
+		for _, asset := range src {
+			key := pipelineAssetKey(asset)
+			if seen[key] || !matchStageCondition(in.When, asset) {
+				continue
+			}
+			seen[key] = true
+			assets = append(assets, asset)
+		}
+	}
+	return lines, assets
+}
+
+// seedAssets 由任务目标生成的初始资产，供带条件的目标输入使用
+func (r *pipelineRun) seedAssets() []*scanner.Asset {
+	r.seedOnce.Do(func() {
+		r.seed = r.w.generateAssetsFromTarget(r.target, nil)
+	})
+	return r.seed
+}
+
+// restore 恢复暂停前已完成的阶段和阶段输出
+func (r *pipelineRun) restore(taskConfig map[string]interface{}) {
+	stateStr, _ := taskConfig["resumeState"].(string)
+	if stateStr == "" {
+		return
+	}
+	var state struct {
+		CompletedPhases []string `json:"completedPhases"`
+		StageOutputs    string   `json:"stageOutputs"`
+	}
+	if err := json.Unmarshal([]byte(stateStr), &state); err != nil {
+		return
+	}
+	json.Unmarshal([]byte(state.StageOutputs), &r.outputs)
+	for _, name := range state.CompletedPhases {
+		r.completed[name] = true
+	}
+	r.w.taskLog(r.task.TaskId, LevelInfo, "Resuming pipeline, completed stages: %s", strings.Join(state.CompletedPhases, ", "))
+}
+
+// saveProgress 保存已完成的阶段和阶段输出，继续执行时跳过已完成的阶段
+func (r *pipelineRun) saveProgress(ctx context.Context) {
+	r.mu.Lock()
+	phases := make([]string, 0, len(r.completed))
+	for name := range r.completed {
+		phases = append(phases, name)
+	}
+	outputsJson, _ := json.Marshal(r.outputs)
+	r.mu.Unlock()
+
+	stateJson, _ := json.Marshal(map[string]interface{}{
+		"completedPhases": phases,
+		"stageOutputs":    string(outputsJson),
+	})
+	r.w.httpClient.UpdateTask(ctx, &TaskUpdateReq{
+		TaskId: r.task.TaskId,
+		State:  "PAUSED",
+		Result: string(stateJson),
+	})
+	r.w.taskLog(r.task.TaskId, LevelInfo, "Task %s progress saved: completedStages=%v", r.task.TaskId, phases)
+}
+
+// matchStageCondition 判断资产是否满足输入边条件，条件为空时全部满足
+func matchStageCondition(c *scheduler.StageCondition, asset *scanner.Asset) bool {
+	if c == nil {
+		return true
+	}
+	if len(c.Apps) > 0 {
+		matched := false
+		for _, app := range asset.App {
+			name := parseAppName(app)
+			for _, want := range c.Apps {
+				if strings.EqualFold(name, want) {
+					matched = true
+				}
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(c.Services) > 0 && !containsFold(c.Services, asset.Service) {
+		return false
+	}
+	if len(c.Categories) > 0 && !containsFold(c.Categories, asset.Category) {
+		return false
+	}
+	if len(c.Ports) > 0 {
+		matched := false
+		for _, port := range c.Ports {
+			if port == asset.Port {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if c.HTTP != nil && *c.HTTP != asset.IsHTTP {
+		return false
+	}
+	return true
+}
+
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
+
+func pipelineAssetKey(asset *scanner.Asset) string {
+	return fmt.Sprintf("%s:%d%s", asset.Host, asset.Port, asset.Path)
+}
+
+func splitTargetLines(target string) []string {
+	var lines []string
+	for _, line := range strings.Split(target, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// splitChunks 将列表均分为最多 n 组
+func splitChunks[T any](list []T, n int) [][]T {
+	if len(list) == 0 {
+		return nil
+	}
+	if n > len(list) {
+		n = len(list)
+	}
+	chunks := make([][]T, 0, n)
+	size, rest := len(list)/n, len(list)%n
+	start := 0
+	for i := 0; i < n; i++ {
+		end := start + size
+		if i < rest {
+			end++
+		}
+		chunks = append(chunks, list[start:end])
+		start = end
+	}
+	return chunks
+}
+
+// prepareSubfinderStage 加载子域名数据源密钥
+func (w *Worker) prepareSubfinderStage(ctx context.Context, task *scheduler.TaskInfo, s scanner.Scanner, opts interface{}, assets []*scanner.Asset) error {
+	opts.(*scanner.SubfinderOptions).ProviderConfig = w.loadSubfinderProviders(ctx, task)
+	return nil
+}
+
+// prepareFingerprintStage 加载HTTP服务映射和自定义指纹
+func (w *Worker) prepareFingerprintStage(ctx context.Context, task *scheduler.TaskInfo, s scanner.Scanner, opts interface{}, assets []*scanner.Asset) error {
+	fpOpts := opts.(*scanner.FingerprintOptions)
+	if fpOpts.Concurrency <= 0 {
+		fpOpts.Concurrency = w.config.Concurrency
+	}
+	w.loadHttpServiceMappings()
+	if fpOpts.CustomEngine {
+		w.loadCustomFingerprints(ctx, s.(*scanner.FingerprintScanner), fpOpts.ActiveScan)
+	}
+	return nil
+}
+
+// prepareNucleiStage 按标签和资产指纹加载POC模板
+func (w *Worker) prepareNucleiStage(ctx context.Context, task *scheduler.TaskInfo, s scanner.Scanner, opts interface{}, assets []*scanner.Asset) error {
+	nucleiOpts := opts.(*scanner.NucleiOptions)
+	if len(nucleiOpts.CustomTemplates) == 0 {
+		tags := nucleiOpts.Tags
+		if nucleiOpts.AutoScan || nucleiOpts.AutomaticScan {
+			tags = append(tags, w.generateAutoTags(assets, &scheduler.PocScanConfig{
+				AutoScan:      nucleiOpts.AutoScan,
+				AutomaticScan: nucleiOpts.AutomaticScan,
+				TagMappings:   nucleiOpts.TagMappings,
+			})...)
+		}
+		var severities []string
+		if nucleiOpts.Severity != "" {
+			severities = strings.Split(nucleiOpts.Severity, ",")
+		}
+		nucleiOpts.CustomTemplates = w.getTemplatesByTags(ctx, tags, severities)
+		if len(nucleiOpts.CustomTemplates) == 0 {
+			return fmt.Errorf("no POC templates matched tags %v", tags)
+		}
+		// 标签已在Worker端解析为模板
+		nucleiOpts.Tags = tags
+		nucleiOpts.AutoScan, nucleiOpts.AutomaticScan = false, false
+	}
+	nucleiOpts.Interactsh = w.loadInteractsh(ctx, task.TaskId)
+	nucleiOpts.OnVulnerabilityFound = func(vul *scanner.Vulnerability) {
+		w.taskLog(task.TaskId, LevelInfo, "Vulnerability found: %s → %s", vul.PocFile, vul.Url)
+	}
+	return nil
+}
+
+// prepareBruteStage 加载工作空间用户名和密码字典
+func (w *Worker) prepareBruteStage(ctx context.Context, task *scheduler.TaskInfo, s scanner.Scanner, opts interface{}, assets []*scanner.Asset) error {
+	return w.loadBruteDicts(ctx, task, opts.(*scanner.BruteOptions))
+}
+
+// prepareCDNDetectStage 更新CDN节点IP段，开启源站发现时从服务端获取候选源站IP
+func (w *Worker) prepareCDNDetectStage(ctx context.Context, task *scheduler.TaskInfo, s scanner.Scanner, opts interface{}, assets []*scanner.Asset) error {
+	cdnOpts := opts.(*scanner.CDNDetectOptions)
+	if cdnOpts.UpdateRanges {
+		if err := scanner.UpdateCDNRanges(ctx, 24*time.Hour); err != nil {
+			w.taskLog(task.TaskId, LevelWarn, "CDN detect: update ranges failed: %v", err)
+		}
+	}
+	if cdnOpts.OriginDiscovery {
+		cdnOpts.OriginCandidates = w.originCandidates(task)
+	}
+	return nil
+}
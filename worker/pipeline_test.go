@@ -0,0 +1,153 @@
+package worker
+
+import (
+	"reflect"
+	"testing"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// TestMatchStageCondition 测试流水线输入边条件匹配
+func TestMatchStageCondition(t *testing.T) {
+	yes := true
+	asset := &scanner.Asset{
+		Host:     "example.com",
+		Port:     8080,
+		Service:  "http",
+		Category: "domain",
+		IsHTTP:   true,
+		App:      []string{"Apache Shiro[custom(abc)]", "Nginx"},
+	}
+	tests := []struct {
+		name string
+		cond *scheduler.StageCondition
+		want bool
+	}{
+		{"nil", nil, true},
+		{"app", &scheduler.StageCondition{Apps: []string{"apache shiro"}}, true},
+		{"app miss", &scheduler.StageCondition{Apps: []string{"tomcat"}}, false},
+		{"port", &scheduler.StageCondition{Ports: []int{80, 8080}}, true},
+		{"port miss", &scheduler.StageCondition{Ports: []int{443}}, false},
+		{"service and http", &scheduler.StageCondition{Services: []string{"HTTP"}, HTTP: &yes}, true},
+		{"category miss", &scheduler.StageCondition{Apps: []string{"nginx"}, Categories: []string{"ipv4"}}, false},
+	}
+	for _, tt := range tests {
+		if got := matchStageCondition(tt.cond, asset); got != tt.want {
+			t.Errorf("%s: matchStageCondition() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+// TestSplitChunks 测试扇出分组
+func TestSplitChunks(t *testing.T) {
+	tests := []struct {
+		list []int
+		n    int
+		want [][]int
+	}{
+		{nil, 3, nil},
+		{[]int{1, 2, 3, 4, 5}, 2, [][]int{{1, 2, 3}, {4, 5}}},
+		{[]int{1, 2}, 4, [][]int{{1}, {2}}},
+		{[]int{1, 2, 3}, 1, [][]int{{1, 2, 3}}},
+	}
+	for _, tt := range tests {
+		if got := splitChunks(tt.list, tt.n); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("splitChunks(%v, %d) = %v, want %v", tt.list, tt.n, got, tt.want)
+		}
+	}
+}
+
+// TestBruteStageFilter 测试流水线爆破阶段与传统流程一致，跳过 UDP、路径资产和重复端口
+func TestBruteStageFilter(t *testing.T) {
+	assets := []*scanner.Asset{
+		{Host: "10.0.0.1", Port: 22},
+		{Host: "10.0.0.1", Port: 22, Path: "/admin"},
+		{Host: "10.0.0.1", Port: 161, Transport: scanner.TransportUDP},
+		{Host: "10.0.0.1"},
+		{Host: "10.0.0.2", Port: 3306},
+		{Host: "10.0.0.2", Port: 3306},
+	}
+	got := stageFilters["brute"](assets)
+	var keys []string
+	for _, asset := range got {
+		keys = append(keys, pipelineAssetKey(asset))
+	}
+	if want := []string{"10.0.0.1:22", "10.0.0.2:3306"}; !reflect.DeepEqual(keys, want) {
+		t.Errorf("brute targets = %v, want %v", keys, want)
+	}
+}
+
+// TestStagePreparers 测试需要从服务端补全选项的扫描器都注册了预处理
+func TestStagePreparers(t *testing.T) {
+	for _, name := range []string{"subfinder", "fingerprint", "nuclei", "brute", "cdndetect"} {
+		if _, ok := stagePreparers[name]; !ok {
+			t.Errorf("scanner %s has no stage preparer", name)
+		}
+		if _, ok := scanner.Lookup(name); !ok {
+			t.Errorf("scanner %s is not registered", name)
+		}
+	}
+}
+
+// TestStageScopers 测试端口扫描阶段执行前去掉工作空间排除的端口
+func TestStageScopers(t *testing.T) {
+	scope, err := scheduler.NewScope(&scheduler.ScopeRules{ExcludedPorts: "22,53,161,3389,8000-8100"})
+	if err != nil {
+		t.Fatalf("NewScope: %v", err)
+	}
+	hostOnly, err := scheduler.NewScope(&scheduler.ScopeRules{AllowedDomains: []string{"example.com"}})
+	if err != nil {
+		t.Fatalf("NewScope: %v", err)
+	}
+	naabu := &scanner.NaabuOptions{Ports: "22,80,443,8080"}
+	masscan := &scanner.MasscanOptions{Ports: "21-23,3389,8443"}
+	udp := &scanner.UDPScanOptions{}
+	noPortRules := &scanner.NaabuOptions{Ports: "22,80"}
+	excluded := &scanner.NaabuOptions{Ports: "22,3389"}
+	tests := []struct {
+		name    string
+		scanner string
+		opts    interface{}
+		ports   *string
+		scope   *scheduler.Scope
+		want    string
+		wantErr bool
+	}{
+		{"naabu", "naabu", naabu, &naabu.Ports, scope, "80,443", false},
+		{"masscan", "masscan", masscan, &masscan.Ports, scope, "21,23,8443", false},
+		{"udp default ports", "udpscan", udp, &udp.Ports, scope, "69,123,623,1900", false},
+		{"no port rules", "naabu", noPortRules, &noPortRules.Ports, hostOnly, "22,80", false},
+		{"all excluded", "naabu", excluded, &excluded.Ports, scope, "", true},
+	}
+	for _, tt := range tests {
+		err := stageScopers[tt.scanner](nil, "", tt.opts, tt.scope)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: stage scoper error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && *tt.ports != tt.want {
+			t.Errorf("%s: ports = %q, want %q", tt.name, *tt.ports, tt.want)
+		}
+	}
+}
+
+// TestCDNDetectStageScoper 测试开启源站发现时按扫描范围过滤候选源站IP
+func TestCDNDetectStageScoper(t *testing.T) {
+	scope, err := scheduler.NewScope(&scheduler.ScopeRules{AllowedCidrs: []string{"47.88.0.0/16"}})
+	if err != nil {
+		t.Fatalf("NewScope: %v", err)
+	}
+	w := &Worker{}
+	opts := &scanner.CDNDetectOptions{}
+	if err := stageScopers["cdndetect"](w, "task", opts, scope); err != nil || opts.HostAllowed != nil {
+		t.Errorf("origin discovery disabled: HostAllowed set = %v, err = %v", opts.HostAllowed != nil, err)
+	}
+	opts.OriginDiscovery = true
+	if err := stageScopers["cdndetect"](w, "task", opts, scope); err != nil || opts.HostAllowed == nil {
+		t.Fatalf("origin discovery enabled: HostAllowed set = %v, err = %v", opts.HostAllowed != nil, err)
+	}
+	if !opts.HostAllowed("47.88.1.1") {
+		t.Error("HostAllowed(47.88.1.1) = false, want true")
+	}
+}
@@ -0,0 +1,107 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"cscan/pkg/ratelimit"
+	"cscan/scanner"
+)
+
+// rateLimitRetryInterval 令牌接口请求失败时的重试间隔，请求失败时不放行，避免超出限速预算
+const rateLimitRetryInterval = time.Second
+
+// rateLimiter 通过 API 从分布式令牌桶获取令牌，所有 Worker 和子任务共享工作空间的限速预算
+type rateLimiter struct {
+	client      *WorkerHTTPClient
+	workspaceId string
+	budget      *ratelimit.Budget
+
+	mu      sync.Mutex
+	reserve map[string]int // 按主机预取的剩余令牌，减少令牌接口请求次数
+}
+
+// loadRateLimiter 获取任务所属工作空间的限速预算，未配置时返回 nil
+func (w *Worker) loadRateLimiter(ctx context.Context, workspaceId string) (scanner.RateLimiter, error) {
+	resp, err := w.httpClient.GetRateLimitBudget(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("%s", resp.Msg)
+	}
+	if !resp.Budget.Enabled() {
+		return nil, nil
+	}
+	return newRateLimiter(w.httpClient, workspaceId, resp.Budget), nil
+}
+
+func newRateLimiter(client *WorkerHTTPClient, workspaceId string, budget *ratelimit.Budget) *rateLimiter {
+	return &rateLimiter{
+		client:      client,
+		workspaceId: workspaceId,
+		budget:      budget,
+		reserve:     make(map[string]int),
+	}
+}
+
+// Rate 访问 host 的最严格速率，host 为空时返回工作空间速率
+func (l *rateLimiter) Rate(host string) int {
+	return l.budget.Rate(l.workspaceId, host)
+}
+
+// Wait 阻塞直到获得访问 host 的 n 个令牌
+// 令牌数超过令牌桶容量时分多次获取，每次额外预取十分之一秒的令牌
+func (l *rateLimiter) Wait(ctx context.Context, host string, n int) error {
+	buckets := l.budget.Buckets(l.workspaceId, host)
+	if len(buckets) == 0 || n <= 0 {
+		return nil
+	}
+	key := ratelimit.NormalizeHost(host)
+	n = l.takeReserve(key, n)
+	prefetch := l.Rate(host) / 10
+
+	for n > 0 {
+		resp, err := l.client.AcquireRateLimit(ctx, &RateLimitAcquireReq{Buckets: buckets, Tokens: n + prefetch})
+		var wait time.Duration
+		switch {
+		case err != nil:
+			wait = rateLimitRetryInterval
+		case resp.Code != 0:
+			return fmt.Errorf("acquire rate limit tokens: %s", resp.Msg)
+		case resp.Granted > 0:
+			if resp.Granted > n {
+				l.mu.Lock()
+				l.reserve[key] += resp.Granted - n
+				l.mu.Unlock()
+			}
+			n -= resp.Granted
+			continue
+		default:
+			wait = time.Duration(resp.WaitMs) * time.Millisecond
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return nil
+}
+
+// takeReserve 优先使用预取的令牌，返回仍需获取的令牌数
+func (l *rateLimiter) takeReserve(key string, n int) int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	used := min(l.reserve[key], n)
+	l.reserve[key] -= used
+	if l.reserve[key] == 0 {
+		delete(l.reserve, key)
+	}
+	return n - used
+}
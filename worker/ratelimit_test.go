@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"cscan/pkg/ratelimit"
+)
+
+// TestRateLimiterWait 测试令牌获取：预取的令牌在后续请求中使用，令牌不足时按返回的时间等待
+func TestRateLimiterWait(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req RateLimitAcquireReq
+		json.NewDecoder(r.Body).Decode(&req)
+		resp := RateLimitAcquireResp{Granted: req.Tokens}
+		// 第二次请求令牌不足
+		if calls.Add(1) == 2 {
+			resp = RateLimitAcquireResp{WaitMs: 20}
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	defer srv.Close()
+
+	l := newRateLimiter(NewWorkerHTTPClient(srv.URL, "key", "w1"), "ws1", &ratelimit.Budget{HostRate: 50})
+	ctx := context.Background()
+
+	// 请求 1 个令牌，额外预取 5 个
+	if err := l.Wait(ctx, "10.0.0.1", 1); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	if err := l.Wait(ctx, "http://10.0.0.1:8080/", 5); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Fatalf("acquire calls = %d, want 1 (reserve used)", got)
+	}
+
+	start := time.Now()
+	if err := l.Wait(ctx, "10.0.0.1", 1); err != nil {
+		t.Fatalf("Wait() error: %v", err)
+	}
+	if calls.Load() != 3 || time.Since(start) < 20*time.Millisecond {
+		t.Errorf("Wait() should retry after waitMs, calls = %d", calls.Load())
+	}
+
+	// 未配置主机限速的目标不请求令牌
+	l = newRateLimiter(l.client, "ws1", &ratelimit.Budget{CIDRRate: 10})
+	if err := l.Wait(ctx, "example.com", 1); err != nil || calls.Load() != 3 {
+		t.Errorf("Wait(domain) = %v, calls = %d", err, calls.Load())
+	}
+
+	cctx, cancel := context.WithCancel(ctx)
+	cancel()
+	l = newRateLimiter(NewWorkerHTTPClient("http://127.0.0.1:0", "key", "w1"), "ws1", &ratelimit.Budget{WorkspaceRate: 10})
+	if err := l.Wait(cctx, "10.0.0.1", 1); err == nil {
+		t.Error("Wait() with canceled context expected error")
+	}
+}
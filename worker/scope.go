@@ -0,0 +1,83 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"cscan/scanner"
+	"cscan/scheduler"
+)
+
+// loadScope 获取任务所属工作空间的扫描范围，未配置时返回 nil
+func (w *Worker) loadScope(ctx context.Context, workspaceId string) (*scheduler.Scope, error) {
+	resp, err := w.httpClient.GetScanScope(ctx, workspaceId)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("%s", resp.Msg)
+	}
+	return scheduler.NewScope(resp.Scope)
+}
+
+// logScopeDrops 记录被扫描范围过滤的目标
+func (w *Worker) logScopeDrops(taskId, phase string, drops []scheduler.ScopeDrop) {
+	for _, d := range drops {
+		w.taskLog(taskId, LevelWarn, "[Scope] %s: skip out-of-scope target %s", phase, d)
+	}
+}
+
+// scopeHostAllowed 返回检查主机是否在扫描范围内的函数，被过滤的主机写入任务日志
+func (w *Worker) scopeHostAllowed(taskId, phase string, scope *scheduler.Scope) func(host string) bool {
+	return func(host string) bool {
+		ok, reason := scope.AllowHost(host)
+		if !ok {
+			w.logScopeDrops(taskId, phase, []scheduler.ScopeDrop{{Target: host, Reason: reason}})
+		}
+		return ok
+	}
+}
+
+// scopeAssetAllowed 返回检查主机和端口是否在扫描范围内的函数，可并发调用，同一目标只记录一次日志
+func (w *Worker) scopeAssetAllowed(taskId, phase string, scope *scheduler.Scope) func(host string, port int) bool {
+	var mu sync.Mutex
+	logged := make(map[string]bool)
+	return func(host string, port int) bool {
+		ok, reason := scope.AllowAsset(host, port)
+		if !ok {
+			target := host + ":" + strconv.Itoa(port)
+			mu.Lock()
+			first := !logged[target]
+			logged[target] = true
+			mu.Unlock()
+			if first {
+				w.logScopeDrops(taskId, phase, []scheduler.ScopeDrop{{Target: target, Reason: reason}})
+			}
+		}
+		return ok
+	}
+}
+
+// filterAssetsInScope 过滤超出扫描范围的资产，每个被过滤的资产写入任务日志
+func (w *Worker) filterAssetsInScope(taskId, phase string, scope *scheduler.Scope, assets []*scanner.Asset) []*scanner.Asset {
+	if scope == nil || len(assets) == 0 {
+		return assets
+	}
+	kept := make([]*scanner.Asset, 0, len(assets))
+	var drops []scheduler.ScopeDrop
+	for _, asset := range assets {
+		if ok, reason := scope.AllowAsset(asset.Host, asset.Port); !ok {
+			target := asset.Host
+			if asset.Port > 0 {
+				target += ":" + strconv.Itoa(asset.Port)
+			}
+			drops = append(drops, scheduler.ScopeDrop{Target: target, Reason: reason})
+			continue
+		}
+		kept = append(kept, asset)
+	}
+	w.logScopeDrops(taskId, phase, drops)
+	return kept
+}
@@ -4,9 +4,12 @@ import (
 	"os"
 	"os/exec"
 	"runtime"
+	"sort"
 	"strings"
 	"time"
 
+	"cscan/scheduler"
+
 	"github.com/shirou/gopsutil/v3/cpu"
 	"github.com/shirou/gopsutil/v3/disk"
 	"github.com/shirou/gopsutil/v3/host"
@@ -121,15 +124,31 @@ func (c *SysInfoCollector) detectTools() map[string]bool {
 		"httpx":     false,
 		"ffuf":      false,
 		"dirsearch": false,
+		"chromium":  false,
 	}
 
 	for tool := range tools {
 		tools[tool] = c.isToolInstalled(tool)
 	}
+	// 截图和无头爬虫使用的浏览器，可通过 CHROME_BIN 指定
+	tools["chromium"] = os.Getenv("CHROME_BIN") != "" || c.isToolInstalled("chromium") ||
+		c.isToolInstalled("chromium-browser") || c.isToolInstalled("google-chrome")
 
 	return tools
 }
 
+// ToolLabels 已安装工具的 tool=<工具名> 标签，用于获取需要特定工具的任务
+func (c *SysInfoCollector) ToolLabels() []string {
+	var labels []string
+	for tool, installed := range c.detectTools() {
+		if installed {
+			labels = append(labels, scheduler.ToolLabel(tool))
+		}
+	}
+	sort.Strings(labels)
+	return labels
+}
+
 // isToolInstalled 检查工具是否已安装
 func (c *SysInfoCollector) isToolInstalled(toolName string) bool {
 	// 尝试使用 which/where 命令查找
@@ -7,6 +7,7 @@ import (
 	"net"
 	"os"
 	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 	"sync"
@@ -25,12 +26,14 @@ import (
 
 // WorkerConfig Worker配置
 type WorkerConfig struct {
-	Name        string `json:"name"`
-	IP          string `json:"ip"`
-	ServerAddr  string `json:"serverAddr"`  // API 服务地址 (e.g., http://server:8888)
-	InstallKey  string `json:"installKey"`  // 安装密钥
-	Concurrency int    `json:"concurrency"`
-	Timeout     int    `json:"timeout"`
+	Name        string   `json:"name"`
+	IP          string   `json:"ip"`
+	ServerAddr  string   `json:"serverAddr"` // API 服务地址 (e.g., http://server:8888)
+	InstallKey  string   `json:"installKey"` // 安装密钥
+	Concurrency int      `json:"concurrency"`
+	Timeout     int      `json:"timeout"`
+	Labels      []string `json:"labels"`    // 自定义标签（key=value），如 region、zone、vantage
+	LabelOnly   bool     `json:"labelOnly"` // 只执行标签选择器匹配的任务，不从公共队列获取
 }
 
 // Worker 工作节点
@@ -69,6 +72,9 @@ type Worker struct {
 	// 系统信息收集器
 	sysInfoCollector *SysInfoCollector
 
+	// Worker 标签（自定义标签和启动时检测的已安装工具），拉取任务时上报
+	labels []string
+
 	// 文件管理器
 	fileManager *FileManager
 
@@ -199,6 +205,8 @@ func NewWorker(config WorkerConfig) (*Worker, error) {
 		logger:           NewWorkerLoggerLocal(config.Name), // 使用本地日志
 		sysInfoCollector: NewSysInfoCollector(config.Name, config.IP, workerVersion),
 	}
+	w.labels = append(append([]string{}, config.Labels...), w.sysInfoCollector.ToolLabels()...)
+	sort.Strings(w.labels)
 
 	// 创建 WebSocket 客户端
 	wsConfig := DefaultWSClientConfig(config.ServerAddr, config.Name, config.InstallKey)
@@ -349,16 +357,12 @@ func (w *Worker) GetWorkerInfo() *WorkerInfoPayload {
 	return w.sysInfoCollector.Collect(taskStarted, taskRunning, concurrency)
 }
 
-// registerScanners 注册扫描器
+// registerScanners 注册扫描器，扫描器在 scanner 包中自行注册
 func (w *Worker) registerScanners() {
-	w.scanners["portscan"] = scanner.NewPortScanner()
-	w.scanners["masscan"] = scanner.NewMasscanScanner()
-	w.scanners["nmap"] = scanner.NewNmapScanner()
-	w.scanners["naabu"] = scanner.NewNaabuScanner()
-	w.scanners["subfinder"] = scanner.NewSubfinderScanner()
-	w.scanners["fingerprint"] = scanner.NewFingerprintScanner()
-	w.scanners["nuclei"] = scanner.NewNucleiScanner()
-	w.scanners["urlfinder"] = scanner.NewURLFinderScanner()
+	for _, name := range scanner.Registered() {
+		reg, _ := scanner.Lookup(name)
+		w.scanners[name] = reg.New()
+	}
 }
 
 // Start 启动Worker
@@ -626,7 +630,7 @@ func (w *Worker) pullTask() bool {
 	}
 
 	// 通过 HTTP 接口获取任务
-	resp, err := w.httpClient.CheckTask(ctx)
+	resp, err := w.httpClient.CheckTask(ctx, w.labels, w.config.LabelOnly)
 	if err != nil {
 		w.logger.Debug("pullTask: CheckTask failed: %v", err)
 		return false
@@ -883,6 +887,9 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 	if config.DomainScan != nil && config.DomainScan.Enable {
 		enabledPhases = append(enabledPhases, "Domain Scan")
 	}
+	if config.CDN != nil && config.CDN.Enable {
+		enabledPhases = append(enabledPhases, "CDN Detect")
+	}
 	if config.PortScan != nil && config.PortScan.Enable {
 		enabledPhases = append(enabledPhases, "Port Scan")
 	}
@@ -892,13 +899,53 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 	if config.Fingerprint != nil && config.Fingerprint.Enable {
 		enabledPhases = append(enabledPhases, "Fingerprint")
 	}
+	if config.Crawler != nil && config.Crawler.Enable {
+		enabledPhases = append(enabledPhases, "Crawler")
+	}
 	if config.DirScan != nil && config.DirScan.Enable {
 		enabledPhases = append(enabledPhases, "Dir Scan")
 	}
+	if config.Brute != nil && config.Brute.Enable {
+		enabledPhases = append(enabledPhases, "Brute")
+	}
 	if config.PocScan != nil && config.PocScan.Enable {
 		enabledPhases = append(enabledPhases, "POC Scan")
 	}
 
+	// 获取工作空间扫描范围，过滤超出范围的目标和端口
+	scope, err := w.loadScope(ctx, task.WorkspaceId)
+	if err != nil {
+		w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusFailure, "获取扫描范围失败: "+err.Error())
+		return
+	}
+	// 获取工作空间限速预算，各扫描器通过分布式令牌桶协调访问速率
+	limiter, err := w.loadRateLimiter(ctx, task.WorkspaceId)
+	if err != nil {
+		w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusFailure, "获取限速配置失败: "+err.Error())
+		return
+	}
+	if scope != nil {
+		var drops []scheduler.ScopeDrop
+		target, drops = scope.FilterTargets(target)
+		w.logScopeDrops(task.TaskId, "Target", drops)
+		if target == "" {
+			w.updateTaskStatus(ctx, task.TaskId, scheduler.TaskStatusFailure, "所有目标均不在工作空间扫描范围内")
+			return
+		}
+		if scope.HasPortRules() && config.PortScan != nil && config.PortScan.Ports != "" {
+			config.PortScan.Ports = scanner.ExcludePorts(config.PortScan.Ports, func(port int) bool {
+				return !scope.AllowPort(port)
+			})
+			w.taskLog(task.TaskId, LevelInfo, "[Scope] Ports after exclusion: %s", config.PortScan.Ports)
+		}
+		if scope.HasPortRules() && config.PortScan != nil && config.PortScan.UdpPorts != "" {
+			config.PortScan.UdpPorts = scanner.ExcludePorts(config.PortScan.UdpPorts, func(port int) bool {
+				return !scope.AllowPort(port)
+			})
+			w.taskLog(task.TaskId, LevelInfo, "[Scope] UDP ports after exclusion: %s", config.PortScan.UdpPorts)
+		}
+	}
+
 	// 解析目标列表
 	targetLines := strings.Split(strings.TrimSpace(target), "\n")
 	var targets []string
@@ -909,6 +956,13 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		}
 	}
 
+	// 配置了声明式流水线时按流水线执行
+	if config.Pipeline.Enabled() {
+		w.taskLog(task.TaskId, LevelInfo, "Targets (%d): %s", len(targets), strings.Join(targets, ", "))
+		w.executePipeline(ctx, task, config.Pipeline, target, orgId, scope, limiter, taskConfig, startTime)
+		return
+	}
+
 	// 输出任务开始日志
 	w.taskLog(task.TaskId, LevelInfo, "Starting: %s", strings.Join(enabledPhases, " → "))
 	w.taskLog(task.TaskId, LevelInfo, "Targets (%d): %s", len(targets), strings.Join(targets, ", "))
@@ -968,24 +1022,12 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		domainTaskLogger := func(level, format string, args ...interface{}) {
 			w.taskLog(task.TaskId, level, format, args...)
 		}
+		// 主动侦察只针对原始目标中的域名
+		domainTarget := target
+		var subdomainAssets []*scanner.Asset
 
 		// 通过 HTTP 接口获取 Subfinder 配置
-		var providerConfig map[string][]string
-		providerResp, err := w.httpClient.GetSubfinderProviders(ctx, task.WorkspaceId)
-		if err != nil {
-			w.taskLog(task.TaskId, LevelWarn, "Failed to get subfinder providers: %v", err)
-		} else if providerResp != nil && len(providerResp.Providers) > 0 {
-			providerConfig = make(map[string][]string)
-			for _, p := range providerResp.Providers {
-				if len(p.Keys) > 0 {
-					providerConfig[p.Provider] = p.Keys
-					w.taskLog(task.TaskId, LevelDebug, "Subfinder provider: %s, keys: %d", p.Provider, len(p.Keys))
-				}
-			}
-			w.taskLog(task.TaskId, LevelInfo, "Loaded %d subfinder providers with keys", len(providerConfig))
-		} else {
-			w.taskLog(task.TaskId, LevelInfo, "No subfinder providers configured in database")
-		}
+		providerConfig := w.loadSubfinderProviders(ctx, task)
 
 		// 构建Subfinder选项，使用Worker并发数
 		subfinderOpts := &scanner.SubfinderOptions{
@@ -1022,12 +1064,18 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 				TaskLogger:  domainTaskLogger,
 			})
 
+			// 过滤超出扫描范围的子域名
+			if err == nil && result != nil {
+				result.Assets = w.filterAssetsInScope(task.TaskId, "Domain Scan", scope, result.Assets)
+			}
+
 			if err != nil {
 				w.taskLog(task.TaskId, LevelError, "Domain scan error: %v", err)
 			} else if result != nil && len(result.Assets) > 0 {
 				// 保存子域名扫描结果到数据库
 				w.taskLog(task.TaskId, LevelInfo, "Saving %d subdomains to database", len(result.Assets))
 				w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, result.Assets)
+				subdomainAssets = result.Assets
 
 				// 将发现的子域名添加到目标列表
 				var newTargets []string
@@ -1046,11 +1094,98 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 			w.taskLog(task.TaskId, LevelWarn, "Subfinder scanner not available")
 		}
 
+		// 主动DNS侦察：爆破、排列组合、域传送、记录采集和子域名接管检测
+		if dc := config.DomainScan; (dc.Brute || dc.Permutation || dc.AXFR || dc.DNSRecords || dc.Takeover) && ctx.Err() == nil {
+			reconAssets := w.executeDNSRecon(ctx, task, domainTarget, subdomainAssets, dc)
+			reconAssets = w.filterAssetsInScope(task.TaskId, "DNS Recon", scope, reconAssets)
+			if len(reconAssets) > 0 {
+				w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, reconAssets)
+				var newTargets []string
+				for _, asset := range reconAssets {
+					newTargets = append(newTargets, asset.Host)
+				}
+				target = target + "\n" + strings.Join(newTargets, "\n")
+				w.taskLog(task.TaskId, LevelInfo, "DNS recon completed: found %d new subdomains", len(newTargets))
+			}
+		}
+
 		completedPhases["domainscan"] = true
 		// 子域名扫描模块完成，递增子任务进度
 		w.incrSubTaskDone(ctx, task, "子域名扫描")
 	}
 
+	// 执行CDN/WAF/云厂商识别（不单独计入子任务进度，恢复任务时重新识别，端口扫描已完成时跳过）
+	portTarget := target
+	cdnHosts := make(map[string]*scanner.Asset)
+	if config.CDN != nil && config.CDN.Enable && !completedPhases["portscan"] && ctx.Err() == nil {
+		w.updateTaskProgressWithPhase(ctx, task.TaskId, 15, "CDN识别中", "CDN识别")
+		result := w.executeCDNDetect(ctx, task, target, config.CDN, scope)
+		if ctx.Err() != nil {
+			w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+			return
+		}
+		if result != nil {
+			var domainAssets, originAssets []*scanner.Asset
+			for _, asset := range result.Assets {
+				if asset.Source == "cdn-origin" {
+					originAssets = append(originAssets, asset)
+					continue
+				}
+				cdnHosts[asset.Host] = asset
+				if asset.Category == "domain" {
+					domainAssets = append(domainAssets, asset)
+				}
+			}
+			domainAssets = w.filterAssetsInScope(task.TaskId, "CDN Detect", scope, domainAssets)
+			if len(domainAssets) > 0 {
+				w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, domainAssets)
+			}
+			if len(result.Vulnerabilities) > 0 {
+				w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+			}
+			for _, asset := range allAssets {
+				if info := cdnHosts[asset.Host]; info != nil {
+					scanner.ApplyCDNInfo(asset, info)
+				}
+			}
+
+			// CDN节点的端口是边缘节点的端口，不做端口扫描，域名只探测Web端口
+			if config.CDN.SkipPortScan {
+				var lines, cdnDomains []string
+				skipped := 0
+				for _, line := range strings.Split(target, "\n") {
+					host := scanner.TargetHost(line)
+					if info := cdnHosts[host]; info != nil && info.IsCDN {
+						skipped++
+						if info.Category == "domain" {
+							cdnDomains = append(cdnDomains, host)
+						}
+						continue
+					}
+					lines = append(lines, line)
+				}
+				if skipped > 0 {
+					portTarget = strings.Join(lines, "\n")
+					w.taskLog(task.TaskId, LevelInfo, "CDN detect: skip port scan on %d CDN hosts", skipped)
+					if config.PortScan != nil && config.PortScan.Enable {
+						webAssets := w.generateHTTPAssetsFromTarget(strings.Join(cdnDomains, "\n"))
+						for _, asset := range webAssets {
+							asset.Category = "domain"
+							scanner.ApplyCDNInfo(asset, cdnHosts[asset.Host])
+						}
+						allAssets = append(allAssets, webAssets...)
+					}
+				}
+			}
+
+			// 源站IP加入端口扫描
+			originAssets = w.filterAssetsInScope(task.TaskId, "CDN Origin", scope, originAssets)
+			for _, asset := range originAssets {
+				portTarget += "\n" + asset.Host
+			}
+		}
+	}
+
 	// 执行端口扫描（只有明确启用时才执行）
 	if config.PortScan != nil && config.PortScan.Enable && !completedPhases["portscan"] {
 		// 检查控制信号
@@ -1096,15 +1231,18 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		}
 
 		// 第一步：端口发现
-		switch portDiscoveryTool {
-		case "masscan":
+		switch {
+		case strings.TrimSpace(portTarget) == "":
+			w.taskLog(task.TaskId, LevelInfo, "Port scan: no targets left after CDN filtering")
+		case portDiscoveryTool == "masscan":
 			w.taskLog(task.TaskId, LevelInfo, "Port scan: Masscan")
 			masscanScanner := w.scanners["masscan"]
 			masscanResult, err := masscanScanner.Scan(portCtx, &scanner.ScanConfig{
-				Target:     target,
-				Options:    config.PortScan,
-				TaskLogger: taskLogger,
-				OnProgress: onProgress,
+				Target:      portTarget,
+				Options:     config.PortScan,
+				TaskLogger:  taskLogger,
+				OnProgress:  onProgress,
+				RateLimiter: limiter,
 			})
 			// 检查是否被停止或超时
 			if portCtx.Err() == context.DeadlineExceeded {
@@ -1125,10 +1263,11 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 			w.taskLog(task.TaskId, LevelInfo, "Port scan: Naabu")
 			naabuScanner := w.scanners["naabu"]
 			naabuResult, err := naabuScanner.Scan(portCtx, &scanner.ScanConfig{
-				Target:     target,
-				Options:    config.PortScan,
-				TaskLogger: taskLogger,
-				OnProgress: onProgress,
+				Target:      portTarget,
+				Options:     config.PortScan,
+				TaskLogger:  taskLogger,
+				OnProgress:  onProgress,
+				RateLimiter: limiter,
 			})
 			// 检查是否有目标超过端口阈值（不终止任务，只记录警告）
 			if err == scanner.ErrPortThresholdExceeded {
@@ -1151,6 +1290,26 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 			}
 		}
 
+		// UDP 端口发现（配置了UDP端口时执行）
+		if config.PortScan.UdpPorts != "" && strings.TrimSpace(portTarget) != "" && ctx.Err() == nil {
+			w.taskLog(task.TaskId, LevelInfo, "Port scan: UDP")
+			udpResult, err := w.scanners["udpscan"].Scan(portCtx, &scanner.ScanConfig{
+				Target: portTarget,
+				Options: &scanner.UDPScanOptions{
+					Ports:   config.PortScan.UdpPorts,
+					Timeout: config.PortScan.Timeout,
+				},
+				TaskLogger: taskLogger,
+			})
+			if err != nil {
+				w.taskLog(task.TaskId, LevelError, "UDP scan error: %v", err)
+			}
+			if udpResult != nil && len(udpResult.Assets) > 0 {
+				openPorts = append(openPorts, udpResult.Assets...)
+				w.taskLog(task.TaskId, LevelInfo, "Found %d open UDP ports", len(udpResult.Assets))
+			}
+		}
+
 		// 检查是否被停止
 		if ctx.Err() != nil || w.checkTaskControl(ctx, task.TaskId) == "STOP" {
 			portCancel()
@@ -1159,9 +1318,13 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		}
 
 		// 端口发现完成，将结果添加到 allAssets
+		openPorts = w.filterAssetsInScope(task.TaskId, "Port Scan", scope, openPorts)
 		if len(openPorts) > 0 {
 			for _, asset := range openPorts {
-				asset.IsHTTP = scanner.IsHTTPService(asset.Service, asset.Port)
+				asset.IsHTTP = asset.Transport != scanner.TransportUDP && scanner.IsHTTPService(asset.Service, asset.Port)
+				if info := cdnHosts[asset.Host]; info != nil {
+					scanner.ApplyCDNInfo(asset, info)
+				}
 			}
 			allAssets = append(allAssets, openPorts...)
 			w.taskLog(task.TaskId, LevelInfo, "Port scan completed: %d assets", len(allAssets))
@@ -1188,8 +1351,14 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		return
 	}
 
+	// 按阶段分布式执行时，之后每个阶段开始前检查是否需要交给具备其他能力的 Worker
+	if w.handoffPhases(ctx, task, config, "portidentify", completedPhases, allAssets) {
+		return
+	}
+
 	// 执行端口识别（Nmap服务识别）- 独立阶段
 	if config.PortIdentify != nil && config.PortIdentify.Enable && !completedPhases["portidentify"] {
+		allAssets = w.filterAssetsInScope(task.TaskId, "Port Identify", scope, allAssets)
 		// 没有资产时跳过实际扫描，但仍需递增进度
 		if len(allAssets) == 0 {
 			w.taskLog(task.TaskId, LevelInfo, "Port identify: skipped (no assets)")
@@ -1231,8 +1400,39 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		return
 	}
 
+	if w.handoffPhases(ctx, task, config, "tlsscan", completedPhases, allAssets) {
+		return
+	}
+
+	// 执行TLS证书采集（不单独计入子任务进度）
+	if config.TLSScan != nil && config.TLSScan.Enable && !completedPhases["tlsscan"] && len(allAssets) > 0 {
+		w.updateTaskProgressWithPhase(ctx, task.TaskId, 45, "TLS证书采集中", "TLS证书采集")
+		sanAssets := w.executeTLSScan(ctx, task, allAssets, config.TLSScan)
+		if ctx.Err() != nil {
+			w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+			return
+		}
+		w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, allAssets)
+		var tlsVuls []*scanner.Vulnerability
+		for _, asset := range allAssets {
+			tlsVuls = append(tlsVuls, scanner.TLSFindingVuls(asset)...)
+		}
+		w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, tlsVuls)
+		sanAssets = w.filterAssetsInScope(task.TaskId, "TLS SAN", scope, sanAssets)
+		if len(sanAssets) > 0 {
+			w.taskLog(task.TaskId, LevelInfo, "TLS scan: saving %d domains from certificate SANs", len(sanAssets))
+			w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, sanAssets)
+		}
+		completedPhases["tlsscan"] = true
+	}
+
+	if w.handoffPhases(ctx, task, config, "fingerprint", completedPhases, allAssets) {
+		return
+	}
+
 	// 执行指纹识别
 	if config.Fingerprint != nil && config.Fingerprint.Enable && !completedPhases["fingerprint"] {
+		allAssets = w.filterAssetsInScope(task.TaskId, "Fingerprint", scope, allAssets)
 		// 没有资产时跳过实际扫描，但仍需递增进度
 		if len(allAssets) == 0 {
 			w.taskLog(task.TaskId, LevelInfo, "Fingerprint: skipped (no assets)")
@@ -1283,9 +1483,10 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 			}
 
 			result, err := s.Scan(fpCtx, &scanner.ScanConfig{
-				Assets:     allAssets,
-				Options:    config.Fingerprint,
-				TaskLogger: fpTaskLogger,
+				Assets:      allAssets,
+				Options:     config.Fingerprint,
+				TaskLogger:  fpTaskLogger,
+				RateLimiter: limiter,
 			})
 			fpCancel()
 
@@ -1344,6 +1545,75 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		return
 	}
 
+	if w.handoffPhases(ctx, task, config, "crawler", completedPhases, allAssets) {
+		return
+	}
+
+	// 执行网页爬虫（在指纹识别之后、目录扫描之前），发现的URL供目录扫描和POC扫描使用
+	if config.Crawler != nil && config.Crawler.Enable && !completedPhases["crawler"] {
+		allAssets = w.filterAssetsInScope(task.TaskId, "Crawler", scope, allAssets)
+		if len(allAssets) == 0 {
+			w.taskLog(task.TaskId, LevelInfo, "Crawler: skipped (no assets)")
+		} else {
+			if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
+				w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+				return
+			} else if ctrl == "PAUSE" {
+				w.taskLog(task.TaskId, LevelInfo, "Task paused, saving progress...")
+				w.saveTaskProgress(ctx, task, completedPhases, allAssets)
+				return
+			}
+
+			w.updateTaskProgressWithPhase(ctx, task.TaskId, 65, "网页爬虫中", "网页爬虫")
+			result := w.executeCrawler(ctx, task, allAssets, config.Crawler, scope)
+			if ctx.Err() != nil {
+				w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+				return
+			}
+			if result != nil {
+				var urlAssets, siteAssets, domainAssets []*scanner.Asset
+				for _, asset := range result.Assets {
+					switch {
+					case asset.Category == "domain":
+						domainAssets = append(domainAssets, asset)
+					case asset.Path != "":
+						urlAssets = append(urlAssets, asset)
+					default:
+						siteAssets = append(siteAssets, asset)
+					}
+				}
+				// URL 按地址保存到目录扫描结果，资产表以 host:port 为键，不逐条保存
+				if len(urlAssets) > 0 {
+					w.saveDirScanResults(ctx, task, urlAssets)
+					allAssets = append(allAssets, urlAssets...)
+				}
+				// 新发现的站点和子域名可能超出扫描范围
+				siteAssets = w.filterAssetsInScope(task.TaskId, "Crawler", scope, siteAssets)
+				if len(siteAssets) > 0 {
+					w.taskLog(task.TaskId, LevelInfo, "Crawler: found %d new sites", len(siteAssets))
+					w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, siteAssets)
+					allAssets = append(allAssets, siteAssets...)
+				}
+				if config.Crawler.SaveSubdomains {
+					domainAssets = w.filterAssetsInScope(task.TaskId, "Crawler", scope, domainAssets)
+					if len(domainAssets) > 0 {
+						w.taskLog(task.TaskId, LevelInfo, "Crawler: saving %d subdomains", len(domainAssets))
+						w.saveAssetResult(ctx, task.WorkspaceId, task.MainTaskId, orgId, domainAssets)
+					}
+				}
+				if len(result.Vulnerabilities) > 0 {
+					w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+				}
+			}
+		}
+		completedPhases["crawler"] = true
+		w.incrSubTaskDone(ctx, task, "网页爬虫")
+	}
+
+	if w.handoffPhases(ctx, task, config, "dirscan", completedPhases, allAssets) {
+		return
+	}
+
 	// 执行目录扫描（在指纹识别之后、POC扫描之前）
 	if config.DirScan != nil && config.DirScan.Enable && !completedPhases["dirscan"] {
 		// 如果没有资产，尝试从目标生成 HTTP 资产（用于只启用目录扫描的场景）
@@ -1354,6 +1624,7 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 				w.taskLog(task.TaskId, LevelInfo, "Dir scan: generated %d HTTP assets from target", len(allAssets))
 			}
 		}
+		allAssets = w.filterAssetsInScope(task.TaskId, "Dir Scan", scope, allAssets)
 
 		// 仍然没有资产时跳过
 		if len(allAssets) == 0 {
@@ -1375,7 +1646,7 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 			w.updateTaskProgressWithPhase(ctx, task.TaskId, 70, "目录扫描中", "目录扫描")
 
 			// 执行目录扫描
-			dirScanAssets := w.executeDirScan(ctx, task, allAssets, config.DirScan, orgId)
+			dirScanAssets := w.executeDirScan(ctx, task, allAssets, config.DirScan, orgId, limiter)
 			if len(dirScanAssets) > 0 {
 				// 将目录扫描发现的资产添加到总资产列表
 				allAssets = append(allAssets, dirScanAssets...)
@@ -1388,6 +1659,40 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		}
 	}
 
+	if w.handoffPhases(ctx, task, config, "brute", completedPhases, allAssets) {
+		return
+	}
+
+	// 执行弱口令爆破（在目录扫描之后、POC扫描之前）
+	if config.Brute != nil && config.Brute.Enable && !completedPhases["brute"] {
+		allAssets = w.filterAssetsInScope(task.TaskId, "Brute", scope, allAssets)
+		if len(allAssets) == 0 {
+			w.taskLog(task.TaskId, LevelInfo, "Brute: skipped (no assets)")
+		} else {
+			if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
+				w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+				return
+			} else if ctrl == "PAUSE" {
+				w.taskLog(task.TaskId, LevelInfo, "Task paused, saving progress...")
+				w.saveTaskProgress(ctx, task, completedPhases, allAssets)
+				return
+			}
+
+			w.updateTaskProgressWithPhase(ctx, task.TaskId, 75, "弱口令爆破中", "弱口令爆破")
+			bruteVuls := w.executeBrute(ctx, task, allAssets, config.Brute)
+			if ctx.Err() != nil {
+				w.taskLog(task.TaskId, LevelInfo, "Task stopped")
+				return
+			}
+			if len(bruteVuls) > 0 {
+				w.taskLog(task.TaskId, LevelInfo, "Brute completed: found %d weak credentials", len(bruteVuls))
+				w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, bruteVuls)
+			}
+		}
+		completedPhases["brute"] = true
+		w.incrSubTaskDone(ctx, task, "弱口令爆破")
+	}
+
 	// 检查控制信号
 	if ctrl := w.checkTaskControl(ctx, task.TaskId); ctrl == "STOP" {
 		w.taskLog(task.TaskId, LevelInfo, "Task stopped")
@@ -1398,8 +1703,13 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 		return
 	}
 
+	if w.handoffPhases(ctx, task, config, "pocscan", completedPhases, allAssets) {
+		return
+	}
+
 	// 执行POC扫描 (使用Nuclei引擎)
 	if config.PocScan != nil && config.PocScan.Enable && !completedPhases["pocscan"] {
+		allAssets = w.filterAssetsInScope(task.TaskId, "POC Scan", scope, allAssets)
 		// 没有资产时跳过实际扫描，但仍需递增进度
 		if len(allAssets) == 0 {
 			w.taskLog(task.TaskId, LevelInfo, "POC scan: skipped (no assets)")
@@ -1517,6 +1827,7 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 					CustomPocOnly:   config.PocScan.CustomPocOnly,
 					CustomTemplates: templates,
 					TagMappings:     config.PocScan.TagMappings,
+					Interactsh:      w.loadInteractsh(ctx, task.TaskId),
 					// 设置回调函数，发现漏洞时添加到缓冲区
 					OnVulnerabilityFound: func(vul *scanner.Vulnerability) {
 						vulCount++
@@ -1538,9 +1849,10 @@ func (w *Worker) executeTask(task *scheduler.TaskInfo) {
 				}
 
 				result, err := s.Scan(pocCtx, &scanner.ScanConfig{
-					Assets:     allAssets,
-					Options:    nucleiOpts,
-					TaskLogger: pocTaskLogger,
+					Assets:      allAssets,
+					Options:     nucleiOpts,
+					TaskLogger:  pocTaskLogger,
+					RateLimiter: limiter,
 				})
 				pocCancel()
 
@@ -1703,7 +2015,12 @@ func (w *Worker) saveAssetResult(ctx context.Context, workspaceId, mainTaskId, o
 				Cname:      asset.CName,
 				IsCdn:      asset.IsCDN,
 				IsCloud:    asset.IsCloud,
+				CdnName:    asset.CDNName,
+				Waf:        asset.WAF,
+				CloudName:  asset.CloudName,
 				Source:     asset.Source,
+				Transport:  asset.Transport,
+				TLS:        asset.TLS,
 			}
 
 			// 添加IPv4信息
@@ -1812,6 +2129,7 @@ func (w *Worker) saveVulResult(ctx context.Context, workspaceId, mainTaskId stri
 			responseTruncated := vul.ResponseTruncated
 			httpVul.ResponseTruncated = &responseTruncated
 		}
+		httpVul.Interaction = vul.Interaction
 
 		// 输出httpVul中的证据字段
 		w.taskLog(mainTaskId, LevelDebug, "[SaveVul] httpVul.CurlCommand=%v, httpVul.Request=%v, httpVul.Response=%v",
@@ -1948,6 +2266,8 @@ func (w *Worker) doSendHeartbeat(ctx context.Context) error {
 		TaskExecutedNumber: int32(w.taskExecuted),
 		IsDaemon:           false,
 		Concurrency:        w.config.Concurrency,
+		TaskIds:            w.getRunningTaskIds(),
+		Labels:             w.labels,
 	})
 
 	if err != nil {
@@ -2155,6 +2475,28 @@ func GetSystemInfo() map[string]interface{} {
 	}
 }
 
+// loadSubfinderProviders 通过 HTTP 接口获取 Subfinder 数据源密钥
+func (w *Worker) loadSubfinderProviders(ctx context.Context, task *scheduler.TaskInfo) map[string][]string {
+	providerResp, err := w.httpClient.GetSubfinderProviders(ctx, task.WorkspaceId)
+	if err != nil {
+		w.taskLog(task.TaskId, LevelWarn, "Failed to get subfinder providers: %v", err)
+		return nil
+	}
+	if providerResp == nil || len(providerResp.Providers) == 0 {
+		w.taskLog(task.TaskId, LevelInfo, "No subfinder providers configured in database")
+		return nil
+	}
+	providerConfig := make(map[string][]string)
+	for _, p := range providerResp.Providers {
+		if len(p.Keys) > 0 {
+			providerConfig[p.Provider] = p.Keys
+			w.taskLog(task.TaskId, LevelDebug, "Subfinder provider: %s, keys: %d", p.Provider, len(p.Keys))
+		}
+	}
+	w.taskLog(task.TaskId, LevelInfo, "Loaded %d subfinder providers with keys", len(providerConfig))
+	return providerConfig
+}
+
 // generateAutoTags 根据资产的应用信息生成Nuclei标签
 func (w *Worker) generateAutoTags(assets []*scanner.Asset, pocConfig *scheduler.PocScanConfig) []string {
 	tagSet := make(map[string]bool)
@@ -2535,6 +2877,7 @@ func (w *Worker) executePocValidateTask(ctx context.Context, task *scheduler.Tas
 		Concurrency:     10,
 		CustomTemplates: templates,
 		CustomPocOnly:   true, // 只使用自定义POC
+		Interactsh:      w.loadInteractsh(ctx, task.TaskId),
 	}
 
 	w.taskLog(task.TaskId, LevelInfo, "[%s] Scanning target: %s", task.TaskId, url)
@@ -2703,6 +3046,7 @@ func (w *Worker) executePocBatchValidateTask(ctx context.Context, task *schedule
 		Timeout:         int(timeout),
 		CustomTemplates: templates,
 		CustomPocOnly:   true,
+		Interactsh:      w.loadInteractsh(ctx, task.TaskId),
 		// 设置回调函数，发现漏洞时立即保存到数据库
 		OnVulnerabilityFound: func(vul *scanner.Vulnerability) {
 			w.taskLog(task.TaskId, LevelInfo, "[%s] Vulnerability found! %s → %s", task.TaskId, vul.PocFile, vul.Url)
@@ -2839,9 +3183,18 @@ func (c *WorkerHttpServiceChecker) SetMapping(serviceName string, isHttp bool) {
 	c.cache[serviceName] = isHttp
 }
 
-// executePortIdentify 执行端口识别阶段（Nmap服务识别）
+// executePortIdentify 执行端口识别阶段（Nmap或内置探测库服务识别）
 func (w *Worker) executePortIdentify(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.PortIdentifyConfig) []*scanner.Asset {
-	w.taskLog(task.TaskId, LevelInfo, "Port identify: Nmap (%d assets)", len(assets))
+	useNative := config.Tool == "native"
+	if !useNative && !scanner.CheckNmapInstalled() {
+		w.taskLog(task.TaskId, LevelWarn, "Nmap not installed, using native service probes")
+		useNative = true
+	}
+	if useNative {
+		w.taskLog(task.TaskId, LevelInfo, "Port identify: Native (%d assets)", len(assets))
+	} else {
+		w.taskLog(task.TaskId, LevelInfo, "Port identify: Nmap (%d assets)", len(assets))
+	}
 
 	// 获取超时配置
 	timeout := config.Timeout
@@ -2852,7 +3205,13 @@ func (w *Worker) executePortIdentify(ctx context.Context, task *scheduler.TaskIn
 	// 按主机分组
 	hostPorts := make(map[string][]int)
 	hostAssets := make(map[string][]*scanner.Asset)
+	var udpAssets []*scanner.Asset
 	for _, asset := range assets {
+		// UDP 资产由协议探测识别，不交给 Nmap
+		if asset.Transport == scanner.TransportUDP {
+			udpAssets = append(udpAssets, asset)
+			continue
+		}
 		hostPorts[asset.Host] = append(hostPorts[asset.Host], asset.Port)
 		hostAssets[asset.Host] = append(hostAssets[asset.Host], asset)
 	}
@@ -2866,7 +3225,10 @@ func (w *Worker) executePortIdentify(ctx context.Context, task *scheduler.TaskIn
 	defer identifyCancel()
 
 	var identifiedAssets []*scanner.Asset
-	nmapScanner := w.scanners["nmap"]
+	identifyScanner := w.scanners["nmap"]
+	if useNative {
+		identifyScanner = w.scanners["serviceprobe"]
+	}
 
 	for host, ports := range hostPorts {
 		// 检查是否被停止或超时
@@ -2891,18 +3253,24 @@ func (w *Worker) executePortIdentify(ctx context.Context, task *scheduler.TaskIn
 		}
 		portsStr := strings.Join(portStrs, ",")
 
-		// 构建 Nmap 选项
-		nmapOpts := &scanner.NmapOptions{
-			Ports:   portsStr,
-			Timeout: timeout,
-		}
-		if config.Args != "" {
-			nmapOpts.Args = config.Args
+		// 构建识别选项
+		var identifyOpts interface{}
+		if useNative {
+			identifyOpts = &scanner.ServiceProbeOptions{Ports: portsStr}
+		} else {
+			nmapOpts := &scanner.NmapOptions{
+				Ports:   portsStr,
+				Timeout: timeout,
+			}
+			if config.Args != "" {
+				nmapOpts.Args = config.Args
+			}
+			identifyOpts = nmapOpts
 		}
 
-		nmapResult, err := nmapScanner.Scan(identifyCtx, &scanner.ScanConfig{
+		identifyResult, err := identifyScanner.Scan(identifyCtx, &scanner.ScanConfig{
 			Target:  host,
-			Options: nmapOpts,
+			Options: identifyOpts,
 		})
 
 		// 检查是否被停止
@@ -2912,8 +3280,8 @@ func (w *Worker) executePortIdentify(ctx context.Context, task *scheduler.TaskIn
 		}
 
 		if err != nil {
-			w.taskLog(task.TaskId, LevelError, "Nmap error %s: %v", host, err)
-			// Nmap失败时，使用原始资产
+			w.taskLog(task.TaskId, LevelError, "Port identify error %s: %v", host, err)
+			// 识别失败时，使用原始资产
 			for _, asset := range hostAssets[host] {
 				asset.IsHTTP = scanner.IsHTTPService(asset.Service, asset.Port)
 				identifiedAssets = append(identifiedAssets, asset)
@@ -2921,14 +3289,14 @@ func (w *Worker) executePortIdentify(ctx context.Context, task *scheduler.TaskIn
 			continue
 		}
 
-		if nmapResult != nil && len(nmapResult.Assets) > 0 {
+		if identifyResult != nil && len(identifyResult.Assets) > 0 {
 			// 设置 IsHTTP 字段
-			for _, asset := range nmapResult.Assets {
+			for _, asset := range identifyResult.Assets {
 				asset.IsHTTP = scanner.IsHTTPService(asset.Service, asset.Port)
 			}
-			identifiedAssets = append(identifiedAssets, nmapResult.Assets...)
+			identifiedAssets = append(identifiedAssets, identifyResult.Assets...)
 		} else {
-			// Nmap没有结果时，使用原始资产
+			// 没有识别结果时，使用原始资产
 			for _, asset := range hostAssets[host] {
 				asset.IsHTTP = scanner.IsHTTPService(asset.Service, asset.Port)
 				identifiedAssets = append(identifiedAssets, asset)
@@ -2936,12 +3304,395 @@ func (w *Worker) executePortIdentify(ctx context.Context, task *scheduler.TaskIn
 		}
 	}
 
+	// UDP 服务识别
+	if len(udpAssets) > 0 {
+		identified := 0
+		for _, asset := range udpAssets {
+			if identifyCtx.Err() == nil && scanner.IdentifyUDP(identifyCtx, asset, 0) {
+				identified++
+			}
+			asset.IsHTTP = false
+			identifiedAssets = append(identifiedAssets, asset)
+		}
+		w.taskLog(task.TaskId, LevelInfo, "UDP identify: %d/%d services identified", identified, len(udpAssets))
+	}
+
 	w.taskLog(task.TaskId, LevelInfo, "Port identify completed: %d assets", len(identifiedAssets))
 	return identifiedAssets
 }
 
+// executeTLSScan 对TCP资产采集TLS证书和握手信息，返回证书SAN中新发现的域名资产
+func (w *Worker) executeTLSScan(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.TLSScanConfig) []*scanner.Asset {
+	var tcpAssets []*scanner.Asset
+	knownHosts := make(map[string]bool)
+	for _, asset := range assets {
+		knownHosts[strings.ToLower(asset.Host)] = true
+		if asset.Port > 0 && asset.Transport != scanner.TransportUDP {
+			tcpAssets = append(tcpAssets, asset)
+		}
+	}
+	w.taskLog(task.TaskId, LevelInfo, "TLS scan: %d ports", len(tcpAssets))
+
+	opts := &scanner.TLSCollectOptions{Timeout: config.Timeout, Jarm: config.Jarm}
+	concurrency := w.config.Concurrency
+	if concurrency <= 0 {
+		concurrency = 10
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for _, asset := range tcpAssets {
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(asset *scanner.Asset) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			info := scanner.CollectTLS(ctx, asset.Host, asset.Port, opts)
+			if info == nil {
+				return
+			}
+			asset.TLS = info
+			asset.Cert = info.CertSummary()
+		}(asset)
+	}
+	wg.Wait()
+
+	var sanAssets []*scanner.Asset
+	tlsCount := 0
+	for _, asset := range tcpAssets {
+		if asset.TLS == nil {
+			continue
+		}
+		tlsCount++
+		if len(asset.TLS.Findings) > 0 {
+			w.taskLog(task.TaskId, LevelWarn, "TLS %s: %s", asset.Authority, strings.Join(asset.TLS.Findings, ", "))
+		}
+		if !config.SanDomains {
+			continue
+		}
+		for _, domain := range asset.TLS.SANDomains() {
+			if knownHosts[domain] {
+				continue
+			}
+			knownHosts[domain] = true
+			sanAssets = append(sanAssets, &scanner.Asset{
+				Authority: domain,
+				Host:      domain,
+				Category:  "domain",
+				Source:    "tls-san",
+			})
+		}
+	}
+	w.taskLog(task.TaskId, LevelInfo, "TLS scan completed: %d/%d ports speak TLS", tlsCount, len(tcpAssets))
+	return sanAssets
+}
+
+// executeBrute 执行弱口令爆破阶段，返回发现的弱口令漏洞（密码已脱敏）
+func (w *Worker) executeBrute(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.BruteConfig) []*scanner.Vulnerability {
+	targets := bruteTargets(assets)
+	if len(targets) == 0 {
+		w.taskLog(task.TaskId, LevelInfo, "Brute: skipped (no ports)")
+		return nil
+	}
+
+	opts := &scanner.BruteOptions{
+		Services:         config.Services,
+		Threads:          config.Threads,
+		Timeout:          config.Timeout,
+		Interval:         config.Interval,
+		MaxAttempts:      config.MaxAttempts,
+		LockoutThreshold: config.LockoutThreshold,
+		StopOnSuccess:    config.StopOnSuccess,
+		UsernameDictIds:  config.UsernameDictIds,
+		PasswordDictIds:  config.PasswordDictIds,
+	}
+	if err := w.loadBruteDicts(ctx, task, opts); err != nil {
+		w.taskLog(task.TaskId, LevelError, "Brute: get dicts failed: %v", err)
+		return nil
+	}
+
+	bruteScanner, ok := w.scanners["brute"]
+	if !ok {
+		w.taskLog(task.TaskId, LevelError, "Brute: scanner not found")
+		return nil
+	}
+
+	taskLogger := func(level, format string, args ...interface{}) {
+		w.taskLog(task.TaskId, level, format, args...)
+	}
+	onProgress := func(progress int, message string) {
+		w.updateTaskProgress(ctx, task.TaskId, 75+progress/10, message) // 75-85%
+	}
+
+	result, err := bruteScanner.Scan(ctx, &scanner.ScanConfig{
+		Assets:      targets,
+		Options:     opts,
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger:  taskLogger,
+		OnProgress:  onProgress,
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "Brute error: %v", err)
+		return nil
+	}
+	if result == nil {
+		return nil
+	}
+	return result.Vulnerabilities
+}
+
+// bruteTargets 过滤出可爆破的 TCP 端口资产。目录扫描发现的路径资产与端口重复，每个端口只爆破一次
+func bruteTargets(assets []*scanner.Asset) []*scanner.Asset {
+	var targets []*scanner.Asset
+	seen := make(map[string]bool)
+	for _, asset := range assets {
+		if asset.Port <= 0 || asset.Path != "" || asset.Transport == scanner.TransportUDP {
+			continue
+		}
+		key := fmt.Sprintf("%s:%d", asset.Host, asset.Port)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		targets = append(targets, asset)
+	}
+	return targets
+}
+
+// loadBruteDicts 加载选项中引用的工作空间字典，未配置字典时使用内置字典
+func (w *Worker) loadBruteDicts(ctx context.Context, task *scheduler.TaskInfo, opts *scanner.BruteOptions) error {
+	if opts.Usernames == nil {
+		opts.Usernames = make(map[string][]string)
+	}
+	if opts.Passwords == nil {
+		opts.Passwords = make(map[string][]string)
+	}
+	dictIds := append(append([]string{}, opts.UsernameDictIds...), opts.PasswordDictIds...)
+	if len(dictIds) == 0 {
+		return nil
+	}
+	dictResp, err := w.httpClient.GetBruteDicts(ctx, task.WorkspaceId, dictIds)
+	if err != nil {
+		return err
+	}
+	if dictResp.Code != 0 {
+		return fmt.Errorf("%s", dictResp.Msg)
+	}
+	for _, dict := range dictResp.Dicts {
+		dest := opts.Passwords
+		if dict.Type == model.BruteDictUsername {
+			dest = opts.Usernames
+		}
+		services := dict.Services
+		if len(services) == 0 {
+			services = []string{scanner.BruteAny}
+		}
+		for _, svc := range services {
+			dest[svc] = append(dest[svc], dict.Words...)
+		}
+		w.taskLog(task.TaskId, LevelInfo, "Brute: loaded %s dict '%s' with %d words", dict.Type, dict.Name, len(dict.Words))
+	}
+	return nil
+}
+
+// executeCDNDetect 执行CDN/WAF/云厂商识别，开启源站发现时从服务端获取历史解析和证书关联IP
+func (w *Worker) executeCDNDetect(ctx context.Context, task *scheduler.TaskInfo, target string, config *scheduler.CDNConfig, scope *scheduler.Scope) *scanner.ScanResult {
+	cdnScanner, ok := w.scanners["cdndetect"]
+	if !ok {
+		w.taskLog(task.TaskId, LevelError, "CDN detect: scanner not found")
+		return nil
+	}
+
+	if config.UpdateRanges {
+		if err := scanner.UpdateCDNRanges(ctx, 24*time.Hour); err != nil {
+			w.taskLog(task.TaskId, LevelWarn, "CDN detect: update ranges failed: %v", err)
+		}
+	}
+
+	opts := &scanner.CDNDetectOptions{
+		ASN:             config.ASN,
+		Headers:         config.Headers,
+		OriginDiscovery: config.OriginDiscovery,
+		Threads:         w.config.Concurrency * 5,
+	}
+	if config.OriginDiscovery {
+		opts.OriginCandidates = w.originCandidates(task)
+		if scope != nil {
+			opts.HostAllowed = w.scopeHostAllowed(task.TaskId, "CDN origin", scope)
+		}
+	}
+
+	taskLogger := func(level, format string, args ...interface{}) {
+		w.taskLog(task.TaskId, level, format, args...)
+	}
+
+	cdnCtx, cdnCancel := context.WithTimeout(ctx, 20*time.Minute)
+	defer cdnCancel()
+
+	result, err := cdnScanner.Scan(cdnCtx, &scanner.ScanConfig{
+		Target:      target,
+		Options:     opts,
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger:  taskLogger,
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "CDN detect error: %v", err)
+		return nil
+	}
+	if cdnCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		w.taskLog(task.TaskId, LevelWarn, "CDN detect timeout, using partial results")
+	}
+	return result
+}
+
+// originCandidates 从服务端查询历史解析和证书关联的候选源站IP
+func (w *Worker) originCandidates(task *scheduler.TaskInfo) func(ctx context.Context, domains []string) map[string][]scanner.OriginCandidate {
+	return func(ctx context.Context, domains []string) map[string][]scanner.OriginCandidate {
+		resp, err := w.httpClient.GetOriginCandidates(ctx, &OriginReq{WorkspaceId: task.WorkspaceId, Domains: domains})
+		if err != nil {
+			w.taskLog(task.TaskId, LevelWarn, "CDN origin: query candidates failed: %v", err)
+			return nil
+		}
+		if resp.Code != 0 {
+			w.taskLog(task.TaskId, LevelWarn, "CDN origin: query candidates failed: %s", resp.Msg)
+			return nil
+		}
+		return resp.Candidates
+	}
+}
+
+// executeDNSRecon 执行主动DNS侦察，保存DNS记录和发现的问题，返回新发现的子域名
+func (w *Worker) executeDNSRecon(ctx context.Context, task *scheduler.TaskInfo, target string, known []*scanner.Asset, config *scheduler.DomainScanConfig) []*scanner.Asset {
+	reconScanner, ok := w.scanners["dnsrecon"]
+	if !ok {
+		w.taskLog(task.TaskId, LevelError, "DNS recon: scanner not found")
+		return nil
+	}
+
+	opts := &scanner.DNSReconOptions{
+		Brute:       config.Brute,
+		Permutation: config.Permutation,
+		AXFR:        config.AXFR,
+		Records:     config.DNSRecords,
+		Takeover:    config.Takeover,
+		Resolvers:   config.Resolvers,
+		Threads:     w.config.Concurrency * 10,
+	}
+	if config.BruteWordlist != "" {
+		opts.Wordlist = strings.Split(config.BruteWordlist, "\n")
+	}
+
+	taskLogger := func(level, format string, args ...interface{}) {
+		w.taskLog(task.TaskId, level, format, args...)
+	}
+	onProgress := func(progress int, message string) {
+		w.updateTaskProgress(ctx, task.TaskId, 10+progress/10, message) // 10-20%
+	}
+
+	// 整个侦察最长30分钟，超时后使用已有结果
+	reconCtx, reconCancel := context.WithTimeout(ctx, 30*time.Minute)
+	defer reconCancel()
+
+	result, err := reconScanner.Scan(reconCtx, &scanner.ScanConfig{
+		Target:      target,
+		Assets:      known,
+		Options:     opts,
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger:  taskLogger,
+		OnProgress:  onProgress,
+	})
+	if err != nil && result == nil {
+		w.taskLog(task.TaskId, LevelError, "DNS recon error: %v", err)
+		return nil
+	}
+	if reconCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		w.taskLog(task.TaskId, LevelWarn, "DNS recon timeout, using partial results")
+	}
+
+	if len(result.DNSRecords) > 0 {
+		w.saveDNSRecords(ctx, task, result.DNSRecords)
+	}
+	if len(result.Vulnerabilities) > 0 {
+		w.saveVulResult(ctx, task.WorkspaceId, task.MainTaskId, result.Vulnerabilities)
+	}
+	return result.Assets
+}
+
+// saveDNSRecords 保存DNS侦察采集的记录
+func (w *Worker) saveDNSRecords(ctx context.Context, task *scheduler.TaskInfo, records []*scanner.DNSRecord) {
+	resp, err := w.httpClient.SaveDNSRecords(ctx, &DNSRecordReq{
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		Records:     records,
+	})
+	if err != nil {
+		w.taskLog(task.TaskId, LevelError, "DNS recon: save records failed: %v", err)
+	} else if resp.Code != 0 {
+		w.taskLog(task.TaskId, LevelError, "DNS recon: save records failed: %s", resp.Msg)
+	} else {
+		w.taskLog(task.TaskId, LevelInfo, "DNS recon: saved %d records", len(records))
+	}
+}
+
+// executeCrawler 执行网页爬虫阶段
+func (w *Worker) executeCrawler(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.CrawlerConfig, scope *scheduler.Scope) *scanner.ScanResult {
+	crawlerScanner, ok := w.scanners["crawler"]
+	if !ok {
+		w.taskLog(task.TaskId, LevelError, "Crawler: scanner not found")
+		return nil
+	}
+
+	opts := &scanner.CrawlerOptions{
+		MaxDepth: config.MaxDepth,
+		MaxPages: config.MaxPages,
+		Scope:    config.Scope,
+		Threads:  config.Threads,
+		Timeout:  config.Timeout,
+		Forms:    config.Forms,
+		ParseJS:  config.ParseJS,
+		Headless: config.Headless,
+	}
+	if scope != nil {
+		opts.AssetAllowed = w.scopeAssetAllowed(task.TaskId, "Crawler", scope)
+	}
+
+	// 整个阶段最长1小时，超时后使用已爬取的结果
+	crawlCtx, crawlCancel := context.WithTimeout(ctx, time.Hour)
+	defer crawlCancel()
+
+	taskLogger := func(level, format string, args ...interface{}) {
+		w.taskLog(task.TaskId, level, format, args...)
+	}
+	onProgress := func(progress int, message string) {
+		w.updateTaskProgress(ctx, task.TaskId, 65+progress/20, message) // 65-70%
+	}
+
+	result, err := crawlerScanner.Scan(crawlCtx, &scanner.ScanConfig{
+		Assets:      assets,
+		Options:     opts,
+		WorkspaceId: task.WorkspaceId,
+		MainTaskId:  task.MainTaskId,
+		TaskLogger:  taskLogger,
+		OnProgress:  onProgress,
+	})
+	if crawlCtx.Err() == context.DeadlineExceeded && ctx.Err() == nil {
+		w.taskLog(task.TaskId, LevelWarn, "Crawler timeout, using partial results")
+		return result
+	}
+	if err != nil && result == nil {
+		w.taskLog(task.TaskId, LevelError, "Crawler error: %v", err)
+		return nil
+	}
+	return result
+}
+
 // executeDirScan 执行目录扫描阶段
-func (w *Worker) executeDirScan(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.DirScanConfig, orgId string) []*scanner.Asset {
+func (w *Worker) executeDirScan(ctx context.Context, task *scheduler.TaskInfo, assets []*scanner.Asset, config *scheduler.DirScanConfig, orgId string, limiter scanner.RateLimiter) []*scanner.Asset {
 	// 过滤出HTTP资产
 	var httpAssets []*scanner.Asset
 	for _, asset := range assets {
@@ -3053,6 +3804,7 @@ func (w *Worker) executeDirScan(ctx context.Context, task *scheduler.TaskInfo, a
 		MainTaskId:  task.MainTaskId,
 		TaskLogger:  taskLogger,
 		OnProgress:  onProgress,
+		RateLimiter: limiter,
 	})
 
 	// 检查是否超时
@@ -3117,6 +3869,7 @@ func (w *Worker) saveDirScanResults(ctx context.Context, task *scheduler.TaskInf
 			ContentLength: asset.ContentLength,
 			ContentType:   asset.ContentType,
 			Title:         asset.Title,
+			Source:        asset.Source,
 		})
 	}
 